@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestActivityFeed(t *testing.T) {
+	now := time.Now().UTC()
+	stamp := func(ago time.Duration) string { return now.Add(-ago).Truncate(time.Minute).Format(time.RFC3339) }
+	pages := []map[string]interface{}{
+		{"object": "page", "id": "p1", "last_edited_time": stamp(time.Hour)},
+		{"object": "page", "id": "p2", "last_edited_time": stamp(30 * time.Hour)},
+	}
+	var sorts []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sorts = append(sorts, body["sort"])
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "results": pages, "has_more": false})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	run := func() []activityItem {
+		t.Helper()
+		var err error
+		out := captureStdout(t, func() {
+			_, _, err = executeCommand("activity", "--format", "json")
+		})
+		outputFormat = ""
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Items []activityItem `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("output %q: %v", out, err)
+		}
+		return got.Items
+	}
+
+	// First run: the last 24 hours.
+	if items := run(); len(items) != 1 || items[0].ID != "p1" {
+		t.Fatalf("first run = %+v, want p1", items)
+	}
+	if sort, _ := sorts[0].(map[string]interface{}); sort["timestamp"] != "last_edited_time" || sort["direction"] != "descending" {
+		t.Errorf("search sort = %v", sorts[0])
+	}
+
+	// Nothing new, then p2 edited just now and a new page p3 in the same minute.
+	if items := run(); len(items) != 0 {
+		t.Fatalf("second run = %+v, want nothing", items)
+	}
+	pages = []map[string]interface{}{
+		{"object": "page", "id": "p2", "last_edited_time": stamp(0)},
+		{"object": "page", "id": "p3", "last_edited_time": stamp(0)},
+		{"object": "page", "id": "p1", "last_edited_time": stamp(time.Hour)},
+	}
+	if items := run(); len(items) != 2 || items[0].ID != "p2" || items[1].ID != "p3" {
+		t.Fatalf("third run = %+v, want p2, p3", items)
+	}
+	if items := run(); len(items) != 0 {
+		t.Fatalf("fourth run = %+v, want nothing (same-minute edits already seen)", items)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2h": now.Add(-2 * time.Hour),
+		"7d": now.AddDate(0, 0, -7),
+		"2w": now.AddDate(0, 0, -14),
+		"1y": now.AddDate(-1, 0, 0),
+	}
+	for in, want := range tests {
+		if got, err := parseSince(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if got, err := parseSince("2026-03-01T09:00:00Z", now); err != nil || got.Hour() != 9 {
+		t.Errorf("RFC 3339: %v, %v", got, err)
+	}
+	if _, err := parseSince("soon", now); err == nil {
+		t.Error("parseSince(soon) should fail")
+	}
+}
@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var agentToolsCmd = &cobra.Command{
+	Use:   "agent-tools",
+	Short: "Tool definitions for LLM agents",
+	Long: `Print tool (function calling) definitions for the commands an agent
+needs most — searching, reading and writing pages, querying and adding
+database rows — and run the tool calls the model makes.
+
+Parameter schemas are strict: every parameter is typed and described, and
+unknown ones are rejected. Descriptions and types come from the commands'
+own flags, so the tools stay in step with the CLI.
+
+Examples:
+  notion agent-tools                           # Anthropic tool definitions
+  notion agent-tools --provider openai         # OpenAI function tools
+  notion agent-tools call notion_search '{"query":"roadmap"}'
+  echo '{"page":"abc123"}' | notion agent-tools call notion_page_read`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, _ := cmd.Flags().GetString("provider")
+		var tools []map[string]interface{}
+		for _, t := range agentTools {
+			def, err := t.definition(provider)
+			if err != nil {
+				return err
+			}
+			tools = append(tools, def)
+		}
+		return render.JSON(tools)
+	},
+}
+
+var agentToolsCallCmd = &cobra.Command{
+	Use:   "call <tool> [input-json]",
+	Short: "Run a tool call made by a model",
+	Long: `Run the command behind a tool with the model's input (a JSON object,
+from the argument or stdin) and print its output, as JSON or, for
+notion_page_read, as Markdown.
+
+Global flags apply to the call, so 'notion agent-tools call --read-only'
+only ever reads.
+
+Examples:
+  notion agent-tools call notion_db_query '{"database":"tasks","filter":["Status=Open"]}'
+  notion agent-tools call notion_page_set --read-only '{"page":"abc","properties":["Status=Done"]}'`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tool := findAgentTool(args[0])
+		if tool == nil {
+			return usageError(fmt.Errorf("unknown tool %q (see 'notion agent-tools')", args[0]))
+		}
+		var data []byte
+		if len(args) == 2 {
+			data = []byte(args[1])
+		} else {
+			var err error
+			if data, err = io.ReadAll(os.Stdin); err != nil {
+				return fmt.Errorf("read tool input: %w", err)
+			}
+		}
+		var input map[string]interface{}
+		if strings.TrimSpace(string(data)) != "" {
+			if err := json.Unmarshal(data, &input); err != nil {
+				return invalidInput(fmt.Errorf("tool input is not a JSON object: %w", err))
+			}
+		}
+		argv, err := tool.argv(input)
+		if err != nil {
+			return invalidInput(err)
+		}
+		rootCmd.SetArgs(argv)
+		defer rootCmd.SetArgs(nil)
+		return rootCmd.Execute()
+	},
+}
+
+func init() {
+	agentToolsCmd.Flags().String("provider", "anthropic", "Definition format: anthropic or openai")
+	agentToolsCmd.AddCommand(agentToolsCallCmd)
+}
+
+// agentTool is a command offered to a model as a tool.
+type agentTool struct {
+	Name        string
+	Command     string // command path, e.g. "page set"
+	Description string
+	// Fixed are arguments always passed, such as the output format.
+	Fixed  []string
+	Params []agentParam
+}
+
+// agentParam is one tool parameter: the command's Arg-th argument, or
+// its Flag.
+type agentParam struct {
+	Name        string
+	Arg         int
+	Flag        string
+	Required    bool
+	Description string // overrides the command's own
+	Enum        []string
+}
+
+// agentTools are the tools offered by 'notion agent-tools'.
+var agentTools = []agentTool{
+	{
+		Name:        "notion_search",
+		Command:     "search",
+		Description: "Search the Notion workspace for pages and databases by title. Returns their IDs, titles and URLs.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "query", Arg: 0, Description: "Text to look for in titles; empty lists recently edited pages and databases"},
+			{Name: "type", Flag: "type", Enum: []string{"page", "database"}},
+			{Name: "limit", Flag: "limit"},
+		},
+	},
+	{
+		Name:        "notion_page_read",
+		Command:     "page view",
+		Description: "Read a Notion page: its properties and its content as Markdown.",
+		Fixed:       []string{"--format", "md", "--all"},
+		Params: []agentParam{
+			{Name: "page", Arg: 0, Required: true, Description: "Page ID or URL"},
+		},
+	},
+	{
+		Name:        "notion_page_create",
+		Command:     "page create",
+		Description: "Create a page under a page, or a row in a database. Property values are converted using the database schema.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "parent", Arg: 0, Required: true, Description: "ID or URL of the parent page or database"},
+			{Name: "title", Flag: "title"},
+			{Name: "properties", Arg: 1, Description: "Database properties as Name=value, e.g. Status=Done or Due=2026-03-01"},
+			{Name: "body", Flag: "body"},
+		},
+	},
+	{
+		Name:        "notion_page_set",
+		Command:     "page set",
+		Description: "Change properties of a page or database row. Values are converted using the database schema; an empty value clears the property.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "page", Arg: 0, Required: true, Description: "Page ID or URL"},
+			{Name: "properties", Arg: 1, Required: true, Description: "Properties as Name=value, e.g. Status=Done"},
+		},
+	},
+	{
+		Name:        "notion_page_append",
+		Command:     "block append",
+		Description: "Append a block of text to the end of a page.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "page", Arg: 0, Required: true, Description: "Page ID or URL"},
+			{Name: "text", Arg: 1, Required: true, Description: "Text of the block"},
+			{Name: "type", Flag: "type", Enum: []string{"paragraph", "h1", "h2", "h3", "todo", "bullet", "numbered", "quote", "code", "callout"}},
+		},
+	},
+	{
+		Name:        "notion_db_schema",
+		Command:     "db view",
+		Description: "Show a database's title and properties with their types and options. Read it before querying or adding rows.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "database", Arg: 0, Required: true, Description: "Database ID or URL"},
+		},
+	},
+	{
+		Name:        "notion_db_query",
+		Command:     "db query",
+		Description: "List the rows of a database, optionally filtered and sorted.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "database", Arg: 0, Required: true, Description: "Database ID or URL"},
+			{Name: "filter", Flag: "filter", Description: "Filters, all of which must match, e.g. Status=Done, Priority!=Low, Due<2026-03-01, Name~=launch"},
+			{Name: "sort", Flag: "sort", Description: "Sort keys, e.g. Due:asc or Created:desc"},
+			{Name: "limit", Flag: "limit", Description: "Maximum rows to return"},
+		},
+	},
+	{
+		Name:        "notion_db_add",
+		Command:     "db add",
+		Description: "Add a row to a database. Values are converted using the database schema.",
+		Fixed:       []string{"--format", "json"},
+		Params: []agentParam{
+			{Name: "database", Arg: 0, Required: true, Description: "Database ID or URL"},
+			{Name: "properties", Arg: 1, Required: true, Description: "Properties as Name=value, e.g. Name=Write report or Status=Todo"},
+		},
+	},
+}
+
+func findAgentTool(name string) *agentTool {
+	for i := range agentTools {
+		if agentTools[i].Name == name {
+			return &agentTools[i]
+		}
+	}
+	return nil
+}
+
+// command returns the manifest entry of the command behind t.
+func (t agentTool) command() (manifestCommand, error) {
+	c, _, err := rootCmd.Find(strings.Fields(t.Command))
+	if err != nil || c == rootCmd {
+		return manifestCommand{}, fmt.Errorf("tool %s: no command %q", t.Name, t.Command)
+	}
+	return describeCommand(c), nil
+}
+
+// paramSchema is the JSON Schema of p, taken from the command's argument
+// or flag.
+func (t agentTool) paramSchema(mc manifestCommand, p agentParam) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	description := p.Description
+	if p.Flag != "" {
+		for _, f := range mc.Flags {
+			if f.Name == p.Flag {
+				schema = copySchema(f.Schema)
+				if description == "" {
+					description = f.Description
+				}
+			}
+		}
+	} else if p.Arg < len(mc.Args) {
+		schema = copySchema(mc.Args[p.Arg].Schema)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("tool %s: %q has no parameter %q", t.Name, t.Command, p.Name)
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		delete(items, "description")
+	}
+	delete(schema, "description")
+	if description != "" {
+		schema["description"] = description
+	}
+	if p.Enum != nil {
+		schema["enum"] = p.Enum
+	}
+	return schema, nil
+}
+
+func copySchema(s map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{}
+	for k, v := range s {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = copySchema(m)
+		}
+		c[k] = v
+	}
+	return c
+}
+
+// definition is t as a tool definition for provider: "anthropic"
+// (input_schema) or "openai" (a strict function, where optional
+// parameters are nullable rather than left out).
+func (t agentTool) definition(provider string) (map[string]interface{}, error) {
+	if provider != "anthropic" && provider != "openai" {
+		return nil, usageError(fmt.Errorf("--provider: expected anthropic or openai, got %q", provider))
+	}
+	mc, err := t.command()
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, p := range t.Params {
+		schema, err := t.paramSchema(mc, p)
+		if err != nil {
+			return nil, err
+		}
+		if provider == "openai" {
+			if !p.Required {
+				schema["type"] = []interface{}{schema["type"], "null"}
+				if p.Enum != nil {
+					schema["enum"] = append(append([]interface{}{}, toInterfaces(p.Enum)...), nil)
+				}
+			}
+			required = append(required, p.Name)
+		} else if p.Required {
+			required = append(required, p.Name)
+		}
+		properties[p.Name] = schema
+	}
+	parameters := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if provider == "openai" {
+		return map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        t.Name,
+				"description": t.Description,
+				"strict":      true,
+				"parameters":  parameters,
+			},
+		}, nil
+	}
+	return map[string]interface{}{
+		"name":         t.Name,
+		"description":  t.Description,
+		"input_schema": parameters,
+	}, nil
+}
+
+func toInterfaces(ss []string) []interface{} {
+	out := make([]interface{}, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
+
+// argv turns a tool call's input into the command line that runs it.
+// Arguments come after "--", so values starting with a dash stay values.
+func (t agentTool) argv(input map[string]interface{}) ([]string, error) {
+	known := map[string]bool{}
+	for _, p := range t.Params {
+		known[p.Name] = true
+	}
+	var unknown []string
+	for k := range input {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("tool %s: unknown parameter %s", t.Name, strings.Join(unknown, ", "))
+	}
+
+	argv := append(strings.Fields(t.Command), t.Fixed...)
+	var positional [][]string
+	for _, p := range t.Params {
+		v, ok := input[p.Name]
+		if !ok || v == nil {
+			if p.Required {
+				return nil, fmt.Errorf("tool %s: %s is required", t.Name, p.Name)
+			}
+			continue
+		}
+		if b, ok := v.(bool); ok && p.Flag != "" {
+			if b {
+				argv = append(argv, "--"+p.Flag)
+			}
+			continue
+		}
+		values, err := toolValues(v)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %s: %w", t.Name, p.Name, err)
+		}
+		if p.Flag != "" {
+			for _, s := range values {
+				argv = append(argv, "--"+p.Flag+"="+s)
+			}
+			continue
+		}
+		for len(positional) <= p.Arg {
+			positional = append(positional, nil)
+		}
+		positional[p.Arg] = values
+	}
+	argv = append(argv, "--")
+	for _, values := range positional {
+		argv = append(argv, values...)
+	}
+	return argv, nil
+}
+
+// toolValues is a JSON input value as command-line strings: one for a
+// scalar, one per element for an array.
+func toolValues(v interface{}) ([]string, error) {
+	switch v := v.(type) {
+	case string:
+		return []string{v}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case []interface{}:
+		var out []string
+		for _, e := range v {
+			s, err := toolValues(e)
+			if err != nil || len(s) != 1 {
+				return nil, fmt.Errorf("expected a list of strings")
+			}
+			out = append(out, s...)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unsupported value %v", v)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAgentToolDefinitions(t *testing.T) {
+	for _, provider := range []string{"anthropic", "openai"} {
+		for _, tool := range agentTools {
+			def, err := tool.definition(provider)
+			if err != nil {
+				t.Fatalf("%s %s: %v", provider, tool.Name, err)
+			}
+			data, _ := json.Marshal(def)
+			if !strings.Contains(string(data), `"additionalProperties":false`) {
+				t.Errorf("%s %s is not strict: %s", provider, tool.Name, data)
+			}
+		}
+	}
+	def, _ := findAgentTool("notion_db_query").definition("openai")
+	params := def["function"].(map[string]interface{})["parameters"].(map[string]interface{})
+	if got := params["required"].([]string); len(got) != 4 {
+		t.Errorf("openai required = %v", got)
+	}
+	filter := params["properties"].(map[string]interface{})["filter"].(map[string]interface{})
+	if typ, _ := json.Marshal(filter["type"]); string(typ) != `["array","null"]` {
+		t.Errorf("filter type = %s", typ)
+	}
+}
+
+func TestAgentToolCall(t *testing.T) {
+	tool := findAgentTool("notion_db_query")
+	argv, err := tool.argv(map[string]interface{}{"database": "-odd", "filter": []interface{}{"Status=Done", "Points>3"}, "limit": 5.0, "sort": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(argv, " "); got != "db query --format json --filter=Status=Done --filter=Points>3 --limit=5 -- -odd" {
+		t.Errorf("argv = %q", got)
+	}
+	if _, err := tool.argv(map[string]interface{}{"database": "x", "rm": "-rf"}); err == nil {
+		t.Error("unknown parameter accepted")
+	}
+	if _, err := tool.argv(map[string]interface{}{}); err == nil {
+		t.Error("missing database accepted")
+	}
+
+	var bodies []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+r.URL.Path+" "+string(data))
+		w.Write([]byte(`{"object":"list","results":[],"has_more":false,"properties":{"Status":{"id":"s","type":"select","select":null}}}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("agent-tools", "call", "notion_search", `{"query":"roadmap","type":"page"}`); err != nil {
+			t.Error(err)
+		}
+	})
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"query":"roadmap"`) || !strings.Contains(out, `"results"`) {
+		t.Errorf("requests = %v, output = %s", bodies, out)
+	}
+
+	_, _, err = executeCommand("agent-tools", "call", "--read-only", "notion_page_set", `{"page":"11111111111111111111111111111111","properties":["Status=Done"]}`)
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("read-only call: err = %v", err)
+	}
+}
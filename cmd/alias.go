@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage short names for pages and databases",
+	Long: `Save short names for pages and databases you use often.
+
+Aliases are accepted by 'notion open' and as table names by 'notion sql'.
+
+Examples:
+  notion alias set roadmap https://notion.so/Roadmap-abc123
+  notion alias list
+  notion alias remove roadmap`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <id|url>",
+	Short: "Create or update an alias",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, target := args[0], args[1]
+		if !util.IsID(target) {
+			return fmt.Errorf("%q is not a Notion ID or URL", target)
+		}
+
+		cfg, _ := config.Load()
+		if cfg.Aliases == nil {
+			cfg.Aliases = map[string]string{}
+		}
+		cfg.Aliases[name] = util.ResolveID(target)
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("✓ Alias %q → %s\n", name, cfg.Aliases[name])
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _ := config.Load()
+		if outputFormat == "json" {
+			aliases := cfg.Aliases
+			if aliases == nil {
+				aliases = map[string]string{}
+			}
+			return render.JSON(aliases)
+		}
+
+		names := make([]string, 0, len(cfg.Aliases))
+		for name := range cfg.Aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var rows [][]string
+		for _, name := range names {
+			rows = append(rows, []string{name, cfg.Aliases[name]})
+		}
+		render.Table([]string{"ALIAS", "ID"}, rows)
+		return nil
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("alias %q not found", args[0])
+		}
+		if _, ok := cfg.Aliases[args[0]]; !ok {
+			return fmt.Errorf("alias %q not found", args[0])
+		}
+		delete(cfg.Aliases, args[0])
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("✓ Alias %q removed\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+}
+
+// resolveAlias returns the ID saved under name, if any.
+func resolveAlias(name string) (string, bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", false
+	}
+	id, ok := cfg.Aliases[name]
+	return id, ok
+}
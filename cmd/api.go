@@ -7,7 +7,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/spf13/cobra"
 )
 
@@ -71,14 +70,13 @@ Examples:
 			}
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var respData []byte
 		if bodyStr != "" {
 			var body interface{}
 			if err := json.Unmarshal([]byte(bodyStr), &body); err != nil {
-				return fmt.Errorf("invalid JSON body: %w", err)
+				return invalidInput(fmt.Errorf("invalid JSON body: %w", err))
 			}
 			switch method {
 			case "PATCH":
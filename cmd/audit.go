@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Reports for keeping a workspace tidy",
+}
+
+var auditStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List pages and rows not edited for a while",
+	Long: `List the pages and database rows nobody has edited within --than (a
+duration such as 180d or 26w, or a date), grouped by where they live, oldest
+first. Only pages shared with the integration are seen.
+
+With --in the report covers one database's rows, or the pages directly
+under one page; otherwise the whole workspace.
+
+Then act on them in bulk:
+  --tag 'Prop=Value'  set a property (rows of databases that have it)
+  --comment 'text'    leave a comment asking the owner to review
+  --archive           move them to the trash (restorable)
+--dry-run shows what would be done.
+
+Examples:
+  notion audit stale --than 180d
+  notion audit stale --than 1y --in <wiki-page-id>
+  notion audit stale --than 90d --in <db-id> --tag 'Status=Needs review'
+  notion audit stale --than 2025-01-01 --archive --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		than, _ := cmd.Flags().GetString("than")
+		in, _ := cmd.Flags().GetString("in")
+		tag, _ := cmd.Flags().GetString("tag")
+		comment, _ := cmd.Flags().GetString("comment")
+		archive, _ := cmd.Flags().GetBool("archive")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		tagProp, tagValue, hasTag := strings.Cut(tag, "=")
+		if tag != "" && (!hasTag || strings.TrimSpace(tagProp) == "") {
+			return usageError(fmt.Errorf("--tag must be Prop=Value, got %q", tag))
+		}
+		cutoff, err := parseSince(than, time.Now())
+		if err != nil {
+			return err
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c, ctx, stop := interruptible(newClient(token))
+		defer stop()
+
+		pages, err := stalePages(c, util.ResolveID(in), cutoff)
+		if err != nil {
+			return err
+		}
+		items := groupStalePages(c, pages)
+
+		acting := tag != "" || comment != "" || archive
+		if !acting || dryRun {
+			if outputFormat == "json" {
+				if items == nil {
+					items = []staleItem{}
+				}
+				return render.JSON(map[string]interface{}{"before": cutoff.Format(time.RFC3339), "count": len(items), "results": items})
+			}
+			printStaleReport(items, cutoff)
+			if acting {
+				fmt.Printf("%d page(s) would be %s\n", len(items), staleActions(tag, comment, archive))
+			}
+			return nil
+		}
+
+		done := 0
+		var errors []string
+		progress := newProgress("audit stale", "%d/%d pages updated", len(items), os.Stdout)
+		defer progress.stop()
+		const resume = "run the same command again to continue"
+		for _, item := range items {
+			if ctx.Err() != nil {
+				return progress.interrupted(resume)
+			}
+			if err := actOnStalePage(c, item, strings.TrimSpace(tagProp), strings.TrimSpace(tagValue), comment, archive); err != nil {
+				if canceled(err) {
+					return progress.interrupted(resume)
+				}
+				errors = append(errors, fmt.Sprintf("%s: %v", item.ID, err))
+				progress.fail(item.ID, err)
+				continue
+			}
+			done++
+			progress.add(1)
+		}
+		progress.finish()
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"matched": len(items), "updated": done, "errors": errors})
+		}
+		if done > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("✓ %d/%d stale page(s) %s\n", done, len(items), staleActions(tag, comment, archive))
+		for _, e := range errors {
+			fmt.Printf("  ✗ %s\n", e)
+		}
+		return nil
+	},
+}
+
+func init() {
+	auditStaleCmd.Flags().String("than", "180d", "Not edited within this duration or since this date (180d, 26w, 1y, 2025-01-01)")
+	auditStaleCmd.Flags().String("in", "", "Only a database's rows or a page's sub-pages")
+	markIDFlags(auditStaleCmd, "in")
+	auditStaleCmd.Flags().String("tag", "", "Set this property on stale rows (Prop=Value)")
+	auditStaleCmd.Flags().String("comment", "", "Comment on each stale page")
+	auditStaleCmd.Flags().Bool("archive", false, "Archive the stale pages")
+	auditStaleCmd.Flags().Bool("dry-run", false, "Show what --tag, --comment or --archive would do")
+	auditCmd.AddCommand(auditStaleCmd)
+}
+
+// staleItem is a page in the stale report.
+type staleItem struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	EditedTime  string `json:"last_edited_time"`
+	ParentID    string `json:"parent_id,omitempty"`
+	ParentTitle string `json:"parent_title"`
+
+	page map[string]interface{}
+}
+
+// stalePages returns the pages last edited before cutoff: rows of the
+// database in, pages directly under the page in, or with in empty every
+// page the search can see.
+func stalePages(c *notion.Client, in string, cutoff time.Time) ([]map[string]interface{}, error) {
+	var results []interface{}
+	if in == "" {
+		body := map[string]interface{}{
+			"filter":    map[string]interface{}{"property": "object", "value": "page"},
+			"sort":      map[string]interface{}{"timestamp": "last_edited_time", "direction": "ascending"},
+			"page_size": 100,
+		}
+		// Oldest first, so the walk stops at the first page edited after
+		// the cutoff.
+		for {
+			data, err := c.Post("/v1/search", body)
+			if err != nil {
+				return nil, fmt.Errorf("search: %w", err)
+			}
+			var page map[string]interface{}
+			if err := json.Unmarshal(data, &page); err != nil {
+				return nil, fmt.Errorf("parse response: %w", err)
+			}
+			batch, _ := page["results"].([]interface{})
+			for _, r := range batch {
+				obj, _ := r.(map[string]interface{})
+				if !editedBefore(obj, cutoff) {
+					return toStaleMaps(results), nil
+				}
+				results = append(results, r)
+			}
+			hasMore, _ := page["has_more"].(bool)
+			next, _ := page["next_cursor"].(string)
+			if !hasMore || next == "" {
+				return toStaleMaps(results), nil
+			}
+			body["start_cursor"] = next
+		}
+	}
+
+	if _, err := c.GetDatabase(in); err == nil {
+		rows, err := c.QueryDatabaseAll(in, map[string]interface{}{
+			"filter": map[string]interface{}{"timestamp": "last_edited_time", "last_edited_time": map[string]interface{}{"before": cutoff.Format(time.RFC3339)}},
+			"sorts":  []interface{}{map[string]interface{}{"timestamp": "last_edited_time", "direction": "ascending"}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("query database: %w", err)
+		}
+		return toStaleMaps(rows), nil
+	}
+	children, err := fetchBlockChildren(c, in, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("list sub-pages: %w", err)
+	}
+	for _, ch := range children {
+		block, _ := ch.(map[string]interface{})
+		if block["type"] != "child_page" {
+			continue
+		}
+		id, _ := block["id"].(string)
+		page, err := c.GetPage(id)
+		if err != nil {
+			return nil, fmt.Errorf("get page: %w", err)
+		}
+		if editedBefore(page, cutoff) {
+			results = append(results, page)
+		}
+	}
+	return toStaleMaps(results), nil
+}
+
+func toStaleMaps(results []interface{}) []map[string]interface{} {
+	var pages []map[string]interface{}
+	for _, r := range results {
+		if page, ok := r.(map[string]interface{}); ok && page["archived"] != true {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+func editedBefore(obj map[string]interface{}, cutoff time.Time) bool {
+	edited, _ := obj["last_edited_time"].(string)
+	t, err := time.Parse(time.RFC3339, edited)
+	return err == nil && t.Before(cutoff)
+}
+
+// groupStalePages names each page's parent and orders the pages by parent
+// title, then oldest first.
+func groupStalePages(c *notion.Client, pages []map[string]interface{}) []staleItem {
+	titles := map[string]string{}
+	blockParents := map[string]string{}
+	parentTitle := func(parent map[string]interface{}) (string, string) {
+		kind, _ := parent["type"].(string)
+		id, _ := parent[kind].(string)
+		if kind == "block_id" {
+			id = blockPage(c, id, blockParents)
+			kind = "page_id"
+		}
+		if id == "" {
+			return "", "Workspace"
+		}
+		if title, ok := titles[id]; ok {
+			return id, title
+		}
+		var obj map[string]interface{}
+		var err error
+		if kind == "database_id" {
+			obj, err = c.GetDatabase(id)
+		} else {
+			obj, err = c.GetPage(id)
+		}
+		title := id
+		if err == nil {
+			title = firstNonEmpty(render.ExtractTitle(obj), "Untitled")
+		}
+		titles[id] = title
+		return id, title
+	}
+
+	items := make([]staleItem, 0, len(pages))
+	for _, page := range pages {
+		id, _ := page["id"].(string)
+		url, _ := page["url"].(string)
+		edited, _ := page["last_edited_time"].(string)
+		parent, _ := page["parent"].(map[string]interface{})
+		parentID, title := parentTitle(parent)
+		items = append(items, staleItem{
+			ID: id, Title: firstNonEmpty(render.ExtractTitle(page), "Untitled"), URL: url, EditedTime: edited,
+			ParentID: parentID, ParentTitle: title, page: page,
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].ParentTitle != items[j].ParentTitle {
+			return items[i].ParentTitle < items[j].ParentTitle
+		}
+		return items[i].EditedTime < items[j].EditedTime
+	})
+	return items
+}
+
+func printStaleReport(items []staleItem, cutoff time.Time) {
+	if len(items) == 0 {
+		fmt.Printf("Nothing unedited since %s.\n", cutoff.Local().Format("2006-01-02"))
+		return
+	}
+	for i := 0; i < len(items); {
+		j := i
+		var rows [][]string
+		for ; j < len(items) && items[j].ParentID == items[i].ParentID && items[j].ParentTitle == items[i].ParentTitle; j++ {
+			edited := items[j].EditedTime
+			if t, err := time.Parse(time.RFC3339, edited); err == nil {
+				edited = t.Local().Format("2006-01-02")
+			}
+			rows = append(rows, []string{items[j].Title, edited, items[j].ID})
+		}
+		render.Title("📁", fmt.Sprintf("%s (%d)", items[i].ParentTitle, j-i))
+		render.Table([]string{"TITLE", "EDITED", "ID"}, rows)
+		fmt.Println()
+		i = j
+	}
+	fmt.Printf("%d page(s) not edited since %s\n", len(items), cutoff.Local().Format("2006-01-02"))
+}
+
+// staleActions describes what was (or would be) done, e.g. "tagged and
+// archived".
+func staleActions(tag, comment string, archive bool) string {
+	var actions []string
+	if tag != "" {
+		actions = append(actions, "tagged")
+	}
+	if comment != "" {
+		actions = append(actions, "commented on")
+	}
+	if archive {
+		actions = append(actions, "archived")
+	}
+	return strings.Join(actions, " and ")
+}
+
+// actOnStalePage tags, comments on and archives one page, in that order.
+// A page without the --tag property (a page outside a database, or a row
+// of another database) is left untagged.
+func actOnStalePage(c *notion.Client, item staleItem, tagProp, tagValue, comment string, archive bool) error {
+	if tagProp != "" {
+		props, _ := item.page["properties"].(map[string]interface{})
+		for name, p := range props {
+			prop, _ := p.(map[string]interface{})
+			propType, _ := prop["type"].(string)
+			if !strings.EqualFold(name, tagProp) || readOnlyPropertyTypes[propType] {
+				continue
+			}
+			body := map[string]interface{}{"properties": map[string]interface{}{name: buildPropertyValue(propType, tagValue)}}
+			if _, err := c.Patch("/v1/pages/"+item.ID, body); err != nil {
+				return fmt.Errorf("tag: %w", err)
+			}
+		}
+	}
+	if comment != "" {
+		if _, err := c.AddComment(item.ID, comment, nil); err != nil {
+			return fmt.Errorf("comment: %w", err)
+		}
+	}
+	if archive {
+		if _, err := c.Patch("/v1/pages/"+item.ID, map[string]interface{}{"archived": true}); err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+	}
+	return nil
+}
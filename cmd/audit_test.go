@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuditStaleCommand(t *testing.T) {
+	const (
+		wikiID = "11111111-1111-1111-1111-111111111111"
+		dbID   = "22222222-2222-2222-2222-222222222222"
+		oldRow = "33333333-3333-3333-3333-333333333333"
+		oldDoc = "44444444-4444-4444-4444-444444444444"
+		fresh  = "55555555-5555-5555-5555-555555555555"
+	)
+	ago := func(days int) string { return time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339) }
+	page := func(id, title, edited string, parent map[string]interface{}, extra map[string]interface{}) map[string]interface{} {
+		props := map[string]interface{}{"Name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": title}}}}
+		for k, v := range extra {
+			props[k] = v
+		}
+		return map[string]interface{}{"object": "page", "id": id, "last_edited_time": edited, "parent": parent, "properties": props}
+	}
+	search := []interface{}{
+		page(oldDoc, "Old spec", ago(400), map[string]interface{}{"type": "page_id", "page_id": wikiID}, nil),
+		page(oldRow, "Q1 plan", ago(300), map[string]interface{}{"type": "database_id", "database_id": dbID},
+			map[string]interface{}{"Status": map[string]interface{}{"type": "select", "select": nil}}),
+		page(fresh, "This week", ago(1), map[string]interface{}{"type": "page_id", "page_id": wikiID}, nil),
+	}
+
+	var mu sync.Mutex
+	var writes []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/v1/search":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": search})
+		case r.Method != "GET":
+			mu.Lock()
+			writes = append(writes, r.Method+" "+r.URL.Path+" "+string(body))
+			mu.Unlock()
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/v1/databases/"+dbID:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": dbID, "title": []interface{}{map[string]interface{}{"plain_text": "Plans"}}})
+		default:
+			json.NewEncoder(w).Encode(page(wikiID, "Wiki", ago(1), map[string]interface{}{"type": "workspace"}, nil))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("audit", "stale", "--than", "180d"); err != nil {
+			t.Fatalf("audit stale: %v", err)
+		}
+	})
+	// Grouped by parent title: Plans before Wiki.
+	row, doc := strings.Index(out, "Q1 plan"), strings.Index(out, "Old spec")
+	if row < 0 || doc < row || strings.Contains(out, "This week") {
+		t.Errorf("report:\n%s", out)
+	}
+	if !strings.Contains(out, "2 page(s) not edited since") {
+		t.Errorf("summary missing:\n%s", out)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("audit", "stale", "--than", "180d", "--tag", "Status=Review", "--archive", "--dry-run"); err != nil {
+			t.Fatalf("audit stale --dry-run: %v", err)
+		}
+	})
+	if len(writes) != 0 {
+		t.Fatalf("dry run wrote: %v", writes)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("audit", "stale", "--than", "180d", "--tag", "Status=Review", "--archive", "--dry-run=false"); err != nil {
+			t.Fatalf("audit stale --archive: %v", err)
+		}
+	})
+	want := []string{
+		`PATCH /v1/pages/` + oldRow + ` {"properties":{"Status":{"select":{"name":"Review"}}}}`,
+		`PATCH /v1/pages/` + oldRow + ` {"archived":true}`,
+		`PATCH /v1/pages/` + oldDoc + ` {"archived":true}`,
+	}
+	if strings.Join(writes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("writes:\n%s\nwant:\n%s", strings.Join(writes, "\n"), strings.Join(want, "\n"))
+	}
+}
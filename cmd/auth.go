@@ -7,9 +7,9 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/config"
 	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
 	"github.com/spf13/cobra"
 )
 
@@ -24,12 +24,15 @@ var authLoginCmd = &cobra.Command{
 	Long: `Authenticate with Notion using an integration token.
 
 Use --profile to save credentials under a named profile for multi-workspace support.
+--read-only marks the profile so commands using it can't change the
+workspace; logging in to it again keeps the mark unless --read-only=false.
 
 Examples:
   notion auth login
   notion auth login --with-token
   notion auth login --profile work
-  echo "secret_xxx" | notion auth login --with-token --profile personal`,
+  echo "secret_xxx" | notion auth login --with-token --profile personal
+  echo "secret_xxx" | notion auth login --with-token --profile agent --read-only`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		withToken, _ := cmd.Flags().GetBool("with-token")
 		profileName, _ := cmd.Flags().GetString("profile")
@@ -58,47 +61,23 @@ Examples:
 			return fmt.Errorf("no token provided")
 		}
 
-		// Validate token by calling the API
-		c := client.New(token)
-		me, err := c.GetMe()
-		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+		var readOnly *bool
+		if cmd.Flags().Changed("read-only") {
+			v, _ := cmd.Flags().GetBool("read-only")
+			readOnly = &v
 		}
-
-		// Extract workspace info
-		botInfo, _ := me["bot"].(map[string]interface{})
-		workspaceName, _ := botInfo["workspace_name"].(string)
-		workspaceID, _ := botInfo["workspace_id"].(string)
-		botID, _ := me["id"].(string)
-
-		// Load existing config or create new
-		cfg, _ := config.Load()
-		if cfg == nil {
-			cfg = &config.Config{}
-		}
-
-		// Migrate legacy config if needed
-		cfg.MigrateToProfiles()
-
-		// Set the profile
-		cfg.SetProfile(profileName, &config.Profile{
-			Token:         token,
-			WorkspaceName: workspaceName,
-			WorkspaceID:   workspaceID,
-			BotID:         botID,
-		})
-
-		// Set as current profile
-		cfg.CurrentProfile = profileName
-
-		if err := config.Save(cfg); err != nil {
-			return fmt.Errorf("save config: %w", err)
+		workspaceName, err := saveLogin(profileName, token, readOnly)
+		if err != nil {
+			return err
 		}
 
 		render.Title("✓", fmt.Sprintf("Logged in to %s", workspaceName))
 		if profileName != "default" {
 			render.Field("Profile", profileName)
 		}
+		if readOnly != nil && *readOnly {
+			render.Field("Mode", "read-only")
+		}
 		return nil
 	},
 }
@@ -123,7 +102,7 @@ Examples:
 			return nil
 		}
 
-		c := client.New(profile.Token)
+		c := notion.New(profile.Token, clientOptions()...)
 		me, err := c.GetMe()
 		if err != nil {
 			return fmt.Errorf("token is invalid: %w", err)
@@ -137,14 +116,14 @@ Examples:
 		render.Title("✓", "Authenticated")
 
 		// Show current profile name
-		profileName := cfg.CurrentProfile
-		if profileName == "" {
-			profileName = "default"
-		}
+		profileName := cfg.ActiveProfile()
 		profiles := cfg.ListProfiles()
 		if len(profiles) > 1 {
 			render.Field("Profile", profileName)
 		}
+		if profile.ReadOnly {
+			render.Field("Mode", "read-only")
+		}
 
 		render.Field("Workspace", workspaceName)
 		render.Field("Bot", name)
@@ -336,77 +315,10 @@ Examples:
 	},
 }
 
-var authDoctorCmd = &cobra.Command{
-	Use:   "doctor",
-	Short: "Check authentication and API connectivity",
-	Long: `Run health checks on your Notion CLI setup.
-
-Validates:
-  - Config file exists and has a token
-  - Token is valid (API responds)
-  - Workspace is accessible
-  - Can list databases
-
-Examples:
-  notion auth doctor`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("Notion CLI Health Check")
-		fmt.Println()
-
-		// Check 1: Config file
-		cfg, err := config.Load()
-		profile := cfg.GetCurrentProfile()
-		token := cfg.Token
-		if token == "" && profile != nil {
-			token = profile.Token
-		}
-		if err != nil || token == "" {
-			fmt.Println("  ✗ Config: no token found")
-			fmt.Println("    Run: notion auth login --with-token")
-			return nil
-		}
-		fmt.Println("  ✓ Config: token found")
-
-		// Check 2: Token validity
-		c := client.New(token)
-		me, err := c.GetMe()
-		if err != nil {
-			fmt.Printf("  ✗ Auth: token is invalid (%v)\n", err)
-			return nil
-		}
-
-		name, _ := me["name"].(string)
-		botInfo, _ := me["bot"].(map[string]interface{})
-		workspace, _ := botInfo["workspace_name"].(string)
-		integrationType := detectIntegrationType(botInfo)
-		fmt.Printf("  ✓ Auth: %s\n", name)
-		fmt.Printf("  ✓ Workspace: %s\n", workspace)
-		if integrationType != "" {
-			fmt.Printf("  ✓ Integration: %s\n", integrationType)
-			if integrationType == "internal" {
-				fmt.Println("    note: internal integrations cannot create pages at the workspace root;")
-				fmt.Println("          create a parent page in Notion and share it with this integration first.")
-			}
-		}
-
-		// Check 3: Can search
-		result, err := c.Search("", "", 1, "")
-		if err != nil {
-			fmt.Printf("  ✗ API: search failed (%v)\n", err)
-			return nil
-		}
-		results, _ := result["results"].([]interface{})
-		fmt.Printf("  ✓ API: search works (%d+ items accessible)\n", len(results))
-
-		fmt.Println()
-		fmt.Println("All checks passed ✓")
-		return nil
-	},
-}
-
 func init() {
 	authLoginCmd.Flags().Bool("with-token", false, "Read token from standard input")
 	authLoginCmd.Flags().StringP("profile", "p", "", "Profile name to save credentials under (default: \"default\")")
+	authLoginCmd.Flags().Bool("read-only", false, "Mark the profile read-only: commands using it can't change the workspace")
 
 	authCmd.AddCommand(authLoginCmd)
 	authCmd.AddCommand(authStatusCmd)
@@ -415,6 +327,55 @@ func init() {
 	authCmd.AddCommand(authSwitchCmd)
 }
 
+// saveLogin validates token against the API, stores it under profileName
+// and makes that profile current. A nil readOnly keeps the profile's
+// read-only mark as it was. It returns the token's workspace name.
+func saveLogin(profileName, token string, readOnly *bool) (string, error) {
+	// Validate token by calling the API
+	c := notion.New(token, clientOptions()...)
+	me, err := c.GetMe()
+	if err != nil {
+		return "", fmt.Errorf("authentication failed: %w", err)
+	}
+
+	// Extract workspace info
+	botInfo, _ := me["bot"].(map[string]interface{})
+	workspaceName, _ := botInfo["workspace_name"].(string)
+	workspaceID, _ := botInfo["workspace_id"].(string)
+	botID, _ := me["id"].(string)
+
+	// Load existing config or create new
+	cfg, _ := config.Load()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	// Migrate legacy config if needed
+	cfg.MigrateToProfiles()
+
+	// Set the profile
+	profile := &config.Profile{
+		Token:         token,
+		WorkspaceName: workspaceName,
+		WorkspaceID:   workspaceID,
+		BotID:         botID,
+	}
+	if readOnly != nil {
+		profile.ReadOnly = *readOnly
+	} else if old := cfg.Profiles[profileName]; old != nil {
+		profile.ReadOnly = old.ReadOnly
+	}
+	cfg.SetProfile(profileName, profile)
+
+	// Set as current profile
+	cfg.CurrentProfile = profileName
+
+	if err := config.Save(cfg); err != nil {
+		return "", fmt.Errorf("save config: %w", err)
+	}
+	return workspaceName, nil
+}
+
 // detectIntegrationType inspects the bot object returned by
 // GET /v1/users/me and classifies the integration as "internal",
 // "public", or "" when the shape is unrecognizable.
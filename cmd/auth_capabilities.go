@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// capability is the outcome of probing one thing the token may be allowed
+// to do. Status is "yes", "no" or "unknown".
+type capability struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+const integrationSettingsURL = "https://www.notion.so/my-integrations"
+
+var authCapabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Probe what the current token can do",
+	Long: `Report what the current token is allowed to do, derived from probing
+read-only endpoints:
+
+  - integration type (internal or public) and workspace
+  - how many pages and databases are shared with it
+  - whether it can read comments
+  - whether it can read user information, and whether emails are visible
+
+Write capabilities (insert/update content, insert comments) cannot be
+detected without writing, so they are reported as unknown.
+
+Examples:
+  notion auth capabilities
+  notion auth capabilities --limit 0
+  notion auth capabilities --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		c := newClient(token)
+
+		report, err := probeCapabilities(c, limit)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(report)
+		}
+
+		render.Title("🔑", "Token capabilities")
+		render.Field("Workspace", report.Workspace)
+		render.Field("Bot", report.Bot)
+		if report.Integration != "" {
+			render.Field("Integration", report.Integration)
+		}
+		render.Field("Pages", countLabel(report.Pages, report.Truncated))
+		render.Field("Databases", countLabel(report.Databases, report.Truncated))
+		fmt.Println()
+		for _, entry := range report.Capabilities {
+			mark := "?"
+			switch entry.Status {
+			case "yes":
+				mark = "✓"
+			case "no":
+				mark = "✗"
+			}
+			line := fmt.Sprintf("  %s %s", mark, entry.Name)
+			if entry.Detail != "" {
+				line += " — " + entry.Detail
+			}
+			fmt.Println(line)
+			if entry.Hint != "" {
+				fmt.Printf("    → %s\n", entry.Hint)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	authCapabilitiesCmd.Flags().Int("limit", 1000, "Stop counting shared pages/databases after this many (0 = count all)")
+	authCmd.AddCommand(authCapabilitiesCmd)
+}
+
+// capabilityReport is the full result of 'auth capabilities'.
+type capabilityReport struct {
+	Workspace    string       `json:"workspace"`
+	Bot          string       `json:"bot"`
+	Integration  string       `json:"integration,omitempty"`
+	Pages        int          `json:"pages"`
+	Databases    int          `json:"databases"`
+	Truncated    bool         `json:"truncated,omitempty"`
+	Capabilities []capability `json:"capabilities"`
+}
+
+// probeCapabilities issues read-only requests and classifies each failure.
+// Only an invalid token is returned as an error; everything else becomes a
+// capability entry.
+func probeCapabilities(c *notion.Client, limit int) (*capabilityReport, error) {
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, fmt.Errorf("token is invalid: %w", err)
+	}
+	botInfo, _ := me["bot"].(map[string]interface{})
+	report := &capabilityReport{}
+	report.Workspace, _ = botInfo["workspace_name"].(string)
+	report.Bot, _ = me["name"].(string)
+	report.Integration = detectIntegrationType(botInfo)
+
+	var firstPage string
+	var searchErr error
+	var truncPages, truncDBs bool
+	report.Pages, firstPage, truncPages, searchErr = countShared(c, "page", limit)
+	if searchErr == nil {
+		report.Databases, _, truncDBs, searchErr = countShared(c, "database", limit)
+	}
+	report.Truncated = truncPages || truncDBs
+
+	read := capability{Name: "Read content"}
+	switch {
+	case searchErr != nil:
+		read.Status = "unknown"
+		read.Detail = firstLine(searchErr)
+	case report.Pages+report.Databases == 0:
+		read.Status = "unknown"
+		read.Detail = "nothing is shared with this integration yet"
+		read.Hint = "Open a page in Notion → ••• → Connections → add this integration"
+	default:
+		read.Status = "yes"
+		read.Detail = fmt.Sprintf("%s pages, %s databases shared", countLabel(report.Pages, truncPages), countLabel(report.Databases, truncDBs))
+	}
+	report.Capabilities = append(report.Capabilities, read)
+
+	comments := capability{Name: "Read comments"}
+	if firstPage == "" {
+		comments.Status = "unknown"
+		comments.Detail = "no shared page to probe"
+	} else if _, err := c.ListComments(firstPage, 1, ""); err != nil {
+		comments.Status, comments.Detail = classifyProbeError(err)
+		if comments.Status == "no" {
+			comments.Hint = "Enable \"Read comments\" under Capabilities at " + integrationSettingsURL
+		}
+	} else {
+		comments.Status = "yes"
+	}
+	report.Capabilities = append(report.Capabilities, comments)
+
+	users := capability{Name: "Read user information"}
+	if result, err := c.GetUsers(100, ""); err != nil {
+		users.Status, users.Detail = classifyProbeError(err)
+		if users.Status == "no" {
+			users.Hint = "Enable \"Read user information\" under Capabilities at " + integrationSettingsURL
+		}
+	} else {
+		users.Status = "yes"
+		if usersExposeEmail(result) {
+			users.Detail = "including email addresses"
+		} else {
+			users.Detail = "without email addresses"
+		}
+	}
+	report.Capabilities = append(report.Capabilities, users)
+
+	for _, name := range []string{"Insert content", "Update content", "Insert comments"} {
+		report.Capabilities = append(report.Capabilities, capability{
+			Name:   name,
+			Status: "unknown",
+			Detail: "not probed (would require a write)",
+		})
+	}
+
+	if report.Integration == "internal" {
+		report.Capabilities = append(report.Capabilities, capability{
+			Name:   "Create workspace-root pages",
+			Status: "no",
+			Detail: "internal integrations can only create pages under shared parents",
+		})
+	}
+
+	return report, nil
+}
+
+// countShared pages through search results of one object type. It returns
+// the count, the ID of the first result, and whether the count stopped at
+// limit.
+func countShared(c *notion.Client, objectType string, limit int) (int, string, bool, error) {
+	count := 0
+	first := ""
+	cursor := ""
+	for {
+		result, err := c.Search("", objectType, 100, cursor)
+		if err != nil {
+			return count, first, false, err
+		}
+		results, _ := result["results"].([]interface{})
+		for _, r := range results {
+			if obj, ok := r.(map[string]interface{}); ok && first == "" {
+				first, _ = obj["id"].(string)
+			}
+		}
+		count += len(results)
+		hasMore, _ := result["has_more"].(bool)
+		nextCursor, _ := result["next_cursor"].(string)
+		if limit > 0 && count >= limit {
+			return limit, first, hasMore || count > limit, nil
+		}
+		if !hasMore || nextCursor == "" {
+			return count, first, false, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// classifyProbeError maps an API error to a capability status: permission
+// errors mean "no", anything else leaves the answer unknown.
+func classifyProbeError(err error) (string, string) {
+	if errors.Is(err, notion.ErrRestricted) {
+		return "no", "integration lacks this capability"
+	}
+	return "unknown", firstLine(err)
+}
+
+func usersExposeEmail(result map[string]interface{}) bool {
+	results, _ := result["results"].([]interface{})
+	for _, r := range results {
+		u, _ := r.(map[string]interface{})
+		person, _ := u["person"].(map[string]interface{})
+		if email, _ := person["email"].(string); email != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func countLabel(n int, truncated bool) string {
+	if truncated {
+		return fmt.Sprintf("%d+", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func firstLine(err error) string {
+	msg := err.Error()
+	if i := strings.Index(msg, "\n"); i >= 0 {
+		msg = msg[:i]
+	}
+	return msg
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func newCapabilitiesServer(t *testing.T, commentsAllowed bool) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/users/me", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":   "bot-1",
+			"name": "Probe Bot",
+			"bot": map[string]interface{}{
+				"workspace_name": "Acme",
+				"owner":          map[string]interface{}{"type": "workspace", "workspace": true},
+			},
+		})
+	})
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter, _ := body["filter"].(map[string]interface{})
+		if filter["value"] == "database" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"id": "db-1"},
+			}})
+			return
+		}
+		if body["start_cursor"] == nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"results":     []interface{}{map[string]interface{}{"id": "page-1"}, map[string]interface{}{"id": "page-2"}},
+				"has_more":    true,
+				"next_cursor": "c2",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{map[string]interface{}{"id": "page-3"}}})
+	})
+	mux.HandleFunc("/v1/comments", func(w http.ResponseWriter, r *http.Request) {
+		if !commentsAllowed {
+			w.WriteHeader(403)
+			json.NewEncoder(w).Encode(map[string]string{
+				"code":    "restricted_resource",
+				"message": "Insufficient permissions for this endpoint.",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+	})
+	mux.HandleFunc("/v1/users", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+			map[string]interface{}{"type": "person", "person": map[string]interface{}{"email": "a@b.co"}},
+		}})
+	})
+	return httptest.NewServer(mux)
+}
+
+func findCapability(report *capabilityReport, name string) capability {
+	for _, c := range report.Capabilities {
+		if c.Name == name {
+			return c
+		}
+	}
+	return capability{}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	server := newCapabilitiesServer(t, false)
+	defer server.Close()
+
+	report, err := probeCapabilities(notion.New("tok", notion.WithBaseURL(server.URL)), 0)
+	if err != nil {
+		t.Fatalf("probeCapabilities: %v", err)
+	}
+	if report.Workspace != "Acme" || report.Integration != "internal" {
+		t.Errorf("workspace/integration = %q/%q", report.Workspace, report.Integration)
+	}
+	if report.Pages != 3 || report.Databases != 1 || report.Truncated {
+		t.Errorf("pages=%d databases=%d truncated=%v", report.Pages, report.Databases, report.Truncated)
+	}
+	if got := findCapability(report, "Read comments"); got.Status != "no" || got.Hint == "" {
+		t.Errorf("Read comments = %+v, want no with hint", got)
+	}
+	if got := findCapability(report, "Read user information"); got.Status != "yes" || got.Detail != "including email addresses" {
+		t.Errorf("Read user information = %+v", got)
+	}
+	if got := findCapability(report, "Create workspace-root pages"); got.Status != "no" {
+		t.Errorf("internal integration should not create root pages: %+v", got)
+	}
+}
+
+func TestProbeCapabilities_LimitTruncates(t *testing.T) {
+	server := newCapabilitiesServer(t, true)
+	defer server.Close()
+
+	report, err := probeCapabilities(notion.New("tok", notion.WithBaseURL(server.URL)), 2)
+	if err != nil {
+		t.Fatalf("probeCapabilities: %v", err)
+	}
+	if report.Pages != 2 || !report.Truncated {
+		t.Errorf("pages=%d truncated=%v, want 2 truncated", report.Pages, report.Truncated)
+	}
+	if got := findCapability(report, "Read comments"); got.Status != "yes" {
+		t.Errorf("Read comments = %+v, want yes", got)
+	}
+}
@@ -0,0 +1,558 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// Doctor check statuses.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// maxClockSkew is how far the local clock may drift from Notion's before
+// date filters like "today" start returning surprising results.
+const maxClockSkew = 2 * time.Minute
+
+// doctorResult is the outcome of one diagnostic check.
+type doctorResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+	Fixable bool   `json:"fixable,omitempty"`
+	Fixed   bool   `json:"fixed,omitempty"`
+}
+
+// doctorCheck is a named diagnostic. Checks run in order and share state
+// through the doctor, so later checks can build on earlier ones (the API
+// checks reuse the token and response found by the auth check).
+type doctorCheck struct {
+	name string
+	run  func(d *doctor) doctorResult
+}
+
+// doctor carries the state shared by all checks of one run.
+type doctor struct {
+	fix bool
+
+	cfg    *config.Config
+	cfgErr error
+
+	token       string
+	tokenSource string
+	client      *notion.Client
+
+	me     map[string]interface{}
+	meResp *http.Response
+}
+
+var doctorChecks = []doctorCheck{
+	{"Config", checkDoctorConfig},
+	{"Profiles", checkDoctorProfiles},
+	{"Permissions", checkDoctorPermissions},
+	{"Keyring", checkDoctorKeyring},
+	{"Network", checkDoctorNetwork},
+	{"Auth", checkDoctorAuth},
+	{"Workspace", checkDoctorWorkspace},
+	{"Clock", checkDoctorClock},
+	{"Rate limit", checkDoctorRateLimit},
+	{"API", checkDoctorSearch},
+	{"Cache", checkDoctorCache},
+}
+
+var authDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration, authentication and connectivity",
+	Long: `Run health checks on your Notion CLI setup.
+
+Checks:
+  - Config       config file exists and parses
+  - Profiles     current profile exists, no stale legacy token, no empty tokens
+  - Permissions  config file is 0600 and its directory 0700
+  - Keyring      whether an OS keyring is available (tokens live in config.json)
+  - Network      api.notion.com (or NOTION_BASE_URL) is reachable
+  - Auth         the token in use is valid, and where it comes from
+  - Workspace    the token's workspace matches the saved profile
+  - Clock        local clock agrees with Notion's within 2 minutes
+  - Rate limit   the token is not currently being throttled
+  - API          search works and content is shared with the integration
+  - Cache        the cache directory is writable and its entries readable
+
+With --fix, repairable problems are fixed in place: legacy configs are
+migrated to profiles, a dangling current profile is reset, file
+permissions are tightened, stale workspace metadata is refreshed and
+corrupt cache entries are removed.
+
+Examples:
+  notion auth doctor
+  notion auth doctor --fix
+  notion auth doctor --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fix, _ := cmd.Flags().GetBool("fix")
+		results := runDoctor(fix)
+
+		failed, fixable := 0, 0
+		for _, r := range results {
+			if r.Status == doctorFail || r.Status == doctorWarn {
+				failed++
+				if r.Fixable && !r.Fixed {
+					fixable++
+				}
+			}
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{
+				"ok":     failed == 0,
+				"checks": results,
+			})
+		}
+
+		fmt.Println("Notion CLI Health Check")
+		fmt.Println()
+		for _, r := range results {
+			icon := "✓"
+			switch r.Status {
+			case doctorWarn:
+				icon = "!"
+			case doctorFail:
+				icon = "✗"
+			case doctorSkip:
+				icon = "-"
+			}
+			line := fmt.Sprintf("  %s %s", icon, r.Name)
+			if r.Detail != "" {
+				line += ": " + r.Detail
+			}
+			if r.Fixed {
+				line += " (fixed)"
+			}
+			fmt.Println(line)
+			if r.Hint != "" && !r.Fixed {
+				for _, h := range strings.Split(r.Hint, "\n") {
+					fmt.Printf("    %s\n", h)
+				}
+			}
+		}
+
+		fmt.Println()
+		switch {
+		case failed == 0:
+			fmt.Println("All checks passed ✓")
+		case fixable > 0:
+			fmt.Printf("%d problem(s) found; run 'notion auth doctor --fix' to repair %d of them\n", failed, fixable)
+		default:
+			fmt.Printf("%d problem(s) found\n", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	authDoctorCmd.Flags().Bool("fix", false, "Repair problems that can be fixed automatically")
+}
+
+// runDoctor executes every check in order and returns their results.
+func runDoctor(fix bool) []doctorResult {
+	d := &doctor{fix: fix}
+	d.cfg, d.cfgErr = config.Load()
+	d.token, d.tokenSource = resolveToken()
+	if d.token != "" {
+		d.client = newClient(d.token)
+	}
+
+	results := make([]doctorResult, 0, len(doctorChecks))
+	for _, check := range doctorChecks {
+		r := check.run(d)
+		r.Name = check.name
+		results = append(results, r)
+	}
+	return results
+}
+
+func checkDoctorConfig(d *doctor) doctorResult {
+	path := config.Path()
+	if errors.Is(d.cfgErr, config.ErrDisabled) {
+		return doctorResult{Status: doctorSkip, Detail: "not read (--no-config)"}
+	}
+	if d.cfgErr != nil {
+		if os.IsNotExist(d.cfgErr) {
+			if d.tokenSource == "NOTION_TOKEN" {
+				return doctorResult{Status: doctorOK, Detail: "no config file (using NOTION_TOKEN)"}
+			}
+			return doctorResult{Status: doctorFail, Detail: "no config file", Hint: "Run: notion auth login --with-token"}
+		}
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("%s is unreadable: %v", path, d.cfgErr),
+			Hint: "Fix or remove the file, then run: notion auth login"}
+	}
+	return doctorResult{Status: doctorOK, Detail: path}
+}
+
+func checkDoctorProfiles(d *doctor) doctorResult {
+	if d.cfgErr != nil {
+		return doctorResult{Status: doctorSkip, Detail: "no config"}
+	}
+	cfg := d.cfg
+	var problems []string
+
+	if cfg.Token != "" && len(cfg.Profiles) > 0 {
+		problems = append(problems, "stale legacy token next to profiles (ignored)")
+	} else if cfg.Token != "" {
+		problems = append(problems, "legacy single-token config")
+	}
+	if len(cfg.Profiles) > 0 {
+		current := cfg.CurrentProfile
+		if current == "" {
+			current = "default"
+		}
+		if _, ok := cfg.Profiles[current]; !ok {
+			problems = append(problems, fmt.Sprintf("current profile %q does not exist", current))
+		}
+	}
+	var empty []string
+	for _, name := range cfg.ListProfiles() {
+		if p := cfg.Profiles[name]; p != nil && p.Token == "" {
+			empty = append(empty, name)
+		}
+	}
+
+	if len(problems) == 0 && len(empty) == 0 {
+		if len(cfg.Profiles) == 0 {
+			return doctorResult{Status: doctorOK, Detail: "no profiles saved"}
+		}
+		return doctorResult{Status: doctorOK, Detail: fmt.Sprintf("%d profile(s), current %q", len(cfg.Profiles), currentProfileName(cfg))}
+	}
+
+	// Only the structural problems can be repaired; empty profiles need a login.
+	r := doctorResult{Status: doctorWarn, Fixable: len(problems) > 0}
+	if len(empty) > 0 {
+		problems = append(problems, fmt.Sprintf("profile(s) without a token: %s", strings.Join(empty, ", ")))
+		r.Hint = "Run: notion auth login --profile <name>"
+	}
+	r.Detail = strings.Join(problems, "; ")
+	if !r.Fixable || !d.fix {
+		return r
+	}
+
+	if cfg.Token != "" && len(cfg.Profiles) > 0 {
+		cfg.Token, cfg.WorkspaceName, cfg.WorkspaceID, cfg.BotID = "", "", "", ""
+	}
+	cfg.MigrateToProfiles()
+	if _, ok := cfg.Profiles[currentProfileName(cfg)]; !ok {
+		if names := cfg.ListProfiles(); len(names) > 0 {
+			cfg.CurrentProfile = names[0]
+		}
+	}
+	if err := config.Save(cfg); err != nil {
+		r.Hint = fmt.Sprintf("could not save config: %v", err)
+		return r
+	}
+	r.Fixed = true
+	return r
+}
+
+func checkDoctorPermissions(d *doctor) doctorResult {
+	if runtime.GOOS == "windows" {
+		return doctorResult{Status: doctorSkip, Detail: "not applicable on Windows"}
+	}
+	if config.IsDisabled() {
+		return doctorResult{Status: doctorSkip, Detail: "not read (--no-config)"}
+	}
+	path := config.Path()
+	fi, err := os.Stat(path)
+	if err != nil {
+		return doctorResult{Status: doctorSkip, Detail: "no config file"}
+	}
+	var problems []string
+	if fi.Mode().Perm()&0o077 != 0 {
+		problems = append(problems, fmt.Sprintf("config.json is %04o (want 0600)", fi.Mode().Perm()))
+	}
+	if di, err := os.Stat(config.Dir()); err == nil && di.Mode().Perm()&0o077 != 0 {
+		problems = append(problems, fmt.Sprintf("%s is %04o (want 0700)", config.Dir(), di.Mode().Perm()))
+	}
+	if len(problems) == 0 {
+		return doctorResult{Status: doctorOK, Detail: "config.json is private (0600)"}
+	}
+
+	r := doctorResult{
+		Status:  doctorFail,
+		Detail:  strings.Join(problems, "; "),
+		Hint:    "Other users on this machine can read your token",
+		Fixable: true,
+	}
+	if d.fix {
+		if err := os.Chmod(path, 0o600); err != nil {
+			r.Hint = fmt.Sprintf("chmod failed: %v", err)
+			return r
+		}
+		if err := os.Chmod(config.Dir(), 0o700); err != nil {
+			r.Hint = fmt.Sprintf("chmod failed: %v", err)
+			return r
+		}
+		r.Fixed = true
+	}
+	return r
+}
+
+func checkDoctorKeyring(d *doctor) doctorResult {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "windows":
+		tool = "cmdkey"
+	default:
+		tool = "secret-tool"
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return doctorResult{Status: doctorOK, Detail: "no OS keyring found; tokens are stored in config.json"}
+	}
+	return doctorResult{Status: doctorOK, Detail: fmt.Sprintf("%s available; tokens are stored in config.json", tool)}
+}
+
+func checkDoctorNetwork(d *doctor) doctorResult {
+	base := notion.BaseURL
+	if d.client != nil {
+		base = d.client.APIBase()
+	} else if env := os.Getenv("NOTION_BASE_URL"); env != "" {
+		base = env
+	}
+	u, err := url.Parse(base)
+	if err != nil || u.Host == "" {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("invalid API base %q", base)}
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "http" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+	if err != nil {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("cannot reach %s (%v)", u.Host, err),
+			Hint: "Check your connection, proxy (HTTPS_PROXY) and firewall settings"}
+	}
+	conn.Close()
+	return doctorResult{Status: doctorOK, Detail: fmt.Sprintf("%s reachable (%s)", u.Host, time.Since(start).Round(time.Millisecond))}
+}
+
+func checkDoctorAuth(d *doctor) doctorResult {
+	if d.client == nil {
+		return doctorResult{Status: doctorFail, Detail: "no token found", Hint: "Run: notion auth login --with-token"}
+	}
+	resp, body, err := d.client.Probe("/v1/users/me")
+	if err != nil {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("request failed (%v)", err)}
+	}
+	d.meResp = resp
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return doctorResult{Status: doctorSkip, Detail: "rate limited, could not verify token"}
+	}
+	if resp.StatusCode != http.StatusOK {
+		r := doctorResult{Status: doctorFail, Detail: fmt.Sprintf("token from %s is invalid (%s)", d.tokenSource, resp.Status)}
+		if d.tokenSource == "NOTION_TOKEN" {
+			r.Hint = "Unset NOTION_TOKEN or replace it with a valid token"
+		} else {
+			r.Hint = "Run: notion auth login --with-token"
+		}
+		return r
+	}
+	if err := json.Unmarshal(body, &d.me); err != nil {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("parse response: %v", err)}
+	}
+
+	name, _ := d.me["name"].(string)
+	detail := fmt.Sprintf("%s (token from %s)", name, d.tokenSource)
+	botInfo, _ := d.me["bot"].(map[string]interface{})
+	r := doctorResult{Status: doctorOK, Detail: detail}
+	if detectIntegrationType(botInfo) == "internal" {
+		r.Detail += ", internal integration"
+		r.Hint = "note: internal integrations cannot create pages at the workspace root;\n" +
+			"      create a parent page in Notion and share it with this integration first."
+	}
+	if d.tokenSource == "NOTION_TOKEN" && d.cfgErr == nil && len(d.cfg.Profiles) > 0 {
+		r.Status = doctorWarn
+		r.Detail += fmt.Sprintf("; overrides profile %q", currentProfileName(d.cfg))
+		r.Hint = "Unset NOTION_TOKEN to use the saved profile"
+	}
+	return r
+}
+
+func checkDoctorWorkspace(d *doctor) doctorResult {
+	if d.me == nil {
+		return doctorResult{Status: doctorSkip, Detail: "token not verified"}
+	}
+	botInfo, _ := d.me["bot"].(map[string]interface{})
+	workspace, _ := botInfo["workspace_name"].(string)
+	workspaceID, _ := botInfo["workspace_id"].(string)
+	botID, _ := d.me["id"].(string)
+
+	if d.tokenSource == "NOTION_TOKEN" || d.cfgErr != nil || len(d.cfg.Profiles) == 0 {
+		return doctorResult{Status: doctorOK, Detail: workspace}
+	}
+	name := currentProfileName(d.cfg)
+	profile := d.cfg.Profiles[name]
+	if profile == nil {
+		return doctorResult{Status: doctorOK, Detail: workspace}
+	}
+	if (profile.WorkspaceID == "" || profile.WorkspaceID == workspaceID) && profile.WorkspaceName == workspace {
+		return doctorResult{Status: doctorOK, Detail: workspace}
+	}
+
+	r := doctorResult{
+		Status:  doctorWarn,
+		Detail:  fmt.Sprintf("profile %q says %q but the token belongs to %q", name, profile.WorkspaceName, workspace),
+		Fixable: true,
+	}
+	if d.fix {
+		profile.WorkspaceName, profile.WorkspaceID, profile.BotID = workspace, workspaceID, botID
+		if err := config.Save(d.cfg); err != nil {
+			r.Hint = fmt.Sprintf("could not save config: %v", err)
+			return r
+		}
+		r.Fixed = true
+	}
+	return r
+}
+
+func checkDoctorClock(d *doctor) doctorResult {
+	if d.meResp == nil {
+		return doctorResult{Status: doctorSkip, Detail: "no response from Notion"}
+	}
+	serverTime, err := http.ParseTime(d.meResp.Header.Get("Date"))
+	if err != nil {
+		return doctorResult{Status: doctorSkip, Detail: "server sent no Date header"}
+	}
+	skew := time.Since(serverTime).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		return doctorResult{Status: doctorWarn, Detail: fmt.Sprintf("local clock is off by %s", skew),
+			Hint: "Relative dates (today, this week) may resolve incorrectly; enable NTP sync"}
+	}
+	return doctorResult{Status: doctorOK, Detail: fmt.Sprintf("in sync (±%s)", skew)}
+}
+
+func checkDoctorRateLimit(d *doctor) doctorResult {
+	if d.meResp == nil {
+		return doctorResult{Status: doctorSkip, Detail: "no response from Notion"}
+	}
+	if d.meResp.StatusCode == http.StatusTooManyRequests {
+		wait := d.meResp.Header.Get("Retry-After")
+		if wait == "" {
+			wait = "a few"
+		}
+		return doctorResult{Status: doctorWarn, Detail: "currently throttled",
+			Hint: fmt.Sprintf("Retry after %s second(s); Notion allows about 3 requests/second per integration", wait)}
+	}
+	if remaining := d.meResp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+		return doctorResult{Status: doctorOK, Detail: fmt.Sprintf("%s request(s) remaining in window", remaining)}
+	}
+	return doctorResult{Status: doctorOK, Detail: "not throttled"}
+}
+
+func checkDoctorSearch(d *doctor) doctorResult {
+	if d.me == nil {
+		return doctorResult{Status: doctorSkip, Detail: "token not verified"}
+	}
+	result, err := d.client.Search("", "", 1, "")
+	if err != nil {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("search failed (%s)", firstLine(err))}
+	}
+	results, _ := result["results"].([]interface{})
+	if len(results) == 0 {
+		return doctorResult{Status: doctorWarn, Detail: "search works but nothing is shared with this integration",
+			Hint: "Open a page in Notion → ••• → Connections → add this integration"}
+	}
+	return doctorResult{Status: doctorOK, Detail: fmt.Sprintf("search works (%d+ items accessible)", len(results))}
+}
+
+func checkDoctorCache(d *doctor) doctorResult {
+	dir := config.CacheDir()
+	fi, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return doctorResult{Status: doctorOK, Detail: "empty"}
+	}
+	if err != nil || !fi.IsDir() {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("%s is not a usable directory", dir)}
+	}
+
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return doctorResult{Status: doctorFail, Detail: fmt.Sprintf("%s is not writable", dir),
+			Hint: fmt.Sprintf("Fix its permissions or remove it: rm -rf %s", dir)}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	var files int
+	var size int64
+	var corrupt []string
+	filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return nil
+		}
+		files++
+		if info, err := e.Info(); err == nil {
+			size += info.Size()
+		}
+		if strings.HasSuffix(path, ".json") {
+			data, err := os.ReadFile(path)
+			if err != nil || !json.Valid(data) {
+				corrupt = append(corrupt, path)
+			}
+		}
+		return nil
+	})
+
+	detail := fmt.Sprintf("%d file(s), %.1f KB in %s", files, float64(size)/1024, dir)
+	if len(corrupt) == 0 {
+		return doctorResult{Status: doctorOK, Detail: detail}
+	}
+	r := doctorResult{
+		Status:  doctorWarn,
+		Detail:  fmt.Sprintf("%s; %d corrupt entries", detail, len(corrupt)),
+		Fixable: true,
+	}
+	if d.fix {
+		for _, path := range corrupt {
+			os.Remove(path)
+		}
+		r.Fixed = true
+	}
+	return r
+}
+
+// currentProfileName returns the name of the active profile, defaulting to
+// "default" like Config.GetCurrentProfile does.
+func currentProfileName(cfg *config.Config) string {
+	if cfg.CurrentProfile == "" {
+		return "default"
+	}
+	return cfg.CurrentProfile
+}
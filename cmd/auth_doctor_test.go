@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func doctorResultByName(results []doctorResult, name string) doctorResult {
+	for _, r := range results {
+		if r.Name == name {
+			return r
+		}
+	}
+	return doctorResult{}
+}
+
+func TestDoctorPrefersProfileOverStaleLegacyToken(t *testing.T) {
+	server := setupAuthTest(t)
+	defer server.Close()
+
+	config.Save(&config.Config{
+		Token:          "secret_bad_token",
+		CurrentProfile: "default",
+		Profiles: map[string]*config.Profile{
+			"default": {Token: "secret_valid_token", WorkspaceName: "Test Workspace"},
+		},
+	})
+
+	results := runDoctor(false)
+	if got := doctorResultByName(results, "Auth"); got.Status != doctorOK {
+		t.Errorf("Auth = %+v, want ok (profile token should be used)", got)
+	}
+	profiles := doctorResultByName(results, "Profiles")
+	if profiles.Status != doctorWarn || !profiles.Fixable {
+		t.Errorf("Profiles = %+v, want fixable warning", profiles)
+	}
+
+	runDoctor(true)
+	cfg, _ := config.Load()
+	if cfg.Token != "" {
+		t.Errorf("legacy token not cleared by --fix: %q", cfg.Token)
+	}
+	if cfg.Profiles["default"].Token != "secret_valid_token" {
+		t.Errorf("profile token changed: %q", cfg.Profiles["default"].Token)
+	}
+}
+
+func TestDoctorUsesNotionTokenEnv(t *testing.T) {
+	server := setupAuthTest(t)
+	defer server.Close()
+	t.Setenv("NOTION_TOKEN", "secret_valid_token")
+
+	results := runDoctor(false)
+	if got := doctorResultByName(results, "Config"); got.Status != doctorOK {
+		t.Errorf("Config = %+v, want ok with NOTION_TOKEN", got)
+	}
+	if got := doctorResultByName(results, "Auth"); got.Status != doctorOK {
+		t.Errorf("Auth = %+v, want ok", got)
+	}
+}
+
+func TestDoctorFixMigratesLegacyAndDanglingProfile(t *testing.T) {
+	server := setupAuthTest(t)
+	defer server.Close()
+
+	config.Save(&config.Config{Token: "secret_valid_token", WorkspaceName: "Test Workspace"})
+	runDoctor(true)
+	cfg, _ := config.Load()
+	if cfg.Token != "" || cfg.Profiles["default"] == nil {
+		t.Fatalf("legacy config not migrated: %+v", cfg)
+	}
+
+	cfg.CurrentProfile = "gone"
+	config.Save(cfg)
+	if got := doctorResultByName(runDoctor(false), "Profiles"); got.Status != doctorWarn {
+		t.Errorf("Profiles = %+v, want warning for dangling current profile", got)
+	}
+	runDoctor(true)
+	cfg, _ = config.Load()
+	if cfg.CurrentProfile != "default" {
+		t.Errorf("CurrentProfile = %q, want default", cfg.CurrentProfile)
+	}
+}
+
+func TestDoctorFixTightensPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix file permissions not applicable on Windows")
+	}
+	server := setupAuthTest(t)
+	defer server.Close()
+
+	config.Save(&config.Config{
+		CurrentProfile: "default",
+		Profiles:       map[string]*config.Profile{"default": {Token: "secret_valid_token", WorkspaceName: "Test Workspace"}},
+	})
+	os.Chmod(config.Path(), 0o644)
+
+	if got := doctorResultByName(runDoctor(false), "Permissions"); got.Status != doctorFail {
+		t.Fatalf("Permissions = %+v, want fail", got)
+	}
+	runDoctor(true)
+	fi, _ := os.Stat(config.Path())
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %04o, want 0600", fi.Mode().Perm())
+	}
+}
+
+func TestDoctorWorkspaceMismatch(t *testing.T) {
+	server := setupAuthTest(t)
+	defer server.Close()
+
+	config.Save(&config.Config{
+		CurrentProfile: "default",
+		Profiles:       map[string]*config.Profile{"default": {Token: "secret_valid_token", WorkspaceName: "Old Name"}},
+	})
+	if got := doctorResultByName(runDoctor(false), "Workspace"); got.Status != doctorWarn {
+		t.Fatalf("Workspace = %+v, want warning", got)
+	}
+	runDoctor(true)
+	cfg, _ := config.Load()
+	if cfg.Profiles["default"].WorkspaceName != "Test Workspace" {
+		t.Errorf("WorkspaceName = %q, want refreshed", cfg.Profiles["default"].WorkspaceName)
+	}
+}
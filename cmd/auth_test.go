@@ -10,8 +10,6 @@ import (
 	"testing"
 
 	"github.com/4ier/notion-cli/internal/config"
-	"github.com/spf13/cobra"
-	"github.com/spf13/pflag"
 )
 
 // setupAuthTest creates a mock Notion API server and sets env vars for isolated testing.
@@ -65,6 +63,7 @@ func setupAuthTest(t *testing.T) *httptest.Server {
 	t.Setenv("NOTION_BASE_URL", server.URL)
 	// Use temp config dir
 	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
 	// Clear any real token
 	t.Setenv("NOTION_TOKEN", "")
 
@@ -86,29 +85,6 @@ func executeCommand(args ...string) (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
-func resetCommandFlags(cmd interface{ Flags() *pflag.FlagSet; PersistentFlags() *pflag.FlagSet; Commands() []*cobra.Command }) {
-	resetFlagSet(cmd.Flags())
-	resetFlagSet(cmd.PersistentFlags())
-	for _, sub := range cmd.Commands() {
-		resetCommandFlags(sub)
-	}
-}
-
-func resetFlagSet(fs *pflag.FlagSet) {
-	if fs == nil {
-		return
-	}
-
-	fs.VisitAll(func(f *pflag.Flag) {
-		if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
-			_ = sliceValue.Replace(nil)
-		} else {
-			_ = f.Value.Set(f.DefValue)
-		}
-		f.Changed = false
-	})
-}
-
 // --- auth login ---
 
 func TestAuthLoginValidToken(t *testing.T) {
@@ -0,0 +1,498 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// batchOp is one line of a batch file.
+type batchOp struct {
+	Op         string                 `json:"op"`
+	Parent     string                 `json:"parent,omitempty"`
+	Page       string                 `json:"page,omitempty"`
+	Title      string                 `json:"title,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Markdown   string                 `json:"markdown,omitempty"`
+	Text       string                 `json:"text,omitempty"`
+
+	line int
+}
+
+// batchResult is the outcome of one operation.
+type batchResult struct {
+	Line   int    `json:"line"`
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch --file <ops.jsonl>",
+	Short: "Run many write operations from a file",
+	Long: `Run a file of operations, one JSON object per line.
+
+Operations:
+  {"op":"create_page", "parent":"<id>", "title":"...", "properties":{...}, "markdown":"..."}
+  {"op":"append", "parent":"<id>", "markdown":"..."}      (or "text" for one paragraph)
+  {"op":"set", "page":"<id>", "properties":{"Status":"Done"}}
+
+Property values are given as strings (converted using the database schema,
+like 'page set') or as raw Notion property objects. Database schemas are
+fetched once and shared by all operations.
+
+IDs may be Notion IDs/URLs, @last / @N / @new, or $N for the page created
+or changed by the operation on line N of the file.
+
+Operations are started at most --rate per second. By default the batch
+stops at the first failure; --on-error continue runs the rest. A summary
+is printed at the end, and the command exits non-zero if anything failed.
+Blank lines and lines starting with # are ignored. Use --file - for stdin.
+
+Examples:
+  notion batch --file ops.jsonl
+  notion batch --file ops.jsonl --on-error continue --format json
+  notion batch --file ops.jsonl --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		onError, _ := cmd.Flags().GetString("on-error")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if file == "" {
+			return usageError(fmt.Errorf("--file is required"))
+		}
+		if onError != "stop" && onError != "continue" {
+			return usageError(fmt.Errorf("--on-error must be stop or continue (got %q)", onError))
+		}
+
+		var in io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", file, err)
+			}
+			defer f.Close()
+			in = f
+		}
+		ops, err := parseBatchOps(in)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			if outputFormat == "json" {
+				return render.JSON(map[string]interface{}{"valid": true, "operations": len(ops)})
+			}
+			fmt.Printf("✓ %d operation(s) are valid\n", len(ops))
+			return nil
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c, ctx, stop := interruptible(newClient(token))
+		defer stop()
+		r := newBatchRunner(c)
+
+		var interval time.Duration
+		if rate > 0 {
+			interval = time.Duration(float64(time.Second) / rate)
+		}
+		var next time.Time
+		results := make([]batchResult, 0, len(ops))
+		failed := 0
+		interruptedAt := 0
+		for i, op := range ops {
+			if ctx.Err() != nil {
+				interruptedAt = op.line
+			}
+			if (failed > 0 && onError == "stop") || interruptedAt > 0 {
+				for _, skipped := range ops[i:] {
+					results = append(results, batchResult{Line: skipped.line, Op: skipped.Op, Status: "skipped"})
+				}
+				break
+			}
+			if wait := time.Until(next); wait > 0 {
+				noteThrottle(wait)
+				time.Sleep(wait)
+			}
+			next = time.Now().Add(interval)
+
+			res := batchResult{Line: op.line, Op: op.Op, Status: "ok"}
+			id, err := r.run(op)
+			if err != nil && canceled(err) {
+				interruptedAt = op.line
+				for _, skipped := range ops[i:] {
+					results = append(results, batchResult{Line: skipped.line, Op: skipped.Op, Status: "skipped"})
+				}
+				break
+			}
+			if err != nil {
+				failed++
+				res.Status = "failed"
+				res.Error = err.Error()
+			} else {
+				res.ID = id
+			}
+			results = append(results, res)
+			if outputFormat != "json" {
+				if err != nil {
+					fmt.Printf("✗ line %d %s: %s\n", op.line, op.Op, firstLine(err))
+				} else {
+					fmt.Printf("✓ line %d %s → %s\n", op.line, op.Op, id)
+				}
+			}
+		}
+
+		succeeded := 0
+		for _, res := range results {
+			if res.Status == "ok" {
+				succeeded++
+			}
+		}
+		skipped := len(results) - succeeded - failed
+		if outputFormat == "json" {
+			if err := render.JSON(map[string]interface{}{
+				"ok":        failed == 0,
+				"succeeded": succeeded,
+				"failed":    failed,
+				"skipped":   skipped,
+				"results":   results,
+			}); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("\n%d succeeded, %d failed, %d skipped\n", succeeded, failed, skipped)
+		}
+		if interruptedAt > 0 {
+			return &interruptedError{op: "batch", done: succeeded, failed: failed, total: len(ops),
+				resume: fmt.Sprintf("operations from line %d on were not run", interruptedAt)}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d operation(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	batchCmd.Flags().String("file", "", "JSON Lines file of operations (- for stdin)")
+	batchCmd.Flags().String("on-error", "stop", "What to do when an operation fails: stop|continue")
+	batchCmd.Flags().Float64("rate", 3, "Maximum operations started per second (0 = no limit)")
+	batchCmd.Flags().Bool("dry-run", false, "Validate the file without running it")
+}
+
+// parseBatchOps reads and validates a batch file.
+func parseBatchOps(in io.Reader) ([]batchOp, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var ops []batchOp
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var op batchOp
+		if err := json.Unmarshal([]byte(text), &op); err != nil {
+			return nil, invalidInput(fmt.Errorf("line %d: invalid JSON: %w", line, err))
+		}
+		op.line = line
+		if err := validateBatchOp(op); err != nil {
+			return nil, invalidInput(fmt.Errorf("line %d: %w", line, err))
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read batch file: %w", err)
+	}
+	if len(ops) == 0 {
+		return nil, invalidInput(fmt.Errorf("no operations found"))
+	}
+	return ops, nil
+}
+
+func validateBatchOp(op batchOp) error {
+	switch op.Op {
+	case "create_page":
+		if op.Parent == "" {
+			return fmt.Errorf("create_page needs \"parent\"")
+		}
+	case "append":
+		if op.Parent == "" {
+			return fmt.Errorf("append needs \"parent\"")
+		}
+		if op.Markdown == "" && op.Text == "" {
+			return fmt.Errorf("append needs \"markdown\" or \"text\"")
+		}
+	case "set":
+		if op.Page == "" {
+			return fmt.Errorf("set needs \"page\"")
+		}
+		if len(op.Properties) == 0 {
+			return fmt.Errorf("set needs \"properties\"")
+		}
+	case "":
+		return fmt.Errorf("missing \"op\"")
+	default:
+		return fmt.Errorf("unknown op %q (want create_page, append or set)", op.Op)
+	}
+	return nil
+}
+
+// batchRunner executes operations, sharing lookups between them.
+type batchRunner struct {
+	c *notion.Client
+	// schemas maps database IDs to their properties; a nil entry records
+	// that the ID is not a database.
+	schemas map[string]map[string]interface{}
+	// pageDB maps page IDs to their parent database.
+	pageDB map[string]string
+	// results maps line numbers to the ID the operation produced.
+	results map[int]string
+}
+
+func newBatchRunner(c *notion.Client) *batchRunner {
+	return &batchRunner{
+		c:       c,
+		schemas: map[string]map[string]interface{}{},
+		pageDB:  map[string]string{},
+		results: map[int]string{},
+	}
+}
+
+// run executes one operation and returns the ID of the page or block it
+// created or changed.
+func (r *batchRunner) run(op batchOp) (string, error) {
+	var id string
+	var err error
+	switch op.Op {
+	case "create_page":
+		id, err = r.createPage(op)
+	case "append":
+		id, err = r.appendBlocks(op)
+	case "set":
+		id, err = r.setProperties(op)
+	}
+	if err == nil {
+		r.results[op.line] = id
+	}
+	return id, err
+}
+
+// resolve turns an ID, URL, @-reference or $N into an ID.
+func (r *batchRunner) resolve(ref string) (string, error) {
+	if strings.HasPrefix(ref, "$") {
+		n, err := strconv.Atoi(ref[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid reference %q", ref)
+		}
+		id, ok := r.results[n]
+		if !ok {
+			return "", fmt.Errorf("%s: line %d has not produced an ID", ref, n)
+		}
+		return id, nil
+	}
+	id, ok, err := resolveRecentRef(ref)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return id, nil
+	}
+	return util.ResolveID(ref), nil
+}
+
+// schema returns the properties of database id, or nil when id is not a
+// database. Only that answer is remembered: other failures, such as rate
+// limits or network errors, are returned and retried by the next row.
+func (r *batchRunner) schema(id string) (map[string]interface{}, error) {
+	if props, ok := r.schemas[id]; ok {
+		return props, nil
+	}
+	db, err := r.c.GetDatabase(id)
+	if err != nil {
+		if !errors.Is(err, notion.ErrNotFound) && !errors.Is(err, notion.ErrValidation) {
+			return nil, err
+		}
+		r.schemas[id] = nil
+		return nil, nil
+	}
+	props, _ := db["properties"].(map[string]interface{})
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	r.schemas[id] = props
+	return props, nil
+}
+
+func (r *batchRunner) createPage(op batchOp) (string, error) {
+	parentID, err := r.resolve(op.Parent)
+	if err != nil {
+		return "", err
+	}
+	schema, err := r.schema(parentID)
+	if err != nil {
+		return "", err
+	}
+
+	var body map[string]interface{}
+	if schema != nil {
+		properties, err := batchPropertyValues(schema, op.Properties)
+		if err != nil {
+			return "", err
+		}
+		if op.Title != "" {
+			for _, name := range sortedKeys(schema) {
+				if prop, _ := schema[name].(map[string]interface{}); prop["type"] == "title" {
+					properties[name] = buildPropertyValue("title", op.Title)
+					break
+				}
+			}
+		}
+		body = map[string]interface{}{
+			"parent":     map[string]interface{}{"database_id": parentID},
+			"properties": properties,
+		}
+	} else {
+		if op.Title == "" {
+			return "", fmt.Errorf("\"title\" is required for a page parent")
+		}
+		if len(op.Properties) > 0 {
+			return "", fmt.Errorf("\"properties\" need a database parent")
+		}
+		body = map[string]interface{}{
+			"parent":     map[string]interface{}{"page_id": parentID},
+			"properties": map[string]interface{}{"title": buildPropertyValue("title", op.Title)},
+		}
+	}
+
+	var rest []map[string]interface{}
+	if op.Markdown != "" {
+		children, err := handleOversizedBlocks(parseMarkdownToBlocks(op.Markdown), oversizeSplit)
+		if err != nil {
+			return "", err
+		}
+		if len(children) > maxChildrenPerRequest {
+			children, rest = children[:maxChildrenPerRequest], children[maxChildrenPerRequest:]
+		}
+		body["children"] = children
+	}
+
+	data, err := r.c.Post("/v1/pages", body)
+	if err != nil {
+		return "", fmt.Errorf("create page: %w", err)
+	}
+	rememberCreated(data)
+	var page map[string]interface{}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return "", fmt.Errorf("parse response: %w", err)
+	}
+	id, _ := page["id"].(string)
+	if schema != nil {
+		r.pageDB[id] = parentID
+	}
+	if len(rest) > 0 {
+		if _, err := appendChildrenBatched(r.c, id, "", rest); err != nil {
+			return id, fmt.Errorf("page %s created, but appending content failed: %w", id, err)
+		}
+	}
+	return id, nil
+}
+
+func (r *batchRunner) appendBlocks(op batchOp) (string, error) {
+	parentID, err := r.resolve(op.Parent)
+	if err != nil {
+		return "", err
+	}
+	var children []map[string]interface{}
+	if op.Markdown != "" {
+		children = parseMarkdownToBlocks(op.Markdown)
+	} else {
+		children = []map[string]interface{}{makeTextBlock("paragraph", op.Text)}
+	}
+	children, err = handleOversizedBlocks(children, oversizeSplit)
+	if err != nil {
+		return "", err
+	}
+	if _, err := appendChildrenBatched(r.c, parentID, "", children); err != nil {
+		return "", fmt.Errorf("append blocks: %w", err)
+	}
+	return parentID, nil
+}
+
+func (r *batchRunner) setProperties(op batchOp) (string, error) {
+	pageID, err := r.resolve(op.Page)
+	if err != nil {
+		return "", err
+	}
+
+	var schema map[string]interface{}
+	if dbID, ok := r.pageDB[pageID]; ok {
+		if schema, err = r.schema(dbID); err != nil {
+			return "", err
+		}
+	}
+	if schema == nil {
+		page, err := r.c.GetPage(pageID)
+		if err != nil {
+			return "", fmt.Errorf("get page: %w", err)
+		}
+		schema, _ = page["properties"].(map[string]interface{})
+		if parent, ok := page["parent"].(map[string]interface{}); ok {
+			if dbID, _ := parent["database_id"].(string); dbID != "" {
+				r.pageDB[pageID] = dbID
+				if _, cached := r.schemas[dbID]; !cached {
+					r.schemas[dbID] = schema
+				}
+			}
+		}
+	}
+
+	properties, err := batchPropertyValues(schema, op.Properties)
+	if err != nil {
+		return "", err
+	}
+	if _, err := r.c.Patch("/v1/pages/"+pageID, map[string]interface{}{"properties": properties}); err != nil {
+		return "", fmt.Errorf("set properties: %w", err)
+	}
+	return pageID, nil
+}
+
+// batchPropertyValues converts batch property values to Notion property
+// values using schema. Objects are passed through unchanged; other values
+// are converted like 'page set' arguments.
+func batchPropertyValues(schema, values map[string]interface{}) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	for _, name := range sortedKeys(values) {
+		value := values[name]
+		if raw, ok := value.(map[string]interface{}); ok {
+			properties[name] = raw
+			continue
+		}
+		prop, ok := schema[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("property %q not found in database schema", name)
+		}
+		propType, _ := prop["type"].(string)
+		if value == nil {
+			value = ""
+		}
+		properties[name] = buildPropertyValue(propType, fmt.Sprint(value))
+	}
+	return properties, nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestParseBatchOps(t *testing.T) {
+	ops, err := parseBatchOps(strings.NewReader(`# setup
+{"op":"create_page","parent":"db1","title":"A"}
+
+{"op":"set","page":"$2","properties":{"Status":"Done"}}
+`))
+	if err != nil {
+		t.Fatalf("parseBatchOps: %v", err)
+	}
+	if len(ops) != 2 || ops[0].line != 2 || ops[1].line != 4 {
+		t.Errorf("ops = %+v", ops)
+	}
+
+	for input, want := range map[string]string{
+		`{"op":"append","parent":"x"}`: `line 1: append needs "markdown" or "text"`,
+		`{"op":"delete","page":"x"}`:   `unknown op "delete"`,
+		`{"op":`:                       "line 1: invalid JSON",
+		"":                             "no operations found",
+	} {
+		if _, err := parseBatchOps(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseBatchOps(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestBatchRun(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	created := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/databases/11111111-1111-1111-1111-111111111111":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database",
+				"id":     "11111111-1111-1111-1111-111111111111",
+				"properties": map[string]interface{}{
+					"Name":   map[string]interface{}{"type": "title"},
+					"Status": map[string]interface{}{"type": "select"},
+				},
+			})
+		case r.Method == "POST" && r.URL.Path == "/v1/pages":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			props, _ := body["properties"].(map[string]interface{})
+			if _, ok := props["Name"]; !ok {
+				t.Errorf("create body missing Name: %v", body)
+			}
+			created++
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": fmt.Sprintf("new-%d", created)})
+		case r.Method == "PATCH":
+			if strings.Contains(r.URL.Path, "bad") {
+				w.WriteHeader(400)
+				json.NewEncoder(w).Encode(map[string]string{"code": "validation_error", "message": "nope"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page"})
+		default:
+			w.WriteHeader(404)
+			json.NewEncoder(w).Encode(map[string]string{"code": "object_not_found", "message": "not found"})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	file := t.TempDir() + "/ops.jsonl"
+	ops := `{"op":"create_page","parent":"11111111111111111111111111111111","title":"One","properties":{"Status":"Todo"}}
+{"op":"create_page","parent":"11111111111111111111111111111111","title":"Two"}
+{"op":"set","page":"$1","properties":{"Status":"Done"}}
+{"op":"append","parent":"bad","text":"hello"}
+{"op":"append","parent":"$2","text":"never runs"}
+`
+	if err := os.WriteFile(file, []byte(ops), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand("batch", "--file", file, "--rate", "0", "--format", "json")
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 operation(s) failed") {
+		t.Fatalf("err = %v", err)
+	}
+
+	var report struct {
+		Succeeded, Failed, Skipped int
+		Results                    []batchResult
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("parse output: %v\n%s", err, out)
+	}
+	if report.Succeeded != 3 || report.Failed != 1 || report.Skipped != 1 {
+		t.Errorf("report = %+v", report)
+	}
+
+	schemaFetches := 0
+	for _, c := range calls {
+		if strings.HasPrefix(c, "GET /v1/databases/") {
+			schemaFetches++
+		}
+		if strings.HasPrefix(c, "GET /v1/pages/") {
+			t.Errorf("set on a page created in the batch should not refetch it: %s", c)
+		}
+	}
+	if schemaFetches != 1 {
+		t.Errorf("schema fetched %d times, want 1 (calls: %v)", schemaFetches, calls)
+	}
+}
+
+func TestBatchSchemaCache(t *testing.T) {
+	var status int
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			fmt.Fprintf(w, `{"object":"error","status":%d,"code":"x","message":"x"}`, status)
+			return
+		}
+		w.Write([]byte(`{"object":"database","id":"db","properties":{"Name":{"type":"title"}}}`))
+	}))
+	defer server.Close()
+	r := newBatchRunner(notion.New("secret_test", notion.WithBaseURL(server.URL)))
+
+	// A transient failure is reported, not taken to mean "not a database".
+	status = http.StatusServiceUnavailable
+	if _, err := r.schema("db"); err == nil {
+		t.Fatal("503 not reported")
+	}
+	status = http.StatusOK
+	if props, err := r.schema("db"); err != nil || props["Name"] == nil {
+		t.Fatalf("schema after the failure = %v, %v", props, err)
+	}
+
+	status = http.StatusNotFound
+	for i := 0; i < 2; i++ {
+		if props, err := r.schema("page"); props != nil || err != nil {
+			t.Errorf("page parent = %v, %v; want no schema", props, err)
+		}
+	}
+	if gets != 3 {
+		t.Errorf("%d requests, want 3 (a page parent is looked up once)", gets)
+	}
+}
@@ -4,12 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"regexp"
+	"path/filepath"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
 	"github.com/spf13/cobra"
 )
 
@@ -43,8 +43,7 @@ Examples:
 			depth = 1
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		allResults, err := fetchBlockChildren(c, parentID, cursor, all)
 		if err != nil {
@@ -59,6 +58,10 @@ Examples:
 		if outputFormat == "json" {
 			return render.JSON(map[string]interface{}{"results": allResults})
 		}
+		if porcelain != "" {
+			porcelainBlocks(allResults, 0)
+			return nil
+		}
 
 		mdMode, _ := cmd.Flags().GetBool("md")
 		if outputFormat == "md" || outputFormat == "markdown" {
@@ -96,8 +99,7 @@ Examples:
 		}
 
 		blockID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		block, err := c.GetBlock(blockID)
 		if err != nil {
@@ -162,17 +164,16 @@ Examples:
 		markdown, _ := cmd.Flags().GetBool("markdown")
 
 		if text != "" && filePath != "" {
-			return fmt.Errorf("--text and --file are mutually exclusive")
+			return usageError(fmt.Errorf("--text and --file are mutually exclusive"))
 		}
 		if markdown && filePath != "" {
-			return fmt.Errorf("--markdown is implied for --file; drop --markdown when using --file")
+			return usageError(fmt.Errorf("--markdown is implied for --file; drop --markdown when using --file"))
 		}
 		if text == "" && filePath == "" {
-			return fmt.Errorf("one of --text or --file is required")
+			return usageError(fmt.Errorf("one of --text or --file is required"))
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		// Resolve target type: user override wins, otherwise inspect the block.
 		if blockType == "" {
@@ -185,7 +186,7 @@ Examples:
 			blockType = mapBlockType(blockType)
 		}
 		if blockType == "" {
-			return fmt.Errorf("could not determine block type; pass --type explicitly")
+			return usageError(fmt.Errorf("could not determine block type; pass --type explicitly"))
 		}
 
 		body, err := buildUpdateBlockBody(blockType, text, filePath, markdown)
@@ -222,12 +223,12 @@ func buildUpdateBlockBody(blockType, text, filePath string, markdown bool) (map[
 		}
 		blocks := parseMarkdownToBlocks(string(data))
 		if len(blocks) != 1 {
-			return nil, fmt.Errorf("--file must contain exactly one block, got %d; use 'block delete' + 'block append' for multi-block replacements", len(blocks))
+			return nil, invalidInput(fmt.Errorf("--file must contain exactly one block, got %d; use 'block delete' + 'block append' for multi-block replacements", len(blocks)))
 		}
 		parsed := blocks[0]
 		parsedType, _ := parsed["type"].(string)
 		if parsedType != blockType {
-			return nil, fmt.Errorf("block type mismatch: target is %q but --file parsed as %q (Notion's PATCH cannot change block type)", blockType, parsedType)
+			return nil, invalidInput(fmt.Errorf("block type mismatch: target is %q but --file parsed as %q (Notion's PATCH cannot change block type)", blockType, parsedType))
 		}
 		// Preserve any type-specific fields (language on code, checked on to_do) from the parsed block.
 		inner, _ := parsed[blockType].(map[string]interface{})
@@ -259,23 +260,47 @@ Supports plain text, block types, markdown files, and media blocks.
 Media blocks accept one of:
   --image-url/--image-file/--image-upload (and the same pattern for
   file/video/audio/pdf). See 'notion block append --help' for the full list.
+  --image-clipboard uploads the image on the system clipboard (a copied
+  screenshot).
+
+Images on a line of their own in a markdown --file (![alt](chart.png))
+are uploaded, relative to the file, and embedded as image blocks.
 
 Large markdown files are handled transparently:
   - >100 children are auto-batched into sequential PATCHes.
   - code / rich_text exceeding Notion's 2000-char limit are split by
     default (override with --on-oversize=truncate|fail).
 
+--check reports what the markdown converter would drop or flatten
+(nested lists, deep headings, malformed tables, missing images) and
+links Notion would reject, without appending anything.
+
 Examples:
   notion block append <page-id> "Hello world"
   notion block append <page-id> --type heading1 "Section Title"
   notion block append <page-id> --type code --lang go "fmt.Println()"
   notion block append <page-id> --file notes.md
   notion block append <page-id> --file big.md --on-oversize=truncate
+  notion block append <page-id> --file notes.md --check   # CI pre-flight
+  notion block append <page-id> --paste          # clipboard text, as markdown
   notion block append <page-id> --image-url https://example.com/a.png --caption "图 1-1"
   notion block append <page-id> --image-file ./chart.png --caption "heap usage"
+  notion block append <page-id> --image-clipboard --caption "screenshot"
   notion block append <page-id> --pdf-upload 351d45fb-... --caption "spec v2"`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if check, _ := cmd.Flags().GetBool("check"); check {
+			filePath, _ := cmd.Flags().GetString("file")
+			if filePath == "" {
+				return usageError(fmt.Errorf("--check needs --file"))
+			}
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return fmt.Errorf("read file: %w", err)
+			}
+			return reportMarkdownCheck(filePath, string(data))
+		}
+
 		token, err := getToken()
 		if err != nil {
 			return err
@@ -300,16 +325,28 @@ Examples:
 			return err
 		}
 
+		paste, _ := cmd.Flags().GetBool("paste")
+		var pasted string
+		if paste {
+			if text != "" || filePath != "" || mediaSrc.IsActive() {
+				return usageError(fmt.Errorf("--paste cannot be combined with text, --file or a media source"))
+			}
+			if pasted, err = pastedText(); err != nil {
+				return err
+			}
+		}
+
 		if blockType == "" {
 			blockType = "paragraph"
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var children []map[string]interface{}
 
-		if mediaSrc.IsActive() {
+		if paste {
+			children = parseMarkdownToBlocks(pasted)
+		} else if mediaSrc.IsActive() {
 			block, err := mediaSrc.Build(c)
 			if err != nil {
 				return err
@@ -321,10 +358,13 @@ Examples:
 			if err != nil {
 				return fmt.Errorf("read file: %w", err)
 			}
-			children = parseMarkdownToBlocks(string(data))
+			children, _, err = parseMarkdownWithImages(c, string(data), diskImages(filepath.Dir(filePath)))
+			if err != nil {
+				return err
+			}
 		} else {
 			if text == "" {
-				return fmt.Errorf("text content, --file, or a media source (--image-url, --image-file, --image-upload, ...) is required")
+				return usageError(fmt.Errorf("text content, --file, or a media source (--image-url, --image-file, --image-upload, ...) is required"))
 			}
 
 			notionType := mapBlockType(blockType)
@@ -386,8 +426,7 @@ Examples:
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		deleted := 0
 		for _, arg := range args {
@@ -441,7 +480,7 @@ Examples:
 		}
 
 		if afterID == "" {
-			return fmt.Errorf("--after <block-id> is required (use 'block append' to add to end)")
+			return usageError(fmt.Errorf("--after <block-id> is required (use 'block append' to add to end)"))
 		}
 		afterID = util.ResolveID(afterID)
 
@@ -449,8 +488,7 @@ Examples:
 			blockType = "paragraph"
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var children []map[string]interface{}
 
@@ -471,10 +509,13 @@ Examples:
 			if err != nil {
 				return fmt.Errorf("read file: %w", err)
 			}
-			children = parseMarkdownToBlocks(string(data))
+			children, _, err = parseMarkdownWithImages(c, string(data), diskImages(filepath.Dir(filePath)))
+			if err != nil {
+				return err
+			}
 		} else {
 			if text == "" {
-				return fmt.Errorf("text content, --file, or a media source (--image-url, --image-file, --image-upload, ...) is required")
+				return usageError(fmt.Errorf("text content, --file, or a media source (--image-url, --image-file, --image-upload, ...) is required"))
 			}
 
 			notionType := mapBlockType(blockType)
@@ -544,15 +585,14 @@ Examples:
 		parentID, _ := cmd.Flags().GetString("parent")
 
 		if afterID == "" && beforeID == "" && parentID == "" {
-			return fmt.Errorf("at least one of --after, --before, or --parent is required")
+			return usageError(fmt.Errorf("at least one of --after, --before, or --parent is required"))
 		}
 
 		if afterID != "" && beforeID != "" {
-			return fmt.Errorf("cannot specify both --after and --before")
+			return usageError(fmt.Errorf("cannot specify both --after and --before"))
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		// Get the current block to find its parent if not specified
 		currentBlock, err := c.GetBlock(blockID)
@@ -669,8 +709,11 @@ func init() {
 	blockAppendCmd.Flags().String("lang", "plain text", "Language for code blocks (e.g. go, python, bash)")
 	blockAppendCmd.Flags().String("file", "", "Read content from a file (each double-newline-separated section becomes a block)")
 	blockAppendCmd.Flags().String("on-oversize", "split", "Behavior for rich_text >2000 chars: split|truncate|fail")
+	blockAppendCmd.Flags().Bool("paste", false, "Append the clipboard's text (parsed as markdown)")
+	blockAppendCmd.Flags().Bool("check", false, "Check that --file converts cleanly, without appending; exits non-zero on problems")
 	registerMediaFlags(blockAppendCmd)
 	blockInsertCmd.Flags().String("after", "", "Block ID to insert after (required)")
+	markIDFlags(blockInsertCmd, "after")
 	blockInsertCmd.Flags().StringP("type", "t", "paragraph", "Block type")
 	blockInsertCmd.Flags().String("lang", "plain text", "Language for code blocks")
 	blockInsertCmd.Flags().String("file", "", "Read content from a file")
@@ -687,6 +730,7 @@ func init() {
 	blockMoveCmd.Flags().String("after", "", "Block ID to position after")
 	blockMoveCmd.Flags().String("before", "", "Block ID to position before")
 	blockMoveCmd.Flags().String("parent", "", "New parent block/page ID to move to")
+	markIDFlags(blockMoveCmd, "after", "before", "parent")
 
 	blockCmd.AddCommand(blockListCmd)
 	blockCmd.AddCommand(blockGetCmd)
@@ -709,13 +753,13 @@ func validateMediaFlags(imageURL, filePath, text string) error {
 		return nil
 	}
 	if filePath != "" {
-		return fmt.Errorf("--image-url cannot be combined with --file")
+		return usageError(fmt.Errorf("--image-url cannot be combined with --file"))
 	}
 	if text != "" {
-		return fmt.Errorf("--image-url cannot be combined with a positional text argument")
+		return usageError(fmt.Errorf("--image-url cannot be combined with a positional text argument"))
 	}
 	if !strings.HasPrefix(imageURL, "http://") && !strings.HasPrefix(imageURL, "https://") {
-		return fmt.Errorf("--image-url must be an http:// or https:// URL")
+		return usageError(fmt.Errorf("--image-url must be an http:// or https:// URL"))
 	}
 	return nil
 }
@@ -750,7 +794,7 @@ func mapBlockType(t string) string {
 }
 
 // fetchBlockChildren fetches all children of a block with optional pagination.
-func fetchBlockChildren(c *client.Client, parentID, cursor string, all bool) ([]interface{}, error) {
+func fetchBlockChildren(c *notion.Client, parentID, cursor string, all bool) ([]interface{}, error) {
 	var allResults []interface{}
 	currentCursor := cursor
 
@@ -775,7 +819,7 @@ func fetchBlockChildren(c *client.Client, parentID, cursor string, all bool) ([]
 }
 
 // fetchNestedBlocks recursively fetches children for blocks that have them.
-func fetchNestedBlocks(c *client.Client, blocks []interface{}, remainingDepth int) []interface{} {
+func fetchNestedBlocks(c *notion.Client, blocks []interface{}, remainingDepth int) []interface{} {
 	if remainingDepth <= 0 {
 		return blocks
 	}
@@ -816,324 +860,34 @@ func renderBlockRecursive(block map[string]interface{}, indent int) {
 	}
 }
 
-// parseMarkdownToBlocks converts markdown text to Notion block objects.
+// parseMarkdownToBlocks converts markdown text to Notion block objects,
+// warning on stderr about code fences in unsupported languages.
 func parseMarkdownToBlocks(content string) []map[string]interface{} {
-	var blocks []map[string]interface{}
-	lines := strings.Split(content, "\n")
-
-	i := 0
-	for i < len(lines) {
-		line := lines[i]
-
-		// Code fence
-		if strings.HasPrefix(line, "```") {
-			lang := normalizeCodeLanguage(strings.TrimPrefix(line, "```"))
-			var codeLines []string
-			i++
-			for i < len(lines) && !strings.HasPrefix(lines[i], "```") {
-				codeLines = append(codeLines, lines[i])
-				i++
-			}
-			i++ // skip closing ```
-			blocks = append(blocks, map[string]interface{}{
-				"object": "block",
-				"type":   "code",
-				"code": map[string]interface{}{
-					"rich_text": []map[string]interface{}{
-						{"text": map[string]interface{}{"content": strings.Join(codeLines, "\n")}},
-					},
-					"language": lang,
-				},
-			})
-			continue
-		}
-
-		// Skip empty lines
-		if strings.TrimSpace(line) == "" {
-			i++
-			continue
-		}
-
-		// Headings
-		if strings.HasPrefix(line, "### ") {
-			blocks = append(blocks, makeTextBlock("heading_3", strings.TrimPrefix(line, "### ")))
-			i++
-			continue
-		}
-		if strings.HasPrefix(line, "## ") {
-			blocks = append(blocks, makeTextBlock("heading_2", strings.TrimPrefix(line, "## ")))
-			i++
-			continue
-		}
-		if strings.HasPrefix(line, "# ") {
-			blocks = append(blocks, makeTextBlock("heading_1", strings.TrimPrefix(line, "# ")))
-			i++
-			continue
-		}
-
-		// Todo (must check before bullet — "- [ ]" starts with "- ")
-		if strings.HasPrefix(line, "- [ ] ") {
-			block := makeTextBlock("to_do", line[6:])
-			block["to_do"].(map[string]interface{})["checked"] = false
-			blocks = append(blocks, block)
-			i++
-			continue
-		}
-		if strings.HasPrefix(line, "- [x] ") || strings.HasPrefix(line, "- [X] ") {
-			block := makeTextBlock("to_do", line[6:])
-			block["to_do"].(map[string]interface{})["checked"] = true
-			blocks = append(blocks, block)
-			i++
-			continue
-		}
-
-		// Bullet list
-		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
-			blocks = append(blocks, makeTextBlock("bulleted_list_item", line[2:]))
-			i++
-			continue
-		}
-
-		// Numbered list
-		if len(line) > 2 && line[0] >= '0' && line[0] <= '9' && strings.Contains(line[:5], ". ") {
-			idx := strings.Index(line, ". ")
-			blocks = append(blocks, makeTextBlock("numbered_list_item", line[idx+2:]))
-			i++
-			continue
-		}
-
-		// Quote
-		if strings.HasPrefix(line, "> ") {
-			blocks = append(blocks, makeTextBlock("quote", strings.TrimPrefix(line, "> ")))
-			i++
-			continue
-		}
-
-		// Divider
-		if line == "---" || line == "***" || line == "___" {
-			blocks = append(blocks, map[string]interface{}{
-				"object":  "block",
-				"type":    "divider",
-				"divider": map[string]interface{}{},
-			})
-			i++
-			continue
-		}
-
-		// GFM Table: starts with '|'
-		if strings.HasPrefix(strings.TrimSpace(line), "|") {
-			// Collect all consecutive pipe-starting lines
-			var tableLines []string
-			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
-				tableLines = append(tableLines, lines[i])
-				i++
-			}
-			// Need at least header + separator + 1 data row to be a valid GFM table
-			if len(tableLines) >= 2 && isTableSeparator(tableLines[1]) {
-				tableBlock := buildTableBlock(tableLines)
-				if tableBlock != nil {
-					blocks = append(blocks, tableBlock)
-					continue
-				}
-			}
-			// Not a valid table — treat each line as a paragraph
-			for _, tl := range tableLines {
-				blocks = append(blocks, makeTextBlock("paragraph", tl))
-			}
-			continue
-		}
-
-		// Default: paragraph
-		blocks = append(blocks, makeTextBlock("paragraph", line))
-		i++
-	}
-
-	return blocks
-}
-
-// isTableSeparator returns true when a line looks like a GFM table separator (|---|---|).
-func isTableSeparator(line string) bool {
-	trimmed := strings.TrimSpace(line)
-	if !strings.HasPrefix(trimmed, "|") {
-		return false
-	}
-	// Strip leading/trailing '|', split cells, check each cell is only -/:/space
-	inner := strings.Trim(trimmed, "|")
-	cells := strings.Split(inner, "|")
-	for _, cell := range cells {
-		cell = strings.TrimSpace(cell)
-		if cell == "" {
-			continue
-		}
-		// Must consist of dashes and optional colons (alignment markers)
-		for _, ch := range cell {
-			if ch != '-' && ch != ':' {
-				return false
-			}
-		}
-	}
-	return true
-}
-
-// splitTableRow splits a pipe-delimited table row into trimmed cell strings.
-func splitTableRow(line string) []string {
-	trimmed := strings.TrimSpace(line)
-	// Strip leading/trailing '|'
-	trimmed = strings.Trim(trimmed, "|")
-	parts := strings.Split(trimmed, "|")
-	cells := make([]string, len(parts))
-	for i, p := range parts {
-		cells[i] = strings.TrimSpace(p)
-	}
-	return cells
-}
-
-// buildTableBlock converts collected GFM table lines into a Notion table block.
-// tableLines[0] = header row, tableLines[1] = separator, tableLines[2:] = data rows.
-func buildTableBlock(tableLines []string) map[string]interface{} {
-	headerCells := splitTableRow(tableLines[0])
-	tableWidth := len(headerCells)
-	if tableWidth == 0 {
-		return nil
-	}
-
-	var rows []map[string]interface{}
-
-	// Header row (index 0), skip separator (index 1), then data rows
-	for idx, line := range tableLines {
-		if idx == 1 {
-			continue // separator — skip
-		}
-		cells := splitTableRow(line)
-		// Pad or trim to tableWidth
-		for len(cells) < tableWidth {
-			cells = append(cells, "")
-		}
-		cells = cells[:tableWidth]
-
-		notionCells := make([]interface{}, tableWidth)
-		for j, cellText := range cells {
-			notionCells[j] = parseInlineFormatting(cellText)
-		}
-		rows = append(rows, map[string]interface{}{
-			"object": "block",
-			"type":   "table_row",
-			"table_row": map[string]interface{}{
-				"cells": notionCells,
-			},
-		})
-	}
-
-	if len(rows) == 0 {
-		return nil
-	}
-
-	// Notion API requires table_row children INSIDE table{}, not at block top-level.
-	return map[string]interface{}{
-		"object": "block",
-		"type":   "table",
-		"table": map[string]interface{}{
-			"table_width":       tableWidth,
-			"has_column_header": true,
-			"has_row_header":    false,
-			"children":          rows,
-		},
-	}
+	return notion.MarkdownToBlocks(content, notion.OnUnknownLanguage(warnUnknownCodeLanguage))
 }
 
 func makeTextBlock(blockType, text string) map[string]interface{} {
-	return map[string]interface{}{
-		"object": "block",
-		"type":   blockType,
-		blockType: map[string]interface{}{
-			"rich_text": parseInlineFormatting(strings.TrimSpace(text)),
-		},
-	}
+	return notion.TextBlock(blockType, text)
 }
 
-// parseInlineFormatting converts inline markdown (bold, italic, code, link, strikethrough)
-// into a Notion rich_text array.
 func parseInlineFormatting(text string) []map[string]interface{} {
-	// token pattern: **bold**, *italic*, _italic_, `code`, ~~strike~~, [text](url)
-	tokenRe := regexp.MustCompile(`\*\*(.+?)\*\*|\*(.+?)\*|_(.+?)_|` + "`" + `(.+?)` + "`" + `|~~(.+?)~~|\[([^\]]+)\]\(([^)]+)\)`)
-
-	var result []map[string]interface{}
-	remaining := text
-	for len(remaining) > 0 {
-		loc := tokenRe.FindStringIndex(remaining)
-		if loc == nil {
-			// No more tokens — append remaining as plain text
-			if remaining != "" {
-				result = append(result, plainRichText(remaining))
-			}
-			break
-		}
-		// Plain text before the match
-		if loc[0] > 0 {
-			result = append(result, plainRichText(remaining[:loc[0]]))
-		}
-		match := tokenRe.FindStringSubmatch(remaining[loc[0]:loc[1]])
-		rt := buildAnnotatedRichText(match)
-		result = append(result, rt)
-		remaining = remaining[loc[1]:]
-	}
-	if len(result) == 0 {
-		return []map[string]interface{}{plainRichText(text)}
-	}
-	return result
+	return notion.RichTextFromMarkdown(text)
 }
 
-func plainRichText(text string) map[string]interface{} {
-	return map[string]interface{}{
-		"text": map[string]interface{}{"content": text},
+// normalizeCodeLanguage converts a raw markdown fence language label into
+// a value that the Notion API's code.language enum accepts. Unknown values
+// fall back to "plain text" with a one-line stderr warning so long docs
+// don't hard-fail on a single unrecognized fence tag.
+func normalizeCodeLanguage(raw string) string {
+	lang, ok := notion.CodeLanguage(raw)
+	if !ok {
+		warnUnknownCodeLanguage(raw)
 	}
+	return lang
 }
 
-func buildAnnotatedRichText(match []string) map[string]interface{} {
-	// match[0] = full match
-	// match[1] = **bold**  content
-	// match[2] = *italic*  content
-	// match[3] = _italic_  content
-	// match[4] = `code`    content
-	// match[5] = ~~strike~~ content
-	// match[6] = [text](url) text part
-	// match[7] = [text](url) url part
-	switch {
-	case match[1] != "": // **bold**
-		return map[string]interface{}{
-			"text":        map[string]interface{}{"content": match[1]},
-			"annotations": map[string]interface{}{"bold": true},
-		}
-	case match[2] != "": // *italic*
-		return map[string]interface{}{
-			"text":        map[string]interface{}{"content": match[2]},
-			"annotations": map[string]interface{}{"italic": true},
-		}
-	case match[3] != "": // _italic_
-		return map[string]interface{}{
-			"text":        map[string]interface{}{"content": match[3]},
-			"annotations": map[string]interface{}{"italic": true},
-		}
-	case match[4] != "": // `code`
-		return map[string]interface{}{
-			"text":        map[string]interface{}{"content": match[4]},
-			"annotations": map[string]interface{}{"code": true},
-		}
-	case match[5] != "": // ~~strike~~
-		return map[string]interface{}{
-			"text":        map[string]interface{}{"content": match[5]},
-			"annotations": map[string]interface{}{"strikethrough": true},
-		}
-	case match[6] != "": // [text](url)
-		return map[string]interface{}{
-			"text": map[string]interface{}{
-				"content": match[6],
-				"link":    map[string]interface{}{"url": match[7]},
-			},
-		}
-	default:
-		return plainRichText(match[0])
-	}
+func warnUnknownCodeLanguage(raw string) {
+	fmt.Fprintf(os.Stderr, "note: unknown code language %q, falling back to plain text\n", raw)
 }
 
 // richTextToMarkdown converts a Notion rich_text cell ([]interface{} of rich_text objects)
@@ -38,7 +38,7 @@ func parseOversizeMode(raw string) (oversizeMode, error) {
 	case "fail":
 		return oversizeFail, nil
 	default:
-		return "", fmt.Errorf("--on-oversize must be one of: split, truncate, fail (got %q)", raw)
+		return "", usageError(fmt.Errorf("--on-oversize must be one of: split, truncate, fail (got %q)", raw))
 	}
 }
 
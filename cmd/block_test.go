@@ -433,28 +433,6 @@ func TestParseInlineFormatting(t *testing.T) {
 	}
 }
 
-func TestIsTableSeparator(t *testing.T) {
-	tests := []struct {
-		input string
-		want  bool
-	}{
-		{"| --- | --- |", true},
-		{"|---|---|", true},
-		{"| :--- | ---: | :---: |", true},
-		{"| Name | Age |", false},
-		{"---", false},
-		{"| - |", true},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := isTableSeparator(tt.input)
-			if got != tt.want {
-				t.Errorf("isTableSeparator(%q) = %v, want %v", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestMapBlockTypeAliases(t *testing.T) {
 	tests := []struct {
 		input string
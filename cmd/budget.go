@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// errBudgetExhausted is wrapped by the error of every request refused
+// because the command used up its --max-requests budget.
+var errBudgetExhausted = errors.New("request budget exhausted")
+
+var (
+	// maxRequests is the --max-requests flag; 0 falls back to
+	// NOTION_MAX_REQUESTS and the max_requests setting.
+	maxRequests int
+	// requestBudget is the limit in force for the running command (0 = none).
+	requestBudget int
+	// requestsSent counts the requests the running command started.
+	requestsSent int64
+)
+
+// resetRequestBudget resolves the budget for a new command: --max-requests,
+// then NOTION_MAX_REQUESTS, then 'notion config set max_requests'.
+func resetRequestBudget() error {
+	atomic.StoreInt64(&requestsSent, 0)
+	requestBudget = maxRequests
+	if requestBudget < 0 {
+		return usageError(fmt.Errorf("--max-requests must be 0 (no limit) or more"))
+	}
+	if requestBudget > 0 {
+		return nil
+	}
+	if env := os.Getenv("NOTION_MAX_REQUESTS"); env != "" {
+		n, err := strconv.Atoi(env)
+		if err != nil || n < 0 {
+			return fmt.Errorf("NOTION_MAX_REQUESTS: expected a number, got %q", env)
+		}
+		requestBudget = n
+		return nil
+	}
+	if cfg, err := config.Load(); err == nil {
+		requestBudget, _ = strconv.Atoi(cfg.Setting("max_requests"))
+	}
+	return nil
+}
+
+// enforceBudget is client middleware refusing requests beyond the budget.
+// Retries count against it too: each one reaches the API.
+func enforceBudget(next notion.Handler) notion.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		if requestBudget > 0 && atomic.AddInt64(&requestsSent, 1) > int64(requestBudget) {
+			return nil, fmt.Errorf("%w: limit of %d API request(s) reached (--max-requests); output is truncated", errBudgetExhausted, requestBudget)
+		}
+		return next(req)
+	}
+}
+
+// overBudget reports whether a paginated listing stopped because of the
+// request budget or the time limit after collecting some results, which
+// are then worth printing before the error.
+func overBudget(err error, results []interface{}) bool {
+	return len(results) > 0 && (errors.Is(err, errBudgetExhausted) || errors.Is(err, errDeadline))
+}
+
+// renderTruncated prints the partial results of a listing cut short by the
+// request budget as JSON, with the cursor to resume from, and returns the
+// budget error.
+func renderTruncated(results []interface{}, nextCursor string, err error) error {
+	if rerr := render.JSON(map[string]interface{}{
+		"results":     results,
+		"truncated":   true,
+		"next_cursor": nextCursor,
+	}); rerr != nil {
+		return rerr
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMaxRequestsTruncatesListing(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":      "list",
+			"results":     []interface{}{map[string]interface{}{"object": "page", "id": fmt.Sprintf("p%d", calls)}},
+			"has_more":    true,
+			"next_cursor": fmt.Sprintf("c%d", calls),
+		})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand("search", "--all", "--max-requests", "2", "--format", "json")
+	})
+	outputFormat = ""
+	if !errors.Is(err, errBudgetExhausted) {
+		t.Fatalf("err = %v, want errBudgetExhausted", err)
+	}
+	if calls != 2 {
+		t.Errorf("API calls = %d, want 2", calls)
+	}
+	var got struct {
+		Results    []interface{} `json:"results"`
+		Truncated  bool          `json:"truncated"`
+		NextCursor string        `json:"next_cursor"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if len(got.Results) != 2 || !got.Truncated || got.NextCursor != "c2" {
+		t.Errorf("output = %+v", got)
+	}
+}
+
+func TestRequestBudgetFromEnv(t *testing.T) {
+	maxRequests = 0
+	t.Setenv("NOTION_MAX_REQUESTS", "7")
+	if err := resetRequestBudget(); err != nil || requestBudget != 7 {
+		t.Errorf("budget = %d, %v; want 7", requestBudget, err)
+	}
+	maxRequests = 3
+	defer func() { maxRequests = 0 }()
+	if err := resetRequestBudget(); err != nil || requestBudget != 3 {
+		t.Errorf("flag should win: budget = %d, %v", requestBudget, err)
+	}
+	maxRequests = 0
+	t.Setenv("NOTION_MAX_REQUESTS", "lots")
+	if err := resetRequestBudget(); err == nil {
+		t.Error("invalid NOTION_MAX_REQUESTS accepted")
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// interruptedError reports a long operation stopped by Ctrl-C (or
+// SIGTERM) and how far it got.
+type interruptedError struct {
+	op     string
+	done   int
+	failed int
+	total  int    // 0 when unknown
+	resume string // how to carry on, if anything is left to do
+}
+
+func (e *interruptedError) Error() string {
+	msg := fmt.Sprintf("interrupted: %s stopped after %d", e.op, e.done)
+	if e.total > 0 {
+		msg += fmt.Sprintf(" of %d", e.total)
+	}
+	msg += " item(s)"
+	if e.failed > 0 {
+		msg += fmt.Sprintf(", %d failed", e.failed)
+	}
+	if e.resume != "" {
+		msg += "\n" + e.resume
+	}
+	return msg
+}
+
+// interruptible returns a client whose requests are cancelled by Ctrl-C or
+// SIGTERM, and the context long loops check between items. After the first
+// signal the default handling is restored, so a second Ctrl-C quits at
+// once. stop releases the signal handler.
+func interruptible(c *notion.Client) (*notion.Client, context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return c.WithContext(ctx), ctx, stop
+}
+
+// canceled reports whether err comes from a request cut short by
+// interruptible's signal handler.
+func canceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// interrupted reports the operation as stopped by the user, emitting an
+// "interrupted" progress event, and returns the error the command exits
+// with.
+func (p *progress) interrupted(resume string) error {
+	p.stop()
+	p.emit("interrupted", "", nil)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.text != "" && outputFormat != "json" && p.format != "json" && p.format != "none" {
+		fmt.Fprintln(p.w)
+	}
+	return &interruptedError{op: p.op, done: p.done, failed: p.failed, total: p.total, resume: resume}
+}
+
+// writeRemaining saves rows not yet processed as a JSON array for
+// 'db add-bulk --file', next to base (base.remaining.json), and returns a
+// hint naming the file.
+func writeRemaining(base, dbID string, rows []map[string]string) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	path := strings.TrimSuffix(base, filepath.Ext(base)) + ".remaining.json"
+	if base == "" || base == "-" {
+		path = "notion-remaining.json"
+	}
+	data, _ := json.MarshalIndent(rows, "", "  ")
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Sprintf("could not save the %d remaining row(s): %v", len(rows), err)
+	}
+	return fmt.Sprintf("%d remaining row(s) saved to %s; continue with:\n  notion db add-bulk %s --file %s", len(rows), path, dbID, path)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddBulkInterruptSavesRemainingRows(t *testing.T) {
+	posts := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"type": "title"},
+			}})
+			return
+		}
+		io.ReadAll(r.Body)
+		posts++
+		if posts == 2 {
+			// Ctrl-C while the second row is being created.
+			self, _ := os.FindProcess(os.Getpid())
+			if err := self.Signal(os.Interrupt); err != nil {
+				t.Skipf("cannot signal: %v", err)
+			}
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"object":"page","id":"p"}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	file := filepath.Join(t.TempDir(), "rows.json")
+	os.WriteFile(file, []byte(`[{"Name": "a"}, {"Name": "b"}, {"Name": "c"}]`), 0o644)
+
+	var err error
+	captureStdout(t, func() {
+		_, _, err = executeCommand("db", "add-bulk", "66666666666666666666666666666666", "--file", file)
+	})
+	if err == nil || exitCode(err) != exitInterrupted {
+		t.Fatalf("err = %v, want an interruption", err)
+	}
+	if !strings.Contains(err.Error(), "stopped after 1 of 3") {
+		t.Errorf("summary = %q", err)
+	}
+	data, readErr := os.ReadFile(strings.TrimSuffix(file, ".json") + ".remaining.json")
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	var rest []map[string]string
+	json.Unmarshal(data, &rest)
+	if len(rest) != 2 || rest[0]["Name"] != "b" || rest[1]["Name"] != "c" {
+		t.Errorf("remaining rows = %v", rest)
+	}
+}
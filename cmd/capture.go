@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// captureTarget is what capture needs to know about the inbox. It is
+// cached on disk so a capture is a single API request.
+type captureTarget struct {
+	Object    string `json:"object"` // "database" or "page"
+	TitleProp string `json:"title_prop,omitempty"`
+	TagsProp  string `json:"tags_prop,omitempty"`
+	TagsType  string `json:"tags_type,omitempty"` // "multi_select" or "select"
+	DateProp  string `json:"date_prop,omitempty"`
+}
+
+var captureCmd = &cobra.Command{
+	Use:   "capture [text]",
+	Short: "Quickly file a note into your inbox",
+	Long: `Capture a one-liner (or stdin) into an inbox database or page.
+
+The inbox is --to, or the inbox setting. In a database the first line
+becomes the title of a new row; tags fill the Tags (or first multi-select)
+property and the time fills a Captured/Date property when the schema has
+one. On a page the note is appended as a to-do with its tags and time.
+Further lines are added as the row's or to-do's body.
+
+The inbox layout is cached after the first capture so later captures make
+a single request; it is refreshed automatically when the database changes.
+
+Examples:
+  notion config set inbox abc123
+  notion capture "idea: rate limit UI" -t idea
+  notion capture "call the bank" -t todo -t home
+  pbpaste | notion capture -t reading
+  notion capture "draft" --to def456`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		tags, _ := cmd.Flags().GetStringArray("tag")
+		if to == "" {
+			if cfg, _ := config.Load(); cfg != nil {
+				to = cfg.Setting("inbox")
+			}
+		}
+		if to == "" {
+			return usageError(fmt.Errorf("no inbox: pass --to or run 'notion config set inbox <page|db>'"))
+		}
+
+		text := strings.Join(args, " ")
+		if text == "" || text == "-" {
+			if text == "" && term.IsTerminal(int(os.Stdin.Fd())) {
+				return usageError(fmt.Errorf("nothing to capture: pass the text as an argument or on stdin"))
+			}
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("read stdin: %w", err)
+			}
+			text = string(data)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return fmt.Errorf("nothing to capture")
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		inboxID := util.ResolveID(to)
+
+		target, cached := loadCaptureTarget(inboxID)
+		if !cached {
+			if target, err = detectCaptureTarget(c, inboxID); err != nil {
+				return err
+			}
+		}
+		data, err := sendCapture(c, inboxID, target, text, tags, time.Now())
+		if err != nil && cached && errors.Is(err, notion.ErrValidation) {
+			// The database changed since the layout was cached.
+			if target, err = detectCaptureTarget(c, inboxID); err != nil {
+				return err
+			}
+			data, err = sendCapture(c, inboxID, target, text, tags, time.Now())
+		}
+		if err != nil {
+			return fmt.Errorf("capture: %w", err)
+		}
+		saveCaptureTarget(inboxID, target)
+
+		if outputFormat == "json" {
+			var result map[string]interface{}
+			json.Unmarshal(data, &result)
+			return render.JSON(result)
+		}
+		fmt.Println("✓ Captured")
+		return nil
+	},
+}
+
+func init() {
+	captureCmd.Flags().StringArrayP("tag", "t", nil, "Tag to attach (repeatable)")
+	captureCmd.Flags().String("to", "", "Inbox page or database (default: inbox setting)")
+	markIDFlags(captureCmd, "to")
+}
+
+func captureTargetPath(id string) string {
+	return filepath.Join(config.CacheDir(), "capture", strings.ReplaceAll(id, "-", "")+".json")
+}
+
+func loadCaptureTarget(id string) (captureTarget, bool) {
+	var t captureTarget
+	data, err := os.ReadFile(captureTargetPath(id))
+	if err != nil || json.Unmarshal(data, &t) != nil || t.Object == "" {
+		return captureTarget{}, false
+	}
+	return t, true
+}
+
+// saveCaptureTarget caches the inbox layout. Failures are ignored: the
+// cache only saves a request.
+func saveCaptureTarget(id string, t captureTarget) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return
+	}
+	path := captureTargetPath(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0600)
+}
+
+// detectCaptureTarget looks the inbox up: a database's schema decides
+// which properties capture fills; anything else is treated as a page.
+func detectCaptureTarget(c *notion.Client, id string) (captureTarget, error) {
+	db, err := c.GetDatabase(id)
+	if err != nil {
+		if errors.Is(err, notion.ErrUnauthorized) {
+			return captureTarget{}, fmt.Errorf("get inbox: %w", err)
+		}
+		return captureTarget{Object: "page"}, nil
+	}
+	props, _ := db["properties"].(map[string]interface{})
+	return captureLayout(props), nil
+}
+
+// captureLayout picks the properties capture fills from a database schema:
+// the title, a select or multi-select named Tags (else the first
+// multi-select), and a date named Captured or Clipped (else Date or
+// Created).
+func captureLayout(props map[string]interface{}) captureTarget {
+	t := captureTarget{Object: "database"}
+	dateRank := 0
+	tagsNamed := false
+	for _, name := range sortedKeys(props) {
+		prop, _ := props[name].(map[string]interface{})
+		propType, _ := prop["type"].(string)
+		lower := strings.ToLower(name)
+		switch propType {
+		case "title":
+			t.TitleProp = name
+		case "multi_select", "select":
+			if lower == "tags" || lower == "tag" {
+				t.TagsProp, t.TagsType = name, propType
+				tagsNamed = true
+			} else if !tagsNamed && t.TagsProp == "" && propType == "multi_select" {
+				t.TagsProp, t.TagsType = name, propType
+			}
+		case "date":
+			rank := map[string]int{"captured": 3, "captured at": 3, "clipped": 3, "date": 2, "created": 1}[lower]
+			if rank > dateRank {
+				t.DateProp, dateRank = name, rank
+			}
+		}
+	}
+	return t
+}
+
+// sendCapture writes one capture and returns the API response.
+func sendCapture(c *notion.Client, inboxID string, t captureTarget, text string, tags []string, now time.Time) ([]byte, error) {
+	first, rest, _ := strings.Cut(text, "\n")
+	body := parseMarkdownToBlocks(strings.TrimSpace(rest))
+
+	if t.Object != "database" {
+		line := strings.TrimSpace(first)
+		for _, tag := range tags {
+			line += " #" + tag
+		}
+		line += " — " + now.Format("2006-01-02 15:04")
+		todo := notion.TextBlock("to_do", line)
+		if len(body) > 0 {
+			todo["to_do"].(map[string]interface{})["children"] = body
+		}
+		return c.Patch(fmt.Sprintf("/v1/blocks/%s/children", inboxID), map[string]interface{}{
+			"children": []map[string]interface{}{todo},
+		})
+	}
+
+	if t.TitleProp == "" {
+		return nil, fmt.Errorf("the inbox database has no title property")
+	}
+	properties := map[string]interface{}{
+		t.TitleProp: buildPropertyValue("title", strings.TrimSpace(first)),
+	}
+	if len(tags) > 0 {
+		if t.TagsProp == "" {
+			return nil, fmt.Errorf("the inbox database has no select or multi-select property for tags")
+		}
+		if t.TagsType == "select" && len(tags) > 1 {
+			return nil, fmt.Errorf("%q is a select property and takes one tag", t.TagsProp)
+		}
+		properties[t.TagsProp] = buildPropertyValue(t.TagsType, strings.Join(tags, ","))
+	}
+	if t.DateProp != "" {
+		properties[t.DateProp] = buildPropertyValue("date", now.Format(time.RFC3339))
+	}
+	page := map[string]interface{}{
+		"parent":     map[string]interface{}{"database_id": inboxID},
+		"properties": properties,
+	}
+	if len(body) > 0 {
+		page["children"] = body
+	}
+	data, err := c.Post("/v1/pages", page)
+	if err == nil {
+		rememberCreated(data)
+	}
+	return data, err
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCaptureLayout(t *testing.T) {
+	props := map[string]interface{}{
+		"Name":     map[string]interface{}{"type": "title"},
+		"Area":     map[string]interface{}{"type": "multi_select"},
+		"Tags":     map[string]interface{}{"type": "select"},
+		"Date":     map[string]interface{}{"type": "date"},
+		"Captured": map[string]interface{}{"type": "date"},
+	}
+	got := captureLayout(props)
+	want := captureTarget{Object: "database", TitleProp: "Name", TagsProp: "Tags", TagsType: "select", DateProp: "Captured"}
+	if got != want {
+		t.Errorf("captureLayout = %+v, want %+v", got, want)
+	}
+
+	delete(props, "Tags")
+	delete(props, "Captured")
+	got = captureLayout(props)
+	if got.TagsProp != "Area" || got.DateProp != "Date" {
+		t.Errorf("fallbacks: %+v, want Area and Date", got)
+	}
+}
+
+func TestCaptureCachesSchema(t *testing.T) {
+	var schemaFetches int
+	var created []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			schemaFetches++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database",
+				"id":     "inbox",
+				"properties": map[string]interface{}{
+					"Name": map[string]interface{}{"type": "title"},
+					"Tags": map[string]interface{}{"type": "multi_select"},
+				},
+			})
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		created = append(created, body)
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "p1"})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	inbox := "22222222222222222222222222222222"
+	for i := 0; i < 2; i++ {
+		captureStdout(t, func() {
+			if _, _, err := executeCommand("capture", "idea: rate limit UI", "-t", "idea", "--to", inbox); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	if schemaFetches != 1 {
+		t.Errorf("schema fetched %d times, want 1 (cached after the first capture)", schemaFetches)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d rows, want 2", len(created))
+	}
+	props, _ := created[1]["properties"].(map[string]interface{})
+	tags, _ := props["Tags"].(map[string]interface{})
+	if opts, _ := tags["multi_select"].([]interface{}); len(opts) != 1 {
+		t.Errorf("Tags = %v, want one option", props["Tags"])
+	}
+	if props["Name"] == nil {
+		t.Error("title not set")
+	}
+}
@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// defaultChangelogTemplate renders one changelog entry as markdown.
+const defaultChangelogTemplate = `{{.Subject}} ({{if .URL}}[{{.ShortSHA}}]({{.URL}}){{else}}{{.ShortSHA}}{{end}}) — {{.Author}}, {{.Date}}`
+
+// commitInfo is what a changelog entry knows about a commit. The field
+// names are the ones available to --template.
+type commitInfo struct {
+	SHA      string `json:"sha"`
+	ShortSHA string `json:"short_sha"`
+	Subject  string `json:"subject"`
+	Author   string `json:"author"`
+	Date     string `json:"date"`
+	Time     string `json:"time"`
+	URL      string `json:"url,omitempty"`
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Record commits in a Notion changelog",
+}
+
+var changelogAppendCmd = &cobra.Command{
+	Use:   "append [commit]",
+	Short: "Append a commit to a changelog page or database",
+	Long: `Append an entry for a git commit (HEAD by default) to a changelog page
+or database. Designed to run from CI or a post-commit hook.
+
+The target is --to, or the changelog_target setting. On a page the entry is
+added as a bullet; in a database it becomes a row titled with the entry,
+and properties named SHA/Commit, Author, Date and URL/Link are filled in
+when the schema has them.
+
+Entries are idempotent: when the target already mentions the commit's SHA
+nothing is written, so re-running a pipeline is safe.
+
+The commit link comes from GITHUB_SERVER_URL/GITHUB_REPOSITORY,
+CI_PROJECT_URL (GitLab) or the origin remote. --template is a Go template
+over .Subject, .SHA, .ShortSHA, .Author, .Date, .Time and .URL, written in
+markdown.
+
+Examples:
+  notion changelog append --to abc123
+  notion changelog append v1.2.0 --to abc123
+  notion config set changelog_target abc123 && notion changelog append
+  notion changelog append --template '{{.Subject}} by {{.Author}}'
+  notion changelog append --subject "Hotfix" --sha "$CI_COMMIT_SHA"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, _ := cmd.Flags().GetString("to")
+		if target == "" {
+			cfg, _ := config.Load()
+			if cfg != nil {
+				target = cfg.Setting("changelog_target")
+			}
+		}
+		if target == "" {
+			return usageError(fmt.Errorf("no changelog target: pass --to or run 'notion config set changelog_target <page|db>'"))
+		}
+		rev := "HEAD"
+		if len(args) == 1 {
+			rev = args[0]
+		}
+
+		info, err := changelogCommit(cmd, rev)
+		if err != nil {
+			return err
+		}
+		tmpl, _ := cmd.Flags().GetString("template")
+		entry, err := renderChangelogEntry(tmpl, info)
+		if err != nil {
+			return err
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		targetID := util.ResolveID(target)
+
+		db, err := c.GetDatabase(targetID)
+		isDB := err == nil
+		if err != nil && errors.Is(err, notion.ErrUnauthorized) {
+			return fmt.Errorf("get changelog target: %w", err)
+		}
+
+		var exists bool
+		if isDB {
+			exists, err = changelogRowExists(c, targetID, db, info)
+		} else {
+			exists, err = changelogBlockExists(c, targetID, info)
+		}
+		if err != nil {
+			return err
+		}
+
+		result := map[string]interface{}{"target": targetID, "commit": info, "entry": entry, "created": false}
+		if exists {
+			if outputFormat == "json" {
+				return render.JSON(result)
+			}
+			fmt.Printf("✓ %s is already in the changelog\n", info.ShortSHA)
+			return nil
+		}
+
+		var id string
+		if isDB {
+			id, err = appendChangelogRow(c, targetID, db, entry, info)
+		} else {
+			id, err = appendChangelogBlock(c, targetID, entry)
+		}
+		if err != nil {
+			return err
+		}
+
+		result["created"] = true
+		result["id"] = id
+		if outputFormat == "json" {
+			return render.JSON(result)
+		}
+		fmt.Printf("✓ Added %s to the changelog\n", info.ShortSHA)
+		return nil
+	},
+}
+
+func init() {
+	changelogAppendCmd.Flags().String("to", "", "Changelog page or database (default: changelog_target setting)")
+	changelogAppendCmd.Flags().String("template", defaultChangelogTemplate, "Go template for the entry (markdown)")
+	changelogAppendCmd.Flags().String("subject", "", "Override the commit subject")
+	changelogAppendCmd.Flags().String("author", "", "Override the commit author")
+	changelogAppendCmd.Flags().String("sha", "", "Override the commit SHA (skips git when --subject is also set)")
+	changelogAppendCmd.Flags().String("url", "", "Override the commit link")
+	markIDFlags(changelogAppendCmd, "to")
+	changelogCmd.AddCommand(changelogAppendCmd)
+}
+
+// changelogCommit reads rev from git and applies the override flags. git
+// isn't needed when --sha and --subject are both given.
+func changelogCommit(cmd *cobra.Command, rev string) (commitInfo, error) {
+	sha, _ := cmd.Flags().GetString("sha")
+	subject, _ := cmd.Flags().GetString("subject")
+	author, _ := cmd.Flags().GetString("author")
+	link, _ := cmd.Flags().GetString("url")
+
+	var info commitInfo
+	if sha == "" || subject == "" {
+		if sha != "" {
+			rev = sha
+		}
+		out, err := exec.Command("git", "log", "-1", "--format=%H%x00%s%x00%an%x00%aI", rev).Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+				return info, fmt.Errorf("read commit %s: %s", rev, strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return info, fmt.Errorf("read commit %s: %w", rev, err)
+		}
+		if info, err = parseGitLog(string(out)); err != nil {
+			return info, err
+		}
+	} else {
+		info = commitInfo{SHA: sha, Time: time.Now().UTC().Format(time.RFC3339)}
+	}
+
+	if sha != "" {
+		info.SHA = sha
+	}
+	if subject != "" {
+		info.Subject = subject
+	}
+	if author != "" {
+		info.Author = author
+	}
+	info.ShortSHA = info.SHA
+	if len(info.ShortSHA) > 7 {
+		info.ShortSHA = info.ShortSHA[:7]
+	}
+	if info.Date == "" && len(info.Time) >= 10 {
+		info.Date = info.Time[:10]
+	}
+
+	info.URL = link
+	if info.URL == "" {
+		info.URL = ciCommitURL(info.SHA)
+	}
+	if info.URL == "" {
+		if remote, err := exec.Command("git", "remote", "get-url", "origin").Output(); err == nil {
+			info.URL = remoteCommitURL(strings.TrimSpace(string(remote)), info.SHA)
+		}
+	}
+	return info, nil
+}
+
+// parseGitLog parses the output of
+// git log -1 --format=%H%x00%s%x00%an%x00%aI.
+func parseGitLog(out string) (commitInfo, error) {
+	parts := strings.Split(strings.TrimRight(out, "\n"), "\x00")
+	if len(parts) != 4 || parts[0] == "" {
+		return commitInfo{}, fmt.Errorf("unexpected git log output %q", out)
+	}
+	info := commitInfo{SHA: parts[0], Subject: parts[1], Author: parts[2], Time: parts[3]}
+	if t, err := time.Parse(time.RFC3339, parts[3]); err == nil {
+		info.Date = t.Format("2006-01-02")
+	}
+	return info, nil
+}
+
+// ciCommitURL builds a commit link from CI environment variables.
+func ciCommitURL(sha string) string {
+	if server, repo := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"); server != "" && repo != "" {
+		return strings.TrimSuffix(server, "/") + "/" + repo + "/commit/" + sha
+	}
+	if project := os.Getenv("CI_PROJECT_URL"); project != "" {
+		return strings.TrimSuffix(project, "/") + "/-/commit/" + sha
+	}
+	return ""
+}
+
+var scpRemoteRe = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):(.+)$`)
+
+// remoteCommitURL turns a git remote (https or scp-style ssh) into a web
+// link to sha, or "" when the remote isn't recognisable.
+func remoteCommitURL(remote, sha string) string {
+	var host, path string
+	switch {
+	case strings.HasPrefix(remote, "https://"), strings.HasPrefix(remote, "http://"), strings.HasPrefix(remote, "ssh://"):
+		rest := remote[strings.Index(remote, "://")+3:]
+		if at := strings.Index(rest, "@"); at >= 0 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:]
+		}
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			return ""
+		}
+		host, path = rest[:slash], rest[slash+1:]
+		if colon := strings.Index(host, ":"); colon >= 0 && strings.HasPrefix(remote, "ssh://") {
+			host = host[:colon]
+		}
+	default:
+		m := scpRemoteRe.FindStringSubmatch(remote)
+		if m == nil {
+			return ""
+		}
+		host, path = m[1], m[2]
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return ""
+	}
+	if strings.Contains(host, "gitlab") {
+		return "https://" + host + "/" + path + "/-/commit/" + sha
+	}
+	return "https://" + host + "/" + path + "/commit/" + sha
+}
+
+func renderChangelogEntry(text string, info commitInfo) (string, error) {
+	tmpl, err := template.New("entry").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse --template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, info); err != nil {
+		return "", fmt.Errorf("render --template: %w", err)
+	}
+	entry := strings.TrimSpace(buf.String())
+	if entry == "" {
+		return "", usageError(fmt.Errorf("--template rendered an empty entry"))
+	}
+	return entry, nil
+}
+
+// mentionsCommit reports whether text contains the commit's full or short
+// SHA.
+func mentionsCommit(text string, info commitInfo) bool {
+	return strings.Contains(text, info.SHA) || (info.ShortSHA != "" && strings.Contains(text, info.ShortSHA))
+}
+
+// changelogBlockExists looks for the commit in the page's top-level
+// blocks, including link targets.
+func changelogBlockExists(c *notion.Client, pageID string, info commitInfo) (bool, error) {
+	blocks, err := c.GetBlockChildrenAll(pageID)
+	if err != nil {
+		return false, fmt.Errorf("read changelog page: %w", err)
+	}
+	for _, b := range blocks {
+		block, _ := b.(map[string]interface{})
+		blockType, _ := block["type"].(string)
+		data, _ := block[blockType].(map[string]interface{})
+		richText, _ := data["rich_text"].([]interface{})
+		if mentionsCommit(notion.PlainText(richText), info) {
+			return true, nil
+		}
+		for _, rt := range richText {
+			item, _ := rt.(map[string]interface{})
+			if href, _ := item["href"].(string); href != "" && mentionsCommit(href, info) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// changelogRowExists searches the database for a row mentioning the
+// commit: in its SHA/Commit property when there is a text one, otherwise
+// in the title.
+func changelogRowExists(c *notion.Client, dbID string, db map[string]interface{}, info commitInfo) (bool, error) {
+	props, _ := db["properties"].(map[string]interface{})
+	filter := map[string]interface{}{}
+	for name, p := range props {
+		prop, _ := p.(map[string]interface{})
+		propType, _ := prop["type"].(string)
+		if propType == "title" && len(filter) == 0 {
+			filter = map[string]interface{}{"property": name, "title": map[string]interface{}{"contains": info.ShortSHA}}
+		}
+		if changelogField(name) == "sha" && (propType == "rich_text" || propType == "url") {
+			filter = map[string]interface{}{"property": name, propType: map[string]interface{}{"contains": info.ShortSHA}}
+			break
+		}
+	}
+	body := map[string]interface{}{"page_size": 1}
+	if len(filter) > 0 {
+		body["filter"] = filter
+	}
+	result, err := c.QueryDatabase(dbID, body)
+	if err != nil {
+		return false, fmt.Errorf("query changelog database: %w", err)
+	}
+	results, _ := result["results"].([]interface{})
+	return len(results) > 0, nil
+}
+
+// changelogField maps a database property name to the commit field it is
+// filled from, or "" when it isn't one.
+func changelogField(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "sha", "commit", "commit sha", "hash":
+		return "sha"
+	case "author":
+		return "author"
+	case "date", "committed", "commit date":
+		return "date"
+	case "url", "link", "commit url":
+		return "url"
+	case "subject", "message":
+		return "subject"
+	}
+	return ""
+}
+
+// changelogProperties builds the properties of a changelog row.
+func changelogProperties(schema map[string]interface{}, entry string, info commitInfo) map[string]interface{} {
+	values := map[string]string{"sha": info.SHA, "author": info.Author, "date": info.Time, "url": info.URL, "subject": info.Subject}
+	properties := map[string]interface{}{}
+	for name, p := range schema {
+		prop, _ := p.(map[string]interface{})
+		propType, _ := prop["type"].(string)
+		if propType == "title" {
+			properties[name] = map[string]interface{}{"title": notion.RichTextFromMarkdown(entry)}
+			continue
+		}
+		value := values[changelogField(name)]
+		if value == "" {
+			continue
+		}
+		switch propType {
+		case "rich_text", "select", "url", "date":
+			properties[name] = buildPropertyValue(propType, value)
+		}
+	}
+	return properties
+}
+
+func appendChangelogRow(c *notion.Client, dbID string, db map[string]interface{}, entry string, info commitInfo) (string, error) {
+	schema, _ := db["properties"].(map[string]interface{})
+	data, err := c.Post("/v1/pages", map[string]interface{}{
+		"parent":     map[string]interface{}{"database_id": dbID},
+		"properties": changelogProperties(schema, entry, info),
+	})
+	if err != nil {
+		return "", fmt.Errorf("add changelog row: %w", err)
+	}
+	rememberCreated(data)
+	return createdID(data), nil
+}
+
+func appendChangelogBlock(c *notion.Client, pageID, entry string) (string, error) {
+	block := map[string]interface{}{
+		"object":             "block",
+		"type":               "bulleted_list_item",
+		"bulleted_list_item": map[string]interface{}{"rich_text": notion.RichTextFromMarkdown(entry)},
+	}
+	data, err := appendChildrenBatched(c, pageID, "", []map[string]interface{}{block})
+	if err != nil {
+		return "", fmt.Errorf("append changelog entry: %w", err)
+	}
+	var resp struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+	}
+	if json.Unmarshal(data, &resp) == nil && len(resp.Results) > 0 {
+		return resp.Results[len(resp.Results)-1].ID, nil
+	}
+	return "", nil
+}
+
+// createdID returns the "id" of an API response, or "".
+func createdID(data []byte) string {
+	var obj struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(data, &obj)
+	return obj.ID
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// maxClipBytes bounds the size of a clipped page.
+const maxClipBytes = 10 << 20
+
+// clipClient fetches pages for 'notion clip'.
+var clipClient = &http.Client{Timeout: 30 * time.Second}
+
+var clipCmd = &cobra.Command{
+	Use:   "clip <url>",
+	Short: "Save a web page into Notion",
+	Long: `Fetch a web page, extract its readable content and save it as a Notion
+page — a web clipper for the terminal.
+
+The article is found the way reader modes do (the <article> or <main>
+element, else the part of the page with the most paragraph text) and
+converted to headings, paragraphs, lists, quotes, code, tables and
+images. The new page starts with a bookmark of the URL and a note of the
+site, author and clip date.
+
+The page is created under --to, or the clips_parent setting. In a
+database the clip becomes a row: its URL and the clip date fill a URL
+property and a Clipped/Date property when the schema has them, and
+--tag fills the Tags property.
+
+Examples:
+  notion config set clips_parent abc123
+  notion clip https://go.dev/blog/go1.22
+  notion clip https://example.com/post --to def456 -t reading
+  notion clip https://example.com/post --no-content   # bookmark only`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		title, _ := cmd.Flags().GetString("title")
+		tags, _ := cmd.Flags().GetStringArray("tag")
+		noContent, _ := cmd.Flags().GetBool("no-content")
+		if to == "" {
+			if cfg, _ := config.Load(); cfg != nil {
+				to = cfg.Setting("clips_parent")
+			}
+		}
+		if to == "" {
+			return usageError(fmt.Errorf("no parent for clips: pass --to or run 'notion config set clips_parent <page|db>'"))
+		}
+
+		page, err := fetchClip(args[0], !noContent)
+		if err != nil {
+			return err
+		}
+		if title != "" {
+			page.Meta.Title = title
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		parentID := util.ResolveID(to)
+
+		body := map[string]interface{}{"children": clipHeader(page, time.Now())}
+		db, err := c.GetDatabase(parentID)
+		switch {
+		case err == nil:
+			props, _ := db["properties"].(map[string]interface{})
+			properties, err := clipProperties(props, page, tags, time.Now())
+			if err != nil {
+				return err
+			}
+			body["parent"] = map[string]interface{}{"database_id": parentID}
+			body["properties"] = properties
+		case errors.Is(err, notion.ErrUnauthorized):
+			return fmt.Errorf("get parent: %w", err)
+		default:
+			if len(tags) > 0 {
+				return usageError(fmt.Errorf("--tag needs a database parent"))
+			}
+			body["parent"] = map[string]interface{}{"page_id": parentID}
+			body["properties"] = map[string]interface{}{"title": buildPropertyValue("title", page.Meta.Title)}
+		}
+
+		data, err := c.Post("/v1/pages", body)
+		if err != nil {
+			return fmt.Errorf("create page: %w", err)
+		}
+		rememberCreated(data)
+		var result map[string]interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		id, _ := result["id"].(string)
+
+		if len(page.Blocks) > 0 {
+			blocks, err := handleOversizedBlocks(page.Blocks, oversizeSplit)
+			if err != nil {
+				return err
+			}
+			if _, err := appendChildrenBatched(c, id, "", blocks); err != nil {
+				return fmt.Errorf("page %s created, but adding its content failed: %w", id, err)
+			}
+		}
+
+		if outputFormat == "json" {
+			result["clipped_blocks"] = len(page.Blocks)
+			return render.JSON(result)
+		}
+		render.Title("✓", fmt.Sprintf("Clipped: %s", page.Meta.Title))
+		render.Field("ID", id)
+		if u, _ := result["url"].(string); u != "" {
+			render.Field("URL", notionLink(u))
+		}
+		render.Field("Blocks", fmt.Sprintf("%d", len(page.Blocks)))
+		return nil
+	},
+}
+
+func init() {
+	clipCmd.Flags().String("to", "", "Parent page or database (default: clips_parent setting)")
+	clipCmd.Flags().String("title", "", "Page title (default: the web page's title)")
+	markIDFlags(clipCmd, "to")
+	clipCmd.Flags().StringArrayP("tag", "t", nil, "Tag for a database parent's Tags property (repeatable)")
+	clipCmd.Flags().Bool("no-content", false, "Save only the bookmark and source details")
+}
+
+// clippedPage is a fetched web page ready to be saved.
+type clippedPage struct {
+	URL    *url.URL
+	Meta   pageMeta
+	Blocks []map[string]interface{}
+}
+
+// fetchClip downloads rawURL and, when content is set and the response is
+// HTML, extracts its readable content.
+func fetchClip(rawURL string, content bool) (*clippedPage, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("not an http(s) URL: %s", rawURL)
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; notion-cli clipper)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.5")
+	resp, err := clipClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("fetch %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+
+	page := &clippedPage{URL: resp.Request.URL}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		// Not a web page (a PDF, an image, ...): keep just the bookmark.
+		page.Meta.Title = path.Base(page.URL.Path)
+		if page.Meta.Title == "/" || page.Meta.Title == "." {
+			page.Meta.Title = page.URL.Host
+		}
+		return page, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxClipBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", rawURL, err)
+	}
+
+	doc := parseHTML(bytes.NewReader(data))
+	page.Meta = readPageMeta(doc)
+	if page.Meta.Title == "" {
+		page.Meta.Title = page.URL.Host
+	}
+	if content {
+		page.Blocks = htmlToBlocks(readableRoot(doc), page.URL, page.Meta.Title)
+	}
+	return page, nil
+}
+
+// clipHeader returns the blocks a clip starts with: a bookmark of the
+// source and a callout naming the site, author and dates.
+func clipHeader(page *clippedPage, now time.Time) []map[string]interface{} {
+	site := firstNonEmpty(page.Meta.SiteName, strings.TrimPrefix(page.URL.Host, "www."))
+	rt := []map[string]interface{}{
+		{"type": "text", "text": map[string]interface{}{"content": "Source: "}},
+		{"type": "text", "text": map[string]interface{}{"content": site, "link": map[string]interface{}{"url": page.URL.String()}}},
+	}
+	var details []string
+	if page.Meta.Author != "" {
+		details = append(details, "by "+page.Meta.Author)
+	}
+	if published := page.Meta.Published; len(published) >= 10 {
+		details = append(details, "published "+published[:10])
+	}
+	details = append(details, "clipped "+now.Format("2006-01-02"))
+	rt = append(rt, map[string]interface{}{"type": "text", "text": map[string]interface{}{"content": " · " + strings.Join(details, " · ")}})
+
+	blocks := []map[string]interface{}{
+		{"object": "block", "type": "bookmark", "bookmark": map[string]interface{}{"url": page.URL.String()}},
+		{"object": "block", "type": "callout", "callout": map[string]interface{}{
+			"rich_text": rt,
+			"icon":      map[string]interface{}{"type": "emoji", "emoji": "🔖"},
+		}},
+	}
+	if len(page.Blocks) == 0 && page.Meta.Description != "" {
+		blocks = append(blocks, notion.TextBlock("quote", page.Meta.Description))
+	}
+	return blocks
+}
+
+// clipProperties fills a clip row: the title, tags and date as capture
+// does, and the source in the first URL property.
+func clipProperties(schema map[string]interface{}, page *clippedPage, tags []string, now time.Time) (map[string]interface{}, error) {
+	layout := captureLayout(schema)
+	if layout.TitleProp == "" {
+		return nil, fmt.Errorf("the database has no title property")
+	}
+	properties := map[string]interface{}{
+		layout.TitleProp: buildPropertyValue("title", page.Meta.Title),
+	}
+	for _, name := range sortedKeys(schema) {
+		prop, _ := schema[name].(map[string]interface{})
+		if t, _ := prop["type"].(string); t == "url" {
+			properties[name] = buildPropertyValue("url", page.URL.String())
+			break
+		}
+	}
+	if layout.DateProp != "" {
+		properties[layout.DateProp] = buildPropertyValue("date", now.Format(time.RFC3339))
+	}
+	if len(tags) > 0 {
+		if layout.TagsProp == "" {
+			return nil, fmt.Errorf("the database has no select or multi-select property for tags")
+		}
+		properties[layout.TagsProp] = buildPropertyValue(layout.TagsType, strings.Join(tags, ","))
+	}
+	return properties, nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClipToDatabase(t *testing.T) {
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(sampleArticle))
+	}))
+	defer web.Close()
+
+	var created, appended map[string]interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database",
+				"id":     "clips",
+				"properties": map[string]interface{}{
+					"Name":    map[string]interface{}{"type": "title"},
+					"Source":  map[string]interface{}{"type": "url"},
+					"Tags":    map[string]interface{}{"type": "multi_select"},
+					"Clipped": map[string]interface{}{"type": "date"},
+				},
+			})
+		case "POST":
+			created = body
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "new-page"})
+		case "PATCH":
+			appended = body
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("clip", web.URL+"/post", "--to", "33333333333333333333333333333333", "-t", "reading"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	props, _ := created["properties"].(map[string]interface{})
+	for _, name := range []string{"Name", "Source", "Tags", "Clipped"} {
+		if props[name] == nil {
+			t.Errorf("property %s not set: %v", name, props)
+		}
+	}
+	if src, _ := props["Source"].(map[string]interface{}); src["url"] != web.URL+"/post" {
+		t.Errorf("Source = %v", props["Source"])
+	}
+	header, _ := created["children"].([]interface{})
+	if len(header) < 2 || header[0].(map[string]interface{})["type"] != "bookmark" {
+		t.Errorf("page should start with a bookmark, got %v", header)
+	}
+	if children, _ := appended["children"].([]interface{}); len(children) != 9 {
+		t.Errorf("appended %d content blocks, want 9", len(children))
+	}
+}
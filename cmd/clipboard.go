@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// clipboardTool is one external program that reads or writes the system
+// clipboard. Programs are tried in order; the first one installed is used.
+type clipboardTool struct {
+	name string
+	args []string
+	// decode, when set, turns the program's output into the clipboard
+	// content.
+	decode func([]byte) ([]byte, error)
+}
+
+// clipboardCopyTools and clipboardPasteTools list the clipboard programs
+// for each platform. On Linux, Wayland tools come first when a Wayland
+// session is running.
+func clipboardCopyTools() []clipboardTool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []clipboardTool{{name: "pbcopy"}}
+	case "windows":
+		return []clipboardTool{{name: "clip"}}
+	}
+	tools := []clipboardTool{
+		{name: "xclip", args: []string{"-selection", "clipboard", "-in"}},
+		{name: "xsel", args: []string{"--clipboard", "--input"}},
+		{name: "clip.exe"}, // WSL
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append([]clipboardTool{{name: "wl-copy"}}, tools...)
+	}
+	return tools
+}
+
+func clipboardPasteTools() []clipboardTool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []clipboardTool{{name: "pbpaste"}}
+	case "windows":
+		return []clipboardTool{{name: "powershell", args: []string{"-NoProfile", "-Command", "Get-Clipboard -Raw"}}}
+	}
+	tools := []clipboardTool{
+		{name: "xclip", args: []string{"-selection", "clipboard", "-out"}},
+		{name: "xsel", args: []string{"--clipboard", "--output"}},
+		{name: "powershell.exe", args: []string{"-NoProfile", "-Command", "Get-Clipboard -Raw"}}, // WSL
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append([]clipboardTool{{name: "wl-paste", args: []string{"--no-newline"}}}, tools...)
+	}
+	return tools
+}
+
+// windowsClipboardImageScript writes the clipboard's image to stdout as
+// PNG, or nothing when the clipboard holds no image.
+const windowsClipboardImageScript = `Add-Type -AssemblyName System.Windows.Forms; ` +
+	`$img = [Windows.Forms.Clipboard]::GetImage(); ` +
+	`if ($img) { $ms = New-Object IO.MemoryStream; ` +
+	`$img.Save($ms, [Drawing.Imaging.ImageFormat]::Png); ` +
+	`$out = [Console]::OpenStandardOutput(); $out.Write($ms.ToArray(), 0, $ms.Length) }`
+
+// clipboardImageTools lists the programs that print the clipboard's image
+// as PNG. macOS falls back to AppleScript when pngpaste isn't installed.
+func clipboardImageTools() []clipboardTool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []clipboardTool{
+			{name: "pngpaste", args: []string{"-"}},
+			{name: "osascript", args: []string{"-e", "get the clipboard as «class PNGf»"}, decode: decodeAppleScriptData},
+		}
+	case "windows":
+		return []clipboardTool{{name: "powershell", args: []string{"-NoProfile", "-STA", "-Command", windowsClipboardImageScript}}}
+	}
+	tools := []clipboardTool{
+		{name: "xclip", args: []string{"-selection", "clipboard", "-target", "image/png", "-out"}},
+		{name: "powershell.exe", args: []string{"-NoProfile", "-STA", "-Command", windowsClipboardImageScript}}, // WSL
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = append([]clipboardTool{{name: "wl-paste", args: []string{"--type", "image/png"}}}, tools...)
+	}
+	return tools
+}
+
+// decodeAppleScriptData decodes AppleScript's «data PNGf89504E47…»
+// notation into raw bytes.
+func decodeAppleScriptData(out []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(s, "«data ") || !strings.HasSuffix(s, "»") {
+		return nil, fmt.Errorf("unexpected osascript output")
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "«data "), "»")
+	if len(s) < 4 {
+		return nil, fmt.Errorf("unexpected osascript output")
+	}
+	return hex.DecodeString(s[4:]) // skip the 4-letter type code
+}
+
+// findClipboardTool returns the first installed tool of tools.
+func findClipboardTool(tools []clipboardTool) (clipboardTool, error) {
+	var names []string
+	for _, t := range tools {
+		if _, err := exec.LookPath(t.name); err == nil {
+			return t, nil
+		}
+		names = append(names, t.name)
+	}
+	return clipboardTool{}, fmt.Errorf("no clipboard tool found (install one of: %s)", strings.Join(names, ", "))
+}
+
+// clipboardWrite, clipboardRead and clipboardReadImage access the system clipboard. They are
+// variables so tests can replace them.
+var (
+	clipboardWrite     = writeSystemClipboard
+	clipboardRead      = readSystemClipboard
+	clipboardReadImage = readSystemClipboardImage
+)
+
+func writeSystemClipboard(text string) error {
+	tool, err := findClipboardTool(clipboardCopyTools())
+	if err != nil {
+		return err
+	}
+	c := exec.Command(tool.name, tool.args...)
+	c.Stdin = strings.NewReader(text)
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", tool.name, firstNonEmpty(strings.TrimSpace(string(out)), err.Error()))
+	}
+	return nil
+}
+
+func readSystemClipboard() (string, error) {
+	tool, err := findClipboardTool(clipboardPasteTools())
+	if err != nil {
+		return "", err
+	}
+	var stderr bytes.Buffer
+	c := exec.Command(tool.name, tool.args...)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", tool.name, firstNonEmpty(strings.TrimSpace(stderr.String()), err.Error()))
+	}
+	// Windows tools end the text with CRLF.
+	return strings.ReplaceAll(string(out), "\r\n", "\n"), nil
+}
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func readSystemClipboardImage() ([]byte, error) {
+	tool, err := findClipboardTool(clipboardImageTools())
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	c := exec.Command(tool.name, tool.args...)
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		// The tools fail when the clipboard holds text rather than an image.
+		return nil, fmt.Errorf("the clipboard has no image (%s: %s)", tool.name, firstNonEmpty(strings.TrimSpace(stderr.String()), err.Error()))
+	}
+	if tool.decode != nil {
+		if out, err = tool.decode(out); err != nil {
+			return nil, fmt.Errorf("the clipboard has no image (%s)", err)
+		}
+	}
+	return out, nil
+}
+
+// loadSourceFromClipboard returns the clipboard's image as an upload
+// source named after the current time.
+func loadSourceFromClipboard() (*fileSource, error) {
+	data, err := clipboardReadImage()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("the clipboard has no image")
+	}
+	return &fileSource{
+		Name:        "clipboard-" + time.Now().Format("20060102-150405") + ".png",
+		Size:        int64(len(data)),
+		ContentType: "image/png",
+		Data:        data,
+	}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// addCopyFlag registers --copy on a command that prints a created or found
+// object. A bare --copy copies the URL; --copy=id copies the ID.
+func addCopyFlag(cmd *cobra.Command) {
+	cmd.Flags().String("copy", "", "Copy the result's URL to the clipboard (--copy=id for the ID)")
+	cmd.Flags().Lookup("copy").NoOptDefVal = "url"
+}
+
+// checkCopyFlag validates --copy before the command does any work.
+func checkCopyFlag(cmd *cobra.Command) error {
+	switch what, _ := cmd.Flags().GetString("copy"); what {
+	case "", "url", "id":
+		return nil
+	default:
+		return usageError(fmt.Errorf("--copy must be url or id, got %q", what))
+	}
+}
+
+// copyResult puts the URL or ID of obj on the clipboard when --copy was
+// given. Messages go to stderr so stdout stays parseable, and a clipboard
+// failure is only a note: the command itself succeeded.
+func copyResult(cmd *cobra.Command, obj map[string]interface{}) {
+	what, _ := cmd.Flags().GetString("copy")
+	if what == "" {
+		return
+	}
+	value, _ := obj[what].(string)
+	if what == "url" {
+		value = notionLink(value)
+	}
+	if value == "" {
+		fmt.Fprintf(os.Stderr, "note: --copy: the result has no %s\n", what)
+		return
+	}
+	if err := clipboardWrite(value); err != nil {
+		fmt.Fprintf(os.Stderr, "note: --copy: %s\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "✓ Copied %s to the clipboard\n", strings.ToUpper(what))
+}
+
+// pastedText returns the clipboard's text for --paste.
+func pastedText() (string, error) {
+	text, err := clipboardRead()
+	if err != nil {
+		return "", fmt.Errorf("read clipboard: %w", err)
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", invalidInput(fmt.Errorf("--paste: the clipboard is empty"))
+	}
+	return text, nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeClipboard replaces the system clipboard for one test.
+func fakeClipboard(t *testing.T, content string) *string {
+	t.Helper()
+	clip := content
+	oldWrite, oldRead := clipboardWrite, clipboardRead
+	clipboardWrite = func(text string) error { clip = text; return nil }
+	clipboardRead = func() (string, error) { return clip, nil }
+	t.Cleanup(func() { clipboardWrite, clipboardRead = oldWrite, oldRead })
+	return &clip
+}
+
+func newClipboardTestServer(t *testing.T, bodies *[]map[string]interface{}) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*bodies = append(*bodies, body)
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object":     "database",
+				"id":         "db1",
+				"properties": map[string]interface{}{"Name": map[string]interface{}{"id": "title", "type": "title"}},
+			})
+		case r.URL.Path == "/v1/search":
+			page := func(id string) map[string]interface{} {
+				return map[string]interface{}{"object": "page", "id": id, "url": "https://www.notion.so/" + id, "properties": map[string]interface{}{}}
+			}
+			results := []interface{}{page("p1")}
+			if body["query"] == "many" {
+				results = append(results, page("p2"))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results, "has_more": false})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "new-id", "url": "https://www.notion.so/new-id", "results": []interface{}{}})
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+}
+
+func TestDBAddCopy(t *testing.T) {
+	var bodies []map[string]interface{}
+	newClipboardTestServer(t, &bodies)
+	clip := fakeClipboard(t, "")
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "add", "db1", "Name=Task", "--copy=id"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if *clip != "new-id" {
+		t.Errorf("clipboard = %q, want the new row's ID", *clip)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "add", "db1", "Name=Task", "--copy"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if *clip != "https://www.notion.so/new-id" {
+		t.Errorf("clipboard = %q, want the new row's URL", *clip)
+	}
+
+	if _, _, err := executeCommand("db", "add", "db1", "Name=Task", "--copy=title"); err == nil {
+		t.Error("expected an error for --copy=title")
+	}
+}
+
+func TestSearchCopyNeedsOneResult(t *testing.T) {
+	var bodies []map[string]interface{}
+	newClipboardTestServer(t, &bodies)
+	clip := fakeClipboard(t, "unchanged")
+
+	captureStdout(t, func() { executeCommand("search", "many", "--copy") })
+	if *clip != "unchanged" {
+		t.Errorf("copied %q although the search matched two pages", *clip)
+	}
+	captureStdout(t, func() { executeCommand("search", "one", "--copy=id") })
+	if *clip != "p1" {
+		t.Errorf("clipboard = %q, want p1", *clip)
+	}
+}
+
+func TestBlockAppendPaste(t *testing.T) {
+	var bodies []map[string]interface{}
+	newClipboardTestServer(t, &bodies)
+	fakeClipboard(t, "# Pasted\n\nsome text\n")
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("block", "append", "page1", "--paste"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	children, _ := bodies[len(bodies)-1]["children"].([]interface{})
+	if len(children) != 2 {
+		t.Fatalf("appended %d blocks, want 2", len(children))
+	}
+	if first, _ := children[0].(map[string]interface{}); first["type"] != "heading_1" {
+		t.Errorf("first block = %v, want a heading", first["type"])
+	}
+
+	if _, _, err := executeCommand("block", "append", "page1", "text", "--paste"); err == nil {
+		t.Error("expected an error combining --paste with text")
+	}
+	fakeClipboard(t, "  \n")
+	if _, _, err := executeCommand("block", "append", "page1", "--paste"); err == nil {
+		t.Error("expected an error for an empty clipboard")
+	}
+}
+
+func TestDecodeAppleScriptData(t *testing.T) {
+	got, err := decodeAppleScriptData([]byte("«data PNGf89504E47»\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "\x89PNG" {
+		t.Errorf("decoded %q", got)
+	}
+	if _, err := decodeAppleScriptData([]byte("hello")); err == nil {
+		t.Error("expected an error for text output")
+	}
+}
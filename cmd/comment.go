@@ -6,7 +6,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
 	"github.com/spf13/cobra"
@@ -36,15 +35,19 @@ Examples:
 		blockID := util.ResolveID(args[0])
 		all, _ := cmd.Flags().GetBool("all")
 		cursor, _ := cmd.Flags().GetString("cursor")
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var allResults []interface{}
 		currentCursor := cursor
+		var truncated error
 
 		for {
 			result, err := c.ListComments(blockID, 100, currentCursor)
 			if err != nil {
+				if overBudget(err, allResults) {
+					truncated = err
+					break
+				}
 				return fmt.Errorf("list comments: %w", err)
 			}
 
@@ -66,6 +69,10 @@ Examples:
 			currentCursor = nextCursor
 		}
 
+		if truncated != nil && outputFormat == "json" {
+			return renderTruncated(allResults, currentCursor, truncated)
+		}
+
 		if len(allResults) == 0 {
 			fmt.Println("No comments found.")
 			return nil
@@ -98,7 +105,7 @@ Examples:
 			fmt.Println()
 		}
 
-		return nil
+		return truncated
 	},
 }
 
@@ -124,8 +131,7 @@ Examples:
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.AddComment(pageID, text, mentionUserIDs)
 		if err != nil {
@@ -166,7 +172,7 @@ func resolveCommentAddContent(cmd *cobra.Command, args []string) (string, []stri
 	mentionUserIDs, _ := cmd.Flags().GetStringArray("mention-user")
 
 	if len(args) == 2 && flagText != "" {
-		return "", nil, fmt.Errorf("provide comment text either as an argument or with --text, not both")
+		return "", nil, usageError(fmt.Errorf("provide comment text either as an argument or with --text, not both"))
 	}
 
 	text := flagText
@@ -175,7 +181,7 @@ func resolveCommentAddContent(cmd *cobra.Command, args []string) (string, []stri
 	}
 
 	if strings.TrimSpace(text) == "" && len(mentionUserIDs) == 0 {
-		return "", nil, fmt.Errorf("comment content required: provide text or at least one --mention-user")
+		return "", nil, usageError(fmt.Errorf("comment content required: provide text or at least one --mention-user"))
 	}
 
 	resolvedMentionUserIDs := make([]string, len(mentionUserIDs))
@@ -202,8 +208,7 @@ Examples:
 		}
 
 		commentID := args[0]
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.Get("/v1/comments/" + commentID)
 		if err != nil {
@@ -260,8 +265,7 @@ Examples:
 		commentID := args[0]
 		text := args[1]
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		// Get the parent comment to find its discussion_id
 		data, err := c.Get("/v1/comments/" + commentID)
@@ -353,11 +357,10 @@ Examples:
 		mentionUserIDs, _ := cmd.Flags().GetStringArray("mention-user")
 
 		if text == "" && len(mentionUserIDs) == 0 {
-			return fmt.Errorf("--text or --mention-user is required")
+			return usageError(fmt.Errorf("--text or --mention-user is required"))
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.UpdateComment(commentID, text, mentionUserIDs)
 		if err != nil {
@@ -399,8 +402,7 @@ Examples:
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		deleted := 0
 		for _, id := range args {
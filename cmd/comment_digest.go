@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// commentDigestState is what 'notion comment digest' remembers between
+// runs, in the same way as activityState.
+type commentDigestState struct {
+	Watermark time.Time `json:"watermark"`
+	// Seen holds the IDs of comments created since Watermark that were
+	// already reported.
+	Seen map[string]bool `json:"seen"`
+}
+
+var commentDigestCmd = &cobra.Command{
+	Use:   "digest [page-or-database...]",
+	Short: "Show comments made since the last run, grouped by page",
+	Long: `Print the comments made on a set of pages and databases since the
+previous run — an inbox for integrations, which get no notifications.
+
+The pages and databases to scan are the arguments, or else the
+comment_digest setting (comma-separated IDs). A page is scanned with its
+blocks and sub-pages; a database with every row and the blocks in it.
+Notion only lists comments per block, so large trees take a while.
+
+The first run (or --since) looks back the given time: a duration such as
+2h or 7d, or a date. Comment times are to the minute, so the digest keeps
+track of the comments it reported in the last minutes to avoid repeats.
+
+Examples:
+  notion config set comment_digest <page-id>,<database-id>
+  notion comment digest
+  notion comment digest <page-id> --since 7d --peek
+  notion comment digest --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reset, _ := cmd.Flags().GetBool("reset"); reset {
+			if err := os.Remove(commentDigestPath()); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("reset digest: %w", err)
+			}
+			fmt.Println("✓ Comment digest reset")
+			return nil
+		}
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		peek, _ := cmd.Flags().GetBool("peek")
+
+		targets := args
+		if len(targets) == 0 {
+			cfg, _ := config.Load()
+			targets = splitIDList(cfg.Setting("comment_digest"))
+		}
+		if len(targets) == 0 {
+			return usageError(fmt.Errorf("nothing to scan: pass page or database IDs, or 'notion config set comment_digest <id>,<id>'"))
+		}
+
+		now := time.Now().UTC()
+		state := loadCommentDigestState()
+		if sinceFlag != "" || state.Watermark.IsZero() {
+			if sinceFlag == "" {
+				sinceFlag = "24h"
+			}
+			since, err := parseSince(sinceFlag, now)
+			if err != nil {
+				return err
+			}
+			state = commentDigestState{Watermark: since, Seen: map[string]bool{}}
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+
+		next := commentDigestState{Watermark: now.Truncate(time.Minute).Add(-2 * time.Minute), Seen: map[string]bool{}}
+		scan := newCommentScan(c, true)
+		scan.keep = func(comment map[string]interface{}) bool {
+			id, _ := comment["id"].(string)
+			created, _ := comment["created_time"].(string)
+			t, err := time.Parse(time.RFC3339, created)
+			if err != nil || t.Before(state.Watermark) {
+				return false
+			}
+			if !t.Before(next.Watermark) {
+				next.Seen[id] = true
+			}
+			return !state.Seen[id]
+		}
+
+		var threads []*commentThread
+		for _, target := range targets {
+			id := util.ResolveID(target)
+			found, err := digestTarget(scan, id)
+			if err != nil {
+				return err
+			}
+			threads = append(threads, found...)
+		}
+
+		if !peek {
+			if err := saveCommentDigestState(next); err != nil {
+				return err
+			}
+		}
+
+		if outputFormat == "json" {
+			if threads == nil {
+				threads = []*commentThread{}
+			}
+			return render.JSON(map[string]interface{}{"since": state.Watermark, "threads": threads})
+		}
+		since := state.Watermark.Local().Format("2006-01-02 15:04")
+		if len(threads) == 0 {
+			fmt.Printf("No new comments since %s.\n", since)
+			return nil
+		}
+		comments, pages := 0, map[string]bool{}
+		for _, t := range threads {
+			comments += len(t.Comments)
+			pages[t.PageID] = true
+		}
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "# %d new comment(s) on %d page(s) since %s\n\n", comments, len(pages), since)
+		writeCommentThreads(&buf, threads)
+		fmt.Print(buf.String())
+		return nil
+	},
+}
+
+func init() {
+	commentDigestCmd.Flags().String("since", "", "Look back this far instead of to the last run (e.g. 2h, 7d, 2026-03-01)")
+	commentDigestCmd.Flags().Bool("peek", false, "Show the digest without marking the comments as seen")
+	commentDigestCmd.Flags().Bool("reset", false, "Forget what has been seen")
+	commentCmd.AddCommand(commentDigestCmd)
+}
+
+// digestTarget scans a database's rows, or a page tree.
+func digestTarget(scan *commentScan, id string) ([]*commentThread, error) {
+	if _, err := scan.c.GetDatabase(id); err == nil {
+		rows, err := scan.c.QueryDatabaseAll(id, nil)
+		if err != nil {
+			return nil, fmt.Errorf("query database: %w", err)
+		}
+		var threads []*commentThread
+		for _, r := range rows {
+			row, _ := r.(map[string]interface{})
+			rowID, _ := row["id"].(string)
+			found, err := scan.threads(rowID, render.ExtractTitle(row))
+			if err != nil {
+				return threads, err
+			}
+			threads = append(threads, found...)
+		}
+		return threads, nil
+	}
+	page, err := scan.c.GetPage(id)
+	if err != nil {
+		return nil, fmt.Errorf("get page: %w", err)
+	}
+	return scan.threads(id, render.ExtractTitle(page))
+}
+
+// splitIDList splits a comma-separated list of IDs.
+func splitIDList(v string) []string {
+	var ids []string
+	for _, id := range strings.Split(v, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func validateIDList(v string) error {
+	for _, id := range splitIDList(v) {
+		if err := validateID(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commentDigestPath() string {
+	return filepath.Join(config.CacheDir(), "comment-digest.json")
+}
+
+func loadCommentDigestState() commentDigestState {
+	var state commentDigestState
+	if data, err := os.ReadFile(commentDigestPath()); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	if state.Seen == nil {
+		state.Seen = map[string]bool{}
+	}
+	return state
+}
+
+func saveCommentDigestState(state commentDigestState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.CacheDir(), 0700); err != nil {
+		return fmt.Errorf("save digest: %w", err)
+	}
+	if err := os.WriteFile(commentDigestPath(), data, 0600); err != nil {
+		return fmt.Errorf("save digest: %w", err)
+	}
+	return nil
+}
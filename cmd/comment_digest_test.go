@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommentDigestCommand(t *testing.T) {
+	const (
+		pageID = "11111111-1111-1111-1111-111111111111"
+		dbID   = "55555555-5555-5555-5555-555555555555"
+		rowID  = "66666666-6666-6666-6666-666666666666"
+	)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	now := time.Now().UTC()
+	stamp := func(d time.Duration) string { return now.Add(-d).Truncate(time.Minute).Format(time.RFC3339) }
+	comment := func(id, created, text string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "discussion_id": "d-" + id, "created_time": created,
+			"created_by":   map[string]interface{}{"id": "u1"},
+			"display_name": map[string]interface{}{"resolved_name": "Ada"},
+			"rich_text":    []interface{}{map[string]interface{}{"plain_text": text}},
+		}
+	}
+	comments := map[string][]interface{}{
+		pageID: {
+			comment("old", stamp(48*time.Hour), "Stale"),
+			comment("hour", stamp(time.Hour), "An hour ago"),
+			comment("now", stamp(0), "Just now"),
+		},
+		rowID: {comment("row", stamp(2*time.Hour), "On a row")},
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/v1/comments":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": comments[r.URL.Query().Get("block_id")]})
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		case r.URL.Path == "/v1/databases/"+dbID:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": dbID, "properties": map[string]interface{}{}})
+		case r.URL.Path == "/v1/databases/"+dbID+"/query":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"id": rowID, "properties": map[string]interface{}{
+					"Name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Bug 7"}}},
+				}},
+			}})
+		case strings.HasPrefix(r.URL.Path, "/v1/databases/"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not a database"}`))
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": pageID, "properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Launch plan"}}},
+			}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("comment", "digest", pageID, dbID, "--since", "24h"); err != nil {
+			t.Fatalf("comment digest: %v", err)
+		}
+	})
+	if !strings.Contains(out, "3 new comment(s) on 2 page(s)") {
+		t.Errorf("header missing:\n%s", out)
+	}
+	for _, want := range []string{"## Launch plan", "An hour ago", "Just now", "## Bug 7", "On a row"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("digest lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Stale") {
+		t.Errorf("digest includes a comment older than --since:\n%s", out)
+	}
+
+	// The next run starts where this one ended and skips what it reported.
+	out = captureStdout(t, func() {
+		if _, _, err := executeCommand("comment", "digest", pageID, "--since", ""); err != nil {
+			t.Fatalf("comment digest: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "No new comments since") {
+		t.Errorf("second run:\n%s", out)
+	}
+
+	if _, _, err := executeCommand("comment", "digest", "--since", ""); err == nil || !strings.Contains(err.Error(), "comment_digest") {
+		t.Errorf("no targets: err = %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var commentExportCmd = &cobra.Command{
+	Use:   "export <page-id|url>",
+	Short: "Export every comment thread on a page tree",
+	Long: `Collect every discussion on a page: comments on the page itself and on
+each of its blocks, and — unless --no-subpages — on every page nested under
+it. Threads are listed in document order with each comment's author and
+time, as markdown or, with --format json, as data.
+
+Notion only lists comments per block, so this makes one request per block;
+expect a minute or so for a few hundred blocks.
+
+Examples:
+  notion comment export abc123
+  notion comment export abc123 --out review-comments.md
+  notion comment export abc123 --no-subpages --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		noSubpages, _ := cmd.Flags().GetBool("no-subpages")
+		outPath, _ := cmd.Flags().GetString("out")
+		c := newClient(token)
+		pageID := util.ResolveID(args[0])
+
+		page, err := c.GetPage(pageID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		scan := newCommentScan(c, !noSubpages)
+		threads, err := scan.threads(pageID, render.ExtractTitle(page))
+		if err != nil {
+			return err
+		}
+		if threads == nil {
+			threads = []*commentThread{}
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"page_id": pageID, "threads": threads})
+		}
+		markdown := commentThreadsMarkdown(firstNonEmpty(render.ExtractTitle(page), "Untitled"), threads)
+		if outPath != "" {
+			if err := os.WriteFile(outPath, []byte(markdown), 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", outPath, err)
+			}
+			fmt.Printf("✓ Exported %d thread(s) to %s\n", len(threads), outPath)
+			return nil
+		}
+		fmt.Print(markdown)
+		return nil
+	},
+}
+
+func init() {
+	commentExportCmd.Flags().Bool("no-subpages", false, "Only export the page itself, not the pages nested under it")
+	commentExportCmd.Flags().String("out", "", "Write the markdown to this file")
+	commentCmd.AddCommand(commentExportCmd)
+}
+
+// commentThread is one discussion: the comments sharing a discussion_id,
+// oldest first.
+type commentThread struct {
+	DiscussionID string `json:"discussion_id"`
+	PageID       string `json:"page_id"`
+	PageTitle    string `json:"page_title"`
+	// BlockID and BlockText are empty for a discussion on the page itself.
+	BlockID   string            `json:"block_id,omitempty"`
+	BlockText string            `json:"block_text,omitempty"`
+	Comments  []exportedComment `json:"comments"`
+}
+
+type exportedComment struct {
+	ID          string `json:"id"`
+	AuthorID    string `json:"author_id"`
+	Author      string `json:"author"`
+	CreatedTime string `json:"created_time"`
+	Text        string `json:"text"`
+}
+
+// commentScan collects the discussions on pages.
+type commentScan struct {
+	c        *notion.Client
+	subpages bool // descend into child pages
+	// keep, when set, picks the comments to collect.
+	keep    func(comment map[string]interface{}) bool
+	authors map[string]string // user ID → name
+}
+
+func newCommentScan(c *notion.Client, subpages bool) *commentScan {
+	return &commentScan{c: c, subpages: subpages, authors: map[string]string{}}
+}
+
+// threads returns the discussions on the page pageID and its blocks, then
+// on its sub-pages when s.subpages is set.
+func (s *commentScan) threads(pageID, title string) ([]*commentThread, error) {
+	c := s.c
+	type job struct{ id, title string }
+	queue := []job{{pageID, title}}
+	var threads []*commentThread
+	for len(queue) > 0 {
+		j := queue[0]
+		queue = queue[1:]
+		blocks, err := flattenBlockTree(c, j.id)
+		if err != nil {
+			return threads, fmt.Errorf("get content of %s: %w", firstNonEmpty(j.title, j.id), err)
+		}
+		targets := append([]map[string]interface{}{{"id": j.id}}, blocks...)
+		for _, block := range targets {
+			blockID, _ := block["id"].(string)
+			blockType, _ := block["type"].(string)
+			if blockType == "child_page" {
+				if s.subpages {
+					data, _ := block["child_page"].(map[string]interface{})
+					childTitle, _ := data["title"].(string)
+					queue = append(queue, job{blockID, childTitle})
+				}
+				continue
+			}
+			comments, err := listAllComments(c, blockID)
+			if err != nil {
+				return threads, fmt.Errorf("list comments: %w", err)
+			}
+			byDiscussion := map[string]*commentThread{}
+			var order []*commentThread
+			for _, comment := range comments {
+				if s.keep != nil && !s.keep(comment) {
+					continue
+				}
+				discussion, _ := comment["discussion_id"].(string)
+				t := byDiscussion[discussion]
+				if t == nil {
+					t = &commentThread{DiscussionID: discussion, PageID: j.id, PageTitle: firstNonEmpty(j.title, "Untitled")}
+					if blockID != j.id {
+						t.BlockID = blockID
+						t.BlockText = truncateRunes(blockText(block), 120)
+					}
+					byDiscussion[discussion] = t
+					order = append(order, t)
+				}
+				t.Comments = append(t.Comments, exportComment(c, comment, s.authors))
+			}
+			for _, t := range order {
+				sort.SliceStable(t.Comments, func(a, b int) bool { return t.Comments[a].CreatedTime < t.Comments[b].CreatedTime })
+			}
+			threads = append(threads, order...)
+		}
+	}
+	return threads, nil
+}
+
+// listAllComments returns every comment on a block or page.
+func listAllComments(c *notion.Client, blockID string) ([]map[string]interface{}, error) {
+	results, err := notion.Paginate(func(cursor string) (map[string]interface{}, error) {
+		return c.ListComments(blockID, 100, cursor)
+	})
+	var comments []map[string]interface{}
+	for _, r := range results {
+		if comment, ok := r.(map[string]interface{}); ok {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, err
+}
+
+// exportComment flattens a comment, looking up its author's name once per
+// author. Authors the integration can't see are shown by ID.
+func exportComment(c *notion.Client, comment map[string]interface{}, authors map[string]string) exportedComment {
+	id, _ := comment["id"].(string)
+	created, _ := comment["created_time"].(string)
+	rt, _ := comment["rich_text"].([]interface{})
+	by, _ := comment["created_by"].(map[string]interface{})
+	authorID, _ := by["id"].(string)
+
+	name, known := authors[authorID]
+	if !known {
+		display, _ := comment["display_name"].(map[string]interface{})
+		name, _ = display["resolved_name"].(string)
+		if name == "" && authorID != "" {
+			if user, err := c.GetUser(authorID); err == nil {
+				name, _ = user["name"].(string)
+			}
+		}
+		authors[authorID] = name
+	}
+	return exportedComment{
+		ID:          id,
+		AuthorID:    authorID,
+		Author:      firstNonEmpty(name, authorID),
+		CreatedTime: created,
+		Text:        notion.PlainText(rt),
+	}
+}
+
+// commentThreadsMarkdown renders threads grouped by page, each under a
+// quote of the block it is attached to.
+func commentThreadsMarkdown(title string, threads []*commentThread) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Comments on %s\n\n", title)
+	if len(threads) == 0 {
+		buf.WriteString("No comments.\n")
+		return buf.String()
+	}
+	writeCommentThreads(&buf, threads)
+	return buf.String()
+}
+
+// writeCommentThreads writes a "## Page" section per page and each thread
+// in it as a list of comments.
+func writeCommentThreads(buf *bytes.Buffer, threads []*commentThread) {
+	lastPage := ""
+	for _, t := range threads {
+		if t.PageID != lastPage {
+			fmt.Fprintf(buf, "## %s\n\n", t.PageTitle)
+			lastPage = t.PageID
+		}
+		if t.BlockID == "" {
+			buf.WriteString("*On the page*\n\n")
+		} else {
+			fmt.Fprintf(buf, "> %s\n\n", strings.ReplaceAll(firstNonEmpty(t.BlockText, "(block without text)"), "\n", " "))
+		}
+		for _, cm := range t.Comments {
+			fmt.Fprintf(buf, "- **%s** (%s): %s\n", cm.Author, commentTime(cm.CreatedTime), strings.ReplaceAll(cm.Text, "\n", "\n  "))
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// commentTime shows an API timestamp to the minute, in UTC.
+func commentTime(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with "…".
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
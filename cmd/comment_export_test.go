@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCommentExportCommand(t *testing.T) {
+	const (
+		pageID  = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+		blockID = "33333333-3333-3333-3333-333333333333"
+	)
+	comment := func(id, discussion, author, created, text string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "discussion_id": discussion, "created_time": created,
+			"created_by": map[string]interface{}{"object": "user", "id": author},
+			"rich_text":  []interface{}{map[string]interface{}{"plain_text": text}},
+		}
+	}
+	children := map[string][]interface{}{
+		pageID: {
+			map[string]interface{}{"id": blockID, "type": "paragraph", "paragraph": map[string]interface{}{
+				"rich_text": []interface{}{map[string]interface{}{"plain_text": "Ship on Friday"}}}},
+			map[string]interface{}{"id": childID, "type": "child_page", "has_children": true, "child_page": map[string]interface{}{"title": "Notes"}},
+		},
+	}
+	comments := map[string][]interface{}{
+		pageID: {comment("c1", "d1", "u1", "2026-10-01T09:00:00.000Z", "Looks good")},
+		blockID: {
+			comment("c3", "d2", "u1", "2026-10-02T11:30:00.000Z", "Agreed"),
+			comment("c2", "d2", "u2", "2026-10-02T10:00:00.000Z", "Friday is risky"),
+		},
+		childID: {comment("c4", "d3", "u2", "2026-10-03T08:00:00.000Z", "Typo in step 2")},
+	}
+	userLookups := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/v1/comments":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": comments[r.URL.Query().Get("block_id")]})
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": children[id]})
+		case strings.HasPrefix(r.URL.Path, "/v1/users/"):
+			userLookups++
+			if r.URL.Path == "/v1/users/u2" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"no"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "u1", "name": "Ada"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": pageID, "properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Launch plan"}}},
+			}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("comment", "export", pageID); err != nil {
+			t.Fatalf("comment export: %v", err)
+		}
+	})
+	want := "# Comments on Launch plan\n\n" +
+		"## Launch plan\n\n" +
+		"*On the page*\n\n" +
+		"- **Ada** (2026-10-01 09:00 UTC): Looks good\n\n" +
+		"> Ship on Friday\n\n" +
+		"- **u2** (2026-10-02 10:00 UTC): Friday is risky\n" +
+		"- **Ada** (2026-10-02 11:30 UTC): Agreed\n\n" +
+		"## Notes\n\n" +
+		"*On the page*\n\n" +
+		"- **u2** (2026-10-03 08:00 UTC): Typo in step 2\n\n"
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if userLookups != 2 {
+		t.Errorf("looked up users %d times, want once per author", userLookups)
+	}
+
+	out = captureStdout(t, func() {
+		if _, _, err := executeCommand("comment", "export", pageID, "--no-subpages", "--format", "json"); err != nil {
+			t.Fatalf("comment export --format json: %v", err)
+		}
+	})
+	var result struct {
+		Threads []commentThread `json:"threads"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parse %q: %v", out, err)
+	}
+	if len(result.Threads) != 2 || result.Threads[1].BlockID != blockID || len(result.Threads[1].Comments) != 2 {
+		t.Errorf("threads = %+v", result.Threads)
+	}
+}
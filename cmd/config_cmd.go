@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// configSetting describes one key accepted by 'notion config set'.
+type configSetting struct {
+	description string
+	validate    func(string) error
+}
+
+func validateBool(v string) error {
+	switch v {
+	case "true", "false":
+		return nil
+	}
+	return fmt.Errorf("expected true or false, got %q", v)
+}
+
+func validateID(v string) error {
+	if !util.IsID(v) {
+		return fmt.Errorf("expected a Notion ID or URL, got %q", v)
+	}
+	return nil
+}
+
+func validateCount(v string) error {
+	if n, err := strconv.Atoi(v); err != nil || n < 0 {
+		return fmt.Errorf("expected a whole number, got %q", v)
+	}
+	return nil
+}
+
+// configSettings lists every supported setting.
+var configSettings = map[string]configSetting{
+	"changelog_target": {"Page or database 'notion changelog append' writes to", validateID},
+	"clips_parent":     {"Page or database 'notion clip' saves web pages under", validateID},
+	"comment_digest":   {"Pages and databases 'notion comment digest' scans (comma-separated IDs)", validateIDList},
+	"deep_links":       {"Print and open notion:// desktop links instead of web URLs", validateBool},
+	"default_db":       {"Database @db stands for (NOTION_DEFAULT_DB overrides it)", validateID},
+	"email_parent":     {"Page or database 'notion email-to-page' saves emails under", validateID},
+	"front_matter":     {"Front matter keys mapped to properties on import (key=Property,...)", validateFrontMatterMapping},
+	"http2":            {"Use HTTP/2 when the API offers it (default true)", validateBool},
+	"http_compression": {"Ask for gzip-compressed responses (default true)", validateBool},
+	"http_idle_conns":  {"Idle API connections kept open for reuse (0 = default of 16)", validateCount},
+	"http_keepalive":   {"Reuse connections between requests (default true)", validateBool},
+	"inbox":            {"Page or database 'notion capture' files notes into", validateID},
+	"max_requests":     {"Default --max-requests budget per command (0 = no limit)", validateCount},
+	"offline_queue":    {"Queue writes while offline for 'notion flush' (like --queue-offline)", validateBool},
+	"oplog":            {"Record every write in the operation log (default true)", validateBool},
+	"read_only":        {"Refuse every write, like --read-only", validateBool},
+	"relative_dates":   {"Show dates as \"2 hours ago\" in tables (like --relative-dates)", validateBool},
+	"tasks_db":         {"Task database 'notion task rollover' works on", validateID},
+	"timezone":         {"Time zone for times given without an offset (like --tz)", validateTimeZone},
+	"write_allow":      {"Pages and databases writes are limited to, with everything under them (comma-separated IDs)", validateIDList},
+	"write_deny":       {"Pages and databases writes may never touch, with everything under them (comma-separated IDs)", validateIDList},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI settings",
+	Long: `Read and change persistent CLI settings stored in config.json.
+
+Settings:
+  changelog_target  page or database for 'notion changelog append'
+  clips_parent      page or database for 'notion clip'
+  comment_digest    pages and databases for 'notion comment digest' (id,id,...)
+  deep_links        true/false — print and open notion:// desktop-app links
+  default_db        database @db stands for (NOTION_DEFAULT_DB overrides it)
+  email_parent      page or database for 'notion email-to-page'
+  front_matter      front matter keys → properties for 'notion import' (status=Stage,tags=Labels)
+  http2             true/false — use HTTP/2 when available (default true)
+  http_compression  true/false — gzip-compressed responses (default true)
+  http_idle_conns   idle connections kept for reuse (0 = default of 16)
+  http_keepalive    true/false — reuse connections (false for flaky proxies)
+  inbox             page or database for 'notion capture'
+  max_requests      API requests allowed per command (0 = no limit)
+  offline_queue     true/false — queue writes while offline for 'notion flush'
+  oplog             true/false — record writes for 'notion log' (default true)
+  read_only         true/false — refuse every write (see also 'auth login --read-only')
+  relative_dates    true/false — "2 hours ago" instead of timestamps in tables
+  tasks_db          task database for 'notion task rollover'
+  timezone          time zone for times without an offset (Europe/Berlin; default local)
+  write_allow       only these pages/databases and what's under them may be written (id,id,...)
+  write_deny        these pages/databases and what's under them may never be written (id,id,...)
+
+Examples:
+  notion config set deep_links true
+  notion config get deep_links
+  notion config list`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := configSettings[args[0]]; !ok {
+			return usageError(fmt.Errorf("unknown setting %q (see 'notion config list')", args[0]))
+		}
+		cfg, _ := config.Load()
+		fmt.Println(cfg.Setting(args[0]))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		setting, ok := configSettings[key]
+		if !ok {
+			return usageError(fmt.Errorf("unknown setting %q (see 'notion config list')", key))
+		}
+		if err := setting.validate(value); err != nil {
+			return usageError(fmt.Errorf("%s: %w", key, err))
+		}
+
+		cfg, _ := config.Load()
+		if cfg.Settings == nil {
+			cfg.Settings = map[string]string{}
+		}
+		cfg.Settings[key] = value
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("✓ %s = %s\n", key, value)
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Reset a setting to its default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil
+		}
+		delete(cfg.Settings, args[0])
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("✓ %s unset\n", args[0])
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List settings and their values",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _ := config.Load()
+		keys := make([]string, 0, len(configSettings))
+		for k := range configSettings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		if outputFormat == "json" {
+			out := map[string]string{}
+			for _, k := range keys {
+				out[k] = cfg.Setting(k)
+			}
+			return render.JSON(out)
+		}
+		var rows [][]string
+		for _, k := range keys {
+			rows = append(rows, []string{k, cfg.Setting(k), configSettings[k].description})
+		}
+		render.Table([]string{"KEY", "VALUE", "DESCRIPTION"}, rows)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+}
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
+	"time"
 
-	"github.com/4ier/notion-cli/internal/client"
+	"github.com/4ier/notion-cli/internal/config"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
 	"github.com/spf13/cobra"
 )
 
@@ -25,25 +28,37 @@ var dbListCmd = &cobra.Command{
 Examples:
   notion db list
   notion db list --limit 20
-  notion db list --format json`,
+  notion db list --format json
+  notion db list --all-profiles`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		cursor, _ := cmd.Flags().GetString("cursor")
+		all, _ := cmd.Flags().GetBool("all")
+
+		if allProfiles, _ := cmd.Flags().GetBool("all-profiles"); allProfiles {
+			if cursor != "" {
+				return usageError(fmt.Errorf("--cursor cannot be combined with --all-profiles"))
+			}
+			return searchAllProfiles("", "database", limit, all, false)
+		}
+
 		token, err := getToken()
 		if err != nil {
 			return err
 		}
-
-		limit, _ := cmd.Flags().GetInt("limit")
-		cursor, _ := cmd.Flags().GetString("cursor")
-		all, _ := cmd.Flags().GetBool("all")
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var allResults []interface{}
 		currentCursor := cursor
+		var truncated error
 
 		for {
 			result, err := c.Search("", "database", limit, currentCursor)
 			if err != nil {
+				if overBudget(err, allResults) {
+					truncated = err
+					break
+				}
 				return err
 			}
 
@@ -65,6 +80,15 @@ Examples:
 			currentCursor = nextCursor
 		}
 
+		if truncated != nil && outputFormat == "json" {
+			return renderTruncated(allResults, currentCursor, truncated)
+		}
+
+		if porcelain != "" {
+			porcelainObjects(allResults, false)
+			return truncated
+		}
+
 		headers := []string{"TITLE", "ID", "LAST EDITED"}
 		var rows [][]string
 
@@ -76,14 +100,11 @@ Examples:
 			title := render.ExtractTitle(obj)
 			id, _ := obj["id"].(string)
 			lastEdited, _ := obj["last_edited_time"].(string)
-			if len(lastEdited) > 10 {
-				lastEdited = lastEdited[:10]
-			}
-			rows = append(rows, []string{title, id, lastEdited})
+			rows = append(rows, []string{title, id, displayDate(lastEdited)})
 		}
 
 		render.Table(headers, rows)
-		return nil
+		return truncated
 	},
 }
 
@@ -92,9 +113,14 @@ var dbViewCmd = &cobra.Command{
 	Short: "Show database schema",
 	Long: `Display the schema (columns/fields) of a database.
 
+Properties are listed in the order the API gives them, title first; the
+order columns appear in a view isn't exposed by the API. --sort-by name or
+type sorts them instead.
+
 Examples:
   notion db view abc123
-  notion db view https://notion.so/abc123`,
+  notion db view https://notion.so/abc123
+  notion db view abc123 --sort-by type`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -102,14 +128,19 @@ Examples:
 			return err
 		}
 
+		sortBy, _ := cmd.Flags().GetString("sort-by")
 		dbID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
-		db, err := c.GetDatabase(dbID)
+		db, order, err := getDatabaseOrdered(c, dbID)
 		if err != nil {
 			return fmt.Errorf("get database: %w", err)
 		}
+		props, _ := db["properties"].(map[string]interface{})
+		order, err = sortPropertyNames(order, props, sortBy)
+		if err != nil {
+			return err
+		}
 
 		if outputFormat == "json" {
 			return render.JSON(db)
@@ -123,18 +154,17 @@ Examples:
 		render.Field("ID", id)
 		url, _ := db["url"].(string)
 		if url != "" {
-			render.Field("URL", url)
+			render.Field("URL", notionLink(url))
 		}
 		fmt.Println()
 
 		// Show schema
-		props, _ := db["properties"].(map[string]interface{})
 		if len(props) > 0 {
 			headers := []string{"PROPERTY", "TYPE", "OPTIONS"}
 			var rows [][]string
 
-			for name, v := range props {
-				prop, ok := v.(map[string]interface{})
+			for _, name := range order {
+				prop, ok := props[name].(map[string]interface{})
 				if !ok {
 					continue
 				}
@@ -154,9 +184,17 @@ var dbCreateCmd = &cobra.Command{
 	Short: "Create a new database",
 	Long: `Create a database under a parent page.
 
+With --from-csv the schema is inferred from the CSV (numbers, dates,
+checkboxes, URLs, emails, selects, and comma-separated multi-selects) and
+every row is imported. The first column becomes the title unless
+--title-column says otherwise; --title defaults to the file name.
+Repeated column names are numbered, e.g. "Notes (2)".
+
 Examples:
   notion db create <parent-id> --title "Task Tracker"
-  notion db create <parent-id> --title "Tasks" --props "Status:select,Priority:select,Date:date"`,
+  notion db create <parent-id> --title "Tasks" --props "Status:select,Priority:select,Date:date"
+  notion db create <parent-id> --from-csv tasks.csv
+  notion db create <parent-id> --from-csv tasks.csv --title-column Task --dry-run`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -167,13 +205,22 @@ Examples:
 		parentID := util.ResolveID(args[0])
 		title, _ := cmd.Flags().GetString("title")
 		propsFlag, _ := cmd.Flags().GetString("props")
+		fromCSV, _ := cmd.Flags().GetString("from-csv")
 
-		if title == "" {
-			return fmt.Errorf("--title is required")
+		c := newClient(token)
+
+		if fromCSV != "" {
+			if propsFlag != "" {
+				return usageError(fmt.Errorf("--props cannot be combined with --from-csv (the schema is inferred)"))
+			}
+			titleColumn, _ := cmd.Flags().GetString("title-column")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			return createDatabaseFromCSV(c, parentID, fromCSV, title, titleColumn, dryRun)
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		if title == "" {
+			return usageError(fmt.Errorf("--title is required"))
+		}
 
 		// Build properties
 		properties := map[string]interface{}{
@@ -229,7 +276,7 @@ Examples:
 		render.Title("✓", fmt.Sprintf("Created database: %s", title))
 		render.Field("ID", id)
 		if url != "" {
-			render.Field("URL", url)
+			render.Field("URL", notionLink(url))
 		}
 
 		return nil
@@ -255,8 +302,7 @@ Examples:
 		title, _ := cmd.Flags().GetString("title")
 		addProp, _ := cmd.Flags().GetString("add-prop")
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		body := map[string]interface{}{}
 
@@ -282,7 +328,7 @@ Examples:
 		}
 
 		if len(body) == 0 {
-			return fmt.Errorf("nothing to update. Specify --title or --add-prop")
+			return usageError(fmt.Errorf("nothing to update. Specify --title or --add-prop"))
 		}
 
 		data, err := c.Patch("/v1/databases/"+dbID, body)
@@ -312,17 +358,20 @@ The CLI fetches the database schema to determine property types automatically.
 
 Examples:
   notion db add abc123 "Name=My Task" "Status=Todo"
-  notion db add abc123 "Name=Meeting" "Date=2026-03-01" "Priority=High"`,
+  notion db add abc123 "Name=Meeting" "Date=2026-03-01" "Priority=High"
+  notion db add abc123 "Name=Bug" --copy`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkCopyFlag(cmd); err != nil {
+			return err
+		}
 		token, err := getToken()
 		if err != nil {
 			return err
 		}
 
 		dbID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		// Get database schema to determine property types
 		db, err := c.GetDatabase(dbID)
@@ -337,13 +386,13 @@ Examples:
 		for _, kv := range args[1:] {
 			parts := strings.SplitN(kv, "=", 2)
 			if len(parts) != 2 {
-				return fmt.Errorf("invalid property format %q, expected key=value", kv)
+				return invalidInput(fmt.Errorf("invalid property format %q, expected key=value", kv))
 			}
 			key, value := parts[0], parts[1]
 
 			propDef, ok := dbProps[key].(map[string]interface{})
 			if !ok {
-				return fmt.Errorf("property %q not found in database schema", key)
+				return invalidInput(fmt.Errorf("property %q not found in database schema", key))
 			}
 			propType, _ := propDef["type"].(string)
 			properties[key] = buildPropertyValue(propType, value)
@@ -360,26 +409,25 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("add row: %w", err)
 		}
+		rememberCreated(data)
 
-		if outputFormat == "json" {
-			var result map[string]interface{}
-			if err := json.Unmarshal(data, &result); err != nil {
+		var result map[string]interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
 		return fmt.Errorf("parse response: %w", err)
 	}
+		copyResult(cmd, result)
+
+		if outputFormat == "json" {
 			return render.JSON(result)
 		}
 
-		var result map[string]interface{}
-		if err := json.Unmarshal(data, &result); err != nil {
-		return fmt.Errorf("parse response: %w", err)
-	}
 		id, _ := result["id"].(string)
 		url, _ := result["url"].(string)
 
 		render.Title("✓", "Row added")
 		render.Field("ID", id)
 		if url != "" {
-			render.Field("URL", url)
+			render.Field("URL", notionLink(url))
 		}
 
 		return nil
@@ -392,18 +440,53 @@ var dbQueryCmd = &cobra.Command{
 	Long: `Query a database with optional filters and sorting.
 
 Simple filter syntax: property operator value
-Operators: = != > >= < <= ~= (contains)
+Operators: = != > >= < <= ~= (contains) !~= (does not contain)
+Empty values: 'Due=∅' or 'Due is empty'; 'Due!=∅' or 'Due is not empty'.
+Formulas are tested as text; rollups by their result.
+@created and @edited filter and sort on when rows were created or last
+edited.
 
 For complex filters (OR, nesting), use --filter-json with raw Notion API JSON.
 
+Table columns follow the schema order, title first, the same on every
+run. --columns shows only the named properties, in that order, and asks the API
+for just those, which speeds up queries on wide databases (JSON output
+carries only those properties too).
+
+--view queries the rows a view saved in the app shows: its filter applies
+together with any --filter, and its sorts unless --sort is given.
+
+--save-as saves the query's filters, sorts, columns and view under a name
+for this database; --as runs it again, with any --filter added to it and
+any other flag given replacing what was saved. 'db saved' lists and
+deletes them.
+
+--count prints just the number of matching rows. It reads every match,
+but asks for the title alone, so even wide databases count quickly.
+
 Examples:
   notion db query abc123
   notion db query abc123 --filter 'Status=Done'
   notion db query abc123 --filter 'Date>=2026-01-01' --sort 'Date:desc'
   notion db query abc123 --filter 'Status=Done' --filter 'Priority=High'
+  notion db query abc123 --filter '@created>=2026-01-01' --sort '@edited:desc'
+  notion db query abc123 --filter 'Due is empty' --filter 'Title!~=draft'
   notion db query abc123 --filter-json '{"or":[{"property":"Status","status":{"equals":"Done"}},{"property":"Status","status":{"equals":"Cancelled"}}]}'
   notion db query abc123 --limit 5
-  notion db query abc123 --all`,
+  notion db query abc123 --all
+  notion db query abc123 --filter 'Status=Done' --count
+  notion db query abc123 --columns Name,Status,Due
+  notion db query abc123 --all --cursor-file sync.json --format json
+  notion db query abc123 --view 'Open bugs' --all
+  notion db query abc123 --filter 'Sprint=Current' --columns Name,Status --save-as sprint-board
+  notion db query abc123 --as sprint-board
+
+--cursor-file keeps the query's place in a file: the first run reads
+everything, later runs only rows edited since the newest one seen (the
+same minute included, as Notion timestamps are to the minute), and a run
+cut short by --max-requests, --max-duration or a missing --all carries on
+from its cursor next time. Keep the filters and sorts the same between
+runs.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -418,12 +501,31 @@ Examples:
 		limit, _ := cmd.Flags().GetInt("limit")
 		all, _ := cmd.Flags().GetBool("all")
 		cursor, _ := cmd.Flags().GetString("cursor")
+		cursorFile, _ := cmd.Flags().GetString("cursor-file")
+		if cursorFile != "" && cursor != "" {
+			return usageError(fmt.Errorf("--cursor and --cursor-file can't be used together"))
+		}
+		count, _ := cmd.Flags().GetBool("count")
+		if cursorFile != "" && count {
+			return usageError(fmt.Errorf("--count and --cursor-file can't be used together"))
+		}
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		viewArg, _ := cmd.Flags().GetString("view")
+		saved := &config.SavedQuery{Filters: filters, FilterJSON: filterJSON, Sorts: sorts, Columns: columns, View: viewArg}
+		if as, _ := cmd.Flags().GetString("as"); as != "" {
+			q, err := loadSavedQuery(dbID, as)
+			if err != nil {
+				return err
+			}
+			saved = mergeSavedQuery(q, saved)
+			filters, filterJSON, sorts, columns, viewArg = saved.Filters, saved.FilterJSON, saved.Sorts, saved.Columns, saved.View
+		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
-		// Get database schema to determine property types
-		db, err := c.GetDatabase(dbID)
+		// Get database schema to determine property types, and the
+		// column order
+		db, schemaOrder, err := getDatabaseOrdered(c, dbID)
 		if err != nil {
 			return fmt.Errorf("get database schema: %w", err)
 		}
@@ -435,7 +537,7 @@ Examples:
 		if filterJSON != "" {
 			var rawFilter interface{}
 			if err := json.Unmarshal([]byte(filterJSON), &rawFilter); err != nil {
-				return fmt.Errorf("invalid --filter-json: %w", err)
+				return invalidInput(fmt.Errorf("invalid --filter-json: %w", err))
 			}
 			body["filter"] = rawFilter
 		} else if len(filters) > 0 {
@@ -443,7 +545,7 @@ Examples:
 			for _, f := range filters {
 				condition, err := parseFilter(f, dbProps)
 				if err != nil {
-					return fmt.Errorf("invalid filter %q: %w", f, err)
+					return invalidInput(fmt.Errorf("invalid filter %q: %w", f, err))
 				}
 				filterConditions = append(filterConditions, condition)
 			}
@@ -467,20 +569,65 @@ Examples:
 			body["sorts"] = sortList
 		}
 
+		if viewArg != "" {
+			view, err := resolveView(c, dbID, viewArg)
+			if err != nil {
+				return err
+			}
+			applyView(body, view)
+		}
+
+		if saveAs, _ := cmd.Flags().GetString("save-as"); saveAs != "" {
+			if err := saveQuery(dbID, saveAs, saved); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "note: saved as %q; run it again with --as %s\n", saveAs, saveAs)
+		}
+
+		if count {
+			n, err := countRows(c, dbID, body)
+			if err != nil {
+				return fmt.Errorf("query database: %w", err)
+			}
+			if outputFormat == "json" {
+				return render.JSON(map[string]interface{}{"count": n})
+			}
+			fmt.Println(n)
+			return nil
+		}
+
+		columns, columnIDs, err := resolveColumns(dbProps, columns)
+		if err != nil {
+			return err
+		}
+
 		if limit > 0 {
 			body["page_size"] = limit
 		}
 
 		var allResults []interface{}
 		currentCursor := cursor
+		var truncated error
+
+		var state *queryCursor
+		if cursorFile != "" {
+			if state, err = loadQueryCursor(cursorFile, dbID); err != nil {
+				return err
+			}
+			currentCursor = state.apply(body)
+		}
 
 		for {
 			if currentCursor != "" {
 				body["start_cursor"] = currentCursor
 			}
 
-			result, err := c.QueryDatabase(dbID, body)
+			result, err := c.QueryDatabase(dbID, body, columnIDs...)
 			if err != nil {
+				if overBudget(err, allResults) {
+					truncated = err
+					break
+				}
 				return fmt.Errorf("query database: %w", err)
 			}
 
@@ -488,46 +635,58 @@ Examples:
 			allResults = append(allResults, results...)
 
 			hasMore, _ := result["has_more"].(bool)
+			nextCursor, _ := result["next_cursor"].(string)
+			if state != nil {
+				state.advance(results, hasMore, nextCursor)
+			}
 			if !all || !hasMore {
 				if !all && outputFormat == "json" {
+					if state != nil {
+						if err := state.save(cursorFile); err != nil {
+							return err
+						}
+					}
 					return render.JSON(result)
 				}
 				break
 			}
-			nextCursor, _ := result["next_cursor"].(string)
 			currentCursor = nextCursor
 		}
 
+		if state != nil {
+			if err := state.save(cursorFile); err != nil {
+				return err
+			}
+		}
+
+		if truncated != nil && outputFormat == "json" {
+			return renderTruncated(allResults, currentCursor, truncated)
+		}
+
 		if outputFormat == "json" {
 			return render.JSON(map[string]interface{}{"results": allResults, "count": len(allResults)})
 		}
 
+		if porcelain != "" {
+			names := schemaOrder
+			if len(columns) > 0 {
+				names = columns
+			}
+			porcelainRows(allResults, names)
+			return truncated
+		}
+
 		if len(allResults) == 0 {
 			fmt.Println("No results found.")
 			return nil
 		}
 
-		// Collect all property names from schema for column headers
-		propNames := []string{}
-		propTypes := map[string]string{}
-		for name, v := range dbProps {
-			prop, ok := v.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			propType, _ := prop["type"].(string)
-			propNames = append(propNames, name)
-			propTypes[name] = propType
-		}
+		// Columns in schema order, title first, so output diffs cleanly
+		// from run to run
+		sortedNames := schemaOrder
 
-		// Sort: put title first
-		sortedNames := []string{}
-		for _, n := range propNames {
-			if propTypes[n] == "title" {
-				sortedNames = append([]string{n}, sortedNames...)
-			} else {
-				sortedNames = append(sortedNames, n)
-			}
+		if len(columns) > 0 {
+			sortedNames = columns
 		}
 
 		headers := make([]string, len(sortedNames))
@@ -544,7 +703,7 @@ Examples:
 			row := make([]string, len(sortedNames))
 			for i, name := range sortedNames {
 				if prop, ok := pageProps[name].(map[string]interface{}); ok {
-					row[i] = extractPropertyValue(prop)
+					row[i] = displayPropertyValue(prop)
 				}
 			}
 			rows = append(rows, row)
@@ -552,7 +711,7 @@ Examples:
 
 		render.Table(headers, rows)
 		fmt.Printf("\n%d row(s)\n", len(rows))
-		return nil
+		return truncated
 	},
 }
 
@@ -599,7 +758,7 @@ Examples:
 		filePath, _ := cmd.Flags().GetString("file")
 
 		if filePath == "" {
-			return fmt.Errorf("--file is required")
+			return usageError(fmt.Errorf("--file is required"))
 		}
 
 		// Read and parse JSON file
@@ -617,8 +776,8 @@ Examples:
 			return fmt.Errorf("no items in file")
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c, ctx, stop := interruptible(newClient(token))
+		defer stop()
 
 		// Get database schema once
 		db, err := c.GetDatabase(dbID)
@@ -629,8 +788,13 @@ Examples:
 
 		created := 0
 		var errors []string
+		progress := newProgress("db add-bulk", "%d/%d rows created", len(items), os.Stdout)
+		defer progress.stop()
 
 		for i, item := range items {
+			if ctx.Err() != nil {
+				return progress.interrupted(writeRemaining(filePath, dbID, items[i:]))
+			}
 			properties := map[string]interface{}{}
 			for key, value := range item {
 				propDef, ok := dbProps[key].(map[string]interface{})
@@ -651,15 +815,17 @@ Examples:
 
 			_, err := c.Post("/v1/pages", body)
 			if err != nil {
+				if ctx.Err() != nil {
+					return progress.interrupted(writeRemaining(filePath, dbID, items[i:]))
+				}
 				errors = append(errors, fmt.Sprintf("row %d: %v", i+1, err))
+				progress.fail(fmt.Sprintf("row %d", i+1), err)
 				continue
 			}
 			created++
-
-			if outputFormat != "json" {
-				fmt.Printf("\r  %d/%d rows created", created, len(items))
-			}
+			progress.add(1)
 		}
+		progress.finish()
 
 		if outputFormat == "json" {
 			return render.JSON(map[string]interface{}{
@@ -682,19 +848,43 @@ Examples:
 
 var dbExportCmd = &cobra.Command{
 	Use:   "export <db-id|url>",
-	Short: "Export database rows to CSV, JSON, or Markdown",
+	Short: "Export database rows to CSV, JSON, Markdown or SQLite",
 	Long: `Export all rows from a database to various formats.
 
 Formats:
-  csv  - Comma-separated values (default)
-  json - Array of JSON objects
-  md   - Markdown table
+  csv    - Comma-separated values (default)
+  json   - Array of JSON objects
+  md     - Markdown table
+  sqlite - A table in a SQLite file (--output), typed from the schema
+  sql    - The SQL script --format sqlite runs, for loading elsewhere
+
+SQLite tables (named after the database, or --table) have _id, _url,
+_created_time and _last_edited_time columns, then one column per
+property: numbers as REAL, checkboxes as 0/1, dates as ISO text with a
+<Name>_end column for ranges. --refresh fetches only rows edited since the
+last export and upserts them; rows deleted in Notion stay until the next
+full export. The sqlite3 shell must be installed.
+
+--since exports only the rows edited since then: a duration (24h, 7d), a
+date, or last-run for the start of the previous export of the database to
+the same output. A nightly 'db export abc123 --since last-run -o
+changes.csv' writes just the day's changes (all rows the first time); with
+--format sql the rows are upserted instead of recreating the table.
+
+--resolve-rollups recomputes rollups from the related pages (following
+relations past the 25 entries a row carries) and re-reads formulas, so
+the export is self-consistent. It costs a request per related page and
+per formula cell.
 
 Examples:
   notion db export abc123
   notion db export abc123 --format json
   notion db export abc123 --format md --output report.md
-  notion db export abc123 -o data.csv`,
+  notion db export abc123 -o data.csv
+  notion db export abc123 --format sqlite -o notion.db
+  notion db export abc123 --format sqlite -o notion.db --refresh
+  notion db export abc123 --resolve-rollups -o data.csv
+  notion db export abc123 --since last-run -o changes.csv`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -709,60 +899,85 @@ Examples:
 		if format == "" {
 			format = "csv"
 		}
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		if sinceFlag != "" && format == "sqlite" {
+			return usageError(fmt.Errorf("--since doesn't apply to --format sqlite: use --refresh"))
+		}
+		start := exportRunStart(time.Now())
+		since, err := exportSince(sinceFlag, loadDBExportRun(dbID, outputPath), time.Now())
+		if err != nil {
+			return usageError(err)
+		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c, _, stop := interruptible(newClient(token))
+		defer stop()
 
 		// Get database schema
-		db, err := c.GetDatabase(dbID)
+		db, propNames, err := getDatabaseOrdered(c, dbID)
 		if err != nil {
 			return fmt.Errorf("get database: %w", err)
 		}
 		dbProps, _ := db["properties"].(map[string]interface{})
 
-		// Build ordered list of property names (title first)
-		var propNames []string
+		// Property names are in schema order (title first)
 		propTypes := map[string]string{}
-		for name, v := range dbProps {
-			prop, ok := v.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			propType, _ := prop["type"].(string)
-			propTypes[name] = propType
-			if propType == "title" {
-				propNames = append([]string{name}, propNames...)
-			} else {
-				propNames = append(propNames, name)
-			}
+		for _, name := range propNames {
+			prop, _ := dbProps[name].(map[string]interface{})
+			propTypes[name], _ = prop["type"].(string)
 		}
 
-		// Query all rows
-		var allResults []interface{}
-		body := map[string]interface{}{
-			"page_size": 100,
+		var prepare func([]interface{}) error
+		if resolve, _ := cmd.Flags().GetBool("resolve-rollups"); resolve {
+			resolver := newRollupResolver(c)
+			prepare = func(rows []interface{}) error { return resolver.resolveRows(dbProps, rows) }
 		}
-		currentCursor := ""
 
-		for {
-			if currentCursor != "" {
-				body["start_cursor"] = currentCursor
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		if refresh && format != "sqlite" {
+			return usageError(fmt.Errorf("--refresh needs --format sqlite"))
+		}
+		table, _ := cmd.Flags().GetString("table")
+		if table == "" {
+			table = sqliteTableName(render.ExtractTitle(db))
+		}
+		if format == "sqlite" {
+			if err := sqliteOutputPath(outputPath); err != nil {
+				return err
 			}
-
-			result, err := c.QueryDatabase(dbID, body)
+			n, err := exportSQLite(c, dbID, outputPath, table, sqliteColumns(propNames, propTypes), refresh, prepare)
 			if err != nil {
-				return fmt.Errorf("query database: %w", err)
+				return err
 			}
+			if refresh {
+				fmt.Fprintf(os.Stderr, "✓ Refreshed %d changed row(s) in %s (table %s)\n", n, outputPath, table)
+			} else {
+				fmt.Fprintf(os.Stderr, "✓ Exported %d rows to %s (table %s)\n", n, outputPath, table)
+			}
+			return nil
+		}
 
-			results, _ := result["results"].([]interface{})
-			allResults = append(allResults, results...)
-
-			hasMore, _ := result["has_more"].(bool)
-			if !hasMore {
-				break
+		// Query all rows, or those edited since --since
+		var body map[string]interface{}
+		if !since.IsZero() {
+			body = map[string]interface{}{"filter": editedSinceFilter(since)}
+		}
+		progress := newProgress("db export", "", 0, os.Stderr)
+		defer progress.stop()
+		allResults, err := queryAllWithProgress(c, dbID, body, progress)
+		if err != nil {
+			if canceled(err) {
+				return progress.interrupted("nothing was written")
+			}
+			return fmt.Errorf("query database: %w", err)
+		}
+		progress.finish()
+		if prepare != nil {
+			if err := prepare(allResults); err != nil {
+				if canceled(err) {
+					return progress.interrupted("nothing was written")
+				}
+				return err
 			}
-			nextCursor, _ := result["next_cursor"].(string)
-			currentCursor = nextCursor
 		}
 
 		// Prepare output writer
@@ -806,6 +1021,9 @@ Examples:
 			}
 			fmt.Fprintln(output, string(jsonData))
 
+		case "sql":
+			writeSQLiteDump(output, table, dbID, sqliteColumns(propNames, propTypes), allResults, since.IsZero(), nil)
+
 		case "md", "markdown":
 			// Markdown table
 			// Header row
@@ -871,8 +1089,15 @@ Examples:
 			}
 		}
 
+		if err := saveDBExportRun(dbID, outputPath, start); err != nil {
+			return err
+		}
 		if outputPath != "" {
-			fmt.Fprintf(os.Stderr, "✓ Exported %d rows to %s\n", len(allResults), outputPath)
+			if since.IsZero() {
+				fmt.Fprintf(os.Stderr, "✓ Exported %d rows to %s\n", len(allResults), outputPath)
+			} else {
+				fmt.Fprintf(os.Stderr, "✓ Exported %d row(s) changed since %s to %s\n", len(allResults), since.Local().Format("2006-01-02 15:04"), outputPath)
+			}
 		}
 		return nil
 	},
@@ -882,19 +1107,35 @@ func init() {
 	dbListCmd.Flags().IntP("limit", "l", 10, "Maximum results")
 	dbListCmd.Flags().String("cursor", "", "Pagination cursor")
 	dbListCmd.Flags().Bool("all", false, "Fetch all pages of results")
-	dbCreateCmd.Flags().String("title", "", "Database title (required)")
+	dbListCmd.Flags().Bool("all-profiles", false, "List across every saved profile")
+	dbViewCmd.Flags().String("sort-by", "", "Order properties by name or type instead of schema order")
+	dbCreateCmd.Flags().String("title", "", "Database title (required unless --from-csv)")
 	dbCreateCmd.Flags().String("props", "", "Additional properties as name:type,... (e.g. Status:select,Date:date)")
+	dbCreateCmd.Flags().String("from-csv", "", "Infer the schema from a CSV file and import its rows")
+	dbCreateCmd.Flags().String("title-column", "", "CSV column to use as the title property (default: first column)")
+	dbCreateCmd.Flags().Bool("dry-run", false, "With --from-csv, print the inferred schema without creating anything")
 	dbUpdateCmd.Flags().String("title", "", "New database title")
 	dbUpdateCmd.Flags().String("add-prop", "", "Add properties as name:type,... (e.g. Priority:select)")
+	addCopyFlag(dbAddCmd)
 	dbQueryCmd.Flags().StringArrayP("filter", "F", nil, "Filter expression (e.g. 'Status=Done')")
 	dbQueryCmd.Flags().String("filter-json", "", "Raw Notion API filter JSON (for complex OR/nested filters)")
 	dbQueryCmd.Flags().StringArrayP("sort", "s", nil, "Sort expression (e.g. 'Date:desc')")
 	dbQueryCmd.Flags().IntP("limit", "l", 0, "Maximum results per page")
 	dbQueryCmd.Flags().String("cursor", "", "Pagination cursor")
+	dbQueryCmd.Flags().String("cursor-file", "", "Resume from and save the query's place in this file, fetching only new and changed rows")
 	dbQueryCmd.Flags().Bool("all", false, "Fetch all pages of results")
+	dbQueryCmd.Flags().Bool("count", false, "Print only the number of matching rows")
+	dbQueryCmd.Flags().StringSlice("columns", nil, "Properties to fetch and show, in order (e.g. Name,Status)")
+	dbQueryCmd.Flags().String("view", "", "Apply the saved filter and sorts of this view (ID or name, see 'db views list')")
+	dbQueryCmd.Flags().String("as", "", "Run the query saved under this name (see 'db saved list')")
+	dbQueryCmd.Flags().String("save-as", "", "Save this query's filters, sorts, columns and view under a name")
 	dbAddBulkCmd.Flags().String("file", "", "JSON file with rows to create (required)")
-	dbExportCmd.Flags().String("format", "csv", "Output format: csv, json, md")
+	dbExportCmd.Flags().String("format", "csv", "Output format: csv, json, md, sqlite, sql")
+	dbExportCmd.Flags().String("table", "", "Table name for sqlite/sql (default: from the database title)")
+	dbExportCmd.Flags().Bool("resolve-rollups", false, "Recompute rollups from related pages and re-read formulas")
+	dbExportCmd.Flags().Bool("refresh", false, "With --format sqlite, only re-sync rows edited since the last export")
 	dbExportCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+	dbExportCmd.Flags().String("since", "", "Only rows edited since this time (24h, 7d, 2026-03-01) or last-run")
 
 	dbCmd.AddCommand(dbListCmd)
 	dbCmd.AddCommand(dbViewCmd)
@@ -909,6 +1150,10 @@ func init() {
 
 // parseFilter parses a filter expression like "Status=Done" into a Notion filter object.
 func parseFilter(expr string, dbProps map[string]interface{}) (map[string]interface{}, error) {
+	if m := emptyFilterRe.FindStringSubmatch(expr); m != nil && !strings.ContainsAny(m[1], "=<>") {
+		return parseEmptyFilter(strings.TrimSpace(m[1]), m[2] != "", dbProps)
+	}
+
 	// Try operators in order of specificity (longest first)
 	operators := []struct {
 		op     string
@@ -916,9 +1161,9 @@ func parseFilter(expr string, dbProps map[string]interface{}) (map[string]interf
 	}{
 		{">=", "gte"},
 		{"<=", "lte"},
+		{"!~=", "not_contains"},
 		{"!=", "neq"},
 		{"~=", "contains"},
-		{"!~=", "not_contains"},
 		{">", "gt"},
 		{"<", "lt"},
 		{"=", "eq"},
@@ -933,6 +1178,17 @@ func parseFilter(expr string, dbProps map[string]interface{}) (map[string]interf
 		propName := strings.TrimSpace(expr[:idx])
 		value := strings.TrimSpace(expr[idx+len(op.op):])
 
+		if timestamp, ok := timestampProperty(propName); ok {
+			return map[string]interface{}{
+				"timestamp": timestamp,
+				timestamp:   map[string]interface{}{mapTimestampOp(op.notion): notion.DateTimeIn(value, dateLocation)},
+			}, nil
+		}
+
+		if value == "∅" && (op.notion == "eq" || op.notion == "neq") {
+			return parseEmptyFilter(propName, op.notion == "neq", dbProps)
+		}
+
 		// Look up property type
 		propDef, ok := dbProps[propName].(map[string]interface{})
 		if !ok {
@@ -946,6 +1202,74 @@ func parseFilter(expr string, dbProps map[string]interface{}) (map[string]interf
 	return nil, fmt.Errorf("no valid operator found in expression")
 }
 
+// emptyFilterRe matches "Prop is empty" and "Prop is not empty". A left
+// side holding an operator makes it a comparison instead, as in
+// "Notes=this is empty".
+var emptyFilterRe = regexp.MustCompile(`(?i)^(.+?)\s+is\s+(not\s+)?empty$`)
+
+// parseEmptyFilter builds an is_empty (or, negated, is_not_empty) filter.
+// Formulas and rollups are tested through the sub-filter of their result:
+// a formula's result type isn't in the schema, so it is taken as text, and
+// a rollup's follows from its function, show_original and show_unique
+// testing whether any related value is non-empty.
+func parseEmptyFilter(propName string, negate bool, dbProps map[string]interface{}) (map[string]interface{}, error) {
+	propDef, ok := dbProps[propName].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("property %q not found in database", propName)
+	}
+	propType, _ := propDef["type"].(string)
+	op := "is_empty"
+	if negate {
+		op = "is_not_empty"
+	}
+	cond := map[string]interface{}{op: true}
+	switch propType {
+	case "checkbox", "unique_id":
+		return nil, fmt.Errorf("%s properties can't be tested for emptiness", propType)
+	case "formula":
+		resultType := "string"
+		if f, _ := propDef["formula"].(map[string]interface{}); f != nil {
+			if t, _ := f["type"].(string); t != "" {
+				resultType = t
+			}
+		}
+		if resultType == "checkbox" {
+			return nil, fmt.Errorf("checkbox formulas can't be tested for emptiness")
+		}
+		cond = map[string]interface{}{resultType: cond}
+	case "rollup":
+		rollup, _ := propDef["rollup"].(map[string]interface{})
+		switch function, _ := rollup["function"].(string); function {
+		case "show_original", "show_unique":
+			quantifier := "none"
+			if negate {
+				quantifier = "any"
+			}
+			cond = map[string]interface{}{quantifier: map[string]interface{}{"rich_text": map[string]interface{}{"is_not_empty": true}}}
+		case "earliest_date", "latest_date", "date_range":
+			cond = map[string]interface{}{"date": cond}
+		default:
+			cond = map[string]interface{}{"number": cond}
+		}
+	}
+	return map[string]interface{}{
+		"property": propName,
+		propType:   cond,
+	}, nil
+}
+
+// timestampProperty maps the @created and @edited pseudo-properties of the
+// filter and sort syntax to the page timestamps they stand for.
+func timestampProperty(name string) (string, bool) {
+	switch strings.ToLower(name) {
+	case "@created", "@created_time":
+		return "created_time", true
+	case "@edited", "@last_edited", "@last_edited_time":
+		return "last_edited_time", true
+	}
+	return "", false
+}
+
 // buildFilter creates a Notion API filter based on property type and operator.
 func buildFilter(propName, propType, op, value string) map[string]interface{} {
 	filter := map[string]interface{}{
@@ -967,6 +1291,13 @@ func buildFilter(propName, propType, op, value string) map[string]interface{} {
 			}
 		}
 		filter["number"] = map[string]interface{}{numOp: numVal}
+	case "unique_id":
+		// TASK-123 and 123 both filter on the number
+		var idVal interface{} = value
+		if _, n, ok := parseUniqueID(value); ok {
+			idVal = n
+		}
+		filter["unique_id"] = map[string]interface{}{mapNumberOp(op): idVal}
 	case "select":
 		selectOp := "equals"
 		if op == "neq" {
@@ -991,7 +1322,7 @@ func buildFilter(propName, propType, op, value string) map[string]interface{} {
 		if dateType == "date" {
 			dateType = "date"
 		}
-		filter[dateType] = map[string]interface{}{dateOp: value}
+		filter[dateType] = map[string]interface{}{dateOp: notion.DateTimeIn(value, dateLocation)}
 	case "checkbox":
 		boolVal := value == "true" || value == "1" || value == "yes"
 		filter["checkbox"] = map[string]interface{}{"equals": boolVal}
@@ -1053,7 +1384,20 @@ func mapDateOp(op string) string {
 	}
 }
 
-// parseSort parses a sort expression like "Date:desc" into a Notion sort object.
+// mapTimestampOp maps an operator to a created_time / last_edited_time
+// condition, keeping > and < strict.
+func mapTimestampOp(op string) string {
+	switch op {
+	case "gt":
+		return "after"
+	case "lt":
+		return "before"
+	}
+	return mapDateOp(op)
+}
+
+// parseSort parses a sort expression like "Date:desc" into a Notion sort
+// object. @created and @edited sort by the row timestamps.
 func parseSort(expr string) map[string]interface{} {
 	parts := strings.SplitN(expr, ":", 2)
 	propName := strings.TrimSpace(parts[0])
@@ -1064,12 +1408,75 @@ func parseSort(expr string) map[string]interface{} {
 			direction = "descending"
 		}
 	}
+	if timestamp, ok := timestampProperty(propName); ok {
+		return map[string]interface{}{
+			"timestamp": timestamp,
+			"direction": direction,
+		}
+	}
 	return map[string]interface{}{
 		"property":  propName,
 		"direction": direction,
 	}
 }
 
+// countRows counts the rows matching a query body. Rows are fetched 100 at
+// a time with only the title property, the smallest payload the API offers.
+func countRows(c *notion.Client, dbID string, body map[string]interface{}) (int, error) {
+	req := map[string]interface{}{"page_size": 100}
+	if filter, ok := body["filter"]; ok {
+		req["filter"] = filter
+	}
+	n := 0
+	for {
+		result, err := c.QueryDatabase(dbID, req, "title")
+		if err != nil {
+			return n, err
+		}
+		results, _ := result["results"].([]interface{})
+		n += len(results)
+		hasMore, _ := result["has_more"].(bool)
+		nextCursor, _ := result["next_cursor"].(string)
+		if !hasMore || nextCursor == "" {
+			return n, nil
+		}
+		req["start_cursor"] = nextCursor
+	}
+}
+
+// resolveColumns matches --columns names against the database schema
+// (case-insensitively when there is no exact match) and returns the schema
+// names with the property IDs to pass as filter_properties.
+func resolveColumns(dbProps map[string]interface{}, columns []string) ([]string, []string, error) {
+	var names, ids []string
+	for _, col := range columns {
+		col = strings.TrimSpace(col)
+		if col == "" {
+			continue
+		}
+		name := col
+		if _, ok := dbProps[name]; !ok {
+			name = ""
+			for n := range dbProps {
+				if strings.EqualFold(n, col) {
+					name = n
+					break
+				}
+			}
+		}
+		if name == "" {
+			return nil, nil, invalidInput(fmt.Errorf("unknown column %q; properties are: %s", col, strings.Join(sortedKeys(dbProps), ", ")))
+		}
+		prop, _ := dbProps[name].(map[string]interface{})
+		id, _ := prop["id"].(string)
+		names = append(names, name)
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return names, ids, nil
+}
+
 // extractSchemaOptions returns a summary of options for select/multi_select/status properties.
 func extractSchemaOptions(prop map[string]interface{}, propType string) string {
 	var getData func() []interface{}
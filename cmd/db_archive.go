@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var dbArchiveRowsCmd = &cobra.Command{
+	Use:   "archive-rows <db-id|url>",
+	Short: "Archive rows older than a cutoff",
+	Long: `Archive (move to trash) every row of a database older than --before,
+to keep large log-style databases lean.
+
+Age is measured by --by: created_time (the default), last_edited_time,
+or any date property. --before takes a date (2026-01-01) or an age back
+from now (90d, 12w). --filter narrows the rows further, as in 'db query'.
+
+--backup first writes the matched rows (ID, URL and every property) to a
+CSV file, which must not exist yet; nothing is archived if that fails.
+Archived rows can be restored from the trash.
+
+Examples:
+  notion db archive-rows abc123 --before 90d --dry-run
+  notion db archive-rows abc123 --before 2026-01-01 --backup old-logs.csv
+  notion db archive-rows abc123 --before 30d --by Date --filter 'Level=debug'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		before, _ := cmd.Flags().GetString("before")
+		by, _ := cmd.Flags().GetString("by")
+		filters, _ := cmd.Flags().GetStringArray("filter")
+		backup, _ := cmd.Flags().GetString("backup")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if before == "" {
+			return usageError(fmt.Errorf("--before is required (a date like 2026-01-01 or an age like 90d)"))
+		}
+		cutoff, err := parseSince(before, time.Now())
+		if err != nil {
+			return err
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c, ctx, stop := interruptible(newClient(token))
+		defer stop()
+		dbID := util.ResolveID(args[0])
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database schema: %w", err)
+		}
+		schema, _ := db["properties"].(map[string]interface{})
+
+		ageFilter, err := archiveAgeFilter(schema, by, cutoff)
+		if err != nil {
+			return err
+		}
+		conditions := []interface{}{ageFilter}
+		for _, f := range filters {
+			condition, err := parseFilter(f, schema)
+			if err != nil {
+				return invalidInput(fmt.Errorf("invalid filter %q: %w", f, err))
+			}
+			conditions = append(conditions, condition)
+		}
+		body := map[string]interface{}{"filter": ageFilter}
+		if len(conditions) > 1 {
+			body["filter"] = map[string]interface{}{"and": conditions}
+		}
+
+		rows, err := c.QueryDatabaseAll(dbID, body)
+		if err != nil {
+			return fmt.Errorf("query database: %w", err)
+		}
+
+		if dryRun {
+			if outputFormat == "json" {
+				return render.JSON(map[string]interface{}{"matched": len(rows), "before": cutoff.Format(time.RFC3339), "results": rows})
+			}
+			var table [][]string
+			for _, r := range rows {
+				page, _ := r.(map[string]interface{})
+				id, _ := page["id"].(string)
+				table = append(table, []string{render.ExtractTitle(page), id, archiveRowAge(page, by)})
+			}
+			if len(table) > 0 {
+				render.Table([]string{"TITLE", "ID", strings.ToUpper(by)}, table)
+				fmt.Println()
+			}
+			fmt.Printf("%d row(s) older than %s would be archived\n", len(rows), cutoff.Local().Format("2006-01-02 15:04"))
+			return nil
+		}
+
+		if len(rows) > 0 && backup != "" {
+			if err := writeRowsCSV(backup, schema, rows); err != nil {
+				return err
+			}
+		}
+
+		archived := 0
+		var errors []string
+		progress := newProgress("db archive-rows", "%d/%d rows archived", len(rows), os.Stdout)
+		defer progress.stop()
+		const resume = "run the same command again to continue: archived rows no longer match"
+		for _, r := range rows {
+			if ctx.Err() != nil {
+				return progress.interrupted(resume)
+			}
+			page, _ := r.(map[string]interface{})
+			id, _ := page["id"].(string)
+			if _, err := c.Patch("/v1/pages/"+id, map[string]interface{}{"archived": true}); err != nil {
+				if canceled(err) {
+					return progress.interrupted(resume)
+				}
+				errors = append(errors, fmt.Sprintf("%s: %v", id, err))
+				progress.fail(id, err)
+				continue
+			}
+			archived++
+			progress.add(1)
+		}
+		progress.finish()
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{
+				"matched":  len(rows),
+				"archived": archived,
+				"backup":   backup,
+				"errors":   errors,
+			})
+		}
+		if archived > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("✓ %d/%d rows archived", archived, len(rows))
+		if backup != "" && len(rows) > 0 {
+			fmt.Printf(" (backup: %s)", backup)
+		}
+		fmt.Println()
+		for _, e := range errors {
+			fmt.Printf("  ✗ %s\n", e)
+		}
+		return nil
+	},
+}
+
+func init() {
+	dbArchiveRowsCmd.Flags().String("before", "", "Archive rows older than this date or age (2026-01-01, 90d)")
+	dbArchiveRowsCmd.Flags().String("by", "created_time", "What age is measured by: created_time, last_edited_time or a date property")
+	dbArchiveRowsCmd.Flags().StringArrayP("filter", "F", nil, "Extra filter expression (e.g. 'Level=debug')")
+	dbArchiveRowsCmd.Flags().String("backup", "", "Write the rows to this CSV file before archiving them")
+	dbArchiveRowsCmd.Flags().Bool("dry-run", false, "List the rows that would be archived")
+	dbCmd.AddCommand(dbArchiveRowsCmd)
+}
+
+// archiveAgeFilter builds the "older than cutoff" filter for --by: a row
+// timestamp or a date-typed property.
+func archiveAgeFilter(schema map[string]interface{}, by string, cutoff time.Time) (map[string]interface{}, error) {
+	value := cutoff.Format(time.RFC3339)
+	if ts, ok := timestampProperty("@" + strings.TrimPrefix(by, "@")); ok && schema[by] == nil {
+		return map[string]interface{}{"timestamp": ts, ts: map[string]interface{}{"before": value}}, nil
+	}
+	names, _, err := resolveColumns(schema, []string{by})
+	if err != nil {
+		return nil, err
+	}
+	prop, _ := schema[names[0]].(map[string]interface{})
+	propType, _ := prop["type"].(string)
+	switch propType {
+	case "date", "created_time", "last_edited_time":
+		return map[string]interface{}{"property": names[0], propType: map[string]interface{}{"before": value}}, nil
+	}
+	return nil, usageError(fmt.Errorf("--by %s: a %s property has no date to compare", names[0], propType))
+}
+
+// archiveRowAge shows the value a row's age was measured by.
+func archiveRowAge(page map[string]interface{}, by string) string {
+	if ts, ok := timestampProperty("@" + strings.TrimPrefix(by, "@")); ok {
+		if v, _ := page[ts].(string); v != "" {
+			return v
+		}
+	}
+	props, _ := page["properties"].(map[string]interface{})
+	for name, p := range props {
+		if strings.EqualFold(name, by) {
+			prop, _ := p.(map[string]interface{})
+			return extractPropertyValue(prop)
+		}
+	}
+	return ""
+}
+
+// writeRowsCSV writes rows to a new CSV file: id, url, then every
+// property with the title first.
+func writeRowsCSV(path string, schema map[string]interface{}, rows []interface{}) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("create backup: %w", err)
+	}
+	var names []string
+	for _, name := range sortedKeys(schema) {
+		if prop, _ := schema[name].(map[string]interface{}); prop["type"] == "title" {
+			names = append([]string{name}, names...)
+		} else {
+			names = append(names, name)
+		}
+	}
+	w := csv.NewWriter(f)
+	w.Write(append([]string{"id", "url"}, names...))
+	for _, r := range rows {
+		page, _ := r.(map[string]interface{})
+		props, _ := page["properties"].(map[string]interface{})
+		id, _ := page["id"].(string)
+		url, _ := page["url"].(string)
+		record := []string{id, url}
+		for _, name := range names {
+			prop, _ := props[name].(map[string]interface{})
+			record = append(record, extractPropertyValue(prop))
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveAgeFilter(t *testing.T) {
+	schema := map[string]interface{}{
+		"Name": map[string]interface{}{"type": "title"},
+		"When": map[string]interface{}{"type": "date"},
+	}
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for by, want := range map[string]string{
+		"created_time": `{"created_time":{"before":"2026-01-01T00:00:00Z"},"timestamp":"created_time"}`,
+		"@edited":      `{"last_edited_time":{"before":"2026-01-01T00:00:00Z"},"timestamp":"last_edited_time"}`,
+		"when":         `{"date":{"before":"2026-01-01T00:00:00Z"},"property":"When"}`,
+	} {
+		f, err := archiveAgeFilter(schema, by, cutoff)
+		if err != nil {
+			t.Fatalf("%s: %v", by, err)
+		}
+		if got, _ := json.Marshal(f); string(got) != want {
+			t.Errorf("%s:\n got %s\nwant %s", by, got, want)
+		}
+	}
+	if _, err := archiveAgeFilter(schema, "Name", cutoff); err == nil {
+		t.Error("a title property has no date and should fail")
+	}
+}
+
+func TestArchiveRowsWritesBackupFirst(t *testing.T) {
+	var query map[string]interface{}
+	var archived []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name":  map[string]interface{}{"type": "title"},
+				"Level": map[string]interface{}{"type": "select"},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewDecoder(r.Body).Decode(&query)
+			var rows []interface{}
+			for _, id := range []string{"r1", "r2"} {
+				rows = append(rows, map[string]interface{}{"id": id, "url": "https://notion.so/" + id, "properties": map[string]interface{}{
+					"Name":  map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "log, " + id}}},
+					"Level": map[string]interface{}{"type": "select", "select": map[string]interface{}{"name": "debug"}},
+				}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": rows})
+		case r.Method == "PATCH":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["archived"] != true {
+				t.Errorf("patch body = %v", body)
+			}
+			archived = append(archived, strings.TrimPrefix(r.URL.Path, "/v1/pages/"))
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	backup := filepath.Join(t.TempDir(), "old.csv")
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "archive-rows", "66666666666666666666666666666666", "--before", "2026-01-01", "--filter", "Level=debug", "--backup", backup); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Join(archived, ",") != "r1,r2" {
+		t.Errorf("archived %v", archived)
+	}
+	if and, _ := query["filter"].(map[string]interface{})["and"].([]interface{}); len(and) != 2 {
+		t.Errorf("filter = %v", query["filter"])
+	}
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "id,url,Name,Level\nr1,https://notion.so/r1,\"log, r1\",debug\nr2,https://notion.so/r2,\"log, r2\",debug\n"
+	if string(data) != want {
+		t.Errorf("backup:\n%s\nwant\n%s", data, want)
+	}
+
+	// An existing backup file is never overwritten, and nothing is archived.
+	archived = nil
+	if _, _, err := executeCommand("db", "archive-rows", "66666666666666666666666666666666", "--before", "30d", "--backup", backup); err == nil || len(archived) > 0 {
+		t.Errorf("err = %v, archived %v", err, archived)
+	}
+}
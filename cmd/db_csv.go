@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+var (
+	csvDateRe  = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}([T ]\d{2}:\d{2}(:\d{2})?(\.\d+)?(Z|[+-]\d{2}:?\d{2})?)?$`)
+	csvEmailRe = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	// csvThousandsRe matches numbers with thousands separators, like
+	// 1,200 or 12,345.6; other commas (1,2) make a list, not a number.
+	csvThousandsRe = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d+)?$`)
+)
+
+// csvColumn is the inferred Notion property for one CSV column.
+type csvColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// readCSVFile loads a CSV file and returns its header and data rows.
+// Rows shorter than the header are padded so callers can index freely.
+func readCSVFile(path string) ([]string, [][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("%s is empty", path)
+	}
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	rows := records[1:]
+	for i, row := range rows {
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+		rows[i] = row[:len(header)]
+	}
+	return header, rows, nil
+}
+
+// inferCSVSchema picks a Notion property type for every column. The column
+// at titleIdx becomes the title property; the others are detected from
+// their non-empty values, most specific type first. Repeated header names
+// are numbered ("Name (2)"), since property names must be unique.
+func inferCSVSchema(header []string, rows [][]string, titleIdx int) []csvColumn {
+	cols := make([]csvColumn, len(header))
+	taken := map[string]bool{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("Column %d", i+1)
+		}
+		for n, base := 2, name; taken[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s (%d)", base, n)
+		}
+		taken[strings.ToLower(name)] = true
+		cols[i].Name = name
+		if i == titleIdx {
+			cols[i].Type = "title"
+			continue
+		}
+		var values []string
+		for _, row := range rows {
+			if v := strings.TrimSpace(row[i]); v != "" {
+				values = append(values, v)
+			}
+		}
+		cols[i].Type = inferCSVColumnType(values)
+	}
+	return cols
+}
+
+func inferCSVColumnType(values []string) string {
+	if len(values) == 0 {
+		return "rich_text"
+	}
+	all := func(pred func(string) bool) bool {
+		for _, v := range values {
+			if !pred(v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case all(isCSVCheckbox):
+		return "checkbox"
+	case all(func(v string) bool {
+		if csvThousandsRe.MatchString(v) {
+			v = strings.ReplaceAll(v, ",", "")
+		}
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	}):
+		return "number"
+	case all(csvDateRe.MatchString):
+		return "date"
+	case all(func(v string) bool { return strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") }):
+		return "url"
+	case all(csvEmailRe.MatchString):
+		return "email"
+	}
+
+	// Comma-separated short tokens look like tags.
+	distinct := map[string]bool{}
+	hasList := false
+	longest := 0
+	for _, v := range values {
+		parts := strings.Split(v, ",")
+		if len(parts) > 1 {
+			hasList = true
+		}
+		for _, p := range parts {
+			p = strings.TrimSpace(p)
+			distinct[p] = true
+			if len(p) > longest {
+				longest = len(p)
+			}
+		}
+	}
+	if longest > 40 {
+		return "rich_text"
+	}
+	if hasList && len(distinct) <= 100 {
+		return "multi_select"
+	}
+	// Few distinct short values repeated across rows look like a select.
+	if len(distinct) <= 25 && len(distinct)*2 <= len(values) {
+		return "select"
+	}
+	return "rich_text"
+}
+
+func isCSVCheckbox(v string) bool {
+	switch strings.ToLower(v) {
+	case "true", "false", "yes", "no", "✓", "✗", "☑", "☐":
+		return true
+	}
+	return false
+}
+
+// csvCellValue normalizes a raw CSV cell for buildPropertyValue.
+func csvCellValue(propType, raw string) string {
+	v := strings.TrimSpace(raw)
+	switch propType {
+	case "checkbox":
+		switch strings.ToLower(v) {
+		case "true", "yes", "✓", "☑":
+			return "true"
+		}
+		return "false"
+	case "number":
+		return strings.ReplaceAll(v, ",", "")
+	}
+	return v
+}
+
+// csvSchemaProperties converts the inferred columns into the "properties"
+// body of POST /v1/databases.
+func csvSchemaProperties(cols []csvColumn) map[string]interface{} {
+	props := map[string]interface{}{}
+	for _, col := range cols {
+		props[col.Name] = map[string]interface{}{col.Type: map[string]interface{}{}}
+	}
+	return props
+}
+
+// createDatabaseFromCSV implements 'db create --from-csv': infer a schema,
+// create the database under parentID, then add every row.
+func createDatabaseFromCSV(c *notion.Client, parentID, csvPath, title, titleColumn string, dryRun bool) error {
+	header, rows, err := readCSVFile(csvPath)
+	if err != nil {
+		return err
+	}
+
+	titleIdx := 0
+	if titleColumn != "" {
+		titleIdx = -1
+		for i, h := range header {
+			if strings.TrimSpace(h) == titleColumn {
+				titleIdx = i
+				break
+			}
+		}
+		if titleIdx < 0 {
+			return invalidInput(fmt.Errorf("--title-column %q not found in CSV header %v", titleColumn, header))
+		}
+	}
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(csvPath), filepath.Ext(csvPath))
+	}
+
+	cols := inferCSVSchema(header, rows, titleIdx)
+
+	if dryRun {
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"title": title, "columns": cols, "rows": len(rows)})
+		}
+		render.Title("🗃️", title)
+		var tableRows [][]string
+		for _, col := range cols {
+			tableRows = append(tableRows, []string{col.Name, col.Type})
+		}
+		render.Table([]string{"PROPERTY", "TYPE"}, tableRows)
+		fmt.Printf("\n%d row(s) would be imported\n", len(rows))
+		return nil
+	}
+
+	data, err := c.Post("/v1/databases", map[string]interface{}{
+		"parent": map[string]interface{}{"page_id": parentID},
+		"title": []map[string]interface{}{
+			{"text": map[string]interface{}{"content": title}},
+		},
+		"properties": csvSchemaProperties(cols),
+	})
+	if err != nil {
+		return fmt.Errorf("create database: %w", err)
+	}
+	var db map[string]interface{}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	dbID, _ := db["id"].(string)
+	url, _ := db["url"].(string)
+
+	created := 0
+	var errors []string
+	progress := newProgress("db create", "%d/%d rows imported", len(rows), os.Stderr)
+	defer progress.stop()
+	c, ctx, stop := interruptible(c)
+	defer stop()
+	remaining := func(from int) error {
+		var items []map[string]string
+		for _, row := range rows[from:] {
+			item := map[string]string{}
+			for j, col := range cols {
+				if v := csvCellValue(col.Type, row[j]); v != "" {
+					item[col.Name] = v
+				}
+			}
+			items = append(items, item)
+		}
+		return progress.interrupted(fmt.Sprintf("database %s was created\n", dbID) + writeRemaining(csvPath, dbID, items))
+	}
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			return remaining(i)
+		}
+		properties := map[string]interface{}{}
+		for j, col := range cols {
+			v := csvCellValue(col.Type, row[j])
+			if v == "" {
+				continue
+			}
+			properties[col.Name] = buildPropertyValue(col.Type, v)
+		}
+		_, err := c.Post("/v1/pages", map[string]interface{}{
+			"parent":     map[string]interface{}{"database_id": dbID},
+			"properties": properties,
+		})
+		if err != nil {
+			if canceled(err) {
+				return remaining(i)
+			}
+			errors = append(errors, fmt.Sprintf("row %d: %v", i+1, err))
+			progress.fail(fmt.Sprintf("row %d", i+1), err)
+			continue
+		}
+		created++
+		progress.add(1)
+	}
+	progress.finish()
+
+	if outputFormat == "json" {
+		return render.JSON(map[string]interface{}{
+			"id":      dbID,
+			"url":     url,
+			"columns": cols,
+			"created": created,
+			"total":   len(rows),
+			"errors":  errors,
+		})
+	}
+
+	if len(rows) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	render.Title("✓", fmt.Sprintf("Created database: %s", title))
+	render.Field("ID", dbID)
+	if url != "" {
+		render.Field("URL", notionLink(url))
+	}
+	render.Field("Rows", fmt.Sprintf("%d/%d imported", created, len(rows)))
+	for _, e := range errors {
+		fmt.Printf("  ✗ %s\n", e)
+	}
+	return nil
+}
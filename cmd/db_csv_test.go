@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInferCSVColumnType(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"empty", nil, "rich_text"},
+		{"numbers", []string{"1", "2.5", "-3", "1,200", "12,345.5"}, "number"},
+		{"id lists", []string{"1,2", "3", "4,15"}, "multi_select"},
+		{"checkbox", []string{"Yes", "no", "TRUE"}, "checkbox"},
+		{"dates", []string{"2026-01-01", "2026-03-05T14:00:00Z"}, "date"},
+		{"urls", []string{"https://a.example", "http://b.example/x"}, "url"},
+		{"emails", []string{"a@b.co", "c@d.io"}, "email"},
+		{"tags", []string{"go, cli", "notion", "cli"}, "multi_select"},
+		{"select", []string{"Todo", "Done", "Todo", "Done", "Todo"}, "select"},
+		{"free text", []string{"alpha", "beta", "gamma"}, "rich_text"},
+		{"long tokens", []string{"this is a sentence that is definitely longer than forty chars, yes"}, "rich_text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferCSVColumnType(tt.values); got != tt.want {
+				t.Errorf("inferCSVColumnType(%v) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferCSVSchema_TitleColumnAndBlankHeader(t *testing.T) {
+	header := []string{"Points", "Task", ""}
+	rows := [][]string{{"3", "Write docs", ""}, {"5", "Ship", ""}}
+	cols := inferCSVSchema(header, rows, 1)
+	if cols[0].Type != "number" {
+		t.Errorf("Points type = %q, want number", cols[0].Type)
+	}
+	if cols[1].Type != "title" {
+		t.Errorf("Task type = %q, want title", cols[1].Type)
+	}
+	if cols[2].Name != "Column 3" {
+		t.Errorf("blank header name = %q, want Column 3", cols[2].Name)
+	}
+}
+
+func TestInferCSVSchema_DuplicateHeaders(t *testing.T) {
+	header := []string{"Name", "Notes", "notes", "Notes", "Column 5", ""}
+	cols := inferCSVSchema(header, [][]string{{"a", "", "", "", "", ""}}, 0)
+	var names []string
+	for _, col := range cols {
+		names = append(names, col.Name)
+	}
+	want := []string{"Name", "Notes", "notes (2)", "Notes (3)", "Column 5", "Column 6"}
+	if strings.Join(names, "|") != strings.Join(want, "|") {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+	if props := csvSchemaProperties(cols); len(props) != len(header) {
+		t.Errorf("schema has %d properties, want %d", len(props), len(header))
+	}
+}
+
+func TestReadCSVFile_PadsShortRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "in.csv")
+	os.WriteFile(path, []byte("\ufeffName,Status,Due\nA,Todo\nB,Done,2026-01-01\n"), 0o644)
+
+	header, rows, err := readCSVFile(path)
+	if err != nil {
+		t.Fatalf("readCSVFile: %v", err)
+	}
+	if header[0] != "Name" {
+		t.Errorf("BOM not stripped: %q", header[0])
+	}
+	if len(rows) != 2 || len(rows[0]) != 3 || rows[0][2] != "" {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestCSVCellValue(t *testing.T) {
+	if got := csvCellValue("checkbox", "Yes"); got != "true" {
+		t.Errorf("checkbox Yes = %q", got)
+	}
+	if got := csvCellValue("checkbox", "no"); got != "false" {
+		t.Errorf("checkbox no = %q", got)
+	}
+	if got := csvCellValue("number", "1,200"); got != "1200" {
+		t.Errorf("number = %q", got)
+	}
+}
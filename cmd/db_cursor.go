@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// queryCursor is the state 'db query --cursor-file' keeps between runs: a
+// pass over the rows edited since a point in time, resumed from
+// NextCursor until it reaches the end, after which the next pass starts
+// from the newest edit it saw.
+type queryCursor struct {
+	DatabaseID string `json:"database_id"`
+	// Since is the last_edited_time filter of the pass in progress; empty
+	// for the first, full pass.
+	Since string `json:"since,omitempty"`
+	// NextCursor is where the pass in progress carries on; empty once it
+	// has reached the end.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// LastEditedTime is the newest last_edited_time seen so far.
+	LastEditedTime string `json:"last_edited_time,omitempty"`
+}
+
+// loadQueryCursor reads the cursor file at path, or starts a new one when
+// there is none yet. A file kept for another database is refused rather
+// than silently mixing the two.
+func loadQueryCursor(path, dbID string) (*queryCursor, error) {
+	state := &queryCursor{DatabaseID: dbID}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cursor file: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, invalidInput(fmt.Errorf("cursor file %s: %w", path, err))
+	}
+	if plainID(state.DatabaseID) != plainID(dbID) {
+		return nil, invalidInput(fmt.Errorf("cursor file %s belongs to database %s; use another file for this one", path, state.DatabaseID))
+	}
+	state.DatabaseID = dbID
+	return state, nil
+}
+
+// apply narrows a query body to the rows of the pass in progress, ANDing
+// the last_edited_time condition with any filter already there, and
+// returns the cursor to start from.
+func (q *queryCursor) apply(body map[string]interface{}) string {
+	if q.Since != "" {
+		since := map[string]interface{}{
+			"timestamp":        "last_edited_time",
+			"last_edited_time": map[string]interface{}{"on_or_after": q.Since},
+		}
+		if filter, ok := body["filter"]; ok {
+			body["filter"] = map[string]interface{}{"and": []interface{}{filter, since}}
+		} else {
+			body["filter"] = since
+		}
+	}
+	return q.NextCursor
+}
+
+// advance records a page of results. When it was the last one the pass is
+// complete and the next starts from the newest edit seen.
+func (q *queryCursor) advance(results []interface{}, hasMore bool, nextCursor string) {
+	for _, r := range results {
+		page, _ := r.(map[string]interface{})
+		if edited, _ := page["last_edited_time"].(string); edited > q.LastEditedTime {
+			q.LastEditedTime = edited
+		}
+	}
+	if hasMore {
+		q.NextCursor = nextCursor
+		return
+	}
+	q.NextCursor = ""
+	q.Since = q.LastEditedTime
+}
+
+// save writes the state to path, through a temporary file so an
+// interrupted run never leaves a half-written cursor behind.
+func (q *queryCursor) save(path string) error {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("save cursor file: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("save cursor file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("save cursor file: %w", err)
+	}
+	return nil
+}
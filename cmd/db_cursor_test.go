@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDBQueryCursorFile(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "id": "db1", "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"id": "title", "type": "title"},
+			}})
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		row := func(id, edited string) map[string]interface{} {
+			return map[string]interface{}{"object": "page", "id": id, "last_edited_time": edited, "properties": map[string]interface{}{}}
+		}
+		if body["start_cursor"] == nil && body["filter"] == nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{row("a", "2026-10-01T10:00:00.000Z")}, "has_more": true, "next_cursor": "c2"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{row("b", "2026-10-02T09:00:00.000Z")}, "has_more": false})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	file := filepath.Join(t.TempDir(), "sync.json")
+	defer func() { outputFormat = "" }()
+
+	load := func() queryCursor {
+		var q queryCursor
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		json.Unmarshal(data, &q)
+		return q
+	}
+
+	// A first page without --all leaves the cursor to resume from.
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "query", "db1", "--cursor-file", file, "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if q := load(); q.NextCursor != "c2" || q.Since != "" || q.LastEditedTime != "2026-10-01T10:00:00.000Z" {
+		t.Fatalf("after first run: %+v", q)
+	}
+
+	// The next run resumes there and completes the pass.
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "query", "db1", "--cursor-file", file, "--all", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if bodies[1]["start_cursor"] != "c2" {
+		t.Errorf("second run did not resume: %v", bodies[1])
+	}
+	if q := load(); q.NextCursor != "" || q.Since != "2026-10-02T09:00:00.000Z" {
+		t.Fatalf("after second run: %+v", q)
+	}
+
+	// Then only rows edited since the newest one seen, on top of the filters.
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "query", "db1", "--cursor-file", file, "--all", "--filter", "Name~=x", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	filter, _ := json.Marshal(bodies[2]["filter"])
+	want := `{"and":[{"property":"Name","title":{"contains":"x"}},{"last_edited_time":{"on_or_after":"2026-10-02T09:00:00.000Z"},"timestamp":"last_edited_time"}]}`
+	if string(filter) != want {
+		t.Errorf("filter = %s, want %s", filter, want)
+	}
+	if bodies[2]["start_cursor"] != nil {
+		t.Errorf("new pass should start at the beginning: %v", bodies[2])
+	}
+}
+
+func TestQueryCursorOtherDatabase(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sync.json")
+	os.WriteFile(file, []byte(`{"database_id": "11111111111111111111111111111111"}`), 0o644)
+	if _, err := loadQueryCursor(file, "22222222-2222-2222-2222-222222222222"); err == nil {
+		t.Error("cursor file of another database accepted")
+	}
+	if _, err := loadQueryCursor(file, "11111111-1111-1111-1111-111111111111"); err != nil {
+		t.Errorf("same database, dashed: %v", err)
+	}
+}
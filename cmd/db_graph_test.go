@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteMermaidGraph(t *testing.T) {
+	g := &workspaceMap{
+		Nodes: []*mapNode{
+			{ID: "a", Title: `Fix "login"`, Row: true, URL: "https://notion.so/a"},
+			{ID: "b", Title: "Ship", Row: true},
+			{ID: "p", Title: "Auth project"},
+		},
+		Edges: []mapEdge{
+			{From: "a", To: "b", Kind: "relation", Label: "Blocks"},
+			{From: "a", To: "p", Kind: "relation", Label: "Project"},
+		},
+	}
+	var out bytes.Buffer
+	writeMermaidGraph(&out, g, true)
+	want := `graph LR
+  n1["Fix #quot;login#quot;"]
+  n2["Ship"]
+  n3(["Auth project"])
+  n1 -->|"Blocks"| n2
+  n1 -->|"Project"| n3
+  click n1 "https://notion.so/a"
+`
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestDBGraphCommand(t *testing.T) {
+	row := func(id, title string, blocks ...string) map[string]interface{} {
+		var rel []interface{}
+		for _, b := range blocks {
+			rel = append(rel, map[string]interface{}{"id": b})
+		}
+		return map[string]interface{}{"id": id, "properties": map[string]interface{}{
+			"Name":   map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": title}}},
+			"Blocks": map[string]interface{}{"type": "relation", "relation": rel},
+		}}
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/pages/outside":
+			json.NewEncoder(w).Encode(row("outside", "Elsewhere"))
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name":   map[string]interface{}{"type": "title"},
+				"Blocks": map[string]interface{}{"id": "bl", "type": "relation"},
+			}})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{row("r1", "One", "r2", "outside"), row("r2", "Two")}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "graph", "66666666666666666666666666666666"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := `graph LR
+  n1["One"]
+  n2["Two"]
+  n3(["Elsewhere"])
+  n1 -->|"Blocks"| n2
+  n1 -->|"Blocks"| n3
+`
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// issue is a tracker issue normalised from one of the import formats.
+type issue struct {
+	Title     string
+	State     string
+	Labels    []string
+	Assignees []string
+	Number    string
+	Key       string
+	URL       string
+	Body      string
+	Created   time.Time
+	Closed    time.Time
+}
+
+// issueField is a database property an imported issue fills, with the
+// type it is created as when the database lacks it.
+type issueField struct {
+	Name  string
+	Type  string
+	Value func(issue) string
+}
+
+var issueFields = []issueField{
+	{"State", "select", func(i issue) string { return i.State }},
+	{"Labels", "multi_select", func(i issue) string {
+		labels := make([]string, len(i.Labels))
+		for n, l := range i.Labels {
+			// Option names can't contain commas.
+			labels[n] = strings.ReplaceAll(l, ",", " ")
+		}
+		return strings.Join(labels, ",")
+	}},
+	{"Assignee", "rich_text", func(i issue) string { return strings.Join(i.Assignees, ", ") }},
+	{"Number", "number", func(i issue) string { return i.Number }},
+	{"Key", "rich_text", func(i issue) string { return i.Key }},
+	{"URL", "url", func(i issue) string { return i.URL }},
+	{"Created", "date", func(i issue) string { return formatIssueTime(i.Created) }},
+	{"Closed", "date", func(i issue) string { return formatIssueTime(i.Closed) }},
+}
+
+// issueValueTypes are the property types a plain string value can fill.
+var issueValueTypes = map[string]bool{
+	"title": true, "rich_text": true, "select": true, "multi_select": true, "status": true,
+	"number": true, "date": true, "url": true, "email": true,
+}
+
+var dbImportCmd = &cobra.Command{
+	Use:   "import <db-id|url>",
+	Short: "Import issues from GitHub or Jira into a database",
+	Long: `Import an issue tracker export as database rows.
+
+Formats:
+  github-issues  output of 'gh issue list --json ...' or the REST API
+                 issues list (pull requests are skipped)
+  jira           a Jira REST search result ({"issues": [...]})
+
+Each issue becomes a row with its title and, as page content, its body.
+State, Labels, Assignee, Number (GitHub) or Key (Jira), URL, Created and
+Closed fill properties of those names; missing ones are added to the
+database first (select, multi-select, text, number, text, URL and dates).
+Issues whose URL is already in the database are skipped, so an import
+can be re-run to pick up new issues.
+
+Examples:
+  gh issue list --state all --limit 500 \
+    --json number,title,state,labels,assignees,body,url,createdAt,closedAt > issues.json
+  notion db import abc123 --format github-issues --file issues.json
+  notion db import abc123 --format jira --file search.json --dry-run
+  gh issue list --json title,url,state | notion db import abc123 --format github-issues`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		file, _ := cmd.Flags().GetString("file")
+		noBody, _ := cmd.Flags().GetBool("no-body")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		var data []byte
+		var err error
+		if file == "" || file == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("read issues: %w", err)
+		}
+		var issues []issue
+		switch format {
+		case "github-issues":
+			issues, err = parseGitHubIssues(data)
+		case "jira":
+			issues, err = parseJiraIssues(data)
+		default:
+			return usageError(fmt.Errorf("unknown format %q (use github-issues or jira)", format))
+		}
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			return fmt.Errorf("no issues in input")
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c, ctx, stop := interruptible(newClient(token))
+		defer stop()
+		dbID := util.ResolveID(args[0])
+
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database schema: %w", err)
+		}
+		schema, _ := db["properties"].(map[string]interface{})
+		titleProp := ""
+		for name, p := range schema {
+			if prop, _ := p.(map[string]interface{}); prop["type"] == "title" {
+				titleProp = name
+			}
+		}
+		if titleProp == "" {
+			return fmt.Errorf("the database has no title property")
+		}
+
+		mapping, missing := issueMapping(schema, issues)
+		if dryRun {
+			if outputFormat == "json" {
+				return render.JSON(map[string]interface{}{"issues": len(issues), "title": titleProp, "mapping": mapping, "create_properties": missing})
+			}
+			fmt.Printf("%d issue(s) → title in %q\n", len(issues), titleProp)
+			for _, f := range issueFields {
+				if name, ok := mapping[f.Name]; ok {
+					fmt.Printf("  %-9s → %s\n", f.Name, name)
+				}
+			}
+			if len(missing) > 0 {
+				fmt.Printf("would add properties: %s\n", strings.Join(missing, ", "))
+			}
+			return nil
+		}
+
+		if len(missing) > 0 {
+			add := map[string]interface{}{}
+			for _, f := range issueFields {
+				for _, name := range missing {
+					if name == f.Name {
+						add[name] = map[string]interface{}{f.Type: map[string]interface{}{}}
+						schema[name] = map[string]interface{}{"type": f.Type}
+					}
+				}
+			}
+			if _, err := c.Patch("/v1/databases/"+dbID, map[string]interface{}{"properties": add}); err != nil {
+				return fmt.Errorf("add properties: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "note: added properties %s\n", strings.Join(missing, ", "))
+		}
+
+		existing := map[string]bool{}
+		if urlProp := mapping["URL"]; urlProp != "" && !contains(missing, urlProp) {
+			if existing, err = existingPropertyValues(c, dbID, schema, urlProp); err != nil {
+				return err
+			}
+		}
+
+		created, skipped := 0, 0
+		var errors []string
+		pending := 0
+		for _, is := range issues {
+			if is.URL == "" || !existing[is.URL] {
+				pending++
+			}
+		}
+		progress := newProgress("db import", "%d/%d issues imported", pending, os.Stdout)
+		defer progress.stop()
+		const resume = "run the same command again to continue: issues already imported are skipped"
+		for i, is := range issues {
+			if ctx.Err() != nil {
+				return progress.interrupted(resume)
+			}
+			if is.URL != "" && existing[is.URL] {
+				skipped++
+				continue
+			}
+			properties := map[string]interface{}{titleProp: buildPropertyValue("title", is.Title)}
+			for _, f := range issueFields {
+				name, ok := mapping[f.Name]
+				if v := f.Value(is); ok && v != "" {
+					prop, _ := schema[name].(map[string]interface{})
+					propType, _ := prop["type"].(string)
+					properties[name] = buildPropertyValue(propType, v)
+				}
+			}
+			resp, err := c.Post("/v1/pages", map[string]interface{}{
+				"parent":     map[string]interface{}{"database_id": dbID},
+				"properties": properties,
+			})
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("issue %d (%s): %v", i+1, is.Title, err))
+				if canceled(err) {
+					return progress.interrupted(resume)
+				}
+				progress.fail(fmt.Sprintf("issue %d", i+1), err)
+				continue
+			}
+			created++
+			if !noBody && strings.TrimSpace(is.Body) != "" {
+				var page map[string]interface{}
+				json.Unmarshal(resp, &page)
+				id, _ := page["id"].(string)
+				blocks, err := handleOversizedBlocks(parseMarkdownToBlocks(is.Body), oversizeSplit)
+				if err == nil {
+					_, err = appendChildrenBatched(c, id, "", blocks)
+				}
+				if err != nil {
+					errors = append(errors, fmt.Sprintf("issue %d (%s): body: %v", i+1, is.Title, err))
+				}
+			}
+			progress.add(1)
+		}
+		progress.finish()
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{
+				"created": created,
+				"skipped": skipped,
+				"total":   len(issues),
+				"errors":  errors,
+			})
+		}
+		if created > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("✓ %d issue(s) imported", created)
+		if skipped > 0 {
+			fmt.Printf(", %d already in the database", skipped)
+		}
+		fmt.Println()
+		for _, e := range errors {
+			fmt.Printf("  ✗ %s\n", e)
+		}
+		return nil
+	},
+}
+
+func init() {
+	dbImportCmd.Flags().String("format", "github-issues", "Input format: github-issues, jira")
+	dbImportCmd.Flags().String("file", "", "JSON export to import (default: stdin)")
+	dbImportCmd.Flags().Bool("no-body", false, "Don't copy issue bodies into the pages")
+	dbImportCmd.Flags().Bool("dry-run", false, "Show the property mapping without writing anything")
+	dbCmd.AddCommand(dbImportCmd)
+}
+
+// issueMapping matches issue fields to database properties by name
+// (case-insensitively). Fields no issue has a value for are left out; the
+// rest that the database lacks are returned as properties to create.
+func issueMapping(schema map[string]interface{}, issues []issue) (map[string]string, []string) {
+	mapping := map[string]string{}
+	var missing []string
+	for _, f := range issueFields {
+		used := false
+		for _, is := range issues {
+			used = used || f.Value(is) != ""
+		}
+		if !used {
+			continue
+		}
+		found := false
+		for _, name := range sortedKeys(schema) {
+			if !strings.EqualFold(name, f.Name) {
+				continue
+			}
+			found = true
+			prop, _ := schema[name].(map[string]interface{})
+			if propType, _ := prop["type"].(string); issueValueTypes[propType] && propType != "title" {
+				mapping[f.Name] = name
+			} else {
+				fmt.Fprintf(os.Stderr, "note: property %q is a %s, not filled from issue %s\n", name, propType, strings.ToLower(f.Name))
+			}
+			break
+		}
+		if !found {
+			mapping[f.Name] = f.Name
+			missing = append(missing, f.Name)
+		}
+	}
+	return mapping, missing
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// issueState turns GitHub's "OPEN"/"open" into "Open".
+func issueState(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ToLower(s)
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func formatIssueTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseGitHubIssues reads 'gh issue list --json' output (camelCase, state
+// OPEN/CLOSED) or a REST API issues array (snake_case, state open/closed).
+func parseGitHubIssues(data []byte) ([]issue, error) {
+	type login struct {
+		Login string `json:"login"`
+	}
+	var raw []struct {
+		Number      json.Number       `json:"number"`
+		Title       string            `json:"title"`
+		State       string            `json:"state"`
+		Body        string            `json:"body"`
+		URL         string            `json:"url"`
+		HTMLURL     string            `json:"html_url"`
+		Labels      []json.RawMessage `json:"labels"`
+		Assignee    *login            `json:"assignee"`
+		Assignees   []login           `json:"assignees"`
+		CreatedAt   time.Time         `json:"createdAt"`
+		CreatedAtV3 time.Time         `json:"created_at"`
+		ClosedAt    *time.Time        `json:"closedAt"`
+		ClosedAtV3  *time.Time        `json:"closed_at"`
+		PullRequest json.RawMessage   `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse GitHub issues: %w (expected a JSON array of issues)", err)
+	}
+	var issues []issue
+	for _, r := range raw {
+		if len(r.PullRequest) > 0 && string(r.PullRequest) != "null" {
+			continue
+		}
+		is := issue{
+			Title:  r.Title,
+			State:  issueState(r.State),
+			Number: r.Number.String(),
+			URL:    firstNonEmpty(r.HTMLURL, r.URL),
+			Body:   r.Body,
+		}
+		for _, l := range r.Labels {
+			var named struct {
+				Name string `json:"name"`
+			}
+			var name string
+			if json.Unmarshal(l, &named) == nil && named.Name != "" {
+				name = named.Name
+			} else if json.Unmarshal(l, &name) != nil {
+				continue
+			}
+			is.Labels = append(is.Labels, name)
+		}
+		for _, a := range r.Assignees {
+			is.Assignees = append(is.Assignees, a.Login)
+		}
+		if len(is.Assignees) == 0 && r.Assignee != nil && r.Assignee.Login != "" {
+			is.Assignees = []string{r.Assignee.Login}
+		}
+		is.Created = r.CreatedAt
+		if is.Created.IsZero() {
+			is.Created = r.CreatedAtV3
+		}
+		for _, t := range []*time.Time{r.ClosedAt, r.ClosedAtV3} {
+			if t != nil && !t.IsZero() {
+				is.Closed = *t
+			}
+		}
+		if strings.TrimSpace(is.Title) == "" {
+			is.Title = "Issue #" + is.Number
+		}
+		issues = append(issues, is)
+	}
+	return issues, nil
+}
+
+// parseJiraIssues reads a Jira REST search result, or a bare array of its
+// issues. Descriptions may be wiki text (API v2) or Atlassian Document
+// Format (API v3), which is flattened to paragraphs.
+func parseJiraIssues(data []byte) ([]issue, error) {
+	type jiraIssue struct {
+		Key    string `json:"key"`
+		Self   string `json:"self"`
+		Fields struct {
+			Summary     string          `json:"summary"`
+			Description json.RawMessage `json:"description"`
+			Labels      []string        `json:"labels"`
+			Status      struct {
+				Name string `json:"name"`
+			} `json:"status"`
+			Assignee *struct {
+				DisplayName string `json:"displayName"`
+			} `json:"assignee"`
+			Created        string `json:"created"`
+			ResolutionDate string `json:"resolutiondate"`
+		} `json:"fields"`
+	}
+	var raw []jiraIssue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var result struct {
+			Issues []jiraIssue `json:"issues"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("parse Jira issues: %w (expected a search result with \"issues\")", err)
+		}
+		raw = result.Issues
+	}
+	var issues []issue
+	for _, r := range raw {
+		is := issue{
+			Title:   firstNonEmpty(r.Fields.Summary, r.Key),
+			State:   r.Fields.Status.Name,
+			Labels:  r.Fields.Labels,
+			Key:     r.Key,
+			Created: parseJiraTime(r.Fields.Created),
+			Closed:  parseJiraTime(r.Fields.ResolutionDate),
+		}
+		if r.Fields.Assignee != nil && r.Fields.Assignee.DisplayName != "" {
+			is.Assignees = []string{r.Fields.Assignee.DisplayName}
+		}
+		if u, err := url.Parse(r.Self); err == nil && u.Host != "" && r.Key != "" {
+			is.URL = u.Scheme + "://" + u.Host + "/browse/" + r.Key
+		}
+		var text string
+		if json.Unmarshal(r.Fields.Description, &text) == nil {
+			is.Body = text
+		} else {
+			var doc map[string]interface{}
+			if json.Unmarshal(r.Fields.Description, &doc) == nil {
+				is.Body = strings.TrimSpace(adfText(doc))
+			}
+		}
+		issues = append(issues, is)
+	}
+	return issues, nil
+}
+
+// parseJiraTime parses Jira's timestamps ("2024-01-02T10:00:00.000+0000").
+func parseJiraTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04:05.000-0700", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// adfText flattens an Atlassian Document Format node to text, one
+// blank-line-separated paragraph per block node.
+func adfText(node map[string]interface{}) string {
+	if t, _ := node["text"].(string); t != "" {
+		return t
+	}
+	if node["type"] == "hardBreak" {
+		return "\n"
+	}
+	var b strings.Builder
+	content, _ := node["content"].([]interface{})
+	for _, c := range content {
+		if child, ok := c.(map[string]interface{}); ok {
+			b.WriteString(adfText(child))
+		}
+	}
+	switch node["type"] {
+	case "paragraph", "heading", "codeBlock", "blockquote", "listItem":
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
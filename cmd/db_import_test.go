@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseGitHubIssues(t *testing.T) {
+	ghCLI := `[{"number": 7, "title": "Crash on start", "state": "CLOSED", "body": "Steps:\n\n1. run",
+	  "url": "https://github.com/o/r/issues/7", "labels": [{"name": "bug"}, {"name": "p1, urgent"}],
+	  "assignees": [{"login": "ada"}], "createdAt": "2025-01-02T03:04:05Z", "closedAt": "2025-01-03T00:00:00Z"}]`
+	issues, err := parseGitHubIssues([]byte(ghCLI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is := issues[0]
+	if is.State != "Closed" || is.Number != "7" || is.URL != "https://github.com/o/r/issues/7" || is.Closed.IsZero() {
+		t.Errorf("issue = %+v", is)
+	}
+	if labels := issueFields[1].Value(is); labels != "bug,p1  urgent" {
+		t.Errorf("labels = %q", labels)
+	}
+
+	rest := `[{"number": 1, "title": "A", "state": "open", "url": "https://api.github.com/repos/o/r/issues/1",
+	  "html_url": "https://github.com/o/r/issues/1", "assignee": {"login": "bob"}, "labels": [],
+	  "created_at": "2025-01-02T03:04:05Z", "closed_at": null},
+	 {"number": 2, "title": "A PR", "state": "open", "pull_request": {"url": "x"}}]`
+	issues, err = parseGitHubIssues([]byte(rest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want the pull request skipped", len(issues))
+	}
+	if is := issues[0]; is.URL != "https://github.com/o/r/issues/1" || is.Assignees[0] != "bob" || is.Created.IsZero() || !is.Closed.IsZero() {
+		t.Errorf("issue = %+v", is)
+	}
+}
+
+func TestParseJiraIssues(t *testing.T) {
+	search := `{"issues": [{"key": "OPS-12", "self": "https://acme.atlassian.net/rest/api/3/issue/10012",
+	  "fields": {"summary": "Rotate keys", "labels": ["security"], "status": {"name": "In Progress"},
+	  "assignee": {"displayName": "Ada L"}, "created": "2025-02-01T10:00:00.000+0100",
+	  "description": {"type": "doc", "content": [
+	    {"type": "paragraph", "content": [{"type": "text", "text": "First"}, {"type": "hardBreak"}, {"type": "text", "text": "line"}]},
+	    {"type": "paragraph", "content": [{"type": "text", "text": "Second"}]}]}}}]}`
+	issues, err := parseJiraIssues([]byte(search))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is := issues[0]
+	if is.Title != "Rotate keys" || is.State != "In Progress" || is.Key != "OPS-12" || is.Created.IsZero() {
+		t.Errorf("issue = %+v", is)
+	}
+	if is.URL != "https://acme.atlassian.net/browse/OPS-12" {
+		t.Errorf("URL = %q", is.URL)
+	}
+	if is.Body != "First\nline\n\nSecond" {
+		t.Errorf("body = %q", is.Body)
+	}
+}
+
+func TestDBImportCreatesPropertiesAndSkipsExisting(t *testing.T) {
+	var mu sync.Mutex
+	var patched map[string]interface{}
+	var createdTitles []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database",
+				"id":     "issues",
+				"properties": map[string]interface{}{
+					"Name": map[string]interface{}{"id": "title", "type": "title"},
+					"url":  map[string]interface{}{"id": "u1", "type": "url"},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"properties": map[string]interface{}{
+					"url": map[string]interface{}{"type": "url", "url": "https://github.com/o/r/issues/1"},
+				}},
+			}})
+		case r.Method == "PATCH" && strings.HasPrefix(r.URL.Path, "/v1/databases/"):
+			patched = body
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database"})
+		case r.Method == "POST":
+			props := body["properties"].(map[string]interface{})
+			title := props["Name"].(map[string]interface{})["title"].([]interface{})[0].(map[string]interface{})
+			createdTitles = append(createdTitles, title["text"].(map[string]interface{})["content"].(string))
+			if props["State"] == nil || props["url"] == nil {
+				t.Errorf("properties = %v", props)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "p"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	path := filepath.Join(t.TempDir(), "issues.json")
+	os.WriteFile(path, []byte(`[
+	  {"number": 1, "title": "Old", "state": "OPEN", "url": "https://github.com/o/r/issues/1"},
+	  {"number": 2, "title": "New", "state": "OPEN", "url": "https://github.com/o/r/issues/2", "body": "Details"}]`), 0o644)
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "import", "55555555555555555555555555555555", "--file", path); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	add, _ := patched["properties"].(map[string]interface{})
+	if add["State"] == nil || add["Number"] == nil || add["URL"] != nil {
+		t.Errorf("added properties = %v, want State and Number but not URL (matched 'url')", add)
+	}
+	if strings.Join(createdTitles, ",") != "New" {
+		t.Errorf("created %v, want only the issue not yet imported", createdTitles)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// getDatabaseOrdered fetches a database along with its property names in
+// schema order (see propertyOrder).
+func getDatabaseOrdered(c *notion.Client, dbID string) (map[string]interface{}, []string, error) {
+	data, err := c.Get("/v1/databases/" + dbID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var db map[string]interface{}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return nil, nil, fmt.Errorf("parse response: %w", err)
+	}
+	return db, propertyOrder(data), nil
+}
+
+// propertyOrder returns the names in the "properties" object of an API
+// response in the order the API lists them, the title property first.
+// The API doesn't expose the order columns are shown in a view, but its
+// own order is stable, unlike iterating over a decoded map.
+func propertyOrder(data []byte) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if key != "properties" {
+			var skip json.RawMessage
+			if dec.Decode(&skip) != nil {
+				return nil
+			}
+			continue
+		}
+		if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+			return nil
+		}
+		var names []string
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				return names
+			}
+			name, _ := t.(string)
+			var prop struct {
+				Type string `json:"type"`
+			}
+			if dec.Decode(&prop) != nil {
+				return names
+			}
+			if prop.Type == "title" {
+				names = append([]string{name}, names...)
+			} else {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// sortPropertyNames orders names for display: as given (schema order),
+// or by "name" or by "type" (then name), the title first either way.
+func sortPropertyNames(names []string, schema map[string]interface{}, by string) ([]string, error) {
+	typeOf := func(name string) string {
+		prop, _ := schema[name].(map[string]interface{})
+		t, _ := prop["type"].(string)
+		return t
+	}
+	out := append([]string(nil), names...)
+	switch by {
+	case "", "schema":
+		return out, nil
+	case "name":
+		sort.SliceStable(out, func(i, j int) bool {
+			if ti, tj := typeOf(out[i]) == "title", typeOf(out[j]) == "title"; ti != tj {
+				return ti
+			}
+			return out[i] < out[j]
+		})
+	case "type":
+		sort.SliceStable(out, func(i, j int) bool {
+			if ti, tj := typeOf(out[i]) == "title", typeOf(out[j]) == "title"; ti != tj {
+				return ti
+			}
+			if typeOf(out[i]) != typeOf(out[j]) {
+				return typeOf(out[i]) < typeOf(out[j])
+			}
+			return out[i] < out[j]
+		})
+	default:
+		return nil, usageError(fmt.Errorf("--sort-by must be schema, name or type, got %q", by))
+	}
+	return out, nil
+}
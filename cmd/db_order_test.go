@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const orderedSchema = `{"object":"database","id":"db","title":[],"properties":{
+	"Status":{"id":"a","type":"select","select":{"options":[{"name":"Todo"}]}},
+	"Due":{"id":"b","type":"date","date":{}},
+	"Name":{"id":"title","type":"title","title":{}},
+	"Assignee":{"id":"c","type":"people","people":{}}
+}}`
+
+func TestPropertyOrder(t *testing.T) {
+	got := propertyOrder([]byte(orderedSchema))
+	want := []string{"Name", "Status", "Due", "Assignee"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("propertyOrder = %v, want %v", got, want)
+	}
+
+	var db map[string]interface{}
+	json.Unmarshal([]byte(orderedSchema), &db)
+	schema, _ := db["properties"].(map[string]interface{})
+	for by, want := range map[string][]string{
+		"name": {"Name", "Assignee", "Due", "Status"},
+		"type": {"Name", "Due", "Assignee", "Status"},
+	} {
+		got, err := sortPropertyNames(got, schema, by)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("--sort-by %s = %v (%v), want %v", by, got, err, want)
+		}
+	}
+	if _, err := sortPropertyNames(got, schema, "size"); err == nil {
+		t.Error("expected an error for an unknown --sort-by")
+	}
+}
+
+func TestDBViewSchemaOrder(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(orderedSchema))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	// The same order every time, not Go's map order.
+	for i := 0; i < 5; i++ {
+		out := captureStdout(t, func() {
+			if _, _, err := executeCommand("db", "view", "11111111-1111-1111-1111-111111111111"); err != nil {
+				t.Fatalf("db view: %v", err)
+			}
+		})
+		name, status, due, assignee := strings.Index(out, "Name"), strings.Index(out, "Status"), strings.Index(out, "Due"), strings.Index(out, "Assignee")
+		if !(name < status && status < due && due < assignee) {
+			t.Fatalf("properties out of schema order:\n%s", out)
+		}
+	}
+}
+
+func TestDBQueryColumnsInSchemaOrder(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			w.Write([]byte(`{"results":[{"object":"page","id":"p1","properties":{
+				"Assignee":{"type":"people","people":[]},
+				"Due":{"type":"date","date":{"start":"2026-03-01"}},
+				"Status":{"type":"select","select":{"name":"Todo"}},
+				"Name":{"type":"title","title":[{"plain_text":"Ship it"}]}
+			}}],"has_more":false}`))
+			return
+		}
+		w.Write([]byte(orderedSchema))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	for i := 0; i < 5; i++ {
+		out := captureStdout(t, func() {
+			if _, _, err := executeCommand("db", "query", "11111111-1111-1111-1111-111111111111"); err != nil {
+				t.Fatalf("db query: %v", err)
+			}
+		})
+		name, status, due := strings.Index(out, "Ship it"), strings.Index(out, "Todo"), strings.Index(out, "2026-03-01")
+		if name < 0 || !(name < status && status < due) {
+			t.Fatalf("columns out of schema order:\n%s", out)
+		}
+	}
+}
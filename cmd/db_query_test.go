@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newQueryTestServer serves a database schema and a two-page query result,
+// recording the query requests it receives.
+func newQueryTestServer(t *testing.T, queries *[]*http.Request, bodies *[]map[string]interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database",
+				"id":     "db1",
+				"properties": map[string]interface{}{
+					"Name":   map[string]interface{}{"id": "title", "type": "title"},
+					"Status": map[string]interface{}{"id": "s%3Ab", "type": "select"},
+					"Notes":  map[string]interface{}{"id": "n1", "type": "rich_text"},
+				},
+			})
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		*queries = append(*queries, r)
+		*bodies = append(*bodies, body)
+		row := func(id string) map[string]interface{} {
+			return map[string]interface{}{"object": "page", "id": id, "properties": map[string]interface{}{}}
+		}
+		if body["start_cursor"] == nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{row("a"), row("b")}, "has_more": true, "next_cursor": "c2"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{row("c")}, "has_more": false})
+	}))
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	return server
+}
+
+func TestDBQueryCount(t *testing.T) {
+	var queries []*http.Request
+	var bodies []map[string]interface{}
+	server := newQueryTestServer(t, &queries, &bodies)
+	defer server.Close()
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand("db", "query", "db1", "--filter", "Status=Done", "--sort", "Name:desc", "--count")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "3" {
+		t.Errorf("output = %q, want 3", out)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("queries = %d, want 2", len(queries))
+	}
+	if got := queries[0].URL.Query()["filter_properties"]; len(got) != 1 || got[0] != "title" {
+		t.Errorf("filter_properties = %v, want [title]", got)
+	}
+	if bodies[0]["filter"] == nil || bodies[0]["sorts"] != nil || bodies[0]["page_size"] != float64(100) {
+		t.Errorf("count query body = %v", bodies[0])
+	}
+}
+
+func TestDBQueryColumns(t *testing.T) {
+	var queries []*http.Request
+	var bodies []map[string]interface{}
+	server := newQueryTestServer(t, &queries, &bodies)
+	defer server.Close()
+
+	var err error
+	captureStdout(t, func() {
+		_, _, err = executeCommand("db", "query", "db1", "--columns", "status,Name")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := queries[0].URL.Query()["filter_properties"]; strings.Join(got, ",") != "s%3Ab,title" {
+		t.Errorf("filter_properties = %v", got)
+	}
+
+	_, _, err = executeCommand("db", "query", "db1", "--columns", "Nope")
+	if err == nil || !strings.Contains(err.Error(), `unknown column "Nope"`) {
+		t.Errorf("unknown column err = %v", err)
+	}
+}
+
+func TestResolveColumns(t *testing.T) {
+	props := map[string]interface{}{
+		"Name":   map[string]interface{}{"id": "title"},
+		"Status": map[string]interface{}{"id": "abc"},
+	}
+	names, ids, err := resolveColumns(props, []string{" status", "Name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "Status,Name" || strings.Join(ids, ",") != "abc,title" {
+		t.Errorf("resolveColumns = %v, %v", names, ids)
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var dbSavedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Manage queries saved with 'db query --save-as'",
+	Long: `Queries saved with 'db query <db> --save-as <name>' keep their filters,
+sorts, columns and view in the config file, per database, and run again
+with 'db query <db> --as <name>'.
+
+Examples:
+  notion db query abc123 --filter 'Sprint=Current' --sort 'Priority' --columns Name,Status --save-as sprint-board
+  notion db query abc123 --as sprint-board
+  notion db saved list
+  notion db saved delete abc123 sprint-board`,
+}
+
+var dbSavedListCmd = &cobra.Command{
+	Use:   "list [db-id|url]",
+	Short: "List saved queries, of every database or of one",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _ := config.Load()
+		queries := cfg.Queries
+		if len(args) == 1 {
+			db := plainID(util.ResolveID(args[0]))
+			queries = map[string]map[string]*config.SavedQuery{db: cfg.Queries[db]}
+		}
+		if outputFormat == "json" {
+			out := map[string]map[string]*config.SavedQuery{}
+			for db, saved := range queries {
+				if len(saved) > 0 {
+					out[util.ResolveID(db)] = saved
+				}
+			}
+			return render.JSON(out)
+		}
+
+		var rows [][]string
+		for _, db := range sortedKeys(queries) {
+			saved := queries[db]
+			for _, name := range sortedKeys(saved) {
+				rows = append(rows, []string{util.ResolveID(db), name, describeSavedQuery(saved[name])})
+			}
+		}
+		if len(rows) == 0 {
+			fmt.Println("No saved queries.")
+			return nil
+		}
+		render.Table([]string{"DATABASE", "NAME", "QUERY"}, rows)
+		return nil
+	},
+}
+
+var dbSavedDeleteCmd = &cobra.Command{
+	Use:   "delete <db-id|url> <name>",
+	Short: "Delete a saved query",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, name := plainID(util.ResolveID(args[0])), args[1]
+		cfg, _ := config.Load()
+		if _, ok := cfg.Queries[db][name]; !ok {
+			return notFoundSavedQuery(cfg, db, name)
+		}
+		delete(cfg.Queries[db], name)
+		if len(cfg.Queries[db]) == 0 {
+			delete(cfg.Queries, db)
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Printf("✓ Saved query %q deleted\n", name)
+		return nil
+	},
+}
+
+func init() {
+	dbSavedCmd.AddCommand(dbSavedListCmd)
+	dbSavedCmd.AddCommand(dbSavedDeleteCmd)
+	dbCmd.AddCommand(dbSavedCmd)
+}
+
+// loadSavedQuery returns the query saved for dbID under name.
+func loadSavedQuery(dbID, name string) (*config.SavedQuery, error) {
+	cfg, _ := config.Load()
+	q, ok := cfg.Queries[plainID(dbID)][name]
+	if !ok {
+		return nil, notFoundSavedQuery(cfg, plainID(dbID), name)
+	}
+	return q, nil
+}
+
+func notFoundSavedQuery(cfg *config.Config, db, name string) error {
+	names := sortedKeys(cfg.Queries[db])
+	if len(names) == 0 {
+		return usageError(fmt.Errorf("no query %q saved for this database (save one with 'db query --save-as')", name))
+	}
+	return usageError(fmt.Errorf("no query %q saved for this database (saved: %s)", name, strings.Join(names, ", ")))
+}
+
+// saveQuery saves q for dbID under name, replacing any query of that name.
+func saveQuery(dbID, name string, q *config.SavedQuery) error {
+	cfg, _ := config.Load()
+	if cfg.Queries == nil {
+		cfg.Queries = map[string]map[string]*config.SavedQuery{}
+	}
+	db := plainID(dbID)
+	if cfg.Queries[db] == nil {
+		cfg.Queries[db] = map[string]*config.SavedQuery{}
+	}
+	cfg.Queries[db][name] = q
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	return nil
+}
+
+// mergeSavedQuery runs saved with the flags given alongside --as: their
+// filters are added to the saved ones, and their raw filter, sorts,
+// columns and view replace the saved ones.
+func mergeSavedQuery(saved, flags *config.SavedQuery) *config.SavedQuery {
+	q := *saved
+	q.Filters = append(append([]string{}, saved.Filters...), flags.Filters...)
+	if flags.FilterJSON != "" {
+		q.FilterJSON = flags.FilterJSON
+	}
+	if len(flags.Sorts) > 0 {
+		q.Sorts = flags.Sorts
+	}
+	if len(flags.Columns) > 0 {
+		q.Columns = flags.Columns
+	}
+	if flags.View != "" {
+		q.View = flags.View
+	}
+	return &q
+}
+
+// describeSavedQuery shows a saved query as the flags that make it.
+func describeSavedQuery(q *config.SavedQuery) string {
+	var parts []string
+	for _, f := range q.Filters {
+		parts = append(parts, fmt.Sprintf("--filter %q", f))
+	}
+	if q.FilterJSON != "" {
+		parts = append(parts, "--filter-json …")
+	}
+	for _, s := range q.Sorts {
+		parts = append(parts, fmt.Sprintf("--sort %q", s))
+	}
+	if len(q.Columns) > 0 {
+		parts = append(parts, "--columns "+strings.Join(q.Columns, ","))
+	}
+	if q.View != "" {
+		parts = append(parts, fmt.Sprintf("--view %q", q.View))
+	}
+	return strings.Join(parts, " ")
+}
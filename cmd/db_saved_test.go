@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDBQuerySaveAs(t *testing.T) {
+	var query map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			query = nil
+			json.NewDecoder(r.Body).Decode(&query)
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}, "has_more": false})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+			"Name":     map[string]interface{}{"type": "title"},
+			"Status":   map[string]interface{}{"type": "status"},
+			"Priority": map[string]interface{}{"type": "number"},
+		}})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer func() { outputFormat = "" }()
+
+	const db = "11111111111111111111111111111111"
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "query", db, "--filter", "Status=Open", "--sort", "Priority:desc", "--columns", "Name,Status", "--save-as", "board", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "query", db, "--as", "board", "--filter", "Name~=crash", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	filter, _ := query["filter"].(map[string]interface{})
+	and, _ := filter["and"].([]interface{})
+	if len(and) != 2 || !strings.Contains(mustJSON(and[0]), "Open") || !strings.Contains(mustJSON(and[1]), "crash") {
+		t.Errorf("filter = %v", query["filter"])
+	}
+	if !strings.Contains(mustJSON(query["sorts"]), "descending") {
+		t.Errorf("sorts = %v", query["sorts"])
+	}
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "saved", "list", db, "--format", "table"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "board") || !strings.Contains(out, `--filter "Status=Open"`) || !strings.Contains(out, `--sort "Priority:desc"`) {
+		t.Errorf("saved list = %q", out)
+	}
+
+	if _, _, err := executeCommand("db", "query", db, "--as", "sprint"); err == nil || !strings.Contains(err.Error(), "board") {
+		t.Errorf("unknown saved query: %v", err)
+	}
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "saved", "delete", db, "board"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if _, _, err := executeCommand("db", "query", db, "--as", "board"); err == nil {
+		t.Error("deleted query still runs")
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// readOnlyPropertyTypes are computed by Notion and cannot be set through
+// 'db add' / 'db add-bulk'.
+var readOnlyPropertyTypes = map[string]bool{
+	"formula":          true,
+	"rollup":           true,
+	"created_time":     true,
+	"created_by":       true,
+	"last_edited_time": true,
+	"last_edited_by":   true,
+	"unique_id":        true,
+	"button":           true,
+}
+
+var dbSchemaCmd = &cobra.Command{
+	Use:   "schema <db-id|url>",
+	Short: "Show a database's row schema",
+	Long: `Show the properties of a database, sorted by name.
+
+With --json-schema a JSON Schema (draft 2020-12) describing one row is
+printed instead. It matches the values 'db add' and 'db add-bulk' accept:
+every value is a string, selects and statuses are limited to their
+options, numbers, dates and checkboxes carry a pattern, and computed
+properties (formula, rollup, created_time, ...) are marked readOnly.
+Tools and agents can validate a payload against it before writing.
+
+Examples:
+  notion db schema abc123
+  notion db schema abc123 --json-schema > tasks.schema.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+
+		dbID := util.ResolveID(args[0])
+		c := newClient(token)
+		// Relation targets are named in the table; fetch them alongside.
+		c.PrefetchSchemas(dbID)
+
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database: %w", err)
+		}
+
+		if jsonSchema, _ := cmd.Flags().GetBool("json-schema"); jsonSchema {
+			return render.JSON(buildDatabaseJSONSchema(db))
+		}
+
+		props, _ := db["properties"].(map[string]interface{})
+		if outputFormat == "json" {
+			return render.JSON(props)
+		}
+
+		render.Title("🗃️", render.ExtractTitle(db))
+		headers := []string{"PROPERTY", "TYPE", "OPTIONS"}
+		var rows [][]string
+		for _, name := range sortedKeys(props) {
+			prop, ok := props[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			propType, _ := prop["type"].(string)
+			options := extractSchemaOptions(prop, propType)
+			if propType == "relation" {
+				options = relationTargetName(c, prop)
+			}
+			rows = append(rows, []string{name, propType, options})
+		}
+		render.Table(headers, rows)
+		return nil
+	},
+}
+
+func init() {
+	dbSchemaCmd.Flags().Bool("json-schema", false, "Print a JSON Schema describing one row")
+	dbCmd.AddCommand(dbSchemaCmd)
+}
+
+// relationTargetName names the database a relation property points at,
+// falling back to its ID when the integration can't read it.
+func relationTargetName(c *notion.Client, prop map[string]interface{}) string {
+	rel, _ := prop["relation"].(map[string]interface{})
+	id, _ := rel["database_id"].(string)
+	if id == "" {
+		return ""
+	}
+	if target, err := c.GetDatabase(id); err == nil {
+		if title := render.ExtractTitle(target); title != "" {
+			return "→ " + title
+		}
+	}
+	return "→ " + id
+}
+
+// buildDatabaseJSONSchema converts a database object into a JSON Schema for
+// the string-valued rows accepted by 'db add' and 'db add-bulk'.
+func buildDatabaseJSONSchema(db map[string]interface{}) map[string]interface{} {
+	props, _ := db["properties"].(map[string]interface{})
+	properties := map[string]interface{}{}
+	for _, name := range sortedKeys(props) {
+		prop, ok := props[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		properties[name] = propertyJSONSchema(prop)
+	}
+
+	schema := map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                render.ExtractTitle(db),
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if id, _ := db["id"].(string); id != "" {
+		schema["$id"] = "notion:database:" + id
+	}
+	return schema
+}
+
+// propertyJSONSchema describes the string form of a single property value,
+// mirroring what buildPropertyValue parses.
+func propertyJSONSchema(prop map[string]interface{}) map[string]interface{} {
+	propType, _ := prop["type"].(string)
+	s := map[string]interface{}{
+		"type":          "string",
+		"x-notion-type": propType,
+	}
+	if id, _ := prop["id"].(string); id != "" {
+		s["x-notion-id"] = id
+	}
+
+	switch propType {
+	case "number":
+		s["pattern"] = `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`
+	case "checkbox":
+		s["enum"] = []string{"true", "false", "1", "0", "yes", "no"}
+	case "select", "status":
+		if opts := schemaOptionNames(prop, propType); len(opts) > 0 {
+			s["enum"] = opts
+		}
+	case "multi_select":
+		s["description"] = "Comma-separated option names"
+		if opts := schemaOptionNames(prop, propType); len(opts) > 0 {
+			s["x-notion-options"] = opts
+		}
+	case "date":
+		s["description"] = "ISO 8601 date or datetime (no offset: the --tz zone); use start..end or start/end for a range"
+		s["pattern"] = `^\d{4}-\d{2}-\d{2}(T[^/.]+(\.\d+)?[^/.]*)?((/|\.\.)(\d{4}-\d{2}-\d{2}(T\S+)?|\d{1,2}:\d{2}(:\d{2})?))?$`
+	case "verification":
+		s["description"] = "verified or unverified; verified/<date> to expire on a date"
+		s["pattern"] = `^(verified(/\d{4}-\d{2}-\d{2})?|unverified)$`
+	case "place":
+		s["description"] = "lat,lon optionally followed by a name"
+	case "url":
+		s["format"] = "uri"
+	case "email":
+		s["format"] = "email"
+	}
+
+	if readOnlyPropertyTypes[propType] {
+		s["readOnly"] = true
+	}
+	return s
+}
+
+// schemaOptionNames returns the option names of a select, multi_select or
+// status property, in the order Notion lists them.
+func schemaOptionNames(prop map[string]interface{}, propType string) []string {
+	def, _ := prop[propType].(map[string]interface{})
+	opts, _ := def["options"].([]interface{})
+	var names []string
+	for _, o := range opts {
+		opt, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _ := opt["name"].(string); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildDatabaseJSONSchema(t *testing.T) {
+	db := map[string]interface{}{
+		"id":    "db-123",
+		"title": []interface{}{map[string]interface{}{"plain_text": "Tasks"}},
+		"properties": map[string]interface{}{
+			"Name":  map[string]interface{}{"id": "title", "type": "title", "title": map[string]interface{}{}},
+			"Count": map[string]interface{}{"type": "number"},
+			"Done":  map[string]interface{}{"type": "checkbox"},
+			"Due":   map[string]interface{}{"type": "date"},
+			"Score": map[string]interface{}{"type": "formula"},
+			"Status": map[string]interface{}{
+				"type": "select",
+				"select": map[string]interface{}{"options": []interface{}{
+					map[string]interface{}{"name": "Todo"},
+					map[string]interface{}{"name": "Done"},
+				}},
+			},
+		},
+	}
+
+	schema := buildDatabaseJSONSchema(db)
+	if schema["type"] != "object" || schema["additionalProperties"] != false {
+		t.Fatalf("unexpected root: %v", schema)
+	}
+	if schema["$id"] != "notion:database:db-123" {
+		t.Errorf("$id = %v", schema["$id"])
+	}
+	props := schema["properties"].(map[string]interface{})
+	if len(props) != 6 {
+		t.Fatalf("len(properties) = %d, want 6", len(props))
+	}
+
+	status := props["Status"].(map[string]interface{})
+	if !reflect.DeepEqual(status["enum"], []string{"Todo", "Done"}) {
+		t.Errorf("Status enum = %v", status["enum"])
+	}
+	if _, ok := props["Count"].(map[string]interface{})["pattern"]; !ok {
+		t.Error("Count should carry a number pattern")
+	}
+	if props["Score"].(map[string]interface{})["readOnly"] != true {
+		t.Error("formula should be readOnly")
+	}
+	if props["Name"].(map[string]interface{})["x-notion-type"] != "title" {
+		t.Error("Name should keep its Notion type")
+	}
+}
+
+func TestSchemaOptionNames_Missing(t *testing.T) {
+	if got := schemaOptionNames(map[string]interface{}{"type": "select"}, "select"); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestDBSchemaNamesRelationTargets(t *testing.T) {
+	gets := map[string]int{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets[r.URL.Path]++
+		db := map[string]interface{}{"object": "database", "title": []interface{}{map[string]interface{}{"plain_text": "Projects"}}}
+		if strings.HasSuffix(r.URL.Path, "/tasks") {
+			db = map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Project": map[string]interface{}{"type": "relation", "relation": map[string]interface{}{"database_id": "projects"}},
+			}}
+		}
+		json.NewEncoder(w).Encode(db)
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "schema", "tasks", "--format", "table"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "→ Projects") {
+		t.Errorf("output should name the relation target:\n%s", out)
+	}
+	if gets["/v1/databases/tasks"] != 1 || gets["/v1/databases/projects"] != 1 {
+		t.Errorf("GETs = %v, want each schema fetched once", gets)
+	}
+}
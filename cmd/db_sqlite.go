@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// sqliteSyncTable records, per exported table, the database it came from
+// and the newest last_edited_time seen, for --refresh.
+const sqliteSyncTable = "_notion_sync"
+
+const sqliteSyncSchema = `CREATE TABLE IF NOT EXISTS ` + sqliteSyncTable + ` ("table" TEXT PRIMARY KEY, database_id TEXT, last_edited_time TEXT);`
+
+// runSQLite feeds script to the sqlite3 shell against the database file
+// at path and returns its output. Tests replace it.
+var runSQLite = func(path, script string) ([]byte, error) {
+	bin, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite3 not found in PATH (install it, or use --format sql and load the dump yourself)")
+	}
+	cmd := exec.Command(bin, "-batch", "-bail", path)
+	cmd.Stdin = strings.NewReader(script)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("sqlite3: %s", msg)
+		}
+		return nil, fmt.Errorf("sqlite3: %w", err)
+	}
+	return out, nil
+}
+
+// sqliteColumn is one column of an exported table.
+type sqliteColumn struct {
+	Name     string // column name
+	Prop     string // Notion property it is read from ("" for row metadata)
+	PropType string
+	SQLType  string
+	End      bool // the end of a date range
+}
+
+// sqliteColumns lays out the table for a database: row metadata first,
+// then one column per property, title first and the rest by name (two for
+// dates: start and end), typed so that numbers sort and compare as numbers.
+func sqliteColumns(propNames []string, propTypes map[string]string) []sqliteColumn {
+	propNames = append([]string(nil), propNames...)
+	sort.SliceStable(propNames, func(i, j int) bool {
+		ti, tj := propTypes[propNames[i]] == "title", propTypes[propNames[j]] == "title"
+		if ti != tj {
+			return ti
+		}
+		return propNames[i] < propNames[j]
+	})
+	cols := []sqliteColumn{
+		{Name: "_id", SQLType: "TEXT PRIMARY KEY"},
+		{Name: "_url", SQLType: "TEXT"},
+		{Name: "_created_time", SQLType: "TEXT"},
+		{Name: "_last_edited_time", SQLType: "TEXT"},
+	}
+	for _, name := range propNames {
+		t := propTypes[name]
+		col := sqliteColumn{Name: name, Prop: name, PropType: t, SQLType: "TEXT"}
+		switch t {
+		case "number":
+			col.SQLType = "REAL"
+		case "checkbox":
+			col.SQLType = "INTEGER"
+		}
+		cols = append(cols, col)
+		if t == "date" {
+			cols = append(cols, sqliteColumn{Name: name + "_end", Prop: name, PropType: t, SQLType: "TEXT", End: true})
+		}
+	}
+	return cols
+}
+
+// sqliteValue returns the SQL literal for a column of a row.
+func sqliteValue(page map[string]interface{}, col sqliteColumn) string {
+	if col.Prop == "" {
+		v, _ := page[strings.TrimPrefix(col.Name, "_")].(string)
+		return sqliteString(v)
+	}
+	props, _ := page["properties"].(map[string]interface{})
+	prop, ok := props[col.Prop].(map[string]interface{})
+	if !ok {
+		return "NULL"
+	}
+	switch col.PropType {
+	case "number":
+		if n, ok := prop["number"].(float64); ok {
+			return strconv.FormatFloat(n, 'g', -1, 64)
+		}
+		return "NULL"
+	case "checkbox":
+		if b, _ := prop["checkbox"].(bool); b {
+			return "1"
+		}
+		return "0"
+	case "date":
+		d, _ := prop["date"].(map[string]interface{})
+		key := "start"
+		if col.End {
+			key = "end"
+		}
+		v, _ := d[key].(string)
+		return sqliteString(v)
+	}
+	return sqliteString(extractPropertyValue(prop))
+}
+
+// sqliteString quotes s as an SQL string literal; "" is NULL.
+func sqliteString(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqliteIdent quotes an identifier.
+func sqliteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+var sqliteTableRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sqliteTableName derives a table name from a database title.
+func sqliteTableName(title string) string {
+	name := strings.Trim(sqliteTableRe.ReplaceAllString(strings.ToLower(title), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "notion_" + name
+	}
+	return strings.TrimSuffix(name, "_")
+}
+
+// writeSQLiteDump writes an SQL script that loads rows into table. With
+// replace the table is recreated; otherwise rows are upserted by _id into
+// the existing table after adding any columns it lacks (existing lists the
+// columns it has; nil creates the table if it isn't there). The newest
+// last_edited_time is recorded for --refresh.
+func writeSQLiteDump(w io.Writer, table, dbID string, cols []sqliteColumn, rows []interface{}, replace bool, existing map[string]bool) {
+	fmt.Fprintln(w, "BEGIN;")
+	var defs []string
+	for _, col := range cols {
+		defs = append(defs, sqliteIdent(col.Name)+" "+col.SQLType)
+	}
+	if replace {
+		fmt.Fprintf(w, "DROP TABLE IF EXISTS %s;\n", sqliteIdent(table))
+		fmt.Fprintf(w, "CREATE TABLE %s (\n  %s\n);\n", sqliteIdent(table), strings.Join(defs, ",\n  "))
+	} else if existing == nil {
+		fmt.Fprintf(w, "CREATE TABLE IF NOT EXISTS %s (\n  %s\n);\n", sqliteIdent(table), strings.Join(defs, ",\n  "))
+	} else {
+		for _, col := range cols {
+			if !existing[col.Name] {
+				fmt.Fprintf(w, "ALTER TABLE %s ADD COLUMN %s %s;\n", sqliteIdent(table), sqliteIdent(col.Name), strings.TrimSuffix(col.SQLType, " PRIMARY KEY"))
+			}
+		}
+	}
+
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = sqliteIdent(col.Name)
+	}
+	latest := ""
+	for _, r := range rows {
+		page, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		values := make([]string, len(cols))
+		for i, col := range cols {
+			values[i] = sqliteValue(page, col)
+		}
+		fmt.Fprintf(w, "INSERT OR REPLACE INTO %s (%s) VALUES (%s);\n", sqliteIdent(table), strings.Join(names, ", "), strings.Join(values, ", "))
+		if edited, _ := page["last_edited_time"].(string); edited > latest {
+			latest = edited
+		}
+	}
+
+	fmt.Fprintln(w, sqliteSyncSchema)
+	if latest != "" {
+		fmt.Fprintf(w, "INSERT INTO %s VALUES (%s, %s, %s) ON CONFLICT(\"table\") DO UPDATE SET database_id = excluded.database_id, last_edited_time = max(coalesce(last_edited_time, ''), excluded.last_edited_time);\n",
+			sqliteSyncTable, sqliteString(table), sqliteString(dbID), sqliteString(latest))
+	}
+	fmt.Fprintln(w, "COMMIT;")
+}
+
+// exportSQLite writes the database's rows into a table of the SQLite file
+// at path. With refresh only rows edited since the last export are
+// fetched and upserted; without it the table is rebuilt. prepare, when
+// set, is applied to the fetched rows before they are written.
+func exportSQLite(c *notion.Client, dbID, path, table string, cols []sqliteColumn, refresh bool, prepare func([]interface{}) error) (int, error) {
+	body := map[string]interface{}{}
+	var existing map[string]bool
+	if refresh {
+		out, err := runSQLite(path, fmt.Sprintf(".mode list\n%s\nSELECT name FROM pragma_table_info(%s);\nSELECT '@' || last_edited_time FROM %s WHERE \"table\" = %s;\n",
+			sqliteSyncSchema, sqliteString(table), sqliteSyncTable, sqliteString(table)))
+		if err != nil {
+			return 0, err
+		}
+		since := ""
+		existing = map[string]bool{}
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if strings.HasPrefix(line, "@") {
+				since = strings.TrimPrefix(line, "@")
+			} else if line != "" {
+				existing[line] = true
+			}
+		}
+		if len(existing) == 0 {
+			// Nothing exported yet: a refresh is a full export.
+			refresh = false
+		} else if since != "" {
+			body["filter"] = map[string]interface{}{
+				"timestamp":        "last_edited_time",
+				"last_edited_time": map[string]interface{}{"on_or_after": since},
+			}
+		}
+	}
+
+	progress := newProgress("db export", "", 0, os.Stderr)
+	defer progress.stop()
+	rows, err := queryAllWithProgress(c, dbID, body, progress)
+	if err != nil {
+		if canceled(err) {
+			return 0, progress.interrupted(path + " was not changed")
+		}
+		return 0, fmt.Errorf("query database: %w", err)
+	}
+	progress.finish()
+	if prepare != nil {
+		if err := prepare(rows); err != nil {
+			if canceled(err) {
+				return 0, progress.interrupted(path + " was not changed")
+			}
+			return 0, err
+		}
+	}
+	var script bytes.Buffer
+	writeSQLiteDump(&script, table, dbID, cols, rows, !refresh, existing)
+	if _, err := runSQLite(path, script.String()); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// sqliteOutputPath checks the --output for --format sqlite.
+func sqliteOutputPath(outputPath string) error {
+	if outputPath == "" {
+		return usageError(fmt.Errorf("--format sqlite needs --output <file.db>"))
+	}
+	if fi, err := os.Stat(outputPath); err == nil && fi.IsDir() {
+		return fmt.Errorf("%s is a directory", outputPath)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sqliteTestRows() []interface{} {
+	var rows []interface{}
+	json.Unmarshal([]byte(`[
+	  {"id": "r1", "url": "https://notion.so/r1", "created_time": "2025-01-01T00:00:00.000Z", "last_edited_time": "2025-03-01T10:00:00.000Z",
+	   "properties": {
+	     "Name": {"type": "title", "title": [{"plain_text": "Ship it's done"}]},
+	     "Points": {"type": "number", "number": 3.5},
+	     "Done": {"type": "checkbox", "checkbox": true},
+	     "Due": {"type": "date", "date": {"start": "2025-03-02", "end": "2025-03-04"}}}},
+	  {"id": "r2", "last_edited_time": "2025-02-01T10:00:00.000Z",
+	   "properties": {
+	     "Name": {"type": "title", "title": []},
+	     "Points": {"type": "number", "number": null},
+	     "Done": {"type": "checkbox", "checkbox": false},
+	     "Due": {"type": "date", "date": null}}}]`), &rows)
+	return rows
+}
+
+var sqliteTestTypes = map[string]string{"Points": "number", "Name": "title", "Due": "date", "Done": "checkbox"}
+
+func TestSQLiteColumns(t *testing.T) {
+	cols := sqliteColumns([]string{"Points", "Name", "Due", "Done"}, sqliteTestTypes)
+	var got []string
+	for _, c := range cols {
+		got = append(got, c.Name+":"+c.SQLType)
+	}
+	want := "_id:TEXT PRIMARY KEY _url:TEXT _created_time:TEXT _last_edited_time:TEXT Name:TEXT Done:INTEGER Due:TEXT Due_end:TEXT Points:REAL"
+	if strings.Join(got, " ") != want {
+		t.Errorf("columns:\n got %s\nwant %s", strings.Join(got, " "), want)
+	}
+	if name := sqliteTableName("2025 Tasks & Bugs!"); name != "notion_2025_tasks_bugs" {
+		t.Errorf("table name = %q", name)
+	}
+}
+
+func TestWriteSQLiteDump(t *testing.T) {
+	cols := sqliteColumns([]string{"Points", "Name", "Due", "Done"}, sqliteTestTypes)
+	var buf bytes.Buffer
+	writeSQLiteDump(&buf, "tasks", "db1", cols, sqliteTestRows(), true, nil)
+	dump := buf.String()
+	for _, want := range []string{
+		`CREATE TABLE "tasks"`,
+		`VALUES ('r1', 'https://notion.so/r1', '2025-01-01T00:00:00.000Z', '2025-03-01T10:00:00.000Z', 'Ship it''s done', 1, '2025-03-02', '2025-03-04', 3.5);`,
+		`VALUES ('r2', NULL, NULL, '2025-02-01T10:00:00.000Z', NULL, 0, NULL, NULL, NULL);`,
+		`'tasks', 'db1', '2025-03-01T10:00:00.000Z'`,
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump missing %q:\n%s", want, dump)
+		}
+	}
+
+	buf.Reset()
+	writeSQLiteDump(&buf, "tasks", "db1", cols, nil, false, map[string]bool{"_id": true, "_url": true, "_created_time": true, "_last_edited_time": true, "Name": true, "Done": true, "Due": true, "Due_end": true})
+	if dump := buf.String(); strings.Contains(dump, "DROP TABLE") || !strings.Contains(dump, `ALTER TABLE "tasks" ADD COLUMN "Points" REAL;`) {
+		t.Errorf("refresh dump:\n%s", dump)
+	}
+}
+
+func TestWriteSQLiteDumpLoads(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	path := filepath.Join(t.TempDir(), "notion.db")
+	cols := sqliteColumns([]string{"Points", "Name", "Due", "Done"}, sqliteTestTypes)
+	var buf bytes.Buffer
+	writeSQLiteDump(&buf, "tasks", "db1", cols, sqliteTestRows(), true, nil)
+	if _, err := runSQLite(path, buf.String()); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runSQLite(path, "SELECT sum(Points), sum(Done) FROM tasks;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "3.5|1" {
+		t.Errorf("query = %q", got)
+	}
+}
+
+func TestExportSQLiteRefresh(t *testing.T) {
+	var filter interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		filter = body["filter"]
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": sqliteTestRows()[:1]})
+	}))
+	defer api.Close()
+
+	var scripts []string
+	orig := runSQLite
+	runSQLite = func(path, script string) ([]byte, error) {
+		scripts = append(scripts, script)
+		return []byte("_id\nName\n@2025-02-01T10:00:00.000Z\n"), nil
+	}
+	defer func() { runSQLite = orig }()
+
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	c := newClient("secret_test")
+	cols := sqliteColumns([]string{"Name", "Points"}, sqliteTestTypes)
+	n, err := exportSQLite(c, "db1", "x.db", "tasks", cols, true, nil)
+	if err != nil || n != 1 {
+		t.Fatalf("exportSQLite = %d, %v", n, err)
+	}
+	f, _ := filter.(map[string]interface{})
+	if f["timestamp"] != "last_edited_time" {
+		t.Errorf("filter = %v", filter)
+	}
+	if len(scripts) != 2 || strings.Contains(scripts[1], "DROP TABLE") || !strings.Contains(scripts[1], `ADD COLUMN "Points"`) {
+		t.Errorf("scripts = %q", scripts)
+	}
+}
@@ -31,6 +31,21 @@ func TestParseFilter(t *testing.T) {
 		"Website": map[string]interface{}{
 			"type": "url",
 		},
+		"Notes": map[string]interface{}{
+			"type": "rich_text",
+		},
+		"Score": map[string]interface{}{
+			"type":    "formula",
+			"formula": map[string]interface{}{"expression": "prop(\"Count\") * 2"},
+		},
+		"Total": map[string]interface{}{
+			"type":   "rollup",
+			"rollup": map[string]interface{}{"function": "sum"},
+		},
+		"Owners": map[string]interface{}{
+			"type":   "rollup",
+			"rollup": map[string]interface{}{"function": "show_original"},
+		},
 	}
 
 	tests := []struct {
@@ -155,6 +170,134 @@ func TestParseFilter(t *testing.T) {
 			expr:    "StatusDone",
 			wantErr: true,
 		},
+		{
+			name: "created timestamp",
+			expr: "@created>=2026-01-01",
+			check: func(t *testing.T, r map[string]interface{}) {
+				if r["timestamp"] != "created_time" || r["property"] != nil {
+					t.Errorf("filter = %v, want a created_time timestamp filter", r)
+				}
+				ts := r["created_time"].(map[string]interface{})
+				if ts["on_or_after"] != "2026-01-01" {
+					t.Errorf("created_time = %v, want on_or_after 2026-01-01", ts)
+				}
+			},
+		},
+		{
+			name: "is empty",
+			expr: "Date is empty",
+			check: func(t *testing.T, r map[string]interface{}) {
+				d := r["date"].(map[string]interface{})
+				if r["property"] != "Date" || d["is_empty"] != true {
+					t.Errorf("filter = %v, want date is_empty", r)
+				}
+			},
+		},
+		{
+			name: "comparison whose value reads is empty",
+			expr: "Notes=this is empty",
+			check: func(t *testing.T, r map[string]interface{}) {
+				text := r["rich_text"].(map[string]interface{})
+				if r["property"] != "Notes" || text["equals"] != "this is empty" {
+					t.Errorf("filter = %v, want rich_text equals %q", r, "this is empty")
+				}
+			},
+		},
+		{
+			name: "formula is empty",
+			expr: "Score is empty",
+			check: func(t *testing.T, r map[string]interface{}) {
+				f := r["formula"].(map[string]interface{})
+				if s, _ := f["string"].(map[string]interface{}); s["is_empty"] != true {
+					t.Errorf("filter = %v, want formula string is_empty", r)
+				}
+			},
+		},
+		{
+			name: "number rollup is not empty",
+			expr: "Total is not empty",
+			check: func(t *testing.T, r map[string]interface{}) {
+				roll := r["rollup"].(map[string]interface{})
+				if n, _ := roll["number"].(map[string]interface{}); n["is_not_empty"] != true {
+					t.Errorf("filter = %v, want rollup number is_not_empty", r)
+				}
+			},
+		},
+		{
+			name: "array rollup is empty",
+			expr: "Owners is empty",
+			check: func(t *testing.T, r map[string]interface{}) {
+				roll := r["rollup"].(map[string]interface{})
+				if _, ok := roll["none"].(map[string]interface{}); !ok {
+					t.Errorf("filter = %v, want rollup none", r)
+				}
+			},
+		},
+		{
+			name: "not empty symbol",
+			expr: "Tags!=∅",
+			check: func(t *testing.T, r map[string]interface{}) {
+				ms := r["multi_select"].(map[string]interface{})
+				if ms["is_not_empty"] != true {
+					t.Errorf("filter = %v, want multi_select is_not_empty", r)
+				}
+			},
+		},
+		{
+			name:    "checkbox can't be empty",
+			expr:    "Done IS NOT EMPTY",
+			wantErr: true,
+		},
+		{
+			name: "text does not contain",
+			expr: "Name!~=draft",
+			check: func(t *testing.T, r map[string]interface{}) {
+				title := r["title"].(map[string]interface{})
+				if r["property"] != "Name" || title["does_not_contain"] != "draft" {
+					t.Errorf("filter = %v, want title does_not_contain draft", r)
+				}
+			},
+		},
+		{
+			name: "created timestamp after",
+			expr: "@created>2026-01-01",
+			check: func(t *testing.T, r map[string]interface{}) {
+				ts := r["created_time"].(map[string]interface{})
+				if ts["after"] != "2026-01-01" || ts["on_or_after"] != nil {
+					t.Errorf("created_time = %v, want after 2026-01-01", ts)
+				}
+			},
+		},
+		{
+			name: "edited timestamp before",
+			expr: "@edited<2026-02-01",
+			check: func(t *testing.T, r map[string]interface{}) {
+				ts := r["last_edited_time"].(map[string]interface{})
+				if ts["before"] != "2026-02-01" || ts["on_or_before"] != nil {
+					t.Errorf("last_edited_time = %v, want before 2026-02-01", ts)
+				}
+			},
+		},
+		{
+			name: "edited timestamp on or before",
+			expr: "@edited<=2026-02-01",
+			check: func(t *testing.T, r map[string]interface{}) {
+				ts := r["last_edited_time"].(map[string]interface{})
+				if ts["on_or_before"] != "2026-02-01" {
+					t.Errorf("last_edited_time = %v, want on_or_before 2026-02-01", ts)
+				}
+			},
+		},
+		{
+			name: "edited timestamp",
+			expr: "@Edited<2026-02-01",
+			check: func(t *testing.T, r map[string]interface{}) {
+				ts, ok := r["last_edited_time"].(map[string]interface{})
+				if r["timestamp"] != "last_edited_time" || !ok || ts["before"] != "2026-02-01" {
+					t.Errorf("filter = %v", r)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -228,6 +371,16 @@ func TestParseSort(t *testing.T) {
 	}
 }
 
+func TestParseSortTimestamp(t *testing.T) {
+	got := parseSort("@edited:desc")
+	if got["timestamp"] != "last_edited_time" || got["direction"] != "descending" || got["property"] != nil {
+		t.Errorf("parseSort(@edited:desc) = %v", got)
+	}
+	if got := parseSort("@created"); got["timestamp"] != "created_time" || got["direction"] != "ascending" {
+		t.Errorf("parseSort(@created) = %v", got)
+	}
+}
+
 func TestMapTextOp(t *testing.T) {
 	tests := []struct{ op, want string }{
 		{"eq", "equals"},
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// dbTrackHistory is what 'db track' keeps about a database: the values
+// seen at the last run and every change seen so far.
+type dbTrackHistory struct {
+	DatabaseID string                `json:"database_id"`
+	Props      []string              `json:"props"`
+	FirstRun   time.Time             `json:"first_run"`
+	LastRun    time.Time             `json:"last_run"`
+	Rows       map[string]dbTrackRow `json:"rows"`
+	Changes    []dbTrackChange       `json:"changes"`
+}
+
+// dbTrackRow is a row's values at the last run; Added is the run that
+// first saw it (zero for rows there since the first run).
+type dbTrackRow struct {
+	Title  string            `json:"title"`
+	Added  time.Time         `json:"added,omitempty"`
+	Values map[string]string `json:"values"`
+}
+
+// dbTrackChange is a property value that differs between two runs. Time is
+// the row's last edit when that falls between the runs, else the later
+// run; By is whoever last edited the row.
+type dbTrackChange struct {
+	Time     time.Time `json:"time"`
+	RowID    string    `json:"row_id"`
+	Title    string    `json:"title"`
+	Property string    `json:"property"`
+	Old      string    `json:"old"`
+	New      string    `json:"new"`
+	By       string    `json:"by,omitempty"`
+}
+
+var dbTrackCmd = &cobra.Command{
+	Use:   "track <db-id|url>",
+	Short: "Snapshot property values to see how they change",
+	Long: `The API keeps no history of property values, so 'db track' keeps its
+own: each run saves the --props values of every row and records the ones
+that changed since the previous run. Run it regularly (from cron, say) and
+the changes add up to a history.
+
+A run prints the changes it found; --since prints every change recorded
+since then instead, and --prop and --to narrow them down, e.g. to the
+rows moved to Done this week. Changes are only as fine-grained as the
+runs: a value changed twice between runs shows as one change. BY is
+whoever last edited the row, which is usually who made the change.
+
+--props is needed on the first run and remembered after; rows added
+between runs show with an empty old value. The history is kept in the
+cache directory.
+
+Examples:
+  notion db track abc123 --props Status,Points
+  notion db track abc123 --since 7d --prop Status --to Done
+  notion db track abc123 --since 2026-10-01 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		propsFlag, _ := cmd.Flags().GetStringSlice("props")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		propFilter, _ := cmd.Flags().GetString("prop")
+		toFilter, _ := cmd.Flags().GetString("to")
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		dbID := util.ResolveID(args[0])
+		run, err := trackDatabase(c, dbID, propsFlag)
+		if err != nil {
+			return err
+		}
+		history, props, previous, now := run.history, run.history.Props, run.previous, run.history.LastRun
+
+		changes := run.found
+		var since time.Time
+		if sinceFlag != "" {
+			if since, err = parseSince(sinceFlag, now); err != nil {
+				return usageError(err)
+			}
+			changes = nil
+			for _, ch := range history.Changes {
+				if !ch.Time.Before(since) {
+					changes = append(changes, ch)
+				}
+			}
+		}
+		changes = filterTrackChanges(changes, propFilter, toFilter)
+
+		if outputFormat == "json" {
+			out := map[string]interface{}{"database_id": dbID, "props": props, "run": now, "rows": len(history.Rows), "changes": changes}
+			if !previous.IsZero() {
+				out["previous_run"] = previous
+			}
+			if !since.IsZero() {
+				out["since"] = since
+			}
+			if changes == nil {
+				out["changes"] = []dbTrackChange{}
+			}
+			return render.JSON(out)
+		}
+		if previous.IsZero() && since.IsZero() {
+			fmt.Printf("Tracking %s of %d rows; run again to see what changed.\n", strings.Join(props, ", "), len(history.Rows))
+			return nil
+		}
+		if len(changes) == 0 {
+			if since.IsZero() {
+				fmt.Printf("No changes since the last run (%s).\n", previous.Local().Format("2006-01-02 15:04"))
+			} else {
+				fmt.Printf("No changes recorded since %s.\n", since.Local().Format("2006-01-02 15:04"))
+			}
+			return nil
+		}
+		var table [][]string
+		for _, ch := range changes {
+			table = append(table, []string{ch.Time.Local().Format("2006-01-02 15:04"), ch.Title, ch.Property, orDash(ch.Old) + " → " + orDash(ch.New), ch.By})
+		}
+		render.Table([]string{"WHEN", "ROW", "PROPERTY", "CHANGE", "BY"}, table)
+		return nil
+	},
+}
+
+func init() {
+	dbTrackCmd.Flags().StringSlice("props", nil, "Properties to track (remembered after the first run)")
+	dbTrackCmd.Flags().String("since", "", "Show every change recorded since then (7d, 2026-10-01) instead of this run's")
+	dbTrackCmd.Flags().String("prop", "", "Only show changes of this property")
+	dbTrackCmd.Flags().String("to", "", "Only show changes to this value")
+	dbCmd.AddCommand(dbTrackCmd)
+}
+
+// dbTrackRun is a run of 'db track': the history after it, the changes
+// it found, the previous run and the database schema.
+type dbTrackRun struct {
+	history  dbTrackHistory
+	found    []dbTrackChange
+	previous time.Time
+	schema   map[string]interface{}
+}
+
+// trackDatabase snapshots props of every row of dbID (the properties
+// tracked so far when props is empty) and saves the history.
+func trackDatabase(c *notion.Client, dbID string, props []string) (*dbTrackRun, error) {
+	history := loadDBTrackHistory(dbID)
+	if len(props) == 0 {
+		props = history.Props
+	}
+	if len(props) == 0 {
+		return nil, usageError(fmt.Errorf("name the properties to track with --props (e.g. --props Status,Points)"))
+	}
+
+	db, err := c.GetDatabase(dbID)
+	if err != nil {
+		return nil, fmt.Errorf("get database schema: %w", err)
+	}
+	schema, _ := db["properties"].(map[string]interface{})
+	props, ids, err := resolveColumns(schema, props)
+	if err != nil {
+		return nil, err
+	}
+	// Rows need their title too, to be shown.
+	for _, p := range schema {
+		if prop, _ := p.(map[string]interface{}); prop["type"] == "title" {
+			if id, _ := prop["id"].(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	rows, err := c.QueryDatabaseAll(dbID, map[string]interface{}{}, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("query database: %w", err)
+	}
+
+	run := &dbTrackRun{previous: history.LastRun, schema: schema}
+	now := timeNow().UTC().Truncate(time.Second)
+	run.found = trackRows(c, &history, rows, props, now)
+	if history.FirstRun.IsZero() {
+		history.FirstRun = now
+	}
+	history.DatabaseID, history.Props, history.LastRun = dbID, props, now
+	if err := saveDBTrackHistory(history); err != nil {
+		return nil, err
+	}
+	run.history = history
+	return run, nil
+}
+
+// trackRows compares rows with the values history saw last, appends the
+// changes to it and replaces its snapshot with rows. Nothing is recorded
+// on the first run, nor for properties that weren't tracked before. Names
+// of the users who edited changed rows are looked up once each.
+func trackRows(c *notion.Client, history *dbTrackHistory, rows []interface{}, props []string, now time.Time) []dbTrackChange {
+	tracked := map[string]bool{}
+	for _, p := range history.Props {
+		tracked[p] = true
+	}
+	first := history.LastRun.IsZero()
+	users := map[string]string{}
+	snapshot := make(map[string]dbTrackRow, len(rows))
+	var found []dbTrackChange
+	for _, r := range rows {
+		row, _ := r.(map[string]interface{})
+		id, _ := row["id"].(string)
+		if id == "" {
+			continue
+		}
+		values, _ := row["properties"].(map[string]interface{})
+		current := dbTrackRow{Title: firstNonEmpty(render.ExtractTitle(row), "Untitled"), Added: history.Rows[id].Added, Values: map[string]string{}}
+		if _, seen := history.Rows[id]; !seen && !first {
+			current.Added = now
+		}
+		for _, p := range props {
+			prop, _ := values[p].(map[string]interface{})
+			current.Values[p] = extractPropertyValue(prop)
+		}
+		snapshot[id] = current
+		if first {
+			continue
+		}
+
+		before := history.Rows[id].Values
+		for _, p := range props {
+			if !tracked[p] || before[p] == current.Values[p] {
+				continue
+			}
+			found = append(found, dbTrackChange{
+				Time:     trackChangeTime(row, history.LastRun, now),
+				RowID:    id,
+				Title:    current.Title,
+				Property: p,
+				Old:      before[p],
+				New:      current.Values[p],
+				By:       trackEditor(c, row, users),
+			})
+		}
+	}
+	history.Rows = snapshot
+	history.Changes = append(history.Changes, found...)
+	return found
+}
+
+// trackChangeTime is when a change between the runs at last and now most
+// likely happened: the row's last edit if it falls between them, else now.
+func trackChangeTime(row map[string]interface{}, last, now time.Time) time.Time {
+	edited, _ := row["last_edited_time"].(string)
+	if t, err := time.Parse(time.RFC3339, edited); err == nil && t.After(last) && !t.After(now) {
+		return t.UTC()
+	}
+	return now
+}
+
+// trackEditor is the name of who last edited row, by ID when the
+// integration can't see the user.
+func trackEditor(c *notion.Client, row map[string]interface{}, users map[string]string) string {
+	by, _ := row["last_edited_by"].(map[string]interface{})
+	id, _ := by["id"].(string)
+	if id == "" {
+		return ""
+	}
+	name, known := users[id]
+	if !known {
+		if user, err := c.GetUser(id); err == nil {
+			name, _ = user["name"].(string)
+		}
+		users[id] = name
+	}
+	return firstNonEmpty(name, id)
+}
+
+// filterTrackChanges keeps the changes of property prop and to value to
+// (both case-insensitive; empty keeps all).
+func filterTrackChanges(changes []dbTrackChange, prop, to string) []dbTrackChange {
+	if prop == "" && to == "" {
+		return changes
+	}
+	var kept []dbTrackChange
+	for _, ch := range changes {
+		if prop != "" && !strings.EqualFold(ch.Property, prop) {
+			continue
+		}
+		if to != "" && !strings.EqualFold(ch.New, to) {
+			continue
+		}
+		kept = append(kept, ch)
+	}
+	return kept
+}
+
+func dbTrackHistoryPath(dbID string) string {
+	sum := sha1.Sum([]byte(plainID(dbID)))
+	return filepath.Join(config.CacheDir(), "track", hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadDBTrackHistory returns what 'db track' kept about dbID, empty when
+// it never ran on it.
+func loadDBTrackHistory(dbID string) dbTrackHistory {
+	var history dbTrackHistory
+	if data, err := os.ReadFile(dbTrackHistoryPath(dbID)); err == nil {
+		json.Unmarshal(data, &history)
+	}
+	return history
+}
+
+func saveDBTrackHistory(history dbTrackHistory) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := dbTrackHistoryPath(history.DatabaseID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("save history: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("save history: %w", err)
+	}
+	return nil
+}
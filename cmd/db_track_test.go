@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDBTrack(t *testing.T) {
+	status := "In progress"
+	var filterProps []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			filterProps = r.URL.Query()["filter_properties"]
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"object": "page", "id": "aaaa", "last_edited_time": "2026-10-15T09:00:00Z",
+					"last_edited_by": map[string]interface{}{"id": "u1"},
+					"properties": map[string]interface{}{
+						"Name":   map[string]interface{}{"id": "title", "type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Fix login"}}},
+						"Status": map[string]interface{}{"id": "st", "type": "status", "status": map[string]interface{}{"name": status}},
+					}},
+			}, "has_more": false})
+		case strings.HasPrefix(r.URL.Path, "/v1/users/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "u1", "name": "Ada"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name":   map[string]interface{}{"id": "title", "type": "title"},
+				"Status": map[string]interface{}{"id": "st", "type": "status"},
+			}})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func() { outputFormat = "" }()
+	oldNow := timeNow
+	defer func() { timeNow = oldNow }()
+
+	const db = "11111111111111111111111111111111"
+	if _, _, err := executeCommand("db", "track", db); err == nil {
+		t.Error("first run without --props accepted")
+	}
+	timeNow = func() time.Time { return time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC) }
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "track", db, "--props", "status"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Tracking Status of 1 rows") {
+		t.Errorf("first run = %q", out)
+	}
+	if strings.Join(filterProps, ",") != "st,title" {
+		t.Errorf("filter_properties = %v", filterProps)
+	}
+
+	status = "Done"
+	timeNow = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "track", db); err != nil {
+			t.Fatal(err)
+		}
+	})
+	out = captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "track", db, "--since", "7d", "--to", "done", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var result struct {
+		Changes []dbTrackChange `json:"changes"`
+	}
+	json.Unmarshal([]byte(out), &result)
+	if len(result.Changes) != 1 {
+		t.Fatalf("changes = %s", out)
+	}
+	ch := result.Changes[0]
+	if ch.Title != "Fix login" || ch.Old != "In progress" || ch.New != "Done" || ch.By != "Ada" || !ch.Time.Equal(time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("change = %+v", ch)
+	}
+}
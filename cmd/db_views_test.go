@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestDBQueryView(t *testing.T) {
+	view := map[string]interface{}{
+		"object": "view", "id": "55555555-5555-5555-5555-555555555555", "name": "Open bugs", "type": "table",
+		"filter": map[string]interface{}{"property": "Status", "status": map[string]interface{}{"does_not_equal": "Done"}},
+		"sorts":  []interface{}{map[string]interface{}{"property": "Priority", "direction": "ascending"}},
+	}
+	var query map[string]interface{}
+	var versions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/views":
+			versions = append(versions, r.Header.Get("Notion-Version"))
+			if r.URL.Query().Get("database_id") != "11111111-1111-1111-1111-111111111111" {
+				t.Errorf("views of %q", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{view}, "has_more": false})
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewDecoder(r.Body).Decode(&query)
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}, "has_more": false})
+		default:
+			versions = append(versions, r.Header.Get("Notion-Version"))
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name":   map[string]interface{}{"type": "title"},
+				"Status": map[string]interface{}{"type": "status"},
+			}})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	defer func() { outputFormat = "" }()
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "views", "list", "11111111111111111111111111111111"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Open bugs") || !strings.Contains(out, "table") {
+		t.Errorf("views list = %q", out)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "query", "11111111111111111111111111111111", "--view", "open BUGS", "--filter", "Name~=crash", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	filter, _ := query["filter"].(map[string]interface{})
+	and, _ := filter["and"].([]interface{})
+	if len(and) != 2 || !strings.Contains(mustJSON(and[0]), "does_not_equal") || !strings.Contains(mustJSON(and[1]), "crash") {
+		t.Errorf("filter = %v", query["filter"])
+	}
+	if !strings.Contains(mustJSON(query["sorts"]), "Priority") {
+		t.Errorf("sorts = %v", query["sorts"])
+	}
+	if versions[0] != notion.ViewsVersion || versions[1] != notion.NotionVersion {
+		t.Errorf("Notion-Version headers = %v", versions)
+	}
+
+	if _, _, err := executeCommand("db", "query", "11111111111111111111111111111111", "--view", "Closed"); err == nil || !strings.Contains(err.Error(), "Open bugs") {
+		t.Errorf("unknown view: %v", err)
+	}
+}
+
+func mustJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// errDeadline is wrapped by the error of every request cut short because
+// the command ran past --max-duration.
+var errDeadline = errors.New("time limit reached")
+
+var (
+	// maxDuration is the --max-duration flag; 0 falls back to
+	// NOTION_MAX_DURATION.
+	maxDuration time.Duration
+	// requestTimeout is the --request-timeout flag: how long one request
+	// may take (0 = notion.DefaultTimeout), however long the command runs.
+	requestTimeout time.Duration
+	// operationDeadline is when the running command must stop (zero =
+	// never).
+	operationDeadline time.Time
+)
+
+// resetDeadline resolves the time limit for a new command: --max-duration,
+// then NOTION_MAX_DURATION.
+func resetDeadline() error {
+	operationDeadline = time.Time{}
+	if maxDuration < 0 || requestTimeout < 0 {
+		return usageError(fmt.Errorf("--max-duration and --request-timeout must be 0 (no limit) or more"))
+	}
+	limit := maxDuration
+	if limit == 0 {
+		if env := os.Getenv("NOTION_MAX_DURATION"); env != "" {
+			d, err := time.ParseDuration(env)
+			if err != nil || d < 0 {
+				return fmt.Errorf("NOTION_MAX_DURATION: expected a duration like 30m, got %q", env)
+			}
+			limit = d
+		}
+	}
+	if limit > 0 {
+		operationDeadline = time.Now().Add(limit)
+	}
+	return nil
+}
+
+// enforceDeadline is client middleware holding every request, retries and
+// their waits included, to the command's deadline. It sits outside the
+// retries, so a request isn't retried past it.
+func enforceDeadline(next notion.Handler) notion.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		if operationDeadline.IsZero() {
+			return next(req)
+		}
+		limitErr := fmt.Errorf("%w: stopped after %s (--max-duration); output is truncated", errDeadline, maxDurationText())
+		if !time.Now().Before(operationDeadline) {
+			return nil, limitErr
+		}
+		ctx, cancel := context.WithDeadline(req.Context(), operationDeadline)
+		resp, err := next(req.WithContext(ctx))
+		if err != nil {
+			cancel()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil {
+				return nil, limitErr
+			}
+			return nil, err
+		}
+		resp.Body = &cancelOnClose{resp.Body, cancel}
+		return resp, nil
+	}
+}
+
+// maxDurationText is the limit in force, as given.
+func maxDurationText() string {
+	if maxDuration > 0 {
+		return maxDuration.String()
+	}
+	return os.Getenv("NOTION_MAX_DURATION")
+}
+
+// cancelOnClose releases a request's context once its body is read.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxDurationTruncatesListing(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":      "list",
+			"results":     []interface{}{map[string]interface{}{"object": "page", "id": fmt.Sprintf("p%d", calls)}},
+			"has_more":    true,
+			"next_cursor": fmt.Sprintf("c%d", calls),
+		})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func() { maxDuration = 0 }()
+
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand("search", "--all", "--max-duration", "150ms", "--format", "json")
+	})
+	outputFormat = ""
+	if !errors.Is(err, errDeadline) {
+		t.Fatalf("err = %v, want errDeadline", err)
+	}
+	var got struct {
+		Results    []interface{} `json:"results"`
+		Truncated  bool          `json:"truncated"`
+		NextCursor string        `json:"next_cursor"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if len(got.Results) != 1 || !got.Truncated || got.NextCursor != "c1" {
+		t.Errorf("output = %+v", got)
+	}
+}
+
+func TestDeadlineFromEnv(t *testing.T) {
+	maxDuration = 0
+	t.Setenv("NOTION_MAX_DURATION", "1h")
+	if err := resetDeadline(); err != nil || time.Until(operationDeadline) <= 59*time.Minute {
+		t.Errorf("deadline = %v, %v; want an hour from now", operationDeadline, err)
+	}
+	t.Setenv("NOTION_MAX_DURATION", "soon")
+	if err := resetDeadline(); err == nil {
+		t.Error("invalid NOTION_MAX_DURATION accepted")
+	}
+	t.Setenv("NOTION_MAX_DURATION", "")
+	if err := resetDeadline(); err != nil || !operationDeadline.IsZero() {
+		t.Errorf("deadline = %v, %v; want none", operationDeadline, err)
+	}
+}
+
+func TestProgressHeartbeat(t *testing.T) {
+	var events bytes.Buffer
+	progressStderr = &events
+	progressFormat = "json"
+	heartbeatInterval = 20 * time.Millisecond
+	defer func() { progressStderr = os.Stderr; progressFormat = ""; heartbeatInterval = 10 * time.Second }()
+
+	p := newProgress("db export", "", 0, os.Stderr)
+	time.Sleep(100 * time.Millisecond)
+	p.finish() // waits for the heartbeat to stop
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	var kinds []string
+	for _, line := range lines {
+		var e progressEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("event %q: %v", line, err)
+		}
+		kinds = append(kinds, e.Event)
+	}
+	if !strings.Contains(strings.Join(kinds, ","), "heartbeat") {
+		t.Errorf("no heartbeat in %v", kinds)
+	}
+	if kinds[len(kinds)-1] != "done" {
+		t.Errorf("heartbeat after done: %v", kinds)
+	}
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// maxParentDepth bounds the walk up an object's parents.
+const maxParentDepth = 20
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose why something doesn't work",
+	Long: `Diagnose problems with specific Notion objects.
+
+For setup problems (token, config, network) run 'notion auth doctor'.`,
+}
+
+var doctorPermissionsCmd = &cobra.Command{
+	Use:   "permissions <id|url>",
+	Short: "Explain why an object is (in)accessible",
+	Long: `Probe the page, database, block and user endpoints for an ID and
+explain what the integration can see: whether the object is shared,
+archived or in the trash, what kind of object it really is, and which of
+its parents are shared, with the fix for each problem.
+
+The API answers "not found" both for objects that don't exist and for
+objects not shared with the integration; both are reported as not shared,
+since sharing the page (or a page above it) is the fix when it exists.
+
+Examples:
+  notion doctor permissions abc123
+  notion doctor permissions https://notion.so/My-Page-abc123
+  notion doctor permissions abc123 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		report := diagnoseAccess(c, args[0])
+
+		if outputFormat == "json" {
+			return render.JSON(report)
+		}
+
+		title := report.ID
+		if report.Title != "" {
+			title = fmt.Sprintf("%s (%s)", report.Title, report.ID)
+		}
+		render.Title("🔍", title)
+		render.Field("Status", report.Status)
+		if report.Object != "" {
+			render.Field("Object", report.Object)
+		}
+		if report.Integration != "" {
+			render.Field("Integration", report.Integration)
+		}
+		fmt.Println()
+		fmt.Println(report.Detail)
+		if len(report.Probes) > 0 {
+			fmt.Println()
+			for _, p := range report.Probes {
+				mark := "✓"
+				if p.Result != "ok" {
+					mark = "✗"
+				}
+				fmt.Printf("  %s %s — %s\n", mark, p.Endpoint, p.Result)
+			}
+		}
+		if len(report.Parents) > 0 {
+			fmt.Println("\nParents:")
+			for _, p := range report.Parents {
+				mark := "✓"
+				if p.Status != "shared" {
+					mark = "✗"
+				}
+				name := p.ID
+				if p.Title != "" {
+					name = fmt.Sprintf("%q (%s)", p.Title, p.ID)
+				}
+				fmt.Printf("  %s %s %s — %s\n", mark, p.Object, name, p.Status)
+			}
+		}
+		for _, fix := range report.Fixes {
+			fmt.Printf("\n→ %s\n", fix)
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.AddCommand(doctorPermissionsCmd)
+}
+
+// accessReport is the result of 'doctor permissions'.
+type accessReport struct {
+	ID string `json:"id"`
+	// Status is accessible, archived, in_trash, not_shared, restricted,
+	// invalid_id or unauthorized.
+	Status      string         `json:"status"`
+	OK          bool           `json:"ok"`
+	Object      string         `json:"object,omitempty"`
+	Title       string         `json:"title,omitempty"`
+	Integration string         `json:"integration,omitempty"`
+	Detail      string         `json:"detail"`
+	Fixes       []string       `json:"fixes,omitempty"`
+	Probes      []accessProbe  `json:"probes,omitempty"`
+	Parents     []accessParent `json:"parents,omitempty"`
+}
+
+// accessProbe is one endpoint tried for the ID.
+type accessProbe struct {
+	Endpoint string `json:"endpoint"`
+	Result   string `json:"result"` // "ok" or why it failed
+}
+
+// accessParent is one step up the object's parent chain.
+type accessParent struct {
+	ID     string `json:"id,omitempty"`
+	Object string `json:"object"` // page, database, block or workspace
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"` // shared, not shared, archived or in trash
+}
+
+// diagnoseAccess works out what the integration can see of ref.
+func diagnoseAccess(c *notion.Client, ref string) *accessReport {
+	id := util.ResolveID(ref)
+	r := &accessReport{ID: id}
+	if !util.IsID(id) {
+		r.Status = "invalid_id"
+		r.Detail = fmt.Sprintf("%q is not a Notion ID or URL.", ref)
+		r.Fixes = []string{"Pass the page's URL (Share → Copy link) or its 32-character ID."}
+		return r
+	}
+
+	me, err := c.GetMe()
+	if err != nil {
+		if errors.Is(err, notion.ErrUnauthorized) {
+			r.Status = "unauthorized"
+			r.Detail = "The token was rejected, so nothing is accessible."
+			r.Fixes = []string{"Run 'notion auth login' (or 'notion auth doctor' to see what's wrong)."}
+			return r
+		}
+	}
+	bot, _ := me["name"].(string)
+	r.Integration = bot
+	if bot == "" {
+		bot = "your integration"
+	}
+
+	probes := []struct {
+		object, endpoint string
+		get              func(string) (map[string]interface{}, error)
+	}{
+		{"page", "/v1/pages/", c.GetPage},
+		{"database", "/v1/databases/", c.GetDatabase},
+		{"block", "/v1/blocks/", c.GetBlock},
+		{"user", "/v1/users/", c.GetUser},
+	}
+	var obj map[string]interface{}
+	restricted := false
+	for _, p := range probes {
+		found, err := p.get(id)
+		result := "ok"
+		if err != nil {
+			result = accessProblem(err)
+			restricted = restricted || errors.Is(err, notion.ErrRestricted)
+		}
+		r.Probes = append(r.Probes, accessProbe{Endpoint: "GET " + p.endpoint + id, Result: result})
+		if err == nil {
+			obj, r.Object = found, p.object
+			break
+		}
+	}
+
+	if obj == nil {
+		if restricted {
+			r.Status = "restricted"
+			r.Detail = fmt.Sprintf("The object exists, but %s isn't allowed to read it.", bot)
+			r.Fixes = []string{"Enable the \"Read content\" capability for the integration at " + integrationSettingsURL + "."}
+			return r
+		}
+		r.Status = "not_shared"
+		r.Detail = fmt.Sprintf("No page, database, block or user with this ID is visible to %s: it isn't shared with the integration, or doesn't exist.", bot)
+		r.Fixes = []string{fmt.Sprintf("In Notion, open the page (or any page above it) → ••• → Connections → add %q. Sharing a page shares everything under it.", bot)}
+		return r
+	}
+
+	r.Title = render.ExtractTitle(obj)
+	switch r.Object {
+	case "block":
+		blockType, _ := obj["type"].(string)
+		r.Object = blockType + " block"
+		if blockType == "child_database" {
+			r.Detail = "This is an inline database block, but its database can't be read: it is probably a linked view of a database that isn't shared."
+			r.Fixes = append(r.Fixes, fmt.Sprintf("Share the source database with %q.", bot))
+		} else {
+			r.Detail = fmt.Sprintf("This is a %s block, not a page or database; use 'notion block get %s'.", blockType, id)
+		}
+	case "user":
+		r.Detail = "This is a user, not a page or database; use 'notion user get " + id + "'."
+	default:
+		r.Detail = fmt.Sprintf("The %s is shared with %s.", r.Object, bot)
+	}
+
+	r.Status = "accessible"
+	if inTrash, _ := obj["in_trash"].(bool); inTrash {
+		r.Status = "in_trash"
+	} else if archived, _ := obj["archived"].(bool); archived {
+		r.Status = "archived"
+	}
+	if r.Status != "accessible" {
+		r.Detail = fmt.Sprintf("The %s is shared with %s, but it is %s, so most commands won't see it.", r.Object, bot, map[string]string{"in_trash": "in the trash", "archived": "archived"}[r.Status])
+		if r.Object == "page" {
+			r.Fixes = append(r.Fixes, "Restore it with 'notion page restore "+id+"'.")
+		} else {
+			r.Fixes = append(r.Fixes, "Restore it from the trash in Notion.")
+		}
+	}
+	r.OK = r.Status == "accessible" && len(r.Fixes) == 0
+
+	r.Parents = accessParents(c, obj)
+	for _, p := range r.Parents {
+		switch p.Status {
+		case "not shared":
+			r.Fixes = append(r.Fixes, fmt.Sprintf("The parent %s %s isn't shared, so commands that walk up from here (breadcrumbs, moves, sibling lookups) stop at it; share it with %q if you need them.", p.Object, p.ID, bot))
+		case "archived", "in trash":
+			r.Fixes = append(r.Fixes, fmt.Sprintf("The parent %s %q is %s; restore it to bring this %s back.", p.Object, p.Title, p.Status, r.Object))
+			r.OK = false
+		}
+	}
+	return r
+}
+
+// accessParents walks up from obj until the workspace, or the first
+// parent the integration can't read.
+func accessParents(c *notion.Client, obj map[string]interface{}) []accessParent {
+	var parents []accessParent
+	for depth := 0; depth < maxParentDepth; depth++ {
+		parent, _ := obj["parent"].(map[string]interface{})
+		kind, _ := parent["type"].(string)
+		if kind == "" {
+			break
+		}
+		if kind == "workspace" {
+			parents = append(parents, accessParent{Object: "workspace", Status: "shared"})
+			break
+		}
+		id, _ := parent[kind].(string)
+		p := accessParent{ID: id}
+		var err error
+		switch kind {
+		case "page_id":
+			p.Object = "page"
+			obj, err = c.GetPage(id)
+		case "database_id":
+			p.Object = "database"
+			obj, err = c.GetDatabase(id)
+		case "block_id":
+			p.Object = "block"
+			obj, err = c.GetBlock(id)
+		default:
+			return parents
+		}
+		if err != nil {
+			p.Status = "not shared"
+			if !errors.Is(err, notion.ErrNotFound) {
+				p.Status = accessProblem(err)
+			}
+			return append(parents, p)
+		}
+		p.Title = render.ExtractTitle(obj)
+		p.Status = "shared"
+		if inTrash, _ := obj["in_trash"].(bool); inTrash {
+			p.Status = "in trash"
+		} else if archived, _ := obj["archived"].(bool); archived {
+			p.Status = "archived"
+		}
+		parents = append(parents, p)
+	}
+	return parents
+}
+
+// accessProblem describes a failed probe in a few words.
+func accessProblem(err error) string {
+	switch {
+	case errors.Is(err, notion.ErrNotFound):
+		return "not found"
+	case errors.Is(err, notion.ErrRestricted):
+		return "restricted"
+	case errors.Is(err, notion.ErrValidation):
+		return "not this kind of object"
+	}
+	return firstLine(err)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	doctorPageID   = "11111111-1111-1111-1111-111111111111"
+	doctorParentID = "22222222-2222-2222-2222-222222222222"
+	doctorDBID     = "33333333-3333-3333-3333-333333333333"
+)
+
+func doctorTestServer(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"Could not find object"}`))
+		}
+		switch r.URL.Path {
+		case "/v1/users/me":
+			w.Write([]byte(`{"object":"user","type":"bot","name":"CLI Bot"}`))
+		case "/v1/pages/" + doctorPageID:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": doctorPageID, "archived": true,
+				"parent": map[string]interface{}{"type": "page_id", "page_id": doctorParentID},
+				"properties": map[string]interface{}{"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Notes"}}}}})
+		case "/v1/pages/" + doctorDBID:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"object":"error","status":400,"code":"validation_error","message":"Provided ID is a database, not a page."}`))
+		case "/v1/databases/" + doctorDBID:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "id": doctorDBID,
+				"parent": map[string]interface{}{"type": "workspace", "workspace": true}})
+		default:
+			notFound()
+		}
+	}))
+	t.Cleanup(api.Close)
+	t.Setenv("NOTION_BASE_URL", api.URL)
+}
+
+func TestDiagnoseAccess(t *testing.T) {
+	doctorTestServer(t)
+	c := newClient("secret_test")
+
+	r := diagnoseAccess(c, doctorPageID)
+	if r.Status != "archived" || r.Object != "page" || r.Title != "Notes" || r.OK {
+		t.Errorf("archived page: %+v", r)
+	}
+	if len(r.Parents) != 1 || r.Parents[0].Status != "not shared" || r.Parents[0].ID != doctorParentID {
+		t.Errorf("parents = %+v", r.Parents)
+	}
+	if !strings.Contains(strings.Join(r.Fixes, "\n"), "notion page restore") {
+		t.Errorf("fixes = %v", r.Fixes)
+	}
+
+	r = diagnoseAccess(c, doctorDBID)
+	if r.Status != "accessible" || r.Object != "database" || !r.OK || len(r.Probes) != 2 || r.Probes[0].Result != "not this kind of object" {
+		t.Errorf("database: %+v", r)
+	}
+
+	r = diagnoseAccess(c, "44444444444444444444444444444444")
+	if r.Status != "not_shared" || len(r.Probes) != 4 || !strings.Contains(r.Fixes[0], `"CLI Bot"`) {
+		t.Errorf("unshared: %+v", r)
+	}
+
+	if r = diagnoseAccess(c, "not-an-id"); r.Status != "invalid_id" {
+		t.Errorf("invalid: %+v", r)
+	}
+}
@@ -0,0 +1,400 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// parsedEmail is the part of a message 'email-to-page' saves.
+type parsedEmail struct {
+	Subject     string
+	From        string
+	FromAddress string
+	To          string
+	Date        time.Time
+	Text        string
+	HTML        string
+	Attachments []*fileSource
+}
+
+var emailToPageCmd = &cobra.Command{
+	Use:   "email-to-page",
+	Short: "Save an email (RFC 822 on stdin) as a page",
+	Long: `Read a raw email from stdin (or --file) and save it as a Notion page,
+for mail filters such as procmail, maildrop or a Fastmail/Gmail forward to
+a script.
+
+The subject becomes the title and the body the page content (the plain
+text part, else the HTML part converted to blocks). Attachments are
+uploaded and embedded: images as images, PDFs as PDFs, anything else as a
+file. The page starts with a callout naming the sender, recipients and
+date.
+
+The page is created under --to, or the email_parent setting. In a database
+the sender fills a From property (email or text) and the date a Date or
+Received property when the schema has them.
+
+Examples:
+  notion email-to-page --to abc123 < message.eml
+  notion config set email_parent abc123
+  procmail: | notion email-to-page
+  notion email-to-page --file message.eml --no-attachments`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		file, _ := cmd.Flags().GetString("file")
+		noAttachments, _ := cmd.Flags().GetBool("no-attachments")
+		if to == "" {
+			if cfg, _ := config.Load(); cfg != nil {
+				to = cfg.Setting("email_parent")
+			}
+		}
+		if to == "" {
+			return usageError(fmt.Errorf("no parent for emails: pass --to or run 'notion config set email_parent <page|db>'"))
+		}
+
+		var in io.Reader = os.Stdin
+		if file != "" {
+			f, err := os.Open(file)
+			if err != nil {
+				return fmt.Errorf("open email: %w", err)
+			}
+			defer f.Close()
+			in = f
+		}
+		msg, err := parseEmail(in)
+		if err != nil {
+			return err
+		}
+		if noAttachments {
+			msg.Attachments = nil
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		parentID := util.ResolveID(to)
+
+		body := map[string]interface{}{"children": []map[string]interface{}{emailHeader(msg)}}
+		db, err := c.GetDatabase(parentID)
+		switch {
+		case err == nil:
+			props, _ := db["properties"].(map[string]interface{})
+			properties, err := emailProperties(props, msg)
+			if err != nil {
+				return err
+			}
+			body["parent"] = map[string]interface{}{"database_id": parentID}
+			body["properties"] = properties
+		case errors.Is(err, notion.ErrUnauthorized):
+			return fmt.Errorf("get parent: %w", err)
+		default:
+			body["parent"] = map[string]interface{}{"page_id": parentID}
+			body["properties"] = map[string]interface{}{"title": buildPropertyValue("title", msg.Subject)}
+		}
+
+		data, err := c.Post("/v1/pages", body)
+		if err != nil {
+			return fmt.Errorf("create page: %w", err)
+		}
+		rememberCreated(data)
+		var result map[string]interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		pageID, _ := result["id"].(string)
+
+		blocks, err := handleOversizedBlocks(emailBodyBlocks(msg), oversizeSplit)
+		if err != nil {
+			return err
+		}
+		var failed []string
+		for _, att := range msg.Attachments {
+			outcome, err := uploadFromSource(c, att, "")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "note: attachment %s not uploaded: %s\n", att.Name, firstLine(err))
+				failed = append(failed, att.Name)
+				continue
+			}
+			blocks = append(blocks, buildFileUploadMediaBlock(mediaKindFor(att.ContentType), outcome.UploadID, att.Name))
+		}
+		if len(blocks) > 0 {
+			if _, err := appendChildrenBatched(c, pageID, "", blocks); err != nil {
+				return fmt.Errorf("page %s created, but adding the message body failed: %w", pageID, err)
+			}
+		}
+
+		if outputFormat == "json" {
+			result["attachments"] = len(msg.Attachments) - len(failed)
+			if err := render.JSON(result); err != nil {
+				return err
+			}
+		} else {
+			render.Title("✓", fmt.Sprintf("Saved: %s", msg.Subject))
+			render.Field("ID", pageID)
+			if u, _ := result["url"].(string); u != "" {
+				render.Field("URL", notionLink(u))
+			}
+			if len(msg.Attachments) > 0 {
+				render.Field("Attachments", fmt.Sprintf("%d uploaded", len(msg.Attachments)-len(failed)))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%d attachment(s) could not be uploaded: %s", len(failed), strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	emailToPageCmd.Flags().String("to", "", "Parent page or database (default: email_parent setting)")
+	emailToPageCmd.Flags().String("file", "", "Read the message from a file instead of stdin")
+	markIDFlags(emailToPageCmd, "to")
+	emailToPageCmd.Flags().Bool("no-attachments", false, "Don't upload attachments")
+}
+
+// parseEmail reads an RFC 822 message, walking multipart bodies for the
+// text and HTML parts and the attachments.
+func parseEmail(r io.Reader) (*parsedEmail, error) {
+	m, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse email: %w", err)
+	}
+	dec := new(mime.WordDecoder)
+	decode := func(s string) string {
+		if d, err := dec.DecodeHeader(s); err == nil {
+			return d
+		}
+		return s
+	}
+
+	msg := &parsedEmail{Subject: strings.TrimSpace(decode(m.Header.Get("Subject"))), To: decode(m.Header.Get("To"))}
+	if msg.Subject == "" {
+		msg.Subject = "(no subject)"
+	}
+	if from, err := mail.ParseAddress(m.Header.Get("From")); err == nil {
+		msg.From = firstNonEmpty(from.Name, from.Address)
+		msg.FromAddress = from.Address
+	} else {
+		msg.From = decode(m.Header.Get("From"))
+	}
+	if d, err := m.Header.Date(); err == nil {
+		msg.Date = d
+	}
+
+	if err := walkEmailPart(msg, m.Header.Get("Content-Type"), m.Header.Get("Content-Transfer-Encoding"), "", m.Body); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func walkEmailPart(msg *parsedEmail, contentType, encoding, disposition string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("parse email: %w", err)
+			}
+			err = walkEmailPart(msg, part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"), part)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(encoding, body))
+	if err != nil {
+		return fmt.Errorf("decode email part: %w", err)
+	}
+
+	dispType, dispParams, _ := mime.ParseMediaType(disposition)
+	name := firstNonEmpty(dispParams["filename"], params["name"])
+	if name != "" {
+		if d, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+			name = d
+		}
+	}
+	isAttachment := dispType == "attachment" || name != "" ||
+		(!strings.HasPrefix(mediaType, "text/") && mediaType != "message/rfc822")
+	switch {
+	case isAttachment:
+		if name == "" {
+			ext, _ := mime.ExtensionsByType(mediaType)
+			name = "attachment"
+			if len(ext) > 0 {
+				name += ext[0]
+			}
+		}
+		msg.Attachments = append(msg.Attachments, &fileSource{
+			Name:        filepath.Base(name),
+			Size:        int64(len(data)),
+			ContentType: mediaType,
+			Data:        data,
+		})
+	case mediaType == "text/plain" && msg.Text == "":
+		msg.Text = decodeCharset(data, params["charset"])
+	case mediaType == "text/html" && msg.HTML == "":
+		msg.HTML = decodeCharset(data, params["charset"])
+	}
+	return nil
+}
+
+func transferDecoder(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// decodeCharset converts Latin-1 text to UTF-8; other charsets are
+// assumed to be UTF-8 compatible.
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	return string(data)
+}
+
+// emailHeader is the callout a saved email starts with.
+func emailHeader(msg *parsedEmail) map[string]interface{} {
+	lines := []string{"From: " + msg.From}
+	if msg.FromAddress != "" && msg.FromAddress != msg.From {
+		lines[0] += " <" + msg.FromAddress + ">"
+	}
+	if msg.To != "" {
+		lines = append(lines, "To: "+msg.To)
+	}
+	if !msg.Date.IsZero() {
+		lines = append(lines, "Date: "+msg.Date.Format("2006-01-02 15:04 -0700"))
+	}
+	return map[string]interface{}{
+		"object": "block",
+		"type":   "callout",
+		"callout": map[string]interface{}{
+			"rich_text": []map[string]interface{}{{"type": "text", "text": map[string]interface{}{"content": strings.Join(lines, "\n")}}},
+			"icon":      map[string]interface{}{"type": "emoji", "emoji": "✉️"},
+		},
+	}
+}
+
+// emailBodyBlocks converts the message body: the text part as one
+// paragraph per blank-line-separated chunk (quoted lines as quotes), or
+// the HTML part through the HTML converter.
+func emailBodyBlocks(msg *parsedEmail) []map[string]interface{} {
+	text := strings.ReplaceAll(msg.Text, "\r\n", "\n")
+	if strings.TrimSpace(text) == "" && msg.HTML != "" {
+		doc := parseHTML(strings.NewReader(msg.HTML))
+		root := doc.find("body")
+		if root == nil {
+			root = doc
+		}
+		return htmlToBlocks(root, nil, "")
+	}
+	var blocks []map[string]interface{}
+	for _, chunk := range strings.Split(text, "\n\n") {
+		chunk = strings.Trim(chunk, "\n")
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+		blockType := "paragraph"
+		lines := strings.Split(chunk, "\n")
+		quoted := true
+		for _, l := range lines {
+			quoted = quoted && strings.HasPrefix(l, ">")
+		}
+		if quoted {
+			blockType = "quote"
+			for i, l := range lines {
+				lines[i] = strings.TrimPrefix(strings.TrimPrefix(l, ">"), " ")
+			}
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"object": "block",
+			"type":   blockType,
+			blockType: map[string]interface{}{
+				"rich_text": []map[string]interface{}{{"type": "text", "text": map[string]interface{}{"content": strings.Join(lines, "\n")}}},
+			},
+		})
+	}
+	return blocks
+}
+
+// mediaKindFor picks the media block type for an attachment.
+func mediaKindFor(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return "image"
+	case contentType == "application/pdf":
+		return "pdf"
+	case strings.HasPrefix(contentType, "video/"):
+		return "video"
+	case strings.HasPrefix(contentType, "audio/"):
+		return "audio"
+	}
+	return "file"
+}
+
+// emailProperties fills an email row: the subject as title, the sender in
+// a From/Sender property and the date in a Date/Received property.
+func emailProperties(schema map[string]interface{}, msg *parsedEmail) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	for _, name := range sortedKeys(schema) {
+		prop, _ := schema[name].(map[string]interface{})
+		propType, _ := prop["type"].(string)
+		lower := strings.ToLower(name)
+		switch {
+		case propType == "title":
+			properties[name] = buildPropertyValue("title", msg.Subject)
+		case (lower == "from" || lower == "sender") && propType == "email" && msg.FromAddress != "":
+			properties[name] = buildPropertyValue("email", msg.FromAddress)
+		case (lower == "from" || lower == "sender") && propType == "rich_text":
+			from := msg.From
+			if msg.FromAddress != "" && msg.FromAddress != msg.From {
+				from += " <" + msg.FromAddress + ">"
+			}
+			properties[name] = buildPropertyValue("rich_text", from)
+		case (lower == "date" || lower == "received") && propType == "date" && !msg.Date.IsZero():
+			properties[name] = buildPropertyValue("date", msg.Date.Format(time.RFC3339))
+		}
+	}
+	if len(properties) == 0 {
+		return nil, fmt.Errorf("the database has no title property")
+	}
+	return properties, nil
+}
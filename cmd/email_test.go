@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleEmail = "From: =?UTF-8?Q?Ren=C3=A9e?= <renee@example.com>\r\n" +
+	"To: notes@example.com\r\n" +
+	"Subject: =?UTF-8?B?UXVhcnRlcmx5IHJlcG9ydCDwn5OI?=\r\n" +
+	"Date: Tue, 14 Oct 2025 09:30:00 +0200\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Hi team,\r\n" +
+	"numbers are =E2=9C=93 up.\r\n" +
+	"\r\n" +
+	"> earlier reply\r\n" +
+	"> second line\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Hi team</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=\"report.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"report.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQK\r\n" +
+	"--outer--\r\n"
+
+func TestParseEmail(t *testing.T) {
+	msg, err := parseEmail(strings.NewReader(sampleEmail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "Quarterly report 📈" || msg.From != "Renée" || msg.FromAddress != "renee@example.com" {
+		t.Errorf("headers = %q from %q <%s>", msg.Subject, msg.From, msg.FromAddress)
+	}
+	if msg.Date.IsZero() || msg.HTML == "" {
+		t.Errorf("date %v, html %q", msg.Date, msg.HTML)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Name != "report.pdf" || string(msg.Attachments[0].Data) != "%PDF-1.4\n" {
+		t.Fatalf("attachments = %+v", msg.Attachments)
+	}
+
+	blocks := emailBodyBlocks(msg)
+	if len(blocks) != 2 || blocks[0]["type"] != "paragraph" || blocks[1]["type"] != "quote" {
+		t.Fatalf("blocks = %v", blocks)
+	}
+	rt := blocks[0]["paragraph"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	if got := rt[0]["text"].(map[string]interface{})["content"]; got != "Hi team,\nnumbers are ✓ up." {
+		t.Errorf("paragraph = %q", got)
+	}
+	quote := blocks[1]["quote"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	if got := quote[0]["text"].(map[string]interface{})["content"]; got != "earlier reply\nsecond line" {
+		t.Errorf("quote = %q", got)
+	}
+}
+
+func TestParseEmailHTMLOnly(t *testing.T) {
+	msg, err := parseEmail(strings.NewReader("Subject: hi\r\nContent-Type: text/html; charset=iso-8859-1\r\n\r\n<html><body><h2>Caf\xe9</h2><p>Body</p></body></html>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := emailBodyBlocks(msg)
+	if len(blocks) != 2 || blocks[0]["type"] != "heading_2" {
+		t.Fatalf("blocks = %v", blocks)
+	}
+	rt := blocks[0]["heading_2"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	if got := rt[0]["text"].(map[string]interface{})["content"]; got != "Café" {
+		t.Errorf("heading = %q", got)
+	}
+}
+
+func TestEmailToPageDatabase(t *testing.T) {
+	var created, appended map[string]interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database",
+				"id":     "mail",
+				"properties": map[string]interface{}{
+					"Subject":  map[string]interface{}{"type": "title"},
+					"From":     map[string]interface{}{"type": "email"},
+					"Received": map[string]interface{}{"type": "date"},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/send"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "up-1", "status": "uploaded"})
+		case r.URL.Path == "/v1/file_uploads":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "up-1"})
+		case r.Method == "POST":
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "new-page"})
+		case r.Method == "PATCH":
+			json.NewDecoder(r.Body).Decode(&appended)
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "message.eml")
+	if err := os.WriteFile(path, []byte(sampleEmail), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("email-to-page", "--file", path, "--to", "44444444444444444444444444444444"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	props, _ := created["properties"].(map[string]interface{})
+	if from, _ := props["From"].(map[string]interface{}); from["email"] != "renee@example.com" {
+		t.Errorf("From = %v", props["From"])
+	}
+	if props["Subject"] == nil || props["Received"] == nil {
+		t.Errorf("properties = %v", props)
+	}
+	children, _ := appended["children"].([]interface{})
+	if len(children) != 3 {
+		t.Fatalf("appended %d blocks, want 2 body blocks and the attachment", len(children))
+	}
+	if last := children[2].(map[string]interface{}); last["type"] != "pdf" {
+		t.Errorf("attachment block = %v", last)
+	}
+}
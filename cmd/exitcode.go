@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses, so scripts can tell why a command failed. They are part
+// of the CLI's interface: existing ones never change meaning.
+const (
+	exitError       = 1 // anything not covered below
+	exitUsage       = 2 // bad command line: unknown command or flag, wrong arguments
+	exitAuth        = 3 // not logged in, token rejected, no access, or writes refused
+	exitNotFound    = 4 // the object doesn't exist or isn't shared with the integration
+	exitValidation  = 5 // input rejected, by the CLI or the API
+	exitRateLimited = 6 // still rate limited after retrying
+	exitNetwork     = 7 // Notion unreachable or failing (queued writes included)
+	// exitInterrupted is the exit status of a command stopped by Ctrl-C:
+	// 128 + SIGINT, as shells report it.
+	exitInterrupted = 130
+)
+
+// exitCodeError gives an error a specific exit status.
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// usageError marks err as a mistake in the command line.
+func usageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{err, exitUsage}
+}
+
+// invalidInput marks err as input the CLI rejected, such as an unknown
+// property or a malformed filter.
+func invalidInput(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{err, exitValidation}
+}
+
+// authError marks err as a missing or unusable login.
+func authError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{err, exitAuth}
+}
+
+// cobraUsagePrefixes start the command-line errors cobra reports without
+// going through the flag error or argument validation hooks.
+var cobraUsagePrefixes = []string{"unknown command", "required flag(s)", "if any flags in the group", "at least one of the flags in the group"}
+
+// exitCode is the process exit status for a command that returned err.
+func exitCode(err error) int {
+	var coded *exitCodeError
+	var interrupted *interruptedError
+	var apiErr *notion.APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &interrupted):
+		return exitInterrupted
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, notion.ErrUnauthorized), errors.Is(err, notion.ErrRestricted),
+		errors.Is(err, errReadOnly), errors.Is(err, errPolicy):
+		return exitAuth
+	case errors.Is(err, notion.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, notion.ErrValidation):
+		return exitValidation
+	case errors.Is(err, notion.ErrRateLimited):
+		return exitRateLimited
+	case errors.Is(err, errQueued), isOffline(err),
+		errors.As(err, &netErr),
+		errors.As(err, &apiErr) && apiErr.Status >= http.StatusInternalServerError:
+		return exitNetwork
+	}
+	for _, prefix := range cobraUsagePrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return exitUsage
+		}
+	}
+	return exitError
+}
+
+var markUsageErrorsOnce sync.Once
+
+// markUsageErrors makes the argument validation errors of every command
+// usage errors. It runs before the first command does, once all commands
+// are registered.
+func markUsageErrors() {
+	markUsageErrorsOnce.Do(func() {
+		var walk func(c *cobra.Command)
+		walk = func(c *cobra.Command) {
+			if validate := c.Args; validate != nil {
+				c.Args = func(cmd *cobra.Command, args []string) error {
+					return usageError(validate(cmd, args))
+				}
+			}
+			for _, sub := range c.Commands() {
+				walk(sub)
+			}
+		}
+		walk(rootCmd)
+	})
+}
+
+func init() {
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return usageError(err)
+	})
+	cobra.OnInitialize(markUsageErrors)
+}
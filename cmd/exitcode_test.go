@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestExitCode(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want int
+	}{
+		{errors.New("boom"), exitError},
+		{fmt.Errorf("get page: %w", &notion.APIError{Status: 404, Code: "object_not_found"}), exitNotFound},
+		{fmt.Errorf("get page: %w", &notion.APIError{Status: 401, Code: "unauthorized"}), exitAuth},
+		{&notion.APIError{Status: 403, Code: "restricted_resource"}, exitAuth},
+		{&notion.APIError{Status: 400, Code: "validation_error"}, exitValidation},
+		{&notion.APIError{Status: 429, Code: "rate_limited"}, exitRateLimited},
+		{&notion.APIError{Status: 503, Code: "service_unavailable"}, exitNetwork},
+		{fmt.Errorf("%w as request #1", errQueued), exitNetwork},
+		{fmt.Errorf("%w: refusing PATCH", errReadOnly), exitAuth},
+		{invalidInput(errors.New(`property "X" not found`)), exitValidation},
+		{errors.New(`unknown command "nope" for "notion"`), exitUsage},
+		{&interruptedError{op: "export"}, exitInterrupted},
+	} {
+		if got := exitCode(tc.err); got != tc.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestCommandExitCodes(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"Could not find page"}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	const page = "11111111111111111111111111111111"
+	badOps := filepath.Join(t.TempDir(), "ops.jsonl")
+	os.WriteFile(badOps, []byte(`{"op":"create_page"}`+"\n"), 0o600)
+	for _, tc := range []struct {
+		args []string
+		want int
+	}{
+		{[]string{"page", "view"}, exitUsage},
+		{[]string{"page", "view", page, "--no-such-flag"}, exitUsage},
+		{[]string{"search", "--porcelain=v9"}, exitUsage},
+		{[]string{"page", "view", page}, exitNotFound},
+		{[]string{"sql", "SELEC * FROM tasks"}, exitValidation},
+		{[]string{"block", "update", page, "--text", "x", "--file", "x.md"}, exitUsage},
+		{[]string{"block", "insert", page, "text"}, exitUsage},
+		{[]string{"search", "x", "--parent", page, "--all-profiles"}, exitUsage},
+		{[]string{"db", "list", "--cursor", "abc", "--all-profiles"}, exitUsage},
+		{[]string{"db", "create", page}, exitUsage},
+		{[]string{"batch"}, exitUsage},
+		{[]string{"batch", "--file", "ops.jsonl", "--on-error", "skip"}, exitUsage},
+		{[]string{"batch", "--file", badOps}, exitValidation},
+		{[]string{"activity", "--type", "block"}, exitUsage},
+		{[]string{"comment", "add", page}, exitUsage},
+	} {
+		_, _, err := executeCommand(tc.args...)
+		if err == nil || exitCode(err) != tc.want {
+			t.Errorf("%v: err = %v, exit code %d, want %d", tc.args, err, exitCode(err), tc.want)
+		}
+	}
+
+	t.Setenv("NOTION_TOKEN", "")
+	if _, _, err := executeCommand("page", "view", page); exitCode(err) != exitAuth {
+		t.Errorf("logged out: err = %v, exit code %d", err, exitCode(err))
+	}
+}
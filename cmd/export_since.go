@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+// sinceLastRun is the --since value of 'db export' and 'page export' that
+// means "since the previous export of the same thing started".
+const sinceLastRun = "last-run"
+
+// exportSince resolves an export's --since flag: last-run is lastRun (zero
+// when nothing was exported before, so everything is), anything else a
+// duration or date parseSince understands. The zero time means no limit.
+func exportSince(flag string, lastRun, now time.Time) (time.Time, error) {
+	switch flag {
+	case "":
+		return time.Time{}, nil
+	case sinceLastRun:
+		return lastRun, nil
+	}
+	return parseSince(flag, now)
+}
+
+// exportRunStart is the time an export run records as its start. Notion
+// keeps edit times to the minute, so it is rounded down: a row edited
+// during the minute the export started is exported again next time rather
+// than missed.
+func exportRunStart(now time.Time) time.Time {
+	return now.UTC().Truncate(time.Minute)
+}
+
+// dbExportRun is what 'db export' remembers about the last export of a
+// database to an output.
+type dbExportRun struct {
+	DatabaseID string    `json:"database_id"`
+	Output     string    `json:"output"`
+	LastRun    time.Time `json:"last_run"`
+}
+
+// dbExportRunPath is where the last export of dbID to output is recorded;
+// output "" is stdout.
+func dbExportRunPath(dbID, output string) string {
+	if output == "" {
+		output = "-"
+	} else if abs, err := filepath.Abs(output); err == nil {
+		output = abs
+	}
+	sum := sha1.Sum([]byte(plainID(dbID) + "|" + output))
+	return filepath.Join(config.CacheDir(), "exports", hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadDBExportRun returns when dbID was last exported to output, or the
+// zero time.
+func loadDBExportRun(dbID, output string) time.Time {
+	var run dbExportRun
+	if data, err := os.ReadFile(dbExportRunPath(dbID, output)); err == nil {
+		json.Unmarshal(data, &run)
+	}
+	return run.LastRun
+}
+
+// saveDBExportRun records a finished export of dbID to output that
+// started at start.
+func saveDBExportRun(dbID, output string, start time.Time) error {
+	data, err := json.MarshalIndent(dbExportRun{DatabaseID: dbID, Output: output, LastRun: start}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := dbExportRunPath(dbID, output)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("save export run: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("save export run: %w", err)
+	}
+	return nil
+}
+
+// editedSinceFilter is the query filter for rows edited at or after since.
+func editedSinceFilter(since time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"timestamp":        "last_edited_time",
+		"last_edited_time": map[string]interface{}{"on_or_after": since.UTC().Format(time.RFC3339)},
+	}
+}
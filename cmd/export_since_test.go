@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	lastRun := time.Date(2026, 3, 9, 2, 0, 0, 0, time.UTC)
+	if got, err := exportSince("", lastRun, now); err != nil || !got.IsZero() {
+		t.Errorf("no --since = %v, %v", got, err)
+	}
+	if got, err := exportSince("last-run", lastRun, now); err != nil || !got.Equal(lastRun) {
+		t.Errorf("last-run = %v, %v", got, err)
+	}
+	if got, err := exportSince("24h", lastRun, now); err != nil || !got.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("24h = %v, %v", got, err)
+	}
+	if _, err := exportSince("lately", lastRun, now); err == nil {
+		t.Error("invalid --since accepted")
+	}
+	if got := exportRunStart(time.Date(2026, 3, 10, 12, 34, 56, 0, time.UTC)); !got.Equal(time.Date(2026, 3, 10, 12, 34, 0, 0, time.UTC)) {
+		t.Errorf("run start = %v", got)
+	}
+}
+
+func TestDBExportSinceLastRun(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "id": "db1", "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"id": "title", "type": "title"},
+			}})
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}, "has_more": false})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	out := filepath.Join(t.TempDir(), "rows.csv")
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := executeCommand("db", "export", "db1", "--since", "last-run", "-o", out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := bodies[0]["filter"]; ok {
+		t.Errorf("first export should fetch everything: %v", bodies[0])
+	}
+	filter, _ := bodies[1]["filter"].(map[string]interface{})
+	if filter["timestamp"] != "last_edited_time" {
+		t.Errorf("second export should fetch changes only: %v", bodies[1])
+	}
+}
+
+func TestPageExportSinceSkipsUnchanged(t *testing.T) {
+	const (
+		rootID  = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+	)
+	fetched := map[string]int{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "/children") {
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+			fetched[id]++
+			var results []interface{}
+			if id == rootID {
+				results = []interface{}{map[string]interface{}{"id": childID, "type": "child_page", "last_edited_time": "2020-01-01T00:00:00.000Z", "child_page": map[string]interface{}{"title": "Old"}}}
+			} else {
+				results = []interface{}{map[string]interface{}{"id": "c1", "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"plain_text": "still here"}}}}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": rootID, "last_edited_time": "2099-01-01T00:00:00.000Z", "properties": map[string]interface{}{
+			"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Root"}}},
+		}})
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	dir := t.TempDir()
+
+	export := func() string {
+		return captureStdout(t, func() {
+			if _, _, err := executeCommand("page", "export", rootID, "--dir", dir, "--since", "last-run"); err != nil {
+				t.Fatalf("page export: %v", err)
+			}
+		})
+	}
+	export()
+	if _, err := os.Stat(filepath.Join(dir, exportManifestName)); err != nil {
+		t.Fatalf("no manifest: %v", err)
+	}
+	out := export()
+	if fetched[childID] != 1 || fetched[rootID] != 2 {
+		t.Errorf("fetched = %v; the unchanged child should be fetched once", fetched)
+	}
+	if !strings.Contains(out, "Exported 1 page(s)") || !strings.Contains(out, "1 unchanged") {
+		t.Errorf("output = %q", out)
+	}
+	root, _ := os.ReadFile(filepath.Join(dir, "Root.md"))
+	if !strings.Contains(string(root), "[Old](Root/Old.md)") {
+		t.Errorf("Root.md = %q", root)
+	}
+	if child, _ := os.ReadFile(filepath.Join(dir, "Root", "Old.md")); !strings.Contains(string(child), "still here") {
+		t.Errorf("Old.md = %q", child)
+	}
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// extPrefix starts the file name of every extension executable.
+const extPrefix = "notion-"
+
+var extCmd = &cobra.Command{
+	Use:   "ext",
+	Short: "Manage CLI extensions",
+	Long: `Extensions are executables named notion-<name>, kept in the extensions
+directory (extensions/ in the config directory) or anywhere on PATH.
+'notion <name> [args...]' runs one when no built-in command has that name.
+
+An extension gets the arguments after its name and the CLI's environment,
+plus:
+  NOTION_TOKEN      the token the CLI would use (profile or environment)
+  NOTION_CLI        the path of the notion executable, to call back into it
+  NOTION_READ_ONLY  1 when the CLI is in read-only mode
+
+In read-only mode, or when write_allow / write_deny is set, an extension
+gets no NOTION_TOKEN, not even one set in the environment: the token
+could write anywhere, so the extension has to go through NOTION_CLI,
+which the read-only mode and the write policy carry over to. Global flags
+may come before the extension's name: 'notion --read-only standup'.
+
+Examples:
+  notion ext list
+  notion ext install ./notion-standup
+  notion standup --team core
+  notion ext remove standup`,
+}
+
+var extListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed extensions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exts := findExtensions()
+		if outputFormat == "json" {
+			return render.JSON(exts)
+		}
+		if len(exts) == 0 {
+			fmt.Println("No extensions found.")
+			return nil
+		}
+		var rows [][]string
+		for _, e := range exts {
+			name := e.Name
+			if e.Shadowed {
+				name += " (shadowed by built-in)"
+			}
+			rows = append(rows, []string{name, e.Path})
+		}
+		render.Table([]string{"NAME", "PATH"}, rows)
+		return nil
+	},
+}
+
+var extInstallCmd = &cobra.Command{
+	Use:   "install <file>",
+	Short: "Copy an executable into the extensions directory",
+	Long: `Copy an executable named notion-<name> into the extensions directory,
+replacing an installed extension of the same name.
+
+Examples:
+  notion ext install ./notion-standup
+  notion ext install ~/src/standup/notion-standup`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src := args[0]
+		name := extensionName(filepath.Base(src))
+		if name == "" {
+			return fmt.Errorf("%s: an extension's file name must start with %q", src, extPrefix)
+		}
+		if isBuiltinCommand(name) {
+			return fmt.Errorf("%q is a built-in command; rename the extension", name)
+		}
+		dst := filepath.Join(extensionDir(), filepath.Base(src))
+		if err := copyExecutable(src, dst); err != nil {
+			return fmt.Errorf("install extension: %w", err)
+		}
+		fmt.Printf("✓ Installed extension %q (run 'notion %s')\n", name, name)
+		return nil
+	},
+}
+
+var extRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an installed extension",
+	Long: `Remove an extension from the extensions directory. Extensions found on
+PATH are left alone.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimPrefix(args[0], extPrefix)
+		for _, e := range findExtensions() {
+			if e.Name == name && filepath.Dir(e.Path) == extensionDir() {
+				if err := os.Remove(e.Path); err != nil {
+					return fmt.Errorf("remove extension: %w", err)
+				}
+				fmt.Printf("✓ Extension %q removed\n", name)
+				return nil
+			}
+		}
+		return fmt.Errorf("extension %q is not installed in %s", name, extensionDir())
+	},
+}
+
+func init() {
+	extCmd.AddCommand(extListCmd)
+	extCmd.AddCommand(extInstallCmd)
+	extCmd.AddCommand(extRemoveCmd)
+}
+
+// extension is an executable 'notion <Name>' dispatches to.
+type extension struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Shadowed is set when a built-in command has the same name, so the
+	// extension never runs.
+	Shadowed bool `json:"shadowed,omitempty"`
+}
+
+// extensionDir is where 'notion ext install' puts extensions.
+func extensionDir() string {
+	return filepath.Join(config.Dir(), "extensions")
+}
+
+// extensionName is the command name of an extension file, or "" when
+// file isn't one.
+func extensionName(file string) string {
+	if !strings.HasPrefix(file, extPrefix) {
+		return ""
+	}
+	name := strings.TrimPrefix(file, extPrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// findExtensions lists the extensions in the extensions directory, then on
+// PATH, by name; the first found of a name wins, as it does when running.
+func findExtensions() []extension {
+	seen := map[string]bool{}
+	var exts []extension
+	dirs := append([]string{extensionDir()}, filepath.SplitList(os.Getenv("PATH"))...)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := extensionName(entry.Name())
+			if name == "" || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			exts = append(exts, extension{Name: name, Path: path, Shadowed: isBuiltinCommand(name)})
+		}
+	}
+	sort.Slice(exts, func(i, j int) bool { return exts[i].Name < exts[j].Name })
+	return exts
+}
+
+// lookupExtension returns the executable for 'notion name', or "".
+func lookupExtension(name string) string {
+	if name == "" || strings.ContainsAny(name, `/\`) || isBuiltinCommand(name) {
+		return ""
+	}
+	for _, e := range findExtensions() {
+		if e.Name == name {
+			return e.Path
+		}
+	}
+	return ""
+}
+
+// isBuiltinCommand reports whether name runs one of the CLI's own
+// commands. help and completion are added by cobra only once it runs.
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" || strings.HasPrefix(name, "__") {
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(path))
+		return ext == ".exe" || ext == ".bat" || ext == ".cmd"
+	}
+	return info.Mode()&0111 != 0
+}
+
+// runExtension runs the extension named by args[0], if there is one, with
+// the rest of args, and returns its exit code. ok is false when args
+// don't name an extension and the CLI should handle them itself. Global
+// flags may come before the name, as in 'notion --read-only standup'.
+func runExtension(args []string) (code int, ok bool, err error) {
+	n := leadingGlobalFlags(args)
+	if n < 0 || n == len(args) {
+		return 0, false, nil
+	}
+	if err := rootCmd.PersistentFlags().Parse(args[:n]); err != nil {
+		return 0, false, nil
+	}
+	name, args := args[n], args[n+1:]
+	config.SetDisabled(noConfig || isTruthy(os.Getenv("NOTION_NO_CONFIG")))
+	path := lookupExtension(name)
+	if path == "" {
+		return 0, false, nil
+	}
+	c := exec.Command(path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = extensionEnv()
+	err = c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true, nil
+	}
+	if err != nil {
+		return 1, true, fmt.Errorf("run extension %q: %w", name, err)
+	}
+	return 0, true, nil
+}
+
+// leadingGlobalFlags returns how many of args are global flags (with their
+// values) before the first other argument, or -1 when one of them isn't a
+// global flag.
+func leadingGlobalFlags(args []string) int {
+	flags := rootCmd.PersistentFlags()
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") {
+		arg := args[i]
+		if arg == "-" || arg == "--" {
+			return -1
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		var f *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			f = flags.Lookup(name)
+		} else if len(name) == 1 {
+			f = flags.ShorthandLookup(name)
+		}
+		if f == nil {
+			return -1
+		}
+		i++
+		if !hasValue && f.NoOptDefVal == "" {
+			i++ // the flag's value
+		}
+	}
+	if i > len(args) {
+		return -1
+	}
+	return i
+}
+
+// extensionEnv is the CLI's environment plus what an extension needs to
+// act as the CLI would. The token is passed resolved, except in read-only
+// mode or under a write policy: then it is left out, environment
+// included, so writes have to go through NOTION_CLI, which carries the
+// read-only mode (NOTION_READ_ONLY) and the policy over.
+func extensionEnv() []string {
+	readOnly := readOnlyReason() != ""
+	policy := loadWritePolicy()
+	if readOnly || len(policy.Allow)+len(policy.Deny) > 0 {
+		var env []string
+		for _, kv := range os.Environ() {
+			if !strings.HasPrefix(kv, "NOTION_TOKEN=") {
+				env = append(env, kv)
+			}
+		}
+		if readOnly {
+			env = append(env, "NOTION_READ_ONLY=1")
+		}
+		return extensionCLIEnv(env)
+	}
+	env := os.Environ()
+	if token, _ := resolveToken(); token != "" && os.Getenv("NOTION_TOKEN") == "" {
+		env = append(env, "NOTION_TOKEN="+token)
+	}
+	return extensionCLIEnv(env)
+}
+
+// extensionCLIEnv adds NOTION_CLI to env, and NOTION_NO_CONFIG when
+// --no-config was given, so calls back into the CLI act as this one.
+func extensionCLIEnv(env []string) []string {
+	if noConfig {
+		env = append(env, "NOTION_NO_CONFIG=1")
+	}
+	if self, err := os.Executable(); err == nil {
+		env = append(env, "NOTION_CLI="+self)
+	}
+	return env
+}
+
+// copyExecutable copies src to dst, creating dst's directory, and makes
+// dst executable.
+func copyExecutable(src, dst string) error {
+	if !isExecutable(src) {
+		return fmt.Errorf("%s is not an executable file", src)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func TestRunExtension(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("extension scripts need a POSIX shell")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "")
+	cfg := &config.Config{CurrentProfile: "agent"}
+	cfg.SetProfile("agent", &config.Profile{Token: "secret_agent", ReadOnly: true})
+	if err := config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	src := t.TempDir()
+	out := filepath.Join(src, "out")
+	script := "#!/bin/sh\necho \"$NOTION_TOKEN $NOTION_READ_ONLY $*\" > " + out + "\nexit 3\n"
+	if err := os.WriteFile(filepath.Join(src, "notion-hello"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Built-ins always win.
+	if err := os.WriteFile(filepath.Join(src, "notion-search"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", src)
+
+	code, ok, err := runExtension([]string{"hello", "--team", "core"})
+	if !ok || err != nil || code != 3 {
+		t.Fatalf("runExtension = %d, %v, %v", code, ok, err)
+	}
+	// A read-only profile's token, which could write, isn't handed out.
+	got, _ := os.ReadFile(out)
+	if strings.TrimSpace(string(got)) != "1 --team core" {
+		t.Errorf("extension saw %q", got)
+	}
+	t.Setenv("NOTION_READ_ONLY", "")
+	t.Setenv("NOTION_TOKEN", "secret_env")
+	runExtension([]string{"hello"})
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "secret_env" {
+		t.Errorf("extension saw %q with a token in the environment", got)
+	}
+	t.Setenv("NOTION_READ_ONLY", "1")
+	runExtension([]string{"hello"})
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "1" {
+		t.Errorf("extension saw %q under NOTION_READ_ONLY", got)
+	}
+	t.Setenv("NOTION_READ_ONLY", "")
+	t.Setenv("NOTION_TOKEN", "")
+	cfg.SetProfile("agent", &config.Profile{Token: "secret_agent"})
+	config.Save(cfg)
+	runExtension([]string{"hello"})
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "secret_agent" {
+		t.Errorf("extension of a writable profile saw %q", got)
+	}
+	// A write policy can't be enforced on a raw token either.
+	t.Setenv("NOTION_WRITE_ALLOW", "11111111111111111111111111111111")
+	runExtension([]string{"hello"})
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "" {
+		t.Errorf("extension saw %q under a write policy", got)
+	}
+	t.Setenv("NOTION_WRITE_ALLOW", "")
+	// Global flags before the name are applied, not mistaken for it.
+	defer func() { readOnlyFlag, outputFormat = false, "" }()
+	if _, ok, _ := runExtension([]string{"--format", "json", "--read-only", "hello", "x"}); !ok {
+		t.Fatal("extension after global flags not dispatched")
+	}
+	if got, _ := os.ReadFile(out); strings.TrimSpace(string(got)) != "1 x" {
+		t.Errorf("extension run with --read-only saw %q", got)
+	}
+	readOnlyFlag = false
+	for _, args := range [][]string{{"search", "x"}, {"nope"}, {"--help"}, {}, {"--read-only"}, {"--format"}, {"--no-such-flag", "hello"}} {
+		if _, ok, _ := runExtension(args); ok {
+			t.Errorf("runExtension(%q) dispatched", args)
+		}
+	}
+
+	// Installed extensions come first and are listed once.
+	t.Setenv("PATH", "")
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("ext", "install", filepath.Join(src, "notion-hello")); err != nil {
+			t.Fatal(err)
+		}
+	})
+	exts := findExtensions()
+	if len(exts) != 1 || exts[0].Name != "hello" || exts[0].Path != filepath.Join(extensionDir(), "notion-hello") {
+		t.Errorf("extensions = %+v", exts)
+	}
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("ext", "remove", "hello"); err != nil {
+			t.Error(err)
+		}
+	})
+	if exts := findExtensions(); len(exts) != 0 {
+		t.Errorf("after remove: %+v", exts)
+	}
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const sampleRSS = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel>
+  <title>Blog</title>
+  <item>
+    <title>Second &amp; newest</title>
+    <link>https://example.com/2</link>
+    <guid>post-2</guid>
+    <pubDate>Tue, 13 Oct 2026 10:00:00 +0000</pubDate>
+    <dc:creator>Ada</dc:creator>
+    <description>&lt;p&gt;Hello &lt;b&gt;world&lt;/b&gt;&lt;/p&gt;</description>
+  </item>
+  <item>
+    <title>First</title>
+    <link>https://example.com/1</link>
+    <pubDate>Mon, 12 Oct 2026 10:00:00 +0000</pubDate>
+  </item>
+</channel>
+</rss>`
+
+const sampleAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Go Blog</title>
+  <entry>
+    <title>Go 1.30</title>
+    <id>tag:go.dev,2026:1.30</id>
+    <link rel="alternate" href="https://go.dev/blog/go1.30"/>
+    <published>2026-08-12T00:00:00Z</published>
+    <author><name>Gopher</name></author>
+    <summary>Released.</summary>
+  </entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	entries, err := parseFeed([]byte(sampleRSS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d RSS entries, want 2", len(entries))
+	}
+	e := entries[0]
+	if e.Title != "Second & newest" || e.GUID != "post-2" || e.Author != "Ada" || e.Summary != "Hello world" || e.Published.Day() != 13 {
+		t.Errorf("RSS entry = %+v", e)
+	}
+	if entries[1].GUID != "https://example.com/1" {
+		t.Errorf("GUID should fall back to the link, got %q", entries[1].GUID)
+	}
+
+	entries, err = parseFeed([]byte(sampleAtom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Link != "https://go.dev/blog/go1.30" || entries[0].GUID != "tag:go.dev,2026:1.30" || entries[0].Author != "Gopher" {
+		t.Errorf("Atom entries = %+v", entries)
+	}
+
+	if _, err := parseFeed([]byte("<html><body>nope</body></html>")); err == nil {
+		t.Error("expected an error for a non-feed document")
+	}
+}
+
+func TestFeedSyncDedupes(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleRSS))
+	}))
+	defer feed.Close()
+
+	var titles []string
+	existing := []interface{}{map[string]interface{}{
+		"object": "page", "id": "row0",
+		"properties": map[string]interface{}{"GUID": map[string]interface{}{
+			"type": "rich_text", "rich_text": []interface{}{map[string]interface{}{"plain_text": "post-2"}},
+		}},
+	}}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"object": "database", "id": "db",
+				"properties": map[string]interface{}{
+					"Name":      map[string]interface{}{"id": "title", "type": "title"},
+					"Link":      map[string]interface{}{"id": "l", "type": "url"},
+					"Published": map[string]interface{}{"id": "p", "type": "date"},
+					"GUID":      map[string]interface{}{"id": "g", "type": "rich_text"},
+				},
+			})
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": existing, "has_more": false})
+		default:
+			props := body["properties"].(map[string]interface{})
+			title := props["Name"].(map[string]interface{})["title"].([]interface{})[0].(map[string]interface{})
+			titles = append(titles, title["text"].(map[string]interface{})["content"].(string))
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "new"})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		captureStdout(t, func() {
+			if _, _, err := executeCommand("feed", "sync", "--url", feed.URL, "--db", "44444444444444444444444444444444"); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	if strings.Join(titles, ",") != "First" {
+		t.Errorf("added %v, want only the entry missing from the database, once", titles)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// defaultFetchWorkers keeps concurrent fetches under Notion's average rate
+// limit of about three requests per second per integration.
+const defaultFetchWorkers = 3
+
+// pageTree is the flattened block tree of one page, as produced by
+// fetchPageTrees.
+type pageTree struct {
+	PageID string
+	Blocks []map[string]interface{}
+	Err    error
+}
+
+// fetchPageTrees walks the complete block tree of every page in pageIDs
+// using up to workers goroutines, and calls fn once per page from the
+// calling goroutine (so fn needs no locking). Blocks are flattened in
+// document order. Child pages and child databases are not descended into:
+// they are pages of their own.
+func fetchPageTrees(c *notion.Client, pageIDs []string, workers int, fn func(pageTree)) {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan string)
+	results := make(chan pageTree)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				blocks, err := flattenBlockTree(c, id)
+				results <- pageTree{PageID: id, Blocks: blocks, Err: err}
+			}
+		}()
+	}
+	go func() {
+		for _, id := range pageIDs {
+			jobs <- id
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		fn(r)
+	}
+}
+
+// flattenBlockTree returns every block under parentID, depth first.
+func flattenBlockTree(c *notion.Client, parentID string) ([]map[string]interface{}, error) {
+	children, err := fetchBlockChildren(c, parentID, "", true)
+	if err != nil {
+		return nil, err
+	}
+	var out []map[string]interface{}
+	for _, ch := range children {
+		block, ok := ch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		out = append(out, block)
+
+		blockType, _ := block["type"].(string)
+		hasChildren, _ := block["has_children"].(bool)
+		if !hasChildren || blockType == "child_page" || blockType == "child_database" {
+			continue
+		}
+		id, _ := block["id"].(string)
+		nested, err := flattenBlockTree(c, id)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, nested...)
+	}
+	return out, nil
+}
@@ -10,7 +10,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
 	"github.com/spf13/cobra"
@@ -61,8 +60,7 @@ Examples:
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.Get("/v1/file_uploads")
 		if err != nil {
@@ -136,8 +134,7 @@ Examples:
 		targetID, _ := cmd.Flags().GetString("to")
 		nameOverride, _ := cmd.Flags().GetString("name")
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		outcome, err := uploadFromAny(c, source, nameOverride, targetID)
 		if err != nil {
@@ -202,7 +199,7 @@ func loadSourceFromPath(filePath, nameOverride string) (*fileSource, error) {
 
 func loadSourceFromStdin(nameOverride string) (*fileSource, error) {
 	if nameOverride == "" {
-		return nil, fmt.Errorf("--name is required when reading from stdin (notion file upload - --name <filename>)")
+		return nil, usageError(fmt.Errorf("--name is required when reading from stdin (notion file upload - --name <filename>)"))
 	}
 	data, err := io.ReadAll(os.Stdin)
 	if err != nil {
@@ -465,11 +462,10 @@ Examples:
 
 		uploadID := strings.TrimSpace(args[0])
 		if uploadID == "" {
-			return fmt.Errorf("upload id is required")
+			return usageError(fmt.Errorf("upload id is required"))
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.Get("/v1/file_uploads/" + uploadID)
 		if err != nil {
@@ -549,6 +545,7 @@ func renderFileUpload(result map[string]interface{}) {
 func init() {
 	fileUploadCmd.Flags().String("to", "", "Target page ID to attach file to")
 	fileUploadCmd.Flags().String("name", "", "Override filename (required for stdin source, optional for URL)")
+	markIDFlags(fileUploadCmd, "to")
 	fileCmd.AddCommand(fileListCmd)
 	fileCmd.AddCommand(fileUploadCmd)
 	fileCmd.AddCommand(fileGetCmd)
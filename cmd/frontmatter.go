@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// frontMatter is the YAML front matter at the top of a markdown file,
+// flattened to strings: lists are joined with commas, as property values
+// on the command line are.
+type frontMatter struct {
+	Keys   []string // in file order
+	Values map[string]string
+}
+
+// value returns the value of key, ignoring case.
+func (fm *frontMatter) value(key string) string {
+	for _, k := range fm.Keys {
+		if strings.EqualFold(k, key) {
+			return fm.Values[k]
+		}
+	}
+	return ""
+}
+
+// frontMatterTypes are the property types a well-known front matter key
+// fills when no property is named after it.
+var frontMatterTypes = map[string][]string{
+	"status": {"status", "select"},
+	"tags":   {"multi_select"},
+	"date":   {"date"},
+}
+
+// parseFrontMatter splits markdown into its front matter (between "---"
+// lines at the very top) and the rest. It reads the flat subset of YAML
+// front matter is written in: scalars, quoted strings, [inline] lists and
+// "- item" lists. Without front matter it returns nil and markdown as is.
+func parseFrontMatter(markdown string) (*frontMatter, string, error) {
+	normalized := strings.ReplaceAll(markdown, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return nil, markdown, nil
+	}
+	lines := strings.Split(normalized, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if lines[i] == "---" || lines[i] == "..." {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, markdown, nil
+	}
+
+	fm := &frontMatter{Values: map[string]string{}}
+	var listKey string
+	for n, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "- ") && listKey != "" {
+			item := unquoteYAML(strings.TrimSpace(trimmed[2:]))
+			if fm.Values[listKey] != "" {
+				item = fm.Values[listKey] + "," + item
+			}
+			fm.Values[listKey] = item
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			return nil, markdown, fmt.Errorf("front matter line %d: expected \"key: value\", got %q", n+2, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if _, dup := fm.Values[key]; !dup {
+			fm.Keys = append(fm.Keys, key)
+		}
+		listKey = ""
+		switch {
+		case value == "":
+			listKey = key
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			var items []string
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = unquoteYAML(strings.TrimSpace(item)); item != "" {
+					items = append(items, item)
+				}
+			}
+			value = strings.Join(items, ",")
+		default:
+			value = unquoteYAML(value)
+		}
+		fm.Values[key] = value
+	}
+	body := strings.TrimLeft(strings.Join(lines[end+1:], "\n"), "\n")
+	return fm, body, nil
+}
+
+// unquoteYAML strips the quotes around a YAML string, or a trailing
+// comment from an unquoted one.
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// parseFrontMatterMapping reads the front_matter setting:
+// "key=Property,key=Property".
+func parseFrontMatterMapping(setting string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(setting, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, prop, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" || strings.TrimSpace(prop) == "" {
+			return nil, fmt.Errorf("expected key=Property pairs separated by commas, got %q", pair)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(prop)
+	}
+	return mapping, nil
+}
+
+func validateFrontMatterMapping(v string) error {
+	_, err := parseFrontMatterMapping(v)
+	return err
+}
+
+// frontMatterProperties maps front matter onto a database schema. A key
+// goes to the property mapping names for it, else the property with the
+// same name (ignoring case), else — for status, tags and date — the only
+// property of a fitting type. It returns the property values and the keys
+// that matched nothing. "title" is left to the caller.
+func frontMatterProperties(fm *frontMatter, schema map[string]interface{}, mapping map[string]string) (map[string]interface{}, []string) {
+	props := map[string]interface{}{}
+	var unmatched []string
+	for _, key := range fm.Keys {
+		lower := strings.ToLower(key)
+		if lower == "title" {
+			continue
+		}
+		name := mapping[lower]
+		if name == "" {
+			for _, candidate := range sortedKeys(schema) {
+				if strings.EqualFold(candidate, key) {
+					name = candidate
+					break
+				}
+			}
+		}
+		if name == "" {
+			for _, want := range frontMatterTypes[lower] {
+				var fits []string
+				for _, candidate := range sortedKeys(schema) {
+					if prop, _ := schema[candidate].(map[string]interface{}); prop["type"] == want {
+						fits = append(fits, candidate)
+					}
+				}
+				if len(fits) == 1 {
+					name = fits[0]
+					break
+				}
+			}
+		}
+		prop, _ := schema[name].(map[string]interface{})
+		propType, _ := prop["type"].(string)
+		if prop == nil || propType == "title" || readOnlyPropertyTypes[propType] {
+			unmatched = append(unmatched, key)
+			continue
+		}
+		props[name] = buildPropertyValue(propType, fm.Values[key])
+	}
+	return props, unmatched
+}
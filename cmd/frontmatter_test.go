@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	md := "---\ntitle: \"Launch: v2\"\nstatus: In review # set by CI\ntags: [go, 'cli']\nowners:\n  - ana\n  - bo\n---\n\n# Body\n"
+	fm, body, err := parseFrontMatter(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "# Body\n" {
+		t.Errorf("body = %q", body)
+	}
+	want := map[string]string{"title": "Launch: v2", "status": "In review", "tags": "go,cli", "owners": "ana,bo"}
+	if !reflect.DeepEqual(fm.Values, want) || !reflect.DeepEqual(fm.Keys, []string{"title", "status", "tags", "owners"}) {
+		t.Errorf("front matter = %+v", fm)
+	}
+
+	if fm, body, _ := parseFrontMatter("# No front matter\n---\n"); fm != nil || body != "# No front matter\n---\n" {
+		t.Errorf("got %+v, %q", fm, body)
+	}
+	if _, _, err := parseFrontMatter("---\nnested:\n  key: value\n---\n"); err == nil {
+		t.Error("expected an error for nested maps")
+	}
+}
+
+func TestFrontMatterProperties(t *testing.T) {
+	schema := map[string]interface{}{
+		"Name":     map[string]interface{}{"type": "title"},
+		"Stage":    map[string]interface{}{"type": "status"},
+		"Labels":   map[string]interface{}{"type": "multi_select"},
+		"Due":      map[string]interface{}{"type": "date"},
+		"Priority": map[string]interface{}{"type": "select"},
+		"Created":  map[string]interface{}{"type": "created_time"},
+	}
+	fm := &frontMatter{
+		Keys:   []string{"title", "status", "tags", "date", "priority", "created", "author"},
+		Values: map[string]string{"title": "T", "status": "Done", "tags": "a,b", "date": "2026-10-16", "priority": "P1", "created": "x", "author": "me"},
+	}
+	props, unmatched := frontMatterProperties(fm, schema, map[string]string{"tags": "Labels"})
+	want := map[string]interface{}{
+		"Stage":    buildPropertyValue("status", "Done"),
+		"Labels":   buildPropertyValue("multi_select", "a,b"),
+		"Due":      buildPropertyValue("date", "2026-10-16"),
+		"Priority": buildPropertyValue("select", "P1"),
+	}
+	if !reflect.DeepEqual(props, want) {
+		t.Errorf("props = %v", props)
+	}
+	if !reflect.DeepEqual(unmatched, []string{"created", "author"}) {
+		t.Errorf("unmatched = %v", unmatched)
+	}
+}
+
+func TestImportMarkdownDirIntoDatabase(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "post.md"), []byte("---\ntitle: Hello\ntags: [a, b]\n---\nBody text\n"), 0o644)
+
+	var created map[string]interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"type": "title"},
+				"Tags": map[string]interface{}{"type": "multi_select"},
+			}})
+		case r.Method == "POST":
+			json.Unmarshal(body, &created)
+			w.Write([]byte(`{"id":"row1"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("import", "markdown-dir", dir, "--to", "66666666666666666666666666666666"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	got, _ := json.Marshal(created)
+	for _, want := range []string{`"database_id":"66666666-6666-6666-6666-666666666666"`, `"Name":{"title":[{"text":{"content":"Hello"}}]}`, `"Tags":{"multi_select":[{"name":"a"},{"name":"b"}]}`} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("create body %s lacks %s", got, want)
+		}
+	}
+}
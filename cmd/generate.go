@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// generateFuncs are the functions templates can call besides Go's own.
+var generateFuncs = template.FuncMap{
+	"split": func(s, sep string) []string {
+		var parts []string
+		for _, p := range strings.Split(s, sep) {
+			if p = strings.TrimSpace(p); p != "" {
+				parts = append(parts, p)
+			}
+		}
+		return parts
+	},
+	"join":  func(list []string, sep string) string { return strings.Join(list, sep) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// generatedPage is one record's page in 'notion generate'.
+type generatedPage struct {
+	Record   int    `json:"record"`
+	Title    string `json:"title"`
+	Action   string `json:"action"` // create, or skip when the title is taken
+	Markdown string `json:"markdown,omitempty"`
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate <template.md>",
+	Short: "Create a page per record from a template",
+	Long: `Render a markdown template once per record of --data (a CSV with a header
+row, or a JSON array of objects) and create the results as pages under
+--to: a page per customer, per quarter, per anything.
+
+The template is a Go template over the record's fields, front matter
+included: {{.customer}} inserts a field, {{range split .tags ","}}…{{end}}
+loops over a comma-separated one (JSON arrays loop as they are), and
+{{if .notes}}…{{end}} keeps a part for the records that have it. split,
+join, upper and lower are there to help; a field missing from a record
+is an error rather than an empty string.
+
+The page title is the rendered front matter's title, else --title (a
+template too). Under a database the other front matter keys set the row's
+properties, as with 'import markdown-dir'. Images on lines of their own
+are uploaded from the template's directory.
+
+Pages are deduplicated by title: a record whose title is already taken
+under --to, or by an earlier record, is skipped, so re-running after
+adding records only creates the new ones (--no-dedupe turns this off).
+--dry-run shows what would be created and skipped.
+
+Examples:
+  notion generate review.md --data customers.csv --to <parent-id> --title '{{.customer}} — Q3 review' --dry-run
+  notion generate review.md --data customers.csv --to <parent-id> --title '{{.customer}} — Q3 review'
+  notion generate onboarding.md --data hires.json --to <database-id> --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dataFile, _ := cmd.Flags().GetString("data")
+		parent, _ := cmd.Flags().GetString("to")
+		titleFlag, _ := cmd.Flags().GetString("title")
+		noDedupe, _ := cmd.Flags().GetBool("no-dedupe")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if dataFile == "" || parent == "" {
+			return usageError(fmt.Errorf("--data and --to are required"))
+		}
+
+		text, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("read template: %w", err)
+		}
+		tmpl, err := template.New(filepath.Base(args[0])).Funcs(generateFuncs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return invalidInput(fmt.Errorf("parse template: %w", err))
+		}
+		var titleTmpl *template.Template
+		if titleFlag != "" {
+			if titleTmpl, err = template.New("title").Funcs(generateFuncs).Option("missingkey=error").Parse(titleFlag); err != nil {
+				return usageError(fmt.Errorf("parse --title: %w", err))
+			}
+		}
+		records, err := readGenerateRecords(dataFile)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return fmt.Errorf("%s has no records", dataFile)
+		}
+
+		base := filepath.Base(args[0])
+		var nodes []*importNode
+		for i, record := range records {
+			node, err := generateNode(tmpl, titleTmpl, record)
+			if err != nil {
+				return invalidInput(fmt.Errorf("record %d: %w", i+1, err))
+			}
+			node.Path = fmt.Sprintf("%s (record %d)", base, i+1)
+			node.fsys = os.DirFS(filepath.Dir(args[0]))
+			nodes = append(nodes, node)
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		parentID := util.ResolveID(parent)
+		var schema map[string]interface{}
+		if db, err := c.GetDatabase(parentID); err == nil {
+			schema, _ = db["properties"].(map[string]interface{})
+		}
+
+		taken := map[string]bool{}
+		if !noDedupe {
+			if taken, err = titlesUnder(c, parentID, schema); err != nil {
+				return err
+			}
+		}
+		var pages []generatedPage
+		var create []*importNode
+		for i, n := range nodes {
+			page := generatedPage{Record: i + 1, Title: n.Title, Action: "create"}
+			key := strings.ToLower(strings.TrimSpace(n.Title))
+			if !noDedupe && taken[key] {
+				page.Action = "skip"
+			} else {
+				taken[key] = true
+				create = append(create, n)
+			}
+			if dryRun {
+				page.Markdown = n.Markdown
+			}
+			pages = append(pages, page)
+		}
+
+		if dryRun {
+			if outputFormat == "json" {
+				return render.JSON(pages)
+			}
+			for _, p := range pages {
+				if p.Action == "skip" {
+					fmt.Printf("  – %s (exists)\n", p.Title)
+				} else {
+					fmt.Printf("  📄 %s\n", p.Title)
+				}
+			}
+			fmt.Printf("\n%d page(s) would be created, %d skipped\n", len(create), len(pages)-len(create))
+			return nil
+		}
+
+		mapping := map[string]string{}
+		if cfg, _ := config.Load(); cfg != nil {
+			if mapping, err = parseFrontMatterMapping(cfg.Setting("front_matter")); err != nil {
+				return fmt.Errorf("front_matter setting: %w", err)
+			}
+		}
+		var created []map[string]interface{}
+		if err := importNodes(c, parentID, schema, mapping, nil, create, &created); err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"created": created, "count": len(created), "skipped": len(pages) - len(create)})
+		}
+		fmt.Printf("✓ Generated %d page(s)", len(created))
+		if skipped := len(pages) - len(create); skipped > 0 {
+			fmt.Printf(", skipped %d whose title exists", skipped)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+func init() {
+	generateCmd.Flags().String("data", "", "CSV or JSON file of records, one page each")
+	generateCmd.Flags().String("to", "", "Parent page or database")
+	generateCmd.Flags().String("title", "", "Title template, when the front matter has no title")
+	markIDFlags(generateCmd, "to")
+	generateCmd.Flags().Bool("no-dedupe", false, "Create pages even when their title is taken")
+	generateCmd.Flags().Bool("dry-run", false, "Show what would be created")
+}
+
+// generateNode renders the template for a record into a page.
+func generateNode(tmpl, titleTmpl *template.Template, record map[string]interface{}) (*importNode, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, record); err != nil {
+		return nil, err
+	}
+	fm, body, err := parseFrontMatter(buf.String())
+	if err != nil {
+		return nil, err
+	}
+	node := &importNode{Markdown: body, front: fm}
+	if fm != nil {
+		node.Title, node.FrontMatter = fm.value("title"), fm.Values
+	}
+	if node.Title == "" && titleTmpl != nil {
+		buf.Reset()
+		if err := titleTmpl.Execute(&buf, record); err != nil {
+			return nil, fmt.Errorf("--title: %w", err)
+		}
+		node.Title = strings.TrimSpace(buf.String())
+	}
+	if node.Title == "" {
+		return nil, fmt.Errorf("no title: give the template a title in its front matter, or use --title")
+	}
+	return node, nil
+}
+
+// readGenerateRecords reads the records of a JSON file (an array of
+// objects) or a CSV file (a header row, then a record per row).
+func readGenerateRecords(path string) ([]map[string]interface{}, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		var records []map[string]interface{}
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, invalidInput(fmt.Errorf("parse %s: want an array of objects: %w", path, err))
+		}
+		return records, nil
+	}
+	header, rows, err := readCSVFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []map[string]interface{}
+	for _, row := range rows {
+		record := make(map[string]interface{}, len(header))
+		for i, col := range header {
+			record[strings.TrimSpace(col)] = row[i]
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// titlesUnder returns the lowercased titles of the rows of a database
+// (schema non-nil) or the sub-pages of a page.
+func titlesUnder(c *notion.Client, parentID string, schema map[string]interface{}) (map[string]bool, error) {
+	titles := map[string]bool{}
+	if schema != nil {
+		var ids []string
+		for _, p := range schema {
+			if prop, _ := p.(map[string]interface{}); prop["type"] == "title" {
+				if id, _ := prop["id"].(string); id != "" {
+					ids = append(ids, id)
+				}
+			}
+		}
+		rows, err := c.QueryDatabaseAll(parentID, map[string]interface{}{}, ids...)
+		if err != nil {
+			return nil, fmt.Errorf("list existing rows: %w", err)
+		}
+		for _, r := range rows {
+			if row, ok := r.(map[string]interface{}); ok {
+				titles[strings.ToLower(strings.TrimSpace(render.ExtractTitle(row)))] = true
+			}
+		}
+		return titles, nil
+	}
+	children, err := c.GetBlockChildrenAll(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("list existing pages: %w", err)
+	}
+	for _, ch := range children {
+		block, _ := ch.(map[string]interface{})
+		if page, ok := block["child_page"].(map[string]interface{}); ok {
+			title, _ := page["title"].(string)
+			titles[strings.ToLower(strings.TrimSpace(title))] = true
+		}
+	}
+	return titles, nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	var created []string
+	var content []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/databases/"):
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "error", "code": "object_not_found", "message": "not a database"})
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/children"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"object": "block", "id": "b1", "type": "child_page", "child_page": map[string]interface{}{"title": "Globex — Q3"}},
+			}, "has_more": false})
+		case r.Method == "PATCH":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			content = append(content, mustJSON(body["children"]))
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		case r.URL.Path == "/v1/pages":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			created = append(created, mustJSON(body["properties"]))
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "new-page"})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	defer func() { outputFormat = "" }()
+
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "review.md")
+	os.WriteFile(tmpl, []byte("---\ntitle: {{.customer}} — {{.quarter}}\n---\n# Review of {{.customer}}\n{{range split .products \",\"}}\n- {{upper .}}\n{{end}}\n"), 0o644)
+	data := filepath.Join(dir, "customers.csv")
+	os.WriteFile(data, []byte("customer,quarter,products\nAcme,Q3,\"rockets, anvils\"\nGlobex,Q3,lasers\nacme,Q3,glue\n"), 0o644)
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("generate", tmpl, "--data", data, "--to", "22222222222222222222222222222222", "--dry-run"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "1 page(s) would be created, 2 skipped") || len(created) != 0 {
+		t.Errorf("dry run = %q", out)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("generate", tmpl, "--data", data, "--to", "22222222222222222222222222222222"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if len(created) != 1 || !strings.Contains(created[0], "Acme — Q3") {
+		t.Errorf("created = %v", created)
+	}
+	if len(content) != 1 || !strings.Contains(content[0], "Review of Acme") || !strings.Contains(content[0], "ROCKETS") || !strings.Contains(content[0], "ANVILS") {
+		t.Errorf("content = %v", content)
+	}
+
+	records := filepath.Join(dir, "records.json")
+	os.WriteFile(records, []byte(`[{"customer": "Initech", "quarter": "Q4", "products": "staplers"}, {"customer": "Umbrella"}]`), 0o644)
+	if _, _, err := executeCommand("generate", tmpl, "--data", records, "--to", "22222222222222222222222222222222", "--dry-run"); err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("missing field: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/util"
+)
+
+// idMap rewrites references to pages, databases, blocks and users of one
+// workspace into references to their copies in another, when content
+// exported from the first is imported into the second.
+type idMap struct {
+	ids   map[string]string // plain old ID → new ID
+	users map[string]string // plain old user ID → new user ID
+}
+
+// exportFileIDRe finds the ID Notion's Markdown & CSV export appends to
+// file names ("Roadmap 1a2b....md"), which its links point to.
+var exportFileIDRe = regexp.MustCompile(`[0-9a-f]{32}$`)
+
+// loadIDMap reads an ID map: the --map-file of 'notion migrate', a JSON
+// object of old → new IDs, or a CSV with old and new IDs in its first two
+// columns. IDs may be given as URLs.
+func loadIDMap(file string) (*idMap, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read ID map: %w", err)
+	}
+	m := &idMap{ids: map[string]string{}, users: map[string]string{}}
+	add := func(to map[string]string, pairs map[string]string) {
+		for old, id := range pairs {
+			if old, id = strings.TrimSpace(old), strings.TrimSpace(id); old != "" && id != "" {
+				to[plainID(util.ResolveID(old))] = util.ResolveID(id)
+			}
+		}
+	}
+
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		var migration struct {
+			IDs   map[string]string `json:"ids"`
+			Users map[string]string `json:"users"`
+		}
+		if json.Unmarshal(data, &migration) == nil && migration.IDs != nil {
+			add(m.ids, migration.IDs)
+			add(m.users, migration.Users)
+			return m, nil
+		}
+		var flat map[string]string
+		if err := json.Unmarshal(data, &flat); err != nil {
+			return nil, invalidInput(fmt.Errorf("ID map %s: expected an object of old → new IDs: %w", file, err))
+		}
+		add(m.ids, flat)
+		return m, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(string(data)))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, invalidInput(fmt.Errorf("ID map %s: %w", file, err))
+	}
+	pairs := map[string]string{}
+	for i, rec := range records {
+		if len(rec) < 2 {
+			return nil, invalidInput(fmt.Errorf("ID map %s: line %d: expected old,new", file, i+1))
+		}
+		if i == 0 && !util.IsID(util.ResolveID(rec[0])) {
+			continue // header
+		}
+		pairs[rec[0]] = rec[1]
+	}
+	add(m.ids, pairs)
+	return m, nil
+}
+
+// id returns the new ID of an old page, database or block.
+func (m *idMap) id(old string) (string, bool) {
+	id, ok := m.ids[plainID(util.ResolveID(old))]
+	return id, ok
+}
+
+// href points a link at the copy of what it links to: a Notion URL, or a
+// file of a Notion export, whose name ends with the page's ID. Other
+// links are returned unchanged.
+func (m *idMap) href(link string) string {
+	target, fragment, ok := notionHrefTarget(link)
+	if !ok {
+		u, err := url.Parse(link)
+		if err != nil || u.Scheme != "" || u.Host != "" {
+			return link
+		}
+		base := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+		if target = exportFileIDRe.FindString(base); target == "" {
+			return link
+		}
+		fragment = u.Fragment
+	}
+	id, ok := m.id(target)
+	if !ok {
+		return link
+	}
+	link = "https://www.notion.so/" + plainID(id)
+	if block, ok := m.id(fragment); ok && fragment != "" {
+		link += "#" + plainID(block)
+	}
+	return link
+}
+
+// rewrite rewrites in place every reference in v, which holds blocks or
+// property values as built for the API: text links, page, database and
+// user mentions, link-to-page blocks, relations and people.
+func (m *idMap) rewrite(v interface{}) {
+	switch v := v.(type) {
+	case []map[string]interface{}:
+		for _, item := range v {
+			m.rewrite(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			m.rewrite(item)
+		}
+	case map[string]interface{}:
+		if link, ok := v["link"].(map[string]interface{}); ok {
+			if u, _ := link["url"].(string); u != "" {
+				link["url"] = m.href(u)
+			}
+		}
+		if mention, ok := v["mention"].(map[string]interface{}); ok {
+			kind, _ := mention["type"].(string)
+			if ref, ok := mention[kind].(map[string]interface{}); ok {
+				m.ref(ref, kind == "user")
+			}
+		}
+		if to, ok := v["link_to_page"].(map[string]interface{}); ok {
+			for _, key := range []string{"page_id", "database_id"} {
+				if old, _ := to[key].(string); old != "" {
+					if id, ok := m.id(old); ok {
+						to[key] = id
+					}
+				}
+			}
+		}
+		for _, key := range []string{"relation", "people"} {
+			switch refs := v[key].(type) {
+			case []map[string]interface{}:
+				for _, ref := range refs {
+					m.ref(ref, key == "people")
+				}
+			case []interface{}:
+				for _, ref := range refs {
+					if ref, ok := ref.(map[string]interface{}); ok {
+						m.ref(ref, key == "people")
+					}
+				}
+			}
+		}
+		for key, child := range v {
+			if key != "link" && key != "mention" && key != "link_to_page" {
+				m.rewrite(child)
+			}
+		}
+	}
+}
+
+// ref rewrites the "id" of a reference to a page or, with user, a user.
+func (m *idMap) ref(ref map[string]interface{}, user bool) {
+	old, _ := ref["id"].(string)
+	if old == "" {
+		return
+	}
+	if user {
+		if id, ok := m.users[plainID(old)]; ok {
+			ref["id"] = id
+		}
+		return
+	}
+	if id, ok := m.id(old); ok {
+		ref["id"] = id
+	}
+}
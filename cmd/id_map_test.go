@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadIDMapFormats(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		os.WriteFile(p, []byte(content), 0o644)
+		return p
+	}
+	const old, new = "11111111111111111111111111111111", "22222222-2222-2222-2222-222222222222"
+	for name, content := range map[string]string{
+		"migrate.json": `{"to": "new", "ids": {"11111111-1111-1111-1111-111111111111": "` + new + `"}, "users": {"u-old": "u-new"}}`,
+		"flat.json":    `{"https://www.notion.so/Roadmap-` + old + `": "` + new + `"}`,
+		"map.csv":      "old,new\n" + old + "," + new + "\n",
+	} {
+		m, err := loadIDMap(write(name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if id, ok := m.id(old); !ok || id != new {
+			t.Errorf("%s: id = %q, %v", name, id, ok)
+		}
+	}
+	if _, err := loadIDMap(write("bad.json", `{"ids": 3}`)); err == nil {
+		t.Error("invalid map accepted")
+	}
+}
+
+func TestIDMapHref(t *testing.T) {
+	m := &idMap{ids: map[string]string{
+		"11111111111111111111111111111111": "22222222-2222-2222-2222-222222222222",
+		"33333333333333333333333333333333": "44444444-4444-4444-4444-444444444444",
+	}}
+	for link, want := range map[string]string{
+		"https://www.notion.so/Roadmap-11111111111111111111111111111111":     "https://www.notion.so/22222222222222222222222222222222",
+		"/11111111111111111111111111111111#33333333333333333333333333333333": "https://www.notion.so/22222222222222222222222222222222#44444444444444444444444444444444",
+		"Roadmap%2011111111111111111111111111111111.md":                      "https://www.notion.so/22222222222222222222222222222222",
+		"https://www.notion.so/Other-55555555555555555555555555555555":       "https://www.notion.so/Other-55555555555555555555555555555555",
+		"https://example.com/11111111111111111111111111111111":               "https://example.com/11111111111111111111111111111111",
+		"notes.md": "notes.md",
+	} {
+		if got := m.href(link); got != want {
+			t.Errorf("href(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestImportMarkdownDirWithIDMap(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "post.md"), []byte("---\ntitle: Hello\nproject: 11111111111111111111111111111111\n---\n"+
+		"See [the roadmap](Roadmap%2011111111111111111111111111111111.md) and [elsewhere](https://example.com).\n"), 0o644)
+	mapFile := filepath.Join(t.TempDir(), "map.json")
+	os.WriteFile(mapFile, []byte(`{"11111111-1111-1111-1111-111111111111": "22222222-2222-2222-2222-222222222222"}`), 0o644)
+
+	var bodies []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name":    map[string]interface{}{"type": "title"},
+				"Project": map[string]interface{}{"type": "relation"},
+			}})
+			return
+		}
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"id":"row1","results":[]}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("import", "markdown-dir", dir, "--to", "66666666666666666666666666666666", "--id-map", mapFile); err != nil {
+			t.Fatal(err)
+		}
+	})
+	got := strings.Join(bodies, "\n")
+	for _, want := range []string{
+		`"Project":{"relation":[{"id":"22222222-2222-2222-2222-222222222222"}]}`,
+		`"url":"https://www.notion.so/22222222222222222222222222222222"`,
+		`"url":"https://example.com"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("requests %s lack %s", got, want)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// exportIDSuffixRe matches the " <32-hex>" suffix Notion appends to every
+// file and folder name in its Markdown & CSV export.
+var exportIDSuffixRe = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+
+// importNode is one page to be created: its title, its markdown body and
+// the pages nested under it.
+type importNode struct {
+	Title    string        `json:"title"`
+	Path     string        `json:"path"`
+	Markdown string        `json:"-"`
+	Children []*importNode `json:"children,omitempty"`
+	// FrontMatter holds the file's front matter, if any.
+	FrontMatter map[string]string `json:"front_matter,omitempty"`
+
+	front *frontMatter
+	fsys  fs.FS // the tree Path is in, for the images the page refers to
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import content into Notion",
+}
+
+var importMarkdownDirCmd = &cobra.Command{
+	Use:   "markdown-dir <dir|export.zip>",
+	Short: "Import a tree of markdown files as nested pages",
+	Long: `Import a directory of markdown files as pages under a parent.
+
+Every .md file becomes a page. A sub-directory with the same name as a
+file (notes.md + notes/) holds that page's children; a sub-directory
+without a matching file becomes an empty container page.
+
+With --from-export the source is the .zip produced by Notion's
+"Export → Markdown & CSV". The 32-char ID suffix Notion appends to every
+name is stripped, the leading "# Title" line is dropped (it is already
+the page title), and the multi-part zips Notion produces for large
+workspaces are unpacked transparently. Database CSVs are skipped — use
+'notion db create --from-csv' for those.
+
+A file may start with YAML front matter. Its "title" names the page;
+when --to is a database, the files become rows and the other keys set
+their properties: each key fills the property 'notion config set
+front_matter' maps it to ("status=Stage,tags=Labels"), else the property
+of the same name, else — for status, tags and date — the only property of
+a fitting type.
+
+Images a page shows on a line of their own (![alt](images/chart.png)) are
+uploaded from the tree and embedded; other non-markdown files are skipped.
+
+Content exported from one workspace links to pages there. --id-map takes
+a map of old → new IDs — the --map-file 'notion migrate' writes, a JSON
+object, or a CSV of old,new — and points links to those pages (Notion
+URLs, and the "Page <id>.md" files of an export) at the new ones. Front
+matter relation and people values (comma-separated IDs) are mapped too.
+
+Examples:
+  notion import markdown-dir ./docs --to <parent-id>
+  notion import markdown-dir ./posts --to <database-id>
+  notion import markdown-dir Export-1a2b.zip --from-export --to <parent-id>
+  notion import markdown-dir Export-1a2b.zip --from-export --to <parent-id> --dry-run
+  notion import markdown-dir Export-1a2b.zip --from-export --to <parent-id> --id-map migrate-map.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := args[0]
+		parent, _ := cmd.Flags().GetString("to")
+		fromExport, _ := cmd.Flags().GetBool("from-export")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		idMapFile, _ := cmd.Flags().GetString("id-map")
+
+		if parent == "" && !dryRun {
+			return usageError(fmt.Errorf("--to <parent-id> is required"))
+		}
+
+		roots, err := openImportSource(source, fromExport)
+		if err != nil {
+			return err
+		}
+
+		var nodes []*importNode
+		var warnings []string
+		for _, fsys := range roots {
+			n, w, err := buildImportTree(fsys, ".", fromExport)
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, n...)
+			warnings = append(warnings, w...)
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "note: %s\n", w)
+		}
+		if len(nodes) == 0 {
+			return fmt.Errorf("no markdown files found in %s", source)
+		}
+
+		if dryRun {
+			if outputFormat == "json" {
+				return render.JSON(nodes)
+			}
+			printImportTree(nodes, 0)
+			fmt.Printf("\n%d page(s) would be created\n", countImportNodes(nodes))
+			return nil
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		parentID := util.ResolveID(parent)
+
+		// A database parent gets its rows' properties from front matter.
+		var schema map[string]interface{}
+		if db, err := c.GetDatabase(parentID); err == nil {
+			schema, _ = db["properties"].(map[string]interface{})
+		}
+		mapping := map[string]string{}
+		if cfg, _ := config.Load(); cfg != nil {
+			if mapping, err = parseFrontMatterMapping(cfg.Setting("front_matter")); err != nil {
+				return fmt.Errorf("front_matter setting: %w", err)
+			}
+		}
+
+		var ids *idMap
+		if idMapFile != "" {
+			if ids, err = loadIDMap(idMapFile); err != nil {
+				return err
+			}
+		}
+
+		var created []map[string]interface{}
+		if err := importNodes(c, parentID, schema, mapping, ids, nodes, &created); err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"created": created, "count": len(created)})
+		}
+		fmt.Printf("✓ Imported %d page(s)\n", len(created))
+		return nil
+	},
+}
+
+func init() {
+	importMarkdownDirCmd.Flags().String("to", "", "Parent page or database ID or URL to import under (required)")
+	markIDFlags(importMarkdownDirCmd, "to")
+	importMarkdownDirCmd.Flags().Bool("from-export", false, "Source is a Notion 'Markdown & CSV' export zip")
+	importMarkdownDirCmd.Flags().Bool("dry-run", false, "Print the page tree without creating anything")
+	importMarkdownDirCmd.Flags().String("id-map", "", "Rewrite links and relations to old IDs with this map (JSON or CSV, or a 'notion migrate' map file)")
+
+	importCmd.AddCommand(importMarkdownDirCmd)
+}
+
+// openImportSource returns one fs.FS per tree to import. A directory is a
+// single tree; an export zip may contain nested "Part-N.zip" archives,
+// each of which is its own tree.
+func openImportSource(source string, fromExport bool) ([]fs.FS, error) {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", source, err)
+	}
+	if fi.IsDir() {
+		return []fs.FS{os.DirFS(source)}, nil
+	}
+	if !fromExport && !strings.HasSuffix(strings.ToLower(source), ".zip") {
+		return nil, usageError(fmt.Errorf("%s is not a directory (pass --from-export for Notion export zips)", source))
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", source, err)
+	}
+	return openZipTrees(data)
+}
+
+// openZipTrees opens a zip archive held in memory. Nested .zip entries at
+// the archive root are opened as additional trees.
+func openZipTrees(data []byte) ([]fs.FS, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+	trees := []fs.FS{zr}
+	for _, f := range zr.File {
+		if strings.Contains(f.Name, "/") || !strings.HasSuffix(strings.ToLower(f.Name), ".zip") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", f.Name, err)
+		}
+		inner, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f.Name, err)
+		}
+		nested, err := openZipTrees(inner)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		trees = append(trees, nested...)
+	}
+	return trees, nil
+}
+
+// buildImportTree walks dir inside fsys and returns the pages it describes,
+// sorted by name. Non-markdown files are reported as warnings.
+func buildImportTree(fsys fs.FS, dir string, fromExport bool) ([]*importNode, []string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	dirs := map[string]bool{}
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs[e.Name()] = true
+		}
+	}
+
+	var nodes []*importNode
+	var warnings []string
+	claimed := map[string]bool{}
+
+	for _, e := range entries {
+		name := e.Name()
+		full := path.Join(dir, name)
+		if e.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(name))
+		if ext != ".md" && ext != ".markdown" {
+			if ext == ".csv" {
+				warnings = append(warnings, fmt.Sprintf("skipped database export %s (use 'notion db create --from-csv')", full))
+			} else if strings.HasPrefix(mime.TypeByExtension(ext), "image/") {
+				warnings = append(warnings, fmt.Sprintf("%s is only imported where a page shows it", full))
+			} else if ext != ".zip" {
+				warnings = append(warnings, fmt.Sprintf("skipped %s (only markdown is imported)", full))
+			}
+			continue
+		}
+		data, err := fs.ReadFile(fsys, full)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read %s: %w", full, err)
+		}
+		base := strings.TrimSuffix(name, path.Ext(name))
+		node := &importNode{
+			Title:    importTitle(base, fromExport),
+			Path:     full,
+			Markdown: string(data),
+			fsys:     fsys,
+		}
+		if fromExport {
+			node.Markdown = stripTitleHeading(node.Markdown, node.Title)
+		}
+		fm, body, err := parseFrontMatter(node.Markdown)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", full, err)
+		}
+		if fm != nil {
+			node.Markdown, node.front, node.FrontMatter = body, fm, fm.Values
+			if title := fm.value("title"); title != "" {
+				node.Title = title
+			}
+		}
+		if dirs[base] {
+			claimed[base] = true
+			children, w, err := buildImportTree(fsys, path.Join(dir, base), fromExport)
+			if err != nil {
+				return nil, nil, err
+			}
+			node.Children = children
+			warnings = append(warnings, w...)
+		}
+		nodes = append(nodes, node)
+	}
+
+	// Directories with no matching markdown file become container pages.
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || claimed[name] || strings.HasPrefix(name, ".") {
+			continue
+		}
+		children, w, err := buildImportTree(fsys, path.Join(dir, name), fromExport)
+		if err != nil {
+			return nil, nil, err
+		}
+		warnings = append(warnings, w...)
+		if len(children) == 0 {
+			continue
+		}
+		nodes = append(nodes, &importNode{
+			Title:    importTitle(name, fromExport),
+			Path:     path.Join(dir, name),
+			Children: children,
+		})
+	}
+
+	return nodes, warnings, nil
+}
+
+// importTitle turns a file or folder base name into a page title. For
+// Notion exports the trailing 32-char object ID is removed.
+func importTitle(base string, fromExport bool) string {
+	if fromExport {
+		base = exportIDSuffixRe.ReplaceAllString(base, "")
+	}
+	base = strings.TrimSpace(base)
+	if base == "" {
+		return "Untitled"
+	}
+	return base
+}
+
+// stripTitleHeading drops the "# Title" line Notion writes at the top of
+// every exported page, along with the blank lines that follow it.
+func stripTitleHeading(markdown, title string) string {
+	lines := strings.Split(markdown, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "# "+title {
+		return markdown
+	}
+	i := 1
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	return strings.Join(lines[i:], "\n")
+}
+
+// importNodes creates each node as a page under parentID, fills it with the
+// parsed markdown, and recurses into its children. When parentID is a
+// database, schema is its properties, and the nodes become rows whose
+// properties are set from their front matter through mapping. A non-nil
+// ids rewrites links, mentions and relations to pages copied elsewhere.
+func importNodes(c *notion.Client, parentID string, schema map[string]interface{}, mapping map[string]string, ids *idMap, nodes []*importNode, created *[]map[string]interface{}) error {
+	for _, n := range nodes {
+		body := map[string]interface{}{
+			"parent": map[string]interface{}{"page_id": parentID},
+			"properties": map[string]interface{}{
+				"title": buildPropertyValue("title", n.Title),
+			},
+		}
+		if schema != nil {
+			props := map[string]interface{}{}
+			var unmatched []string
+			if n.front != nil {
+				props, unmatched = frontMatterProperties(n.front, schema, mapping)
+			}
+			for name, p := range schema {
+				if prop, _ := p.(map[string]interface{}); prop["type"] == "title" {
+					props[name] = buildPropertyValue("title", n.Title)
+				}
+			}
+			body = map[string]interface{}{
+				"parent":     map[string]interface{}{"database_id": parentID},
+				"properties": props,
+			}
+			if len(unmatched) > 0 {
+				fmt.Fprintf(os.Stderr, "note: %s: no property for front matter %s\n", n.Path, strings.Join(unmatched, ", "))
+			}
+			if ids != nil {
+				ids.rewrite(props)
+			}
+		} else if n.front != nil && len(n.front.Keys) > 0 && !(len(n.front.Keys) == 1 && n.front.value("title") != "") {
+			fmt.Fprintf(os.Stderr, "note: %s: front matter other than title is ignored under a page; import into a database to set properties\n", n.Path)
+		}
+		data, err := c.Post("/v1/pages", body)
+		if err != nil {
+			return fmt.Errorf("create page %q (%s): %w", n.Title, n.Path, err)
+		}
+		var page map[string]interface{}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		id, _ := page["id"].(string)
+
+		blocks, _, err := parseMarkdownWithImages(c, n.Markdown, fsImages(n.fsys, path.Dir(n.Path)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", n.Path, err)
+		}
+		if ids != nil {
+			ids.rewrite(blocks)
+		}
+		if len(blocks) > 0 {
+			blocks, err = handleOversizedBlocks(blocks, oversizeSplit)
+			if err != nil {
+				return err
+			}
+			if _, err := appendChildrenBatched(c, id, "", blocks); err != nil {
+				return fmt.Errorf("append content to %q: %w", n.Title, err)
+			}
+		}
+
+		*created = append(*created, map[string]interface{}{"title": n.Title, "id": id, "source": n.Path})
+		if outputFormat != "json" {
+			fmt.Fprintf(os.Stderr, "  ✓ %s\n", n.Path)
+		}
+
+		if err := importNodes(c, id, nil, mapping, ids, n.Children, created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printImportTree(nodes []*importNode, indent int) {
+	for _, n := range nodes {
+		fmt.Printf("%s📄 %s\n", strings.Repeat("  ", indent), n.Title)
+		printImportTree(n.Children, indent+1)
+	}
+}
+
+func countImportNodes(nodes []*importNode) int {
+	total := 0
+	for _, n := range nodes {
+		total += 1 + countImportNodes(n.Children)
+	}
+	return total
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"testing/fstest"
+)
+
+func TestImportTitle_StripsExportID(t *testing.T) {
+	tests := []struct {
+		base       string
+		fromExport bool
+		want       string
+	}{
+		{"Meeting Notes 0123456789abcdef0123456789abcdef", true, "Meeting Notes"},
+		{"Meeting Notes 0123456789abcdef0123456789abcdef", false, "Meeting Notes 0123456789abcdef0123456789abcdef"},
+		{"plain", true, "plain"},
+		{"  ", false, "Untitled"},
+	}
+	for _, tt := range tests {
+		if got := importTitle(tt.base, tt.fromExport); got != tt.want {
+			t.Errorf("importTitle(%q, %v) = %q, want %q", tt.base, tt.fromExport, got, tt.want)
+		}
+	}
+}
+
+func TestStripTitleHeading(t *testing.T) {
+	got := stripTitleHeading("# Roadmap\n\n\nFirst line\n", "Roadmap")
+	if got != "First line\n" {
+		t.Errorf("got %q", got)
+	}
+	// A different first heading is content, not the title.
+	in := "# Other\nbody"
+	if got := stripTitleHeading(in, "Roadmap"); got != in {
+		t.Errorf("got %q, want unchanged", got)
+	}
+}
+
+func TestBuildImportTree_ExportLayout(t *testing.T) {
+	id := "0123456789abcdef0123456789abcdef"
+	fsys := fstest.MapFS{
+		"Wiki " + id + ".md":                   {Data: []byte("# Wiki\n\nHome")},
+		"Wiki " + id + "/Child " + id + ".md":  {Data: []byte("# Child\n\nNested")},
+		"Wiki " + id + "/Tasks " + id + ".csv": {Data: []byte("Name\nA")},
+		"Loose " + id + "/Only " + id + ".md":  {Data: []byte("# Only\n")},
+		"Wiki " + id + "/image.png":            {Data: []byte{0x89}},
+	}
+
+	nodes, warnings, err := buildImportTree(fsys, ".", true)
+	if err != nil {
+		t.Fatalf("buildImportTree: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("got %d root nodes, want 2", len(nodes))
+	}
+	wiki := nodes[0]
+	if wiki.Title != "Wiki" || wiki.Markdown != "Home" {
+		t.Errorf("wiki node = %+v", wiki)
+	}
+	if len(wiki.Children) != 1 || wiki.Children[0].Title != "Child" {
+		t.Errorf("wiki children = %+v", wiki.Children)
+	}
+	loose := nodes[1]
+	if loose.Title != "Loose" || loose.Markdown != "" || len(loose.Children) != 1 {
+		t.Errorf("container node = %+v", loose)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("warnings = %v, want csv + png", warnings)
+	}
+	if countImportNodes(nodes) != 4 {
+		t.Errorf("countImportNodes = %d, want 4", countImportNodes(nodes))
+	}
+}
+
+func TestOpenZipTrees_NestedParts(t *testing.T) {
+	inner := zipBytes(t, map[string]string{"Page.md": "# Page\n"})
+	outer := zipBytes(t, map[string]string{"Part-1.zip": string(inner), "Top.md": "hi"})
+
+	trees, err := openZipTrees(outer)
+	if err != nil {
+		t.Fatalf("openZipTrees: %v", err)
+	}
+	if len(trees) != 2 {
+		t.Fatalf("got %d trees, want 2 (outer + nested part)", len(trees))
+	}
+	nodes, _, err := buildImportTree(trees[1], ".", true)
+	if err != nil || len(nodes) != 1 || nodes[0].Title != "Page" {
+		t.Errorf("nested tree nodes = %+v, err = %v", nodes, err)
+	}
+}
+
+func zipBytes(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+// notionWebHosts are the hosts whose URLs the desktop app can open.
+var notionWebHosts = map[string]bool{
+	"notion.so":      true,
+	"www.notion.so":  true,
+	"app.notion.com": true,
+}
+
+// deepLinksEnabled reports whether URLs should be emitted as notion://
+// links: --app-links, NOTION_DEEP_LINKS=1, or 'notion config set
+// deep_links true'.
+func deepLinksEnabled() bool {
+	if appLinks {
+		return true
+	}
+	if isTruthy(os.Getenv("NOTION_DEEP_LINKS")) {
+		return true
+	}
+	cfg, err := config.Load()
+	return err == nil && isTruthy(cfg.Setting("deep_links"))
+}
+
+// notionLink returns rawURL as it should be shown or opened: unchanged by
+// default, or as a desktop deep link when deep links are enabled. Only
+// Notion web URLs are rewritten; file and external URLs pass through.
+func notionLink(rawURL string) string {
+	if !deepLinksEnabled() {
+		return rawURL
+	}
+	return desktopURL(rawURL)
+}
+
+// desktopURL rewrites a Notion web URL into the notion:// scheme the
+// desktop app registers. Other URLs are returned unchanged.
+func desktopURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || !notionWebHosts[u.Host] {
+		return rawURL
+	}
+	return "notion://" + strings.TrimPrefix(strings.TrimPrefix(rawURL, "https://"), "http://")
+}
+
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
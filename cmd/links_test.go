@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func TestNotionLink(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("NOTION_DEEP_LINKS", "")
+	appLinks = false
+
+	page := "https://www.notion.so/Roadmap-abc"
+	if got := notionLink(page); got != page {
+		t.Errorf("disabled: got %q", got)
+	}
+
+	config.Save(&config.Config{Settings: map[string]string{"deep_links": "true"}})
+	if got := notionLink(page); got != "notion://www.notion.so/Roadmap-abc" {
+		t.Errorf("config enabled: got %q", got)
+	}
+	file := "https://prod-files-secure.s3.us-west-2.amazonaws.com/x.png"
+	if got := notionLink(file); got != file {
+		t.Errorf("non-Notion URL rewritten: %q", got)
+	}
+
+	config.Save(&config.Config{})
+	t.Setenv("NOTION_DEEP_LINKS", "1")
+	if got := notionLink("https://app.notion.com/p/abc"); got != "notion://app.notion.com/p/abc" {
+		t.Errorf("env enabled: got %q", got)
+	}
+}
+
+func TestDesktopURL(t *testing.T) {
+	if got := desktopURL("https://www.notion.so/abc"); got != "notion://www.notion.so/abc" {
+		t.Errorf("desktopURL = %q", got)
+	}
+	if got := desktopURL("notion://www.notion.so/abc"); got != "notion://www.notion.so/abc" {
+		t.Errorf("desktopURL passthrough = %q", got)
+	}
+}
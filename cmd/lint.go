@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// linkRef is one link found in a block.
+type linkRef struct {
+	// Notion is true when Target is a Notion object ID, false for an
+	// external URL.
+	Notion bool
+	// Object is "page", "database" or "" when the link doesn't say.
+	Object string
+	Target string
+	Source string
+}
+
+// brokenLink is one entry of the 'lint links' report.
+type brokenLink struct {
+	PageID  string `json:"page_id"`
+	Page    string `json:"page"`
+	BlockID string `json:"block_id"`
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Problem string `json:"problem"`
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check pages for problems",
+}
+
+var lintLinksCmd = &cobra.Command{
+	Use:   "links [page-id|url ...]",
+	Short: "Find broken Notion links and dead external URLs",
+	Long: `Scan pages for links that no longer work.
+
+Notion links (link_to_page blocks, @-mentions and notion.so URLs) are
+broken when the target is archived, in the trash, deleted or not shared
+with the integration. External URLs (inline links, bookmarks, embeds,
+external images and files) are dead when they fail to load or answer
+with HTTP 4xx/5xx.
+
+Pass the pages to scan, or --all for every shared page. The report lists
+the block ID of each broken link so it can be fixed with
+'notion block update'. The command exits non-zero when anything is broken.
+
+Examples:
+  notion lint links abc123
+  notion lint links abc123 def456 --no-external
+  notion lint links --all --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		noExternal, _ := cmd.Flags().GetBool("no-external")
+		workers, _ := cmd.Flags().GetInt("workers")
+		if len(args) == 0 && !all {
+			return usageError(fmt.Errorf("pass page IDs to scan, or --all"))
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+
+		titles := map[string]string{}
+		var ids []string
+		if all {
+			pages, err := searchAllPages(c, 0)
+			if err != nil {
+				return err
+			}
+			for _, p := range pages {
+				id, _ := p["id"].(string)
+				titles[id] = render.ExtractTitle(p)
+				ids = append(ids, id)
+			}
+		} else {
+			for _, a := range args {
+				id := util.ResolveID(a)
+				page, err := c.GetPage(id)
+				if err != nil {
+					return fmt.Errorf("get page %s: %w", a, err)
+				}
+				titles[id] = render.ExtractTitle(page)
+				ids = append(ids, id)
+			}
+		}
+
+		type found struct {
+			pageID, blockID string
+			ref             linkRef
+		}
+		var refs []found
+		scanned := 0
+		fetchPageTrees(c, ids, workers, func(tree pageTree) {
+			scanned++
+			if outputFormat != "json" {
+				fmt.Fprintf(os.Stderr, "\r  scanned %d/%d pages", scanned, len(ids))
+			}
+			if tree.Err != nil {
+				fmt.Fprintf(os.Stderr, "\nnote: %s: %s\n", tree.PageID, firstLine(tree.Err))
+			}
+			for _, b := range tree.Blocks {
+				blockID, _ := b["id"].(string)
+				for _, ref := range blockLinks(b) {
+					if !ref.Notion && noExternal {
+						continue
+					}
+					refs = append(refs, found{tree.PageID, blockID, ref})
+				}
+			}
+		})
+		if outputFormat != "json" && len(ids) > 0 {
+			fmt.Fprintln(os.Stderr)
+		}
+
+		// Check each distinct target once.
+		notionProblems := map[string]string{}
+		var urls []string
+		seenURL := map[string]bool{}
+		for _, f := range refs {
+			if f.ref.Notion {
+				if _, ok := notionProblems[f.ref.Target]; !ok {
+					notionProblems[f.ref.Target] = checkNotionTarget(c, f.ref.Target, f.ref.Object)
+				}
+			} else if !seenURL[f.ref.Target] {
+				seenURL[f.ref.Target] = true
+				urls = append(urls, f.ref.Target)
+			}
+		}
+		urlProblems := checkExternalURLs(urls, workers*3)
+
+		var report []brokenLink
+		for _, f := range refs {
+			problem := urlProblems[f.ref.Target]
+			if f.ref.Notion {
+				problem = notionProblems[f.ref.Target]
+			}
+			if problem == "" {
+				continue
+			}
+			report = append(report, brokenLink{
+				PageID:  f.pageID,
+				Page:    titles[f.pageID],
+				BlockID: f.blockID,
+				Source:  f.ref.Source,
+				Target:  f.ref.Target,
+				Problem: problem,
+			})
+		}
+
+		if outputFormat == "json" {
+			if report == nil {
+				report = []brokenLink{}
+			}
+			if err := render.JSON(map[string]interface{}{"scanned": len(ids), "links": len(refs), "broken": report}); err != nil {
+				return err
+			}
+		} else if len(report) == 0 {
+			fmt.Printf("✓ %d link(s) in %d page(s), none broken\n", len(refs), len(ids))
+		} else {
+			var rows [][]string
+			for _, r := range report {
+				rows = append(rows, []string{r.Page, r.BlockID, r.Source, r.Target, r.Problem})
+			}
+			render.Table([]string{"PAGE", "BLOCK", "SOURCE", "TARGET", "PROBLEM"}, rows)
+		}
+		if len(report) > 0 {
+			return fmt.Errorf("%d broken link(s) found", len(report))
+		}
+		return nil
+	},
+}
+
+func init() {
+	lintLinksCmd.Flags().Bool("all", false, "Scan every page shared with the integration")
+	lintLinksCmd.Flags().Bool("no-external", false, "Only check Notion links")
+	lintLinksCmd.Flags().Int("workers", defaultFetchWorkers, "Pages fetched concurrently (external URLs use 3x)")
+	lintCmd.AddCommand(lintLinksCmd)
+}
+
+// blockLinks extracts every Notion and external link from one block.
+func blockLinks(block map[string]interface{}) []linkRef {
+	blockType, _ := block["type"].(string)
+	data, _ := block[blockType].(map[string]interface{})
+	var refs []linkRef
+
+	switch blockType {
+	case "link_to_page":
+		if id, _ := data["page_id"].(string); id != "" {
+			refs = append(refs, linkRef{Notion: true, Object: "page", Target: util.ResolveID(id), Source: "link_to_page"})
+		} else if id, _ := data["database_id"].(string); id != "" {
+			refs = append(refs, linkRef{Notion: true, Object: "database", Target: util.ResolveID(id), Source: "link_to_page"})
+		}
+	case "bookmark", "embed", "link_preview":
+		if u, _ := data["url"].(string); u != "" {
+			refs = append(refs, urlRef(u, blockType))
+		}
+	case "image", "video", "file", "pdf", "audio":
+		if ext, ok := data["external"].(map[string]interface{}); ok {
+			if u, _ := ext["url"].(string); u != "" {
+				refs = append(refs, urlRef(u, blockType))
+			}
+		}
+	}
+
+	richText, _ := data["rich_text"].([]interface{})
+	if caption, ok := data["caption"].([]interface{}); ok {
+		richText = append(richText, caption...)
+	}
+	for _, rt := range richText {
+		item, _ := rt.(map[string]interface{})
+		if mention, ok := item["mention"].(map[string]interface{}); ok {
+			for _, key := range []string{"page", "database"} {
+				if ref, ok := mention[key].(map[string]interface{}); ok {
+					if id, _ := ref["id"].(string); id != "" {
+						refs = append(refs, linkRef{Notion: true, Object: key, Target: util.ResolveID(id), Source: "mention"})
+					}
+				}
+			}
+			continue
+		}
+		if href, _ := item["href"].(string); href != "" {
+			refs = append(refs, urlRef(href, "link"))
+		}
+	}
+	return refs
+}
+
+// urlRef classifies a URL as a Notion link (when it points at a Notion
+// object) or an external one. Relative links ("/abc123") are what Notion
+// stores for in-workspace page links.
+func urlRef(u, source string) linkRef {
+	if strings.HasPrefix(u, "/") {
+		u = "https://www.notion.so" + u
+	}
+	if util.IsID(strings.SplitN(u, "#", 2)[0]) && isNotionURL(u) {
+		return linkRef{Notion: true, Target: util.ResolveID(strings.SplitN(u, "#", 2)[0]), Source: source}
+	}
+	return linkRef{Target: u, Source: source}
+}
+
+func isNotionURL(u string) bool {
+	return strings.Contains(u, "notion.so") || strings.Contains(u, "notion.site") || strings.Contains(u, "app.notion.com")
+}
+
+// checkNotionTarget returns why a linked Notion object is unusable, or ""
+// when it is fine. object narrows the lookup when the link says whether it
+// points at a page or a database.
+func checkNotionTarget(c *notion.Client, id, object string) string {
+	var obj map[string]interface{}
+	var err error
+	if object != "database" {
+		obj, err = c.GetPage(id)
+	}
+	if object == "database" || (err != nil && object == "") {
+		obj, err = c.GetDatabase(id)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, notion.ErrNotFound):
+			return "not found or not shared"
+		case errors.Is(err, notion.ErrRestricted):
+			return "inaccessible"
+		}
+		return firstLine(err)
+	}
+	if inTrash, _ := obj["in_trash"].(bool); inTrash {
+		return "in trash"
+	}
+	if archived, _ := obj["archived"].(bool); archived {
+		return "archived"
+	}
+	return ""
+}
+
+// linkCheckClient is used for external URL checks.
+var linkCheckClient = &http.Client{Timeout: 10 * time.Second}
+
+// checkExternalURLs requests every URL with up to workers in flight and
+// returns the problem for each URL that failed.
+func checkExternalURLs(urls []string, workers int) map[string]string {
+	problems := map[string]string{}
+	if workers < 1 {
+		workers = 1
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for _, u := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if problem := checkExternalURL(u); problem != "" {
+				mu.Lock()
+				problems[u] = problem
+				mu.Unlock()
+			}
+		}(u)
+	}
+	wg.Wait()
+	return problems
+}
+
+// checkExternalURL tries HEAD, then GET for servers that reject HEAD.
+func checkExternalURL(u string) string {
+	status := 0
+	for _, method := range []string{"HEAD", "GET"} {
+		req, err := http.NewRequest(method, u, nil)
+		if err != nil {
+			return "invalid URL"
+		}
+		req.Header.Set("User-Agent", "notion-cli link checker")
+		resp, err := linkCheckClient.Do(req)
+		if err != nil {
+			return "unreachable: " + firstLine(err)
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		if status < 400 {
+			return ""
+		}
+		if status != http.StatusMethodNotAllowed && status != http.StatusForbidden && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return fmt.Sprintf("HTTP %d", status)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestBlockLinks(t *testing.T) {
+	block := map[string]interface{}{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{
+		map[string]interface{}{"type": "mention", "mention": map[string]interface{}{"type": "page", "page": map[string]interface{}{"id": "11111111-1111-1111-1111-111111111111"}}},
+		map[string]interface{}{"type": "text", "href": "/22222222222222222222222222222222"},
+		map[string]interface{}{"type": "text", "href": "https://example.com/docs"},
+	}}}
+	refs := blockLinks(block)
+	if len(refs) != 3 {
+		t.Fatalf("len(refs) = %d, want 3: %+v", len(refs), refs)
+	}
+	if !refs[0].Notion || refs[0].Object != "page" || refs[0].Source != "mention" {
+		t.Errorf("mention ref = %+v", refs[0])
+	}
+	if !refs[1].Notion || refs[1].Target != "22222222-2222-2222-2222-222222222222" {
+		t.Errorf("relative notion link = %+v", refs[1])
+	}
+	if refs[2].Notion || refs[2].Target != "https://example.com/docs" {
+		t.Errorf("external link = %+v", refs[2])
+	}
+
+	bookmark := map[string]interface{}{"type": "bookmark", "bookmark": map[string]interface{}{"url": "https://example.org"}}
+	if refs := blockLinks(bookmark); len(refs) != 1 || refs[0].Source != "bookmark" {
+		t.Errorf("bookmark refs = %+v", refs)
+	}
+}
+
+func TestCheckNotionTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/archived"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "archived": true})
+		case strings.HasSuffix(r.URL.Path, "/ok"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page"})
+		default:
+			w.WriteHeader(404)
+			json.NewEncoder(w).Encode(map[string]string{"code": "object_not_found", "message": "Could not find page"})
+		}
+	}))
+	defer server.Close()
+	c := notion.New("tok", notion.WithBaseURL(server.URL))
+
+	if got := checkNotionTarget(c, "ok", "page"); got != "" {
+		t.Errorf("ok = %q", got)
+	}
+	if got := checkNotionTarget(c, "archived", "page"); got != "archived" {
+		t.Errorf("archived = %q", got)
+	}
+	if got := checkNotionTarget(c, "gone", ""); got != "not found or not shared" {
+		t.Errorf("gone = %q", got)
+	}
+}
+
+func TestCheckExternalURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(200)
+		case "/nohead":
+			if r.Method == "HEAD" {
+				w.WriteHeader(405)
+				return
+			}
+			w.WriteHeader(200)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	problems := checkExternalURLs([]string{server.URL + "/ok", server.URL + "/nohead", server.URL + "/missing"}, 2)
+	if len(problems) != 1 || problems[server.URL+"/missing"] != "HTTP 404" {
+		t.Errorf("problems = %v", problems)
+	}
+}
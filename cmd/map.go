@@ -0,0 +1,462 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// maxBlockDepth bounds the walk from a block up to the page holding it.
+const maxBlockDepth = 20
+
+var mapCmd = &cobra.Command{
+	Use:   "map",
+	Short: "Map every page and database the integration can reach",
+	Long: `Build a graph of the pages and databases shared with the integration:
+parent/child links, relations between databases, and (with --deep) links
+and mentions inside pages.
+
+Formats:
+  tree - The page hierarchy, then relations and links (default)
+  dot  - Graphviz: parents as solid edges, relations dashed, links dotted
+  json - {"nodes": [...], "edges": [...]}
+
+Objects whose parent isn't shared are listed under "Unshared parents":
+they are reachable, but not from the top of the workspace. Database rows
+are counted, not listed, unless --rows is given.
+
+Examples:
+  notion map
+  notion map --format dot | dot -Tsvg > workspace.svg
+  notion map --deep --format json -o map.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		rows, _ := cmd.Flags().GetBool("rows")
+		deep, _ := cmd.Flags().GetBool("deep")
+		workers, _ := cmd.Flags().GetInt("workers")
+		outputPath, _ := cmd.Flags().GetString("output")
+		switch format {
+		case "tree", "dot", "json":
+		default:
+			return usageError(fmt.Errorf("unknown format %q (want tree, dot or json)", format))
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+
+		m, err := buildWorkspaceMap(c, rows, deep, workers)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if outputPath != "" {
+			f, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("create output file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		switch format {
+		case "dot":
+			writeMapDOT(w, m)
+		case "json":
+			if outputPath == "" {
+				return render.JSON(m)
+			}
+			data, _ := json.MarshalIndent(m, "", "  ")
+			_, err = w.Write(append(data, '\n'))
+			return err
+		default:
+			writeMapTree(w, m)
+		}
+		return nil
+	},
+}
+
+func init() {
+	mapCmd.Flags().String("format", "tree", "Output format: tree, dot, json")
+	mapCmd.Flags().Bool("rows", false, "Include database rows (and the relations between them)")
+	mapCmd.Flags().Bool("deep", false, "Read every page to find links and mentions between pages")
+	mapCmd.Flags().Int("workers", defaultFetchWorkers, "Pages read concurrently with --deep")
+	mapCmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+}
+
+// mapNode is a page or database in the workspace map.
+type mapNode struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	Title  string `json:"title"`
+	URL    string `json:"url,omitempty"`
+	// Parent is the parent's ID, "workspace" for top-level objects, or ""
+	// when it couldn't be determined.
+	Parent string `json:"parent,omitempty"`
+	// Row marks a database row; Rows counts the rows of a database.
+	Row  bool `json:"row,omitempty"`
+	Rows int  `json:"rows,omitempty"`
+}
+
+// mapEdge links two nodes: "parent" (From contains To), "relation"
+// (Label names the property) or "link" (From links to or mentions To).
+type mapEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Kind  string `json:"kind"`
+	Label string `json:"label,omitempty"`
+}
+
+// workspaceMap is the graph 'notion map' prints.
+type workspaceMap struct {
+	Nodes []*mapNode `json:"nodes"`
+	Edges []mapEdge  `json:"edges"`
+}
+
+// buildWorkspaceMap searches for everything shared with the integration
+// and links it up. Rows are dropped unless rows is set; deep reads every
+// page for links.
+func buildWorkspaceMap(c *notion.Client, rows, deep bool, workers int) (*workspaceMap, error) {
+	results, err := c.SearchAll("", "")
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	nodes := map[string]*mapNode{}
+	objects := map[string]map[string]interface{}{}
+	for _, r := range results {
+		obj, _ := r.(map[string]interface{})
+		id, _ := obj["id"].(string)
+		if id == "" {
+			continue
+		}
+		kind, _ := obj["object"].(string)
+		url, _ := obj["url"].(string)
+		nodes[id] = &mapNode{ID: id, Object: kind, Title: render.ExtractTitle(obj), URL: url}
+		objects[id] = obj
+	}
+
+	blockPages := map[string]string{}
+	m := &workspaceMap{}
+	for id, obj := range objects {
+		n := nodes[id]
+		parent, _ := obj["parent"].(map[string]interface{})
+		switch kind, _ := parent["type"].(string); kind {
+		case "workspace":
+			n.Parent = "workspace"
+		case "page_id", "database_id":
+			n.Parent, _ = parent[kind].(string)
+			n.Row = kind == "database_id"
+		case "block_id":
+			blockID, _ := parent["block_id"].(string)
+			n.Parent = blockPage(c, blockID, blockPages)
+		}
+		if n.Row {
+			if db := nodes[n.Parent]; db != nil {
+				db.Rows++
+			}
+		}
+	}
+
+	keep := func(id string) bool {
+		n := nodes[id]
+		return n != nil && (rows || !n.Row)
+	}
+	if !rows {
+		// Pages inside rows hang off the row's database instead.
+		for _, n := range nodes {
+			for depth := 0; depth < maxParentDepth && nodes[n.Parent] != nil && !keep(n.Parent); depth++ {
+				n.Parent = nodes[n.Parent].Parent
+			}
+		}
+	}
+	seen := map[mapEdge]bool{}
+	addEdge := func(e mapEdge) {
+		if e.From != e.To && !seen[e] {
+			seen[e] = true
+			m.Edges = append(m.Edges, e)
+		}
+	}
+	for id, obj := range objects {
+		if !keep(id) {
+			continue
+		}
+		n := nodes[id]
+		if keep(n.Parent) {
+			addEdge(mapEdge{From: n.Parent, To: id, Kind: "parent"})
+		}
+		props, _ := obj["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(props) {
+			prop, _ := props[name].(map[string]interface{})
+			if prop["type"] != "relation" {
+				continue
+			}
+			if n.Object == "database" {
+				rel, _ := prop["relation"].(map[string]interface{})
+				if target, _ := rel["database_id"].(string); keep(target) {
+					addEdge(mapEdge{From: id, To: target, Kind: "relation", Label: name})
+				}
+				continue
+			}
+			refs, _ := prop["relation"].([]interface{})
+			for _, ref := range refs {
+				r, _ := ref.(map[string]interface{})
+				if target, _ := r["id"].(string); keep(target) {
+					addEdge(mapEdge{From: id, To: target, Kind: "relation", Label: name})
+				}
+			}
+		}
+	}
+
+	if deep {
+		var pageIDs []string
+		for id, n := range nodes {
+			if n.Object == "page" && keep(id) {
+				pageIDs = append(pageIDs, id)
+			}
+		}
+		sort.Strings(pageIDs)
+		byPlainID := map[string]string{}
+		for id := range nodes {
+			byPlainID[plainID(id)] = id
+		}
+		fetchPageTrees(c, pageIDs, workers, func(tree pageTree) {
+			if tree.Err != nil {
+				fmt.Fprintf(os.Stderr, "note: %s: %s\n", tree.PageID, firstLine(tree.Err))
+			}
+			for _, b := range tree.Blocks {
+				var targets []string
+				for _, ref := range blockLinks(b) {
+					if ref.Notion {
+						targets = append(targets, ref.Target)
+					}
+				}
+				targets = append(targets, mentionedIDs(b)...)
+				for _, target := range targets {
+					if id := byPlainID[plainID(target)]; keep(id) {
+						addEdge(mapEdge{From: tree.PageID, To: id, Kind: "link"})
+					}
+				}
+			}
+		})
+	}
+
+	for id, n := range nodes {
+		if keep(id) {
+			m.Nodes = append(m.Nodes, n)
+		}
+	}
+	sort.Slice(m.Nodes, func(i, j int) bool { return m.Nodes[i].ID < m.Nodes[j].ID })
+	sort.Slice(m.Edges, func(i, j int) bool {
+		a, b := m.Edges[i], m.Edges[j]
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return m, nil
+}
+
+// blockPage returns the page or database a block sits in, walking up
+// through its parent blocks, or "" when that fails.
+func blockPage(c *notion.Client, blockID string, cache map[string]string) string {
+	if id, ok := cache[blockID]; ok {
+		return id
+	}
+	id := blockID
+	for depth := 0; depth < maxBlockDepth; depth++ {
+		b, err := c.GetBlock(id)
+		if err != nil {
+			break
+		}
+		parent, _ := b["parent"].(map[string]interface{})
+		kind, _ := parent["type"].(string)
+		if kind != "block_id" {
+			if kind == "page_id" || kind == "database_id" {
+				cache[blockID], _ = parent[kind].(string)
+			}
+			break
+		}
+		id, _ = parent["block_id"].(string)
+	}
+	return cache[blockID]
+}
+
+// mentionedIDs returns the pages and databases @-mentioned in a block's
+// rich text.
+func mentionedIDs(block map[string]interface{}) []string {
+	blockType, _ := block["type"].(string)
+	data, _ := block[blockType].(map[string]interface{})
+	rt, _ := data["rich_text"].([]interface{})
+	var ids []string
+	for _, t := range rt {
+		seg, _ := t.(map[string]interface{})
+		mention, _ := seg["mention"].(map[string]interface{})
+		for _, kind := range []string{"page", "database"} {
+			ref, _ := mention[kind].(map[string]interface{})
+			if id, _ := ref["id"].(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// plainID normalizes an ID for comparison.
+func plainID(id string) string {
+	return strings.ToLower(strings.ReplaceAll(util.ResolveID(id), "-", ""))
+}
+
+// writeMapTree prints the page hierarchy from the top of the workspace,
+// then the subtrees whose parent isn't shared, then relations and links.
+func writeMapTree(w io.Writer, m *workspaceMap) {
+	byID := map[string]*mapNode{}
+	for _, n := range m.Nodes {
+		byID[n.ID] = n
+	}
+	children := map[string][]*mapNode{}
+	for _, n := range m.Nodes {
+		children[n.Parent] = append(children[n.Parent], n)
+	}
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Title != list[j].Title {
+				return strings.ToLower(list[i].Title) < strings.ToLower(list[j].Title)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+
+	var walk func(parent, indent string)
+	walk = func(parent, indent string) {
+		list := children[parent]
+		for i, n := range list {
+			branch, next := "├── ", "│   "
+			if i == len(list)-1 {
+				branch, next = "└── ", "    "
+			}
+			fmt.Fprintf(w, "%s%s%s\n", indent, branch, mapNodeLabel(n))
+			walk(n.ID, indent+next)
+		}
+	}
+	fmt.Fprintln(w, "Workspace")
+	walk("workspace", "")
+
+	var orphans []string
+	for parent := range children {
+		if parent != "workspace" && byID[parent] == nil {
+			orphans = append(orphans, parent)
+		}
+	}
+	sort.Strings(orphans)
+	if len(orphans) > 0 {
+		fmt.Fprintln(w, "\nUnshared parents")
+		for i, parent := range orphans {
+			label := parent
+			if parent == "" {
+				label = "(unknown)"
+			}
+			branch, next := "├── ", "│   "
+			if i == len(orphans)-1 {
+				branch, next = "└── ", "    "
+			}
+			fmt.Fprintf(w, "%s%s\n", branch, label)
+			walk(parent, next)
+		}
+	}
+
+	var links []string
+	for _, e := range m.Edges {
+		if e.Kind == "parent" {
+			continue
+		}
+		line := fmt.Sprintf("  %s → %s", mapTitle(byID, e.From), mapTitle(byID, e.To))
+		if e.Label != "" {
+			line += fmt.Sprintf(" (%s)", e.Label)
+		} else {
+			line += " (link)"
+		}
+		links = append(links, line)
+	}
+	if len(links) > 0 {
+		fmt.Fprintln(w, "\nRelations and links")
+		for _, l := range links {
+			fmt.Fprintln(w, l)
+		}
+	}
+}
+
+func mapNodeLabel(n *mapNode) string {
+	icon := "📄"
+	if n.Object == "database" {
+		icon = "🗃️"
+	}
+	label := fmt.Sprintf("%s %s", icon, mapTitleOf(n))
+	switch {
+	case n.Rows == 1:
+		label += " (1 row)"
+	case n.Rows > 1:
+		label += fmt.Sprintf(" (%d rows)", n.Rows)
+	}
+	return label
+}
+
+func mapTitleOf(n *mapNode) string {
+	if n.Title == "" {
+		return "Untitled"
+	}
+	return n.Title
+}
+
+func mapTitle(byID map[string]*mapNode, id string) string {
+	if n := byID[id]; n != nil {
+		return mapTitleOf(n)
+	}
+	return id
+}
+
+// writeMapDOT prints the map as a Graphviz digraph.
+func writeMapDOT(w io.Writer, m *workspaceMap) {
+	fmt.Fprintln(w, "digraph notion {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, style=rounded];")
+	for _, n := range m.Nodes {
+		attrs := fmt.Sprintf("label=%s", dotQuote(mapTitleOf(n)))
+		if n.Object == "database" {
+			attrs += ", shape=cylinder"
+		}
+		if n.URL != "" {
+			attrs += ", URL=" + dotQuote(n.URL)
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", dotQuote(n.ID), attrs)
+	}
+	for _, e := range m.Edges {
+		attrs := ""
+		switch e.Kind {
+		case "relation":
+			attrs = fmt.Sprintf(" [style=dashed, label=%s]", dotQuote(e.Label))
+		case "link":
+			attrs = " [style=dotted]"
+		}
+		fmt.Fprintf(w, "  %s -> %s%s;\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func mapTestServer(t *testing.T) {
+	title := func(s string) map[string]interface{} {
+		return map[string]interface{}{"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": s}}}}
+	}
+	results := []interface{}{
+		map[string]interface{}{"object": "page", "id": "home", "parent": map[string]interface{}{"type": "workspace", "workspace": true}, "properties": title("Home")},
+		map[string]interface{}{"object": "database", "id": "tasks", "title": []interface{}{map[string]interface{}{"plain_text": "Tasks"}},
+			"parent": map[string]interface{}{"type": "page_id", "page_id": "home"},
+			"properties": map[string]interface{}{"Project": map[string]interface{}{"type": "relation", "relation": map[string]interface{}{"database_id": "projects"}}}},
+		map[string]interface{}{"object": "database", "id": "projects", "title": []interface{}{map[string]interface{}{"plain_text": "Projects"}},
+			"parent": map[string]interface{}{"type": "block_id", "block_id": "col"}},
+		map[string]interface{}{"object": "page", "id": "row1", "parent": map[string]interface{}{"type": "database_id", "database_id": "tasks"}, "properties": title("Row")},
+		map[string]interface{}{"object": "page", "id": "lost", "parent": map[string]interface{}{"type": "page_id", "page_id": "private"}, "properties": title("Lost \"page\"")},
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/search":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+		case "/v1/blocks/col":
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "block", "id": "col", "parent": map[string]interface{}{"type": "page_id", "page_id": "home"}})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(api.Close)
+	t.Setenv("NOTION_BASE_URL", api.URL)
+}
+
+func TestBuildWorkspaceMap(t *testing.T) {
+	mapTestServer(t)
+	m, err := buildWorkspaceMap(newClient("secret_test"), false, false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Nodes) != 4 {
+		t.Errorf("nodes = %d, want the row left out", len(m.Nodes))
+	}
+	var edges []string
+	for _, e := range m.Edges {
+		edges = append(edges, e.From+">"+e.To+":"+e.Kind)
+	}
+	if got := strings.Join(edges, " "); got != "tasks>projects:relation home>projects:parent home>tasks:parent" {
+		t.Errorf("edges = %s", got)
+	}
+
+	var tree bytes.Buffer
+	writeMapTree(&tree, m)
+	want := `Workspace
+└── 📄 Home
+    ├── 🗃️ Projects
+    └── 🗃️ Tasks (1 row)
+
+Unshared parents
+└── private
+    └── 📄 Lost "page"
+
+Relations and links
+  Tasks → Projects (Project)
+`
+	if tree.String() != want {
+		t.Errorf("tree:\n%s\nwant\n%s", tree.String(), want)
+	}
+
+	var dot bytes.Buffer
+	writeMapDOT(&dot, m)
+	for _, line := range []string{`"lost" [label="Lost \"page\""];`, `"tasks" -> "projects" [style=dashed, label="Project"];`, `"projects" [label="Projects", shape=cylinder];`} {
+		if !strings.Contains(dot.String(), line) {
+			t.Errorf("dot output lacks %s:\n%s", line, dot.String())
+		}
+	}
+}
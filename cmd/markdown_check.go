@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// markdownIssue is a line of a markdown file that won't convert to
+// blocks as written.
+type markdownIssue struct {
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+	// Error is true when the append would be rejected outright, false
+	// when the content is only degraded.
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+}
+
+var (
+	mdNestedListRe  = regexp.MustCompile(`^(\s{2,}|\t+)([-*+]|\d+\.)\s`)
+	mdPlusListRe    = regexp.MustCompile(`^\+\s`)
+	mdDeepHeading   = regexp.MustCompile(`^#{4,6}\s`)
+	mdImageRe       = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]+)[^)]*\)`)
+	mdImageLineRe   = regexp.MustCompile(`^!\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)$`)
+	mdLinkRe        = regexp.MustCompile(`(^|[^!])\[[^\]]+\]\(([^)\s]+)[^)]*\)`)
+	mdFootnoteRe    = regexp.MustCompile(`\[\^([^\]]+)\]`)
+	mdFootnoteDefRe = regexp.MustCompile(`^\[\^([^\]]+)\]:`)
+	mdHTMLRe        = regexp.MustCompile(`^\s*</?[a-zA-Z!][^>]*>`)
+	mdURLSchemeRe   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// checkMarkdown reports what parseMarkdownWithImages would silently drop,
+// flatten or mangle in content, and what the API would reject. Local
+// images are looked up relative to dir.
+func checkMarkdown(content, dir string) []markdownIssue {
+	var issues []markdownIssue
+	add := func(line int, kind string, isError bool, format string, args ...interface{}) {
+		issues = append(issues, markdownIssue{Line: line, Kind: kind, Error: isError, Message: fmt.Sprintf(format, args...)})
+	}
+
+	lines := strings.Split(content, "\n")
+	footnoteIDs := map[string]bool{}
+	for _, line := range lines {
+		if m := mdFootnoteDefRe.FindStringSubmatch(line); m != nil {
+			footnoteIDs[m[1]] = true
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		n := i + 1
+
+		if strings.HasPrefix(line, "```") {
+			label := strings.TrimPrefix(line, "```")
+			if _, ok := notion.CodeLanguage(label); !ok {
+				add(n, "code", false, "code language %q is not supported by Notion; the block is created as plain text", label)
+			}
+			start, size := n, 0
+			for i++; i < len(lines) && !strings.HasPrefix(lines[i], "```"); i++ {
+				size += utf8.RuneCountInString(lines[i]) + 1
+			}
+			if i == len(lines) {
+				add(start, "code", false, "code fence is never closed; the rest of the file becomes code")
+			} else if size > maxRichTextContentLen {
+				add(start, "code", false, "code block is longer than %d characters and is split into several blocks", maxRichTextContentLen)
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case mdNestedListRe.MatchString(line):
+			add(n, "list", false, "nested list item becomes a top-level paragraph; nesting is not converted")
+		case mdPlusListRe.MatchString(line):
+			add(n, "list", false, "'+' list item becomes a paragraph; use '-' or '*'")
+		case mdDeepHeading.MatchString(line):
+			add(n, "heading", false, "Notion has three heading levels; this heading becomes a paragraph")
+		case mdHTMLRe.MatchString(line):
+			add(n, "html", false, "HTML is kept as literal text")
+		case strings.HasPrefix(trimmed, "|"):
+			start := i
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "|") {
+				i++
+			}
+			issues = append(issues, checkMarkdownTable(lines[start:i+1], start+1)...)
+			continue
+		}
+
+		ownLine := mdImageLineRe.MatchString(trimmed)
+		for _, m := range mdImageRe.FindAllStringSubmatch(line, -1) {
+			switch {
+			case isAbsoluteURL(m[1]):
+				add(n, "image", false, "image %s becomes a link, not an image block", m[1])
+			case !ownLine:
+				add(n, "image", true, "local image %s is only uploaded on a line of its own; here Notion rejects the relative link", m[1])
+			default:
+				if _, err := diskImages(dir)(m[1]); err != nil {
+					add(n, "image", true, "local image %s can't be uploaded: %v", m[1], err)
+				}
+			}
+		}
+		for _, m := range mdLinkRe.FindAllStringSubmatch(line, -1) {
+			if !isAbsoluteURL(m[2]) {
+				add(n, "link", true, "link to %q is rejected by Notion, which only accepts absolute URLs", m[2])
+			}
+		}
+		if !mdFootnoteDefRe.MatchString(line) {
+			for _, m := range mdFootnoteRe.FindAllStringSubmatch(line, -1) {
+				if !footnoteIDs[m[1]] {
+					add(n, "footnote", false, "footnote [^%s] has no definition and is kept as literal text", m[1])
+				}
+			}
+		}
+		if utf8.RuneCountInString(trimmed) > maxRichTextContentLen {
+			add(n, "length", false, "line is longer than %d characters and is split into several blocks", maxRichTextContentLen)
+		}
+	}
+	return issues
+}
+
+// checkMarkdownTable reports problems with the table in lines, which
+// starts at line first of the file.
+func checkMarkdownTable(lines []string, first int) []markdownIssue {
+	blocks := notion.MarkdownToBlocks(strings.Join(lines, "\n"))
+	if len(blocks) == 0 || blocks[0]["type"] != "table" {
+		return []markdownIssue{{Line: first, Kind: "table", Message: "table has no header separator row (|---|); each line becomes a paragraph"}}
+	}
+	table, _ := blocks[0]["table"].(map[string]interface{})
+	width, _ := table["table_width"].(int)
+	var issues []markdownIssue
+	for j, row := range lines {
+		if j == 1 {
+			continue
+		}
+		if cells := len(strings.Split(strings.Trim(strings.TrimSpace(row), "|"), "|")); cells > width {
+			issues = append(issues, markdownIssue{Line: first + j, Kind: "table", Message: fmt.Sprintf("row has %d cells but the header has %d; the extra cells are dropped", cells, width)})
+		}
+		if strings.Contains(row, `\|`) {
+			issues = append(issues, markdownIssue{Line: first + j, Kind: "table", Message: `escaped pipe (\|) splits the cell`})
+		}
+	}
+	return issues
+}
+
+// isAbsoluteURL reports whether a link target has a scheme.
+func isAbsoluteURL(target string) bool {
+	return mdURLSchemeRe.MatchString(target)
+}
+
+// reportMarkdownCheck prints the result of checkMarkdown for the file at
+// name, and returns an error when there is anything to fix, so CI can gate
+// on it.
+func reportMarkdownCheck(name, content string) error {
+	issues := checkMarkdown(content, filepath.Dir(name))
+	blocks := len(notion.MarkdownToBlocks(content))
+	if outputFormat == "json" {
+		if issues == nil {
+			issues = []markdownIssue{}
+		}
+		if err := render.JSON(map[string]interface{}{"file": name, "blocks": blocks, "problems": issues}); err != nil {
+			return err
+		}
+	} else {
+		for _, is := range issues {
+			severity := "warning"
+			if is.Error {
+				severity = "error"
+			}
+			fmt.Printf("%s:%d: %s: %s\n", name, is.Line, severity, is.Message)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("✓ %s converts cleanly to %d block(s)\n", name, blocks)
+		}
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d problem(s) found in %s", len(issues), name)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckMarkdown(t *testing.T) {
+	content := strings.Join([]string{
+		"# Title",                           // 1
+		"Intro with [docs](https://x.dev).", // 2
+		"- item",                            // 3
+		"  - nested",                        // 4
+		"#### Deep",                         // 5
+		"See [setup](./setup.md)[^1].",      // 6
+		"![chart](./chart.png)",             // 7
+		"| a | b |",                         // 8
+		"|---|---|",                         // 9
+		"| 1 | 2 | 3 |",                     // 10
+		"```brainfuck",                      // 11
+		"+++",                               // 12
+		"```",                               // 13
+		"| no | separator |",                // 14
+		"<details>",                         // 15
+	}, "\n")
+
+	var got []string
+	for _, is := range checkMarkdown(content, t.TempDir()) {
+		severity := ""
+		if is.Error {
+			severity = "E"
+		}
+		got = append(got, fmt.Sprintf("%s:%s@%d", severity, is.Kind, is.Line))
+	}
+	want := []string{
+		":list@4", ":heading@5", "E:link@6", ":footnote@6", "E:image@7",
+		":table@10", ":code@11", ":table@14", ":html@15",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "chart.png"), []byte("png"), 0o644)
+	if issues := checkMarkdown("![chart](chart.png)\n", dir); len(issues) != 0 {
+		t.Errorf("existing local image reported %v", issues)
+	}
+	if issues := checkMarkdown("# Clean\n\n- a\n- b\n\n```go\nx := 1\n```\n", ""); len(issues) != 0 {
+		t.Errorf("clean file reported %v", issues)
+	}
+}
+
+func TestBlockAppendCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.md")
+	os.WriteFile(path, []byte("# Doc\n\n  - nested\n"), 0o644)
+	var err error
+	out := captureStdout(t, func() {
+		_, _, err = executeCommand("block", "append", "abc", "--file", path, "--check")
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 problem(s)") {
+		t.Errorf("err = %v", err)
+	}
+	if !strings.Contains(out, "doc.md:3: warning: nested list item") {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
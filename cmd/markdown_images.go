@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// imageLoader reads the local image a markdown file refers to as src.
+type imageLoader func(src string) (*fileSource, error)
+
+// parseMarkdownWithImages is parseMarkdownToBlocks for markdown read from
+// a file: images with a local path are uploaded and embedded instead of
+// becoming broken links. It returns the blocks and how many images were
+// uploaded, and fails on the first image that can't be read or uploaded.
+func parseMarkdownWithImages(api fileUploadAPI, content string, load imageLoader) ([]map[string]interface{}, int, error) {
+	uploaded := 0
+	var firstErr error
+	blocks := notion.MarkdownToBlocks(content,
+		notion.OnUnknownLanguage(warnUnknownCodeLanguage),
+		notion.OnLocalImage(func(src, alt string) map[string]interface{} {
+			if firstErr != nil {
+				return nil
+			}
+			file, err := load(src)
+			if err == nil {
+				var outcome *fileUploadOutcome
+				if outcome, err = uploadFromSource(api, file, ""); err == nil {
+					uploaded++
+					return buildFileUploadMediaBlock("image", outcome.UploadID, alt)
+				}
+			}
+			firstErr = fmt.Errorf("image %s: %w", src, err)
+			return nil
+		}))
+	if firstErr != nil {
+		return nil, uploaded, firstErr
+	}
+	return blocks, uploaded, nil
+}
+
+// imagePathCandidates returns the paths src may mean: as written, and
+// URL-decoded (Notion exports write "My%20Page/image.png").
+func imagePathCandidates(src string) []string {
+	candidates := []string{src}
+	if decoded, err := url.PathUnescape(src); err == nil && decoded != src {
+		candidates = append(candidates, decoded)
+	}
+	return candidates
+}
+
+// diskImages loads images relative to dir, the directory of the markdown
+// file.
+func diskImages(dir string) imageLoader {
+	return func(src string) (*fileSource, error) {
+		var err error
+		for _, p := range imagePathCandidates(src) {
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(dir, p)
+			}
+			if _, err = os.Stat(p); err == nil {
+				return loadSourceFromPath(p, "")
+			}
+		}
+		return nil, fmt.Errorf("file not found: %w", err)
+	}
+}
+
+// fsImages loads images relative to dir inside fsys, for imports from a
+// directory tree or an export zip.
+func fsImages(fsys fs.FS, dir string) imageLoader {
+	return func(src string) (*fileSource, error) {
+		var err error
+		for _, p := range imagePathCandidates(src) {
+			name := path.Join(dir, p)
+			var data []byte
+			if data, err = fs.ReadFile(fsys, name); err == nil {
+				base := path.Base(name)
+				return &fileSource{Name: base, Size: int64(len(data)), ContentType: sniffContentType(base, data), Data: data}, nil
+			}
+		}
+		return nil, fmt.Errorf("file not found: %w", err)
+	}
+}
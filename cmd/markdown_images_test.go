@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseMarkdownWithImages(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "my chart.png"), []byte("\x89PNG\r\n\x1a\n"), 0o644)
+
+	mock := &mockFileUploadClient{}
+	blocks, uploaded, err := parseMarkdownWithImages(mock, "# Report\n\n![Q3 chart](my%20chart.png)\n", diskImages(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded != 1 || len(blocks) != 2 || blocks[1]["type"] != "image" {
+		t.Fatalf("uploaded=%d blocks=%v", uploaded, blocks)
+	}
+	image := blocks[1]["image"].(map[string]interface{})
+	if id := image["file_upload"].(map[string]interface{})["id"]; id != "upload-123" {
+		t.Errorf("file_upload id = %v", id)
+	}
+	if mock.sendFileName != "my chart.png" || mock.sendContentType != "image/png" {
+		t.Errorf("sent %s as %s", mock.sendFileName, mock.sendContentType)
+	}
+
+	_, _, err = parseMarkdownWithImages(mock, "![](nope.png)\n", diskImages(dir))
+	if err == nil || !strings.Contains(err.Error(), "image nope.png") {
+		t.Errorf("missing image: err = %v", err)
+	}
+}
+
+func TestFSImages(t *testing.T) {
+	fsys := fstest.MapFS{"Notes/Page/diagram.png": {Data: []byte("GIF89a")}}
+	file, err := fsImages(fsys, "Notes")("Page/diagram.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "diagram.png" || file.ContentType != "image/png" || file.Size != 6 {
+		t.Errorf("file = %+v", file)
+	}
+}
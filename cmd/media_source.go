@@ -22,8 +22,8 @@ type mediaClient interface {
 // Exactly one source is active per CLI invocation.
 type mediaSource struct {
 	kind    string // "image" | "file" | "video" | "audio" | "pdf"
-	mode    string // "external" | "file" | "upload"
-	value   string // URL / local path / file_upload id
+	mode    string // "external" | "file" | "upload" | "clipboard"
+	value   string // URL / local path / file_upload id (unused for clipboard)
 	caption string
 }
 
@@ -47,6 +47,16 @@ func (m *mediaSource) Build(c mediaClient) (map[string]interface{}, error) {
 			return nil, fmt.Errorf("upload %s: %w", m.value, err)
 		}
 		return buildFileUploadMediaBlock(m.kind, outcome.UploadID, m.caption), nil
+	case "clipboard":
+		src, err := loadSourceFromClipboard()
+		if err != nil {
+			return nil, err
+		}
+		outcome, err := uploadFromSource(c, src, "")
+		if err != nil {
+			return nil, fmt.Errorf("upload clipboard image: %w", err)
+		}
+		return buildFileUploadMediaBlock(m.kind, outcome.UploadID, m.caption), nil
 	default:
 		return nil, fmt.Errorf("internal: unknown media mode %q", m.mode)
 	}
@@ -64,6 +74,8 @@ func (m *mediaSource) Build(c mediaClient) (map[string]interface{}, error) {
 // shipped --image-url, and the Notion API accepts external URLs for every
 // media type — we keep parity by also adding -url for the others, so
 // e.g. `--video-url https://…/clip.mp4` works).
+//
+// --image-clipboard uploads the image currently on the system clipboard.
 func registerMediaFlags(cmd *cobra.Command) {
 	cmd.Flags().String("caption", "", "Caption for the media block (applies to any of the --*-url/--*-file/--*-upload flags)")
 	for _, kind := range mediaKinds {
@@ -71,6 +83,7 @@ func registerMediaFlags(cmd *cobra.Command) {
 		cmd.Flags().String(kind+"-file", "", fmt.Sprintf("Local %s file to upload and embed", kind))
 		cmd.Flags().String(kind+"-upload", "", fmt.Sprintf("Existing file_upload ID to embed as %s", kind))
 	}
+	cmd.Flags().Bool("image-clipboard", false, "Upload and embed the image on the system clipboard")
 }
 
 // resolveMediaSource inspects every media flag on the command and returns
@@ -105,25 +118,30 @@ func resolveMediaSource(cmd *cobra.Command, filePath, text string) (*mediaSource
 		}
 	}
 
+	if fromClipboard, _ := cmd.Flags().GetBool("image-clipboard"); fromClipboard {
+		picked = append(picked, "--image-clipboard")
+		active = &mediaSource{kind: "image", mode: "clipboard", caption: caption}
+	}
+
 	if len(picked) == 0 {
 		if caption != "" && filePath == "" && text == "" {
-			return nil, fmt.Errorf("--caption requires one of --<media>-url/--<media>-file/--<media>-upload")
+			return nil, usageError(fmt.Errorf("--caption requires one of --<media>-url/--<media>-file/--<media>-upload/--image-clipboard"))
 		}
 		return nil, nil
 	}
 	if len(picked) > 1 {
-		return nil, fmt.Errorf("at most one media source may be set, got: %s", strings.Join(picked, ", "))
+		return nil, usageError(fmt.Errorf("at most one media source may be set, got: %s", strings.Join(picked, ", ")))
 	}
 	if filePath != "" {
-		return nil, fmt.Errorf("%s cannot be combined with --file", picked[0])
+		return nil, usageError(fmt.Errorf("%s cannot be combined with --file", picked[0]))
 	}
 	if text != "" {
-		return nil, fmt.Errorf("%s cannot be combined with a positional text argument", picked[0])
+		return nil, usageError(fmt.Errorf("%s cannot be combined with a positional text argument", picked[0]))
 	}
 
 	if active.mode == "external" {
 		if !strings.HasPrefix(active.value, "http://") && !strings.HasPrefix(active.value, "https://") {
-			return nil, fmt.Errorf("%s must be an http:// or https:// URL", picked[0])
+			return nil, usageError(fmt.Errorf("%s must be an http:// or https:// URL", picked[0]))
 		}
 	}
 	return active, nil
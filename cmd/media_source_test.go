@@ -216,3 +216,46 @@ func TestMediaSource_Build_File_UploadsAndReferences(t *testing.T) {
 func writeTempFile(path, contents string) error {
 	return os.WriteFile(path, []byte(contents), 0600)
 }
+
+func TestMediaSourceBuild_Clipboard(t *testing.T) {
+	png := append(append([]byte(nil), pngSignature...), "image-bytes"...)
+	old := clipboardReadImage
+	clipboardReadImage = func() ([]byte, error) { return png, nil }
+	defer func() { clipboardReadImage = old }()
+
+	cmd := newMediaCmd()
+	cmd.Flags().Set("image-clipboard", "true")
+	cmd.Flags().Set("caption", "screenshot")
+	src, err := resolveMediaSource(cmd, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock := &fakeMediaClient{}
+	block, err := src.Build(mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block["type"] != "image" {
+		t.Errorf("type = %v, want image", block["type"])
+	}
+	if mock.sendContentType != "image/png" || !strings.HasSuffix(mock.sendFileName, ".png") {
+		t.Errorf("uploaded %q as %q, want a PNG", mock.sendFileName, mock.sendContentType)
+	}
+	if string(mock.sendFileContents) != string(png) {
+		t.Error("uploaded bytes differ from the clipboard image")
+	}
+
+	clipboardReadImage = func() ([]byte, error) { return []byte("plain text"), nil }
+	if _, err := src.Build(&fakeMediaClient{}); err == nil || !strings.Contains(err.Error(), "no image") {
+		t.Errorf("non-image clipboard should fail, got: %v", err)
+	}
+}
+
+func TestResolveMediaSource_ClipboardConflicts(t *testing.T) {
+	cmd := newMediaCmd()
+	cmd.Flags().Set("image-clipboard", "true")
+	cmd.Flags().Set("image-url", "https://x/y.png")
+	if _, err := resolveMediaSource(cmd, "", ""); err == nil {
+		t.Error("--image-clipboard with --image-url should error")
+	}
+}
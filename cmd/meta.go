@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Describe the CLI itself",
+}
+
+var metaCommandsCmd = &cobra.Command{
+	Use:   "commands",
+	Short: "List every command, its arguments, flags and output",
+	Long: `List every command with its arguments, flags and, for the core
+commands, the shape of their --format json output.
+
+The JSON manifest is meant for agent frameworks: each argument and flag
+carries a JSON Schema type, so tool definitions can be generated from it
+instead of written by hand. It changes only when the CLI does.
+
+Examples:
+  notion meta commands
+  notion meta commands --json
+  notion meta commands --json | jq '.commands[] | select(.path == "db query")'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		manifest := commandManifest()
+		if asJSON || outputFormat == "json" {
+			return render.JSON(manifest)
+		}
+		var rows [][]string
+		for _, c := range manifest.Commands {
+			var params []string
+			for _, a := range c.Args {
+				params = append(params, a.Name)
+			}
+			rows = append(rows, []string{c.Path, strings.Join(params, " "), c.Short})
+		}
+		render.Table([]string{"COMMAND", "ARGS", "DESCRIPTION"}, rows)
+		return nil
+	},
+}
+
+func init() {
+	metaCommandsCmd.Flags().Bool("json", false, "Print the manifest as JSON (same as --format json)")
+	metaCmd.AddCommand(metaCommandsCmd)
+}
+
+// manifest describes the CLI for programs: 'notion meta commands --json'.
+type manifest struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	GlobalFlags []manifestFlag    `json:"global_flags"`
+	Commands    []manifestCommand `json:"commands"`
+}
+
+type manifestCommand struct {
+	Path    string                 `json:"path"`
+	Usage   string                 `json:"usage"`
+	Short   string                 `json:"short"`
+	Long    string                 `json:"long,omitempty"`
+	Aliases []string               `json:"aliases,omitempty"`
+	Args    []manifestArg          `json:"args"`
+	Flags   []manifestFlag         `json:"flags"`
+	Output  map[string]interface{} `json:"output,omitempty"`
+}
+
+type manifestArg struct {
+	Name     string                 `json:"name"`
+	Required bool                   `json:"required"`
+	Repeated bool                   `json:"repeated,omitempty"`
+	Schema   map[string]interface{} `json:"schema"`
+}
+
+type manifestFlag struct {
+	Name        string                 `json:"name"`
+	Shorthand   string                 `json:"shorthand,omitempty"`
+	Description string                 `json:"description"`
+	Default     string                 `json:"default,omitempty"`
+	Schema      map[string]interface{} `json:"schema"`
+}
+
+// commandManifest describes every runnable command, sorted by path.
+func commandManifest() manifest {
+	m := manifest{Name: rootCmd.Name(), Version: Version, GlobalFlags: manifestFlags(rootCmd.PersistentFlags())}
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, sub := range c.Commands() {
+			if sub.Hidden || sub.Name() == "help" || sub.Name() == "completion" || sub.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			if sub.Runnable() {
+				m.Commands = append(m.Commands, describeCommand(sub))
+			}
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+	sort.Slice(m.Commands, func(i, j int) bool { return m.Commands[i].Path < m.Commands[j].Path })
+	return m
+}
+
+func describeCommand(c *cobra.Command) manifestCommand {
+	path := strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+	mc := manifestCommand{
+		Path:    path,
+		Usage:   c.UseLine(),
+		Short:   c.Short,
+		Long:    c.Long,
+		Aliases: c.Aliases,
+		Args:    parseUseArgs(c.Use),
+		Flags:   manifestFlags(c.LocalNonPersistentFlags()),
+		Output:  commandOutputs[path],
+	}
+	if mc.Args == nil {
+		mc.Args = []manifestArg{}
+	}
+	return mc
+}
+
+var (
+	// useArgRe matches the <required> and [optional] arguments of a Use
+	// line.
+	useArgRe = regexp.MustCompile(`([<\[])([^>\]]+)[>\]](\.\.\.)?`)
+	// useFlagRe matches flags shown in a Use line, which are described
+	// with the other flags.
+	useFlagRe = regexp.MustCompile(`\[-[^\]]*\]`)
+)
+
+// parseUseArgs reads the arguments out of a command's Use line, e.g.
+// "set <page-id|url> <key=value ...>".
+func parseUseArgs(use string) []manifestArg {
+	var args []manifestArg
+	for _, m := range useArgRe.FindAllStringSubmatch(useFlagRe.ReplaceAllString(use, ""), -1) {
+		name := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[2]), "..."))
+		a := manifestArg{
+			Name:     name,
+			Required: m[1] == "<",
+			Repeated: m[3] != "" || strings.HasSuffix(strings.TrimSpace(m[2]), "..."),
+			Schema:   map[string]interface{}{"type": "string"},
+		}
+		switch {
+		case strings.Contains(name, "id") || strings.Contains(name, "url"):
+			a.Schema["description"] = "Notion ID or URL (also an alias or @N from 'notion recent')"
+		case strings.Contains(name, "="):
+			a.Schema["description"] = "Property assignment, e.g. Status=Done"
+			a.Schema["pattern"] = "^[^=]+=.*$"
+		}
+		if a.Repeated {
+			a.Schema = map[string]interface{}{"type": "array", "items": a.Schema}
+		}
+		args = append(args, a)
+	}
+	return args
+}
+
+// manifestFlags describes the visible flags of fs, sorted by name.
+func manifestFlags(fs *pflag.FlagSet) []manifestFlag {
+	flags := []manifestFlag{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		mf := manifestFlag{Name: f.Name, Shorthand: f.Shorthand, Description: f.Usage, Schema: flagSchema(f)}
+		if f.DefValue != "" && f.DefValue != "[]" && !(f.Value.Type() == "bool" && f.DefValue == "false") {
+			mf.Default = f.DefValue
+		}
+		flags = append(flags, mf)
+	})
+	return flags
+}
+
+// flagSchema is the JSON Schema of the values a flag takes.
+func flagSchema(f *pflag.Flag) map[string]interface{} {
+	switch t := f.Value.Type(); {
+	case t == "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case strings.HasPrefix(t, "int") || strings.HasPrefix(t, "uint"):
+		return map[string]interface{}{"type": "integer"}
+	case strings.HasPrefix(t, "float"):
+		return map[string]interface{}{"type": "number"}
+	case strings.HasSuffix(t, "Slice") || strings.HasSuffix(t, "Array"):
+		return map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// notionObjectSchema is the JSON Schema of a Notion API object.
+func notionObjectSchema(kinds ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"object", "id"},
+		"properties": map[string]interface{}{
+			"object": map[string]interface{}{"enum": kinds},
+			"id":     map[string]interface{}{"type": "string"},
+		},
+	}
+}
+
+// notionListSchema is the JSON Schema of a list of Notion objects, as
+// returned by the API or collected with --all.
+func notionListSchema(kinds ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"results"},
+		"properties": map[string]interface{}{
+			"results":     map[string]interface{}{"type": "array", "items": notionObjectSchema(kinds...)},
+			"has_more":    map[string]interface{}{"type": "boolean"},
+			"next_cursor": map[string]interface{}{"type": []string{"string", "null"}},
+		},
+	}
+}
+
+// commandOutputs is the --format json output of the core commands.
+var commandOutputs = map[string]map[string]interface{}{
+	"search":    notionListSchema("page", "database"),
+	"page list": notionListSchema("page"),
+	"page view": {
+		"type":     "object",
+		"required": []string{"page", "blocks"},
+		"properties": map[string]interface{}{
+			"page":   notionObjectSchema("page"),
+			"blocks": notionListSchema("block"),
+		},
+	},
+	"page create":  notionObjectSchema("page"),
+	"page set":     notionObjectSchema("page"),
+	"page archive": notionObjectSchema("page"),
+	"page restore": notionObjectSchema("page"),
+	"db list":      notionListSchema("database"),
+	"db view":      notionObjectSchema("database"),
+	"db create":    notionObjectSchema("database"),
+	"db update":    notionObjectSchema("database"),
+	"db add":       notionObjectSchema("page"),
+	"db query":     notionListSchema("page"),
+	"block list":   notionListSchema("block"),
+	"block get":    notionObjectSchema("block"),
+	"comment list": notionListSchema("comment"),
+	"comment add":  notionObjectSchema("comment"),
+	"user me":      notionObjectSchema("user"),
+	"user list":    notionListSchema("user"),
+	"user get":     notionObjectSchema("user"),
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import "testing"
+
+func TestCommandManifest(t *testing.T) {
+	m := commandManifest()
+	commands := map[string]manifestCommand{}
+	for _, c := range m.Commands {
+		commands[c.Path] = c
+	}
+	for path := range commandOutputs {
+		if _, ok := commands[path]; !ok {
+			t.Errorf("output schema for unknown command %q", path)
+		}
+	}
+
+	set := commands["page set"]
+	if len(set.Args) != 2 || set.Args[0].Name != "page-id|url" || !set.Args[0].Required || !set.Args[1].Repeated {
+		t.Errorf("page set args = %+v", set.Args)
+	}
+	if len(commands["api"].Args) != 2 {
+		t.Errorf("api args = %+v", commands["api"].Args)
+	}
+	var limit *manifestFlag
+	for i, f := range commands["db query"].Flags {
+		if f.Name == "limit" {
+			limit = &commands["db query"].Flags[i]
+		}
+	}
+	if limit == nil || limit.Schema["type"] != "integer" {
+		t.Errorf("db query --limit = %+v", limit)
+	}
+	if commands["search"].Output == nil {
+		t.Error("search has no output schema")
+	}
+	if _, ok := commands["page"]; ok {
+		t.Error("command groups are not commands")
+	}
+}
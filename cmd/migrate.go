@@ -0,0 +1,1056 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate <page-or-db-id|url>",
+	Short: "Copy a page tree or database to another workspace",
+	Long: `Copy a page and everything under it, or a database with its rows, from
+one saved profile's workspace to another's.
+
+Pages keep their title, icon, cover and content; sub-pages and databases
+are copied recursively, databases with their schema, rows and the rows'
+content. Files hosted by Notion are downloaded and uploaded again.
+Afterwards:
+
+  - relations between copied databases point at the copies, and
+    rollups and formulas are recreated on top of them;
+  - @-mentions, links and link-to-page blocks to copied pages point at
+    the copies; those to anything else keep pointing at the source;
+  - people and user mentions are matched by email; users with no account
+    in the destination become plain text.
+
+Every old → new ID is written to --map-file, also when the migration
+stops half-way, with warnings about what couldn't be carried over
+(status properties become selects, for one: the API can't create them).
+
+Examples:
+  notion migrate abc123 --from work --to personal --parent def456
+  notion migrate abc123 --to archive --parent workspace --map-file ids.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		parentArg, _ := cmd.Flags().GetString("parent")
+		mapFile, _ := cmd.Flags().GetString("map-file")
+		if to == "" {
+			return usageError(fmt.Errorf("--to is required: the profile of the destination workspace"))
+		}
+		if parentArg == "" {
+			return usageError(fmt.Errorf("--parent is required: a page in the destination workspace, or 'workspace'"))
+		}
+		src, err := profileClient(from)
+		if err != nil {
+			return err
+		}
+		dst, err := profileClient(to)
+		if err != nil {
+			return err
+		}
+		parent, err := resolvePageParent(dst, parentArg)
+		if err != nil {
+			return err
+		}
+		if parent.Kind == "database_id" {
+			return usageError(fmt.Errorf("--parent must be a page or 'workspace', not a database"))
+		}
+
+		rootID := util.ResolveID(args[0])
+		m := newMigrator(src, dst)
+		newRoot, runErr := m.run(rootID, parent)
+		report := m.report(from, to, rootID, newRoot)
+		if err := writeMigrationMap(mapFile, report); err != nil {
+			if runErr != nil {
+				return runErr
+			}
+			return err
+		}
+		if runErr != nil {
+			return fmt.Errorf("%w\nwhat was copied so far is listed in %s", runErr, mapFile)
+		}
+
+		for _, w := range report.Warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+		}
+		if outputFormat == "json" {
+			return render.JSON(report)
+		}
+		render.Title("✓", fmt.Sprintf("Copied %d page(s), %d database(s), %d row(s), %d block(s) and %d file(s)",
+			report.Pages, report.Databases, report.Rows, report.Blocks, report.Files))
+		render.Field("New root", newRoot)
+		if m.rootURL != "" {
+			render.Field("URL", m.rootURL)
+		}
+		render.Field("ID map", mapFile)
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().String("from", "", "Profile of the source workspace (default: the current login)")
+	migrateCmd.Flags().String("to", "", "Profile of the destination workspace (required)")
+	migrateCmd.Flags().String("parent", "", "Page in the destination to copy under, or 'workspace' (required)")
+	migrateCmd.Flags().String("map-file", "migrate-map.json", "Where to write the old → new ID map")
+	markIDFlags(migrateCmd, "parent")
+}
+
+// profileClient returns a client for the saved profile name, or for the
+// current login when name is empty.
+func profileClient(name string) (*notion.Client, error) {
+	if name == "" {
+		token, err := getToken()
+		if err != nil {
+			return nil, err
+		}
+		return newClient(token), nil
+	}
+	targets, err := allProfileTargets()
+	if err != nil {
+		return nil, authError(err)
+	}
+	for _, t := range targets {
+		if t.Profile == name {
+			return newProfileClient(t), nil
+		}
+	}
+	return nil, usageError(fmt.Errorf("no profile %q with a token (see 'notion auth switch')", name))
+}
+
+// migrationReport is the ID map 'notion migrate' writes to --map-file.
+type migrationReport struct {
+	From      string            `json:"from,omitempty"` // profile; empty for the current login
+	To        string            `json:"to"`
+	Root      string            `json:"root"`
+	NewRoot   string            `json:"new_root,omitempty"`
+	Pages     int               `json:"pages"`
+	Databases int               `json:"databases"`
+	Rows      int               `json:"rows"`
+	Blocks    int               `json:"blocks"`
+	Files     int               `json:"files"`
+	IDs       map[string]string `json:"ids"`   // source ID → destination ID
+	Users     map[string]string `json:"users"` // source user ID → destination user ID
+	Warnings  []string          `json:"warnings"`
+}
+
+func writeMigrationMap(path string, report migrationReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write ID map: %w", err)
+	}
+	return nil
+}
+
+// migrator copies objects from the source workspace to the destination,
+// remembering the copy of each.
+type migrator struct {
+	src, dst *notion.Client
+	ids      map[string]string // plain source ID → destination ID
+	users    map[string]string // source user ID → destination user ID
+	dbs      []*migratedDB
+	fixups   []migrationFixup
+	progress *progress
+	rootURL  string
+
+	pages, databases, rows, blocks, files int
+	missingUsers                          map[string]bool
+	warnings                              []string
+}
+
+// migratedDB is a copied database, kept for linking relations once every
+// database exists.
+type migratedDB struct {
+	src, dst string
+	schema   map[string]interface{} // source properties
+	rows     []migratedRow
+}
+
+type migratedRow struct {
+	src, dst string
+	props    map[string]interface{} // source property values
+}
+
+// migrationFixup is a copied block whose rich text links to pages that
+// hadn't been copied yet; it is rewritten once everything has been.
+type migrationFixup struct {
+	dst   string
+	block map[string]interface{} // the source block
+}
+
+// migratedBlock is a source block converted for the destination.
+type migratedBlock struct {
+	src     map[string]interface{}
+	body    map[string]interface{}
+	fixup   bool
+	columns [][]migratedBlock // of a column_list: each column's blocks
+}
+
+func newMigrator(src, dst *notion.Client) *migrator {
+	return &migrator{
+		src:          src,
+		dst:          dst,
+		ids:          map[string]string{},
+		users:        map[string]string{},
+		missingUsers: map[string]bool{},
+	}
+}
+
+func (m *migrator) warn(format string, a ...interface{}) {
+	m.warnings = append(m.warnings, fmt.Sprintf(format, a...))
+}
+
+// run copies the page or database rootID under parent, then links what
+// could only be linked once everything was there. It returns the ID of
+// the copy.
+func (m *migrator) run(rootID string, parent pageParent) (string, error) {
+	m.progress = newProgress("migrate", "", 0, os.Stderr)
+	defer m.progress.stop()
+	m.mapUsers()
+
+	var newID string
+	if db, err := m.src.GetDatabase(rootID); err == nil {
+		if parent.Kind != "page_id" {
+			return "", usageError(fmt.Errorf("a database can only be copied under a page: pass --parent <page>"))
+		}
+		if newID, err = m.copyDatabase(db, parent.ID); err != nil {
+			return newID, err
+		}
+	} else {
+		page, err := m.src.GetPage(rootID)
+		if err != nil {
+			return "", fmt.Errorf("%s is neither a page nor a database the source profile can see: %w", rootID, err)
+		}
+		if newID, err = m.copyPage(page, parent); err != nil {
+			return newID, err
+		}
+	}
+	m.linkDatabases()
+	m.applyFixups()
+	if n := len(m.missingUsers); n > 0 {
+		m.warn("%d user(s) have no account with the same email in the destination: their mentions became plain text and they were left out of people properties", n)
+	}
+	m.progress.finish()
+	return newID, nil
+}
+
+// mapUsers pairs the users of both workspaces by email.
+func (m *migrator) mapUsers() {
+	srcUsers, err := m.src.ListUsersAll()
+	if err == nil {
+		var dstUsers []interface{}
+		if dstUsers, err = m.dst.ListUsersAll(); err == nil {
+			byEmail := map[string]string{}
+			for _, u := range dstUsers {
+				if email, id := userEmail(u); email != "" {
+					byEmail[email] = id
+				}
+			}
+			for _, u := range srcUsers {
+				if email, id := userEmail(u); email != "" && byEmail[email] != "" {
+					m.users[id] = byEmail[email]
+				}
+			}
+			return
+		}
+	}
+	m.warn("can't list users (%s): people and @-mentions of users become plain text", firstLine(err))
+}
+
+// userEmail returns the lower-cased email and the ID of a person user.
+func userEmail(u interface{}) (string, string) {
+	user, _ := u.(map[string]interface{})
+	id, _ := user["id"].(string)
+	person, _ := user["person"].(map[string]interface{})
+	email, _ := person["email"].(string)
+	return strings.ToLower(email), id
+}
+
+// create POSTs body to path in the destination and returns the new object.
+func (m *migrator) create(path string, body map[string]interface{}) (map[string]interface{}, error) {
+	data, err := m.dst.Post(path, body)
+	if err != nil {
+		return nil, err
+	}
+	var created map[string]interface{}
+	if err := json.Unmarshal(data, &created); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if m.rootURL == "" {
+		m.rootURL, _ = created["url"].(string)
+	}
+	return created, nil
+}
+
+// copyPage copies page and its content under parent.
+func (m *migrator) copyPage(page map[string]interface{}, parent pageParent) (string, error) {
+	srcID, _ := page["id"].(string)
+	var title []interface{}
+	props, _ := page["properties"].(map[string]interface{})
+	for _, p := range props {
+		if prop, _ := p.(map[string]interface{}); prop["type"] == "title" {
+			title, _ = prop["title"].([]interface{})
+		}
+	}
+	rt, _ := m.richText(title)
+	body := map[string]interface{}{
+		"parent":     parent.object(),
+		"properties": map[string]interface{}{"title": map[string]interface{}{"title": rt}},
+	}
+	m.copyIconCover(page, body)
+	created, err := m.create("/v1/pages", body)
+	if err != nil {
+		return "", fmt.Errorf("copy page %s: %w", srcID, err)
+	}
+	newID, _ := created["id"].(string)
+	m.ids[plainID(srcID)] = newID
+	m.pages++
+	m.progress.add(1)
+	return newID, m.copyChildren(srcID, newID, newID)
+}
+
+// copyIconCover sets the icon and cover of a page or database copy.
+func (m *migrator) copyIconCover(obj, body map[string]interface{}) {
+	for _, key := range []string{"icon", "cover"} {
+		v, ok := obj[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		file, err := m.fileObject(v)
+		if err != nil {
+			m.warn("%s of %v: %s", key, obj["id"], firstLine(err))
+			continue
+		}
+		if file != nil {
+			body[key] = file
+		}
+	}
+}
+
+// fileObject returns a file object (an icon, a cover, a file block or a
+// files property item) the destination can use: files hosted by Notion
+// are downloaded and uploaded again, as their URLs expire and belong to
+// the source workspace. nil means there is nothing to carry over.
+func (m *migrator) fileObject(obj map[string]interface{}) (map[string]interface{}, error) {
+	switch kind, _ := obj["type"].(string); kind {
+	case "external", "emoji":
+		return map[string]interface{}{"type": kind, kind: obj[kind]}, nil
+	case "file":
+		f, _ := obj["file"].(map[string]interface{})
+		url, _ := f["url"].(string)
+		name, _ := obj["name"].(string)
+		source, err := loadSourceFromURL(url, name)
+		if err != nil {
+			return nil, err
+		}
+		outcome, err := uploadFromSource(m.dst, source, "")
+		if err != nil {
+			return nil, err
+		}
+		m.files++
+		return map[string]interface{}{"type": "file_upload", "file_upload": map[string]interface{}{"id": outcome.UploadID}}, nil
+	}
+	return nil, nil
+}
+
+// copyChildren copies the blocks under srcID to dstID, in order. page is
+// the destination page they end up in, where sub-pages nested in other
+// blocks are created.
+func (m *migrator) copyChildren(srcID, dstID, page string) error {
+	children, err := fetchBlockChildren(m.src, srcID, "", true)
+	if err != nil {
+		return fmt.Errorf("get content of %s: %w", srcID, err)
+	}
+	var batch []migratedBlock
+	flush := func() error {
+		pending := batch
+		batch = nil
+		return m.appendBlocks(dstID, page, pending)
+	}
+	for _, ch := range children {
+		block, _ := ch.(map[string]interface{})
+		id, _ := block["id"].(string)
+		switch block["type"] {
+		case "child_page", "child_database":
+			if err := flush(); err != nil {
+				return err
+			}
+			if dstID != page {
+				m.warn("sub-page %s is nested in a block; it was copied to the end of its page", id)
+			}
+			if err := m.copyChild(block, page); err != nil {
+				return err
+			}
+		case "synced_block":
+			if err := flush(); err != nil {
+				return err
+			}
+			m.warn("synced block %s was copied as plain content", id)
+			if hasChildren, _ := block["has_children"].(bool); hasChildren {
+				if err := m.copyChildren(id, dstID, page); err != nil {
+					return err
+				}
+			}
+		default:
+			b, err := m.convertBlock(block)
+			if err != nil {
+				return err
+			}
+			if b.body != nil {
+				batch = append(batch, b)
+			}
+		}
+	}
+	return flush()
+}
+
+// copyChild copies a child_page or child_database block's page or
+// database under the destination page.
+func (m *migrator) copyChild(block map[string]interface{}, page string) error {
+	id, _ := block["id"].(string)
+	if block["type"] == "child_database" {
+		db, err := m.src.GetDatabase(id)
+		if err != nil {
+			m.warn("database %s can't be read (a linked database?): %s; left out", id, firstLine(err))
+			return nil
+		}
+		_, err = m.copyDatabase(db, page)
+		return err
+	}
+	sub, err := m.src.GetPage(id)
+	if err != nil {
+		return fmt.Errorf("get page %s: %w", id, err)
+	}
+	_, err = m.copyPage(sub, pageParent{Kind: "page_id", ID: page})
+	return err
+}
+
+// appendBlocks appends converted blocks to dstID, then copies each one's
+// children into its copy.
+func (m *migrator) appendBlocks(dstID, page string, blocks []migratedBlock) error {
+	for start := 0; start < len(blocks); start += maxChildrenPerRequest {
+		chunk := blocks[start:min(start+maxChildrenPerRequest, len(blocks))]
+		bodies := make([]map[string]interface{}, len(chunk))
+		for i, b := range chunk {
+			bodies[i] = b.body
+		}
+		data, err := m.dst.Patch("/v1/blocks/"+dstID+"/children", map[string]interface{}{"children": bodies})
+		if err != nil {
+			return fmt.Errorf("copy content into %s: %w", dstID, err)
+		}
+		var resp struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		for i, created := range resp.Results {
+			if i >= len(chunk) {
+				break
+			}
+			newID, _ := created["id"].(string)
+			if err := m.copied(chunk[i], newID, page); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copied records that block b was created as newID and copies what goes
+// under it.
+func (m *migrator) copied(b migratedBlock, newID, page string) error {
+	srcID, _ := b.src["id"].(string)
+	m.ids[plainID(srcID)] = newID
+	m.blocks++
+	if b.fixup {
+		m.fixups = append(m.fixups, migrationFixup{dst: newID, block: b.src})
+	}
+	if hasChildren, _ := b.src["has_children"].(bool); !hasChildren {
+		return nil
+	}
+	switch b.src["type"] {
+	case "table":
+		// The rows were created with the table.
+		return nil
+	case "column_list":
+		return m.copyColumns(b, newID, page)
+	}
+	return m.copyChildren(srcID, newID, page)
+}
+
+// copyColumns matches the columns of a copied column list, created with
+// their blocks, to the source, and copies what goes under those blocks.
+func (m *migrator) copyColumns(b migratedBlock, newID, page string) error {
+	columns, err := fetchBlockChildren(m.dst, newID, "", true)
+	if err != nil {
+		return fmt.Errorf("get columns of %s: %w", newID, err)
+	}
+	for i, col := range columns {
+		if i >= len(b.columns) {
+			break
+		}
+		colID, _ := col.(map[string]interface{})["id"].(string)
+		created, err := fetchBlockChildren(m.dst, colID, "", true)
+		if err != nil {
+			return fmt.Errorf("get column %s: %w", colID, err)
+		}
+		for j, c := range created {
+			if j >= len(b.columns[i]) {
+				break
+			}
+			id, _ := c.(map[string]interface{})["id"].(string)
+			if err := m.copied(b.columns[i][j], id, page); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migratableBlocks are the block types the API can create.
+var migratableBlocks = map[string]bool{
+	"paragraph": true, "heading_1": true, "heading_2": true, "heading_3": true,
+	"bulleted_list_item": true, "numbered_list_item": true, "to_do": true, "toggle": true,
+	"quote": true, "callout": true, "code": true, "divider": true, "equation": true,
+	"image": true, "video": true, "audio": true, "file": true, "pdf": true,
+	"bookmark": true, "embed": true, "table": true, "column_list": true,
+	"link_to_page": true, "table_of_contents": true, "breadcrumb": true, "link_preview": true,
+}
+
+// convertBlock turns a source block into the body that creates its copy,
+// without its children (except a table's rows and a column list's
+// columns, which must be created with it). A nil body skips the block.
+func (m *migrator) convertBlock(block map[string]interface{}) (migratedBlock, error) {
+	b := migratedBlock{src: block}
+	kind, _ := block["type"].(string)
+	id, _ := block["id"].(string)
+	data, _ := block[kind].(map[string]interface{})
+	if !migratableBlocks[kind] {
+		m.warn("%s block %s can't be created through the API; left out", kind, id)
+		return b, nil
+	}
+
+	switch kind {
+	case "link_preview":
+		b.body = map[string]interface{}{"type": "bookmark", "bookmark": map[string]interface{}{"url": data["url"]}}
+		return b, nil
+	case "link_to_page":
+		target, _ := data["page_id"].(string)
+		targetKind := "page_id"
+		if target == "" {
+			target, _ = data["database_id"].(string)
+			targetKind = "database_id"
+		}
+		if newID, ok := m.ids[plainID(target)]; ok {
+			b.body = map[string]interface{}{"type": "link_to_page", "link_to_page": map[string]interface{}{"type": targetKind, targetKind: newID}}
+			return b, nil
+		}
+		// Not copied yet: a link to the source for now, made a mention of
+		// the copy by applyFixups if there is one by then.
+		link := "https://www.notion.so/" + plainID(target)
+		b.body = map[string]interface{}{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{
+			map[string]interface{}{"type": "text", "text": map[string]interface{}{"content": link, "link": map[string]interface{}{"url": link}}},
+		}}}
+		b.fixup = true
+		return b, nil
+	}
+
+	out := map[string]interface{}{}
+	for k, v := range data {
+		switch k {
+		case "rich_text", "caption":
+			items, _ := v.([]interface{})
+			rt, unresolved := m.richText(items)
+			out[k] = rt
+			b.fixup = b.fixup || unresolved
+		case "icon":
+			icon, _ := v.(map[string]interface{})
+			if file, err := m.fileObject(icon); err == nil && file != nil {
+				out[k] = file
+			}
+		case "children", "file", "file_upload", "external", "type":
+			// Set below.
+		default:
+			out[k] = v
+		}
+	}
+	if fileType, _ := data["type"].(string); fileType != "" {
+		file, err := m.fileObject(data)
+		if err != nil {
+			m.warn("%s block %s: %s; left out", kind, id, firstLine(err))
+			return b, nil
+		}
+		for k, v := range file {
+			out[k] = v
+		}
+	}
+
+	switch kind {
+	case "table":
+		rows, err := fetchBlockChildren(m.src, id, "", true)
+		if err != nil {
+			return b, fmt.Errorf("get rows of table %s: %w", id, err)
+		}
+		var children []interface{}
+		for _, r := range rows {
+			row, _ := r.(map[string]interface{})
+			rowData, _ := row["table_row"].(map[string]interface{})
+			cells, _ := rowData["cells"].([]interface{})
+			var newCells []interface{}
+			for _, cell := range cells {
+				items, _ := cell.([]interface{})
+				rt, _ := m.richText(items)
+				newCells = append(newCells, rt)
+			}
+			children = append(children, map[string]interface{}{"type": "table_row", "table_row": map[string]interface{}{"cells": newCells}})
+		}
+		out["children"] = children
+	case "column_list":
+		columns, err := fetchBlockChildren(m.src, id, "", true)
+		if err != nil {
+			return b, fmt.Errorf("get columns of %s: %w", id, err)
+		}
+		var children []interface{}
+		for _, col := range columns {
+			colID, _ := col.(map[string]interface{})["id"].(string)
+			content, err := fetchBlockChildren(m.src, colID, "", true)
+			if err != nil {
+				return b, fmt.Errorf("get column %s: %w", colID, err)
+			}
+			var blocks []migratedBlock
+			var bodies []interface{}
+			for _, c := range content {
+				child, _ := c.(map[string]interface{})
+				if t := child["type"]; t == "child_page" || t == "child_database" || t == "synced_block" || t == "column_list" {
+					m.warn("%s %v in a column can't be copied there; left out", t, child["id"])
+					continue
+				}
+				cb, err := m.convertBlock(child)
+				if err != nil {
+					return b, err
+				}
+				if cb.body != nil {
+					blocks = append(blocks, cb)
+					bodies = append(bodies, cb.body)
+				}
+			}
+			if len(bodies) == 0 {
+				bodies = append(bodies, map[string]interface{}{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{}}})
+			}
+			b.columns = append(b.columns, blocks)
+			children = append(children, map[string]interface{}{"type": "column", "column": map[string]interface{}{"children": bodies}})
+		}
+		out["children"] = children
+	}
+	b.body = map[string]interface{}{"type": kind, kind: out}
+	return b, nil
+}
+
+// richText converts rich text for the destination: mentions and links of
+// copied pages point at the copies, users are matched, and anything else
+// becomes text. unresolved reports links to pages not copied (yet).
+func (m *migrator) richText(items []interface{}) ([]interface{}, bool) {
+	out := []interface{}{}
+	unresolved := false
+	for _, it := range items {
+		item, _ := it.(map[string]interface{})
+		plain, _ := item["plain_text"].(string)
+		href, _ := item["href"].(string)
+		var converted map[string]interface{}
+		switch item["type"] {
+		case "mention":
+			mention, _ := item["mention"].(map[string]interface{})
+			kind, _ := mention["type"].(string)
+			ref, _ := mention[kind].(map[string]interface{})
+			refID, _ := ref["id"].(string)
+			switch kind {
+			case "user":
+				if newID, ok := m.users[refID]; ok {
+					converted = map[string]interface{}{"type": "mention", "mention": map[string]interface{}{"type": "user", "user": map[string]interface{}{"id": newID}}}
+				} else {
+					m.missingUsers[refID] = true
+				}
+			case "page", "database":
+				if newID, ok := m.ids[plainID(refID)]; ok {
+					converted = map[string]interface{}{"type": "mention", "mention": map[string]interface{}{"type": kind, kind: map[string]interface{}{"id": newID}}}
+				} else {
+					unresolved = true
+					if href == "" {
+						href = "https://www.notion.so/" + plainID(refID)
+					}
+				}
+			case "date":
+				converted = map[string]interface{}{"type": "mention", "mention": map[string]interface{}{"type": "date", "date": mention["date"]}}
+			}
+		case "equation":
+			converted = map[string]interface{}{"type": "equation", "equation": item["equation"]}
+		case "text":
+			text, _ := item["text"].(map[string]interface{})
+			plain, _ = text["content"].(string)
+			link, _ := text["link"].(map[string]interface{})
+			href, _ = link["url"].(string)
+		}
+		if converted == nil {
+			content := map[string]interface{}{"content": plain}
+			if href != "" {
+				newHref, pending := m.remapHref(href)
+				unresolved = unresolved || pending
+				content["link"] = map[string]interface{}{"url": newHref}
+			}
+			converted = map[string]interface{}{"type": "text", "text": content}
+		}
+		if ann, ok := item["annotations"]; ok {
+			converted["annotations"] = ann
+		}
+		out = append(out, converted)
+	}
+	return out, unresolved
+}
+
+// remapHref points a link to a copied page or block at the copy. pending
+// reports a Notion link to something not copied (yet).
+func (m *migrator) remapHref(href string) (string, bool) {
+	id, fragment, ok := notionHrefTarget(href)
+	if !ok {
+		return href, false
+	}
+	newID, copied := m.ids[plainID(id)]
+	if !copied {
+		if strings.HasPrefix(href, "/") {
+			href = "https://www.notion.so" + href
+		}
+		return href, true
+	}
+	link := "https://www.notion.so/" + plainID(newID)
+	if fragment != "" {
+		if block, ok := m.ids[plainID(fragment)]; ok {
+			link += "#" + plainID(block)
+		}
+	}
+	return link, false
+}
+
+// copyDatabase copies a database, its rows and their content under the
+// destination page parentID. Relations, rollups and formulas are added by
+// linkDatabases.
+func (m *migrator) copyDatabase(db map[string]interface{}, parentID string) (string, error) {
+	srcID, _ := db["id"].(string)
+	schema, _ := db["properties"].(map[string]interface{})
+	props := map[string]interface{}{}
+	for name, p := range schema {
+		prop, _ := p.(map[string]interface{})
+		kind, _ := prop["type"].(string)
+		config, _ := prop[kind].(map[string]interface{})
+		switch kind {
+		case "relation", "rollup", "formula":
+			continue
+		case "select", "multi_select", "status":
+			options, _ := config["options"].([]interface{})
+			var copied []interface{}
+			for _, o := range options {
+				opt, _ := o.(map[string]interface{})
+				copied = append(copied, map[string]interface{}{"name": opt["name"], "color": opt["color"]})
+			}
+			if kind == "status" {
+				m.warn("status property %q became a select: the API can't create status properties", name)
+				kind = "select"
+			}
+			props[name] = map[string]interface{}{kind: map[string]interface{}{"options": copied}}
+		case "number":
+			props[name] = map[string]interface{}{"number": map[string]interface{}{"format": config["format"]}}
+		case "unique_id":
+			props[name] = map[string]interface{}{"unique_id": map[string]interface{}{"prefix": config["prefix"]}}
+		case "button", "verification":
+			m.warn("%s property %q can't be created through the API; left out", kind, name)
+		default:
+			props[name] = map[string]interface{}{kind: map[string]interface{}{}}
+		}
+	}
+	title, _ := db["title"].([]interface{})
+	rt, _ := m.richText(title)
+	body := map[string]interface{}{
+		"parent":     map[string]interface{}{"type": "page_id", "page_id": parentID},
+		"title":      rt,
+		"properties": props,
+	}
+	if inline, ok := db["is_inline"].(bool); ok {
+		body["is_inline"] = inline
+	}
+	m.copyIconCover(db, body)
+	created, err := m.create("/v1/databases", body)
+	if err != nil {
+		return "", fmt.Errorf("copy database %s: %w", srcID, err)
+	}
+	newID, _ := created["id"].(string)
+	m.ids[plainID(srcID)] = newID
+	m.databases++
+	md := &migratedDB{src: srcID, dst: newID, schema: schema}
+	m.dbs = append(m.dbs, md)
+
+	rows, err := m.src.QueryDatabaseAll(srcID, nil)
+	if err != nil {
+		return newID, fmt.Errorf("query database %s: %w", srcID, err)
+	}
+	for _, r := range rows {
+		row, _ := r.(map[string]interface{})
+		rowID, _ := row["id"].(string)
+		values, _ := row["properties"].(map[string]interface{})
+		rowBody := map[string]interface{}{
+			"parent":     map[string]interface{}{"database_id": newID},
+			"properties": m.rowProperties(values),
+		}
+		m.copyIconCover(row, rowBody)
+		created, err := m.create("/v1/pages", rowBody)
+		if err != nil {
+			return newID, fmt.Errorf("copy row %s: %w", rowID, err)
+		}
+		newRow, _ := created["id"].(string)
+		m.ids[plainID(rowID)] = newRow
+		m.rows++
+		m.progress.add(1)
+		md.rows = append(md.rows, migratedRow{src: rowID, dst: newRow, props: values})
+		if err := m.copyChildren(rowID, newRow, newRow); err != nil {
+			return newID, err
+		}
+	}
+	return newID, nil
+}
+
+// rowProperties converts a row's property values for the copy. Computed
+// properties are left to the destination, relations to linkDatabases.
+func (m *migrator) rowProperties(values map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for name, v := range values {
+		prop, _ := v.(map[string]interface{})
+		kind, _ := prop["type"].(string)
+		value := prop[kind]
+		switch kind {
+		case "title", "rich_text":
+			items, _ := value.([]interface{})
+			rt, _ := m.richText(items)
+			out[name] = map[string]interface{}{kind: rt}
+		case "number", "checkbox", "url", "email", "phone_number", "date":
+			if value != nil {
+				out[name] = map[string]interface{}{kind: value}
+			}
+		case "select", "status":
+			if opt, _ := value.(map[string]interface{}); opt != nil {
+				out[name] = map[string]interface{}{"select": map[string]interface{}{"name": opt["name"]}}
+			}
+		case "multi_select":
+			items, _ := value.([]interface{})
+			names := []interface{}{}
+			for _, it := range items {
+				opt, _ := it.(map[string]interface{})
+				names = append(names, map[string]interface{}{"name": opt["name"]})
+			}
+			out[name] = map[string]interface{}{"multi_select": names}
+		case "people":
+			items, _ := value.([]interface{})
+			people := []interface{}{}
+			for _, it := range items {
+				user, _ := it.(map[string]interface{})
+				id, _ := user["id"].(string)
+				if newID, ok := m.users[id]; ok {
+					people = append(people, map[string]interface{}{"id": newID})
+				} else {
+					m.missingUsers[id] = true
+				}
+			}
+			out[name] = map[string]interface{}{"people": people}
+		case "files":
+			items, _ := value.([]interface{})
+			files := []interface{}{}
+			for _, it := range items {
+				item, _ := it.(map[string]interface{})
+				file, err := m.fileObject(item)
+				if err != nil {
+					m.warn("file %v of property %q: %s; left out", item["name"], name, firstLine(err))
+					continue
+				}
+				if file != nil {
+					file["name"] = item["name"]
+					files = append(files, file)
+				}
+			}
+			out[name] = map[string]interface{}{"files": files}
+		}
+	}
+	return out
+}
+
+// linkDatabases adds the relation, rollup and formula properties of the
+// copied databases, now that every database they may point to exists, and
+// sets the rows' relations.
+func (m *migrator) linkDatabases() {
+	for _, md := range m.dbs {
+		names := make([]string, 0, len(md.schema))
+		for name := range md.schema {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		linked := map[string]bool{}
+		// Relations first: rollups read them, and formulas may read both.
+		for _, kind := range []string{"relation", "rollup", "formula"} {
+			for _, name := range names {
+				prop, _ := md.schema[name].(map[string]interface{})
+				if prop["type"] != kind {
+					continue
+				}
+				config, _ := prop[kind].(map[string]interface{})
+				var def map[string]interface{}
+				switch kind {
+				case "relation":
+					target, _ := config["database_id"].(string)
+					newTarget, ok := m.ids[plainID(target)]
+					if !ok {
+						m.warn("relation %q points to a database that wasn't copied; left out", name)
+						continue
+					}
+					def = map[string]interface{}{"database_id": newTarget, "type": "single_property", "single_property": map[string]interface{}{}}
+				case "rollup":
+					def = map[string]interface{}{
+						"relation_property_name": config["relation_property_name"],
+						"rollup_property_name":   config["rollup_property_name"],
+						"function":               config["function"],
+					}
+				case "formula":
+					def = map[string]interface{}{"expression": config["expression"]}
+				}
+				if _, err := m.dst.Patch("/v1/databases/"+md.dst, map[string]interface{}{
+					"properties": map[string]interface{}{name: map[string]interface{}{kind: def}},
+				}); err != nil {
+					m.warn("%s property %q couldn't be recreated: %s", kind, name, firstLine(err))
+					continue
+				}
+				linked[name] = true
+			}
+		}
+
+		for _, row := range md.rows {
+			updates := map[string]interface{}{}
+			for name, v := range row.props {
+				prop, _ := v.(map[string]interface{})
+				if prop["type"] != "relation" || !linked[name] {
+					continue
+				}
+				related := []interface{}{}
+				for _, id := range m.relationIDs(row.src, prop) {
+					if newID, ok := m.ids[plainID(id)]; ok {
+						related = append(related, map[string]interface{}{"id": newID})
+					}
+				}
+				if len(related) > 0 {
+					updates[name] = map[string]interface{}{"relation": related}
+				}
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			if _, err := m.dst.Patch("/v1/pages/"+row.dst, map[string]interface{}{"properties": updates}); err != nil {
+				m.warn("relations of row %s couldn't be set: %s", row.dst, firstLine(err))
+			}
+		}
+	}
+}
+
+// relationIDs lists the pages a row's relation property points to,
+// fetching the rest when the row carries only the first 25.
+func (m *migrator) relationIDs(rowID string, prop map[string]interface{}) []string {
+	items, _ := prop["relation"].([]interface{})
+	if more, _ := prop["has_more"].(bool); more {
+		propID, _ := prop["id"].(string)
+		if results, err := m.src.GetPagePropertyAll(rowID, propID); err == nil {
+			items = nil
+			for _, r := range results {
+				item, _ := r.(map[string]interface{})
+				items = append(items, item["relation"])
+			}
+		}
+	}
+	var ids []string
+	for _, it := range items {
+		rel, _ := it.(map[string]interface{})
+		if id, _ := rel["id"].(string); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// applyFixups rewrites the blocks copied before pages they link to were.
+func (m *migrator) applyFixups() {
+	external := 0
+	for _, f := range m.fixups {
+		kind, _ := f.block["type"].(string)
+		data, _ := f.block[kind].(map[string]interface{})
+		var body map[string]interface{}
+		if kind == "link_to_page" {
+			target, _ := data["page_id"].(string)
+			mentionKind := "page"
+			if target == "" {
+				target, _ = data["database_id"].(string)
+				mentionKind = "database"
+			}
+			newID, ok := m.ids[plainID(target)]
+			if !ok {
+				external++
+				continue
+			}
+			body = map[string]interface{}{"paragraph": map[string]interface{}{"rich_text": []interface{}{
+				map[string]interface{}{"type": "mention", "mention": map[string]interface{}{"type": mentionKind, mentionKind: map[string]interface{}{"id": newID}}},
+			}}}
+		} else {
+			update := map[string]interface{}{}
+			pending := false
+			for _, key := range []string{"rich_text", "caption"} {
+				if items, ok := data[key].([]interface{}); ok {
+					rt, unresolved := m.richText(items)
+					update[key] = rt
+					pending = pending || unresolved
+				}
+			}
+			if pending {
+				external++
+			}
+			body = map[string]interface{}{kind: update}
+		}
+		if _, err := m.dst.Patch("/v1/blocks/"+f.dst, body); err != nil {
+			m.warn("links in block %s couldn't be updated: %s", f.dst, firstLine(err))
+		}
+	}
+	if external > 0 {
+		m.warn("%d block(s) link to pages that weren't copied; those links still go to the source workspace", external)
+	}
+}
+
+// report summarizes the migration for --map-file.
+func (m *migrator) report(from, to, root, newRoot string) migrationReport {
+	r := migrationReport{
+		From: from, To: to, Root: root, NewRoot: newRoot,
+		Pages: m.pages, Databases: m.databases, Rows: m.rows, Blocks: m.blocks, Files: m.files,
+		IDs:      map[string]string{},
+		Users:    m.users,
+		Warnings: m.warnings,
+	}
+	for old, copy := range m.ids {
+		r.IDs[util.ResolveID(old)] = copy
+	}
+	if r.Warnings == nil {
+		r.Warnings = []string{}
+	}
+	return r
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func TestMigrateCopiesTreeAcrossWorkspaces(t *testing.T) {
+	const (
+		rootID  = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+		dbID    = "33333333-3333-3333-3333-333333333333"
+		rowID   = "44444444-4444-4444-4444-444444444444"
+		destID  = "99999999-9999-9999-9999-999999999999"
+	)
+	text := func(s string) []interface{} {
+		return []interface{}{map[string]interface{}{"type": "text", "plain_text": s, "text": map[string]interface{}{"content": s}}}
+	}
+	block := func(id, kind string, data map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": id, "type": kind, kind: data}
+	}
+	children := map[string][]interface{}{
+		rootID: {
+			block("b1", "paragraph", map[string]interface{}{"rich_text": []interface{}{
+				map[string]interface{}{"type": "mention", "plain_text": "Child", "mention": map[string]interface{}{"type": "page", "page": map[string]interface{}{"id": childID}}},
+				map[string]interface{}{"type": "mention", "plain_text": "@Ann", "mention": map[string]interface{}{"type": "user", "user": map[string]interface{}{"id": "ann-src"}}},
+			}}),
+			block(childID, "child_page", map[string]interface{}{"title": "Child"}),
+			block(dbID, "child_database", map[string]interface{}{"title": "Tasks"}),
+		},
+		childID: {block("b2", "heading_1", map[string]interface{}{"rich_text": text("Hello")})},
+		rowID:   {},
+	}
+
+	var mu sync.Mutex
+	var created []map[string]interface{} // destination POST/PATCH bodies, with "_path"
+	n := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		reply := func(v interface{}) { json.NewEncoder(w).Encode(v) }
+		notFound := func() {
+			w.WriteHeader(404)
+			reply(map[string]string{"code": "object_not_found", "message": "not found"})
+		}
+
+		if r.Header.Get("Authorization") == "Bearer secret_dst" {
+			switch {
+			case r.URL.Path == "/v1/users":
+				reply(map[string]interface{}{"results": []interface{}{
+					map[string]interface{}{"id": "ann-dst", "type": "person", "person": map[string]interface{}{"email": "ANN@example.com"}},
+				}})
+			case r.Method == "GET" && r.URL.Path == "/v1/pages/"+destID:
+				reply(map[string]interface{}{"id": destID})
+			case r.Method == "GET":
+				notFound()
+			default:
+				body["_path"] = r.Method + " " + r.URL.Path
+				created = append(created, body)
+				var results []interface{}
+				kids, _ := body["children"].([]interface{})
+				for range kids {
+					n++
+					results = append(results, map[string]interface{}{"id": fmt.Sprintf("new-block-%d", n)})
+				}
+				n++
+				reply(map[string]interface{}{"id": fmt.Sprintf("new-%d", n), "results": results})
+			}
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/v1/users":
+			reply(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"id": "ann-src", "type": "person", "person": map[string]interface{}{"email": "ann@example.com"}},
+				map[string]interface{}{"id": "bob-src", "type": "person", "person": map[string]interface{}{"email": "bob@example.com"}},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+			reply(map[string]interface{}{"results": children[id]})
+		case r.URL.Path == "/v1/databases/"+dbID:
+			reply(map[string]interface{}{"id": dbID, "title": text("Tasks"), "properties": map[string]interface{}{
+				"Name":   map[string]interface{}{"type": "title", "title": map[string]interface{}{}},
+				"Status": map[string]interface{}{"type": "status", "status": map[string]interface{}{"options": []interface{}{map[string]interface{}{"name": "Done", "color": "green"}}}},
+				"Owner":  map[string]interface{}{"type": "people", "people": map[string]interface{}{}},
+				"Blocks": map[string]interface{}{"type": "relation", "relation": map[string]interface{}{"database_id": dbID}},
+			}})
+		case r.URL.Path == "/v1/databases/"+dbID+"/query":
+			reply(map[string]interface{}{"results": []interface{}{map[string]interface{}{"id": rowID, "properties": map[string]interface{}{
+				"Name":   map[string]interface{}{"type": "title", "title": text("Ship it")},
+				"Status": map[string]interface{}{"type": "status", "status": map[string]interface{}{"name": "Done"}},
+				"Owner":  map[string]interface{}{"type": "people", "people": []interface{}{map[string]interface{}{"id": "ann-src"}, map[string]interface{}{"id": "bob-src"}}},
+				"Blocks": map[string]interface{}{"id": "rel", "type": "relation", "relation": []interface{}{map[string]interface{}{"id": rowID}}},
+			}}}})
+		case r.URL.Path == "/v1/pages/"+rootID:
+			reply(map[string]interface{}{"id": rootID, "icon": map[string]interface{}{"type": "emoji", "emoji": "🚀"}, "properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "title", "title": text("Root")},
+			}})
+		case r.URL.Path == "/v1/pages/"+childID:
+			reply(map[string]interface{}{"id": childID, "properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "title", "title": text("Child")},
+			}})
+		default:
+			notFound()
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "")
+	config.Save(&config.Config{
+		CurrentProfile: "old",
+		Profiles: map[string]*config.Profile{
+			"old": {Token: "secret_src", WorkspaceName: "Old"},
+			"new": {Token: "secret_dst", WorkspaceName: "New"},
+		},
+	})
+	mapFile := filepath.Join(t.TempDir(), "map.json")
+
+	if _, _, err := executeCommand("migrate", rootID, "--from", "old", "--to", "new", "--parent", destID, "--map-file", mapFile); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(mapFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report migrationReport
+	json.Unmarshal(data, &report)
+	if report.Pages != 2 || report.Databases != 1 || report.Rows != 1 || report.Blocks != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	for _, id := range []string{rootID, childID, dbID, rowID, "b1", "b2"} {
+		if report.IDs[id] == "" {
+			t.Errorf("no new ID for %s in %v", id, report.IDs)
+		}
+	}
+	if report.Users["ann-src"] != "ann-dst" {
+		t.Errorf("users = %v", report.Users)
+	}
+
+	find := func(path string) []map[string]interface{} {
+		var out []map[string]interface{}
+		for _, c := range created {
+			if c["_path"] == path {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+	root := find("POST /v1/pages")[0]
+	if parent, _ := root["parent"].(map[string]interface{}); parent["page_id"] != destID {
+		t.Errorf("root parent = %v", root["parent"])
+	}
+	if icon, _ := root["icon"].(map[string]interface{}); icon["emoji"] != "🚀" {
+		t.Errorf("icon = %v", root["icon"])
+	}
+	db := find("POST /v1/databases")[0]
+	props, _ := db["properties"].(map[string]interface{})
+	if _, ok := props["Status"].(map[string]interface{})["select"]; !ok || props["Blocks"] != nil {
+		t.Errorf("database properties = %v", props)
+	}
+
+	// The relation is added once the database exists, pointing at the copy,
+	// and the row's relation is set to the copied row.
+	var relation, rowRelation bool
+	for _, c := range created {
+		s, _ := json.Marshal(c)
+		if c["_path"] == "PATCH /v1/databases/"+report.IDs[dbID] && strings.Contains(string(s), `"database_id":"`+report.IDs[dbID]) {
+			relation = true
+		}
+		if c["_path"] == "PATCH /v1/pages/"+report.IDs[rowID] && strings.Contains(string(s), `"id":"`+report.IDs[rowID]) {
+			rowRelation = true
+		}
+	}
+	if !relation || !rowRelation {
+		t.Errorf("relation linked = %v, row relation set = %v", relation, rowRelation)
+	}
+
+	// The mention of the child page, copied after it, is fixed up to point
+	// at the copy; Ann is matched by email, Bob left out.
+	fixed := find("PATCH /v1/blocks/" + report.IDs["b1"])
+	if len(fixed) != 1 {
+		t.Fatalf("mention not fixed up: %v", created)
+	}
+	s, _ := json.Marshal(fixed[0])
+	if !strings.Contains(string(s), report.IDs[childID]) || !strings.Contains(string(s), "ann-dst") {
+		t.Errorf("fixed up block = %s", s)
+	}
+	warnings := strings.Join(report.Warnings, "\n")
+	if !strings.Contains(warnings, "status property") || !strings.Contains(warnings, "1 user(s)") {
+		t.Errorf("warnings = %v", report.Warnings)
+	}
+}
+
+func TestMigrateRefusesReadOnlyDestination(t *testing.T) {
+	const rootID = "11111111-1111-1111-1111-111111111111"
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			writes = append(writes, r.Header.Get("Authorization")+" "+r.Method+" "+r.URL.Path)
+		}
+		switch {
+		case r.URL.Path == "/v1/users", strings.HasSuffix(r.URL.Path, "/children"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		case strings.HasPrefix(r.URL.Path, "/v1/databases/"):
+			w.WriteHeader(404)
+			json.NewEncoder(w).Encode(map[string]string{"code": "object_not_found", "message": "not found"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": rootID, "properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Root"}}},
+			}})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "")
+	config.Save(&config.Config{
+		CurrentProfile: "old",
+		Profiles: map[string]*config.Profile{
+			"old":   {Token: "secret_src"},
+			"agent": {Token: "secret_agent", ReadOnly: true},
+		},
+	})
+
+	_, _, err := executeCommand("migrate", rootID, "--to", "agent", "--parent", "99999999999999999999999999999999", "--map-file", filepath.Join(t.TempDir(), "map.json"))
+	if !errors.Is(err, errReadOnly) || !strings.Contains(err.Error(), `profile "agent" is read-only`) {
+		t.Errorf("migrate --to a read-only profile: err = %v", err)
+	}
+	if len(writes) != 0 {
+		t.Errorf("writes sent: %v", writes)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNoConfigAndDefaultDB(t *testing.T) {
+	const dbID = "11111111-1111-1111-1111-111111111111"
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(orderedSchema))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if _, _, err := executeCommand("config", "set", "deep_links", "true", "--no-config"); err == nil {
+		t.Error("config set --no-config should fail")
+	}
+	if _, err := os.Stat(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "notion-cli", "config.json")); !os.IsNotExist(err) {
+		t.Errorf("config.json written with --no-config: %v", err)
+	}
+
+	t.Setenv("NOTION_DEFAULT_DB", dbID)
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "view", "@db", "--no-config"); err != nil {
+			t.Fatalf("db view @db: %v", err)
+		}
+	})
+	if len(paths) != 1 || paths[0] != "/v1/databases/"+dbID {
+		t.Errorf("requests = %v", paths)
+	}
+
+	t.Setenv("NOTION_DEFAULT_DB", "")
+	if _, _, err := executeCommand("db", "view", "@db", "--no-config"); err == nil || !strings.Contains(err.Error(), "NOTION_DEFAULT_DB") {
+		t.Errorf("@db without a default: err = %v", err)
+	}
+	// Flags persist between executeCommand calls.
+	executeCommand("db", "view", dbID, "--no-config=false")
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <query|id|alias>",
+	Short: "Find a page or database and open it",
+	Long: `Open a page or database by alias, ID, URL or title search.
+
+The argument is resolved in this order:
+  1. an alias saved with 'notion alias set'
+  2. a Notion ID or URL
+  3. a title search; an exact (case-insensitive) title match wins,
+     otherwise you pick from the matches when running in a terminal
+
+With --app the page opens in the Notion desktop app (notion:// link)
+instead of the browser; 'notion config set deep_links true' makes that
+the default. --print prints the URL instead of opening it.
+
+Examples:
+  notion open roadmap
+  notion open "Q3 planning"
+  notion open abc123 --app
+  notion open "meeting notes" --first --print`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := strings.Join(args, " ")
+		app, _ := cmd.Flags().GetBool("app")
+		printOnly, _ := cmd.Flags().GetBool("print")
+		first, _ := cmd.Flags().GetBool("first")
+
+		url, err := resolveOpenTarget(input, first)
+		if err != nil {
+			return err
+		}
+		if app {
+			url = desktopURL(url)
+		} else {
+			url = notionLink(url)
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]string{"url": url})
+		}
+		if printOnly {
+			fmt.Println(url)
+			return nil
+		}
+		return openBrowser(url)
+	},
+}
+
+func init() {
+	openCmd.Flags().Bool("app", false, "Open in the Notion desktop app (notion:// link)")
+	openCmd.Flags().Bool("print", false, "Print the URL instead of opening it")
+	openCmd.Flags().Bool("first", false, "Pick the first search match instead of prompting")
+}
+
+// resolveOpenTarget turns an alias, ID/URL or title query into a web URL.
+func resolveOpenTarget(input string, first bool) (string, error) {
+	if id, ok := resolveAlias(input); ok {
+		input = id
+	}
+	if util.IsID(input) {
+		if strings.HasPrefix(input, "http") {
+			return input, nil
+		}
+		return "https://www.notion.so/" + strings.ReplaceAll(util.ResolveID(input), "-", ""), nil
+	}
+
+	token, err := getToken()
+	if err != nil {
+		return "", err
+	}
+	c := newClient(token)
+
+	result, err := c.Search(input, "", 20, "")
+	if err != nil {
+		return "", fmt.Errorf("search: %w", err)
+	}
+	results, _ := result["results"].([]interface{})
+	var matches []map[string]interface{}
+	for _, r := range results {
+		if obj, ok := r.(map[string]interface{}); ok {
+			matches = append(matches, obj)
+		}
+	}
+
+	picked, err := pickOpenMatch(input, matches, first)
+	if err != nil {
+		return "", err
+	}
+	if url, _ := picked["url"].(string); url != "" {
+		return url, nil
+	}
+	id, _ := picked["id"].(string)
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", ""), nil
+}
+
+// pickOpenMatch chooses among search results: a single result or a unique
+// exact title match is taken directly; otherwise the first result when
+// first is set, or an interactive choice.
+func pickOpenMatch(query string, matches []map[string]interface{}, first bool) (map[string]interface{}, error) {
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("nothing matches %q (is it shared with your integration?)", query)
+	}
+	if len(matches) == 1 || first {
+		return matches[0], nil
+	}
+
+	var exact []map[string]interface{}
+	for _, m := range matches {
+		if strings.EqualFold(strings.TrimSpace(render.ExtractTitle(m)), strings.TrimSpace(query)) {
+			exact = append(exact, m)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+
+	if !isInteractive() {
+		var lines []string
+		for _, m := range matches {
+			id, _ := m["id"].(string)
+			lines = append(lines, fmt.Sprintf("  %s  %s", id, render.ExtractTitle(m)))
+		}
+		return nil, usageError(fmt.Errorf("%d pages match %q; pass an ID or --first:\n%s", len(matches), query, strings.Join(lines, "\n")))
+	}
+
+	for i, m := range matches {
+		icon := "📄"
+		if obj, _ := m["object"].(string); obj == "database" {
+			icon = "🗃️"
+		}
+		fmt.Fprintf(os.Stderr, "%3d. %s %s\n", i+1, icon, render.ExtractTitle(m))
+	}
+	fmt.Fprint(os.Stderr, "Open which? ")
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("no selection")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+	if err != nil || n < 1 || n > len(matches) {
+		return nil, fmt.Errorf("invalid selection %q", scanner.Text())
+	}
+	return matches[n-1], nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func TestResolveOpenTargetAliasAndID(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	config.Save(&config.Config{Aliases: map[string]string{"roadmap": "c9e9f681-ec8e-4eb7-be25-bbbe479b05b0"}})
+
+	got, err := resolveOpenTarget("roadmap", false)
+	if err != nil {
+		t.Fatalf("resolveOpenTarget: %v", err)
+	}
+	if got != "https://www.notion.so/c9e9f681ec8e4eb7be25bbbe479b05b0" {
+		t.Errorf("alias URL = %q", got)
+	}
+
+	url := "https://www.notion.so/Team-c9e9f681ec8e4eb7be25bbbe479b05b0"
+	if got, _ := resolveOpenTarget(url, false); got != url {
+		t.Errorf("URL passthrough = %q", got)
+	}
+}
+
+func TestResolveOpenTargetSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+			map[string]interface{}{"object": "page", "id": "p1", "url": "https://www.notion.so/Planning-Archive-p1",
+				"properties": map[string]interface{}{"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Planning archive"}}}}},
+			map[string]interface{}{"object": "page", "id": "p2", "url": "https://www.notion.so/Planning-p2",
+				"properties": map[string]interface{}{"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Planning"}}}}},
+		}})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "secret")
+	old := isInteractive
+	isInteractive = func() bool { return false }
+	defer func() { isInteractive = old }()
+
+	got, err := resolveOpenTarget("planning", false)
+	if err != nil {
+		t.Fatalf("resolveOpenTarget: %v", err)
+	}
+	if got != "https://www.notion.so/Planning-p2" {
+		t.Errorf("exact title match = %q", got)
+	}
+
+	if _, err := resolveOpenTarget("plan", false); err == nil || !strings.Contains(err.Error(), "2 pages match") {
+		t.Errorf("ambiguous query error = %v", err)
+	}
+	if got, _ := resolveOpenTarget("plan", true); got != "https://www.notion.so/Planning-Archive-p1" {
+		t.Errorf("--first = %q", got)
+	}
+}
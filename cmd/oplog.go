@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// opLogEntry is one write recorded in the operation log.
+type opLogEntry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Auth    string    `json:"auth,omitempty"`
+	Command string    `json:"command"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"`
+	Summary string    `json:"summary,omitempty"`
+	Object  string    `json:"object,omitempty"`
+	Status  int       `json:"status,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Review the writes the CLI has made",
+	Long: `Every create, update and delete the CLI sends is appended to a local
+log (JSON Lines): when, who (NOTION_ACTOR, else the system user), with
+which credentials, the command line, the request and a summary of what it
+changed, and how the API answered. Nothing is ever rewritten, so when
+several agents share one integration the log says which did what.
+
+The log lives in the config directory as oplog.jsonl, or at NOTION_OPLOG.
+'notion config set oplog false' stops the recording.`,
+}
+
+var logShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "List logged writes, newest first",
+	Long: `List the writes in the operation log, newest first.
+
+Examples:
+  notion log show
+  notion log show --since 2h --failed
+  notion log show --limit 0 --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLogQuery(cmd, "")
+	},
+}
+
+var logSearchCmd = &cobra.Command{
+	Use:   "search <text>",
+	Short: "Find logged writes mentioning text",
+	Long: `List the logged writes whose actor, command, path, object ID or
+summary contains text (ignoring case), newest first. An ID or URL of a
+page finds the writes to it.
+
+Examples:
+  notion log search 3f2a1b
+  notion log search "page archive" --since 7d
+  notion log search agent-7 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLogQuery(cmd, args[0])
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{logShowCmd, logSearchCmd} {
+		c.Flags().String("since", "", "Only writes after this time (2h, 7d, 2026-03-01)")
+		c.Flags().IntP("limit", "l", 50, "Maximum entries (0 = all)")
+		c.Flags().Bool("failed", false, "Only writes the API rejected or that never arrived")
+	}
+	logCmd.AddCommand(logShowCmd)
+	logCmd.AddCommand(logSearchCmd)
+}
+
+// runLogQuery prints the log entries matching the command's flags and,
+// when non-empty, the search text.
+func runLogQuery(cmd *cobra.Command, text string) error {
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+	failed, _ := cmd.Flags().GetBool("failed")
+	var since time.Time
+	if sinceFlag != "" {
+		var err error
+		if since, err = parseSince(sinceFlag, time.Now()); err != nil {
+			return err
+		}
+	}
+	entries, err := loadOpLog()
+	if err != nil {
+		return err
+	}
+
+	// A page URL finds the writes to the page, as its ID does.
+	if util.IsID(text) {
+		text = util.ResolveID(text)
+	}
+	needle := strings.ToLower(strings.ReplaceAll(text, "-", ""))
+	matched := []opLogEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Time.Before(since) || (failed && e.Error == "" && e.Status < 400) {
+			continue
+		}
+		if needle != "" {
+			hay := strings.ToLower(strings.ReplaceAll(strings.Join([]string{e.Actor, e.Auth, e.Command, e.Path, e.Object, e.Summary}, "\n"), "-", ""))
+			if !strings.Contains(hay, needle) {
+				continue
+			}
+		}
+		matched = append(matched, e)
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+	}
+
+	if outputFormat == "json" {
+		return render.JSON(matched)
+	}
+	if len(matched) == 0 {
+		fmt.Println("No logged writes.")
+		return nil
+	}
+	var rows [][]string
+	for _, e := range matched {
+		result := fmt.Sprint(e.Status)
+		if e.Error != "" {
+			result = e.Error
+		}
+		rows = append(rows, []string{e.Time.Local().Format("2006-01-02 15:04:05"), e.Actor, e.Method + " " + e.Path, e.Summary, result, e.Command})
+	}
+	render.Table([]string{"TIME", "ACTOR", "REQUEST", "CHANGES", "RESULT", "COMMAND"}, rows)
+	return nil
+}
+
+// opLogPath is where the operation log is kept: NOTION_OPLOG, else the
+// config directory.
+func opLogPath() string {
+	if p := os.Getenv("NOTION_OPLOG"); p != "" {
+		return p
+	}
+	return filepath.Join(config.Dir(), "oplog.jsonl")
+}
+
+// opLogEnabled reports whether writes are recorded: unless 'notion config
+// set oplog false'.
+func opLogEnabled() bool {
+	cfg, err := config.Load()
+	return err != nil || cfg.Setting("oplog") == "" || isTruthy(cfg.Setting("oplog"))
+}
+
+// logWrites is client middleware appending every write, successful or
+// not, to the operation log. It sits outside the retries, so a write is
+// recorded once with how it finally went.
+func logWrites(next notion.Handler) notion.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		if !isWrite(req) || !opLogEnabled() {
+			return next(req)
+		}
+		e := opLogEntry{Time: time.Now().UTC(), Actor: opLogActor(), Command: currentCommandLine(), Method: req.Method, Path: apiPath(req)}
+		_, e.Auth = resolveToken()
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				data, _ := io.ReadAll(body)
+				e.Summary = requestSummary(data)
+			}
+		}
+		resp, err := next(req)
+		if err != nil {
+			e.Error = firstLine(err)
+		} else {
+			e.Status = resp.StatusCode
+			data, rerr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			if rerr != nil {
+				return nil, fmt.Errorf("read response: %w", rerr)
+			}
+			var obj struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(data, &obj) == nil {
+				e.Object = obj.ID
+			}
+		}
+		appendOpLog(e)
+		return resp, err
+	}
+}
+
+// opLogActor names who is running the CLI: NOTION_ACTOR (set it per
+// agent), else the system user.
+func opLogActor() string {
+	if a := os.Getenv("NOTION_ACTOR"); a != "" {
+		return a
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// requestSummary describes what a write body changes: the properties it
+// sets, how many blocks it adds and the flags it flips, e.g.
+// "properties: Due, Status; archived=true".
+func requestSummary(body []byte) string {
+	var m map[string]interface{}
+	if json.Unmarshal(body, &m) != nil {
+		return ""
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case map[string]interface{}:
+			if k == "properties" {
+				parts = append(parts, "properties: "+strings.Join(sortedKeys(v), ", "))
+			} else {
+				parts = append(parts, k)
+			}
+		case []interface{}:
+			parts = append(parts, fmt.Sprintf("%s ×%d", k, len(v)))
+		case bool, float64:
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		default:
+			parts = append(parts, k)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+var (
+	opLogMu     sync.Mutex
+	opLogWarned bool
+)
+
+// appendOpLog adds e to the end of the log. A log that can't be written
+// is reported once on stderr; the write itself has already happened.
+func appendOpLog(e opLogEntry) {
+	opLogMu.Lock()
+	defer opLogMu.Unlock()
+	err := func() error {
+		path := opLogPath()
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = f.Write(append(data, '\n'))
+		return err
+	}()
+	if err != nil && !opLogWarned {
+		opLogWarned = true
+		fmt.Fprintf(os.Stderr, "note: could not write the operation log: %s\n", err)
+	}
+}
+
+func loadOpLog() ([]opLogEntry, error) {
+	f, err := os.Open(opLogPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read operation log: %w", err)
+	}
+	defer f.Close()
+	var entries []opLogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e opLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// A torn last line from a crash shouldn't hide the rest
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
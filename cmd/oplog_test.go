@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain keeps the writes of every test in this package out of the real
+// operation log.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "notion-oplog")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("NOTION_OPLOG", filepath.Join(dir, "oplog.jsonl"))
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestOperationLog(t *testing.T) {
+	const pageID = "11111111-1111-1111-1111-111111111111"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		if r.Method == "PATCH" && strings.Contains(r.URL.Path, "22222222") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"object":"page","id":"` + pageID + `","properties":{"Status":{"id":"s","type":"select","select":null}}}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("NOTION_ACTOR", "agent-7")
+	t.Setenv("NOTION_OPLOG", filepath.Join(t.TempDir(), "oplog.jsonl"))
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "set", pageID, "Status=Done"); err != nil {
+			t.Fatalf("page set: %v", err)
+		}
+		executeCommand("page", "archive", "22222222-2222-2222-2222-222222222222")
+	})
+
+	entries, err := loadOpLog()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("log = %+v, %v; want 2 entries", entries, err)
+	}
+	set := entries[0]
+	if set.Actor != "agent-7" || set.Method != "PATCH" || set.Path != "/v1/pages/"+pageID ||
+		set.Summary != "properties: Status" || set.Object != pageID || set.Status != 200 || set.Auth != "NOTION_TOKEN" {
+		t.Errorf("entry = %+v", set)
+	}
+	if entries[1].Status != 404 {
+		t.Errorf("failed write: %+v", entries[1])
+	}
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("log", "search", "2222-2222", "--failed"); err != nil {
+			t.Fatalf("log search: %v", err)
+		}
+	})
+	if !strings.Contains(out, "agent-7") || strings.Contains(out, "properties: Status") {
+		t.Errorf("log search --failed:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if _, _, err := executeCommand("log", "search", "https://www.notion.so/acme/Roadmap-11111111111111111111111111111111?pvs=4"); err != nil {
+			t.Fatalf("log search <url>: %v", err)
+		}
+	})
+	if !strings.Contains(out, "properties: Status") || strings.Contains(out, "404") {
+		t.Errorf("log search <url>:\n%s", out)
+	}
+}
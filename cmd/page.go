@@ -4,19 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
 	"github.com/spf13/cobra"
 )
 
 // openURL opens a URL in the default browser across platforms.
 func openURL(url string) error {
+	url = notionLink(url)
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "darwin":
@@ -39,20 +42,62 @@ var pageViewCmd = &cobra.Command{
 	Short: "View a page's content",
 	Long: `Display a Notion page's content as readable text.
 
+Every block is shown, printed as soon as the batch holding it arrives,
+so even a 5,000-block page starts showing at once; --limit stops after
+that many top-level blocks. --all also shows the blocks nested in others
+(toggles, list items, columns). --raw-blocks prints the API's block
+objects instead, one JSON object per line, streamed the same way.
+
+A database row can also be named by its unique ID (TASK-123) with --db.
+
 Examples:
   notion page view abc123
   notion page view https://notion.so/My-Page-abc123
-  notion page view abc123 --format json`,
+  notion page view abc123 --format json
+  notion page view abc123 --all
+  notion page view abc123 --limit 20
+  notion page view TASK-123 --db tasks-db-id
+  notion page view abc123 --raw-blocks --all | jq -r .type`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		rawBlocks, _ := cmd.Flags().GetBool("raw-blocks")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return usageError(fmt.Errorf("--limit must be 0 (no limit) or more"))
+		}
+		truncated := false
+		// noteTruncated says on stderr that --limit cut the page short, so
+		// stdout stays clean for pipes.
+		noteTruncated := func() {
+			if truncated {
+				fmt.Fprintf(os.Stderr, "note: showing the first %d blocks; drop --limit to see the rest\n", limit)
+			}
+		}
 		token, err := getToken()
 		if err != nil {
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
+
+		if rawBlocks {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetEscapeHTML(false)
+			err := streamBlocks(c, pageID, 0, all, limitBlocks(limit, &truncated, func(block map[string]interface{}, _ int) error {
+				return enc.Encode(block)
+			}))
+			if err != nil {
+				return fmt.Errorf("get blocks: %w", err)
+			}
+			noteTruncated()
+			return nil
+		}
 
 		// Get page metadata
 		page, err := c.GetPage(pageID)
@@ -60,54 +105,46 @@ Examples:
 			return fmt.Errorf("get page: %w", err)
 		}
 
-		// Get page blocks (content)
-		blocks, err := c.GetBlockChildren(pageID, 100, "")
-		if err != nil {
-			return fmt.Errorf("get blocks: %w", err)
-		}
-
 		if outputFormat == "json" {
+			results, truncated, err := blockTree(c, pageID, all, limit)
+			if err != nil {
+				return fmt.Errorf("get blocks: %w", err)
+			}
 			combined := map[string]interface{}{
 				"page":   page,
-				"blocks": blocks,
+				"blocks": map[string]interface{}{"object": "list", "results": results, "has_more": truncated},
 			}
 			return render.JSON(combined)
 		}
 
-		// Render blocks
-		results, _ := blocks["results"].([]interface{})
-
+		// Render blocks as they arrive
+		emit := func(block map[string]interface{}, depth int) error {
+			renderBlock(block, depth)
+			return nil
+		}
 		if outputFormat == "md" || outputFormat == "markdown" {
 			// Pure markdown output
 			title := render.ExtractTitle(page)
 			fmt.Printf("# %s\n\n", title)
-			for _, b := range results {
-				block, ok := b.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				renderBlockMarkdown(block, 0)
+			emit = func(block map[string]interface{}, depth int) error {
+				renderBlockMarkdown(block, depth)
+				return nil
 			}
-			return nil
-		}
-
-		// Pretty print
-		title := render.ExtractTitle(page)
-		lastEdited, _ := page["last_edited_time"].(string)
-
-		render.Title("📄", title)
-		render.Separator()
-		render.Subtitle(fmt.Sprintf("Last edited: %s", lastEdited))
-		fmt.Println()
+		} else {
+			// Pretty print
+			title := render.ExtractTitle(page)
+			lastEdited, _ := page["last_edited_time"].(string)
 
-		for _, b := range results {
-			block, ok := b.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			renderBlock(block, 0)
+			render.Title("📄", title)
+			render.Separator()
+			render.Subtitle(fmt.Sprintf("Last edited: %s", displayTime(lastEdited)))
+			fmt.Println()
 		}
 
+		if err := streamBlocks(c, pageID, 0, all, limitBlocks(limit, &truncated, emit)); err != nil {
+			return fmt.Errorf("get blocks: %w", err)
+		}
+		noteTruncated()
 		return nil
 	},
 }
@@ -119,25 +156,37 @@ var pageListCmd = &cobra.Command{
 
 Examples:
   notion page list
-  notion page list --limit 20`,
+  notion page list --limit 20
+  notion page list --all-profiles`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		cursor, _ := cmd.Flags().GetString("cursor")
+		all, _ := cmd.Flags().GetBool("all")
+
+		if allProfiles, _ := cmd.Flags().GetBool("all-profiles"); allProfiles {
+			if cursor != "" {
+				return usageError(fmt.Errorf("--cursor cannot be combined with --all-profiles"))
+			}
+			return searchAllProfiles("", "page", limit, all, false)
+		}
+
 		token, err := getToken()
 		if err != nil {
 			return err
 		}
-
-		limit, _ := cmd.Flags().GetInt("limit")
-		cursor, _ := cmd.Flags().GetString("cursor")
-		all, _ := cmd.Flags().GetBool("all")
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var allResults []interface{}
 		currentCursor := cursor
+		var truncated error
 
 		for {
 			result, err := c.Search("", "page", limit, currentCursor)
 			if err != nil {
+				if overBudget(err, allResults) {
+					truncated = err
+					break
+				}
 				return err
 			}
 
@@ -159,6 +208,15 @@ Examples:
 			currentCursor = nextCursor
 		}
 
+		if truncated != nil && outputFormat == "json" {
+			return renderTruncated(allResults, currentCursor, truncated)
+		}
+
+		if porcelain != "" {
+			porcelainObjects(allResults, false)
+			return truncated
+		}
+
 		headers := []string{"TITLE", "ID", "LAST EDITED"}
 		var rows [][]string
 
@@ -170,14 +228,11 @@ Examples:
 			title := render.ExtractTitle(obj)
 			id, _ := obj["id"].(string)
 			lastEdited, _ := obj["last_edited_time"].(string)
-			if len(lastEdited) > 10 {
-				lastEdited = lastEdited[:10]
-			}
-			rows = append(rows, []string{title, id, lastEdited})
+			rows = append(rows, []string{title, id, displayDate(lastEdited)})
 		}
 
 		render.Table(headers, rows)
-		return nil
+		return truncated
 	},
 }
 
@@ -186,37 +241,70 @@ var pageCreateCmd = &cobra.Command{
 	Short: "Create a new page",
 	Long: `Create a new page under a parent page or database.
 
-When creating under a database, provide properties as key=value arguments.
-Property types are auto-detected from the database schema.
+The parent can be a page, a database (wiki databases included) or
+"workspace" for a top-level page, which Notion allows public integrations
+only. Which of them it is is found out by fetching it. Under a database,
+provide properties as key=value arguments; their types are auto-detected
+from the database schema.
+
+--file fills the page with a markdown document ("-" reads stdin), local
+images included. Without --title, its leading "# Heading" (or else the
+file name) becomes the title. Documents longer than the 100 blocks a
+request can carry are appended in batches right after.
 
 Examples:
   notion page create <page-id> --title "My New Page"
   notion page create <page-id> --title "Meeting Notes" --body "Agenda items..."
-  notion page create <db-id> --db "Name=Sprint Review" "Status=Todo" "Date=2026-03-01"`,
+  notion page create <page-id> --file notes.md
+  pandoc -t gfm spec.docx | notion page create <page-id> --title Spec --file -
+  notion page create <db-id> "Name=Sprint Review" "Status=Todo" "Date=2026-03-01"
+  notion page create workspace --title "Team Home"
+  notion page create <page-id> --title "Draft" --copy      # URL to the clipboard
+  notion page create <page-id> --title "Draft" --copy=id`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkCopyFlag(cmd); err != nil {
+			return err
+		}
 		token, err := getToken()
 		if err != nil {
 			return err
 		}
 
-		parentID := util.ResolveID(args[0])
 		title, _ := cmd.Flags().GetString("title")
 		body, _ := cmd.Flags().GetString("body")
-		isDB, _ := cmd.Flags().GetBool("db")
+		filePath, _ := cmd.Flags().GetString("file")
+		if body != "" && filePath != "" {
+			return usageError(fmt.Errorf("--body and --file are mutually exclusive"))
+		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		parent, err := resolvePageParent(c, args[0])
+		if err != nil {
+			return err
+		}
+
+		var children []map[string]interface{}
+		if filePath != "" {
+			var markdown string
+			markdown, title, err = readPageFile(filePath, title)
+			if err != nil {
+				return err
+			}
+			children, _, err = parseMarkdownWithImages(c, markdown, diskImages(filepath.Dir(filePath)))
+			if err != nil {
+				return err
+			}
+			if children, err = handleOversizedBlocks(children, oversizeSplit); err != nil {
+				return err
+			}
+		}
 
 		var reqBody map[string]interface{}
 
-		if isDB {
+		if parent.Kind == "database_id" {
 			// Database parent: auto-detect property types from schema
-			db, err := c.GetDatabase(parentID)
-			if err != nil {
-				return fmt.Errorf("get database schema: %w", err)
-			}
-			dbProps, _ := db["properties"].(map[string]interface{})
+			dbProps := parent.Schema
 
 			properties := map[string]interface{}{}
 
@@ -224,12 +312,12 @@ Examples:
 			for _, kv := range args[1:] {
 				parts := strings.SplitN(kv, "=", 2)
 				if len(parts) != 2 {
-					return fmt.Errorf("invalid property format %q, expected key=value", kv)
+					return invalidInput(fmt.Errorf("invalid property format %q, expected key=value", kv))
 				}
 				key, value := parts[0], parts[1]
 				propDef, ok := dbProps[key].(map[string]interface{})
 				if !ok {
-					return fmt.Errorf("property %q not found in database schema", key)
+					return invalidInput(fmt.Errorf("property %q not found in database schema", key))
 				}
 				propType, _ := propDef["type"].(string)
 				properties[key] = buildPropertyValue(propType, value)
@@ -248,21 +336,19 @@ Examples:
 			}
 
 			reqBody = map[string]interface{}{
-				"parent": map[string]interface{}{
-					"database_id": parentID,
-				},
+				"parent":     parent.object(),
 				"properties": properties,
 			}
 		} else {
-			// Page parent
+			// Page or workspace parent
 			if title == "" {
-				return fmt.Errorf("--title is required")
+				return usageError(fmt.Errorf("--title is required"))
+			}
+			if len(args) > 1 {
+				return fmt.Errorf("%s is not a database; key=value properties need a database parent", args[0])
 			}
-
 			reqBody = map[string]interface{}{
-				"parent": map[string]interface{}{
-					"page_id": parentID,
-				},
+				"parent": parent.object(),
 				"properties": map[string]interface{}{
 					"title": map[string]interface{}{
 						"title": []map[string]interface{}{
@@ -273,6 +359,17 @@ Examples:
 			}
 		}
 
+		// The first batch of blocks goes with the page; any rest is
+		// appended once it exists.
+		var rest []map[string]interface{}
+		if len(children) > 0 {
+			batches := chunkChildren(children)
+			reqBody["children"] = batches[0]
+			for _, b := range batches[1:] {
+				rest = append(rest, b...)
+			}
+		}
+
 		// Add body content if provided
 		if body != "" {
 			reqBody["children"] = []map[string]interface{}{
@@ -290,21 +387,28 @@ Examples:
 
 		data, err := c.Post("/v1/pages", reqBody)
 		if err != nil {
-			return fmt.Errorf("create page: %w", err)
-		}
-
-		if outputFormat == "json" {
-			var result map[string]interface{}
-			if err := json.Unmarshal(data, &result); err != nil {
-				return err
+			if parent.Kind == "workspace" {
+				return fmt.Errorf("create page: %w (top-level pages need a public integration; internal ones must create under a shared page)", err)
 			}
-			return render.JSON(result)
+			return fmt.Errorf("create page: %w", err)
 		}
+		rememberCreated(data)
 
 		var result map[string]interface{}
 		if err := json.Unmarshal(data, &result); err != nil {
 			return err
 		}
+		if len(rest) > 0 {
+			id, _ := result["id"].(string)
+			if _, err := appendChildrenBatched(c, id, "", rest); err != nil {
+				return fmt.Errorf("page %s created, but appending the rest of %s failed: %w", id, filePath, err)
+			}
+		}
+		copyResult(cmd, result)
+
+		if outputFormat == "json" {
+			return render.JSON(result)
+		}
 
 		id, _ := result["id"].(string)
 		url, _ := result["url"].(string)
@@ -316,13 +420,41 @@ Examples:
 		render.Title("✓", fmt.Sprintf("Created: %s", displayTitle))
 		render.Field("ID", id)
 		if url != "" {
-			render.Field("URL", url)
+			render.Field("URL", notionLink(url))
 		}
 
 		return nil
 	},
 }
 
+// readPageFile reads a markdown document for 'page create --file' ("-" is
+// stdin). Without a title, a leading "# Heading" becomes the title (and is
+// dropped from the content), or else the file name.
+func readPageFile(path, title string) (string, string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("read file: %w", err)
+	}
+	markdown := string(data)
+	if title != "" {
+		return markdown, title, nil
+	}
+	first, rest, _ := strings.Cut(strings.TrimLeft(markdown, "\r\n"), "\n")
+	if heading := strings.TrimSpace(first); strings.HasPrefix(heading, "# ") {
+		return strings.TrimLeft(rest, "\r\n"), strings.TrimSpace(heading[2:]), nil
+	}
+	if path != "-" {
+		title = importTitle(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), false)
+	}
+	return markdown, title, nil
+}
+
 var pageArchiveCmd = &cobra.Command{
 	Use:     "archive <page-id|url>",
 	Aliases: []string{"delete", "trash"},
@@ -346,9 +478,12 @@ Examples:
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
 
 		body := map[string]interface{}{
 			"archived": true,
@@ -376,10 +511,22 @@ Examples:
 var pageMoveCmd = &cobra.Command{
 	Use:   "move <page-id|url>",
 	Short: "Move a page to a new parent",
-	Long: `Move a page under a different parent.
+	Long: `Move a page under a different parent: a page, a database or
+"workspace". Which of them --to names is found out by fetching it, and
+moves that can't work (a page in the trash, into its own sub-pages, to a
+parent the integration can't see — such as one in another workspace) are
+refused up front.
+
+Where the API won't move a page, --copy-fallback recreates it under the
+new parent instead (title, icon, cover, content and the properties a
+database parent shares) and archives the original with a link to the
+copy. The copy is a new page with a new ID: comments and backlinks stay
+with the original.
 
 Examples:
-  notion page move abc123 --to def456`,
+  notion page move abc123 --to def456
+  notion page move abc123 --to <db-id>
+  notion page move abc123 --to def456 --copy-fallback`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -389,22 +536,43 @@ Examples:
 
 		pageID := util.ResolveID(args[0])
 		to, _ := cmd.Flags().GetString("to")
+		copyFallback, _ := cmd.Flags().GetBool("copy-fallback")
 		if to == "" {
-			return fmt.Errorf("--to flag is required")
+			return usageError(fmt.Errorf("--to flag is required"))
 		}
-		toID := util.ResolveID(to)
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		parent, err := resolvePageParent(c, to)
+		if err != nil {
+			return fmt.Errorf("%w (a page can only move within its workspace)", err)
+		}
+		page, err := checkMove(c, pageID, parent)
+		if err != nil {
+			return err
+		}
 
 		body := map[string]interface{}{
-			"parent": map[string]interface{}{
-				"page_id": toID,
-			},
+			"parent": parent.object(),
 		}
 
 		data, err := c.Post(fmt.Sprintf("/v1/pages/%s/move", pageID), body)
+		if err != nil && copyFallback && moveRefused(err) {
+			fmt.Fprintf(os.Stderr, "note: the API won't move the page (%s); copying it instead\n", firstLine(err))
+			copied, err := copyPageTo(c, page, parent)
+			if err != nil {
+				return fmt.Errorf("copy page: %w", err)
+			}
+			if outputFormat == "json" {
+				return render.JSON(map[string]interface{}{"copied": true, "archived": pageID, "page": copied})
+			}
+			id, _ := copied["id"].(string)
+			fmt.Printf("✓ Page copied to %s as %s; the original is archived\n", parent, id)
+			return nil
+		}
 		if err != nil {
+			if moveRefused(err) {
+				return fmt.Errorf("move page: %w (--copy-fallback copies it there and archives the original instead)", err)
+			}
 			return fmt.Errorf("move page: %w", err)
 		}
 
@@ -416,7 +584,7 @@ Examples:
 			return render.JSON(result)
 		}
 
-		fmt.Printf("✓ Page moved to %s\n", toID)
+		fmt.Printf("✓ Page moved to %s\n", parent)
 		return nil
 	},
 }
@@ -453,10 +621,14 @@ var pageSetCmd = &cobra.Command{
 
 The CLI will fetch the page schema to determine property types automatically.
 
+Dates take a time (Due=2026-03-01T14:00, in the --tz zone, default local)
+and ranges written start..end (Trip=2026-03-01..2026-03-05).
+
 Examples:
   notion page set abc123 Status=Done
   notion page set abc123 Status=Done Priority=High
-  notion page set abc123 "Name=My New Title"`,
+  notion page set abc123 "Name=My New Title"
+  notion page set abc123 Due=2026-03-01T14:00 --tz Europe/Berlin`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -464,9 +636,12 @@ Examples:
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
 
 		// Get the page to determine property types
 		page, err := c.GetPage(pageID)
@@ -481,14 +656,14 @@ Examples:
 		for _, kv := range args[1:] {
 			parts := strings.SplitN(kv, "=", 2)
 			if len(parts) != 2 {
-				return fmt.Errorf("invalid property format %q, expected key=value", kv)
+				return invalidInput(fmt.Errorf("invalid property format %q, expected key=value", kv))
 			}
 			key, value := parts[0], parts[1]
 
 			// Look up property type from existing properties
 			propDef, ok := existingProps[key].(map[string]interface{})
 			if !ok {
-				return fmt.Errorf("property %q not found on page", key)
+				return invalidInput(fmt.Errorf("property %q not found on page", key))
 			}
 			propType, _ := propDef["type"].(string)
 			properties[key] = buildPropertyValue(propType, value)
@@ -521,9 +696,14 @@ var pagePropsCmd = &cobra.Command{
 	Short: "Show page properties",
 	Long: `Show all properties of a page, or retrieve a specific property value.
 
+With --watch the page is polled every --interval and each property change
+is printed with the time it was seen (JSON Lines with --format json),
+until Ctrl-C. Handy for checking that an automation updates a page.
+
 Examples:
   notion page props abc123
-  notion page props abc123 title`,
+  notion page props abc123 title
+  notion page props abc123 --watch --interval 5s`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		token, err := getToken()
@@ -531,9 +711,20 @@ Examples:
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
+
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			if len(args) == 2 {
+				return usageError(fmt.Errorf("--watch shows all properties; drop the property ID"))
+			}
+			interval, _ := cmd.Flags().GetDuration("interval")
+			return runPropsWatch(c, pageID, interval)
+		}
 
 		if len(args) == 2 {
 			// Get specific property
@@ -562,6 +753,13 @@ Examples:
 
 		title := render.ExtractTitle(page)
 		render.Title("📄", title)
+		if name, prop := pageVerification(page); name != "" {
+			status := extractPropertyValue(prop)
+			if verificationState(prop, timeNow()) == verificationExpired {
+				status += " (expired)"
+			}
+			render.Field("Wiki page", status)
+		}
 		render.Separator()
 
 		props, _ := page["properties"].(map[string]interface{})
@@ -571,7 +769,7 @@ Examples:
 				continue
 			}
 			propType, _ := prop["type"].(string)
-			value := extractPropertyValue(prop)
+			value := displayPropertyValue(prop)
 			render.Field(name, fmt.Sprintf("%s (%s)", value, propType))
 		}
 
@@ -594,8 +792,7 @@ Examples:
 		}
 
 		pageID := util.ResolveID(args[0])
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		body := map[string]interface{}{
 			"archived": false,
@@ -633,20 +830,23 @@ Examples:
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
 		propName, _ := cmd.Flags().GetString("prop")
 		toID, _ := cmd.Flags().GetString("to")
 
 		if propName == "" {
-			return fmt.Errorf("--prop is required")
+			return usageError(fmt.Errorf("--prop is required"))
 		}
 		if toID == "" {
-			return fmt.Errorf("--to is required")
+			return usageError(fmt.Errorf("--to is required"))
 		}
 		toID = util.ResolveID(toID)
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
 
 		// Get current page to read existing relations
 		page, err := c.GetPage(pageID)
@@ -711,20 +911,23 @@ Examples:
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
 		propName, _ := cmd.Flags().GetString("prop")
 		fromID, _ := cmd.Flags().GetString("from")
 
 		if propName == "" {
-			return fmt.Errorf("--prop is required")
+			return usageError(fmt.Errorf("--prop is required"))
 		}
 		if fromID == "" {
-			return fmt.Errorf("--from is required")
+			return usageError(fmt.Errorf("--from is required"))
 		}
 		fromID = util.ResolveID(fromID)
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
 
 		// Get current page to read existing relations
 		page, err := c.GetPage(pageID)
@@ -799,11 +1002,14 @@ Examples:
 			return err
 		}
 
-		pageID := util.ResolveID(args[0])
 		editorFlag, _ := cmd.Flags().GetString("editor")
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
+		dbArg, _ := cmd.Flags().GetString("db")
+		pageID, err := resolveRowID(c, args[0], dbArg)
+		if err != nil {
+			return err
+		}
 
 		// Get page metadata for title
 		page, err := c.GetPage(pageID)
@@ -936,18 +1142,33 @@ Examples:
 }
 
 func init() {
+	pageViewCmd.Flags().Bool("all", false, "Also show blocks nested in other blocks")
+	pageViewCmd.Flags().Int("limit", 0, "Stop after this many top-level blocks (0 = no limit)")
+	pageViewCmd.Flags().Bool("raw-blocks", false, "Print the raw block objects, one JSON object per line")
 	pageListCmd.Flags().IntP("limit", "l", 10, "Maximum results")
 	pageListCmd.Flags().String("cursor", "", "Pagination cursor")
 	pageListCmd.Flags().Bool("all", false, "Fetch all pages of results")
+	pageListCmd.Flags().Bool("all-profiles", false, "List across every saved profile")
 	pageCreateCmd.Flags().String("title", "", "Page title (required for page parent)")
 	pageCreateCmd.Flags().String("body", "", "Page body text")
-	pageCreateCmd.Flags().Bool("db", false, "Create under a database (properties as key=value args)")
-	pageMoveCmd.Flags().String("to", "", "Target parent page/database ID or URL (required)")
+	pageCreateCmd.Flags().String("file", "", "Page content from a markdown file (- for stdin)")
+	pageCreateCmd.Flags().Bool("db", false, "Create under a database (no longer needed)")
+	pageCreateCmd.Flags().MarkDeprecated("db", "the parent type is detected automatically")
+	addCopyFlag(pageCreateCmd)
+	pageMoveCmd.Flags().String("to", "", "Target parent page/database ID or URL, or workspace (required)")
+	markIDFlags(pageMoveCmd, "to")
+	pageMoveCmd.Flags().Bool("copy-fallback", false, "Copy the page and archive the original when it can't be moved")
 	pageLinkCmd.Flags().String("prop", "", "Relation property name (required)")
 	pageLinkCmd.Flags().String("to", "", "Target page ID or URL to link (required)")
 	pageUnlinkCmd.Flags().String("prop", "", "Relation property name (required)")
 	pageUnlinkCmd.Flags().String("from", "", "Target page ID or URL to unlink (required)")
+	markIDFlags(pageLinkCmd, "to")
+	markIDFlags(pageUnlinkCmd, "from")
 	pageEditCmd.Flags().String("editor", "", "Editor to use (default: $VISUAL, $EDITOR, or vi)")
+	for _, c := range []*cobra.Command{pageViewCmd, pagePropsCmd, pageSetCmd, pageArchiveCmd, pageEditCmd, pageLinkCmd, pageUnlinkCmd} {
+		c.Flags().String("db", "", "Database to look a unique ID (e.g. TASK-123) up in")
+		markIDFlags(c, "db")
+	}
 
 	pageCmd.AddCommand(pageViewCmd)
 	pageCmd.AddCommand(pageListCmd)
@@ -1081,200 +1302,21 @@ func renderBlockMarkdownToBuffer(buf *bytes.Buffer, block map[string]interface{}
 }
 
 // buildPropertyValue converts a string value to a Notion property value based on type.
+// Times in dates without an offset are in the --tz zone.
 func buildPropertyValue(propType, value string) interface{} {
-	switch propType {
-	case "title":
-		return map[string]interface{}{
-			"title": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}
-	case "rich_text":
-		return map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}
-	case "number":
-		// Try to parse as number
-		var n json.Number = json.Number(value)
-		if f, err := n.Float64(); err == nil {
-			return map[string]interface{}{"number": f}
-		}
-		return map[string]interface{}{"number": value}
-	case "select":
-		return map[string]interface{}{
-			"select": map[string]interface{}{"name": value},
-		}
-	case "multi_select":
-		names := strings.Split(value, ",")
-		options := []map[string]interface{}{}
-		for _, n := range names {
-			options = append(options, map[string]interface{}{"name": strings.TrimSpace(n)})
-		}
-		return map[string]interface{}{"multi_select": options}
-	case "status":
-		return map[string]interface{}{
-			"status": map[string]interface{}{"name": value},
-		}
-	case "date":
-		parts := strings.SplitN(value, "/", 2)
-		d := map[string]interface{}{"start": parts[0]}
-		if len(parts) == 2 {
-			d["end"] = parts[1]
-		}
-		return map[string]interface{}{
-			"date": d,
-		}
-	case "checkbox":
-		return map[string]interface{}{
-			"checkbox": value == "true" || value == "1" || value == "yes",
-		}
-	case "url":
-		return map[string]interface{}{"url": value}
-	case "email":
-		return map[string]interface{}{"email": value}
-	case "phone_number":
-		return map[string]interface{}{"phone_number": value}
-	default:
-		// Fallback: try as rich_text
-		return map[string]interface{}{
-			"rich_text": []map[string]interface{}{
-				{"text": map[string]interface{}{"content": value}},
-			},
-		}
+	if propType == "date" {
+		return notion.DateValue(value, dateLocation)
 	}
+	return notion.PropertyValue(propType, value)
 }
 
 // extractPropertyValue extracts a human-readable value from a Notion property.
 func extractPropertyValue(prop map[string]interface{}) string {
-	propType, _ := prop["type"].(string)
-
-	switch propType {
-	case "title":
-		if arr, ok := prop["title"].([]interface{}); ok {
-			return extractPlainTextFromRichText(arr)
-		}
-	case "rich_text":
-		if arr, ok := prop["rich_text"].([]interface{}); ok {
-			return extractPlainTextFromRichText(arr)
-		}
-	case "number":
-		if n, ok := prop["number"]; ok && n != nil {
-			return fmt.Sprintf("%v", n)
-		}
-	case "select":
-		if sel, ok := prop["select"].(map[string]interface{}); ok {
-			name, _ := sel["name"].(string)
-			return name
-		}
-	case "multi_select":
-		if arr, ok := prop["multi_select"].([]interface{}); ok {
-			var names []string
-			for _, item := range arr {
-				if m, ok := item.(map[string]interface{}); ok {
-					n, _ := m["name"].(string)
-					names = append(names, n)
-				}
-			}
-			return strings.Join(names, ", ")
-		}
-	case "status":
-		if s, ok := prop["status"].(map[string]interface{}); ok {
-			name, _ := s["name"].(string)
-			return name
-		}
-	case "date":
-		if d, ok := prop["date"].(map[string]interface{}); ok {
-			start, _ := d["start"].(string)
-			end, _ := d["end"].(string)
-			if end != "" {
-				return start + " → " + end
-			}
-			return start
-		}
-	case "checkbox":
-		if b, ok := prop["checkbox"].(bool); ok {
-			if b {
-				return "✓"
-			}
-			return "✗"
-		}
-	case "url":
-		if u, ok := prop["url"].(string); ok {
-			return u
-		}
-	case "email":
-		if e, ok := prop["email"].(string); ok {
-			return e
-		}
-	case "phone_number":
-		if p, ok := prop["phone_number"].(string); ok {
-			return p
-		}
-	case "people":
-		if arr, ok := prop["people"].([]interface{}); ok {
-			var names []string
-			for _, item := range arr {
-				if m, ok := item.(map[string]interface{}); ok {
-					n, _ := m["name"].(string)
-					names = append(names, n)
-				}
-			}
-			return strings.Join(names, ", ")
-		}
-	case "relation":
-		if arr, ok := prop["relation"].([]interface{}); ok {
-			var ids []string
-			for _, item := range arr {
-				if m, ok := item.(map[string]interface{}); ok {
-					id, _ := m["id"].(string)
-					ids = append(ids, id)
-				}
-			}
-			return strings.Join(ids, ", ")
-		}
-	case "formula":
-		if f, ok := prop["formula"].(map[string]interface{}); ok {
-			fType, _ := f["type"].(string)
-			if v, ok := f[fType]; ok {
-				return fmt.Sprintf("%v", v)
-			}
-		}
-	case "rollup":
-		if r, ok := prop["rollup"].(map[string]interface{}); ok {
-			rType, _ := r["type"].(string)
-			if v, ok := r[rType]; ok {
-				return fmt.Sprintf("%v", v)
-			}
-		}
-	case "created_time":
-		if t, ok := prop["created_time"].(string); ok {
-			return t
-		}
-	case "last_edited_time":
-		if t, ok := prop["last_edited_time"].(string); ok {
-			return t
-		}
-	case "created_by", "last_edited_by":
-		if u, ok := prop[propType].(map[string]interface{}); ok {
-			name, _ := u["name"].(string)
-			return name
-		}
-	}
-	return ""
+	return notion.PropertyText(prop)
 }
 
 func extractPlainTextFromRichText(arr []interface{}) string {
-	var parts []string
-	for _, t := range arr {
-		if m, ok := t.(map[string]interface{}); ok {
-			if pt, ok := m["plain_text"].(string); ok {
-				parts = append(parts, pt)
-			}
-		}
-	}
-	return strings.Join(parts, "")
+	return notion.PlainText(arr)
 }
 
 // renderBlock renders a single Notion block to stdout.
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// backlink is one place a page is referenced from.
+type backlink struct {
+	SourceID    string `json:"source_id"`
+	SourceTitle string `json:"source_title"`
+	BlockID     string `json:"block_id,omitempty"`
+	Kind        string `json:"kind"`
+	Property    string `json:"property,omitempty"`
+}
+
+var pageBacklinksCmd = &cobra.Command{
+	Use:   "backlinks <page-id|url>",
+	Short: "Find pages that link to a page",
+	Long: `List every place the workspace references a page.
+
+Every page shared with the integration is scanned (blocks are fetched
+concurrently) for:
+  - link_to_page blocks pointing at the page
+  - @-mentions of the page in text
+  - inline links to the page's URL
+  - relation properties containing the page
+
+Notion has no backlinks API, so this reads every shared page; use
+--limit to bound the scan on large workspaces.
+
+Examples:
+  notion page backlinks abc123
+  notion page backlinks abc123 --limit 200 --workers 5
+  notion page backlinks abc123 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		targetID := util.ResolveID(args[0])
+		limit, _ := cmd.Flags().GetInt("limit")
+		workers, _ := cmd.Flags().GetInt("workers")
+
+		c := newClient(token)
+
+		pages, err := searchAllPages(c, limit)
+		if err != nil {
+			return err
+		}
+
+		titles := map[string]string{}
+		var links []backlink
+		var ids []string
+		for _, p := range pages {
+			id, _ := p["id"].(string)
+			if id == "" || sameID(id, targetID) {
+				continue
+			}
+			titles[id] = render.ExtractTitle(p)
+			ids = append(ids, id)
+			links = append(links, relationBacklinks(p, targetID)...)
+		}
+
+		scanned := 0
+		fetchPageTrees(c, ids, workers, func(tree pageTree) {
+			scanned++
+			if outputFormat != "json" {
+				fmt.Fprintf(os.Stderr, "\r  scanned %d/%d pages", scanned, len(ids))
+			}
+			if tree.Err != nil {
+				fmt.Fprintf(os.Stderr, "\nnote: %s: %s\n", tree.PageID, firstLine(tree.Err))
+			}
+			for _, b := range tree.Blocks {
+				for _, kind := range blockReferences(b, targetID) {
+					blockID, _ := b["id"].(string)
+					links = append(links, backlink{
+						SourceID:    tree.PageID,
+						SourceTitle: titles[tree.PageID],
+						BlockID:     blockID,
+						Kind:        kind,
+					})
+				}
+			}
+		})
+		if outputFormat != "json" && len(ids) > 0 {
+			fmt.Fprintln(os.Stderr)
+		}
+
+		if outputFormat == "json" {
+			if links == nil {
+				links = []backlink{}
+			}
+			return render.JSON(map[string]interface{}{"target": targetID, "scanned": len(ids), "backlinks": links})
+		}
+		if len(links) == 0 {
+			fmt.Printf("No backlinks found in %d page(s).\n", len(ids))
+			return nil
+		}
+		var rows [][]string
+		for _, l := range links {
+			where := l.BlockID
+			if l.Property != "" {
+				where = "property: " + l.Property
+			}
+			rows = append(rows, []string{l.SourceTitle, l.SourceID, l.Kind, where})
+		}
+		render.Table([]string{"SOURCE", "SOURCE ID", "KIND", "BLOCK"}, rows)
+		return nil
+	},
+}
+
+func init() {
+	pageBacklinksCmd.Flags().Int("limit", 0, "Scan at most this many pages (0 = all shared pages)")
+	pageBacklinksCmd.Flags().Int("workers", defaultFetchWorkers, "Pages fetched concurrently")
+	pageCmd.AddCommand(pageBacklinksCmd)
+}
+
+// searchAllPages returns every page shared with the integration, up to
+// limit (0 = no limit).
+func searchAllPages(c *notion.Client, limit int) ([]map[string]interface{}, error) {
+	var pages []map[string]interface{}
+	cursor := ""
+	for {
+		result, err := c.Search("", "page", 100, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("search: %w", err)
+		}
+		results, _ := result["results"].([]interface{})
+		for _, r := range results {
+			if obj, ok := r.(map[string]interface{}); ok {
+				pages = append(pages, obj)
+				if limit > 0 && len(pages) >= limit {
+					return pages, nil
+				}
+			}
+		}
+		hasMore, _ := result["has_more"].(bool)
+		nextCursor, _ := result["next_cursor"].(string)
+		if !hasMore || nextCursor == "" {
+			return pages, nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// blockReferences returns how block refers to targetID: "link_to_page",
+// "mention" and/or "link", once per occurrence.
+func blockReferences(block map[string]interface{}, targetID string) []string {
+	var kinds []string
+	blockType, _ := block["type"].(string)
+	data, _ := block[blockType].(map[string]interface{})
+
+	if blockType == "link_to_page" {
+		pageID, _ := data["page_id"].(string)
+		if pageID == "" {
+			pageID, _ = data["database_id"].(string)
+		}
+		if sameID(pageID, targetID) {
+			kinds = append(kinds, "link_to_page")
+		}
+	}
+
+	richText, _ := data["rich_text"].([]interface{})
+	if caption, ok := data["caption"].([]interface{}); ok {
+		richText = append(richText, caption...)
+	}
+	for _, rt := range richText {
+		kinds = append(kinds, richTextReferences(rt, targetID)...)
+	}
+	return kinds
+}
+
+func richTextReferences(rt interface{}, targetID string) []string {
+	item, ok := rt.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if mention, ok := item["mention"].(map[string]interface{}); ok {
+		for _, key := range []string{"page", "database"} {
+			if ref, ok := mention[key].(map[string]interface{}); ok {
+				if id, _ := ref["id"].(string); sameID(id, targetID) {
+					return []string{"mention"}
+				}
+			}
+		}
+		return nil
+	}
+	href, _ := item["href"].(string)
+	if href != "" && strings.Contains(strings.ReplaceAll(href, "-", ""), strings.ReplaceAll(targetID, "-", "")) {
+		return []string{"link"}
+	}
+	return nil
+}
+
+// relationBacklinks reports relation properties of page that include
+// targetID.
+func relationBacklinks(page map[string]interface{}, targetID string) []backlink {
+	props, _ := page["properties"].(map[string]interface{})
+	pageID, _ := page["id"].(string)
+	var links []backlink
+	for _, name := range sortedKeys(props) {
+		prop, _ := props[name].(map[string]interface{})
+		if t, _ := prop["type"].(string); t != "relation" {
+			continue
+		}
+		rels, _ := prop["relation"].([]interface{})
+		for _, r := range rels {
+			rel, _ := r.(map[string]interface{})
+			if id, _ := rel["id"].(string); sameID(id, targetID) {
+				links = append(links, backlink{
+					SourceID:    pageID,
+					SourceTitle: render.ExtractTitle(page),
+					Kind:        "relation",
+					Property:    name,
+				})
+			}
+		}
+	}
+	return links
+}
+
+// sameID compares two Notion IDs regardless of dashes.
+func sameID(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.ReplaceAll(a, "-", "") == strings.ReplaceAll(b, "-", "")
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+const backlinkTarget = "11111111-1111-1111-1111-111111111111"
+
+func TestBlockReferences(t *testing.T) {
+	tests := []struct {
+		name  string
+		block map[string]interface{}
+		want  []string
+	}{
+		{
+			name: "link_to_page",
+			block: map[string]interface{}{"type": "link_to_page", "link_to_page": map[string]interface{}{
+				"type": "page_id", "page_id": strings.ReplaceAll(backlinkTarget, "-", ""),
+			}},
+			want: []string{"link_to_page"},
+		},
+		{
+			name: "mention and href",
+			block: map[string]interface{}{"type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{
+				map[string]interface{}{"type": "mention", "mention": map[string]interface{}{"type": "page", "page": map[string]interface{}{"id": backlinkTarget}}},
+				map[string]interface{}{"type": "text", "href": "https://www.notion.so/Target-11111111111111111111111111111111"},
+				map[string]interface{}{"type": "text", "href": "https://example.com"},
+			}}},
+			want: []string{"mention", "link"},
+		},
+		{
+			name:  "unrelated",
+			block: map[string]interface{}{"type": "divider", "divider": map[string]interface{}{}},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blockReferences(tt.block, backlinkTarget); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("blockReferences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelationBacklinks(t *testing.T) {
+	page := map[string]interface{}{
+		"id": "row-1",
+		"properties": map[string]interface{}{
+			"Project": map[string]interface{}{"type": "relation", "relation": []interface{}{
+				map[string]interface{}{"id": backlinkTarget},
+			}},
+			"Status": map[string]interface{}{"type": "select"},
+		},
+	}
+	links := relationBacklinks(page, backlinkTarget)
+	if len(links) != 1 || links[0].Property != "Project" || links[0].Kind != "relation" {
+		t.Errorf("relationBacklinks = %+v", links)
+	}
+}
+
+func TestFetchPageTreesFlattensNestedBlocks(t *testing.T) {
+	children := map[string][]interface{}{
+		"page-a": {
+			map[string]interface{}{"id": "a1", "type": "toggle", "has_children": true},
+			map[string]interface{}{"id": "a2", "type": "child_page", "has_children": true},
+		},
+		"a1":     {map[string]interface{}{"id": "a1-1", "type": "paragraph"}},
+		"page-b": {map[string]interface{}{"id": "b1", "type": "paragraph"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": children[id]})
+	}))
+	defer server.Close()
+
+	got := map[string][]string{}
+	fetchPageTrees(notion.New("tok", notion.WithBaseURL(server.URL)), []string{"page-a", "page-b"}, 2, func(tree pageTree) {
+		if tree.Err != nil {
+			t.Errorf("%s: %v", tree.PageID, tree.Err)
+		}
+		for _, b := range tree.Blocks {
+			got[tree.PageID] = append(got[tree.PageID], b["id"].(string))
+		}
+	})
+
+	if !reflect.DeepEqual(got["page-a"], []string{"a1", "a1-1", "a2"}) {
+		t.Errorf("page-a blocks = %v", got["page-a"])
+	}
+	keys := []string{}
+	for k := range got {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"page-a", "page-b"}) {
+		t.Errorf("pages = %v", keys)
+	}
+}
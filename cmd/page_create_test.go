@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPageCreateFromFile(t *testing.T) {
+	const parentID = "11111111-1111-1111-1111-111111111111"
+	var md strings.Builder
+	md.WriteString("\n# Launch plan\n\n")
+	for i := 1; i <= 150; i++ {
+		fmt.Fprintf(&md, "Paragraph %d\n\n", i)
+	}
+	file := filepath.Join(t.TempDir(), "plan.md")
+	if err := os.WriteFile(file, []byte(md.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var created map[string]interface{}
+	var appended []int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]interface{}
+		json.Unmarshal(body, &req)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/databases/"+parentID:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not a database"}`))
+		case r.Method == "GET" && r.URL.Path == "/v1/pages/"+parentID:
+			w.Write([]byte(`{"object":"page","id":"` + parentID + `"}`))
+		case r.Method == "POST" && r.URL.Path == "/v1/pages":
+			created = req
+			w.Write([]byte(`{"object":"page","id":"new-page","url":"https://www.notion.so/new-page"}`))
+		case r.Method == "PATCH" && r.URL.Path == "/v1/blocks/new-page/children":
+			children, _ := req["children"].([]interface{})
+			appended = append(appended, len(children))
+			w.Write([]byte(`{"results":[]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "create", parentID, "--file", file); err != nil {
+			t.Fatalf("page create --file: %v", err)
+		}
+	})
+	props, _ := created["properties"].(map[string]interface{})
+	title, _ := json.Marshal(props["title"])
+	if !strings.Contains(string(title), `"content":"Launch plan"`) {
+		t.Errorf("title = %s", title)
+	}
+	children, _ := created["children"].([]interface{})
+	if len(children) != 100 {
+		t.Fatalf("created with %d children, want 100", len(children))
+	}
+	if first, _ := json.Marshal(children[0]); !strings.Contains(string(first), "Paragraph 1") {
+		t.Errorf("first block = %s; the title heading should be dropped", first)
+	}
+	if len(appended) != 1 || appended[0] != 50 {
+		t.Errorf("appended batches = %v, want [50]", appended)
+	}
+
+	if _, _, err := executeCommand("page", "create", parentID, "--file", file, "--body", "x"); err == nil {
+		t.Error("--file with --body should fail")
+	}
+	// Flags persist between executeCommand calls.
+	executeCommand("page", "create", parentID, "--file", "", "--body", "")
+}
+
+func TestReadPageFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "Meeting notes.md")
+	os.WriteFile(file, []byte("Agenda\n"), 0644)
+	if _, title, err := readPageFile(file, ""); err != nil || title != "Meeting notes" {
+		t.Errorf("title = %q, %v; want the file name", title, err)
+	}
+	if md, title, _ := readPageFile(file, "Given"); title != "Given" || md != "Agenda\n" {
+		t.Errorf("explicit title: %q %q", title, md)
+	}
+}
+
+func TestPageParentIsDetected(t *testing.T) {
+	const (
+		pageID = "11111111-1111-1111-1111-111111111111"
+		dbID   = "22222222-2222-2222-2222-222222222222"
+	)
+	var parents []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/v1/databases/"+dbID:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "id": dbID, "properties": map[string]interface{}{
+				"Page":   map[string]interface{}{"type": "title"},
+				"Status": map[string]interface{}{"type": "select"},
+			}})
+		case r.URL.Path == "/v1/pages/"+pageID:
+			w.Write([]byte(`{"object":"page","id":"` + pageID + `"}`))
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not found"}`))
+		default:
+			var req map[string]interface{}
+			json.Unmarshal(body, &req)
+			parent, _ := json.Marshal(req["parent"])
+			props, _ := json.Marshal(req["properties"])
+			parents = append(parents, string(parent)+" "+string(props))
+			w.Write([]byte(`{"object":"page","id":"new-page"}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	captureStdout(t, func() {
+		for _, args := range [][]string{
+			{"page", "create", dbID, "--title", "Onboarding", "Status=Draft"},
+			{"page", "create", pageID, "--title", "Notes"},
+			{"page", "create", "workspace", "--title", "Team Home"},
+		} {
+			if _, _, err := executeCommand(args...); err != nil {
+				t.Fatalf("%v: %v", args, err)
+			}
+		}
+	})
+	want := []string{
+		`{"database_id":"` + dbID + `","type":"database_id"} {"Page":{"title":[{"text":{"content":"Onboarding"}}]},"Status":{"select":{"name":"Draft"}}}`,
+		`{"page_id":"` + pageID + `","type":"page_id"} {"title":{"title":[{"text":{"content":"Notes"}}]}}`,
+		`{"type":"workspace","workspace":true} {"title":{"title":[{"text":{"content":"Team Home"}}]}}`,
+	}
+	if strings.Join(parents, "\n") != strings.Join(want, "\n") {
+		t.Errorf("created:\n%s\nwant:\n%s", strings.Join(parents, "\n"), strings.Join(want, "\n"))
+	}
+
+	moves := len(parents)
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "move", pageID, "--to", dbID); err != nil {
+			t.Fatalf("page move: %v", err)
+		}
+	})
+	if len(parents) != moves+1 || !strings.HasPrefix(parents[moves], `{"database_id":"`+dbID) || !strings.Contains(out, "moved to database") {
+		t.Errorf("move: %v\n%s", parents[moves:], out)
+	}
+	if _, _, err := executeCommand("page", "move", pageID, "--to", "33333333-3333-3333-3333-333333333333"); err == nil || !strings.Contains(err.Error(), "neither a page nor a database") {
+		t.Errorf("move to a missing parent: err = %v", err)
+	}
+	if _, _, err := executeCommand("page", "create", pageID, "--title", "Notes", "Status=Draft"); err == nil {
+		t.Error("properties under a page parent should fail")
+	}
+	// Flags persist between executeCommand calls.
+	executeCommand("page", "create", pageID, "--title", "")
+}
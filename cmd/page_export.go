@@ -0,0 +1,929 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// exportNameRe matches what can't go in a file name on any platform.
+	exportNameRe = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]+`)
+	// notionHrefIDRe matches the page ID at the end of a Notion URL path.
+	notionHrefIDRe = regexp.MustCompile(`([0-9a-f]{32})$`)
+)
+
+var pageExportCmd = &cobra.Command{
+	Use:   "export <page-id|url>",
+	Short: "Export a page and its sub-pages as linked markdown or HTML files",
+	Long: `Export a page and every page nested under it to a directory, one file
+per page. A page's sub-pages go in a directory named after it, the layout
+'notion import markdown-dir' reads back:
+
+  Handbook.md
+  Handbook/Onboarding.md
+  Handbook/Onboarding/Accounts.md
+
+Links between exported pages keep working offline: sub-pages, link-to-page
+blocks, @-mentions and notion.so URLs of exported pages become relative
+paths, and links to a block become an anchor on the heading of the section
+the block is in. Links to pages outside the export still go to Notion.
+
+Each export leaves a manifest, .notion-export.json, in the directory,
+with a SHA-256 of every file it wrote. --diff-only renders the export
+without writing anything and lists the files that would be added or
+modified, and those of pages no longer in the tree: a quick drift check
+of a backup.
+With --since, pages not edited since then whose files the last export
+wrote are skipped: their content isn't even downloaded. --since takes a
+duration (24h, 7d), a date, or last-run for the start of the previous
+export into the directory. Pages that moved or whose links point to moved
+pages are only brought up to date by a full export.
+
+Examples:
+  notion page export abc123 --dir ./handbook
+  notion page export abc123 --dir ./site --html
+  notion page export abc123 --dir ./backup --since last-run
+  notion page export abc123 --dir ./backup --diff-only`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		dir, _ := cmd.Flags().GetString("dir")
+		asHTML, _ := cmd.Flags().GetBool("html")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		diffOnly, _ := cmd.Flags().GetBool("diff-only")
+		c := newClient(token)
+		pageID := util.ResolveID(args[0])
+
+		start := exportRunStart(time.Now())
+		previous := loadExportManifest(dir, pageID)
+		since, err := exportSince(sinceFlag, previous.LastRun, time.Now())
+		if err != nil {
+			return usageError(err)
+		}
+
+		page, err := c.GetPage(pageID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		e := newPageExporter(asHTML)
+		if !since.IsZero() {
+			e.reuse(dir, previous, since)
+		}
+		edited, _ := page["last_edited_time"].(string)
+		if err := e.collect(c, pageID, render.ExtractTitle(page), edited); err != nil {
+			return err
+		}
+		if diffOnly {
+			return e.reportDiff(dir, previous)
+		}
+		written := 0
+		for _, p := range e.pages {
+			if p.Unchanged {
+				continue
+			}
+			written++
+			full := filepath.Join(dir, filepath.FromSlash(p.Path))
+			if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+				return fmt.Errorf("create directory: %w", err)
+			}
+			data := e.render(p)
+			if err := os.WriteFile(full, data, 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", full, err)
+			}
+			p.sum = contentSum(data)
+		}
+		if err := e.saveManifest(dir, pageID, start); err != nil {
+			return err
+		}
+		if e.external > 0 {
+			fmt.Fprintf(os.Stderr, "note: %d link(s) point to pages outside the export and still open Notion\n", e.external)
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{
+				"dir":            dir,
+				"pages":          e.pages,
+				"written":        written,
+				"unchanged":      len(e.pages) - written,
+				"links":          e.rewritten,
+				"external_links": e.external,
+			})
+		}
+		if unchanged := len(e.pages) - written; unchanged > 0 {
+			fmt.Printf("✓ Exported %d page(s) to %s, %d unchanged (%d internal link(s))\n", written, dir, unchanged, e.rewritten)
+			return nil
+		}
+		fmt.Printf("✓ Exported %d page(s) to %s (%d internal link(s))\n", written, dir, e.rewritten)
+		return nil
+	},
+}
+
+func init() {
+	pageExportCmd.Flags().String("dir", ".", "Directory to export into")
+	pageExportCmd.Flags().Bool("html", false, "Write HTML files instead of markdown")
+	pageExportCmd.Flags().Bool("diff-only", false, "List the files the export would change, without writing anything")
+	pageExportCmd.Flags().String("since", "", "Skip pages not edited since this time (24h, 7d, 2026-03-01) or last-run")
+	pageCmd.AddCommand(pageExportCmd)
+}
+
+// exportPage is one page of an exported tree.
+type exportPage struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Path is where the page is written, relative to the export directory,
+	// with forward slashes.
+	Path string `json:"path"`
+	// Unchanged is set on pages --since skipped: their file is the one
+	// the last export wrote.
+	Unchanged bool `json:"unchanged,omitempty"`
+
+	edited   string                   // last_edited_time
+	sum      string                   // SHA-256 of the file, hex
+	children []exportChild            // sub-pages, in order
+	blocks   []map[string]interface{} // nested under "_children"
+	headings []*tocEntry              // ID is the heading's anchor
+	anchors  map[string]string        // plain block ID → anchor of its section
+}
+
+// exportChild is a sub-page as its parent's child_page block shows it.
+type exportChild struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Edited string `json:"last_edited_time,omitempty"`
+}
+
+// pageExporter renders a page tree with the links between its pages
+// rewritten to relative paths.
+type pageExporter struct {
+	html      bool
+	pages     []*exportPage
+	byID      map[string]*exportPage // plain page ID
+	byBlock   map[string]*exportPage // plain block ID → page holding it
+	names     map[string]bool        // lower-cased paths already taken
+	rewritten int
+	external  int
+
+	// previous holds the pages of the last export, by plain ID, that
+	// collect may reuse when they weren't edited since since.
+	previous map[string]manifestPage
+	since    time.Time
+	dir      string
+}
+
+func newPageExporter(asHTML bool) *pageExporter {
+	return &pageExporter{
+		html:    asHTML,
+		byID:    map[string]*exportPage{},
+		byBlock: map[string]*exportPage{},
+		names:   map[string]bool{},
+	}
+}
+
+func (e *pageExporter) ext() string {
+	if e.html {
+		return ".html"
+	}
+	return ".md"
+}
+
+// exportManifestName is the file in an export directory describing the
+// last export into it.
+const exportManifestName = ".notion-export.json"
+
+// exportManifest records an export: what was exported when, and enough of
+// each page to skip it next time when it hasn't changed.
+type exportManifest struct {
+	Root    string         `json:"root"`
+	LastRun time.Time      `json:"last_run"`
+	Pages   []manifestPage `json:"pages"`
+}
+
+type manifestPage struct {
+	ID       string            `json:"id"`
+	Title    string            `json:"title"`
+	Path     string            `json:"path"`
+	Edited   string            `json:"last_edited_time,omitempty"`
+	SHA256   string            `json:"sha256,omitempty"`
+	Children []exportChild     `json:"children,omitempty"`
+	Anchors  map[string]string `json:"anchors,omitempty"`
+}
+
+// loadExportManifest reads the manifest of the last export of rootID into
+// dir; it is empty when there is none, or it was for another page.
+func loadExportManifest(dir, rootID string) exportManifest {
+	var m exportManifest
+	data, err := os.ReadFile(filepath.Join(dir, exportManifestName))
+	if err != nil || json.Unmarshal(data, &m) != nil || plainID(m.Root) != plainID(rootID) {
+		return exportManifest{}
+	}
+	return m
+}
+
+// reuse lets collect skip the pages of the previous export in dir that
+// weren't edited since since.
+func (e *pageExporter) reuse(dir string, previous exportManifest, since time.Time) {
+	e.dir, e.since = dir, since
+	e.previous = map[string]manifestPage{}
+	for _, p := range previous.Pages {
+		e.previous[plainID(p.ID)] = p
+	}
+}
+
+// unchanged returns the previous export of p when it can stand: p wasn't
+// edited since --since, and its file is still where it was written.
+func (e *pageExporter) unchanged(p *exportPage) (manifestPage, bool) {
+	prev, ok := e.previous[plainID(p.ID)]
+	if !ok || prev.Path != p.Path || p.edited == "" {
+		return manifestPage{}, false
+	}
+	edited, err := time.Parse(time.RFC3339, p.edited)
+	if err != nil || !edited.Before(e.since) {
+		return manifestPage{}, false
+	}
+	if _, err := os.Stat(filepath.Join(e.dir, filepath.FromSlash(p.Path))); err != nil {
+		return manifestPage{}, false
+	}
+	return prev, true
+}
+
+// saveManifest writes the manifest of this export, started at start, into
+// dir.
+func (e *pageExporter) saveManifest(dir, rootID string, start time.Time) error {
+	m := exportManifest{Root: rootID, LastRun: start}
+	for _, p := range e.pages {
+		m.Pages = append(m.Pages, manifestPage{ID: p.ID, Title: p.Title, Path: p.Path, Edited: p.edited, SHA256: p.sum, Children: p.children, Anchors: p.anchors})
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, exportManifestName), data, 0o644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// contentSum is the hex SHA-256 of an exported file.
+func contentSum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// exportChange is a file 'page export --diff-only' found would change.
+type exportChange struct {
+	Status string `json:"status"` // added, modified or removed
+	Path   string `json:"path"`
+	Title  string `json:"title"`
+	ID     string `json:"id"`
+}
+
+// reportDiff compares the collected pages, rendered, with the files in
+// dir and prints those that differ, plus the files of the previous export
+// whose pages are gone. Nothing is written.
+func (e *pageExporter) reportDiff(dir string, previous exportManifest) error {
+	var changes []exportChange
+	current := map[string]bool{}
+	unchanged := 0
+	for _, p := range e.pages {
+		current[p.Path] = true
+		if p.Unchanged {
+			unchanged++
+			continue
+		}
+		sum := contentSum(e.render(p))
+		existing, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(p.Path)))
+		switch {
+		case err != nil:
+			changes = append(changes, exportChange{"added", p.Path, p.Title, p.ID})
+		case contentSum(existing) != sum:
+			changes = append(changes, exportChange{"modified", p.Path, p.Title, p.ID})
+		default:
+			unchanged++
+		}
+	}
+	for _, p := range previous.Pages {
+		if current[p.Path] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(p.Path))); err == nil {
+			changes = append(changes, exportChange{"removed", p.Path, p.Title, p.ID})
+		}
+	}
+
+	if outputFormat == "json" {
+		if changes == nil {
+			changes = []exportChange{}
+		}
+		return render.JSON(map[string]interface{}{
+			"dir":       dir,
+			"changes":   changes,
+			"unchanged": unchanged,
+		})
+	}
+	if len(changes) == 0 {
+		fmt.Printf("✓ %s is up to date (%d page(s))\n", dir, len(e.pages))
+		return nil
+	}
+	var rows [][]string
+	for _, ch := range changes {
+		rows = append(rows, []string{ch.Status, ch.Path, ch.Title})
+	}
+	render.Table([]string{"STATUS", "PATH", "TITLE"}, rows)
+	fmt.Printf("\n%d file(s) would change, %d unchanged\n", len(changes), unchanged)
+	return nil
+}
+
+// collect fetches the page rootID, last edited at edited, and every page
+// under it, breadth first, and assigns each its path.
+func (e *pageExporter) collect(c *notion.Client, rootID, title, edited string) error {
+	type job struct{ id, title, dir, edited string }
+	queue := []job{{rootID, title, "", edited}}
+	for len(queue) > 0 {
+		j := queue[0]
+		queue = queue[1:]
+		p := &exportPage{ID: j.id, Title: firstNonEmpty(j.title, "Untitled"), edited: j.edited}
+		p.Path = e.uniquePath(j.dir, exportFileName(p.Title))
+		if prev, ok := e.unchanged(p); ok {
+			p.Unchanged = true
+			p.sum = prev.SHA256
+			p.children = prev.Children
+			e.addPage(p, prev.Anchors)
+		} else {
+			blocks, err := fetchExportBlocks(c, j.id)
+			if err != nil {
+				return fmt.Errorf("get content of %s: %w", firstNonEmpty(j.title, j.id), err)
+			}
+			p.blocks = blocks
+			e.addPage(p, nil)
+		}
+
+		childDir := strings.TrimSuffix(p.Path, e.ext()) + "/"
+		for _, child := range p.children {
+			queue = append(queue, job{child.ID, child.Title, childDir, child.Edited})
+		}
+	}
+	return nil
+}
+
+// addPage indexes p's blocks, gives each heading an anchor and lists its
+// sub-pages. A page reused from the last export has no blocks, just the
+// anchors recorded then.
+func (e *pageExporter) addPage(p *exportPage, anchors map[string]string) {
+	e.pages = append(e.pages, p)
+	e.byID[plainID(p.ID)] = p
+	if anchors != nil {
+		p.anchors = anchors
+		for id := range anchors {
+			e.byBlock[id] = p
+		}
+		return
+	}
+	p.anchors = map[string]string{}
+
+	flat := flattenExportBlocks(p.blocks)
+	seen := map[string]int{}
+	section := ""
+	for _, block := range flat {
+		id, _ := block["id"].(string)
+		blockType, _ := block["type"].(string)
+		if blockType == "child_page" {
+			data, _ := block["child_page"].(map[string]interface{})
+			childTitle, _ := data["title"].(string)
+			childEdited, _ := block["last_edited_time"].(string)
+			p.children = append(p.children, exportChild{ID: id, Title: childTitle, Edited: childEdited})
+		}
+		if level := headingLevel(blockType); level > 0 {
+			entry := tocEntries([]map[string]interface{}{block})[0]
+			section = uniqueAnchor(headingAnchor(entry.Text), seen)
+			entry.ID = section
+			p.headings = append(p.headings, entry)
+		}
+		p.anchors[plainID(id)] = section
+		e.byBlock[plainID(id)] = p
+	}
+}
+
+// uniquePath returns dir+name+ext, numbering the name when another page
+// already has that path (ignoring case, for case-insensitive file systems).
+func (e *pageExporter) uniquePath(dir, name string) string {
+	candidate := dir + name + e.ext()
+	for n := 2; e.names[strings.ToLower(candidate)]; n++ {
+		candidate = dir + name + " " + strconv.Itoa(n) + e.ext()
+	}
+	e.names[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// link returns the relative link from page from to the Notion object id,
+// with fragment naming a block in it, or "" when id wasn't exported.
+func (e *pageExporter) link(from *exportPage, id, fragment string) string {
+	key := plainID(id)
+	target, anchor := e.byID[key], ""
+	if target == nil {
+		if target = e.byBlock[key]; target == nil {
+			return ""
+		}
+		anchor = target.anchors[key]
+	}
+	if a, ok := target.anchors[plainID(fragment)]; ok && fragment != "" {
+		anchor = a
+	}
+	href := ""
+	if target != from || anchor == "" {
+		href = relativeExportPath(from.Path, target.Path)
+	}
+	if anchor != "" {
+		href += "#" + anchor
+	}
+	return href
+}
+
+// resolveHref rewrites a link to an exported Notion page or block into a
+// relative path and leaves any other link alone.
+func (e *pageExporter) resolveHref(from *exportPage, href string) string {
+	id, fragment, ok := notionHrefTarget(href)
+	if !ok {
+		return href
+	}
+	if link := e.link(from, id, fragment); link != "" {
+		e.rewritten++
+		return link
+	}
+	e.external++
+	return href
+}
+
+// linkTo returns the link to the page id for a link_to_page block or a
+// mention, falling back to its Notion URL when it wasn't exported.
+func (e *pageExporter) linkTo(from *exportPage, id string) string {
+	if link := e.link(from, id, ""); link != "" {
+		e.rewritten++
+		return link
+	}
+	e.external++
+	return "https://www.notion.so/" + plainID(id)
+}
+
+// notionHrefTarget returns the page ID and block fragment a Notion URL
+// points to. Links inside Notion content are often host-relative
+// ("/1a2b...#3c4d...").
+func notionHrefTarget(href string) (id, fragment string, ok bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", "", false
+	}
+	if u.Host == "" {
+		if u.Scheme != "" || !strings.HasPrefix(u.Path, "/") {
+			return "", "", false
+		}
+	} else if !notionWebHosts[u.Host] && !strings.HasSuffix(u.Host, ".notion.site") {
+		return "", "", false
+	}
+	m := notionHrefIDRe.FindString(strings.ReplaceAll(path.Base(u.Path), "-", ""))
+	if m == "" {
+		return "", "", false
+	}
+	return util.ResolveID(m), u.Fragment, true
+}
+
+// relativeExportPath is the URL-escaped path to file to from the
+// directory of file from.
+func relativeExportPath(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		rel = to
+	}
+	return (&url.URL{Path: filepath.ToSlash(rel)}).EscapedPath()
+}
+
+// exportFileName turns a page title into a file name.
+func exportFileName(title string) string {
+	name := strings.Trim(strings.Join(strings.Fields(exportNameRe.ReplaceAllString(title, " ")), " "), ".")
+	if r := []rune(name); len(r) > 100 {
+		name = strings.TrimSpace(string(r[:100]))
+	}
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// headingAnchor is the anchor GitHub and most markdown renderers give a
+// heading: lower case, punctuation dropped, spaces as dashes.
+func headingAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "section"
+	}
+	return b.String()
+}
+
+// uniqueAnchor numbers a repeated anchor the way renderers do: "setup",
+// "setup-1", "setup-2".
+func uniqueAnchor(anchor string, seen map[string]int) string {
+	n := seen[anchor]
+	seen[anchor]++
+	if n == 0 {
+		return anchor
+	}
+	return anchor + "-" + strconv.Itoa(n)
+}
+
+// fetchExportBlocks returns the blocks of a page with their children
+// nested under "_children". Child pages and databases are not descended
+// into.
+func fetchExportBlocks(c *notion.Client, parentID string) ([]map[string]interface{}, error) {
+	children, err := fetchBlockChildren(c, parentID, "", true)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []map[string]interface{}
+	for _, ch := range children {
+		block, ok := ch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		blockType, _ := block["type"].(string)
+		hasChildren, _ := block["has_children"].(bool)
+		if hasChildren && blockType != "child_page" && blockType != "child_database" {
+			id, _ := block["id"].(string)
+			nested, err := fetchExportBlocks(c, id)
+			if err != nil {
+				return nil, err
+			}
+			block["_children"] = nested
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// flattenExportBlocks lists a nested block tree depth first.
+func flattenExportBlocks(blocks []map[string]interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, block := range blocks {
+		out = append(out, block)
+		out = append(out, flattenExportBlocks(exportChildren(block))...)
+	}
+	return out
+}
+
+func exportChildren(block map[string]interface{}) []map[string]interface{} {
+	children, _ := block["_children"].([]map[string]interface{})
+	return children
+}
+
+// render returns the file contents of page p.
+func (e *pageExporter) render(p *exportPage) []byte {
+	var buf bytes.Buffer
+	if e.html {
+		fmt.Fprintf(&buf, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n<h1>%s</h1>\n",
+			html.EscapeString(p.Title), html.EscapeString(p.Title))
+		e.writeHTML(&buf, p, p.blocks)
+		buf.WriteString("</body>\n</html>\n")
+	} else {
+		fmt.Fprintf(&buf, "# %s\n\n", p.Title)
+		e.writeMarkdown(&buf, p, p.blocks, 0)
+	}
+	return buf.Bytes()
+}
+
+// richText renders rich text with links to exported pages rewritten.
+func (e *pageExporter) richText(p *exportPage, rt []interface{}) string {
+	var sb strings.Builder
+	for _, item := range rt {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		text, _ := m["plain_text"].(string)
+		href, _ := m["href"].(string)
+		mentioned := ""
+		if mention, ok := m["mention"].(map[string]interface{}); ok {
+			for _, kind := range []string{"page", "database"} {
+				ref, _ := mention[kind].(map[string]interface{})
+				if id, _ := ref["id"].(string); id != "" {
+					mentioned = id
+				}
+			}
+		}
+		switch {
+		case mentioned != "":
+			href = e.linkTo(p, mentioned)
+		case href != "":
+			href = e.resolveHref(p, href)
+		}
+
+		ann, _ := m["annotations"].(map[string]interface{})
+		bold, _ := ann["bold"].(bool)
+		italic, _ := ann["italic"].(bool)
+		code, _ := ann["code"].(bool)
+		strike, _ := ann["strikethrough"].(bool)
+		if e.html {
+			text = html.EscapeString(text)
+			if code {
+				text = "<code>" + text + "</code>"
+			}
+			if strike {
+				text = "<s>" + text + "</s>"
+			}
+			if bold {
+				text = "<strong>" + text + "</strong>"
+			}
+			if italic {
+				text = "<em>" + text + "</em>"
+			}
+			if href != "" {
+				text = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(href), text)
+			}
+		} else {
+			if code {
+				text = "`" + text + "`"
+			}
+			if strike {
+				text = "~~" + text + "~~"
+			}
+			if bold {
+				text = "**" + text + "**"
+			}
+			if italic {
+				text = "*" + text + "*"
+			}
+			if href != "" {
+				text = fmt.Sprintf("[%s](%s)", text, href)
+			}
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// exportBlockLink returns the title and link of a child_page or
+// link_to_page block.
+func (e *pageExporter) exportBlockLink(p *exportPage, block map[string]interface{}) (string, string) {
+	blockType, _ := block["type"].(string)
+	data, _ := block[blockType].(map[string]interface{})
+	if blockType == "child_page" {
+		id, _ := block["id"].(string)
+		title, _ := data["title"].(string)
+		return firstNonEmpty(title, "Untitled"), e.link(p, id, "")
+	}
+	id, _ := data["page_id"].(string)
+	if id == "" {
+		id, _ = data["database_id"].(string)
+	}
+	title := "Linked page"
+	if target := e.byID[plainID(id)]; target != nil {
+		title = target.Title
+	}
+	return title, e.linkTo(p, id)
+}
+
+// exportMedia returns the URL and caption of an image, file, bookmark or
+// embed block.
+func exportMedia(data map[string]interface{}) (string, []interface{}) {
+	link, _ := data["url"].(string)
+	for _, kind := range []string{"file", "external"} {
+		if f, ok := data[kind].(map[string]interface{}); ok {
+			link, _ = f["url"].(string)
+		}
+	}
+	caption, _ := data["caption"].([]interface{})
+	return link, caption
+}
+
+func (e *pageExporter) writeMarkdown(buf *bytes.Buffer, p *exportPage, blocks []map[string]interface{}, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	for _, block := range blocks {
+		blockType, _ := block["type"].(string)
+		data, _ := block[blockType].(map[string]interface{})
+		rt, _ := data["rich_text"].([]interface{})
+		text := e.richText(p, rt)
+		childIndent := indent + 1
+
+		switch blockType {
+		case "paragraph":
+			if text != "" {
+				fmt.Fprintf(buf, "%s%s\n\n", prefix, text)
+			}
+		case "heading_1", "heading_2", "heading_3":
+			fmt.Fprintf(buf, "%s%s %s\n\n", prefix, strings.Repeat("#", headingLevel(blockType)), text)
+			childIndent = indent
+		case "bulleted_list_item", "toggle":
+			fmt.Fprintf(buf, "%s- %s\n", prefix, text)
+		case "numbered_list_item":
+			fmt.Fprintf(buf, "%s1. %s\n", prefix, text)
+		case "to_do":
+			mark := " "
+			if checked, _ := data["checked"].(bool); checked {
+				mark = "x"
+			}
+			fmt.Fprintf(buf, "%s- [%s] %s\n", prefix, mark, text)
+		case "quote":
+			fmt.Fprintf(buf, "%s> %s\n\n", prefix, text)
+		case "callout":
+			icon := "💡"
+			if iconObj, ok := data["icon"].(map[string]interface{}); ok {
+				if emoji, ok := iconObj["emoji"].(string); ok {
+					icon = emoji
+				}
+			}
+			fmt.Fprintf(buf, "%s> %s %s\n\n", prefix, icon, text)
+		case "code":
+			lang, _ := data["language"].(string)
+			if lang == "plain text" {
+				lang = ""
+			}
+			fmt.Fprintf(buf, "%s```%s\n%s\n%s```\n\n", prefix, lang, notion.PlainText(rt), prefix)
+		case "divider":
+			fmt.Fprintf(buf, "%s---\n\n", prefix)
+		case "equation":
+			expr, _ := data["expression"].(string)
+			fmt.Fprintf(buf, "%s$$\n%s%s\n%s$$\n\n", prefix, prefix, expr, prefix)
+		case "image":
+			link, caption := exportMedia(data)
+			fmt.Fprintf(buf, "%s![%s](%s)\n\n", prefix, notion.PlainText(caption), link)
+		case "bookmark", "embed", "video", "audio", "file", "pdf", "link_preview":
+			link, caption := exportMedia(data)
+			fmt.Fprintf(buf, "%s[%s](%s)\n\n", prefix, firstNonEmpty(e.richText(p, caption), link), e.resolveHref(p, link))
+		case "child_page", "link_to_page":
+			title, link := e.exportBlockLink(p, block)
+			fmt.Fprintf(buf, "%s[%s](%s)\n\n", prefix, title, link)
+		case "child_database":
+			title, _ := data["title"].(string)
+			fmt.Fprintf(buf, "%s%s\n\n", prefix, firstNonEmpty(title, "Untitled database"))
+		case "table_of_contents":
+			for _, h := range p.headings {
+				fmt.Fprintf(buf, "%s%s- [%s](#%s)\n", prefix, strings.Repeat("  ", h.Level-1), h.Text, h.ID)
+			}
+			buf.WriteString("\n")
+		case "table":
+			for i, row := range exportChildren(block) {
+				rowData, _ := row["table_row"].(map[string]interface{})
+				cells, _ := rowData["cells"].([]interface{})
+				var parts []string
+				for _, cell := range cells {
+					cellRT, _ := cell.([]interface{})
+					parts = append(parts, e.richText(p, cellRT))
+				}
+				fmt.Fprintf(buf, "%s| %s |\n", prefix, strings.Join(parts, " | "))
+				if i == 0 {
+					fmt.Fprintf(buf, "%s|%s\n", prefix, strings.Repeat("---|", len(cells)))
+				}
+			}
+			buf.WriteString("\n")
+			continue
+		case "column_list", "column", "synced_block":
+			childIndent = indent
+		default:
+			if text != "" {
+				fmt.Fprintf(buf, "%s%s\n\n", prefix, text)
+			}
+		}
+		e.writeMarkdown(buf, p, exportChildren(block), childIndent)
+	}
+}
+
+// exportListTags are the HTML lists that runs of list items go in.
+var exportListTags = map[string]string{
+	"bulleted_list_item": "ul",
+	"numbered_list_item": "ol",
+	"to_do":              "ul",
+}
+
+func (e *pageExporter) writeHTML(buf *bytes.Buffer, p *exportPage, blocks []map[string]interface{}) {
+	for i, block := range blocks {
+		blockType, _ := block["type"].(string)
+		data, _ := block[blockType].(map[string]interface{})
+		rt, _ := data["rich_text"].([]interface{})
+		text := e.richText(p, rt)
+		children := exportChildren(block)
+
+		if tag, ok := exportListTags[blockType]; ok {
+			if i == 0 || blocks[i-1]["type"] != blockType {
+				fmt.Fprintf(buf, "<%s>\n", tag)
+			}
+			if blockType == "to_do" {
+				checked, _ := data["checked"].(bool)
+				box := `<input type="checkbox" disabled>`
+				if checked {
+					box = `<input type="checkbox" disabled checked>`
+				}
+				text = box + " " + text
+			}
+			fmt.Fprintf(buf, "<li>%s\n", text)
+			e.writeHTML(buf, p, children)
+			buf.WriteString("</li>\n")
+			if i == len(blocks)-1 || blocks[i+1]["type"] != blockType {
+				fmt.Fprintf(buf, "</%s>\n", tag)
+			}
+			continue
+		}
+
+		switch blockType {
+		case "paragraph":
+			if text != "" {
+				fmt.Fprintf(buf, "<p>%s</p>\n", text)
+			}
+		case "heading_1", "heading_2", "heading_3":
+			level := headingLevel(blockType)
+			id, _ := block["id"].(string)
+			fmt.Fprintf(buf, "<h%d id=\"%s\">%s</h%d>\n", level+1, html.EscapeString(p.anchors[plainID(id)]), text, level+1)
+		case "toggle":
+			fmt.Fprintf(buf, "<details>\n<summary>%s</summary>\n", text)
+			e.writeHTML(buf, p, children)
+			buf.WriteString("</details>\n")
+			continue
+		case "quote", "callout":
+			if icon, ok := data["icon"].(map[string]interface{}); ok {
+				if emoji, ok := icon["emoji"].(string); ok {
+					text = emoji + " " + text
+				}
+			}
+			fmt.Fprintf(buf, "<blockquote>\n<p>%s</p>\n", text)
+			e.writeHTML(buf, p, children)
+			buf.WriteString("</blockquote>\n")
+			continue
+		case "code":
+			lang, _ := data["language"].(string)
+			fmt.Fprintf(buf, "<pre><code class=\"language-%s\">%s</code></pre>\n", html.EscapeString(lang), html.EscapeString(notion.PlainText(rt)))
+		case "divider":
+			buf.WriteString("<hr>\n")
+		case "equation":
+			expr, _ := data["expression"].(string)
+			fmt.Fprintf(buf, "<p class=\"equation\">%s</p>\n", html.EscapeString(expr))
+		case "image":
+			link, caption := exportMedia(data)
+			fmt.Fprintf(buf, "<p><img src=\"%s\" alt=\"%s\"></p>\n", html.EscapeString(link), html.EscapeString(notion.PlainText(caption)))
+		case "bookmark", "embed", "video", "audio", "file", "pdf", "link_preview":
+			link, caption := exportMedia(data)
+			fmt.Fprintf(buf, "<p><a href=\"%s\">%s</a></p>\n", html.EscapeString(e.resolveHref(p, link)), firstNonEmpty(e.richText(p, caption), html.EscapeString(link)))
+		case "child_page", "link_to_page":
+			title, link := e.exportBlockLink(p, block)
+			fmt.Fprintf(buf, "<p><a href=\"%s\">%s</a></p>\n", html.EscapeString(link), html.EscapeString(title))
+		case "child_database":
+			title, _ := data["title"].(string)
+			fmt.Fprintf(buf, "<p>%s</p>\n", html.EscapeString(firstNonEmpty(title, "Untitled database")))
+		case "table_of_contents":
+			buf.WriteString("<nav>\n<ul>\n")
+			for _, h := range p.headings {
+				fmt.Fprintf(buf, "<li class=\"toc-%d\"><a href=\"#%s\">%s</a></li>\n", h.Level, html.EscapeString(h.ID), html.EscapeString(h.Text))
+			}
+			buf.WriteString("</ul>\n</nav>\n")
+		case "table":
+			buf.WriteString("<table>\n")
+			header, _ := data["has_column_header"].(bool)
+			for j, row := range exportChildren(block) {
+				rowData, _ := row["table_row"].(map[string]interface{})
+				cells, _ := rowData["cells"].([]interface{})
+				tag := "td"
+				if j == 0 && header {
+					tag = "th"
+				}
+				buf.WriteString("<tr>")
+				for _, cell := range cells {
+					cellRT, _ := cell.([]interface{})
+					fmt.Fprintf(buf, "<%s>%s</%s>", tag, e.richText(p, cellRT), tag)
+				}
+				buf.WriteString("</tr>\n")
+			}
+			buf.WriteString("</table>\n")
+			continue
+		case "column_list", "column", "synced_block":
+		default:
+			if text != "" {
+				fmt.Fprintf(buf, "<p>%s</p>\n", text)
+			}
+		}
+		e.writeHTML(buf, p, children)
+	}
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHeadingAnchor(t *testing.T) {
+	seen := map[string]int{}
+	tests := []struct{ text, want string }{
+		{"Getting Started", "getting-started"},
+		{"What's new? (v2)", "whats-new-v2"},
+		{"Getting Started", "getting-started-1"},
+		{"!!!", "section"},
+		{"Café au lait", "café-au-lait"},
+	}
+	for _, tt := range tests {
+		if got := uniqueAnchor(headingAnchor(tt.text), seen); got != tt.want {
+			t.Errorf("anchor(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNotionHrefTarget(t *testing.T) {
+	tests := []struct {
+		href, id, fragment string
+		ok                 bool
+	}{
+		{"https://www.notion.so/Team-Wiki-1111111111111111111111111111aaaa", "11111111-1111-1111-1111-11111111aaaa", "", true},
+		{"/1111111111111111111111111111aaaa#2222222222222222222222222222bbbb", "11111111-1111-1111-1111-11111111aaaa", "2222222222222222222222222222bbbb", true},
+		{"https://acme.notion.site/Docs-1111111111111111111111111111aaaa", "11111111-1111-1111-1111-11111111aaaa", "", true},
+		{"https://example.com/1111111111111111111111111111aaaa", "", "", false},
+		{"https://www.notion.so/my-integrations", "", "", false},
+		{"relative/path.md", "", "", false},
+	}
+	for _, tt := range tests {
+		id, fragment, ok := notionHrefTarget(tt.href)
+		if id != tt.id || fragment != tt.fragment || ok != tt.ok {
+			t.Errorf("notionHrefTarget(%q) = %q, %q, %v", tt.href, id, fragment, ok)
+		}
+	}
+}
+
+func TestRelativeExportPath(t *testing.T) {
+	tests := []struct{ from, to, want string }{
+		{"Wiki.md", "Wiki/Setup Guide.md", "Wiki/Setup%20Guide.md"},
+		{"Wiki/Setup Guide.md", "Wiki.md", "../Wiki.md"},
+		{"Wiki/A.md", "Wiki/B.md", "B.md"},
+	}
+	for _, tt := range tests {
+		if got := relativeExportPath(tt.from, tt.to); got != tt.want {
+			t.Errorf("relativeExportPath(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPageExportCommand(t *testing.T) {
+	const (
+		rootID    = "11111111-1111-1111-1111-111111111111"
+		childID   = "22222222-2222-2222-2222-222222222222"
+		headingID = "33333333-3333-3333-3333-333333333333"
+		noteID    = "44444444-4444-4444-4444-444444444444"
+		outsideID = "99999999-9999-9999-9999-999999999999"
+	)
+	text := func(s string, extra map[string]interface{}) map[string]interface{} {
+		m := map[string]interface{}{"type": "text", "plain_text": s}
+		for k, v := range extra {
+			m[k] = v
+		}
+		return m
+	}
+	para := func(id string, rt ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": id, "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": rt}}
+	}
+	children := map[string][]interface{}{
+		rootID: {
+			map[string]interface{}{"id": headingID, "type": "heading_2", "heading_2": map[string]interface{}{"rich_text": []interface{}{text("Getting Started", nil)}}},
+			para(noteID, text("See ", nil), text("setup", map[string]interface{}{"href": "/" + plainID(childID)}),
+				text(" and ", nil), text("elsewhere", map[string]interface{}{"href": "https://www.notion.so/" + plainID(outsideID)})),
+			map[string]interface{}{"id": "child-block", "type": "child_page", "has_children": true, "child_page": map[string]interface{}{"title": "Setup: Guide"}},
+		},
+		childID: {
+			para("c1", text("Back to ", nil), text("the start", map[string]interface{}{"href": "https://www.notion.so/" + plainID(rootID) + "#" + plainID(noteID)})),
+			map[string]interface{}{"id": "c2", "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{
+				map[string]interface{}{"type": "mention", "plain_text": "Root", "mention": map[string]interface{}{"type": "page", "page": map[string]interface{}{"id": rootID}}},
+			}}},
+			map[string]interface{}{"id": "c3", "type": "link_to_page", "link_to_page": map[string]interface{}{"type": "page_id", "page_id": rootID}},
+		},
+	}
+	// The child_page block's ID is the child page's ID.
+	children[rootID][2].(map[string]interface{})["id"] = childID
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": children[id]})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": rootID, "properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Wiki"}}},
+			}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	dir := t.TempDir()
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "export", rootID, "--dir", dir); err != nil {
+			t.Fatalf("page export: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Exported 2 page(s)") {
+		t.Errorf("output = %q", out)
+	}
+
+	root, err := os.ReadFile(filepath.Join(dir, "Wiki.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoot := "# Wiki\n\n## Getting Started\n\n" +
+		"See [setup](Wiki/Setup%20Guide.md) and [elsewhere](https://www.notion.so/" + plainID(outsideID) + ")\n\n" +
+		"[Setup: Guide](Wiki/Setup%20Guide.md)\n\n"
+	if string(root) != wantRoot {
+		t.Errorf("Wiki.md:\n%s\nwant:\n%s", root, wantRoot)
+	}
+
+	child, err := os.ReadFile(filepath.Join(dir, "Wiki", "Setup Guide.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantChild := "# Setup: Guide\n\nBack to [the start](../Wiki.md#getting-started)\n\n[Root](../Wiki.md)\n\n[Wiki](../Wiki.md)\n\n"
+	if string(child) != wantChild {
+		t.Errorf("Setup Guide.md:\n%s\nwant:\n%s", child, wantChild)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "export", rootID, "--dir", dir, "--html"); err != nil {
+			t.Fatalf("page export --html: %v", err)
+		}
+	})
+	page, err := os.ReadFile(filepath.Join(dir, "Wiki.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<h3 id="getting-started">Getting Started</h3>`, `<a href="Wiki/Setup%20Guide.html">setup</a>`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("Wiki.html lacks %s:\n%s", want, page)
+		}
+	}
+}
+
+func TestPageExportDiffOnly(t *testing.T) {
+	const (
+		rootID  = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+	)
+	childText := "first draft"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "/children") {
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+			results := []interface{}{map[string]interface{}{"id": childID, "type": "child_page", "child_page": map[string]interface{}{"title": "Notes"}}}
+			if id == childID {
+				results = []interface{}{map[string]interface{}{"id": "c1", "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"plain_text": childText}}}}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": rootID, "properties": map[string]interface{}{
+			"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Root"}}},
+		}})
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	dir := t.TempDir()
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "export", rootID, "--dir", dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+	manifest := loadExportManifest(dir, rootID)
+	if len(manifest.Pages) != 2 || manifest.Pages[1].SHA256 == "" {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+
+	childText = "second draft"
+	defer func() { outputFormat = "" }()
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "export", rootID, "--dir", dir, "--diff-only", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var got struct {
+		Changes   []exportChange `json:"changes"`
+		Unchanged int            `json:"unchanged"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if len(got.Changes) != 1 || got.Changes[0].Status != "modified" || got.Changes[0].Path != "Root/Notes.md" || got.Unchanged != 1 {
+		t.Errorf("diff = %+v", got)
+	}
+	if child, _ := os.ReadFile(filepath.Join(dir, "Root", "Notes.md")); !strings.Contains(string(child), "first draft") {
+		t.Errorf("--diff-only rewrote Notes.md: %q", child)
+	}
+}
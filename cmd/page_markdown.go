@@ -7,7 +7,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
 	"github.com/spf13/cobra"
@@ -50,8 +49,7 @@ Examples:
 		pageID := util.ResolveID(args[0])
 		outPath, _ := cmd.Flags().GetString("out")
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.Get(fmt.Sprintf("/v1/pages/%s/markdown", pageID))
 		if err != nil {
@@ -153,8 +151,7 @@ Examples:
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		data, err := c.Patch(fmt.Sprintf("/v1/pages/%s/markdown", pageID), body)
 		if err != nil {
@@ -187,10 +184,10 @@ Examples:
 // Exactly one must be set; --file "-" also reads from stdin.
 func readMarkdownSource(filePath, text string) (string, error) {
 	if filePath == "" && text == "" {
-		return "", fmt.Errorf("one of --file or --text is required")
+		return "", usageError(fmt.Errorf("one of --file or --text is required"))
 	}
 	if filePath != "" && text != "" {
-		return "", fmt.Errorf("--file and --text are mutually exclusive")
+		return "", usageError(fmt.Errorf("--file and --text are mutually exclusive"))
 	}
 	if text != "" {
 		return text, nil
@@ -228,7 +225,7 @@ func buildSetMarkdownBody(content string, replace, appendMode bool, after, range
 		modes++
 	}
 	if modes > 1 {
-		return nil, fmt.Errorf("pick at most one of --replace, --append, --after, --range")
+		return nil, usageError(fmt.Errorf("pick at most one of --replace, --append, --after, --range"))
 	}
 
 	switch {
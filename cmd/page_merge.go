@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var pageMergeCmd = &cobra.Command{
+	Use:   "merge <target-page> <page>...",
+	Short: "Merge pages into one",
+	Long: `Merge the content and properties of pages into the first one, then
+archive them — for cleaning up duplicate meeting notes and the like.
+
+Each merged page's content is appended to the target under a heading
+with its title. Properties with the same name and type are merged:
+multi-selects, relations and people are combined, checkboxes are checked
+if any page checks them, and other empty properties are filled from the
+first page that has a value. Files and computed properties are left
+alone.
+
+The merged pages get a "Merged into" link to the target before they are
+archived (restore them with 'notion page restore'); --keep leaves them in
+place. A page whose content the server can't render completely as
+markdown stops the merge, unless --allow-partial is given.
+
+Examples:
+  notion page merge abc123 def456 ghi789
+  notion page merge abc123 def456 --title "Weekly sync 2026-10-12"
+  notion page merge abc123 def456 --dry-run`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		title, _ := cmd.Flags().GetString("title")
+		keep, _ := cmd.Flags().GetBool("keep")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		allowPartial, _ := cmd.Flags().GetBool("allow-partial")
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+
+		targetID := util.ResolveID(args[0])
+		target, err := c.GetPage(targetID)
+		if err != nil {
+			return fmt.Errorf("get page %s: %w", targetID, err)
+		}
+
+		type source struct {
+			id, title, markdown string
+			props               map[string]interface{}
+		}
+		var sources []source
+		for _, arg := range args[1:] {
+			id := util.ResolveID(arg)
+			if sameID(id, targetID) {
+				return fmt.Errorf("%s is the target page", arg)
+			}
+			page, err := c.GetPage(id)
+			if err != nil {
+				return fmt.Errorf("get page %s: %w", id, err)
+			}
+			markdown, err := pageMarkdown(c, id, allowPartial)
+			if err != nil {
+				return err
+			}
+			props, _ := page["properties"].(map[string]interface{})
+			sources = append(sources, source{id: id, title: render.ExtractTitle(page), markdown: markdown, props: props})
+		}
+
+		targetProps, _ := target["properties"].(map[string]interface{})
+		var sourceProps []map[string]interface{}
+		for _, s := range sources {
+			sourceProps = append(sourceProps, s.props)
+		}
+		updates := mergeProperties(targetProps, sourceProps)
+		if title != "" {
+			for name, p := range targetProps {
+				if prop, _ := p.(map[string]interface{}); prop["type"] == "title" {
+					updates[name] = buildPropertyValue("title", title)
+				}
+			}
+		}
+
+		if dryRun {
+			if outputFormat == "json" {
+				var ids []string
+				for _, s := range sources {
+					ids = append(ids, s.id)
+				}
+				return render.JSON(map[string]interface{}{"target": targetID, "sources": ids, "properties": updates})
+			}
+			fmt.Printf("Would merge into %s:\n", render.ExtractTitle(target))
+			for _, s := range sources {
+				fmt.Printf("  %s (%d characters of content)\n", s.title, len(s.markdown))
+			}
+			if len(updates) > 0 {
+				fmt.Printf("Properties set: %s\n", strings.Join(sortedKeys(updates), ", "))
+			}
+			return nil
+		}
+
+		if len(updates) > 0 {
+			if _, err := c.Patch("/v1/pages/"+targetID, map[string]interface{}{"properties": updates}); err != nil {
+				return fmt.Errorf("update properties: %w", err)
+			}
+		}
+		for _, s := range sources {
+			section := fmt.Sprintf("\n## %s\n\n%s", firstNonEmpty(s.title, "Untitled"), s.markdown)
+			if _, err := c.Patch("/v1/pages/"+targetID+"/markdown", map[string]interface{}{
+				"type":           "insert_content",
+				"insert_content": map[string]interface{}{"content": section},
+			}); err != nil {
+				return fmt.Errorf("append %s: %w", s.title, err)
+			}
+		}
+
+		targetURL, _ := target["url"].(string)
+		targetTitle := firstNonEmpty(title, render.ExtractTitle(target), "Untitled")
+		var archived []string
+		if !keep {
+			note := fmt.Sprintf("\n> Merged into [%s](%s)\n", targetTitle, targetURL)
+			for _, s := range sources {
+				if _, err := c.Patch("/v1/pages/"+s.id+"/markdown", map[string]interface{}{
+					"type":           "insert_content",
+					"insert_content": map[string]interface{}{"content": note},
+				}); err != nil {
+					fmt.Fprintf(os.Stderr, "note: %s: could not add the merge link: %s\n", s.title, firstLine(err))
+				}
+				if _, err := c.Patch("/v1/pages/"+s.id, map[string]interface{}{"archived": true}); err != nil {
+					return fmt.Errorf("archive %s: %w", s.title, err)
+				}
+				archived = append(archived, s.id)
+			}
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{
+				"id":         targetID,
+				"url":        targetURL,
+				"merged":     len(sources),
+				"properties": sortedKeys(updates),
+				"archived":   archived,
+			})
+		}
+		fmt.Printf("✓ Merged %d page(s) into %s\n", len(sources), targetTitle)
+		if len(updates) > 0 {
+			fmt.Printf("  properties: %s\n", strings.Join(sortedKeys(updates), ", "))
+		}
+		if len(archived) > 0 {
+			fmt.Printf("  archived %d page(s)\n", len(archived))
+		}
+		if targetURL != "" {
+			fmt.Printf("  %s\n", notionLink(targetURL))
+		}
+		return nil
+	},
+}
+
+func init() {
+	pageMergeCmd.Flags().String("title", "", "Rename the merged page")
+	pageMergeCmd.Flags().Bool("keep", false, "Don't archive the merged pages")
+	pageMergeCmd.Flags().Bool("dry-run", false, "Show what would be merged without changing anything")
+	pageMergeCmd.Flags().Bool("allow-partial", false, "Merge pages whose content can't be fully rendered as markdown")
+	pageCmd.AddCommand(pageMergeCmd)
+}
+
+// pageMarkdown fetches a page's server-rendered markdown. Unless partial
+// is allowed, it fails when the server couldn't render all of it, since
+// whatever is missing would be lost.
+func pageMarkdown(c *notion.Client, pageID string, partial bool) (string, error) {
+	data, err := c.Get("/v1/pages/" + pageID + "/markdown")
+	if err != nil {
+		return "", fmt.Errorf("get page markdown: %w", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("parse response: %w", err)
+	}
+	markdown, _ := result["markdown"].(string)
+	unknown, _ := result["unknown_block_ids"].([]interface{})
+	if truncated, _ := result["truncated"].(bool); (truncated || len(unknown) > 0) && !partial {
+		return "", fmt.Errorf("page %s can't be fully rendered as markdown (truncated or %d unsupported block(s)); use --allow-partial to merge it anyway", pageID, len(unknown))
+	}
+	return markdown, nil
+}
+
+// mergeProperties returns the property updates that fold sources into
+// target: list properties are combined, checkboxes ORed, and empty
+// properties filled from the first source with a value. Only properties
+// with the same name and type are merged.
+func mergeProperties(target map[string]interface{}, sources []map[string]interface{}) map[string]interface{} {
+	updates := map[string]interface{}{}
+	for name, tp := range target {
+		tprop, _ := tp.(map[string]interface{})
+		propType, _ := tprop["type"].(string)
+		var same []map[string]interface{}
+		for _, props := range sources {
+			if sprop, ok := props[name].(map[string]interface{}); ok && sprop["type"] == propType {
+				same = append(same, sprop)
+			}
+		}
+		if len(same) == 0 {
+			continue
+		}
+
+		switch propType {
+		case "multi_select", "relation", "people":
+			key := "id"
+			if propType == "multi_select" {
+				key = "name"
+			}
+			var merged []interface{}
+			seen := map[string]bool{}
+			for _, prop := range append([]map[string]interface{}{tprop}, same...) {
+				items, _ := prop[propType].([]interface{})
+				for _, it := range items {
+					m, _ := it.(map[string]interface{})
+					v, _ := m[key].(string)
+					if v != "" && !seen[v] {
+						seen[v] = true
+						merged = append(merged, map[string]interface{}{key: v})
+					}
+				}
+			}
+			current, _ := tprop[propType].([]interface{})
+			if len(merged) > len(current) {
+				updates[name] = map[string]interface{}{propType: merged}
+			}
+		case "checkbox":
+			if checked, _ := tprop["checkbox"].(bool); !checked {
+				for _, prop := range same {
+					if checked, _ := prop["checkbox"].(bool); checked {
+						updates[name] = map[string]interface{}{"checkbox": true}
+						break
+					}
+				}
+			}
+		case "select", "status":
+			if tprop[propType] == nil {
+				for _, prop := range same {
+					if opt, _ := prop[propType].(map[string]interface{}); opt["name"] != nil {
+						updates[name] = map[string]interface{}{propType: map[string]interface{}{"name": opt["name"]}}
+						break
+					}
+				}
+			}
+		case "number", "url", "email", "phone_number", "date":
+			if v := tprop[propType]; v == nil || v == "" {
+				for _, prop := range same {
+					if v := prop[propType]; v != nil && v != "" {
+						updates[name] = map[string]interface{}{propType: v}
+						break
+					}
+				}
+			}
+		case "rich_text":
+			if extractPropertyValue(tprop) == "" {
+				for _, prop := range same {
+					if text := extractPropertyValue(prop); text != "" {
+						updates[name] = buildPropertyValue("rich_text", text)
+						break
+					}
+				}
+			}
+		}
+	}
+	return updates
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMergeProperties(t *testing.T) {
+	target := map[string]interface{}{
+		"Tags":   map[string]interface{}{"type": "multi_select", "multi_select": []interface{}{map[string]interface{}{"name": "a"}}},
+		"Done":   map[string]interface{}{"type": "checkbox", "checkbox": false},
+		"Status": map[string]interface{}{"type": "select", "select": nil},
+		"Points": map[string]interface{}{"type": "number", "number": 3.0},
+		"Owner":  map[string]interface{}{"type": "people", "people": []interface{}{}},
+	}
+	sources := []map[string]interface{}{
+		{
+			"Tags":   map[string]interface{}{"type": "multi_select", "multi_select": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}}},
+			"Done":   map[string]interface{}{"type": "checkbox", "checkbox": true},
+			"Points": map[string]interface{}{"type": "number", "number": 5.0},
+			"Owner":  map[string]interface{}{"type": "rich_text", "rich_text": []interface{}{}},
+		},
+		{"Status": map[string]interface{}{"type": "select", "select": map[string]interface{}{"name": "Open", "color": "red"}}},
+	}
+	got := mergeProperties(target, sources)
+	want := map[string]interface{}{
+		"Tags":   map[string]interface{}{"multi_select": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}}},
+		"Done":   map[string]interface{}{"checkbox": true},
+		"Status": map[string]interface{}{"select": map[string]interface{}{"name": "Open"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPageMergeCommand(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		mu.Unlock()
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/markdown"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"markdown": "Notes from " + strings.Split(r.URL.Path, "/")[3]})
+		case r.Method == "GET":
+			id := strings.TrimPrefix(r.URL.Path, "/v1/pages/")
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "url": "https://notion.so/" + id, "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Page " + id[:1]}}},
+			}})
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	target := "11111111-1111-1111-1111-111111111111"
+	source := "22222222-2222-2222-2222-222222222222"
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "merge", target, source); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Merged 1 page(s) into Page 1") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	var appended, linked, archived bool
+	for _, call := range calls {
+		switch {
+		case strings.HasPrefix(call, "PATCH /v1/pages/"+target+"/markdown") && strings.Contains(call, `## Page 2\n\nNotes from `+source):
+			appended = true
+		case strings.HasPrefix(call, "PATCH /v1/pages/"+source+"/markdown") && strings.Contains(call, "Merged into [Page 1](https://notion.so/"+target+")"):
+			linked = true
+		case call == "PATCH /v1/pages/"+source+` {"archived":true}`:
+			archived = true
+		}
+	}
+	if !appended || !linked || !archived {
+		t.Errorf("appended=%v linked=%v archived=%v; calls:\n%s", appended, linked, archived, strings.Join(calls, "\n"))
+	}
+}
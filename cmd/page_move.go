@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// checkMove catches moves the API would reject or that make no sense
+// before any is attempted: a page in the trash, one already where it is
+// going, or one moved under itself or its own sub-pages. It returns the
+// page.
+func checkMove(c *notion.Client, pageID string, to pageParent) (map[string]interface{}, error) {
+	page, err := c.GetPage(pageID)
+	if err != nil {
+		return nil, fmt.Errorf("get page: %w", err)
+	}
+	if archived, _ := page["archived"].(bool); archived {
+		return nil, fmt.Errorf("%s is in the trash; 'notion page restore' it first", pageID)
+	}
+	current, _ := page["parent"].(map[string]interface{})
+	if kind, _ := current["type"].(string); kind == to.Kind && (kind == "workspace" || sameID(fmt.Sprint(current[kind]), to.ID)) {
+		return nil, fmt.Errorf("the page is already under %s", to)
+	}
+	if to.Kind == "workspace" {
+		return page, nil
+	}
+	if sameID(to.ID, pageID) {
+		return nil, fmt.Errorf("can't move a page under itself")
+	}
+
+	// Walk up from the target: meeting the page means the target lives
+	// inside it.
+	blockParents := map[string]string{}
+	kind, id := to.Kind, to.ID
+	for steps := 0; id != "" && steps < 50; steps++ {
+		var obj map[string]interface{}
+		if kind == "database_id" {
+			obj, err = c.GetDatabase(id)
+		} else {
+			obj, err = c.GetPage(id)
+		}
+		if err != nil {
+			break
+		}
+		parent, _ := obj["parent"].(map[string]interface{})
+		kind, _ = parent["type"].(string)
+		id, _ = parent[kind].(string)
+		if kind == "block_id" {
+			kind, id = "page_id", blockPage(c, id, blockParents)
+		}
+		if sameID(id, pageID) {
+			return nil, fmt.Errorf("can't move a page under one of its own sub-pages")
+		}
+	}
+	return page, nil
+}
+
+// moveRefused reports whether a failed move is one the API won't do at
+// all (the endpoint isn't available, or this kind of move isn't allowed)
+// rather than a passing failure, so copying is worth a try.
+func moveRefused(err error) bool {
+	var apiErr *notion.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Status {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return true
+	}
+	return false
+}
+
+// copyPageTo recreates page under parent, with its title, icon, cover,
+// content and (under a database) the properties the database shares with
+// it, then leaves a link to the copy on the original and archives it.
+// Sub-pages would be archived along with the original, so a page with any
+// is refused.
+func copyPageTo(c *notion.Client, page map[string]interface{}, parent pageParent) (map[string]interface{}, error) {
+	pageID, _ := page["id"].(string)
+	children, err := fetchBlockChildren(c, pageID, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("get page content: %w", err)
+	}
+	subpages := 0
+	for _, ch := range children {
+		if block, _ := ch.(map[string]interface{}); block["type"] == "child_page" || block["type"] == "child_database" {
+			subpages++
+		}
+	}
+	if subpages > 0 {
+		return nil, fmt.Errorf("the page has %d sub-page(s) or database(s) that a copy would leave in the trash; move those first", subpages)
+	}
+	markdown, err := pageMarkdown(c, pageID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	title := render.ExtractTitle(page)
+	properties := map[string]interface{}{"title": buildPropertyValue("title", title)}
+	if parent.Kind == "database_id" {
+		empty := map[string]interface{}{}
+		for name, p := range parent.Schema {
+			prop, _ := p.(map[string]interface{})
+			if prop["type"] == "title" {
+				properties = map[string]interface{}{name: buildPropertyValue("title", title)}
+				continue
+			}
+			empty[name] = map[string]interface{}{"type": prop["type"]}
+		}
+		source, _ := page["properties"].(map[string]interface{})
+		for name, v := range mergeProperties(empty, []map[string]interface{}{source}) {
+			properties[name] = v
+		}
+	}
+	body := map[string]interface{}{"parent": parent.object(), "properties": properties}
+	for _, key := range []string{"icon", "cover"} {
+		if v, ok := page[key].(map[string]interface{}); ok && v["type"] != "file" {
+			body[key] = v
+		}
+	}
+
+	data, err := c.Post("/v1/pages", body)
+	if err != nil {
+		return nil, fmt.Errorf("create copy: %w", err)
+	}
+	rememberCreated(data)
+	var created map[string]interface{}
+	if err := json.Unmarshal(data, &created); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	newID, _ := created["id"].(string)
+	if markdown != "" {
+		if _, err := c.Patch("/v1/pages/"+newID+"/markdown", map[string]interface{}{
+			"type":           "insert_content",
+			"insert_content": map[string]interface{}{"content": markdown},
+		}); err != nil {
+			return nil, fmt.Errorf("copy content to %s (the original is untouched): %w", newID, err)
+		}
+	}
+
+	url, _ := created["url"].(string)
+	note := fmt.Sprintf("\n> Moved to [%s](%s)\n", firstNonEmpty(title, "Untitled"), url)
+	if _, err := c.Patch("/v1/pages/"+pageID+"/markdown", map[string]interface{}{
+		"type":           "insert_content",
+		"insert_content": map[string]interface{}{"content": note},
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "note: could not add the link to the copy: %s\n", firstLine(err))
+	}
+	if _, err := c.Patch("/v1/pages/"+pageID, map[string]interface{}{"archived": true}); err != nil {
+		return nil, fmt.Errorf("copied to %s, but archiving the original failed: %w", newID, err)
+	}
+	return created, nil
+}
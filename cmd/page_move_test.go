@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPageMoveChecksAndCopyFallback(t *testing.T) {
+	const (
+		srcID   = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+		destID  = "33333333-3333-3333-3333-333333333333"
+	)
+	pages := map[string]map[string]interface{}{
+		srcID: {"object": "page", "id": srcID, "parent": map[string]interface{}{"type": "workspace", "workspace": true},
+			"icon":       map[string]interface{}{"type": "emoji", "emoji": "📘"},
+			"properties": map[string]interface{}{"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Handbook"}}}}},
+		childID: {"object": "page", "id": childID, "parent": map[string]interface{}{"type": "page_id", "page_id": srcID}},
+		destID:  {"object": "page", "id": destID, "parent": map[string]interface{}{"type": "workspace", "workspace": true}},
+	}
+	var writes []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		id := strings.TrimPrefix(r.URL.Path, "/v1/pages/")
+		switch {
+		case r.Method == "GET" && pages[id] != nil:
+			json.NewEncoder(w).Encode(pages[id])
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/markdown"):
+			w.Write([]byte(`{"markdown":"Welcome aboard."}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/children"):
+			w.Write([]byte(`{"results":[]}`))
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not found"}`))
+		case strings.HasSuffix(r.URL.Path, "/move"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"object":"error","status":400,"code":"invalid_request_url","message":"Invalid request URL."}`))
+		default:
+			writes = append(writes, r.Method+" "+r.URL.Path+" "+string(body))
+			w.Write([]byte(`{"object":"page","id":"copy","url":"https://www.notion.so/copy"}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if _, _, err := executeCommand("page", "move", srcID, "--to", childID); err == nil || !strings.Contains(err.Error(), "its own sub-pages") {
+		t.Errorf("move into a sub-page: err = %v", err)
+	}
+	if _, _, err := executeCommand("page", "move", srcID, "--to", "workspace"); err == nil || !strings.Contains(err.Error(), "already under") {
+		t.Errorf("move to where it is: err = %v", err)
+	}
+	if _, _, err := executeCommand("page", "move", srcID, "--to", destID); err == nil || !strings.Contains(err.Error(), "--copy-fallback") {
+		t.Errorf("refused move: err = %v", err)
+	}
+	if len(writes) != 0 {
+		t.Fatalf("failed moves wrote: %v", writes)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "move", srcID, "--to", destID, "--copy-fallback"); err != nil {
+			t.Fatalf("page move --copy-fallback: %v", err)
+		}
+	})
+	want := []string{
+		`POST /v1/pages {"icon":{"emoji":"📘","type":"emoji"},"parent":{"page_id":"` + destID + `","type":"page_id"},"properties":{"title":{"title":[{"text":{"content":"Handbook"}}]}}}`,
+		`PATCH /v1/pages/copy/markdown {"insert_content":{"content":"Welcome aboard."},"type":"insert_content"}`,
+		`PATCH /v1/pages/` + srcID + `/markdown {"insert_content":{"content":"\n\u003e Moved to [Handbook](https://www.notion.so/copy)\n"},"type":"insert_content"}`,
+		`PATCH /v1/pages/` + srcID + ` {"archived":true}`,
+	}
+	if strings.Join(writes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("writes:\n%s\nwant:\n%s", strings.Join(writes, "\n"), strings.Join(want, "\n"))
+	}
+	// Flags persist between executeCommand calls.
+	executeCommand("page", "move", srcID, "--to", childID, "--copy-fallback=false")
+}
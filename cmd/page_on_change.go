@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// pageChange is what 'page comment-on-change' knows about a changed page.
+// The field names are the ones available to --comment.
+type pageChange struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	EditedBy   string `json:"edited_by"`
+	EditedTime string `json:"edited_time"`
+	LastCheck  string `json:"last_check"`
+}
+
+// pageContentState is the content hash 'page comment-on-change' saw last.
+type pageContentState struct {
+	PageID  string    `json:"page_id"`
+	Key     string    `json:"key"`
+	Sum     string    `json:"sum"`
+	Checked time.Time `json:"checked"`
+}
+
+var pageCommentOnChangeCmd = &cobra.Command{
+	Use:   "comment-on-change <page-id|url>",
+	Short: "Comment or run a command when a page's content changed",
+	Long: `Compare a hash of the page's title and content with the one saved by the
+previous run and, when it changed, post --comment on the page and/or run
+--exec. Run it from cron or CI to build review reminders and the like.
+
+The first run only saves the hash. The hash is saved again only once the
+actions succeed, so a failed run is retried the next time; --dry-run
+reports a change without acting or saving. Edit times and the expiring
+links of uploaded files don't count as changes. --key keeps a separate
+hash per automation watching the same page.
+
+--comment is a Go template over .Title, .ID, .URL, .EditedBy, .EditedTime
+and .LastCheck. --exec runs through the shell with NOTION_PAGE_ID,
+NOTION_PAGE_TITLE, NOTION_PAGE_URL, NOTION_EDITED_BY and
+NOTION_EDITED_TIME set.
+
+Examples:
+  notion page comment-on-change abc123 --comment "{{.EditedBy}} changed this page; please review" --mention-user user-123
+  notion page comment-on-change abc123 --exec 'curl -d "$NOTION_PAGE_TITLE changed" https://hooks.example.com/x'
+  notion page comment-on-change abc123 --key legal --comment "Legal review needed" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		commentTmpl, _ := cmd.Flags().GetString("comment")
+		mentions, _ := cmd.Flags().GetStringArray("mention-user")
+		execCmd, _ := cmd.Flags().GetString("exec")
+		key, _ := cmd.Flags().GetString("key")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if commentTmpl == "" && execCmd == "" {
+			return usageError(fmt.Errorf("give --comment, --exec or both"))
+		}
+		var tmpl *template.Template
+		if commentTmpl != "" {
+			var err error
+			if tmpl, err = template.New("comment").Option("missingkey=error").Parse(commentTmpl); err != nil {
+				return usageError(fmt.Errorf("parse --comment: %w", err))
+			}
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		pageID := util.ResolveID(args[0])
+		page, err := c.GetPage(pageID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		blocks, err := fetchExportBlocks(c, pageID)
+		if err != nil {
+			return fmt.Errorf("get content: %w", err)
+		}
+		title := render.ExtractTitle(page)
+		sum := pageContentSum(title, blocks)
+		state := loadPageContentState(pageID, key)
+		now := timeNow().UTC().Truncate(time.Second)
+
+		edited, _ := page["last_edited_time"].(string)
+		link, _ := page["url"].(string)
+		change := pageChange{ID: pageID, Title: title, URL: link, EditedTime: edited}
+		if !state.Checked.IsZero() {
+			change.LastCheck = state.Checked.Format(time.RFC3339)
+		}
+		first, changed := state.Sum == "", state.Sum != "" && state.Sum != sum
+		result := map[string]interface{}{"first_run": first, "changed": changed}
+
+		if changed {
+			change.EditedBy = trackEditor(c, page, map[string]string{})
+			text := ""
+			if tmpl != nil {
+				var buf bytes.Buffer
+				if err := tmpl.Execute(&buf, change); err != nil {
+					return usageError(fmt.Errorf("render --comment: %w", err))
+				}
+				text = strings.TrimSpace(buf.String())
+				result["comment"] = text
+			}
+			if !dryRun {
+				if tmpl != nil {
+					ids := make([]string, len(mentions))
+					for i, m := range mentions {
+						ids[i] = util.ResolveID(m)
+					}
+					if _, err := c.AddComment(pageID, text, ids); err != nil {
+						return fmt.Errorf("add comment: %w", err)
+					}
+				}
+				if execCmd != "" {
+					if err := runOnChange(execCmd, change); err != nil {
+						return fmt.Errorf("--exec: %w", err)
+					}
+				}
+			}
+		}
+		if !dryRun {
+			if err := savePageContentState(pageContentState{PageID: pageID, Key: key, Sum: sum, Checked: now}); err != nil {
+				return err
+			}
+		}
+
+		if outputFormat == "json" {
+			result["page"], result["dry_run"] = change, dryRun
+			return render.JSON(result)
+		}
+		name := firstNonEmpty(title, "Untitled")
+		switch {
+		case first:
+			fmt.Printf("✓ Saved the content of %s; later runs act when it changes\n", name)
+		case !changed:
+			fmt.Printf("%s hasn't changed since %s\n", name, state.Checked.Local().Format("2006-01-02 15:04"))
+		case dryRun:
+			fmt.Printf("%s changed (dry run: nothing done)\n", name)
+		default:
+			fmt.Printf("✓ %s changed; actions run\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pageCommentOnChangeCmd.Flags().String("comment", "", "Comment to post when the page changed (Go template)")
+	pageCommentOnChangeCmd.Flags().StringArray("mention-user", nil, "Mention a Notion user by ID in the comment (repeatable)")
+	pageCommentOnChangeCmd.Flags().String("exec", "", "Shell command to run when the page changed")
+	pageCommentOnChangeCmd.Flags().String("key", "", "Name of the saved hash, for several automations on one page")
+	pageCommentOnChangeCmd.Flags().Bool("dry-run", false, "Report a change without acting or saving the hash")
+	pageCmd.AddCommand(pageCommentOnChangeCmd)
+}
+
+// pageContentSum hashes a page's title and blocks, leaving out what
+// changes without an edit: edit times and editors, and the signature and
+// expiry of uploaded files' links.
+func pageContentSum(title string, blocks []map[string]interface{}) string {
+	data, _ := json.Marshal(map[string]interface{}{"title": title, "blocks": stableBlocks(blocks)})
+	return contentSum(data)
+}
+
+func stableBlocks(blocks []map[string]interface{}) []interface{} {
+	out := []interface{}{}
+	for _, block := range blocks {
+		blockType, _ := block["type"].(string)
+		data, _ := block[blockType].(map[string]interface{})
+		stable := map[string]interface{}{}
+		for k, v := range data {
+			stable[k] = v
+		}
+		if file, ok := data["file"].(map[string]interface{}); ok {
+			link, _ := file["url"].(string)
+			if u, err := url.Parse(link); err == nil {
+				u.RawQuery = ""
+				link = u.String()
+			}
+			stable["file"] = link
+		}
+		out = append(out, map[string]interface{}{
+			"type":     blockType,
+			blockType:  stable,
+			"children": stableBlocks(exportChildren(block)),
+		})
+	}
+	return out
+}
+
+// runOnChange runs command through the shell with the change in its
+// environment.
+func runOnChange(command string, change pageChange) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	c.Env = append(os.Environ(),
+		"NOTION_PAGE_ID="+change.ID,
+		"NOTION_PAGE_TITLE="+change.Title,
+		"NOTION_PAGE_URL="+change.URL,
+		"NOTION_EDITED_BY="+change.EditedBy,
+		"NOTION_EDITED_TIME="+change.EditedTime,
+	)
+	c.Stdout, c.Stderr = os.Stderr, os.Stderr
+	return c.Run()
+}
+
+func pageContentStatePath(pageID, key string) string {
+	sum := sha1.Sum([]byte(plainID(pageID) + "|" + key))
+	return filepath.Join(config.CacheDir(), "changes", hex.EncodeToString(sum[:8])+".json")
+}
+
+func loadPageContentState(pageID, key string) pageContentState {
+	var state pageContentState
+	if data, err := os.ReadFile(pageContentStatePath(pageID, key)); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func savePageContentState(state pageContentState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := pageContentStatePath(state.PageID, state.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("save content hash: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("save content hash: %w", err)
+	}
+	return nil
+}
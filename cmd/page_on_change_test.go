@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPageCommentOnChange(t *testing.T) {
+	text, image := "Draft", "https://files.example.com/a.png?X-Amz-Signature=1"
+	var comments []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/comments":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			comments = append(comments, mustJSON(body["rich_text"]))
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "comment", "id": "c1"})
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"object": "block", "id": "b1", "type": "paragraph",
+					"paragraph": map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"plain_text": text}}}},
+				map[string]interface{}{"object": "block", "id": "b2", "type": "image",
+					"image": map[string]interface{}{"type": "file", "file": map[string]interface{}{"url": image, "expiry_time": image}}},
+			}, "has_more": false})
+		case strings.HasPrefix(r.URL.Path, "/v1/users/"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "user", "id": "u1", "name": "Ada"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "11111111-1111-1111-1111-111111111111",
+				"url": "https://www.notion.so/Spec-1111", "last_edited_by": map[string]interface{}{"id": "u1"},
+				"properties": map[string]interface{}{"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Spec"}}}}})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	run := func(extra ...string) string {
+		t.Helper()
+		return captureStdout(t, func() {
+			args := append([]string{"page", "comment-on-change", "11111111111111111111111111111111", "--comment", "{{.EditedBy}} changed {{.Title}}"}, extra...)
+			if _, _, err := executeCommand(args...); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	if out := run(); !strings.Contains(out, "Saved the content of Spec") {
+		t.Errorf("first run = %q", out)
+	}
+	image = "https://files.example.com/a.png?X-Amz-Signature=2"
+	if out := run(); !strings.Contains(out, "hasn't changed") || len(comments) != 0 {
+		t.Errorf("re-signed file link counted as a change: %q, comments %v", out, comments)
+	}
+
+	text = "Final"
+	run("--dry-run")
+	if len(comments) != 0 {
+		t.Errorf("dry run commented: %v", comments)
+	}
+	run()
+	if len(comments) != 1 || !strings.Contains(comments[0], "Ada changed Spec") {
+		t.Errorf("comments = %v", comments)
+	}
+	if out := run(); !strings.Contains(out, "hasn't changed") || len(comments) != 1 {
+		t.Errorf("after commenting = %q", out)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	text = "Final, reviewed"
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "comment-on-change", "11111111111111111111111111111111", "--key", "hook", "--exec", `echo "$NOTION_PAGE_TITLE by $NOTION_EDITED_BY" > `+out); err != nil {
+			t.Fatal(err)
+		}
+		text = "Final, reviewed twice"
+		if _, _, err := executeCommand("page", "comment-on-change", "11111111111111111111111111111111", "--key", "hook", "--exec", `echo "$NOTION_PAGE_TITLE by $NOTION_EDITED_BY" > `+out); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if data, _ := os.ReadFile(out); strings.TrimSpace(string(data)) != "Spec by Ada" {
+		t.Errorf("--exec wrote %q", data)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// pageParent is somewhere a page can live: a page, a database or the
+// workspace root.
+type pageParent struct {
+	Kind string // "page_id", "database_id" or "workspace"
+	ID   string
+	// Schema holds a database parent's properties.
+	Schema map[string]interface{}
+}
+
+// resolvePageParent works out what arg names by fetching it, so commands
+// send the right parent object without being told. "workspace" is the
+// workspace root. Data sources aren't probed: the client speaks API
+// version 2022-06-28, where a database is its own (single) data source.
+func resolvePageParent(c *notion.Client, arg string) (pageParent, error) {
+	if strings.EqualFold(arg, "workspace") {
+		return pageParent{Kind: "workspace"}, nil
+	}
+	id := util.ResolveID(arg)
+	if db, err := c.GetDatabase(id); err == nil {
+		schema, _ := db["properties"].(map[string]interface{})
+		return pageParent{Kind: "database_id", ID: id, Schema: schema}, nil
+	}
+	if _, err := c.GetPage(id); err != nil {
+		return pageParent{}, fmt.Errorf("%s is neither a page nor a database the integration can see: %w", arg, err)
+	}
+	return pageParent{Kind: "page_id", ID: id}, nil
+}
+
+// object is the parent as the API takes it.
+func (p pageParent) object() map[string]interface{} {
+	if p.Kind == "workspace" {
+		return map[string]interface{}{"type": "workspace", "workspace": true}
+	}
+	return map[string]interface{}{"type": p.Kind, p.Kind: p.ID}
+}
+
+// String names the parent in messages.
+func (p pageParent) String() string {
+	switch p.Kind {
+	case "workspace":
+		return "the workspace"
+	case "database_id":
+		return "database " + p.ID
+	}
+	return "page " + p.ID
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"net/url"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
 	"github.com/spf13/cobra"
 )
 
@@ -50,7 +50,7 @@ Examples:
 		name, _ := cmd.Flags().GetString("name")
 		pageSize, _ := cmd.Flags().GetInt("page-size")
 		if pageSize < 1 || pageSize > 100 {
-			return fmt.Errorf("--page-size must be between 1 and 100")
+			return usageError(fmt.Errorf("--page-size must be between 1 and 100"))
 		}
 
 		var propID string
@@ -58,14 +58,13 @@ Examples:
 			propID = args[1]
 		}
 		if propID == "" && name == "" {
-			return fmt.Errorf("provide a property-id positional arg or use --name <property-name>")
+			return usageError(fmt.Errorf("provide a property-id positional arg or use --name <property-name>"))
 		}
 		if propID != "" && name != "" {
-			return fmt.Errorf("pass either a property-id positional arg OR --name, not both")
+			return usageError(fmt.Errorf("pass either a property-id positional arg OR --name, not both"))
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		// Resolve --name to an id by looking at the page's property map.
 		if name != "" {
@@ -96,7 +95,7 @@ Examples:
 // fetchPagePropertyAllPages walks every page of a page-property response
 // and returns a single merged object. For non-paginated property types
 // (title / number / select / ...) a single request is enough.
-func fetchPagePropertyAllPages(c *client.Client, pageID, propID string, pageSize int) (map[string]interface{}, error) {
+func fetchPagePropertyAllPages(c *notion.Client, pageID, propID string, pageSize int) (map[string]interface{}, error) {
 	basePath := fmt.Sprintf("/v1/pages/%s/properties/%s", pageID, propID)
 	var merged map[string]interface{}
 	var allResults []interface{}
@@ -186,7 +185,7 @@ func renderPageProperty(result map[string]interface{}) {
 
 	if obj == "property_item" {
 		// Single-value: print the extracted value directly.
-		value := extractPropertyValue(result)
+		value := displayPropertyValue(result)
 		render.Field("Value", value)
 		return
 	}
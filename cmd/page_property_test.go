@@ -7,7 +7,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/4ier/notion-cli/internal/client"
+	"github.com/4ier/notion-cli/pkg/notion"
 )
 
 func TestFindPropertyIDByName_Found(t *testing.T) {
@@ -129,7 +129,7 @@ func TestFetchPagePropertyAllPages_FollowsCursors(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := client.NewWithBaseURL("fake-token", srv.URL)
+	c := notion.New("fake-token", notion.WithBaseURL(srv.URL))
 	result, err := fetchPagePropertyAllPages(c, "page-x", "prop-y", 100)
 	if err != nil {
 		t.Fatal(err)
@@ -158,7 +158,7 @@ func TestFetchPagePropertyAllPages_PropertyItemNoPagination(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	c := client.NewWithBaseURL("fake-token", srv.URL)
+	c := notion.New("fake-token", notion.WithBaseURL(srv.URL))
 	result, err := fetchPagePropertyAllPages(c, "page-x", "prop-y", 100)
 	if err != nil {
 		t.Fatal(err)
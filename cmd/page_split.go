@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var pageSplitCmd = &cobra.Command{
+	Use:   "split <page-id|url>",
+	Short: "Split a page into child pages by heading",
+	Long: `Break a long page into child pages, one per section. Each heading
+of the --by level starts a section that runs until the next heading of the
+same or a higher level; the section's blocks are moved into a new child
+page titled after the heading, and the heading is replaced by the link to
+that page. Content before the first heading stays where it is.
+
+Blocks are moved, not copied, so comments and block links survive.
+
+Examples:
+  notion page split abc123
+  notion page split abc123 --by heading_2
+  notion page split abc123 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		by = mapBlockType(by)
+		if headingLevel(by) == 0 {
+			return usageError(fmt.Errorf("unknown --by %q (want heading_1, heading_2 or heading_3)", by))
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		pageID := util.ResolveID(args[0])
+
+		children, err := fetchBlockChildren(c, pageID, "", true)
+		if err != nil {
+			return fmt.Errorf("get page content: %w", err)
+		}
+		sections := splitSections(children, by)
+		if len(sections) == 0 {
+			return fmt.Errorf("page has no %s headings to split by", by)
+		}
+
+		if dryRun {
+			if outputFormat == "json" {
+				var out []map[string]interface{}
+				for _, s := range sections {
+					out = append(out, map[string]interface{}{"title": s.title, "blocks": len(s.blocks)})
+				}
+				return render.JSON(map[string]interface{}{"id": pageID, "pages": out})
+			}
+			fmt.Printf("Would create %d page(s):\n", len(sections))
+			for _, s := range sections {
+				fmt.Printf("  %s (%d block(s))\n", s.title, len(s.blocks))
+			}
+			return nil
+		}
+
+		var created []map[string]interface{}
+		for _, s := range sections {
+			page, err := splitSection(c, pageID, s)
+			if err != nil {
+				return fmt.Errorf("split %q: %w", s.title, err)
+			}
+			created = append(created, page)
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"id": pageID, "pages": created})
+		}
+		fmt.Printf("✓ Split into %d page(s)\n", len(created))
+		for _, p := range created {
+			url, _ := p["url"].(string)
+			fmt.Printf("  %s  %s\n", p["title"], notionLink(url))
+		}
+		return nil
+	},
+}
+
+func init() {
+	pageSplitCmd.Flags().String("by", "heading_1", "Heading level that starts a page: heading_1, heading_2, heading_3")
+	pageSplitCmd.Flags().Bool("dry-run", false, "Show the pages that would be created without changing anything")
+	pageCmd.AddCommand(pageSplitCmd)
+}
+
+// pageSection is a heading and the blocks after it, up to the next
+// heading of the same or a higher level.
+type pageSection struct {
+	title   string
+	heading map[string]interface{}
+	blocks  []map[string]interface{}
+}
+
+// headingLevel returns 1-3 for heading block types, and 0 otherwise.
+func headingLevel(blockType string) int {
+	switch blockType {
+	case "heading_1":
+		return 1
+	case "heading_2":
+		return 2
+	case "heading_3":
+		return 3
+	}
+	return 0
+}
+
+// splitSections groups a page's top-level blocks into sections starting
+// at each heading of type by. Blocks outside any section are left out.
+func splitSections(children []interface{}, by string) []pageSection {
+	level := headingLevel(by)
+	var sections []pageSection
+	var current *pageSection
+	for _, ch := range children {
+		block, ok := ch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		blockType, _ := block["type"].(string)
+		if l := headingLevel(blockType); l > 0 && l <= level {
+			current = nil
+			if l == level {
+				data, _ := block[blockType].(map[string]interface{})
+				rt, _ := data["rich_text"].([]interface{})
+				sections = append(sections, pageSection{title: firstNonEmpty(notion.PlainText(rt), "Untitled"), heading: block})
+				current = &sections[len(sections)-1]
+			}
+			continue
+		}
+		if current != nil {
+			current.blocks = append(current.blocks, block)
+		}
+	}
+	return sections
+}
+
+// splitSection creates the child page for s under pageID, moves the
+// section's blocks into it (the children of a toggle heading first), and
+// puts the page's link where the heading was.
+func splitSection(c *notion.Client, pageID string, s pageSection) (map[string]interface{}, error) {
+	data, err := c.Post("/v1/pages", map[string]interface{}{
+		"parent": map[string]interface{}{"page_id": pageID},
+		"properties": map[string]interface{}{
+			"title": buildPropertyValue("title", s.title),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create page: %w", err)
+	}
+	rememberCreated(data)
+	var page map[string]interface{}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	newID, _ := page["id"].(string)
+	url, _ := page["url"].(string)
+	headingID, _ := s.heading["id"].(string)
+
+	blocks := s.blocks
+	if hasChildren, _ := s.heading["has_children"].(bool); hasChildren {
+		nested, err := fetchBlockChildren(c, headingID, "", true)
+		if err != nil {
+			return nil, fmt.Errorf("get heading content: %w", err)
+		}
+		var inner []map[string]interface{}
+		for _, n := range nested {
+			if block, ok := n.(map[string]interface{}); ok {
+				inner = append(inner, block)
+			}
+		}
+		blocks = append(inner, blocks...)
+	}
+
+	after := ""
+	for i, block := range blocks {
+		id, _ := block["id"].(string)
+		body := map[string]interface{}{"parent": map[string]interface{}{"page_id": newID}}
+		if after != "" {
+			body["after"] = after
+		}
+		if _, err := c.Patch("/v1/blocks/"+id, body); err != nil {
+			return nil, fmt.Errorf("move block %d of %d: %w", i+1, len(blocks), err)
+		}
+		after = id
+	}
+
+	// The new page's child_page block is added at the end of the parent;
+	// move it to where the heading is, then drop the heading.
+	if _, err := c.Patch("/v1/blocks/"+newID, map[string]interface{}{
+		"parent": map[string]interface{}{"page_id": pageID},
+		"after":  headingID,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "note: %s: could not move the page link into place, leaving the heading: %s\n", s.title, firstLine(err))
+	} else if _, err := c.Delete("/v1/blocks/" + headingID); err != nil {
+		fmt.Fprintf(os.Stderr, "note: %s: could not remove the heading: %s\n", s.title, firstLine(err))
+	}
+	return map[string]interface{}{"id": newID, "title": s.title, "url": url, "blocks": len(blocks)}, nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func splitTestBlock(id, blockType, text string) map[string]interface{} {
+	return map[string]interface{}{"id": id, "type": blockType, blockType: map[string]interface{}{
+		"rich_text": []interface{}{map[string]interface{}{"plain_text": text}},
+	}}
+}
+
+func TestSplitSections(t *testing.T) {
+	children := []interface{}{
+		splitTestBlock("intro", "paragraph", "Intro"),
+		splitTestBlock("h1", "heading_1", "Part one"),
+		splitTestBlock("p1", "paragraph", "a"),
+		splitTestBlock("h2a", "heading_2", "Sub"),
+		splitTestBlock("p2", "paragraph", "b"),
+		splitTestBlock("h1b", "heading_1", "Part two"),
+		splitTestBlock("p3", "paragraph", "c"),
+	}
+
+	sections := splitSections(children, "heading_1")
+	if len(sections) != 2 || sections[0].title != "Part one" || len(sections[0].blocks) != 3 || len(sections[1].blocks) != 1 {
+		t.Fatalf("heading_1 sections = %+v", sections)
+	}
+
+	// A higher-level heading ends a heading_2 section.
+	sections = splitSections(children, "heading_2")
+	if len(sections) != 1 || sections[0].title != "Sub" || len(sections[0].blocks) != 1 {
+		t.Fatalf("heading_2 sections = %+v", sections)
+	}
+}
+
+func TestPageSplitCommand(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		mu.Unlock()
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				splitTestBlock("h1", "heading_1", "Part one"),
+				splitTestBlock("p1", "paragraph", "a"),
+				splitTestBlock("p2", "paragraph", "b"),
+			}})
+		case r.Method == "POST":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "new1", "url": "https://notion.so/new1"})
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	page := "11111111-1111-1111-1111-111111111111"
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "split", page); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Split into 1 page(s)") {
+		t.Errorf("unexpected output: %s", out)
+	}
+
+	want := []string{
+		"GET /v1/blocks/" + page + "/children ",
+		`POST /v1/pages {"parent":{"page_id":"` + page + `"},"properties":{"title":`,
+		`PATCH /v1/blocks/p1 {"parent":{"page_id":"new1"}}`,
+		`PATCH /v1/blocks/p2 {"after":"p1","parent":{"page_id":"new1"}}`,
+		`PATCH /v1/blocks/new1 {"after":"h1","parent":{"page_id":"` + page + `"}}`,
+		"DELETE /v1/blocks/h1 ",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("calls:\n%s", strings.Join(calls, "\n"))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(calls[i], w) {
+			t.Errorf("call %d = %s, want prefix %s", i, calls[i], w)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// readingWordsPerMinute is the reading speed behind the reading time
+// estimate of 'page stats'.
+const readingWordsPerMinute = 230
+
+var pageStatsCmd = &cobra.Command{
+	Use:   "stats <page-id|url>",
+	Short: "Show word count and reading stats for a page",
+	Long: `Count the words, characters and blocks of a page's content, including
+blocks nested in toggles, columns and lists. Child pages and databases are
+pages of their own and are not counted.
+
+Words are counted in prose only; code blocks are reported separately (with
+their line count) but their characters are included. Reading time assumes
+230 words a minute.
+
+Examples:
+  notion page stats abc123
+  notion page stats abc123 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		pageID := util.ResolveID(args[0])
+
+		page, err := c.GetPage(pageID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		blocks, err := flattenBlockTree(c, pageID)
+		if err != nil {
+			return fmt.Errorf("get page content: %w", err)
+		}
+		s := countPageStats(blocks)
+		s.ID = pageID
+		s.Title = render.ExtractTitle(page)
+
+		if outputFormat == "json" {
+			return render.JSON(s)
+		}
+		render.Title("📊", firstNonEmpty(s.Title, "Untitled"))
+		render.Field("Words", strconv.Itoa(s.Words))
+		render.Field("Characters", strconv.Itoa(s.Characters))
+		render.Field("Reading time", fmt.Sprintf("%d min", s.ReadingMinutes))
+		render.Field("Blocks", strconv.Itoa(s.Blocks))
+		render.Field("Headings", strconv.Itoa(s.Headings))
+		render.Field("Images", strconv.Itoa(s.Images))
+		render.Field("Code blocks", fmt.Sprintf("%d (%d lines)", s.CodeBlocks, s.CodeLines))
+		if len(s.Types) > 0 {
+			fmt.Println()
+			var rows [][]string
+			for _, t := range sortedBlockTypes(s.Types) {
+				rows = append(rows, []string{t, strconv.Itoa(s.Types[t])})
+			}
+			render.Table([]string{"TYPE", "BLOCKS"}, rows)
+		}
+		return nil
+	},
+}
+
+func init() {
+	pageCmd.AddCommand(pageStatsCmd)
+}
+
+// pageStats is the result of 'page stats'.
+type pageStats struct {
+	ID             string         `json:"id"`
+	Title          string         `json:"title"`
+	Words          int            `json:"words"`
+	Characters     int            `json:"characters"`
+	ReadingMinutes int            `json:"reading_minutes"`
+	Blocks         int            `json:"blocks"`
+	Headings       int            `json:"headings"`
+	Images         int            `json:"images"`
+	CodeBlocks     int            `json:"code_blocks"`
+	CodeLines      int            `json:"code_lines"`
+	Types          map[string]int `json:"types"`
+}
+
+// countPageStats tallies a page's flattened block tree.
+func countPageStats(blocks []map[string]interface{}) *pageStats {
+	s := &pageStats{Types: map[string]int{}}
+	for _, block := range blocks {
+		blockType, _ := block["type"].(string)
+		s.Blocks++
+		s.Types[blockType]++
+
+		text := blockText(block)
+		s.Characters += utf8.RuneCountInString(text)
+		switch {
+		case blockType == "code":
+			s.CodeBlocks++
+			if text != "" {
+				s.CodeLines += strings.Count(strings.TrimRight(text, "\n"), "\n") + 1
+			}
+		case blockType == "image":
+			s.Images++
+		case headingLevel(blockType) > 0:
+			s.Headings++
+			s.Words += len(strings.Fields(text))
+		default:
+			s.Words += len(strings.Fields(text))
+		}
+	}
+	if s.Words > 0 {
+		s.ReadingMinutes = (s.Words + readingWordsPerMinute - 1) / readingWordsPerMinute
+	}
+	return s
+}
+
+// blockText returns the plain text of a block: its rich text, or the
+// cells of a table row.
+func blockText(block map[string]interface{}) string {
+	blockType, _ := block["type"].(string)
+	data, _ := block[blockType].(map[string]interface{})
+	if blockType == "table_row" {
+		cells, _ := data["cells"].([]interface{})
+		var parts []string
+		for _, cell := range cells {
+			rt, _ := cell.([]interface{})
+			parts = append(parts, notion.PlainText(rt))
+		}
+		return strings.Join(parts, " ")
+	}
+	rt, _ := data["rich_text"].([]interface{})
+	return notion.PlainText(rt)
+}
+
+// sortedBlockTypes orders block types by count, most common first.
+func sortedBlockTypes(types map[string]int) []string {
+	names := make([]string, 0, len(types))
+	for t := range types {
+		names = append(names, t)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if types[names[i]] != types[names[j]] {
+			return types[names[i]] > types[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
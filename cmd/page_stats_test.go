@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountPageStats(t *testing.T) {
+	blocks := []map[string]interface{}{
+		splitTestBlock("h", "heading_1", "Release notes"),
+		splitTestBlock("p", "paragraph", "We shipped three small fixes."),
+		splitTestBlock("c", "code", "go test ./...\ngo vet ./...\n"),
+		{"id": "i", "type": "image", "image": map[string]interface{}{}},
+		{"id": "r", "type": "table_row", "table_row": map[string]interface{}{"cells": []interface{}{
+			[]interface{}{map[string]interface{}{"plain_text": "Fix"}},
+			[]interface{}{map[string]interface{}{"plain_text": "login bug"}},
+		}}},
+		splitTestBlock("p2", "paragraph", ""),
+	}
+	s := countPageStats(blocks)
+	if s.Words != 10 || s.Headings != 1 || s.Images != 1 || s.CodeBlocks != 1 || s.CodeLines != 2 || s.Blocks != 6 || s.ReadingMinutes != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+	if want := len("Release notes") + len("We shipped three small fixes.") + len("go test ./...\ngo vet ./...\n") + len("Fix login bug"); s.Characters != want {
+		t.Errorf("characters = %d, want %d", s.Characters, want)
+	}
+	if got := sortedBlockTypes(s.Types); !reflect.DeepEqual(got, []string{"paragraph", "code", "heading_1", "image", "table_row"}) {
+		t.Errorf("types = %v", got)
+	}
+
+	long := countPageStats([]map[string]interface{}{splitTestBlock("p", "paragraph", strings.Repeat("word ", 231))})
+	if long.ReadingMinutes != 2 {
+		t.Errorf("reading minutes = %d, want 2", long.ReadingMinutes)
+	}
+}
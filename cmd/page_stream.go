@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// errStopStream, returned by an emit function, ends streamBlocks early
+// without an error.
+var errStopStream = errors.New("stop streaming")
+
+// streamBlocks calls emit with each block under parentID, and its depth,
+// as soon as the batch holding it arrives, so a page of thousands of
+// blocks starts printing at once instead of after the whole tree is
+// fetched. Every batch is read; with nested, blocks nested in others
+// follow their parent depth first (the contents of child pages and
+// databases are not entered).
+func streamBlocks(c *notion.Client, parentID string, depth int, nested bool, emit func(block map[string]interface{}, depth int) error) error {
+	err := walkBlocks(c, parentID, depth, nested, emit)
+	if errors.Is(err, errStopStream) {
+		return nil
+	}
+	return err
+}
+
+func walkBlocks(c *notion.Client, parentID string, depth int, nested bool, emit func(block map[string]interface{}, depth int) error) error {
+	cursor := ""
+	for {
+		result, err := c.GetBlockChildren(parentID, 100, cursor)
+		if err != nil {
+			return err
+		}
+		results, _ := result["results"].([]interface{})
+		for _, b := range results {
+			block, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := emit(block, depth); err != nil {
+				return err
+			}
+			blockType, _ := block["type"].(string)
+			hasChildren, _ := block["has_children"].(bool)
+			if !nested || !hasChildren || blockType == "child_page" || blockType == "child_database" {
+				continue
+			}
+			id, _ := block["id"].(string)
+			if err := walkBlocks(c, id, depth+1, nested, emit); err != nil {
+				return err
+			}
+		}
+		hasMore, _ := result["has_more"].(bool)
+		cursor, _ = result["next_cursor"].(string)
+		if !hasMore || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// limitBlocks wraps emit to stop after limit top-level blocks (0 = no
+// limit), setting *truncated when there were more.
+func limitBlocks(limit int, truncated *bool, emit func(block map[string]interface{}, depth int) error) func(block map[string]interface{}, depth int) error {
+	if limit <= 0 {
+		return emit
+	}
+	shown := 0
+	return func(block map[string]interface{}, depth int) error {
+		if depth == 0 {
+			if shown == limit {
+				*truncated = true
+				return errStopStream
+			}
+			shown++
+		}
+		return emit(block, depth)
+	}
+}
+
+// blockTree returns the blocks under parentID, up to limit top-level ones
+// (0 = all) and whether there were more. With nested, blocks nested in
+// others are under their parent's "_children", as fetchNestedBlocks
+// leaves them.
+func blockTree(c *notion.Client, parentID string, nested bool, limit int) ([]interface{}, bool, error) {
+	top := []interface{}{}
+	var parents []map[string]interface{}
+	truncated := false
+	err := streamBlocks(c, parentID, 0, nested, limitBlocks(limit, &truncated, func(block map[string]interface{}, depth int) error {
+		parents = append(parents[:depth], block)
+		if depth == 0 {
+			top = append(top, block)
+			return nil
+		}
+		parent := parents[depth-1]
+		children, _ := parent["_children"].([]interface{})
+		parent["_children"] = append(children, block)
+		return nil
+	}))
+	return top, truncated, err
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPageViewStreamsAllBlocks(t *testing.T) {
+	const pageID = "11111111-1111-1111-1111-111111111111"
+	para := func(id, text string, children bool) map[string]interface{} {
+		return map[string]interface{}{"object": "block", "id": id, "type": "paragraph", "has_children": children,
+			"paragraph": map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"plain_text": text}}}}
+	}
+	var requests []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		requests = append(requests, r.URL.Path+"?"+r.URL.Query().Get("start_cursor"))
+		switch {
+		case r.URL.Path == "/v1/blocks/"+pageID+"/children" && r.URL.Query().Get("start_cursor") == "":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{para("b1", "First", true)}, "has_more": true, "next_cursor": "c2"})
+		case r.URL.Path == "/v1/blocks/"+pageID+"/children":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{para("b3", "Third", false)}})
+		case r.URL.Path == "/v1/blocks/b1/children":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{para("b2", "Nested", false)}})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": pageID, "properties": map[string]interface{}{}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	// Each block is emitted before the next batch is requested.
+	var seen []string
+	err := streamBlocks(newClient("secret_test"), pageID, 0, true, func(block map[string]interface{}, depth int) error {
+		seen = append(seen, fmt.Sprintf("%s@%d after %d request(s)", block["id"], depth, len(requests)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "b1@0 after 1 request(s),b2@1 after 2 request(s),b3@0 after 3 request(s)"
+	if got := strings.Join(seen, ","); got != want {
+		t.Errorf("emitted %s, want %s", got, want)
+	}
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "view", pageID, "--raw-blocks", "--all"); err != nil {
+			t.Fatalf("page view --raw-blocks: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], `"id":"b2"`) {
+		t.Errorf("raw blocks:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "view", pageID, "--raw-blocks=false", "--all", "--format", "md"); err != nil {
+			t.Fatalf("page view --all: %v", err)
+		}
+	})
+	if !strings.Contains(out, "First\n\n  Nested\n\nThird") {
+		t.Errorf("markdown:\n%s", out)
+	}
+
+	tree, more, err := blockTree(newClient("secret_test"), pageID, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := tree[0].(map[string]interface{})
+	if children, _ := first["_children"].([]interface{}); len(tree) != 2 || len(children) != 1 || more {
+		t.Errorf("tree = %v, more %v", tree, more)
+	}
+}
+
+func TestPageViewPaginatesByDefault(t *testing.T) {
+	const pageID = "11111111-1111-1111-1111-111111111111"
+	batch := func(from, to int) []interface{} {
+		var blocks []interface{}
+		for i := from; i <= to; i++ {
+			blocks = append(blocks, map[string]interface{}{"object": "block", "id": fmt.Sprintf("b%d", i), "type": "paragraph", "has_children": true,
+				"paragraph": map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"plain_text": fmt.Sprintf("Line %d", i)}}}})
+		}
+		return blocks
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path != "/v1/blocks/"+pageID+"/children":
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": pageID, "properties": map[string]interface{}{}})
+		case r.URL.Query().Get("start_cursor") == "":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": batch(1, 100), "has_more": true, "next_cursor": "c2"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": batch(101, 150)})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "view", pageID, "--all=false", "--raw-blocks=false", "--format", "md"); err != nil {
+			t.Fatalf("page view: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Line 100\n") || !strings.Contains(out, "Line 150\n") {
+		t.Errorf("blocks past the first 100 are missing:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "view", pageID, "--limit", "120", "--format", "md"); err != nil {
+			t.Fatalf("page view --limit: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Line 120\n") || strings.Contains(out, "Line 121\n") {
+		t.Errorf("--limit 120:\n%s", out)
+	}
+	captureStdout(t, func() { executeCommand("page", "view", pageID, "--limit", "0") })
+}
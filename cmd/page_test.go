@@ -46,6 +46,21 @@ func TestBuildPropertyValue(t *testing.T) {
 	}
 }
 
+func TestBuildPropertyValueNewerTypes(t *testing.T) {
+	v := buildPropertyValue("verification", "Unverified").(map[string]interface{})
+	if state := v["verification"].(map[string]interface{})["state"]; state != "unverified" {
+		t.Errorf("verification state = %v, want unverified", state)
+	}
+	v = buildPropertyValue("verification", "verified/2026-12-31").(map[string]interface{})
+	if d := v["verification"].(map[string]interface{})["date"].(map[string]interface{}); d["end"] != "2026-12-31" {
+		t.Errorf("verification date = %v, want end 2026-12-31", d)
+	}
+	p := buildPropertyValue("place", "48.8584,2.2945 Eiffel Tower").(map[string]interface{})["place"].(map[string]interface{})
+	if p["lat"] != 48.8584 || p["lon"] != 2.2945 || p["name"] != "Eiffel Tower" {
+		t.Errorf("place = %v", p)
+	}
+}
+
 func TestBuildPropertyValueCheckbox(t *testing.T) {
 	// Verify checkbox boolean values
 	trueInputs := []string{"true", "1", "yes"}
@@ -263,6 +278,33 @@ func TestExtractPropertyValue(t *testing.T) {
 			},
 			want: "2026-02-19T00:00:00.000Z",
 		},
+		{
+			name: "verification",
+			prop: map[string]interface{}{
+				"type": "verification",
+				"verification": map[string]interface{}{
+					"state": "verified",
+					"date":  map[string]interface{}{"start": "2026-02-19", "end": "2026-05-19"},
+				},
+			},
+			want: "verified until 2026-05-19",
+		},
+		{
+			name: "button",
+			prop: map[string]interface{}{
+				"type":   "button",
+				"button": map[string]interface{}{},
+			},
+			want: "[button]",
+		},
+		{
+			name: "place",
+			prop: map[string]interface{}{
+				"type":  "place",
+				"place": map[string]interface{}{"lat": 48.8584, "lon": 2.2945},
+			},
+			want: "48.8584, 2.2945",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var pageTocCmd = &cobra.Command{
+	Use:   "toc <page-id|url>",
+	Short: "Print or insert a page's table of contents",
+	Long: `Print a page's headings as a nested Markdown list, each linking to
+its heading. Headings inside toggles and columns are included.
+
+--insert adds Notion's table of contents block at the top of the page
+(it keeps itself up to date, so it is only added once). --number numbers
+the headings consistently (1, 1.1, 1.1.1), replacing any numbers they
+already start with; run it again after moving sections around.
+
+Examples:
+  notion page toc abc123
+  notion page toc abc123 --insert
+  notion page toc abc123 --number
+  notion page toc abc123 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		insert, _ := cmd.Flags().GetBool("insert")
+		number, _ := cmd.Flags().GetBool("number")
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		pageID := util.ResolveID(args[0])
+
+		page, err := c.GetPage(pageID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		pageURL, _ := page["url"].(string)
+		blocks, err := flattenBlockTree(c, pageID)
+		if err != nil {
+			return fmt.Errorf("get page content: %w", err)
+		}
+		entries := tocEntries(blocks)
+
+		renumbered := 0
+		if number {
+			numberHeadings(entries)
+			for _, e := range entries {
+				rt, changed := numberedRichText(e.richText, e.Number)
+				if !changed {
+					continue
+				}
+				if _, err := c.Patch("/v1/blocks/"+e.ID, map[string]interface{}{
+					e.blockType: map[string]interface{}{"rich_text": rt},
+				}); err != nil {
+					return fmt.Errorf("number heading %q: %w", e.Text, err)
+				}
+				e.Text = e.Number + " " + headingNumberRe.ReplaceAllString(e.Text, "")
+				renumbered++
+			}
+		}
+
+		inserted := false
+		if insert {
+			inserted, err = insertTOCBlock(c, pageID)
+			if err != nil {
+				return err
+			}
+		}
+
+		if outputFormat == "json" {
+			result := map[string]interface{}{"id": pageID, "headings": entries}
+			if number {
+				result["renumbered"] = renumbered
+			}
+			if insert {
+				result["inserted"] = inserted
+			}
+			return render.JSON(result)
+		}
+		if number {
+			fmt.Printf("✓ Numbered %d heading(s) (%d changed)\n", len(entries), renumbered)
+		}
+		if insert {
+			if inserted {
+				fmt.Println("✓ Inserted a table of contents at the top of the page")
+			} else {
+				fmt.Println("✓ The page already starts with a table of contents")
+			}
+		}
+		if number || insert {
+			return nil
+		}
+		if len(entries) == 0 {
+			fmt.Println("No headings.")
+			return nil
+		}
+		fmt.Print(tocMarkdown(entries, pageURL))
+		return nil
+	},
+}
+
+func init() {
+	pageTocCmd.Flags().Bool("insert", false, "Insert a table of contents block at the top of the page")
+	pageTocCmd.Flags().Bool("number", false, "Number the page's headings (1, 1.1, 1.1.1)")
+	pageCmd.AddCommand(pageTocCmd)
+}
+
+// tocEntry is one heading of a page.
+type tocEntry struct {
+	ID     string `json:"id"`
+	Level  int    `json:"level"`
+	Text   string `json:"text"`
+	Number string `json:"number,omitempty"`
+
+	blockType string
+	richText  []interface{}
+}
+
+// headingNumberRe matches a number a heading already starts with, such
+// as "2. " or "1.3 ".
+var headingNumberRe = regexp.MustCompile(`^\d+(\.\d+)*\.?\s+`)
+
+// tocEntries returns the headings among blocks, in document order.
+func tocEntries(blocks []map[string]interface{}) []*tocEntry {
+	var entries []*tocEntry
+	for _, block := range blocks {
+		blockType, _ := block["type"].(string)
+		level := headingLevel(blockType)
+		if level == 0 {
+			continue
+		}
+		id, _ := block["id"].(string)
+		data, _ := block[blockType].(map[string]interface{})
+		rt, _ := data["rich_text"].([]interface{})
+		entries = append(entries, &tocEntry{ID: id, Level: level, Text: notion.PlainText(rt), blockType: blockType, richText: rt})
+	}
+	return entries
+}
+
+// numberHeadings sets the outline number of each entry. The shallowest
+// heading level on the page is the top level, and a level skipped on the
+// way down counts as 1, so a heading_3 right under a heading_1 is "1.1.1".
+func numberHeadings(entries []*tocEntry) {
+	top := 3
+	for _, e := range entries {
+		if e.Level < top {
+			top = e.Level
+		}
+	}
+	counters := make([]int, 4)
+	for _, e := range entries {
+		depth := e.Level - top
+		for i := 0; i < depth; i++ {
+			if counters[i] == 0 {
+				counters[i] = 1
+			}
+		}
+		counters[depth]++
+		for i := depth + 1; i < len(counters); i++ {
+			counters[i] = 0
+		}
+		parts := make([]string, depth+1)
+		for i := range parts {
+			parts[i] = strconv.Itoa(counters[i])
+		}
+		e.Number = strings.Join(parts, ".")
+	}
+}
+
+// numberedRichText returns rt with its leading number replaced by number,
+// keeping the formatting of the rest, and whether that changes anything.
+func numberedRichText(rt []interface{}, number string) ([]interface{}, bool) {
+	text := notion.PlainText(rt)
+	stripped := headingNumberRe.ReplaceAllString(text, "")
+	if number+" "+stripped == text {
+		return rt, false
+	}
+
+	prefix := len(text) - len(stripped)
+	out := []interface{}{map[string]interface{}{"type": "text", "text": map[string]interface{}{"content": number + " "}}}
+	for _, item := range rt {
+		m, _ := item.(map[string]interface{})
+		plain, _ := m["plain_text"].(string)
+		if prefix == 0 || m["type"] != "text" {
+			out = append(out, item)
+			continue
+		}
+		cut := prefix
+		if cut > len(plain) {
+			cut = len(plain)
+		}
+		prefix -= cut
+		if cut == len(plain) {
+			continue
+		}
+		rest := map[string]interface{}{}
+		for k, v := range m {
+			rest[k] = v
+		}
+		textObj, _ := m["text"].(map[string]interface{})
+		newText := map[string]interface{}{}
+		for k, v := range textObj {
+			newText[k] = v
+		}
+		newText["content"] = plain[cut:]
+		rest["text"] = newText
+		rest["plain_text"] = plain[cut:]
+		out = append(out, rest)
+	}
+	return out, true
+}
+
+// tocMarkdown renders entries as a nested Markdown list of links to the
+// headings on the page at pageURL.
+func tocMarkdown(entries []*tocEntry, pageURL string) string {
+	top := 3
+	for _, e := range entries {
+		if e.Level < top {
+			top = e.Level
+		}
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		title := e.Text
+		if e.Number != "" {
+			title = e.Number + " " + headingNumberRe.ReplaceAllString(title, "")
+		}
+		indent := strings.Repeat("  ", e.Level-top)
+		if pageURL == "" {
+			fmt.Fprintf(&b, "%s- %s\n", indent, title)
+			continue
+		}
+		fmt.Fprintf(&b, "%s- [%s](%s#%s)\n", indent, title, pageURL, plainID(e.ID))
+	}
+	return b.String()
+}
+
+// insertTOCBlock puts a table_of_contents block at the top of the page,
+// unless it already starts with one. Blocks can only be inserted after
+// another block, so it goes after the first block, which is then moved
+// below it.
+func insertTOCBlock(c *notion.Client, pageID string) (bool, error) {
+	result, err := c.GetBlockChildren(pageID, 1, "")
+	if err != nil {
+		return false, fmt.Errorf("get page content: %w", err)
+	}
+	toc := []map[string]interface{}{{"object": "block", "type": "table_of_contents", "table_of_contents": map[string]interface{}{}}}
+	results, _ := result["results"].([]interface{})
+	if len(results) == 0 {
+		if _, err := appendChildrenBatched(c, pageID, "", toc); err != nil {
+			return false, fmt.Errorf("insert table of contents: %w", err)
+		}
+		return true, nil
+	}
+
+	first, _ := results[0].(map[string]interface{})
+	if first["type"] == "table_of_contents" {
+		return false, nil
+	}
+	firstID, _ := first["id"].(string)
+	data, err := appendChildrenBatched(c, pageID, firstID, toc)
+	if err != nil {
+		return false, fmt.Errorf("insert table of contents: %w", err)
+	}
+	var appended struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &appended); err != nil || len(appended.Results) == 0 {
+		return false, fmt.Errorf("insert table of contents: unexpected response")
+	}
+	tocID, _ := appended.Results[0]["id"].(string)
+	if _, err := c.Patch("/v1/blocks/"+firstID, map[string]interface{}{
+		"parent": map[string]interface{}{"page_id": pageID},
+		"after":  tocID,
+	}); err != nil {
+		return false, fmt.Errorf("move the first block below the table of contents: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNumberHeadings(t *testing.T) {
+	entries := []*tocEntry{{Level: 2}, {Level: 3}, {Level: 3}, {Level: 2}, {Level: 3}}
+	numberHeadings(entries)
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Number)
+	}
+	want := []string{"1", "1.1", "1.2", "2", "2.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A skipped level counts as 1.
+	entries = []*tocEntry{{Level: 1}, {Level: 3}, {Level: 2}}
+	numberHeadings(entries)
+	if entries[1].Number != "1.1.1" || entries[2].Number != "1.2" {
+		t.Errorf("skipped level: got %s, %s", entries[1].Number, entries[2].Number)
+	}
+}
+
+func TestNumberedRichText(t *testing.T) {
+	text := func(s string, bold bool) map[string]interface{} {
+		return map[string]interface{}{"type": "text", "plain_text": s, "text": map[string]interface{}{"content": s},
+			"annotations": map[string]interface{}{"bold": bold}}
+	}
+	rt := []interface{}{text("3. Set", false), text("up", true)}
+	got, changed := numberedRichText(rt, "1.2")
+	if !changed {
+		t.Fatal("expected a change")
+	}
+	b, _ := json.Marshal(got)
+	want := `[{"text":{"content":"1.2 "},"type":"text"},{"annotations":{"bold":false},"plain_text":"Set","text":{"content":"Set"},"type":"text"},{"annotations":{"bold":true},"plain_text":"up","text":{"content":"up"},"type":"text"}]`
+	if string(b) != want {
+		t.Errorf("got %s\nwant %s", b, want)
+	}
+
+	if _, changed := numberedRichText([]interface{}{text("1.2 Setup", false)}, "1.2"); changed {
+		t.Error("already numbered heading should be left alone")
+	}
+}
+
+func TestTocMarkdown(t *testing.T) {
+	entries := []*tocEntry{
+		{ID: "aaaa-1", Level: 2, Text: "Intro"},
+		{ID: "bbbb-2", Level: 3, Text: "Details"},
+	}
+	got := tocMarkdown(entries, "https://notion.so/Page-123")
+	want := "- [Intro](https://notion.so/Page-123#aaaa1)\n  - [Details](https://notion.so/Page-123#bbbb2)\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInsertTOCBlock(t *testing.T) {
+	var calls []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"id": "first", "type": "paragraph"},
+			}})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"id": "toc", "type": "table_of_contents"},
+			}})
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+
+	inserted, err := insertTOCBlock(newClient("secret_test"), "page")
+	if err != nil || !inserted {
+		t.Fatalf("inserted=%v err=%v", inserted, err)
+	}
+	want := []string{
+		"GET /v1/blocks/page/children ",
+		`PATCH /v1/blocks/page/children {"after":"first","children":[{"object":"block","table_of_contents":{},"type":"table_of_contents"}]}`,
+		`PATCH /v1/blocks/first {"after":"toc","parent":{"page_id":"page"}}`,
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("calls:\n%s", strings.Join(calls, "\n"))
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %s\nwant %s", i, calls[i], want[i])
+		}
+	}
+}
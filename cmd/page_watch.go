@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// propChange is one property value change seen by 'page props --watch'.
+type propChange struct {
+	Time     time.Time `json:"time"`
+	Property string    `json:"property"`
+	Old      string    `json:"old"`
+	New      string    `json:"new"`
+}
+
+func init() {
+	pagePropsCmd.Flags().Bool("watch", false, "Poll the page and print property changes until interrupted")
+	pagePropsCmd.Flags().Duration("interval", 10*time.Second, "Time between polls with --watch")
+}
+
+// runPropsWatch is 'page props --watch': it polls until Ctrl-C and prints
+// each change as it is seen, as text lines or JSON Lines.
+func runPropsWatch(c *notion.Client, pageID string, interval time.Duration) error {
+	if interval < time.Second {
+		return usageError(fmt.Errorf("--interval must be at least 1s"))
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return watchPageProps(ctx, c.WithContext(ctx), pageID, interval, func(title string) {
+		fmt.Fprintf(os.Stderr, "Watching %s every %s (Ctrl-C to stop)\n", title, interval)
+	}, func(ch propChange) {
+		printPropChange(os.Stdout, ch)
+	})
+}
+
+// watchPageProps fetches the page every interval until ctx is done and
+// calls onChange for every property whose value differs from the previous
+// poll. onStart is called once with the page title after the first fetch.
+// Failed polls are reported and retried at the next tick, except when the
+// page is gone or the token rejected.
+func watchPageProps(ctx context.Context, c *notion.Client, pageID string, interval time.Duration, onStart func(string), onChange func(propChange)) error {
+	page, err := c.GetPage(pageID)
+	if err != nil {
+		return fmt.Errorf("get page: %w", err)
+	}
+	onStart(render.ExtractTitle(page))
+	last := propSnapshot(page)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		page, err := c.GetPage(pageID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, notion.ErrNotFound) || errors.Is(err, notion.ErrUnauthorized) {
+				return fmt.Errorf("get page: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "note: poll failed: %s\n", firstLine(err))
+			continue
+		}
+		now := time.Now()
+		current := propSnapshot(page)
+		for _, ch := range diffSnapshots(last, current) {
+			ch.Time = now
+			onChange(ch)
+		}
+		last = current
+	}
+}
+
+// propSnapshot renders every property of page as text.
+func propSnapshot(page map[string]interface{}) map[string]string {
+	props, _ := page["properties"].(map[string]interface{})
+	snap := make(map[string]string, len(props))
+	for name, v := range props {
+		if prop, ok := v.(map[string]interface{}); ok {
+			snap[name] = extractPropertyValue(prop)
+		}
+	}
+	return snap
+}
+
+// diffSnapshots lists the properties that changed, were added or were
+// removed between two snapshots, in name order.
+func diffSnapshots(before, after map[string]string) []propChange {
+	names := map[string]interface{}{}
+	for name := range before {
+		names[name] = nil
+	}
+	for name := range after {
+		names[name] = nil
+	}
+	var changes []propChange
+	for _, name := range sortedKeys(names) {
+		if before[name] != after[name] {
+			changes = append(changes, propChange{Property: name, Old: before[name], New: after[name]})
+		}
+	}
+	return changes
+}
+
+func printPropChange(w io.Writer, ch propChange) {
+	if outputFormat == "json" {
+		data, _ := json.Marshal(ch)
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	fmt.Fprintf(w, "%s  %s: %s → %s\n", ch.Time.Format("15:04:05"), ch.Property, orDash(ch.Old), orDash(ch.New))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestWatchPageProps(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "Todo"
+		if polls >= 3 {
+			status = "Done"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": "page",
+			"id":     "p1",
+			"properties": map[string]interface{}{
+				"Name":   map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Task"}}},
+				"Status": map[string]interface{}{"type": "select", "select": map[string]interface{}{"name": status}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c := notion.New("secret_test", notion.WithBaseURL(server.URL)).WithContext(ctx)
+
+	var title string
+	var changes []propChange
+	err := watchPageProps(ctx, c, "p1", 10*time.Millisecond, func(s string) { title = s }, func(ch propChange) {
+		changes = append(changes, ch)
+		cancel()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Task" {
+		t.Errorf("title = %q", title)
+	}
+	if len(changes) != 1 || changes[0].Property != "Status" || changes[0].Old != "Todo" || changes[0].New != "Done" || changes[0].Time.IsZero() {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	got := diffSnapshots(map[string]string{"A": "1", "B": "x"}, map[string]string{"A": "2", "C": "new"})
+	if len(got) != 3 || got[0].Property != "A" || got[1].Property != "B" || got[1].New != "" || got[2].Old != "" {
+		t.Errorf("diffSnapshots = %+v", got)
+	}
+}
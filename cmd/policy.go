@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// errPolicy is wrapped by the error of every write refused by the
+// write_allow / write_deny lists.
+var errPolicy = errors.New("write policy")
+
+// writePolicy is the pages and databases writes may touch: anything in or
+// under an allowed ID (everything when Allow is empty), except what is in
+// or under a denied one.
+type writePolicy struct {
+	Allow, Deny []string
+}
+
+// loadWritePolicy reads NOTION_WRITE_ALLOW and NOTION_WRITE_DENY, else the
+// write_allow and write_deny settings (comma-separated IDs).
+func loadWritePolicy() writePolicy {
+	allow, deny := os.Getenv("NOTION_WRITE_ALLOW"), os.Getenv("NOTION_WRITE_DENY")
+	if allow == "" && deny == "" {
+		if cfg, err := config.Load(); err == nil {
+			allow, deny = cfg.Setting("write_allow"), cfg.Setting("write_deny")
+		}
+	}
+	var p writePolicy
+	for _, id := range splitIDList(allow) {
+		p.Allow = append(p.Allow, util.ResolveID(id))
+	}
+	for _, id := range splitIDList(deny) {
+		p.Deny = append(p.Deny, util.ResolveID(id))
+	}
+	return p
+}
+
+// check returns why target may not be written to, or "" when it may.
+// ancestors lists what target lives in, innermost first.
+func (p writePolicy) check(target string, ancestors []string) string {
+	chain := append([]string{target}, ancestors...)
+	for _, id := range chain {
+		for _, denied := range p.Deny {
+			if sameID(id, denied) {
+				if sameID(id, target) {
+					return fmt.Sprintf("%s is in write_deny", target)
+				}
+				return fmt.Sprintf("%s is under %s, which is in write_deny", target, denied)
+			}
+		}
+	}
+	if len(p.Allow) == 0 {
+		return ""
+	}
+	for _, id := range chain {
+		for _, allowed := range p.Allow {
+			if sameID(id, allowed) {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf("%s is outside the write_allow list", target)
+}
+
+// writeTargetRe picks the object a write changes out of its path.
+var writeTargetRe = regexp.MustCompile(`^/v1/(?:pages|blocks|databases)/([0-9a-fA-F-]{32,36})`)
+
+// writeTargets returns the objects a write request changes or creates
+// something under: the object in its path and the parent in its body.
+// "workspace" stands for the workspace root.
+func writeTargets(req *http.Request) []string {
+	var targets []string
+	path := apiPath(req)
+	if m := writeTargetRe.FindStringSubmatch(path); m != nil {
+		targets = append(targets, m[1])
+	}
+	if req.GetBody == nil {
+		return targets
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return targets
+	}
+	data, _ := io.ReadAll(body)
+	var b struct {
+		Parent map[string]interface{} `json:"parent"`
+	}
+	if json.Unmarshal(data, &b) != nil || b.Parent == nil {
+		return targets
+	}
+	kind, _ := b.Parent["type"].(string)
+	if kind == "" {
+		for _, k := range []string{"page_id", "database_id", "block_id", "workspace"} {
+			if b.Parent[k] != nil {
+				kind = k
+			}
+		}
+	}
+	if kind == "workspace" {
+		return append(targets, "workspace")
+	}
+	if id, _ := b.Parent[kind].(string); id != "" {
+		targets = append(targets, id)
+	}
+	return targets
+}
+
+// enforcePolicy is client middleware refusing writes outside the write
+// policy before they are sent. Where a target lives is looked up through
+// the rest of the chain, once per object per client; a target whose
+// place can't be found is refused.
+func enforcePolicy(next notion.Handler) notion.Handler {
+	var mu sync.Mutex
+	parents := map[string]string{}
+	return func(req *http.Request) (*http.Response, error) {
+		if !isWrite(req) {
+			return next(req)
+		}
+		policy := loadWritePolicy()
+		if len(policy.Allow) == 0 && len(policy.Deny) == 0 {
+			return next(req)
+		}
+		for _, target := range writeTargets(req) {
+			if target == "workspace" {
+				if len(policy.Allow) > 0 {
+					return nil, fmt.Errorf("%w: %s %s writes to the workspace root, outside the write_allow list", errPolicy, req.Method, apiPath(req))
+				}
+				continue
+			}
+			var ancestors []string
+			id := target
+			for depth := 0; depth < 50; depth++ {
+				mu.Lock()
+				parent, ok := parents[id]
+				mu.Unlock()
+				if !ok {
+					var err error
+					if parent, err = lookupParent(next, req, id); err != nil {
+						return nil, fmt.Errorf("%w: can't tell where %s lives: %v", errPolicy, id, err)
+					}
+					mu.Lock()
+					parents[id] = parent
+					mu.Unlock()
+				}
+				if parent == "" {
+					break
+				}
+				ancestors = append(ancestors, parent)
+				id = parent
+			}
+			if reason := policy.check(target, ancestors); reason != "" {
+				return nil, fmt.Errorf("%w: refusing %s %s: %s", errPolicy, req.Method, apiPath(req), reason)
+			}
+		}
+		return next(req)
+	}
+}
+
+// lookupParent returns the ID of the page, database or block id lives in
+// ("" at the workspace root), asking the API with the credentials of req.
+func lookupParent(send notion.Handler, req *http.Request, id string) (string, error) {
+	base := req.URL.String()
+	if i := strings.Index(base, "/v1/"); i >= 0 {
+		base = base[:i]
+	}
+	var lastErr error
+	for _, kind := range []string{"blocks", "databases"} {
+		get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, base+"/v1/"+kind+"/"+id, nil)
+		if err != nil {
+			return "", err
+		}
+		get.Header.Set("Authorization", req.Header.Get("Authorization"))
+		get.Header.Set("Notion-Version", req.Header.Get("Notion-Version"))
+		resp, err := send(get)
+		if err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("GET /v1/%s/%s: %s", kind, id, resp.Status)
+			continue
+		}
+		var obj struct {
+			Parent map[string]interface{} `json:"parent"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return "", err
+		}
+		kind, _ := obj.Parent["type"].(string)
+		parent, _ := obj.Parent[kind].(string)
+		return parent, nil
+	}
+	return "", lastErr
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWritePolicy(t *testing.T) {
+	const (
+		rootID  = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+		otherID = "33333333-3333-3333-3333-333333333333"
+	)
+	parents := map[string]string{
+		rootID:  `{"type":"workspace","workspace":true}`,
+		childID: `{"type":"page_id","page_id":"` + rootID + `"}`,
+		otherID: `{"type":"workspace","workspace":true}`,
+	}
+	var patched []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if r.Method == "PATCH" {
+			patched = append(patched, id)
+		}
+		w.Write([]byte(`{"object":"page","id":"` + id + `","parent":` + parents[id] + `,"properties":{"Status":{"id":"s","type":"select","select":null}}}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("NOTION_WRITE_ALLOW", rootID)
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "set", childID, "Status=Done"); err != nil {
+			t.Errorf("write under an allowed page: %v", err)
+		}
+	})
+	if _, _, err := executeCommand("page", "set", otherID, "Status=Done"); !errors.Is(err, errPolicy) || !strings.Contains(err.Error(), "outside the write_allow list") {
+		t.Errorf("write outside the allowlist: err = %v", err)
+	}
+	t.Setenv("NOTION_WRITE_DENY", childID)
+	if _, _, err := executeCommand("page", "set", childID, "Status=Done"); !errors.Is(err, errPolicy) || !strings.Contains(err.Error(), "write_deny") {
+		t.Errorf("write to a denied page: err = %v", err)
+	}
+	if _, _, err := executeCommand("page", "create", "workspace", "--title", "Loose"); !errors.Is(err, errPolicy) {
+		t.Errorf("write to the workspace root: err = %v", err)
+	}
+	if strings.Join(patched, ",") != childID {
+		t.Errorf("PATCHed %v, want only %s", patched, childID)
+	}
+}
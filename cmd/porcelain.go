@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// porcelain is the --porcelain flag: the version of the plumbing output
+// format, or "" for the usual output.
+//
+// Porcelain output is a contract for scripts. Within a version, the
+// records below never change: fields are not added, removed or reordered.
+// A new layout gets a new version, and old versions keep working.
+//
+// Version v1: UTF-8, one record per line, fields separated by a tab, no
+// header, no totals, nothing when there are no results. In field values a
+// backslash, tab, newline and carriage return are written \\, \t, \n and
+// \r. Timestamps are RFC 3339 as the API returns them (never relative);
+// IDs are dashed UUIDs. Records, by command:
+//
+//	search      object  id  title  last_edited_time  url
+//	page list   id  title  last_edited_time  url
+//	db list     id  title  last_edited_time  url
+//	db query    id  last_edited_time  url  name=value...
+//	            (one field per property, in schema order or --columns
+//	            order; "=" in a name is written \=)
+//	block list  id  depth  type  has_children  text
+//	            (depth is 0 for the listed blocks, 1 for their children
+//	            with --depth 2, ...; has_children is true or false)
+//	user list   id  type  name  email
+var porcelain string
+
+// porcelainCommands have porcelain output.
+var porcelainCommands = map[string]bool{
+	"search":     true,
+	"page list":  true,
+	"db list":    true,
+	"db query":   true,
+	"block list": true,
+	"user list":  true,
+}
+
+// validatePorcelainFlag checks --porcelain against the running command.
+func validatePorcelainFlag(cmd *cobra.Command) error {
+	if porcelain == "" {
+		return nil
+	}
+	if porcelain != "v1" {
+		return usageError(fmt.Errorf("--porcelain: unknown version %q (supported: v1)", porcelain))
+	}
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if !porcelainCommands[path] {
+		return usageError(fmt.Errorf("--porcelain is not supported by '%s' (only search, page list, db list, db query, block list and user list)", path))
+	}
+	if outputFormat != "" {
+		return usageError(fmt.Errorf("--porcelain and --format can't be combined"))
+	}
+	if f := cmd.Flags().Lookup("all-profiles"); f != nil && f.Changed {
+		return usageError(fmt.Errorf("--porcelain and --all-profiles can't be combined"))
+	}
+	return nil
+}
+
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// porcelainLine prints one porcelain record.
+func porcelainLine(fields ...string) {
+	for i, f := range fields {
+		fields[i] = porcelainEscaper.Replace(f)
+	}
+	fmt.Println(strings.Join(fields, "\t"))
+}
+
+// porcelainObjects prints search, page list and db list records; withType
+// adds the leading object field of search.
+func porcelainObjects(results []interface{}, withType bool) {
+	for _, r := range results {
+		obj, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		objType, _ := obj["object"].(string)
+		id, _ := obj["id"].(string)
+		lastEdited, _ := obj["last_edited_time"].(string)
+		url, _ := obj["url"].(string)
+		fields := []string{id, render.ExtractTitle(obj), lastEdited, url}
+		if withType {
+			fields = append([]string{objType}, fields...)
+		}
+		porcelainLine(fields...)
+	}
+}
+
+// porcelainRows prints db query records with the properties in names.
+func porcelainRows(results []interface{}, names []string) {
+	for _, r := range results {
+		page, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := page["id"].(string)
+		lastEdited, _ := page["last_edited_time"].(string)
+		url, _ := page["url"].(string)
+		pageProps, _ := page["properties"].(map[string]interface{})
+		fields := []string{id, lastEdited, url}
+		for _, name := range names {
+			value := ""
+			if prop, ok := pageProps[name].(map[string]interface{}); ok {
+				value = extractPropertyValue(prop)
+			}
+			fields = append(fields, strings.ReplaceAll(name, "=", `\=`)+"="+value)
+		}
+		porcelainLine(fields...)
+	}
+}
+
+// porcelainBlocks prints block list records, children (fetched with
+// --depth) after their parent.
+func porcelainBlocks(blocks []interface{}, depth int) {
+	for _, b := range blocks {
+		block, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := block["id"].(string)
+		blockType, _ := block["type"].(string)
+		hasChildren, _ := block["has_children"].(bool)
+		porcelainLine(id, strconv.Itoa(depth), blockType, strconv.FormatBool(hasChildren), blockText(block))
+		if children, ok := block["_children"].([]interface{}); ok {
+			porcelainBlocks(children, depth+1)
+		}
+	}
+}
+
+// porcelainUsers prints user list records.
+func porcelainUsers(results []interface{}) {
+	for _, r := range results {
+		user, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := user["id"].(string)
+		userType, _ := user["type"].(string)
+		name, _ := user["name"].(string)
+		person, _ := user["person"].(map[string]interface{})
+		email, _ := person["email"].(string)
+		porcelainLine(id, userType, name, email)
+	}
+}
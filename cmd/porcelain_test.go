@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// The porcelain v1 records are a contract: these tests pin them down.
+func TestPorcelainV1(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/v1/search":
+			w.Write([]byte(`{"results":[{"object":"page","id":"p1","url":"https://www.notion.so/p1","last_edited_time":"2026-03-01T10:00:00.000Z",
+				"properties":{"Name":{"type":"title","title":[{"plain_text":"Tabs\tand\nlines"}]}}}],"has_more":false}`))
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			w.Write([]byte(`{"results":[{"object":"page","id":"p1","url":"https://www.notion.so/p1","last_edited_time":"2026-03-01T10:00:00.000Z","properties":{
+				"Assignee":{"type":"people","people":[]},
+				"Due":{"type":"date","date":{"start":"2026-03-01"}},
+				"Status":{"type":"select","select":{"name":"Todo"}},
+				"Name":{"type":"title","title":[{"plain_text":"Ship it"}]}
+			}}],"has_more":false}`))
+		case strings.HasSuffix(r.URL.Path, "/children"):
+			w.Write([]byte(`{"results":[{"object":"block","id":"b1","type":"paragraph","has_children":false,"paragraph":{"rich_text":[{"plain_text":"Hello"}]}}],"has_more":false}`))
+		case r.URL.Path == "/v1/users":
+			w.Write([]byte(`{"results":[{"object":"user","id":"u1","type":"person","name":"Ada","person":{"email":"ada@example.com"}}],"has_more":false}`))
+		default:
+			w.Write([]byte(orderedSchema))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	const db = "11111111-1111-1111-1111-111111111111"
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"search", "x", "--porcelain"}, "page\tp1\tTabs\\tand\\nlines\t2026-03-01T10:00:00.000Z\thttps://www.notion.so/p1\n"},
+		{[]string{"page", "list", "--porcelain=v1"}, "p1\tTabs\\tand\\nlines\t2026-03-01T10:00:00.000Z\thttps://www.notion.so/p1\n"},
+		{[]string{"db", "query", db, "--porcelain", "--relative-dates"}, "p1\t2026-03-01T10:00:00.000Z\thttps://www.notion.so/p1\tName=Ship it\tStatus=Todo\tDue=2026-03-01\tAssignee=\n"},
+		{[]string{"block", "list", db, "--porcelain"}, "b1\t0\tparagraph\tfalse\tHello\n"},
+		{[]string{"user", "list", "--porcelain"}, "u1\tperson\tAda\tada@example.com\n"},
+	} {
+		out := captureStdout(t, func() {
+			if _, _, err := executeCommand(tc.args...); err != nil {
+				t.Errorf("%v: %v", tc.args, err)
+			}
+		})
+		if out != tc.want {
+			t.Errorf("%v:\n got %q\nwant %q", tc.args, out, tc.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"page", "view", db, "--porcelain"},
+		{"search", "--porcelain", "--format", "json"},
+		{"search", "--porcelain=v9"},
+	} {
+		if _, _, err := executeCommand(args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+)
+
+// profileTarget is one saved workspace a fan-out command runs against.
+type profileTarget struct {
+	Profile   string
+	Workspace string
+	Token     string
+	ReadOnly  bool
+}
+
+// allProfileTargets returns every saved profile that has a token, sorted
+// by profile name.
+func allProfileTargets() ([]profileTarget, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("not authenticated. Run 'notion auth login' first")
+	}
+	cfg.MigrateToProfiles()
+
+	var targets []profileTarget
+	for _, name := range cfg.ListProfiles() {
+		p := cfg.Profiles[name]
+		if p == nil || p.Token == "" {
+			continue
+		}
+		workspace := p.WorkspaceName
+		if workspace == "" {
+			workspace = name
+		}
+		targets = append(targets, profileTarget{Profile: name, Workspace: workspace, Token: p.Token, ReadOnly: p.ReadOnly})
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no profiles found. Run 'notion auth login --profile <name>' first")
+	}
+	return targets, nil
+}
+
+// searchAllProfiles runs the same search against every saved profile and
+// prints the merged results with a WORKSPACE column. Each JSON result is
+// tagged with "profile" and "workspace" keys. A profile that fails is
+// reported on stderr and skipped, so one revoked token doesn't hide the
+// other workspaces.
+func searchAllProfiles(query, filterType string, limit int, all, showType bool) error {
+	targets, err := allProfileTargets()
+	if err != nil {
+		return err
+	}
+
+	var merged []interface{}
+	var failures []map[string]string
+	for _, t := range targets {
+		c := newProfileClient(t)
+
+		cursor := ""
+		for {
+			result, err := c.Search(query, filterType, limit, cursor)
+			if err != nil {
+				failures = append(failures, map[string]string{"profile": t.Profile, "error": firstLine(err)})
+				fmt.Fprintf(os.Stderr, "✗ %s: %s\n", t.Profile, firstLine(err))
+				break
+			}
+			results, _ := result["results"].([]interface{})
+			for _, r := range results {
+				if obj, ok := r.(map[string]interface{}); ok {
+					obj["profile"] = t.Profile
+					obj["workspace"] = t.Workspace
+					merged = append(merged, obj)
+				}
+			}
+			hasMore, _ := result["has_more"].(bool)
+			nextCursor, _ := result["next_cursor"].(string)
+			if !all || !hasMore || nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+	}
+
+	if outputFormat == "json" {
+		out := map[string]interface{}{"results": merged}
+		if len(failures) > 0 {
+			out["errors"] = failures
+		}
+		return render.JSON(out)
+	}
+
+	if len(merged) == 0 {
+		fmt.Println("No results found.")
+		return nil
+	}
+
+	headers := []string{"WORKSPACE", "TITLE", "ID", "LAST EDITED"}
+	if showType {
+		headers = []string{"WORKSPACE", "TYPE", "TITLE", "ID", "LAST EDITED"}
+	}
+	var rows [][]string
+	for _, r := range merged {
+		obj := r.(map[string]interface{})
+		workspace, _ := obj["workspace"].(string)
+		objType, _ := obj["object"].(string)
+		id, _ := obj["id"].(string)
+		lastEdited, _ := obj["last_edited_time"].(string)
+		row := []string{workspace, render.ExtractTitle(obj), id, displayDate(lastEdited)}
+		if showType {
+			icon := "📄"
+			if objType == "database" {
+				icon = "🗃️"
+			}
+			row = []string{workspace, icon + " " + objType, render.ExtractTitle(obj), id, lastEdited}
+		}
+		rows = append(rows, row)
+	}
+	render.Table(headers, rows)
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func TestSearchAllProfilesMergesWorkspaces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer secret_home":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"object": "page", "id": "home-1"},
+			}})
+		case "Bearer secret_work":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"object": "page", "id": "work-1"},
+				map[string]interface{}{"object": "database", "id": "work-2"},
+			}})
+		default:
+			w.WriteHeader(401)
+			json.NewEncoder(w).Encode(map[string]string{"code": "unauthorized", "message": "API token is invalid."})
+		}
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("NOTION_TOKEN", "")
+
+	config.Save(&config.Config{
+		CurrentProfile: "home",
+		Profiles: map[string]*config.Profile{
+			"home":    {Token: "secret_home", WorkspaceName: "Home"},
+			"work":    {Token: "secret_work", WorkspaceName: "Work"},
+			"revoked": {Token: "secret_revoked", WorkspaceName: "Old"},
+			"empty":   {},
+		},
+	})
+
+	outputFormat = "json"
+	defer func() { outputFormat = "" }()
+	out := captureStdout(t, func() {
+		if err := searchAllProfiles("", "", 10, false, true); err != nil {
+			t.Fatalf("searchAllProfiles: %v", err)
+		}
+	})
+
+	var got struct {
+		Results []map[string]interface{} `json:"results"`
+		Errors  []map[string]string      `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("parse output: %v\n%s", err, out)
+	}
+	if len(got.Results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(got.Results))
+	}
+	if got.Results[0]["workspace"] != "Home" || got.Results[2]["profile"] != "work" {
+		t.Errorf("results not tagged by workspace: %v", got.Results)
+	}
+	if len(got.Errors) != 1 || got.Errors[0]["profile"] != "revoked" {
+		t.Errorf("errors = %v, want one for revoked", got.Errors)
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// progressFormat is the --progress flag: "" or "text" (a counter rewritten
+// in place), "json" (events on stderr as JSON lines) or "none".
+var progressFormat string
+
+// progressInterval is how often JSON progress events are emitted at most;
+// start, error and done events are never held back.
+const progressInterval = 250 * time.Millisecond
+
+// heartbeatInterval is how long JSON progress may stay silent: a
+// "heartbeat" event is emitted when nothing else was, so whatever watches
+// a long export (one slow request, a rate-limit wait) knows it's alive.
+var heartbeatInterval = 10 * time.Second
+
+// progressStderr is where JSON progress events go.
+var progressStderr io.Writer = os.Stderr
+
+// progress reports how far a long operation has got.
+type progress struct {
+	mu     sync.Mutex
+	op     string    // the command, e.g. "db add-bulk"
+	text   string    // Printf format of the text counter, given done and total; "" for none
+	w      io.Writer // where the text counter goes
+	total  int       // 0 when unknown
+	done   int
+	failed int
+	start  time.Time
+	last   time.Time
+	quit   chan struct{} // closed to stop the heartbeat
+	exited chan struct{} // closed when the heartbeat has stopped
+	once   sync.Once
+
+	// The settings in force when the operation started, so the heartbeat
+	// doesn't read globals that may change under it.
+	format   string    // --progress
+	events   io.Writer // where JSON events go
+	interval time.Duration
+}
+
+// progressEvent is one JSON line of --progress json.
+type progressEvent struct {
+	Event     string `json:"event"` // start, progress, heartbeat, error, interrupted or done
+	Op        string `json:"op"`
+	Done      int    `json:"done"`
+	Failed    int    `json:"failed"`
+	Total     int    `json:"total,omitempty"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+	ETAMS     int64  `json:"eta_ms,omitempty"`
+	Item      string `json:"item,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// RemainingMS is the time left before --max-duration stops the command.
+	RemainingMS int64 `json:"remaining_ms,omitempty"`
+}
+
+// newProgress starts reporting an operation over total items (0 when not
+// known up front). In text mode the counter is printed to w with text,
+// unless --format json asked for clean output. JSON progress keeps
+// emitting heartbeats until finish, interrupted or stop.
+func newProgress(op, text string, total int, w io.Writer) *progress {
+	p := &progress{op: op, text: text, w: w, total: total, start: time.Now(),
+		quit: make(chan struct{}), exited: make(chan struct{}),
+		format: progressFormat, events: progressStderr, interval: heartbeatInterval}
+	p.emit("start", "", nil)
+	if p.format == "json" {
+		go p.heartbeat()
+	} else {
+		close(p.exited)
+	}
+	return p
+}
+
+// heartbeat emits a "heartbeat" event whenever the operation has been
+// quiet for heartbeatInterval.
+func (p *progress) heartbeat() {
+	defer close(p.exited)
+	ticker := time.NewTicker(p.interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			if time.Since(p.last) >= p.interval {
+				p.emitLocked("heartbeat", "", nil)
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// stop ends the heartbeat and waits for it to finish. It is safe to call
+// more than once, so commands defer it to cover their error returns.
+func (p *progress) stop() {
+	p.once.Do(func() { close(p.quit) })
+	<-p.exited
+}
+
+// add records n items done.
+func (p *progress) add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	switch p.format {
+	case "json":
+		if time.Since(p.last) >= progressInterval {
+			p.emitLocked("progress", "", nil)
+		}
+	case "", "text":
+		if p.text != "" && outputFormat != "json" {
+			fmt.Fprintf(p.w, "\r  "+p.text, p.done, p.total)
+		}
+	}
+}
+
+// fail records an item that failed.
+func (p *progress) fail(item string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed++
+	p.emitLocked("error", item, err)
+}
+
+// finish reports the final counts.
+func (p *progress) finish() {
+	p.stop()
+	p.emit("done", "", nil)
+}
+
+func (p *progress) emit(event, item string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emitLocked(event, item, err)
+}
+
+func (p *progress) emitLocked(event, item string, err error) {
+	if p.format != "json" {
+		return
+	}
+	elapsed := time.Since(p.start)
+	e := progressEvent{
+		Event:     event,
+		Op:        p.op,
+		Done:      p.done,
+		Failed:    p.failed,
+		Total:     p.total,
+		ElapsedMS: elapsed.Milliseconds(),
+		Item:      item,
+	}
+	if err != nil {
+		e.Error = firstLine(err)
+	}
+	if n := p.done + p.failed; p.total > 0 && n > 0 && n < p.total && event != "done" {
+		e.ETAMS = (elapsed / time.Duration(n) * time.Duration(p.total-n)).Milliseconds()
+	}
+	if !operationDeadline.IsZero() && event != "done" {
+		if left := time.Until(operationDeadline); left > 0 {
+			e.RemainingMS = left.Milliseconds()
+		}
+	}
+	data, _ := json.Marshal(e)
+	fmt.Fprintln(p.events, string(data))
+	p.last = time.Now()
+}
+
+// queryAllWithProgress is QueryDatabaseAll, counting rows as each page of
+// results arrives.
+func queryAllWithProgress(c *notion.Client, dbID string, body map[string]interface{}, p *progress) ([]interface{}, error) {
+	return notion.Paginate(func(cursor string) (map[string]interface{}, error) {
+		req := map[string]interface{}{"page_size": 100}
+		for k, v := range body {
+			req[k] = v
+		}
+		if cursor != "" {
+			req["start_cursor"] = cursor
+		}
+		resp, err := c.QueryDatabase(dbID, req)
+		if err == nil {
+			results, _ := resp["results"].([]interface{})
+			p.add(len(results))
+		}
+		return resp, err
+	})
+}
+
+// validateProgressFlag rejects unknown --progress formats before the
+// command runs.
+func validateProgressFlag() error {
+	switch progressFormat {
+	case "", "text", "json", "none":
+		return nil
+	}
+	return usageError(fmt.Errorf("invalid --progress format %q: use text, json or none", progressFormat))
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddBulkJSONProgress(t *testing.T) {
+	posts := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"type": "title"},
+			}})
+			return
+		}
+		posts++
+		if posts == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"object":"error","code":"validation_error","message":"bad row"}`))
+			return
+		}
+		w.Write([]byte(`{"object":"page","id":"p"}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	file := filepath.Join(t.TempDir(), "rows.json")
+	os.WriteFile(file, []byte(`[{"Name": "a"}, {"Name": "b"}, {"Name": "c"}]`), 0o644)
+
+	var events bytes.Buffer
+	progressStderr = &events
+	defer func() { progressStderr = os.Stderr; progressFormat = "" }()
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("db", "add-bulk", "66666666666666666666666666666666", "--file", file, "--progress", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	var kinds []string
+	var last progressEvent
+	for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+		var e progressEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("event %q: %v", line, err)
+		}
+		if e.Op != "db add-bulk" || e.Total != 3 {
+			t.Errorf("event = %+v", e)
+		}
+		kinds = append(kinds, e.Event)
+		if e.Event == "error" && (e.Item != "row 2" || !strings.Contains(e.Error, "bad row")) {
+			t.Errorf("error event = %+v", e)
+		}
+		last = e
+	}
+	if kinds[0] != "start" || last.Event != "done" || last.Done != 2 || last.Failed != 1 {
+		t.Errorf("events %v, last %+v", kinds, last)
+	}
+	if !strings.Contains(strings.Join(kinds, ","), "error") {
+		t.Errorf("no error event in %v", kinds)
+	}
+}
+
+func TestValidateProgressFlag(t *testing.T) {
+	defer func() { progressFormat = "" }()
+	progressFormat = "xml"
+	if err := validateProgressFlag(); err == nil {
+		t.Error("unknown --progress format should fail")
+	}
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// errQueued is wrapped by the error of a write saved to the offline queue
+// instead of being sent.
+var errQueued = errors.New("queued offline")
+
+var (
+	// queueOffline is the --queue-offline flag.
+	queueOffline bool
+	// flushing is set while 'notion flush' replays the queue, whose
+	// requests must reach the API or fail, never queue again.
+	flushing bool
+)
+
+// queuedOp is one write waiting in the offline queue.
+type queuedOp struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Command  string          `json:"command,omitempty"`
+	QueuedAt time.Time       `json:"queued_at"`
+}
+
+var flushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Send writes queued while offline",
+	Long: `Replay the writes saved to the offline queue, oldest first.
+
+With --queue-offline (or NOTION_OFFLINE_QUEUE=1, or 'notion config set
+offline_queue true'), a command whose write can't reach Notion because the
+network is down saves the request to a local queue instead of failing.
+The command stops there, so later steps that need the result (such as the
+ID of a created page) don't run. Run 'notion flush' when back online.
+
+Flushing stops at the first request that fails, keeping it and everything
+after it queued; --drop-failed discards failed requests and carries on.
+Queued requests are sent with the credentials in use when flushing.
+
+Examples:
+  notion --queue-offline page create abc123 --title "Notes from the flight"
+  notion flush --list
+  notion flush
+  notion flush --clear`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ops, err := loadQueue()
+		if err != nil {
+			return err
+		}
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			if err := saveQueue(nil); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Discarded %d queued request(s)\n", len(ops))
+			return nil
+		}
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			if outputFormat == "json" {
+				if ops == nil {
+					ops = []queuedOp{}
+				}
+				return render.JSON(ops)
+			}
+			if len(ops) == 0 {
+				fmt.Println("The offline queue is empty.")
+				return nil
+			}
+			var rows [][]string
+			for i, op := range ops {
+				rows = append(rows, []string{fmt.Sprint(i + 1), op.QueuedAt.Local().Format("2006-01-02 15:04"), op.Method, op.Path, op.Command})
+			}
+			render.Table([]string{"#", "QUEUED", "METHOD", "PATH", "COMMAND"}, rows)
+			return nil
+		}
+		if len(ops) == 0 {
+			fmt.Println("Nothing to flush.")
+			return nil
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		flushing = true
+		defer func() { flushing = false }()
+		c := newClient(token)
+		dropFailed, _ := cmd.Flags().GetBool("drop-failed")
+
+		sent, dropped := 0, 0
+		var flushErr error
+		remaining := ops
+		for len(remaining) > 0 {
+			op := remaining[0]
+			err := sendQueued(c, op)
+			if err == nil {
+				sent++
+				remaining = remaining[1:]
+				continue
+			}
+			var apiErr *notion.APIError
+			if dropFailed && errors.As(err, &apiErr) && !apiErr.Retryable() {
+				fmt.Fprintf(os.Stderr, "dropped: %s %s: %s\n", op.Method, op.Path, firstLine(err))
+				dropped++
+				remaining = remaining[1:]
+				continue
+			}
+			flushErr = fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+			break
+		}
+		if err := saveQueue(remaining); err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			if err := render.JSON(map[string]interface{}{"sent": sent, "dropped": dropped, "remaining": len(remaining)}); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("✓ Sent %d queued request(s)", sent)
+			if dropped > 0 {
+				fmt.Printf(", dropped %d", dropped)
+			}
+			if len(remaining) > 0 {
+				fmt.Printf(", %d still queued", len(remaining))
+			}
+			fmt.Println()
+		}
+		return flushErr
+	},
+}
+
+func init() {
+	flushCmd.Flags().Bool("list", false, "Show the queued requests without sending them")
+	flushCmd.Flags().Bool("clear", false, "Discard every queued request")
+	flushCmd.Flags().Bool("drop-failed", false, "Discard requests the API rejects instead of stopping")
+}
+
+func queuePath() string {
+	return filepath.Join(config.Dir(), "queue.jsonl")
+}
+
+// offlineQueueEnabled reports whether writes should be queued when the
+// network is down: --queue-offline, NOTION_OFFLINE_QUEUE=1, or 'notion
+// config set offline_queue true'.
+func offlineQueueEnabled() bool {
+	if flushing {
+		return false
+	}
+	if queueOffline {
+		return true
+	}
+	if isTruthy(os.Getenv("NOTION_OFFLINE_QUEUE")) {
+		return true
+	}
+	cfg, err := config.Load()
+	return err == nil && isTruthy(cfg.Setting("offline_queue"))
+}
+
+// queueWhenOffline is client middleware that saves writes to the offline
+// queue when they fail because the API can't be reached.
+func queueWhenOffline(next notion.Handler) notion.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		resp, err := next(req)
+		if err == nil || !isOffline(err) || !isWrite(req) || !offlineQueueEnabled() {
+			return resp, err
+		}
+		op := queuedOp{Method: req.Method, Path: apiPath(req), Command: currentCommandLine(), QueuedAt: time.Now().UTC()}
+		if req.GetBody != nil {
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				return nil, err
+			}
+			data, _ := io.ReadAll(body)
+			op.Body = data
+		}
+		n, qerr := appendQueue(op)
+		if qerr != nil {
+			return nil, fmt.Errorf("%w (and queueing failed: %v)", err, qerr)
+		}
+		return nil, fmt.Errorf("%w as request #%d; run 'notion flush' when back online", errQueued, n)
+	}
+}
+
+// isOffline reports whether err means the API couldn't be reached at all,
+// so the request certainly wasn't applied.
+func isOffline(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || (errors.As(err, &opErr) && opErr.Op == "dial")
+}
+
+// isWrite reports whether req changes data. Searches and database queries
+// are POSTs but only read. Multipart uploads are not queued.
+func isWrite(req *http.Request) bool {
+	if req.Method == "GET" || strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+		return false
+	}
+	path := apiPath(req)
+	return !(req.Method == "POST" && (path == "/v1/search" || strings.HasSuffix(path, "/query")))
+}
+
+// apiPath returns the request path from /v1 on, independent of the base
+// URL it was sent to.
+func apiPath(req *http.Request) string {
+	path := req.URL.Path
+	if i := strings.Index(path, "/v1/"); i >= 0 {
+		path = path[i:]
+	}
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+	return path
+}
+
+// currentCommandLine describes the running command for 'flush --list'.
+func currentCommandLine() string {
+	return strings.Join(append([]string{"notion"}, os.Args[1:]...), " ")
+}
+
+func loadQueue() ([]queuedOp, error) {
+	f, err := os.Open(queuePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read offline queue: %w", err)
+	}
+	defer f.Close()
+	var ops []queuedOp
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var op queuedOp
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			return nil, fmt.Errorf("read offline queue: %w", err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, scanner.Err()
+}
+
+// appendQueue adds op to the queue and returns its position.
+func appendQueue(op queuedOp) (int, error) {
+	ops, err := loadQueue()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(config.Dir(), 0700); err != nil {
+		return 0, err
+	}
+	data, err := json.Marshal(op)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(queuePath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return 0, err
+	}
+	return len(ops) + 1, nil
+}
+
+// saveQueue replaces the queue with ops, removing the file when empty.
+func saveQueue(ops []queuedOp) error {
+	if len(ops) == 0 {
+		if err := os.Remove(queuePath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("write offline queue: %w", err)
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, op := range ops {
+		data, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	if err := os.WriteFile(queuePath(), buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("write offline queue: %w", err)
+	}
+	return nil
+}
+
+// sendQueued replays one queued request.
+func sendQueued(c *notion.Client, op queuedOp) error {
+	var body interface{}
+	if len(op.Body) > 0 {
+		body = op.Body
+	}
+	var err error
+	switch op.Method {
+	case "POST":
+		_, err = c.Post(op.Path, body)
+	case "PATCH":
+		_, err = c.Patch(op.Path, body)
+	case "DELETE":
+		_, err = c.Delete(op.Path)
+	default:
+		return fmt.Errorf("unsupported method %s", op.Method)
+	}
+	return err
+}
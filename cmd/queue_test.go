@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOfflineQueueAndFlush(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Method+" "+r.URL.Path+" "+string(body))
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "page", "id": "p1"})
+	}))
+	defer server.Close()
+	offline := httptest.NewServer(http.NotFoundHandler())
+	offline.Close()
+
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("NOTION_BASE_URL", offline.URL)
+
+	_, _, err := executeCommand("api", "PATCH", "/v1/pages/abc", "--body", `{"archived":true}`, "--queue-offline")
+	if !errors.Is(err, errQueued) {
+		t.Fatalf("offline write: err = %v, want errQueued", err)
+	}
+	_, _, err = executeCommand("api", "GET", "/v1/pages/abc", "--queue-offline")
+	if err == nil || errors.Is(err, errQueued) {
+		t.Fatalf("offline read: err = %v, want a plain failure", err)
+	}
+	ops, _ := loadQueue()
+	if len(ops) != 1 || ops[0].Method != "PATCH" || ops[0].Path != "/v1/pages/abc" || string(ops[0].Body) != `{"archived":true}` {
+		t.Fatalf("queue = %+v", ops)
+	}
+
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	captureStdout(t, func() {
+		_, _, err = executeCommand("flush")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0] != `PATCH /v1/pages/abc {"archived":true}` {
+		t.Errorf("replayed = %q", received)
+	}
+	if ops, _ := loadQueue(); len(ops) != 0 {
+		t.Errorf("queue after flush = %+v", ops)
+	}
+}
+
+func TestIsWrite(t *testing.T) {
+	tests := map[string]bool{
+		"GET /v1/pages/x":            false,
+		"POST /v1/search":            false,
+		"POST /v1/databases/x/query": false,
+		"POST /v1/pages":             true,
+		"PATCH /v1/blocks/x":         true,
+		"DELETE /v1/blocks/x":        true,
+	}
+	for in, want := range tests {
+		method, path, _ := strings.Cut(in, " ")
+		req := httptest.NewRequest(method, "https://api.notion.com"+path, nil)
+		if got := isWrite(req); got != want {
+			t.Errorf("isWrite(%s) = %v, want %v", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,557 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// htmlNode is a minimal HTML tree. Text nodes have an empty tag.
+type htmlNode struct {
+	tag      string
+	attrs    map[string]string
+	text     string
+	children []*htmlNode
+	parent   *htmlNode
+}
+
+// rawTextRe matches elements whose content isn't HTML and would confuse
+// the XML tokenizer.
+var rawTextRe = regexp.MustCompile(`(?is)<(script|style|noscript|template|svg)\b.*?</(script|style|noscript|template|svg)\s*>`)
+
+// parseHTML builds a tree from a page using encoding/xml in its lenient
+// HTML mode. Parsing stops quietly at the first error it can't recover
+// from; whatever was read so far is returned.
+func parseHTML(r io.Reader) *htmlNode {
+	data, _ := io.ReadAll(r)
+	src := rawTextRe.ReplaceAllString(string(data), "")
+
+	d := xml.NewDecoder(strings.NewReader(src))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	root := &htmlNode{tag: "#document"}
+	cur := root
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &htmlNode{tag: strings.ToLower(t.Name.Local), attrs: map[string]string{}, parent: cur}
+			for _, a := range t.Attr {
+				n.attrs[strings.ToLower(a.Name.Local)] = a.Value
+			}
+			cur.children = append(cur.children, n)
+			cur = n
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			for n := cur; n != root; n = n.parent {
+				if n.tag == name {
+					cur = n.parent
+					break
+				}
+			}
+		case xml.CharData:
+			cur.children = append(cur.children, &htmlNode{text: string(t), parent: cur})
+		}
+	}
+	return root
+}
+
+// find returns the first element with tag in n's subtree.
+func (n *htmlNode) find(tag string) *htmlNode {
+	for _, c := range n.children {
+		if c.tag == tag {
+			return c
+		}
+		if f := c.find(tag); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// textContent returns the text of n's subtree.
+func (n *htmlNode) textContent() string {
+	if n.tag == "" {
+		return n.text
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(c.textContent())
+	}
+	return b.String()
+}
+
+var spaceRe = regexp.MustCompile(`\s+`)
+
+func collapseSpace(s string) string {
+	return spaceRe.ReplaceAllString(s, " ")
+}
+
+// pageMeta is what a web page says about itself.
+type pageMeta struct {
+	Title       string
+	SiteName    string
+	Author      string
+	Published   string
+	Description string
+}
+
+func readPageMeta(doc *htmlNode) pageMeta {
+	var m pageMeta
+	var titleTag string
+	var walk func(n *htmlNode)
+	walk = func(n *htmlNode) {
+		if n.tag == "meta" {
+			key := strings.ToLower(firstNonEmpty(n.attrs["property"], n.attrs["name"]))
+			content := strings.TrimSpace(n.attrs["content"])
+			switch key {
+			case "og:title":
+				m.Title = content
+			case "og:site_name":
+				m.SiteName = content
+			case "author", "article:author":
+				if m.Author == "" && !strings.HasPrefix(content, "http") {
+					m.Author = content
+				}
+			case "article:published_time", "date":
+				if m.Published == "" {
+					m.Published = content
+				}
+			case "description", "og:description":
+				if m.Description == "" {
+					m.Description = content
+				}
+			}
+		}
+		if n.tag == "title" && titleTag == "" {
+			titleTag = strings.TrimSpace(collapseSpace(n.textContent()))
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(doc)
+	if m.Title == "" {
+		m.Title = titleTag
+	}
+	if m.Title == "" {
+		if h1 := doc.find("h1"); h1 != nil {
+			m.Title = strings.TrimSpace(collapseSpace(h1.textContent()))
+		}
+	}
+	return m
+}
+
+// skippedTags never hold an article's content.
+var skippedTags = map[string]bool{
+	"head": true, "nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"button": true, "select": true, "iframe": true, "script": true, "style": true,
+	"noscript": true, "svg": true, "template": true, "dialog": true,
+}
+
+// junkClassRe and contentClassRe classify elements by class, id and role,
+// like readability's "unlikely candidates": junk unless it also looks like
+// content.
+var (
+	junkClassRe    = regexp.MustCompile(`(?i)comment|sidebar|footer|nav|menu|share|social|related|advert|promo|cookie|subscribe|newsletter|breadcrumb`)
+	contentClassRe = regexp.MustCompile(`(?i)article|body|content|main|post|entry|story`)
+)
+
+func isJunk(n *htmlNode) bool {
+	if skippedTags[n.tag] {
+		return true
+	}
+	switch n.tag {
+	case "#document", "html", "body", "article", "main":
+		return false
+	}
+	hints := n.attrs["class"] + " " + n.attrs["id"] + " " + n.attrs["role"]
+	return junkClassRe.MatchString(hints) && !contentClassRe.MatchString(hints)
+}
+
+// readableRoot picks the element holding the article: <article>, else
+// <main>, else the element whose paragraphs hold the most text (the
+// readability heuristic).
+func readableRoot(doc *htmlNode) *htmlNode {
+	if a := doc.find("article"); a != nil {
+		return a
+	}
+	if m := doc.find("main"); m != nil {
+		return m
+	}
+	scores := map[*htmlNode]int{}
+	var walk func(n *htmlNode)
+	walk = func(n *htmlNode) {
+		if n.tag == "" || isJunk(n) {
+			return
+		}
+		if n.tag == "p" && n.parent != nil {
+			if l := len(strings.TrimSpace(collapseSpace(n.textContent()))); l > 25 {
+				scores[n.parent] += l
+				if n.parent.parent != nil {
+					scores[n.parent.parent] += l / 2
+				}
+			}
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(doc)
+	var best *htmlNode
+	for n, s := range scores {
+		if best == nil || s > scores[best] {
+			best = n
+		}
+	}
+	if best == nil {
+		if body := doc.find("body"); body != nil {
+			return body
+		}
+		return doc
+	}
+	return best
+}
+
+// inlineTags are rendered as rich text inside the enclosing block.
+var inlineTags = map[string]bool{
+	"": true, "a": true, "span": true, "strong": true, "b": true, "em": true, "i": true,
+	"code": true, "kbd": true, "small": true, "sup": true, "sub": true, "abbr": true,
+	"time": true, "mark": true, "u": true, "s": true, "del": true, "ins": true,
+	"label": true, "cite": true, "q": true, "br": true, "font": true, "tt": true,
+}
+
+// htmlConverter accumulates the blocks converted from HTML.
+type htmlConverter struct {
+	base      *url.URL
+	skipTitle string
+	blocks    []map[string]interface{}
+}
+
+// htmlToBlocks converts readable HTML into Notion blocks. base resolves
+// relative links and image sources; a top heading repeating title is
+// dropped.
+func htmlToBlocks(root *htmlNode, base *url.URL, title string) []map[string]interface{} {
+	c := &htmlConverter{base: base, skipTitle: strings.TrimSpace(title)}
+	c.convert(root)
+	return c.blocks
+}
+
+func (c *htmlConverter) convert(n *htmlNode) {
+	var pending []*htmlNode
+	flush := func() {
+		if rt := c.richText(pending); len(rt) > 0 {
+			c.add("paragraph", rt)
+		}
+		pending = nil
+	}
+	for _, child := range n.children {
+		if inlineTags[child.tag] {
+			pending = append(pending, child)
+			continue
+		}
+		flush()
+		c.block(child)
+	}
+	flush()
+}
+
+func (c *htmlConverter) block(n *htmlNode) {
+	if isJunk(n) {
+		return
+	}
+	switch n.tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if n.tag == "h1" && strings.TrimSpace(collapseSpace(n.textContent())) == c.skipTitle {
+			return
+		}
+		blockType := map[string]string{"h1": "heading_1", "h2": "heading_2"}[n.tag]
+		if blockType == "" {
+			blockType = "heading_3"
+		}
+		c.add(blockType, c.richText(n.children))
+	case "p":
+		c.add("paragraph", c.richText(n.children))
+		c.images(n)
+	case "ul", "ol":
+		c.list(n, map[bool]string{true: "numbered_list_item", false: "bulleted_list_item"}[n.tag == "ol"])
+	case "pre":
+		text := strings.Trim(n.textContent(), "\n")
+		if text == "" {
+			return
+		}
+		lang := "plain text"
+		if code := n.find("code"); code != nil {
+			for _, cls := range strings.Fields(code.attrs["class"]) {
+				if l, ok := notion.CodeLanguage(strings.TrimPrefix(strings.TrimPrefix(cls, "language-"), "lang-")); ok {
+					lang = l
+					break
+				}
+			}
+		}
+		c.blocks = append(c.blocks, map[string]interface{}{
+			"object": "block",
+			"type":   "code",
+			"code": map[string]interface{}{
+				"rich_text": []map[string]interface{}{{"type": "text", "text": map[string]interface{}{"content": text}}},
+				"language":  lang,
+			},
+		})
+	case "blockquote":
+		c.add("quote", c.richText(n.children))
+	case "hr":
+		c.blocks = append(c.blocks, map[string]interface{}{"object": "block", "type": "divider", "divider": map[string]interface{}{}})
+	case "img", "figure", "picture":
+		c.images(n)
+	case "table":
+		c.table(n)
+	default:
+		c.convert(n)
+	}
+}
+
+func (c *htmlConverter) add(blockType string, rt []map[string]interface{}) {
+	if len(rt) == 0 {
+		return
+	}
+	c.blocks = append(c.blocks, map[string]interface{}{
+		"object":  "block",
+		"type":    blockType,
+		blockType: map[string]interface{}{"rich_text": rt},
+	})
+}
+
+// list adds one item per <li>; nested lists are flattened after their
+// parent item.
+func (c *htmlConverter) list(n *htmlNode, itemType string) {
+	for _, li := range n.children {
+		if li.tag != "li" {
+			continue
+		}
+		var inline, nested []*htmlNode
+		for _, ch := range li.children {
+			if ch.tag == "ul" || ch.tag == "ol" {
+				nested = append(nested, ch)
+			} else {
+				inline = append(inline, ch)
+			}
+		}
+		c.add(itemType, c.richText(inline))
+		for _, sub := range nested {
+			c.list(sub, itemType)
+		}
+	}
+}
+
+// images adds an image block for every <img> in n, captioned with its
+// figcaption or alt text.
+func (c *htmlConverter) images(n *htmlNode) {
+	caption := ""
+	if fc := n.find("figcaption"); fc != nil {
+		caption = strings.TrimSpace(collapseSpace(fc.textContent()))
+	}
+	var imgs []*htmlNode
+	if n.tag == "img" {
+		imgs = []*htmlNode{n}
+	}
+	var walk func(*htmlNode)
+	walk = func(x *htmlNode) {
+		for _, ch := range x.children {
+			if ch.tag == "img" {
+				imgs = append(imgs, ch)
+			}
+			walk(ch)
+		}
+	}
+	walk(n)
+	for _, img := range imgs {
+		src := c.resolve(firstNonEmpty(img.attrs["data-src"], img.attrs["src"]))
+		if src == "" {
+			continue
+		}
+		c.blocks = append(c.blocks, buildExternalMediaBlock("image", src, firstNonEmpty(caption, strings.TrimSpace(img.attrs["alt"]))))
+	}
+}
+
+func (c *htmlConverter) table(n *htmlNode) {
+	var rows [][][]map[string]interface{}
+	hasHeader := false
+	width := 0
+	var walk func(*htmlNode)
+	walk = func(x *htmlNode) {
+		for _, ch := range x.children {
+			if ch.tag != "tr" {
+				walk(ch)
+				continue
+			}
+			var cells [][]map[string]interface{}
+			for _, cell := range ch.children {
+				if cell.tag == "td" || cell.tag == "th" {
+					if cell.tag == "th" && len(rows) == 0 {
+						hasHeader = true
+					}
+					rt := c.richText(cell.children)
+					if rt == nil {
+						rt = []map[string]interface{}{}
+					}
+					cells = append(cells, rt)
+				}
+			}
+			if len(cells) > 0 {
+				rows = append(rows, cells)
+				if len(cells) > width {
+					width = len(cells)
+				}
+			}
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return
+	}
+	var children []map[string]interface{}
+	for _, cells := range rows {
+		for len(cells) < width {
+			cells = append(cells, []map[string]interface{}{})
+		}
+		children = append(children, map[string]interface{}{
+			"object":    "block",
+			"type":      "table_row",
+			"table_row": map[string]interface{}{"cells": cells},
+		})
+	}
+	c.blocks = append(c.blocks, map[string]interface{}{
+		"object": "block",
+		"type":   "table",
+		"table": map[string]interface{}{
+			"table_width":       width,
+			"has_column_header": hasHeader,
+			"has_row_header":    false,
+			"children":          children,
+		},
+	})
+}
+
+// resolve makes a link absolute. Only http(s) links are kept.
+func (c *htmlConverter) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if c.base != nil {
+		u = c.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// richTextStyle is the formatting in effect while walking inline nodes.
+type richTextStyle struct {
+	bold, italic, code, strike bool
+	link                       string
+}
+
+// maxRichTextItems is Notion's limit on rich text items per block.
+const maxRichTextItems = 100
+
+// richText converts inline nodes to Notion rich text, collapsing
+// whitespace and merging runs with the same formatting.
+func (c *htmlConverter) richText(nodes []*htmlNode) []map[string]interface{} {
+	type run struct {
+		text  string
+		style richTextStyle
+	}
+	var runs []run
+	var walk func(n *htmlNode, st richTextStyle)
+	walk = func(n *htmlNode, st richTextStyle) {
+		switch n.tag {
+		case "":
+			text := collapseSpace(n.text)
+			if len(runs) > 0 && strings.HasSuffix(runs[len(runs)-1].text, " ") {
+				text = strings.TrimLeft(text, " ")
+			}
+			if text == "" {
+				return
+			}
+			if len(runs) > 0 && runs[len(runs)-1].style == st {
+				runs[len(runs)-1].text += text
+			} else {
+				runs = append(runs, run{text, st})
+			}
+			return
+		case "br":
+			walk(&htmlNode{text: " "}, st)
+			return
+		case "strong", "b":
+			st.bold = true
+		case "em", "i", "cite":
+			st.italic = true
+		case "code", "kbd", "tt":
+			st.code = true
+		case "s", "del":
+			st.strike = true
+		case "a":
+			if href := c.resolve(n.attrs["href"]); href != "" {
+				st.link = href
+			}
+		case "img", "figure", "picture", "ul", "ol", "table", "pre":
+			return
+		}
+		if isJunk(n) {
+			return
+		}
+		for _, ch := range n.children {
+			walk(ch, st)
+		}
+	}
+	for _, n := range nodes {
+		walk(n, richTextStyle{})
+	}
+
+	// Trim the outer whitespace and drop runs left empty.
+	if len(runs) > 0 {
+		runs[0].text = strings.TrimLeft(runs[0].text, " ")
+		runs[len(runs)-1].text = strings.TrimRight(runs[len(runs)-1].text, " ")
+	}
+	var out []map[string]interface{}
+	for _, r := range runs {
+		if r.text == "" {
+			continue
+		}
+		text := map[string]interface{}{"content": r.text}
+		if r.style.link != "" {
+			text["link"] = map[string]interface{}{"url": r.style.link}
+		}
+		item := map[string]interface{}{"type": "text", "text": text}
+		if r.style.bold || r.style.italic || r.style.code || r.style.strike {
+			item["annotations"] = map[string]interface{}{
+				"bold": r.style.bold, "italic": r.style.italic, "code": r.style.code, "strikethrough": r.style.strike,
+			}
+		}
+		out = append(out, item)
+	}
+	if len(out) > maxRichTextItems {
+		var plain strings.Builder
+		for _, r := range runs {
+			plain.WriteString(r.text)
+		}
+		out = []map[string]interface{}{{"type": "text", "text": map[string]interface{}{"content": plain.String()}}}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const sampleArticle = `<!DOCTYPE html>
+<html class="nav-open">
+<head>
+  <title>Fallback title</title>
+  <meta property="og:title" content="Go &amp; Notion">
+  <meta property="og:site_name" content="Example Blog">
+  <meta name="author" content="Ada">
+  <script>if (a < b && c) { document.write("<p>nope</p>") }</script>
+  <style>p { color: red }</style>
+</head>
+<body>
+  <nav><a href="/">Home</a></nav>
+  <div class="sidebar"><p>Subscribe to our newsletter for more posts like this one!</p></div>
+  <div class="post-content">
+    <h1>Go &amp; Notion</h1>
+    <p>First <b>bold</b> and <a href="/docs">a link</a>.<br>
+       Same paragraph.</p>
+    <h2>Setup</h2>
+    <ul><li>one</li><li>two<ul><li>nested</li></ul></li></ul>
+    <pre><code class="language-go">fmt.Println("hi")
+</code></pre>
+    <figure><img src="img/chart.png" alt="alt text"><figcaption>The chart</figcaption></figure>
+    <table><tr><th>A</th><th>B</th></tr><tr><td>1</td></tr></table>
+    <p>Unclosed paragraph
+  </div>
+  <footer><p>Copyright notice that is long enough to count as a paragraph.</p></footer>
+</body>
+</html>`
+
+func TestReadableExtraction(t *testing.T) {
+	doc := parseHTML(strings.NewReader(sampleArticle))
+	meta := readPageMeta(doc)
+	if meta.Title != "Go & Notion" || meta.SiteName != "Example Blog" || meta.Author != "Ada" {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	base, _ := url.Parse("https://example.com/blog/post")
+	blocks := htmlToBlocks(readableRoot(doc), base, meta.Title)
+	var types []string
+	for _, b := range blocks {
+		types = append(types, b["type"].(string))
+	}
+	want := "paragraph heading_2 bulleted_list_item bulleted_list_item bulleted_list_item code image table paragraph"
+	if got := strings.Join(types, " "); got != want {
+		t.Fatalf("block types:\n got %s\nwant %s", got, want)
+	}
+
+	para := blocks[0]["paragraph"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	var text strings.Builder
+	for _, rt := range para {
+		text.WriteString(rt["text"].(map[string]interface{})["content"].(string))
+	}
+	if text.String() != "First bold and a link. Same paragraph." {
+		t.Errorf("paragraph text = %q", text.String())
+	}
+	link := para[3]["text"].(map[string]interface{})["link"].(map[string]interface{})["url"]
+	if link != "https://example.com/docs" {
+		t.Errorf("link = %v, want an absolute URL", link)
+	}
+
+	code := blocks[5]["code"].(map[string]interface{})
+	if code["language"] != "go" {
+		t.Errorf("code language = %v", code["language"])
+	}
+	img := blocks[6]["image"].(map[string]interface{})
+	if img["external"].(map[string]interface{})["url"] != "https://example.com/blog/img/chart.png" {
+		t.Errorf("image = %v", img["external"])
+	}
+	table := blocks[7]["table"].(map[string]interface{})
+	if table["table_width"] != 2 || table["has_column_header"] != true {
+		t.Errorf("table = %v", table)
+	}
+}
+
+func TestReadableRootScoring(t *testing.T) {
+	doc := parseHTML(strings.NewReader(`<body>
+<div id="menu"><p>A menu paragraph that is quite long indeed, yes.</p></div>
+<div id="x"><p>The first real paragraph of the story, long enough.</p><p>And a second one that also has plenty of text.</p></div>
+</body>`))
+	root := readableRoot(doc)
+	if root.attrs["id"] != "x" {
+		t.Errorf("picked %q, want the div with the most paragraph text", root.attrs["id"])
+	}
+}
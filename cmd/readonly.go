@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// errReadOnly is wrapped by the error of every write refused in read-only
+// mode.
+var errReadOnly = errors.New("read-only mode")
+
+// readOnlyFlag is the --read-only flag.
+var readOnlyFlag bool
+
+// shellReadOnly is set while a 'notion --read-only shell' runs, so no line
+// of it can write, --read-only=false included.
+var shellReadOnly bool
+
+// readOnlyReason says why the CLI may not write, or "" when it may:
+// --read-only, NOTION_READ_ONLY=1, 'notion config set read_only true', or a
+// profile logged in with 'notion auth login --read-only'. None of them can
+// be switched off by a flag, so a read-only profile handed to an agent
+// stays read-only.
+func readOnlyReason() string {
+	if readOnlyFlag || shellReadOnly {
+		return "--read-only"
+	}
+	if isTruthy(os.Getenv("NOTION_READ_ONLY")) {
+		return "NOTION_READ_ONLY"
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	if isTruthy(cfg.Setting("read_only")) {
+		return "read_only setting"
+	}
+	if os.Getenv("NOTION_TOKEN") == "" {
+		if p := cfg.GetCurrentProfile(); p != nil && p.ReadOnly {
+			return fmt.Sprintf("profile %q is read-only", cfg.ActiveProfile())
+		}
+	}
+	return ""
+}
+
+// refuseWrites is client middleware failing every request that would
+// change the workspace, file uploads included, while in read-only mode.
+// It is outermost, so refused writes are neither queued nor sent.
+func refuseWrites(next notion.Handler) notion.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		if mutates(req) {
+			if reason := readOnlyReason(); reason != "" {
+				return nil, fmt.Errorf("%w (%s): refusing %s %s", errReadOnly, reason, req.Method, apiPath(req))
+			}
+		}
+		return next(req)
+	}
+}
+
+// mutates reports whether req would change the workspace, file uploads
+// included.
+func mutates(req *http.Request) bool {
+	return isWrite(req) || (req.Method != http.MethodGet && strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/"))
+}
+
+// refuseProfileWrites is refuseWrites for a client of a saved profile that
+// isn't the current one, which readOnlyReason doesn't look at: it fails
+// every write when that profile was logged in read-only.
+func refuseProfileWrites(profile string) notion.Middleware {
+	return func(next notion.Handler) notion.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if mutates(req) {
+				return nil, fmt.Errorf("%w (profile %q is read-only): refusing %s %s", errReadOnly, profile, req.Method, apiPath(req))
+			}
+			return next(req)
+		}
+	}
+}
+
+// newProfileClient is newClient for a saved profile picked by name (as
+// 'migrate --to' does) rather than the current one. A read-only profile's
+// client refuses writes before any other middleware sees them.
+func newProfileClient(t profileTarget) *notion.Client {
+	if !t.ReadOnly {
+		return newClient(t.Token)
+	}
+	opts := append([]notion.Option{notion.WithMiddleware(refuseProfileWrites(t.Profile))}, clientOptions()...)
+	c := notion.New(t.Token, opts...)
+	c.OnObject(recordRecent)
+	c.CacheSchemas()
+	return c
+}
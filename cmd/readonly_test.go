@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	const pageID = "11111111-1111-1111-1111-111111111111"
+	var sent []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"object":"page","id":"` + pageID + `","properties":{"Status":{"id":"s","type":"select","select":null}},"results":[],"has_more":false}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	_, _, err := executeCommand("page", "set", pageID, "Status=Done", "--read-only")
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("page set --read-only: err = %v", err)
+	}
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("search", "notes", "--read-only"); err != nil {
+			t.Errorf("search --read-only: %v", err)
+		}
+	})
+	if strings.Join(sent, ",") != "GET /v1/pages/"+pageID+",POST /v1/search" {
+		t.Errorf("requests = %v", sent)
+	}
+
+	// A read-only profile can't be talked out of it.
+	t.Setenv("NOTION_TOKEN", "")
+	cfg := &config.Config{CurrentProfile: "agent"}
+	cfg.SetProfile("agent", &config.Profile{Token: "secret_agent", ReadOnly: true})
+	if err := config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = executeCommand("page", "set", pageID, "Status=Done", "--read-only=false")
+	if err == nil || !strings.Contains(err.Error(), `profile "agent" is read-only`) {
+		t.Errorf("read-only profile: err = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"golang.org/x/term"
+)
+
+// isInteractive reports whether both stdin and stderr are terminals, i.e.
+// a person is there to answer a prompt.
+var isInteractive = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// offerReauth handles a command that failed because its token was rejected
+// (HTTP 401). When a person is at the terminal and the token came from a
+// saved profile, it offers to log that profile in again. It returns true
+// if a new token was saved.
+//
+// Tokens from NOTION_TOKEN are never replaced: the environment is the
+// caller's to fix.
+func offerReauth(err error, in io.Reader, out io.Writer) bool {
+	if !errors.Is(err, notion.ErrUnauthorized) || !isInteractive() {
+		return false
+	}
+	_, source := resolveToken()
+	if source == "" || source == "NOTION_TOKEN" {
+		return false
+	}
+	profileName := "default"
+	if cfg, err := config.Load(); err == nil {
+		profileName = cfg.ActiveProfile()
+	}
+
+	scanner := bufio.NewScanner(in)
+	fmt.Fprintf(out, "The token for profile %q was rejected (revoked or expired).\n", profileName)
+	fmt.Fprint(out, "Log in again now? [y/N] ")
+	if !scanner.Scan() {
+		return false
+	}
+	if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+		return false
+	}
+
+	fmt.Fprint(out, "Paste your integration token: ")
+	if !scanner.Scan() {
+		return false
+	}
+	token := strings.TrimSpace(scanner.Text())
+	if token == "" {
+		fmt.Fprintln(out, "No token provided.")
+		return false
+	}
+	workspaceName, err := saveLogin(profileName, token, nil)
+	if err != nil {
+		fmt.Fprintf(out, "✗ %v\n", err)
+		return false
+	}
+	fmt.Fprintf(out, "✓ Logged in to %s. Re-run the command to continue.\n", workspaceName)
+	return true
+}
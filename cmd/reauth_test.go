@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestOfferReauthSavesNewToken(t *testing.T) {
+	server := setupAuthTest(t)
+	defer server.Close()
+	old := isInteractive
+	isInteractive = func() bool { return true }
+	defer func() { isInteractive = old }()
+
+	config.Save(&config.Config{
+		CurrentProfile: "work",
+		Profiles:       map[string]*config.Profile{"work": {Token: "secret_expired"}},
+	})
+
+	var out bytes.Buffer
+	err := fmt.Errorf("get page: %w", notion.ErrUnauthorized)
+	if !offerReauth(err, strings.NewReader("y\nsecret_work_token\n"), &out) {
+		t.Fatalf("offerReauth returned false; output:\n%s", out.String())
+	}
+	cfg, _ := config.Load()
+	if cfg.Profiles["work"].Token != "secret_work_token" || cfg.CurrentProfile != "work" {
+		t.Errorf("profile not updated: %+v", cfg.Profiles["work"])
+	}
+}
+
+func TestOfferReauthSkipsOtherErrorsAndEnvTokens(t *testing.T) {
+	server := setupAuthTest(t)
+	defer server.Close()
+	old := isInteractive
+	isInteractive = func() bool { return true }
+	defer func() { isInteractive = old }()
+
+	config.Save(&config.Config{
+		CurrentProfile: "default",
+		Profiles:       map[string]*config.Profile{"default": {Token: "secret_expired"}},
+	})
+
+	var out bytes.Buffer
+	if offerReauth(errors.New("restricted_resource: nope"), strings.NewReader("y\n"), &out) {
+		t.Error("should not prompt for non-401 errors")
+	}
+
+	t.Setenv("NOTION_TOKEN", "secret_expired")
+	if offerReauth(notion.ErrUnauthorized, strings.NewReader("y\nsecret_valid_token\n"), &out) {
+		t.Error("should not replace a token supplied through NOTION_TOKEN")
+	}
+	if out.Len() != 0 {
+		t.Errorf("unexpected prompt: %q", out.String())
+	}
+}
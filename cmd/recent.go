@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// maxRecentItems bounds the most-recently-used list.
+const maxRecentItems = 50
+
+// recentItem is one entry of the most-recently-used list.
+type recentItem struct {
+	ID         string    `json:"id"`
+	Object     string    `json:"object"`
+	Title      string    `json:"title"`
+	URL        string    `json:"url,omitempty"`
+	AccessedAt time.Time `json:"accessed_at"`
+}
+
+var (
+	recentMu sync.Mutex
+	// recentSeen collects the objects accessed by this run, oldest first;
+	// saveRecent merges them into the file when the command finishes.
+	recentSeen []recentItem
+)
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List pages and databases the CLI accessed recently",
+	Long: `List the pages and databases recently read, created or updated through
+the CLI, most recent first.
+
+Recent items can be referenced anywhere an ID is expected: @last (or @1)
+is the most recent, @2 the one before, and so on. @new (or ^) is the page
+created by the latest 'page create' or 'db add'.
+
+Examples:
+  notion recent
+  notion page view @last
+  notion db query @2 --limit 5
+  notion page create abc123 --title "Notes" && notion block append @new "Hello"
+  notion recent --clear`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			if err := os.Remove(recentPath()); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("clear recent items: %w", err)
+			}
+			fmt.Println("✓ Recent items cleared")
+			return nil
+		}
+
+		items := loadRecent()
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(items) > limit {
+			items = items[:limit]
+		}
+		if outputFormat == "json" {
+			if items == nil {
+				items = []recentItem{}
+			}
+			return render.JSON(items)
+		}
+		if len(items) == 0 {
+			fmt.Println("No recent items yet.")
+			return nil
+		}
+		var rows [][]string
+		for i, item := range items {
+			rows = append(rows, []string{
+				"@" + strconv.Itoa(i+1),
+				item.Object,
+				item.Title,
+				item.ID,
+				item.AccessedAt.Local().Format("2006-01-02 15:04"),
+			})
+		}
+		render.Table([]string{"REF", "TYPE", "TITLE", "ID", "ACCESSED"}, rows)
+		return nil
+	},
+}
+
+func init() {
+	recentCmd.Flags().Int("limit", 0, "Show at most this many items")
+	recentCmd.Flags().Bool("clear", false, "Forget all recent items")
+}
+
+func recentPath() string {
+	return filepath.Join(config.CacheDir(), "recent.json")
+}
+
+// recordRecent notes an object returned by the API. It is registered as
+// the client's object hook by newClient.
+func recordRecent(obj map[string]interface{}) {
+	id, _ := obj["id"].(string)
+	if id == "" {
+		return
+	}
+	if inTrash, _ := obj["in_trash"].(bool); inTrash {
+		return
+	}
+	kind, _ := obj["object"].(string)
+	url, _ := obj["url"].(string)
+	item := recentItem{ID: id, Object: kind, Title: render.ExtractTitle(obj), URL: url, AccessedAt: time.Now().UTC()}
+
+	recentMu.Lock()
+	recentSeen = append(recentSeen, item)
+	recentMu.Unlock()
+}
+
+// loadRecent returns the saved recent items, most recent first.
+func loadRecent() []recentItem {
+	data, err := os.ReadFile(recentPath())
+	if err != nil {
+		return nil
+	}
+	var items []recentItem
+	if json.Unmarshal(data, &items) != nil {
+		return nil
+	}
+	return items
+}
+
+// saveRecent merges the objects accessed by this run into the saved list.
+// Failures are ignored: the list is a convenience.
+func saveRecent() {
+	recentMu.Lock()
+	seen := recentSeen
+	recentSeen = nil
+	recentMu.Unlock()
+	if len(seen) == 0 {
+		return
+	}
+
+	items := mergeRecent(loadRecent(), seen)
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(config.CacheDir(), 0700); err != nil {
+		return
+	}
+	os.WriteFile(recentPath(), data, 0600)
+}
+
+// mergeRecent puts the items of seen (oldest first) in front of saved
+// (most recent first), dropping duplicates and trimming the list.
+func mergeRecent(saved, seen []recentItem) []recentItem {
+	var merged []recentItem
+	have := map[string]bool{}
+	for i := len(seen) - 1; i >= 0; i-- {
+		if !have[seen[i].ID] {
+			have[seen[i].ID] = true
+			merged = append(merged, seen[i])
+		}
+	}
+	for _, item := range saved {
+		if !have[item.ID] {
+			have[item.ID] = true
+			merged = append(merged, item)
+		}
+	}
+	if len(merged) > maxRecentItems {
+		merged = merged[:maxRecentItems]
+	}
+	return merged
+}
+
+func lastCreatedPath() string {
+	return filepath.Join(config.CacheDir(), "last_created")
+}
+
+// rememberCreated saves the ID of the object in an API response as the
+// target of @new. Failures are ignored.
+func rememberCreated(data []byte) {
+	var obj map[string]interface{}
+	if json.Unmarshal(data, &obj) != nil {
+		return
+	}
+	id, _ := obj["id"].(string)
+	if id == "" {
+		return
+	}
+	if err := os.MkdirAll(config.CacheDir(), 0700); err != nil {
+		return
+	}
+	os.WriteFile(lastCreatedPath(), []byte(id+"\n"), 0600)
+}
+
+var recentRefRe = regexp.MustCompile(`^@(last|new|[0-9]+)$`)
+
+// defaultDatabase is the database @db stands for: NOTION_DEFAULT_DB, else
+// the default_db setting.
+func defaultDatabase() (string, error) {
+	db := os.Getenv("NOTION_DEFAULT_DB")
+	if db == "" {
+		cfg, _ := config.Load()
+		db = cfg.Setting("default_db")
+	}
+	if db == "" {
+		return "", fmt.Errorf("@db: no default database; set NOTION_DEFAULT_DB or 'notion config set default_db <db-id>'")
+	}
+	return util.ResolveID(db), nil
+}
+
+// resolveRecentRef turns @last / @N into the ID of that recent item,
+// @new / ^ into the ID of the last created page, and @db into the default
+// database. ok is false when ref is not such a reference.
+func resolveRecentRef(ref string) (id string, ok bool, err error) {
+	if ref == "^" {
+		ref = "@new"
+	}
+	if ref == "@db" {
+		id, err := defaultDatabase()
+		return id, true, err
+	}
+	m := recentRefRe.FindStringSubmatch(ref)
+	if m == nil {
+		return "", false, nil
+	}
+	if m[1] == "new" {
+		data, err := os.ReadFile(lastCreatedPath())
+		if err != nil || strings.TrimSpace(string(data)) == "" {
+			return "", true, fmt.Errorf("%s: nothing created yet; run 'notion page create' or 'notion db add' first", ref)
+		}
+		return strings.TrimSpace(string(data)), true, nil
+	}
+	n := 1
+	if m[1] != "last" {
+		n, _ = strconv.Atoi(m[1])
+	}
+	items := loadRecent()
+	if n < 1 || n > len(items) {
+		if len(items) == 0 {
+			return "", true, fmt.Errorf("%s: no recent items yet", ref)
+		}
+		return "", true, fmt.Errorf("%s: only %d recent item(s); see 'notion recent'", ref, len(items))
+	}
+	return items[n-1].ID, true, nil
+}
+
+// idFlagAnnotation marks string flags taking an ID or URL, the only flags
+// whose recent references are expanded.
+const idFlagAnnotation = "notion_id"
+
+// markIDFlags marks cmd's flags names as taking an ID or URL.
+func markIDFlags(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		cmd.Flags().SetAnnotation(name, idFlagAnnotation, []string{"true"})
+	}
+}
+
+var (
+	// usageFlagRe matches a flag with its value in a Use line, as in
+	// "sync <page-id|url> --file <path>".
+	usageFlagRe = regexp.MustCompile(`\[?--\S+ <[^>]*>\]?`)
+	// usageArgRe matches the placeholders of positional args in a Use
+	// line, with the "..." of a repeated one.
+	usageArgRe = regexp.MustCompile(`(<[^<>]+>|\[[^\[\]]+\])(\.\.\.)?`)
+)
+
+// recentIDPlaceholders are the Use line placeholders naming an ID that a
+// recent reference can stand for.
+var recentIDPlaceholders = map[string]bool{
+	"id":            true,
+	"page-id":       true,
+	"db-id":         true,
+	"parent-id":     true,
+	"block-id":      true,
+	"row-id":        true,
+	"page-or-db-id": true,
+}
+
+// idArgs reports which of n positional args of cmd take an ID or URL,
+// going by the placeholders of its Use line: those naming the ID of a
+// page, database or block ("<page-id|url>", "<id|url>") or a page or
+// database ("<target-page>"). Comment, upload and user IDs are never
+// recent refs. Args past the last placeholder fill it when it repeats
+// ("...").
+func idArgs(cmd *cobra.Command, n int) []bool {
+	placeholders := usageArgRe.FindAllString(usageFlagRe.ReplaceAllString(cmd.Use, ""), -1)
+	ids := make([]bool, n)
+	for i := range ids {
+		p := i
+		if last := len(placeholders) - 1; p > last {
+			if last < 0 || !strings.Contains(placeholders[last], "...") {
+				break
+			}
+			p = last
+		}
+		for _, alt := range strings.Split(strings.Trim(placeholders[p], "<>[]. "), "|") {
+			alt = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(alt), "..."))
+			if recentIDPlaceholders[alt] || strings.Contains(alt, "page") || strings.Contains(alt, "database") {
+				ids[i] = true
+			}
+		}
+	}
+	return ids
+}
+
+// expandRecentRefs replaces @last / @N / @new / ^ / @db references with
+// the IDs they stand for, so every command taking an ID accepts them: in
+// the positional args idArgs picks and in flags marked with markIDFlags.
+// Other args and flags, text and titles, are left as they are.
+func expandRecentRefs(cmd *cobra.Command, args []string) error {
+	for i, isID := range idArgs(cmd, len(args)) {
+		if !isID {
+			continue
+		}
+		id, ok, err := resolveRecentRef(args[i])
+		if err != nil {
+			return err
+		}
+		if ok {
+			args[i] = id
+		}
+	}
+
+	var flagErr error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if flagErr != nil || f.Value.Type() != "string" || f.Annotations[idFlagAnnotation] == nil {
+			return
+		}
+		id, ok, err := resolveRecentRef(f.Value.String())
+		if err != nil {
+			flagErr = fmt.Errorf("--%s %w", f.Name, err)
+			return
+		}
+		if ok {
+			flagErr = f.Value.Set(id)
+		}
+	})
+	return flagErr
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestMergeRecent(t *testing.T) {
+	saved := []recentItem{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	seen := []recentItem{{ID: "c"}, {ID: "d"}, {ID: "c"}}
+	var got []string
+	for _, item := range mergeRecent(saved, seen) {
+		got = append(got, item.ID)
+	}
+	if strings.Join(got, ",") != "c,d,a,b" {
+		t.Errorf("merged = %v, want [c d a b]", got)
+	}
+}
+
+func TestRecentRefs(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"object":     "database",
+			"id":         id,
+			"title":      []interface{}{map[string]interface{}{"plain_text": "DB " + id}},
+			"properties": map[string]interface{}{},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if _, _, err := resolveRecentRef("@last"); err == nil {
+		t.Fatal("expected an error with no recent items")
+	}
+
+	recentSeen = nil // drop objects recorded by other tests
+	c := newClient("secret_test")
+	c.GetDatabase("first")
+	c.GetDatabase("second")
+	saveRecent()
+
+	if id, ok, err := resolveRecentRef("@last"); !ok || err != nil || id != "second" {
+		t.Errorf("@last = %q, %v, %v", id, ok, err)
+	}
+	if id, _, _ := resolveRecentRef("@2"); id != "first" {
+		t.Errorf("@2 = %q, want first", id)
+	}
+	if _, _, err := resolveRecentRef("@3"); err == nil || !strings.Contains(err.Error(), "only 2") {
+		t.Errorf("@3 error = %v", err)
+	}
+	if _, ok, _ := resolveRecentRef("@john"); ok {
+		t.Error("@john should not be a recent reference")
+	}
+
+	requested = nil
+	if _, _, err := executeCommand("db", "schema", "@2", "--format", "json"); err != nil {
+		t.Fatalf("db schema @2: %v", err)
+	}
+	if len(requested) != 1 || requested[0] != "/v1/databases/first" {
+		t.Errorf("requested = %v, want /v1/databases/first", requested)
+	}
+}
+
+func TestNewRef(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	if _, ok, err := resolveRecentRef("@new"); !ok || err == nil {
+		t.Fatalf("@new before any create: ok=%v err=%v", ok, err)
+	}
+
+	rememberCreated([]byte(`{"object":"page","id":"new-page-id"}`))
+	for _, ref := range []string{"@new", "^"} {
+		if id, ok, err := resolveRecentRef(ref); !ok || err != nil || id != "new-page-id" {
+			t.Errorf("%s = %q, %v, %v", ref, id, ok, err)
+		}
+	}
+}
+
+func TestExpandRecentRefsOnlyIDs(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	rememberCreated([]byte(`{"object":"page","id":"new-page-id"}`))
+	defer resetCommandFlags(rootCmd)
+
+	args := []string{"^", "^"}
+	if err := expandRecentRefs(commentAddCmd, args); err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != "new-page-id" || args[1] != "^" {
+		t.Errorf("comment add args = %v, want the page expanded and the text kept", args)
+	}
+	args = []string{"^", "@new"}
+	if err := expandRecentRefs(pageMergeCmd, args); err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != "new-page-id" || args[1] != "new-page-id" {
+		t.Errorf("page merge args = %v, want both expanded", args)
+	}
+	args = []string{"@2"}
+	if err := expandRecentRefs(searchCmd, args); err != nil || args[0] != "@2" {
+		t.Errorf("search args = %v (%v), want the query kept", args, err)
+	}
+	for _, c := range []*cobra.Command{userGetCmd, commentGetCmd, fileGetCmd} {
+		args = []string{"@1"}
+		if err := expandRecentRefs(c, args); err != nil || args[0] != "@1" {
+			t.Errorf("%s args = %v (%v), want the ID kept", c.CommandPath(), args, err)
+		}
+	}
+
+	pageCreateCmd.Flags().Set("title", "^")
+	if err := expandRecentRefs(pageCreateCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if title, _ := pageCreateCmd.Flags().GetString("title"); title != "^" {
+		t.Errorf("--title = %q, want it kept", title)
+	}
+	pageMoveCmd.Flags().Set("to", "^")
+	if err := expandRecentRefs(pageMoveCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if to, _ := pageMoveCmd.Flags().GetString("to"); to != "new-page-id" {
+		t.Errorf("--to = %q, want the page's ID", to)
+	}
+}
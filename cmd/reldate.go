@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+var (
+	// relativeDatesFlag is --relative-dates; the relative_dates setting
+	// turns it on by default.
+	relativeDatesFlag bool
+	// relativeDates is in force for the running command.
+	relativeDates bool
+	// timeNow is the clock relative dates are measured from.
+	timeNow = time.Now
+)
+
+// resetRelativeDates resolves --relative-dates for a new command.
+func resetRelativeDates() {
+	relativeDates = relativeDatesFlag
+	if !relativeDates {
+		if cfg, err := config.Load(); err == nil {
+			relativeDates = isTruthy(cfg.Setting("relative_dates"))
+		}
+	}
+}
+
+// displayPropertyValue is extractPropertyValue for people to read: with
+// --relative-dates, dates and timestamps read "2 hours ago". JSON output
+// and exports keep the absolute values.
+func displayPropertyValue(prop map[string]interface{}) string {
+	if relativeDates {
+		switch prop["type"] {
+		case "created_time", "last_edited_time":
+			ts, _ := prop[prop["type"].(string)].(string)
+			return displayTime(ts)
+		case "date":
+			d, _ := prop["date"].(map[string]interface{})
+			start, _ := d["start"].(string)
+			if end, _ := d["end"].(string); end != "" {
+				return displayTime(start) + " → " + displayTime(end)
+			}
+			return displayTime(start)
+		}
+	}
+	return extractPropertyValue(prop)
+}
+
+// displayDate shows a timestamp in a table column: its date, or with
+// --relative-dates how long ago it was.
+func displayDate(ts string) string {
+	if relativeDates {
+		return displayTime(ts)
+	}
+	if len(ts) > 10 {
+		return ts[:10]
+	}
+	return ts
+}
+
+// displayTime is ts relative to now when --relative-dates is on, and
+// unchanged otherwise or when it doesn't parse.
+func displayTime(ts string) string {
+	if !relativeDates || ts == "" {
+		return ts
+	}
+	if t, err := time.Parse(time.RFC3339, ts); err == nil {
+		return humanizeTime(t, timeNow())
+	}
+	if day, err := time.ParseInLocation("2006-01-02", ts, dateLocation); err == nil {
+		return humanizeDay(day, timeNow())
+	}
+	return ts
+}
+
+// humanizeTime says when t was (or will be) seen from now: "just now",
+// "5 minutes ago", "yesterday", "in 3 days", then the date once it's
+// more than a week away. Calendar days are those of the --tz zone.
+func humanizeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d >= 0 && d < time.Minute:
+		return "just now"
+	case d > 0 && d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " ago"
+	case d < 0 && -d < time.Hour:
+		return "in " + plural(int((-d+time.Minute-1)/time.Minute), "minute")
+	case d > 0 && d < 12*time.Hour:
+		return plural(int(d/time.Hour), "hour") + " ago"
+	case d < 0 && -d < 12*time.Hour:
+		return "in " + plural(int(-d/time.Hour), "hour")
+	}
+	return humanizeDay(t, now)
+}
+
+// humanizeDay says which day t falls on, seen from now.
+func humanizeDay(t, now time.Time) string {
+	t, now = t.In(dateLocation), now.In(dateLocation)
+	day := func(x time.Time) time.Time { return time.Date(x.Year(), x.Month(), x.Day(), 0, 0, 0, 0, time.UTC) }
+	days := int(day(now).Sub(day(t)).Hours() / 24)
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days == -1:
+		return "tomorrow"
+	case days > 1 && days < 7:
+		return fmt.Sprintf("%d days ago", days)
+	case days < -1 && days > -7:
+		return fmt.Sprintf("in %d days", -days)
+	case t.Year() == now.Year():
+		return t.Format("Jan 2")
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// plural is "1 hour" or "n hours".
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
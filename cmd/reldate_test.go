@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanizeTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	old := dateLocation
+	dateLocation = time.UTC
+	defer func() { dateLocation = old }()
+
+	for ts, want := range map[string]string{
+		"2026-03-10T14:59:30Z": "just now",
+		"2026-03-10T14:59:00Z": "1 minute ago",
+		"2026-03-10T14:15:00Z": "45 minutes ago",
+		"2026-03-10T13:00:00Z": "2 hours ago",
+		"2026-03-10T16:00:00Z": "in 1 hour",
+		"2026-03-10T01:00:00Z": "today",
+		"2026-03-09T10:00:00Z": "yesterday",
+		"2026-03-07T10:00:00Z": "3 days ago",
+		"2026-03-01T10:00:00Z": "Mar 1",
+		"2025-12-24T10:00:00Z": "Dec 24, 2025",
+	} {
+		parsed, _ := time.Parse(time.RFC3339, ts)
+		if got := humanizeTime(parsed, now); got != want {
+			t.Errorf("humanizeTime(%s) = %q, want %q", ts, got, want)
+		}
+	}
+	for day, want := range map[string]string{"2026-03-11": "tomorrow", "2026-03-13": "in 3 days", "2026-03-10": "today"} {
+		parsed, _ := time.Parse("2006-01-02", day)
+		if got := humanizeDay(parsed, now); got != want {
+			t.Errorf("humanizeDay(%s) = %q, want %q", day, got, want)
+		}
+	}
+}
+
+func TestDisplayPropertyValueRelative(t *testing.T) {
+	prop := map[string]interface{}{"type": "last_edited_time", "last_edited_time": "2026-03-10T13:00:00.000Z"}
+	oldNow, oldRel := timeNow, relativeDates
+	timeNow = func() time.Time { return time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC) }
+	defer func() { timeNow, relativeDates = oldNow, oldRel }()
+
+	relativeDates = false
+	if got := displayPropertyValue(prop); got != "2026-03-10T13:00:00.000Z" {
+		t.Errorf("absolute = %q", got)
+	}
+	relativeDates = true
+	if got := displayPropertyValue(prop); got != "2 hours ago" {
+		t.Errorf("relative = %q, want 2 hours ago", got)
+	}
+}
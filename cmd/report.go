@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports built from tracked database history",
+}
+
+// burndownDay is one line of 'report burndown': the rows of the sprint at
+// the end of a day.
+type burndownDay struct {
+	Date  string `json:"date"`
+	Open  int    `json:"open"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+}
+
+var reportBurndownCmd = &cobra.Command{
+	Use:   "burndown",
+	Short: "Open vs. done rows of a sprint per day",
+	Long: `Count the open and done rows of a sprint at the end of each day, from the
+history 'db track' keeps: the report takes a snapshot first, then replays
+the recorded changes backwards to get each day's values. Days before the
+database was first tracked have no data, so start tracking when a sprint
+starts (and snapshot daily, e.g. from cron) to get a full burndown.
+
+A row is in the sprint when --sprint-prop holds --sprint (one of its
+values, for multi-selects); without --sprint, the sprint with the most
+open rows is used. A row is done as in 'task rollover': by its status
+being in the Complete group, a Done checkbox or a select reading Done,
+Closed and the like (--done picks the property).
+
+--format csv prints date,open,done,total lines for a spreadsheet.
+
+Examples:
+  notion report burndown --db abc123 --sprint-prop Sprint
+  notion report burndown --db abc123 --sprint-prop Sprint --sprint "Sprint 12" --from 2026-10-05
+  notion report burndown --db abc123 --sprint-prop Sprint --format csv > burndown.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbFlag, _ := cmd.Flags().GetString("db")
+		sprintProp, _ := cmd.Flags().GetString("sprint-prop")
+		sprint, _ := cmd.Flags().GetString("sprint")
+		doneFlag, _ := cmd.Flags().GetString("done")
+		fromFlag, _ := cmd.Flags().GetString("from")
+		toFlag, _ := cmd.Flags().GetString("to")
+		if dbFlag == "" || sprintProp == "" {
+			return usageError(fmt.Errorf("--db and --sprint-prop are required"))
+		}
+		var from, to time.Time
+		for _, f := range []struct {
+			flag, value string
+			t           *time.Time
+		}{{"--from", fromFlag, &from}, {"--to", toFlag, &to}} {
+			if f.value == "" {
+				continue
+			}
+			t, err := time.ParseInLocation("2006-01-02", f.value, dateLocation)
+			if err != nil {
+				return usageError(fmt.Errorf("%s must be a date like 2026-10-01", f.flag))
+			}
+			*f.t = t
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		dbID := util.ResolveID(dbFlag)
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database schema: %w", err)
+		}
+		schema, _ := db["properties"].(map[string]interface{})
+		names, _, err := resolveColumns(schema, []string{sprintProp})
+		if err != nil {
+			return err
+		}
+		sprintProp = names[0]
+		doneProp, err := doneProperty(schema, doneFlag)
+		if err != nil {
+			return err
+		}
+		isDone, err := doneValueCheck(schema, doneProp)
+		if err != nil {
+			return err
+		}
+
+		// Track the two properties along with whatever is tracked already.
+		props := loadDBTrackHistory(dbID).Props
+		tracked := len(props) > 0
+		for _, p := range []string{sprintProp, doneProp} {
+			if !contains(props, p) {
+				if tracked {
+					fmt.Fprintf(os.Stderr, "note: %s wasn't tracked before; its history starts now\n", p)
+				}
+				props = append(props, p)
+			}
+		}
+		run, err := trackDatabase(c, dbID, props)
+		if err != nil {
+			return err
+		}
+		history := run.history
+
+		if sprint == "" {
+			sprint = busiestSprint(history, sprintProp, doneProp, isDone)
+			if sprint == "" {
+				return usageError(fmt.Errorf("no row has a %s; pick the sprint with --sprint", sprintProp))
+			}
+		}
+		first := history.FirstRun.In(dateLocation)
+		first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, dateLocation)
+		if from.Before(first) {
+			from = first
+		}
+		if to.IsZero() {
+			to = history.LastRun.In(dateLocation)
+		}
+		days := burndown(history, sprintProp, sprint, doneProp, isDone, from, to)
+
+		switch outputFormat {
+		case "json":
+			return render.JSON(map[string]interface{}{"database_id": dbID, "sprint_prop": sprintProp, "sprint": sprint, "done_prop": doneProp, "days": days})
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"date", "open", "done", "total"})
+			for _, d := range days {
+				w.Write([]string{d.Date, strconv.Itoa(d.Open), strconv.Itoa(d.Done), strconv.Itoa(d.Total)})
+			}
+			w.Flush()
+			return w.Error()
+		}
+		if len(days) == 0 {
+			fmt.Printf("No tracked days between %s and %s.\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+			return nil
+		}
+		most := 0
+		for _, d := range days {
+			if d.Total > most {
+				most = d.Total
+			}
+		}
+		fmt.Printf("%s: %s\n\n", sprintProp, sprint)
+		var table [][]string
+		for _, d := range days {
+			table = append(table, []string{d.Date, strconv.Itoa(d.Open), strconv.Itoa(d.Done), strconv.Itoa(d.Total), burndownBar(d.Open, most, 30)})
+		}
+		render.Table([]string{"DATE", "OPEN", "DONE", "TOTAL", ""}, table)
+		return nil
+	},
+}
+
+func init() {
+	reportBurndownCmd.Flags().String("db", "", "Database of the sprint's rows")
+	reportBurndownCmd.Flags().String("sprint-prop", "", "Property holding each row's sprint")
+	reportBurndownCmd.Flags().String("sprint", "", "Sprint to report on (default: the one with the most open rows)")
+	reportBurndownCmd.Flags().String("done", "", "Status, checkbox or select property telling done rows")
+	reportBurndownCmd.Flags().String("from", "", "First day (YYYY-MM-DD; default: the first tracked day)")
+	reportBurndownCmd.Flags().String("to", "", "Last day (YYYY-MM-DD; default: today)")
+	markIDFlags(reportBurndownCmd, "db")
+	reportCmd.AddCommand(reportBurndownCmd)
+}
+
+// doneValueCheck tells done values of the property doneProperty picked,
+// as 'db track' records them as text.
+func doneValueCheck(schema map[string]interface{}, name string) (func(string) bool, error) {
+	prop, _ := schema[name].(map[string]interface{})
+	switch prop["type"] {
+	case "checkbox":
+		return func(v string) bool { return v == "✓" }, nil
+	case "select":
+		return func(v string) bool { return doneWords[strings.ToLower(v)] }, nil
+	case "status":
+		complete := map[string]bool{}
+		ids := completeStatusOptions(prop)
+		status, _ := prop["status"].(map[string]interface{})
+		options, _ := status["options"].([]interface{})
+		for _, o := range options {
+			option, _ := o.(map[string]interface{})
+			if id, _ := option["id"].(string); ids[id] {
+				optName, _ := option["name"].(string)
+				complete[optName] = true
+			}
+		}
+		return func(v string) bool {
+			return complete[v] || (len(complete) == 0 && doneWords[strings.ToLower(v)])
+		}, nil
+	}
+	return nil, usageError(fmt.Errorf("--done %s is a %v property; use a status, checkbox or select", name, prop["type"]))
+}
+
+// inSprint reports whether a sprint property value holds sprint.
+func inSprint(value, sprint string) bool {
+	for _, v := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), sprint) {
+			return true
+		}
+	}
+	return false
+}
+
+// busiestSprint is the sprint with the most open rows now, or with the
+// most rows when all are done.
+func busiestSprint(history dbTrackHistory, sprintProp, doneProp string, isDone func(string) bool) string {
+	open, total := map[string]int{}, map[string]int{}
+	for _, row := range history.Rows {
+		for _, v := range strings.Split(row.Values[sprintProp], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				total[v]++
+				if !isDone(row.Values[doneProp]) {
+					open[v]++
+				}
+			}
+		}
+	}
+	best := ""
+	for _, v := range sortedKeys(total) {
+		if open[v] > open[best] || (open[v] == open[best] && total[v] > total[best]) {
+			best = v
+		}
+	}
+	return best
+}
+
+// burndown counts the rows of sprint at the end of each day from from to
+// to, undoing the recorded changes made after each day, latest first.
+// Rows are counted from the run that first saw them.
+func burndown(history dbTrackHistory, sprintProp, sprint, doneProp string, isDone func(string) bool, from, to time.Time) []burndownDay {
+	values := map[string]map[string]string{}
+	for id, row := range history.Rows {
+		values[id] = map[string]string{sprintProp: row.Values[sprintProp], doneProp: row.Values[doneProp]}
+	}
+	changes := append([]dbTrackChange(nil), history.Changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Time.Before(changes[j].Time) })
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, dateLocation)
+	var days []burndownDay
+	undone := len(changes)
+	for day := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, dateLocation); !day.Before(from); day = day.AddDate(0, 0, -1) {
+		end := day.AddDate(0, 0, 1)
+		for undone > 0 && !changes[undone-1].Time.Before(end) {
+			undone--
+			ch := changes[undone]
+			if v, ok := values[ch.RowID]; ok && (ch.Property == sprintProp || ch.Property == doneProp) {
+				v[ch.Property] = ch.Old
+			}
+		}
+		d := burndownDay{Date: day.Format("2006-01-02")}
+		for id, v := range values {
+			if added := history.Rows[id].Added; !added.IsZero() && !added.Before(end) {
+				continue
+			}
+			if !inSprint(v[sprintProp], sprint) {
+				continue
+			}
+			d.Total++
+			if isDone(v[doneProp]) {
+				d.Done++
+			} else {
+				d.Open++
+			}
+		}
+		days = append(days, d)
+	}
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+	return days
+}
+
+// burndownBar draws n out of most as a bar width characters wide at most.
+func burndownBar(n, most, width int) string {
+	if most == 0 {
+		return ""
+	}
+	return strings.Repeat("█", (n*width+most-1)/most)
+}
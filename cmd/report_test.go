@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBurndown(t *testing.T) {
+	oldLoc := dateLocation
+	dateLocation = time.UTC
+	defer func() { dateLocation = oldLoc }()
+	day := func(d, h int) time.Time { return time.Date(2026, 10, d, h, 0, 0, 0, time.UTC) }
+
+	history := dbTrackHistory{
+		FirstRun: day(12, 9),
+		LastRun:  day(15, 18),
+		Rows: map[string]dbTrackRow{
+			"a": {Values: map[string]string{"Sprint": "S1", "Status": "Done"}},
+			"b": {Values: map[string]string{"Sprint": "S1", "Status": "Done"}},
+			"c": {Values: map[string]string{"Sprint": "S1, S2", "Status": "Todo"}},
+			"d": {Added: day(14, 9), Values: map[string]string{"Sprint": "S1", "Status": "Todo"}},
+			"e": {Values: map[string]string{"Sprint": "S2", "Status": "Todo"}},
+		},
+		Changes: []dbTrackChange{
+			{Time: day(13, 10), RowID: "a", Property: "Status", Old: "Todo", New: "Done"},
+			{Time: day(15, 10), RowID: "b", Property: "Status", Old: "Todo", New: "Done"},
+			{Time: day(14, 10), RowID: "e", Property: "Sprint", Old: "S1", New: "S2"},
+		},
+	}
+	isDone := func(v string) bool { return v == "Done" }
+	got := burndown(history, "Sprint", "s1", "Status", isDone, day(12, 0), day(15, 0))
+	want := []burndownDay{
+		{Date: "2026-10-12", Open: 4, Done: 0, Total: 4},
+		{Date: "2026-10-13", Open: 3, Done: 1, Total: 4},
+		{Date: "2026-10-14", Open: 3, Done: 1, Total: 4},
+		{Date: "2026-10-15", Open: 2, Done: 2, Total: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("burndown = %+v", got)
+	}
+	if s := busiestSprint(history, "Sprint", "Status", isDone); s != "S1" {
+		t.Errorf("busiest sprint = %q", s)
+	}
+}
+
+func TestReportBurndownCSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/query") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				map[string]interface{}{"object": "page", "id": "aaaa", "properties": map[string]interface{}{
+					"Sprint": map[string]interface{}{"type": "select", "select": map[string]interface{}{"name": "S1"}},
+					"Status": map[string]interface{}{"type": "status", "status": map[string]interface{}{"id": "s2", "name": "Shipped"}},
+				}},
+			}, "has_more": false})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": map[string]interface{}{
+			"Name":   map[string]interface{}{"id": "title", "type": "title"},
+			"Sprint": map[string]interface{}{"id": "sp", "type": "select"},
+			"Status": map[string]interface{}{"id": "st", "type": "status", "status": map[string]interface{}{
+				"options": []interface{}{map[string]interface{}{"id": "s1", "name": "Todo"}, map[string]interface{}{"id": "s2", "name": "Shipped"}},
+				"groups":  []interface{}{map[string]interface{}{"name": "Complete", "option_ids": []interface{}{"s2"}}},
+			}},
+		}})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func() { outputFormat = "" }()
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("report", "burndown", "--db", "11111111111111111111111111111111", "--sprint-prop", "sprint", "--format", "csv"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || lines[0] != "date,open,done,total" || !strings.HasSuffix(lines[1], ",0,1,1") {
+		t.Errorf("csv = %q", out)
+	}
+	if _, _, err := executeCommand("report", "burndown", "--db", "11111111111111111111111111111111"); err == nil {
+		t.Error("missing --sprint-prop accepted")
+	}
+}
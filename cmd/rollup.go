@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// rollupResolver recomputes rollup properties client-side by following
+// their relations, so an export doesn't depend on values the API may
+// return stale, truncated (relations past 25 entries) or as raw arrays.
+type rollupResolver struct {
+	c     *notion.Client
+	pages map[string]map[string]interface{} // related pages by ID
+}
+
+func newRollupResolver(c *notion.Client) *rollupResolver {
+	return &rollupResolver{c: c, pages: map[string]map[string]interface{}{}}
+}
+
+// resolveRows replaces every rollup value in rows with one computed from
+// the related pages, and re-reads formulas from the page property
+// endpoint, which evaluates them afresh. Rollups using an aggregation
+// this doesn't know keep the API's value.
+func (r *rollupResolver) resolveRows(schema map[string]interface{}, rows []interface{}) error {
+	var rollups, formulas []string
+	for _, name := range sortedKeys(schema) {
+		prop, _ := schema[name].(map[string]interface{})
+		switch prop["type"] {
+		case "rollup":
+			rollups = append(rollups, name)
+		case "formula":
+			formulas = append(formulas, name)
+		}
+	}
+	for _, row := range rows {
+		page, ok := row.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pageID, _ := page["id"].(string)
+		props, _ := page["properties"].(map[string]interface{})
+		if props == nil {
+			continue
+		}
+		for _, name := range formulas {
+			def, _ := schema[name].(map[string]interface{})
+			id, _ := def["id"].(string)
+			item, err := r.c.GetPageProperty(pageID, id, "")
+			if err != nil {
+				return fmt.Errorf("read formula %s of %s: %w", name, pageID, err)
+			}
+			if f, ok := item["formula"].(map[string]interface{}); ok {
+				props[name] = map[string]interface{}{"id": id, "type": "formula", "formula": f}
+			}
+		}
+		for _, name := range rollups {
+			def, _ := schema[name].(map[string]interface{})
+			conf, _ := def["rollup"].(map[string]interface{})
+			value, ok, err := r.compute(page, schema, conf)
+			if err != nil {
+				return fmt.Errorf("resolve rollup %s of %s: %w", name, pageID, err)
+			}
+			if ok {
+				props[name] = map[string]interface{}{"id": def["id"], "type": "rollup", "rollup": value}
+			}
+		}
+	}
+	return nil
+}
+
+// compute aggregates one rollup for a row. It reports false when the
+// aggregation isn't supported.
+func (r *rollupResolver) compute(page, schema, conf map[string]interface{}) (map[string]interface{}, bool, error) {
+	relName, _ := conf["relation_property_name"].(string)
+	target, _ := conf["rollup_property_name"].(string)
+	function, _ := conf["function"].(string)
+	if relName == "" || target == "" {
+		return nil, false, nil
+	}
+	ids, err := r.relationIDs(page, schema, relName)
+	if err != nil {
+		return nil, false, err
+	}
+	var values []interface{}
+	for _, id := range ids {
+		related, err := r.page(id)
+		if err != nil {
+			return nil, false, err
+		}
+		props, _ := related["properties"].(map[string]interface{})
+		prop, _ := props[target].(map[string]interface{})
+		values = append(values, rollupValues(prop)...)
+	}
+	return aggregateRollup(function, values)
+}
+
+// relationIDs lists the pages a row's relation points at, fetching the
+// full list when the row only carries the first 25.
+func (r *rollupResolver) relationIDs(page, schema map[string]interface{}, relName string) ([]string, error) {
+	props, _ := page["properties"].(map[string]interface{})
+	rel, _ := props[relName].(map[string]interface{})
+	var items []interface{}
+	if more, _ := rel["has_more"].(bool); more || rel == nil {
+		def, _ := schema[relName].(map[string]interface{})
+		propID, _ := def["id"].(string)
+		pageID, _ := page["id"].(string)
+		all, err := r.c.GetPagePropertyAll(pageID, propID)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range all {
+			item, _ := it.(map[string]interface{})
+			items = append(items, item["relation"])
+		}
+	} else {
+		items, _ = rel["relation"].([]interface{})
+	}
+	var ids []string
+	for _, it := range items {
+		if m, ok := it.(map[string]interface{}); ok {
+			if id, _ := m["id"].(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (r *rollupResolver) page(id string) (map[string]interface{}, error) {
+	if p, ok := r.pages[id]; ok {
+		return p, nil
+	}
+	p, err := r.c.GetPage(id)
+	if err != nil {
+		return nil, err
+	}
+	r.pages[id] = p
+	return p, nil
+}
+
+// rollupValues returns the values a related property contributes: one
+// per option, person or relation for list types, else a single value
+// (nil when empty). Numbers are float64, checkboxes bool, dates their
+// start string, everything else text.
+func rollupValues(prop map[string]interface{}) []interface{} {
+	propType, _ := prop["type"].(string)
+	switch propType {
+	case "":
+		return []interface{}{nil}
+	case "number":
+		if n, ok := prop["number"].(float64); ok {
+			return []interface{}{n}
+		}
+		return []interface{}{nil}
+	case "checkbox":
+		b, _ := prop["checkbox"].(bool)
+		return []interface{}{b}
+	case "date":
+		d, _ := prop["date"].(map[string]interface{})
+		if start, _ := d["start"].(string); start != "" {
+			return []interface{}{rollupDate(start)}
+		}
+		return []interface{}{nil}
+	case "formula":
+		f, _ := prop["formula"].(map[string]interface{})
+		fType, _ := f["type"].(string)
+		if fType == "date" {
+			return rollupValues(map[string]interface{}{"type": "date", "date": f["date"]})
+		}
+		if v, ok := f[fType]; ok && v != nil && v != "" {
+			return []interface{}{v}
+		}
+		return []interface{}{nil}
+	case "multi_select", "people", "relation", "files":
+		arr, _ := prop[propType].([]interface{})
+		if len(arr) == 0 {
+			return []interface{}{nil}
+		}
+		var out []interface{}
+		for _, item := range arr {
+			m, _ := item.(map[string]interface{})
+			v, _ := m["name"].(string)
+			if v == "" {
+				v, _ = m["id"].(string)
+			}
+			out = append(out, v)
+		}
+		return out
+	}
+	if text := extractPropertyValue(prop); text != "" {
+		return []interface{}{text}
+	}
+	return []interface{}{nil}
+}
+
+// rollupDate marks a date value so aggregations can tell it from text.
+type rollupDate string
+
+// aggregateRollup applies a Notion rollup function to values, returning
+// the rollup value as the API shapes it ({"type": "number", "number": ...}).
+// Results that aren't numbers are returned as {"type": "text"}.
+func aggregateRollup(function string, values []interface{}) (map[string]interface{}, bool, error) {
+	number := func(n float64) (map[string]interface{}, bool, error) {
+		return map[string]interface{}{"type": "number", "number": n}, true, nil
+	}
+	text := func(s string) (map[string]interface{}, bool, error) {
+		return map[string]interface{}{"type": "text", "text": s}, true, nil
+	}
+
+	var nonEmpty []interface{}
+	var nums []float64
+	var dates []string
+	checked := 0
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		nonEmpty = append(nonEmpty, v)
+		switch v := v.(type) {
+		case float64:
+			nums = append(nums, v)
+		case rollupDate:
+			dates = append(dates, string(v))
+		case bool:
+			if v {
+				checked++
+			}
+		}
+	}
+	total := float64(len(values))
+	percent := func(n int) (map[string]interface{}, bool, error) {
+		if total == 0 {
+			return number(0)
+		}
+		return number(float64(n) / total)
+	}
+	unique := func() []string {
+		seen := map[string]bool{}
+		var out []string
+		for _, v := range nonEmpty {
+			s := rollupText(v)
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	sort.Float64s(nums)
+	sort.Strings(dates)
+
+	switch function {
+	case "count", "count_all":
+		return number(total)
+	case "count_values":
+		return number(float64(len(nonEmpty)))
+	case "unique", "count_unique_values":
+		return number(float64(len(unique())))
+	case "empty", "count_empty":
+		return number(total - float64(len(nonEmpty)))
+	case "not_empty", "count_not_empty":
+		return number(float64(len(nonEmpty)))
+	case "percent_empty":
+		return percent(len(values) - len(nonEmpty))
+	case "percent_not_empty":
+		return percent(len(nonEmpty))
+	case "checked":
+		return number(float64(checked))
+	case "unchecked":
+		return number(total - float64(checked))
+	case "percent_checked":
+		return percent(checked)
+	case "percent_unchecked":
+		return percent(len(values) - checked)
+	case "sum", "average", "median", "min", "max", "range":
+		if len(nums) == 0 {
+			if function == "sum" {
+				return number(0)
+			}
+			return text("")
+		}
+		sum := 0.0
+		for _, n := range nums {
+			sum += n
+		}
+		switch function {
+		case "sum":
+			return number(sum)
+		case "average":
+			return number(sum / float64(len(nums)))
+		case "median":
+			mid := len(nums) / 2
+			if len(nums)%2 == 0 {
+				return number((nums[mid-1] + nums[mid]) / 2)
+			}
+			return number(nums[mid])
+		case "min":
+			return number(nums[0])
+		case "max":
+			return number(nums[len(nums)-1])
+		}
+		return number(nums[len(nums)-1] - nums[0])
+	case "earliest_date", "latest_date", "date_range":
+		if len(dates) == 0 {
+			return text("")
+		}
+		switch function {
+		case "earliest_date":
+			return text(dates[0])
+		case "latest_date":
+			return text(dates[len(dates)-1])
+		}
+		return text(dates[0] + " → " + dates[len(dates)-1])
+	case "show_original", "show_unique":
+		var parts []string
+		if function == "show_unique" {
+			parts = unique()
+		} else {
+			for _, v := range nonEmpty {
+				parts = append(parts, rollupText(v))
+			}
+		}
+		return text(strings.Join(parts, ", "))
+	}
+	return nil, false, nil
+}
+
+func rollupText(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case rollupDate:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAggregateRollup(t *testing.T) {
+	values := []interface{}{3.0, nil, 1.0, 8.0, rollupDate("2026-02-01"), rollupDate("2026-01-15"), true, "a", "a"}
+	for function, want := range map[string]interface{}{
+		"count":             9.0,
+		"count_values":      8.0,
+		"empty":             1.0,
+		"unique":            7.0,
+		"sum":               12.0,
+		"average":           4.0,
+		"median":            3.0,
+		"range":             7.0,
+		"checked":           1.0,
+		"percent_empty":     1.0 / 9,
+		"earliest_date":     "2026-01-15",
+		"date_range":        "2026-01-15 → 2026-02-01",
+		"show_unique":       "3, 1, 8, 2026-02-01, 2026-01-15, true, a",
+		"count_per_group":   nil,
+		"percent_unchecked": 8.0 / 9,
+	} {
+		got, ok, err := aggregateRollup(function, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want == nil {
+			if ok {
+				t.Errorf("%s: unsupported functions should keep the API value", function)
+			}
+			continue
+		}
+		if v := got[got["type"].(string)]; v != want {
+			t.Errorf("%s = %v, want %v", function, v, want)
+		}
+	}
+	if got, _, _ := aggregateRollup("max", []interface{}{nil}); got["text"] != "" {
+		t.Errorf("max of nothing = %v, want empty", got)
+	}
+}
+
+func TestResolveRollupsFollowsFullRelation(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/properties/rel"):
+			// The full relation, over two pages of property items.
+			if r.URL.Query().Get("start_cursor") == "" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+					map[string]interface{}{"type": "relation", "relation": map[string]interface{}{"id": "t1"}},
+				}, "has_more": true, "next_cursor": "c2"})
+			} else {
+				json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+					map[string]interface{}{"type": "relation", "relation": map[string]interface{}{"id": "t2"}},
+				}})
+			}
+		case strings.HasSuffix(r.URL.Path, "/properties/fx"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "property_item", "type": "formula", "formula": map[string]interface{}{"type": "number", "number": 42}})
+		case strings.HasPrefix(r.URL.Path, "/v1/pages/t"):
+			hours := map[string]float64{"t1": 1.5, "t2": 2}[strings.TrimPrefix(r.URL.Path, "/v1/pages/")]
+			json.NewEncoder(w).Encode(map[string]interface{}{"properties": map[string]interface{}{
+				"Hours": map[string]interface{}{"type": "number", "number": hours},
+			}})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+
+	schema := map[string]interface{}{
+		"Tasks": map[string]interface{}{"id": "rel", "type": "relation"},
+		"Total": map[string]interface{}{"id": "tot", "type": "rollup", "rollup": map[string]interface{}{
+			"relation_property_name": "Tasks", "rollup_property_name": "Hours", "function": "sum"}},
+		"Score": map[string]interface{}{"id": "fx", "type": "formula"},
+	}
+	rows := []interface{}{map[string]interface{}{"id": "p1", "properties": map[string]interface{}{
+		"Tasks": map[string]interface{}{"type": "relation", "relation": []interface{}{map[string]interface{}{"id": "t1"}}, "has_more": true},
+		"Total": map[string]interface{}{"type": "rollup", "rollup": map[string]interface{}{"type": "number", "number": 1.5}},
+		"Score": map[string]interface{}{"type": "formula", "formula": map[string]interface{}{"type": "number", "number": 0}},
+	}}}
+
+	if err := newRollupResolver(newClient("secret_test")).resolveRows(schema, rows); err != nil {
+		t.Fatal(err)
+	}
+	props := rows[0].(map[string]interface{})["properties"].(map[string]interface{})
+	if got := extractPropertyValue(props["Total"].(map[string]interface{})); got != "3.5" {
+		t.Errorf("Total = %s, want the sum over the whole relation", got)
+	}
+	if got := extractPropertyValue(props["Score"].(map[string]interface{})); got != "42" {
+		t.Errorf("Score = %s, want the re-read formula", got)
+	}
+}
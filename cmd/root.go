@@ -3,14 +3,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/pkg/notion"
 	"github.com/spf13/cobra"
 )
 
 var (
 	outputFormat string
 	debugMode    bool
+	appLinks     bool
+	noConfig     bool
 	// Version is set by goreleaser ldflags
 	Version = "dev"
 )
@@ -21,22 +25,81 @@ var rootCmd = &cobra.Command{
 	Long: `Work seamlessly with Notion from the command line.
 
 Notion CLI lets you manage pages, databases, blocks, and more
-without leaving your terminal. Built for developers and AI agents.`,
+without leaving your terminal. Built for developers and AI agents.
+
+Exit status:
+  0    success
+  1    other errors
+  2    usage: unknown command or flag, wrong arguments
+  3    auth: not logged in, token rejected, no access, writes refused
+  4    not found: the object doesn't exist or isn't shared
+  5    validation: input rejected by the CLI or the API
+  6    rate limited, even after retrying
+  7    network: Notion unreachable or failing (writes queued offline too)
+  130  interrupted by Ctrl-C`,
 	Version:       Version,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		config.SetDisabled(noConfig || isTruthy(os.Getenv("NOTION_NO_CONFIG")))
+		if err := validateStatsFlag(); err != nil {
+			return usageError(err)
+		}
+		if err := validateProgressFlag(); err != nil {
+			return usageError(err)
+		}
+		if err := validatePorcelainFlag(cmd); err != nil {
+			return usageError(err)
+		}
+		stats.reset()
+		if err := resetRequestBudget(); err != nil {
+			return usageError(err)
+		}
+		if err := resetDeadline(); err != nil {
+			return usageError(err)
+		}
+		if err := resetDateLocation(); err != nil {
+			return usageError(err)
+		}
+		resetRelativeDates()
+		return expandRecentRefs(cmd, args)
+	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	if code, ok, err := runExtension(os.Args[1:]); ok {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		}
+		os.Exit(code)
+	}
+	err := rootCmd.Execute()
+	saveRecent()
+	reportStats(os.Stderr)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		os.Exit(1)
+		offerReauth(err, os.Stdin, os.Stderr)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "", "Output format: json, md, table, text (default: auto)")
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Show HTTP request/response details")
+	rootCmd.PersistentFlags().BoolVar(&appLinks, "app-links", false, "Print and open notion:// links for the desktop app")
+	rootCmd.PersistentFlags().StringVar(&statsFormat, "stats", "", "Print API request statistics to stderr when done (--stats or --stats=json)")
+	rootCmd.PersistentFlags().Lookup("stats").NoOptDefVal = "text"
+	rootCmd.PersistentFlags().BoolVar(&queueOffline, "queue-offline", false, "Queue writes that can't reach Notion for 'notion flush'")
+	rootCmd.PersistentFlags().StringVar(&progressFormat, "progress", "", "Progress of long operations: text, json (JSON lines on stderr) or none")
+	rootCmd.PersistentFlags().IntVar(&maxRequests, "max-requests", 0, "Stop after this many API requests (0 = no limit; also NOTION_MAX_REQUESTS)")
+	rootCmd.PersistentFlags().DurationVar(&maxDuration, "max-duration", 0, "Stop after this long, e.g. 2h (0 = no limit; also NOTION_MAX_DURATION)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 0, "Give up on a single API request after this long (default 30s)")
+	rootCmd.PersistentFlags().BoolVar(&relativeDatesFlag, "relative-dates", false, "Show dates as \"2 hours ago\" or \"yesterday\" in tables (JSON keeps them absolute)")
+	rootCmd.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false, "Refuse every request that would change the workspace")
+	rootCmd.PersistentFlags().BoolVar(&noConfig, "no-config", false, "Never read or write the config file; take everything from flags and NOTION_* (also NOTION_NO_CONFIG=1)")
+	rootCmd.PersistentFlags().StringVar(&porcelain, "porcelain", "", "Stable tab-separated output for scripts from search and list commands (--porcelain or --porcelain=v1)")
+	rootCmd.PersistentFlags().Lookup("porcelain").NoOptDefVal = "v1"
+	rootCmd.PersistentFlags().StringVar(&timeZone, "tz", "", "Time zone for times given without an offset, e.g. Europe/Berlin (default: local)")
 
 	rootCmd.AddCommand(authCmd)
 	rootCmd.AddCommand(searchCmd)
@@ -47,13 +110,116 @@ func init() {
 	rootCmd.AddCommand(apiCmd)
 	rootCmd.AddCommand(commentCmd)
 	rootCmd.AddCommand(fileCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(aliasCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(recentCmd)
+	rootCmd.AddCommand(batchCmd)
+	rootCmd.AddCommand(shellCmd)
+	rootCmd.AddCommand(flushCmd)
+	rootCmd.AddCommand(activityCmd)
+	rootCmd.AddCommand(todoCmd)
+	rootCmd.AddCommand(changelogCmd)
+	rootCmd.AddCommand(captureCmd)
+	rootCmd.AddCommand(clipCmd)
+	rootCmd.AddCommand(feedCmd)
+	rootCmd.AddCommand(emailToPageCmd)
+	rootCmd.AddCommand(sqlCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(mapCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(taskCmd)
+	rootCmd.AddCommand(trackCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(extCmd)
+	rootCmd.AddCommand(metaCmd)
+	rootCmd.AddCommand(agentToolsCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(generateCmd)
 }
 
 // getToken returns the Notion API token from flag, env, or config file.
 func getToken() (string, error) {
+	token, _ := resolveToken()
+	if token == "" && os.Getenv("NOTION_PROFILE") != "" && !config.IsDisabled() {
+		return "", authError(fmt.Errorf("NOTION_PROFILE: no profile %q with a token (see 'notion auth switch')", os.Getenv("NOTION_PROFILE")))
+	}
+	if token == "" {
+		return "", authError(fmt.Errorf("not authenticated. Run 'notion auth login --with-token' or set NOTION_TOKEN"))
+	}
+	return token, nil
+}
+
+// newClient returns an API client for token set up from the global flags.
+// Pages and databases it fetches are recorded for 'notion recent'. Inside
+// 'notion shell' the same client is reused across commands.
+func newClient(token string) *notion.Client {
+	if c, ok := shellClients[token]; ok {
+		c.SetDebug(debugMode)
+		return c
+	}
+	c := notion.New(token, clientOptions()...)
+	c.OnObject(recordRecent)
+	// A command fetches each database schema once, however many lookups
+	// (or goroutines) need it; in the shell, schemas carry over between
+	// commands.
+	c.CacheSchemas()
+	if shellClients != nil {
+		shellClients[token] = c
+	}
+	return c
+}
+
+// clientOptions configures every API client the CLI creates. Rate-limited
+// and transient failures are retried, requests are counted for --stats
+// and capped by --max-requests and --max-duration; writes are refused in
+// read-only mode and outside the write policy, may go to the offline
+// queue and are recorded in the operation log.
+// NOTION_BASE_URL points the CLI at another API host (a proxy or a test
+// server).
+func clientOptions() []notion.Option {
+	opts := []notion.Option{
+		notion.WithBaseURL(os.Getenv("NOTION_BASE_URL")),
+		notion.WithTransport(transportOptions()),
+		notion.WithDebug(debugMode),
+		notion.WithMiddleware(refuseWrites, enforcePolicy, queueWhenOffline, logWrites, enforceDeadline, notion.Retry(maxRetries, noteRetry), enforceBudget, countRequests),
+	}
+	if requestTimeout > 0 {
+		opts = append(opts, notion.WithTimeout(requestTimeout))
+	}
+	return opts
+}
+
+// transportOptions reads the connection settings, for constrained
+// environments: http_compression and http2 are on and http_keepalive is
+// true unless set to false; http_idle_conns caps the pooled connections.
+func transportOptions() notion.TransportOptions {
+	var opts notion.TransportOptions
+	cfg, err := config.Load()
+	if err != nil {
+		return opts
+	}
+	off := func(key string) bool {
+		v := cfg.Setting(key)
+		return v != "" && !isTruthy(v)
+	}
+	opts.DisableCompression = off("http_compression")
+	opts.DisableHTTP2 = off("http2")
+	opts.DisableKeepAlives = off("http_keepalive")
+	opts.IdleConns, _ = strconv.Atoi(cfg.Setting("http_idle_conns"))
+	return opts
+}
+
+// resolveToken returns the token getToken would use and a description of
+// where it came from ("NOTION_TOKEN", `profile "work"`, ...).
+func resolveToken() (string, string) {
 	// 1. Environment variable
 	if token := os.Getenv("NOTION_TOKEN"); token != "" {
-		return token, nil
+		return token, "NOTION_TOKEN"
 	}
 
 	// 2. Config file (with profile support)
@@ -61,9 +227,12 @@ func getToken() (string, error) {
 	if err == nil {
 		profile := cfg.GetCurrentProfile()
 		if profile != nil && profile.Token != "" {
-			return profile.Token, nil
+			if len(cfg.Profiles) == 0 {
+				return profile.Token, "legacy config token"
+			}
+			return profile.Token, fmt.Sprintf("profile %q", cfg.ActiveProfile())
 		}
 	}
 
-	return "", fmt.Errorf("not authenticated. Run 'notion auth login --with-token' or set NOTION_TOKEN")
+	return "", ""
 }
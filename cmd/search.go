@@ -2,10 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -18,13 +19,21 @@ Examples:
   notion search "meeting notes"
   notion search --type page "roadmap"
   notion search --type database
-  notion search --limit 5`,
+  notion search --limit 5
+  notion search "roadmap" --all-profiles
+  notion search "Q3 roadmap" --type page --copy   # copy the URL of the only match
+  notion search --verification expired --all       # wiki pages due for review
+  notion search "retro" --parent <team-space-page>
+
+--parent keeps what lives under a page or database, however deep: the
+search API can't scope by subtree, so each result's parents are looked up
+(once per parent) and results are read until --limit of them match.
+--verification keeps wiki pages that are verified, unverified or expired
+(verified until a date that has passed), read the same way.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		token, err := getToken()
-		if err != nil {
+		if err := checkCopyFlag(cmd); err != nil {
 			return err
 		}
-
 		query := ""
 		if len(args) > 0 {
 			query = strings.Join(args, " ")
@@ -34,29 +43,89 @@ Examples:
 		limit, _ := cmd.Flags().GetInt("limit")
 		cursor, _ := cmd.Flags().GetString("cursor")
 		all, _ := cmd.Flags().GetBool("all")
+		verification, _ := cmd.Flags().GetString("verification")
+		switch verification {
+		case "", verificationVerified, verificationUnverified, verificationExpired:
+		default:
+			return usageError(fmt.Errorf("--verification must be verified, unverified or expired"))
+		}
+		if verification != "" {
+			if filterType == "database" {
+				return usageError(fmt.Errorf("--verification applies to pages, not databases"))
+			}
+			filterType = "page"
+		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		parent, _ := cmd.Flags().GetString("parent")
+
+		if allProfiles, _ := cmd.Flags().GetBool("all-profiles"); allProfiles {
+			if parent != "" {
+				return usageError(fmt.Errorf("--parent cannot be combined with --all-profiles"))
+			}
+			if cursor != "" {
+				return usageError(fmt.Errorf("--cursor cannot be combined with --all-profiles"))
+			}
+			if verification != "" {
+				return usageError(fmt.Errorf("--verification cannot be combined with --all-profiles"))
+			}
+			return searchAllProfiles(query, filterType, limit, all, true)
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+
+		c := newClient(token)
+
+		var scope *subtreeScope
+		if parent != "" {
+			scope = newSubtreeScope(c, util.ResolveID(parent))
+		}
+		// Filtered searches read full pages of results until there are
+		// --limit matches.
+		filtered := scope != nil || verification != ""
+		pageSize := limit
+		if filtered {
+			pageSize = 100
+		}
 
 		var allResults []interface{}
 		currentCursor := cursor
+		var truncated error
 
 		for {
-			result, err := c.Search(query, filterType, limit, currentCursor)
+			result, err := c.Search(query, filterType, pageSize, currentCursor)
 			if err != nil {
+				if overBudget(err, allResults) {
+					truncated = err
+					break
+				}
 				return err
 			}
 
-			if outputFormat == "json" && !all {
-				return render.JSON(result)
-			}
-
 			results, _ := result["results"].([]interface{})
+			if verification != "" {
+				results = filterVerification(results, verification)
+			}
+			if scope != nil {
+				results = scope.filter(results)
+			}
 			allResults = append(allResults, results...)
 
 			hasMore, _ := result["has_more"].(bool)
-			if !all || !hasMore {
-				if all && outputFormat == "json" {
+			more := all || (filtered && len(allResults) < limit)
+			if !more || !hasMore {
+				copySingleResult(cmd, allResults, hasMore)
+			}
+
+			if outputFormat == "json" && !more {
+				result["results"] = allResults
+				return render.JSON(result)
+			}
+
+			if !more || !hasMore {
+				if more && outputFormat == "json" {
 					return render.JSON(map[string]interface{}{
 						"results": allResults,
 					})
@@ -67,6 +136,15 @@ Examples:
 			currentCursor = nextCursor
 		}
 
+		if truncated != nil && outputFormat == "json" {
+			return renderTruncated(allResults, currentCursor, truncated)
+		}
+
+		if porcelain != "" {
+			porcelainObjects(allResults, true)
+			return truncated
+		}
+
 		if len(allResults) == 0 {
 			fmt.Println("No results found.")
 			return nil
@@ -85,20 +163,20 @@ Examples:
 			title := render.ExtractTitle(obj)
 			id, _ := obj["id"].(string)
 			lastEdited, _ := obj["last_edited_time"].(string)
-			if len(lastEdited) > 10 {
-				lastEdited = lastEdited[:10]
-			}
 
 			icon := "📄"
 			if objType == "database" {
 				icon = "🗃️"
+				if isWikiDatabase(obj) {
+					objType += " (wiki)"
+				}
 			}
 
-			rows = append(rows, []string{icon + " " + objType, title, id, lastEdited})
+			rows = append(rows, []string{icon + " " + objType, title, id, displayDate(lastEdited)})
 		}
 
 		render.Table(headers, rows)
-		return nil
+		return truncated
 	},
 }
 
@@ -107,4 +185,36 @@ func init() {
 	searchCmd.Flags().IntP("limit", "l", 10, "Maximum results to return")
 	searchCmd.Flags().String("cursor", "", "Pagination cursor from previous results")
 	searchCmd.Flags().Bool("all", false, "Fetch all pages of results")
+	searchCmd.Flags().Bool("all-profiles", false, "Search every saved profile and merge the results")
+	searchCmd.Flags().String("parent", "", "Only results under this page or database (ID or URL)")
+	markIDFlags(searchCmd, "parent")
+	searchCmd.Flags().String("verification", "", "Only wiki pages that are: verified, unverified, expired")
+	addCopyFlag(searchCmd)
+}
+
+// copySingleResult implements search --copy, which only copies when the
+// search found exactly one object.
+func copySingleResult(cmd *cobra.Command, results []interface{}, hasMore bool) {
+	if what, _ := cmd.Flags().GetString("copy"); what == "" {
+		return
+	}
+	if len(results) != 1 || hasMore {
+		fmt.Fprintf(os.Stderr, "note: --copy needs exactly one result; narrow the search\n")
+		return
+	}
+	obj, _ := results[0].(map[string]interface{})
+	copyResult(cmd, obj)
+}
+
+// filterVerification keeps the wiki pages whose verification is in state.
+func filterVerification(results []interface{}, state string) []interface{} {
+	kept := []interface{}{}
+	now := timeNow()
+	for _, r := range results {
+		obj, _ := r.(map[string]interface{})
+		if name, prop := pageVerification(obj); name != "" && verificationState(prop, now) == state {
+			kept = append(kept, r)
+		}
+	}
+	return kept
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// subtreeScope tells whether objects live under a page or database. The
+// search API can't scope by subtree, so each result's parents are walked
+// up until the root, the workspace or a parent the integration can't read.
+// Parents are fetched once: results in the same corner of the workspace
+// share most of their chain.
+type subtreeScope struct {
+	c      *notion.Client
+	root   string          // plain ID
+	inside map[string]bool // plain ID → under root
+}
+
+func newSubtreeScope(c *notion.Client, root string) *subtreeScope {
+	return &subtreeScope{c: c, root: plainID(root), inside: map[string]bool{}}
+}
+
+// contains reports whether obj is under the root (the root itself isn't).
+func (s *subtreeScope) contains(obj map[string]interface{}) bool {
+	var chain []string
+	under := false
+	for depth := 0; depth < maxParentDepth; depth++ {
+		parent, _ := obj["parent"].(map[string]interface{})
+		kind, _ := parent["type"].(string)
+		id, _ := parent[kind].(string)
+		if id == "" {
+			break
+		}
+		id = plainID(id)
+		if id == s.root {
+			under = true
+			break
+		}
+		if known, ok := s.inside[id]; ok {
+			under = known
+			break
+		}
+		chain = append(chain, id)
+		var err error
+		switch kind {
+		case "page_id":
+			obj, err = s.c.GetPage(id)
+		case "database_id":
+			obj, err = s.c.GetDatabase(id)
+		case "block_id":
+			obj, err = s.c.GetBlock(id)
+		default:
+			obj = nil
+		}
+		if err != nil || obj == nil {
+			break
+		}
+	}
+	for _, id := range chain {
+		s.inside[id] = under
+	}
+	return under
+}
+
+// filter keeps the results under the root.
+func (s *subtreeScope) filter(results []interface{}) []interface{} {
+	kept := []interface{}{}
+	for _, r := range results {
+		if obj, _ := r.(map[string]interface{}); obj != nil && s.contains(obj) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
@@ -443,3 +443,72 @@ func TestSearchDatabaseIcon(t *testing.T) {
 		t.Errorf("expected page icon '📄' in output, got: %s", stdout)
 	}
 }
+
+func TestSearchParentScopesToSubtree(t *testing.T) {
+	const root = "11111111111111111111111111111111"
+	page := func(id, parentKind, parentID string) map[string]interface{} {
+		parent := map[string]interface{}{"type": parentKind, parentKind: parentID}
+		if parentKind == "workspace" {
+			parent[parentKind] = true
+		}
+		return map[string]interface{}{"object": "page", "id": id, "parent": parent, "properties": map[string]interface{}{}}
+	}
+	objects := map[string]map[string]interface{}{
+		"/v1/pages/team":    page("team", "page_id", "11111111-1111-1111-1111-111111111111"),
+		"/v1/blocks/toggle": {"object": "block", "id": "toggle", "parent": map[string]interface{}{"type": "page_id", "page_id": "team"}},
+		"/v1/pages/other":   page("other", "workspace", ""),
+	}
+	fetched := map[string]int{}
+	searches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/search" {
+			searches++
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if searches == 1 {
+				json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+					page("a", "page_id", "team"),
+					page("b", "page_id", "other"),
+				}, "has_more": true, "next_cursor": "c2"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				page("c", "block_id", "toggle"),
+				page("d", "page_id", "team"),
+				page("e", "workspace", ""),
+			}, "has_more": false})
+			return
+		}
+		fetched[r.URL.Path]++
+		if obj, ok := objects[r.URL.Path]; ok {
+			json.NewEncoder(w).Encode(obj)
+			return
+		}
+		w.WriteHeader(404)
+		json.NewEncoder(w).Encode(map[string]string{"code": "object_not_found", "message": "not found"})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	defer func() { outputFormat = "" }()
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("search", "--parent", root, "--limit", "5", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var result struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	json.Unmarshal([]byte(out), &result)
+	var ids []string
+	for _, r := range result.Results {
+		ids = append(ids, r["id"].(string))
+	}
+	if strings.Join(ids, ",") != "a,c,d" {
+		t.Errorf("results = %v; want a,c,d", ids)
+	}
+	if searches != 2 || fetched["/v1/pages/team"] != 1 {
+		t.Errorf("searches = %d, fetched = %v; each parent should be fetched once", searches, fetched)
+	}
+}
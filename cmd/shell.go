@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// maxShellHistory bounds the saved shell history.
+const maxShellHistory = 1000
+
+// shellClients holds one client per token while 'notion shell' runs, so
+// connections and cached database schemas carry over between commands.
+// It is nil outside the shell.
+var shellClients map[string]*notion.Client
+
+// shellRootFlags are the global flags given to 'notion shell' itself, such
+// as --read-only or --format json, by name. Each line starts from them.
+var shellRootFlags map[string]string
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run CLI commands interactively",
+	Long: `Start an interactive shell that runs CLI commands without the
+'notion' prefix.
+
+The shell keeps one API client for the whole session, so connections are
+reused and database schemas are fetched once. Line editing and history
+(saved across sessions) are available in a terminal; Tab completes command
+names, and page or database titles and IDs from 'notion recent'. Global
+flags given to the shell itself, such as --read-only or --format json,
+apply to every command run in it.
+
+Type 'exit' or press Ctrl-D to leave. When stdin is not a terminal, commands
+are read one per line, which makes the shell usable from scripts.
+
+Examples:
+  notion shell
+  > search roadmap
+  > page view @1
+  > db query tasks --limit 5
+  > exit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shellClients = map[string]*notion.Client{}
+		shellRootFlags = map[string]string{}
+		rootCmd.PersistentFlags().Visit(func(f *pflag.Flag) {
+			shellRootFlags[f.Name] = f.Value.String()
+		})
+		shellReadOnly = readOnlyFlag
+		defer func() { shellClients, shellRootFlags, shellReadOnly = nil, nil, false }()
+
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				if !runShellLine(scanner.Text()) {
+					break
+				}
+			}
+			return scanner.Err()
+		}
+		return runInteractiveShell()
+	},
+}
+
+func runInteractiveShell() error {
+	fd := int(os.Stdin.Fd())
+	history := loadShellHistory(filepath.Join(config.CacheDir(), "shell_history"))
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "notion> ")
+	t.History = history
+	t.AutoCompleteCallback = completeShellLine
+
+	fmt.Println("Notion shell — type 'help' for commands, 'exit' to quit.")
+	for {
+		// The terminal is raw only while reading, so commands print normally.
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("enter raw mode: %w", err)
+		}
+		if w, _, err := term.GetSize(fd); err == nil {
+			t.SetSize(w, 0)
+		}
+		line, err := t.ReadLine()
+		term.Restore(fd, state)
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !runShellLine(line) {
+			return nil
+		}
+	}
+}
+
+// runShellLine executes one shell line. It returns false when the shell
+// should exit.
+func runShellLine(line string) bool {
+	args, err := splitShellWords(line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return true
+	}
+	if len(args) > 0 && args[0] == "notion" {
+		args = args[1:]
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+		return true
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return false
+	case "shell":
+		fmt.Fprintln(os.Stderr, "Already in the shell.")
+		return true
+	}
+
+	resetCommandFlags(rootCmd)
+	for name, value := range shellRootFlags {
+		if f := rootCmd.PersistentFlags().Lookup(name); f != nil && f.Value.Set(value) == nil {
+			f.Changed = true
+		}
+	}
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+	saveRecent()
+	reportStats(os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+	return true
+}
+
+// resetCommandFlags puts every flag of cmd and its subcommands back to its
+// default, so a command run in the shell doesn't inherit the previous one's
+// flags.
+func resetCommandFlags(cmd interface {
+	Flags() *pflag.FlagSet
+	PersistentFlags() *pflag.FlagSet
+	Commands() []*cobra.Command
+}) {
+	resetFlagSet(cmd.Flags())
+	resetFlagSet(cmd.PersistentFlags())
+	for _, sub := range cmd.Commands() {
+		resetCommandFlags(sub)
+	}
+}
+
+func resetFlagSet(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sliceValue.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+// splitShellWords splits a line into words, honouring single quotes,
+// double quotes and backslash escapes.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("line ends with a backslash")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// completeShellLine is the terminal's Tab handler.
+func completeShellLine(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	start := strings.LastIndexAny(line[:pos], " \t") + 1
+	word := line[start:pos]
+	candidates := shellCandidates(strings.Fields(line[:start]), word)
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+	completion := candidates[0]
+	for _, c := range candidates[1:] {
+		completion = commonPrefix(completion, c)
+	}
+	if len(candidates) == 1 {
+		completion += " "
+	}
+	if completion == word {
+		return "", 0, false
+	}
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// shellCandidates returns completions for word, given the words before
+// it: subcommand names while the line is still naming a command, otherwise
+// the IDs of recent items whose ID or title starts with word.
+func shellCandidates(before []string, word string) []string {
+	if len(before) > 0 && before[0] == "notion" {
+		before = before[1:]
+	}
+	cmd := rootCmd
+	atCommand := true
+	for _, w := range before {
+		sub := findSubcommand(cmd, w)
+		if sub == nil {
+			atCommand = false
+			break
+		}
+		cmd = sub
+	}
+
+	var out []string
+	if atCommand && cmd.HasAvailableSubCommands() && !strings.HasPrefix(word, "-") {
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() && strings.HasPrefix(sub.Name(), word) {
+				out = append(out, sub.Name())
+			}
+		}
+		if cmd == rootCmd && strings.HasPrefix("exit", word) {
+			out = append(out, "exit")
+		}
+		sort.Strings(out)
+		return out
+	}
+	if word == "" || strings.HasPrefix(word, "-") {
+		return nil
+	}
+
+	lower := strings.ToLower(word)
+	for _, item := range loadRecent() {
+		if strings.HasPrefix(strings.ReplaceAll(item.ID, "-", ""), strings.ReplaceAll(lower, "-", "")) ||
+			strings.HasPrefix(strings.ToLower(item.Title), lower) {
+			out = append(out, item.ID)
+		}
+	}
+	return out
+}
+
+func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+// shellHistory is the terminal history, persisted to a file.
+type shellHistory struct {
+	path    string
+	entries []string // oldest first
+}
+
+func loadShellHistory(path string) *shellHistory {
+	h := &shellHistory{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > maxShellHistory {
+		h.entries = h.entries[len(h.entries)-maxShellHistory:]
+		os.WriteFile(path, []byte(strings.Join(h.entries, "\n")+"\n"), 0600)
+	}
+	return h
+}
+
+func (h *shellHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return
+	}
+	if f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
+		fmt.Fprintln(f, entry)
+		f.Close()
+	}
+}
+
+func (h *shellHistory) Len() int {
+	return len(h.entries)
+}
+
+func (h *shellHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	got, err := splitShellWords(`page create abc --title "My page" 'it''s' a\ b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"page", "create", "abc", "--title", "My page", "its", "a b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("words = %q, want %q", got, want)
+	}
+	if _, err := splitShellWords(`search "open`); err == nil {
+		t.Error("expected an unterminated quote error")
+	}
+}
+
+func TestShellCandidates(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if got := shellCandidates(nil, "sea"); !reflect.DeepEqual(got, []string{"search"}) {
+		t.Errorf("top-level = %v", got)
+	}
+	if got := shellCandidates([]string{"db"}, "q"); !reflect.DeepEqual(got, []string{"query"}) {
+		t.Errorf("db subcommands = %v", got)
+	}
+
+	recentSeen = []recentItem{{ID: "11111111-1111-1111-1111-111111111111", Object: "page", Title: "Roadmap"}}
+	saveRecent()
+	if got := shellCandidates([]string{"page", "view"}, "road"); len(got) != 1 || got[0] != "11111111-1111-1111-1111-111111111111" {
+		t.Errorf("title completion = %v", got)
+	}
+
+	line, pos, ok := completeShellLine("page vi", 7, '\t')
+	if !ok || line != "page view " || pos != len(line) {
+		t.Errorf("completeShellLine = %q, %d, %v", line, pos, ok)
+	}
+}
+
+func TestShellReusesClient(t *testing.T) {
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "id": "d", "properties": map[string]interface{}{}})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	shellClients = map[string]*notion.Client{}
+	defer func() { shellClients = nil }()
+
+	var out string
+	out = captureStdout(t, func() {
+		runShellLine("db schema 11111111111111111111111111111111 --format json")
+		runShellLine("notion db schema 11111111111111111111111111111111")
+	})
+	if gets != 1 {
+		t.Errorf("database fetched %d times, want 1", gets)
+	}
+	if !strings.Contains(out, "No results.") {
+		t.Errorf("second command should not inherit --format json:\n%s", out)
+	}
+	if runShellLine("exit") {
+		t.Error("exit should end the shell")
+	}
+}
+
+func TestReadOnlyShell(t *testing.T) {
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			writes = append(writes, r.Method+" "+r.URL.Path)
+		}
+		w.Write([]byte(`{"object":"comment","id":"c"}`))
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	oldStdin := os.Stdin
+	r, w, _ := os.Pipe()
+	w.WriteString("comment add 11111111111111111111111111111111 hi\n")
+	w.WriteString("comment add 11111111111111111111111111111111 hi --read-only=false\n")
+	w.Close()
+	os.Stdin = r
+	defer func() { os.Stdin = oldStdin }()
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("--read-only", "shell"); err != nil {
+			t.Fatalf("shell: %v", err)
+		}
+	})
+	if len(writes) > 0 {
+		t.Errorf("read-only shell sent %v", writes)
+	}
+	if shellReadOnly || shellRootFlags != nil {
+		t.Error("the shell's global flags should not outlive it")
+	}
+}
@@ -0,0 +1,1098 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var sqlCmd = &cobra.Command{
+	Use:   "sql <query>",
+	Short: "Query a database with SQL",
+	Long: `Query a database with a small SQL dialect:
+
+  SELECT *|COUNT(*)|col, ... FROM table
+    [WHERE cond] [ORDER BY col [ASC|DESC], ...] [LIMIT n]
+
+The table is an alias ('notion alias set tasks <db>') or a database ID;
+columns are property names, double-quoted when they contain spaces
+("Due date"), matched case-insensitively. created_time and
+last_edited_time refer to the row timestamps.
+
+Conditions combine comparisons with AND, OR, NOT and parentheses:
+  = != <> < <= > >=   'text', 123, TRUE/FALSE or a date '2026-01-31'
+  LIKE 'a%'           % and _ wildcards; NOT LIKE
+  IN ('a', 'b')       NOT IN
+  IS NULL             IS NOT NULL
+
+The query is compiled to Notion filters and sorts. Conditions the API
+can't express (say Name > 'M', or a LIKE on a select) are checked on
+each row instead; --explain shows which part runs where.
+
+Examples:
+  notion sql "SELECT Name, Due FROM tasks WHERE Status = 'Doing' ORDER BY Due"
+  notion sql "SELECT COUNT(*) FROM tasks WHERE Due < '2026-01-01' AND Done = FALSE"
+  notion sql "SELECT * FROM tasks WHERE Tags IN ('bug', 'p1') OR Name LIKE 'Fix%' LIMIT 20"
+  notion sql --explain "SELECT Name FROM tasks WHERE NOT (Status = 'Done' OR Estimate >= 5)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		explain, _ := cmd.Flags().GetBool("explain")
+		q, err := parseSQL(args[0])
+		if err != nil {
+			return invalidInput(err)
+		}
+		dbID, err := resolveSQLTable(q.From)
+		if err != nil {
+			return err
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database schema: %w", err)
+		}
+		schema, _ := db["properties"].(map[string]interface{})
+
+		plan, err := planSQL(q, schema)
+		if err != nil {
+			return err
+		}
+		if explain {
+			out := map[string]interface{}{"database_id": dbID, "query": plan.Body}
+			if plan.Residual != nil {
+				out["client_side"] = plan.Residual.String()
+			}
+			return render.JSON(out)
+		}
+
+		if q.Count && plan.Residual == nil && q.Limit == 0 {
+			n, err := countRows(c, dbID, plan.Body)
+			if err != nil {
+				return fmt.Errorf("query database: %w", err)
+			}
+			return printSQLCount(n)
+		}
+
+		rows, err := runSQLPlan(c, dbID, plan, q.Limit)
+		if err != nil {
+			return err
+		}
+		if q.Count {
+			return printSQLCount(len(rows))
+		}
+
+		if outputFormat == "json" {
+			var out []map[string]interface{}
+			for _, page := range rows {
+				row := map[string]interface{}{"id": page["id"]}
+				for _, col := range plan.Columns {
+					row[col], _ = sqlRowValue(page, col, plan.Types[col])
+				}
+				out = append(out, row)
+			}
+			return render.JSON(map[string]interface{}{"columns": plan.Columns, "rows": out, "count": len(out)})
+		}
+		if len(rows) == 0 {
+			fmt.Println("No results found.")
+			return nil
+		}
+		var table [][]string
+		for _, page := range rows {
+			row := make([]string, len(plan.Columns))
+			for i, col := range plan.Columns {
+				props, _ := page["properties"].(map[string]interface{})
+				if prop, ok := props[col].(map[string]interface{}); ok {
+					row[i] = displayPropertyValue(prop)
+				} else {
+					row[i], _ = sqlRowValue(page, col, plan.Types[col])
+					row[i] = displayTime(row[i])
+				}
+			}
+			table = append(table, row)
+		}
+		render.Table(plan.Columns, table)
+		fmt.Printf("\n%d row(s)\n", len(table))
+		return nil
+	},
+}
+
+func init() {
+	sqlCmd.Flags().Bool("explain", false, "Print the Notion query and the client-side conditions instead of running it")
+}
+
+func printSQLCount(n int) error {
+	if outputFormat == "json" {
+		return render.JSON(map[string]interface{}{"count": n})
+	}
+	fmt.Println(n)
+	return nil
+}
+
+// resolveSQLTable maps a FROM name to a database ID: an alias, else an ID
+// or URL.
+func resolveSQLTable(name string) (string, error) {
+	if id, ok := resolveAlias(name); ok {
+		return id, nil
+	}
+	if util.IsID(name) {
+		return util.ResolveID(name), nil
+	}
+	return "", usageError(fmt.Errorf("unknown table %q: save it with 'notion alias set %s <db-id|url>'", name, name))
+}
+
+// --- parsing ---
+
+// sqlQuery is a parsed SELECT statement.
+type sqlQuery struct {
+	Columns []string // nil for *
+	Count   bool
+	From    string
+	Where   sqlExpr
+	OrderBy []sqlOrder
+	Limit   int
+}
+
+type sqlOrder struct {
+	Column string
+	Desc   bool
+}
+
+// sqlExpr is a WHERE condition: *sqlLogic, *sqlNot or *sqlCompare.
+type sqlExpr interface {
+	String() string
+}
+
+// sqlLogic is an AND or OR of two or more conditions.
+type sqlLogic struct {
+	Op    string // "and" or "or"
+	Terms []sqlExpr
+}
+
+type sqlNot struct {
+	X sqlExpr
+}
+
+// sqlCompare compares a column with literal values. Op is one of = != <
+// <= > >= like, "not like", "is null", "is not null", in, "not in".
+type sqlCompare struct {
+	Column string
+	Op     string
+	Values []string
+}
+
+func (e *sqlLogic) String() string {
+	parts := make([]string, len(e.Terms))
+	for i, t := range e.Terms {
+		parts[i] = t.String()
+		if l, ok := t.(*sqlLogic); ok && l.Op != e.Op {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+strings.ToUpper(e.Op)+" ")
+}
+
+func (e *sqlNot) String() string { return "NOT (" + e.X.String() + ")" }
+
+func (e *sqlCompare) String() string {
+	col := sqliteIdent(e.Column)
+	quoted := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	switch e.Op {
+	case "is null", "is not null":
+		return col + " " + strings.ToUpper(e.Op)
+	case "in", "not in":
+		return col + " " + strings.ToUpper(e.Op) + " (" + strings.Join(quoted, ", ") + ")"
+	}
+	return col + " " + strings.ToUpper(e.Op) + " " + quoted[0]
+}
+
+type sqlToken struct {
+	kind string // ident, qident, string, number, op, eof
+	text string
+}
+
+func tokenizeSQL(s string) ([]sqlToken, error) {
+	var toks []sqlToken
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		ch := r[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '\'' || ch == '"' || ch == '`' || ch == '[':
+			closeCh := ch
+			if ch == '[' {
+				closeCh = ']'
+			}
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(r); j++ {
+				if r[j] == closeCh {
+					if closeCh != ']' && j+1 < len(r) && r[j+1] == closeCh {
+						b.WriteRune(closeCh)
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteRune(r[j])
+			}
+			if j >= len(r) {
+				return nil, fmt.Errorf("unterminated %c at position %d", ch, i+1)
+			}
+			kind := "qident"
+			if ch == '\'' {
+				kind = "string"
+			}
+			toks = append(toks, sqlToken{kind, b.String()})
+			i = j + 1
+		case unicode.IsDigit(ch) || (ch == '-' && i+1 < len(r) && unicode.IsDigit(r[i+1])):
+			j := i + 1
+			for j < len(r) && (unicode.IsLetter(r[j]) || unicode.IsDigit(r[j]) || r[j] == '.' || r[j] == '-' || r[j] == '_') {
+				j++
+			}
+			kind := "number"
+			if _, err := strconv.ParseFloat(string(r[i:j]), 64); err != nil {
+				// A database ID such as 1a2b3c...
+				kind = "ident"
+			}
+			toks = append(toks, sqlToken{kind, string(r[i:j])})
+			i = j
+		case unicode.IsLetter(ch) || ch == '_' || ch == '@':
+			j := i + 1
+			for j < len(r) && (unicode.IsLetter(r[j]) || unicode.IsDigit(r[j]) || r[j] == '_' || r[j] == '-') {
+				j++
+			}
+			toks = append(toks, sqlToken{"ident", string(r[i:j])})
+			i = j
+		default:
+			op := string(ch)
+			if i+1 < len(r) {
+				if two := string(r[i : i+2]); two == "!=" || two == "<>" || two == "<=" || two == ">=" {
+					op = two
+				}
+			}
+			if !strings.Contains(" = != <> < <= > >= ( ) , * ; ", " "+op+" ") {
+				return nil, fmt.Errorf("unexpected %q at position %d", op, i+1)
+			}
+			toks = append(toks, sqlToken{"op", op})
+			i += len([]rune(op))
+		}
+	}
+	return append(toks, sqlToken{kind: "eof"}), nil
+}
+
+type sqlParser struct {
+	toks []sqlToken
+	pos  int
+}
+
+func (p *sqlParser) peek() sqlToken { return p.toks[p.pos] }
+
+func (p *sqlParser) next() sqlToken {
+	t := p.toks[p.pos]
+	if t.kind != "eof" {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the keyword kw, consuming it
+// if so.
+func (p *sqlParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == "ident" && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) op(op string) bool {
+	if t := p.peek(); t.kind == "op" && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expect(what string, ok bool) error {
+	if ok {
+		return nil
+	}
+	t := p.peek()
+	if t.kind == "eof" {
+		return fmt.Errorf("expected %s at end of query", what)
+	}
+	return fmt.Errorf("expected %s, found %q", what, t.text)
+}
+
+func (p *sqlParser) name() (string, error) {
+	t := p.peek()
+	if t.kind == "ident" || t.kind == "qident" {
+		p.pos++
+		return t.text, nil
+	}
+	return "", p.expect("a column name", false)
+}
+
+// parseSQL parses a SELECT statement of the supported dialect.
+func parseSQL(s string) (*sqlQuery, error) {
+	toks, err := tokenizeSQL(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{toks: toks}
+	q := &sqlQuery{}
+	if err := p.expect("SELECT", p.keyword("select")); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.op("*"):
+	case p.keyword("count"):
+		if err := p.expect("COUNT(*)", p.op("(") && p.op("*") && p.op(")")); err != nil {
+			return nil, err
+		}
+		q.Count = true
+	default:
+		for {
+			col, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			q.Columns = append(q.Columns, col)
+			if !p.op(",") {
+				break
+			}
+		}
+	}
+	if err := p.expect("FROM", p.keyword("from")); err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == "number" {
+		// An all-digit database ID.
+		q.From = p.next().text
+	} else if q.From, err = p.name(); err != nil {
+		return nil, fmt.Errorf("expected a table after FROM")
+	}
+	if p.keyword("where") {
+		if q.Where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.keyword("order") {
+		if err := p.expect("BY", p.keyword("by")); err != nil {
+			return nil, err
+		}
+		for {
+			col, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			o := sqlOrder{Column: col}
+			if p.keyword("desc") {
+				o.Desc = true
+			} else {
+				p.keyword("asc")
+			}
+			q.OrderBy = append(q.OrderBy, o)
+			if !p.op(",") {
+				break
+			}
+		}
+	}
+	if p.keyword("limit") {
+		t := p.next()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != "number" || err != nil || n <= 0 {
+			return nil, fmt.Errorf("LIMIT needs a positive number")
+		}
+		q.Limit = n
+	}
+	p.op(";")
+	if err := p.expect("end of query", p.peek().kind == "eof"); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (p *sqlParser) parseOr() (sqlExpr, error) {
+	return p.parseLogic("or", p.parseAnd)
+}
+
+func (p *sqlParser) parseAnd() (sqlExpr, error) {
+	return p.parseLogic("and", p.parseUnary)
+}
+
+func (p *sqlParser) parseLogic(op string, operand func() (sqlExpr, error)) (sqlExpr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	terms := []sqlExpr{first}
+	for p.keyword(op) {
+		t, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &sqlLogic{Op: op, Terms: terms}, nil
+}
+
+func (p *sqlParser) parseUnary() (sqlExpr, error) {
+	if p.keyword("not") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &sqlNot{X: x}, nil
+	}
+	if p.op("(") {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")", p.op(")"))
+	}
+	return p.parseCompare()
+}
+
+func (p *sqlParser) literal() (string, error) {
+	t := p.peek()
+	switch {
+	case t.kind == "string" || t.kind == "number":
+	case t.kind == "ident" && (strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false")):
+		t.text = strings.ToLower(t.text)
+	default:
+		return "", p.expect("a value ('text', a number, TRUE or FALSE)", false)
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *sqlParser) parseCompare() (sqlExpr, error) {
+	col, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	c := &sqlCompare{Column: col}
+	switch {
+	case p.keyword("is"):
+		c.Op = "is null"
+		if p.keyword("not") {
+			c.Op = "is not null"
+		}
+		return c, p.expect("NULL", p.keyword("null"))
+	case p.keyword("not"):
+		switch {
+		case p.keyword("like"):
+			c.Op = "not like"
+		case p.keyword("in"):
+			c.Op = "not in"
+		default:
+			return nil, p.expect("LIKE or IN after NOT", false)
+		}
+	case p.keyword("like"):
+		c.Op = "like"
+	case p.keyword("in"):
+		c.Op = "in"
+	default:
+		t := p.peek()
+		if t.kind != "op" || !strings.Contains(" = != <> < <= > >= ", " "+t.text+" ") {
+			return nil, p.expect("a comparison (=, !=, <, >, LIKE, IN, IS NULL)", false)
+		}
+		p.pos++
+		c.Op = t.text
+		if c.Op == "<>" {
+			c.Op = "!="
+		}
+	}
+	if c.Op == "in" || c.Op == "not in" {
+		if err := p.expect("(", p.op("(")); err != nil {
+			return nil, err
+		}
+		for {
+			v, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			c.Values = append(c.Values, v)
+			if !p.op(",") {
+				break
+			}
+		}
+		return c, p.expect(")", p.op(")"))
+	}
+	v, err := p.literal()
+	if err != nil {
+		return nil, err
+	}
+	c.Values = []string{v}
+	return c, nil
+}
+
+// --- planning ---
+
+// sqlNegations maps each comparison to its opposite, for pushing NOT
+// down to the comparisons.
+var sqlNegations = map[string]string{
+	"=": "!=", "!=": "=", "<": ">=", ">=": "<", ">": "<=", "<=": ">",
+	"like": "not like", "not like": "like", "in": "not in", "not in": "in",
+	"is null": "is not null", "is not null": "is null",
+}
+
+// normalizeSQL removes NOT by De Morgan's laws and flattens nested ANDs
+// and ORs.
+func normalizeSQL(e sqlExpr, negate bool) sqlExpr {
+	switch e := e.(type) {
+	case *sqlNot:
+		return normalizeSQL(e.X, !negate)
+	case *sqlLogic:
+		op := e.Op
+		if negate {
+			op = map[string]string{"and": "or", "or": "and"}[op]
+		}
+		out := &sqlLogic{Op: op}
+		for _, t := range e.Terms {
+			t = normalizeSQL(t, negate)
+			if l, ok := t.(*sqlLogic); ok && l.Op == op {
+				out.Terms = append(out.Terms, l.Terms...)
+			} else {
+				out.Terms = append(out.Terms, t)
+			}
+		}
+		return out
+	case *sqlCompare:
+		if !negate {
+			return e
+		}
+		return &sqlCompare{Column: e.Column, Op: sqlNegations[e.Op], Values: e.Values}
+	}
+	return e
+}
+
+// sqlPlan is a query compiled for the API: the request body, the
+// condition left to check on each row, and the columns to show.
+type sqlPlan struct {
+	Body     map[string]interface{}
+	Residual sqlExpr
+	Columns  []string
+	Types    map[string]string
+	PropIDs  []string // filter_properties; nil fetches every property
+}
+
+// planSQL resolves column names against the schema and splits the WHERE
+// clause into the part the API can filter on and the rest.
+func planSQL(q *sqlQuery, schema map[string]interface{}) (*sqlPlan, error) {
+	plan := &sqlPlan{Body: map[string]interface{}{}, Types: map[string]string{}}
+	resolve := func(col string) (string, error) {
+		if ts, ok := timestampProperty("@" + strings.TrimPrefix(col, "@")); ok {
+			if _, isProp := schema[col]; !isProp && (strings.HasPrefix(col, "@") || strings.EqualFold(col, ts)) {
+				plan.Types[ts] = ts
+				return ts, nil
+			}
+		}
+		names, _, err := resolveColumns(schema, []string{col})
+		if err != nil {
+			return "", err
+		}
+		prop, _ := schema[names[0]].(map[string]interface{})
+		plan.Types[names[0]], _ = prop["type"].(string)
+		return names[0], nil
+	}
+
+	for _, col := range q.Columns {
+		name, err := resolve(col)
+		if err != nil {
+			return nil, err
+		}
+		plan.Columns = append(plan.Columns, name)
+	}
+	if q.Columns == nil {
+		for _, name := range sortedKeys(schema) {
+			if prop, _ := schema[name].(map[string]interface{}); prop["type"] == "title" {
+				plan.Columns = append([]string{name}, plan.Columns...)
+			} else {
+				plan.Columns = append(plan.Columns, name)
+			}
+			resolve(name)
+		}
+	}
+
+	var sorts []interface{}
+	for _, o := range q.OrderBy {
+		name, err := resolve(o.Column)
+		if err != nil {
+			return nil, err
+		}
+		dir := "ascending"
+		if o.Desc {
+			dir = "descending"
+		}
+		if name == "created_time" || name == "last_edited_time" {
+			sorts = append(sorts, map[string]interface{}{"timestamp": name, "direction": dir})
+		} else {
+			sorts = append(sorts, map[string]interface{}{"property": name, "direction": dir})
+		}
+	}
+	if len(sorts) > 0 {
+		plan.Body["sorts"] = sorts
+	}
+
+	if q.Where != nil {
+		where := normalizeSQL(q.Where, false)
+		if err := resolveSQLColumns(where, resolve); err != nil {
+			return nil, err
+		}
+		terms := []sqlExpr{where}
+		if l, ok := where.(*sqlLogic); ok && l.Op == "and" {
+			terms = l.Terms
+		}
+		var server []interface{}
+		var residual []sqlExpr
+		for _, t := range terms {
+			if f, depth, ok := compileSQLExpr(t, plan.Types); ok && depth <= 1 {
+				server = append(server, f)
+			} else {
+				residual = append(residual, t)
+			}
+		}
+		switch len(server) {
+		case 0:
+		case 1:
+			plan.Body["filter"] = server[0]
+		default:
+			plan.Body["filter"] = map[string]interface{}{"and": server}
+		}
+		switch len(residual) {
+		case 0:
+		case 1:
+			plan.Residual = residual[0]
+		default:
+			plan.Residual = &sqlLogic{Op: "and", Terms: residual}
+		}
+	}
+
+	// Fetch only the properties shown or checked here (SELECT * shows all).
+	if q.Columns != nil || q.Count {
+		var need []string
+		if !q.Count {
+			need = append(need, plan.Columns...)
+		}
+		if plan.Residual != nil {
+			need = append(need, sqlColumnsOf(plan.Residual)...)
+		}
+		props := []string{}
+		for _, n := range need {
+			if n != "created_time" && n != "last_edited_time" {
+				props = append(props, n)
+			}
+		}
+		if len(props) > 0 {
+			_, plan.PropIDs, _ = resolveColumns(schema, props)
+		} else {
+			plan.PropIDs = []string{"title"}
+		}
+	}
+	return plan, nil
+}
+
+func resolveSQLColumns(e sqlExpr, resolve func(string) (string, error)) error {
+	switch e := e.(type) {
+	case *sqlLogic:
+		for _, t := range e.Terms {
+			if err := resolveSQLColumns(t, resolve); err != nil {
+				return err
+			}
+		}
+	case *sqlCompare:
+		name, err := resolve(e.Column)
+		if err != nil {
+			return err
+		}
+		e.Column = name
+	}
+	return nil
+}
+
+func sqlColumnsOf(e sqlExpr) []string {
+	switch e := e.(type) {
+	case *sqlLogic:
+		var cols []string
+		for _, t := range e.Terms {
+			cols = append(cols, sqlColumnsOf(t)...)
+		}
+		return cols
+	case *sqlCompare:
+		return []string{e.Column}
+	}
+	return nil
+}
+
+// compileSQLExpr compiles a normalized condition into a Notion filter,
+// reporting how many compound levels it nests inside the leaves (the API
+// allows two, one of which the top-level AND may use).
+func compileSQLExpr(e sqlExpr, types map[string]string) (interface{}, int, bool) {
+	switch e := e.(type) {
+	case *sqlLogic:
+		var parts []interface{}
+		depth := 0
+		for _, t := range e.Terms {
+			f, d, ok := compileSQLExpr(t, types)
+			if !ok {
+				return nil, 0, false
+			}
+			parts = append(parts, f)
+			if d > depth {
+				depth = d
+			}
+		}
+		return map[string]interface{}{e.Op: parts}, depth + 1, true
+	case *sqlCompare:
+		if e.Op == "in" || e.Op == "not in" {
+			op, logic := "=", "or"
+			if e.Op == "not in" {
+				op, logic = "!=", "and"
+			}
+			var parts []interface{}
+			for _, v := range e.Values {
+				f, ok := sqlLeafFilter(e.Column, types[e.Column], op, v)
+				if !ok {
+					return nil, 0, false
+				}
+				parts = append(parts, f)
+			}
+			if len(parts) == 1 {
+				return parts[0], 0, true
+			}
+			return map[string]interface{}{logic: parts}, 1, true
+		}
+		value := ""
+		if len(e.Values) > 0 {
+			value = e.Values[0]
+		}
+		f, ok := sqlLeafFilter(e.Column, types[e.Column], e.Op, value)
+		return f, 0, ok
+	}
+	return nil, 0, false
+}
+
+// sqlLeafFilter builds the property filter for one comparison, or
+// reports that the API has no equivalent.
+func sqlLeafFilter(col, propType, op, value string) (map[string]interface{}, bool) {
+	cond := func(key string, v interface{}) (map[string]interface{}, bool) {
+		if propType == "created_time" || propType == "last_edited_time" {
+			return map[string]interface{}{"timestamp": propType, propType: map[string]interface{}{key: v}}, true
+		}
+		return map[string]interface{}{"property": col, propType: map[string]interface{}{key: v}}, true
+	}
+	switch op {
+	case "is null", "is not null":
+		switch propType {
+		case "checkbox", "formula", "rollup", "unique_id", "created_time", "last_edited_time", "created_by", "last_edited_by":
+			return nil, false
+		}
+		if op == "is null" {
+			return cond("is_empty", true)
+		}
+		return cond("is_not_empty", true)
+	}
+
+	switch propType {
+	case "title", "rich_text", "url", "email", "phone_number":
+		switch op {
+		case "=":
+			return cond("equals", value)
+		case "!=":
+			return cond("does_not_equal", value)
+		case "like", "not like":
+			inner := strings.Trim(value, "%")
+			if strings.ContainsAny(inner, "%_") {
+				return nil, false
+			}
+			starts, ends := strings.HasPrefix(value, "%"), strings.HasSuffix(value, "%")
+			switch {
+			case op == "not like" && starts && ends:
+				return cond("does_not_contain", inner)
+			case op == "not like" && !starts && !ends:
+				return cond("does_not_equal", inner)
+			case op == "not like":
+				return nil, false
+			case starts && ends:
+				return cond("contains", inner)
+			case ends:
+				return cond("starts_with", inner)
+			case starts:
+				return cond("ends_with", inner)
+			}
+			return cond("equals", inner)
+		}
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, false
+		}
+		key := map[string]string{"=": "equals", "!=": "does_not_equal", "<": "less_than", "<=": "less_than_or_equal_to",
+			">": "greater_than", ">=": "greater_than_or_equal_to"}[op]
+		if key != "" {
+			return cond(key, n)
+		}
+	case "unique_id":
+		_, n, ok := parseUniqueID(value)
+		if !ok {
+			return nil, false
+		}
+		key := map[string]string{"=": "equals", "!=": "does_not_equal", "<": "less_than", "<=": "less_than_or_equal_to",
+			">": "greater_than", ">=": "greater_than_or_equal_to"}[op]
+		if key != "" {
+			return cond(key, n)
+		}
+	case "select", "status":
+		switch op {
+		case "=":
+			return cond("equals", value)
+		case "!=":
+			return cond("does_not_equal", value)
+		}
+	case "multi_select":
+		switch op {
+		case "=":
+			return cond("contains", value)
+		case "!=":
+			return cond("does_not_contain", value)
+		}
+	case "date", "created_time", "last_edited_time":
+		key := map[string]string{"=": "equals", "<": "before", "<=": "on_or_before", ">": "after", ">=": "on_or_after"}[op]
+		if key != "" {
+			return cond(key, value)
+		}
+	case "checkbox":
+		if value != "true" && value != "false" {
+			return nil, false
+		}
+		switch op {
+		case "=":
+			return cond("equals", value == "true")
+		case "!=":
+			return cond("does_not_equal", value == "true")
+		}
+	}
+	return nil, false
+}
+
+// --- execution ---
+
+// runSQLPlan pages through the query, keeping rows that pass the
+// client-side condition, until limit rows (0 = all) are collected.
+func runSQLPlan(c *notion.Client, dbID string, plan *sqlPlan, limit int) ([]map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	for k, v := range plan.Body {
+		body[k] = v
+	}
+	if limit > 0 && limit < 100 && plan.Residual == nil {
+		body["page_size"] = limit
+	}
+	var rows []map[string]interface{}
+	for {
+		result, err := c.QueryDatabase(dbID, body, plan.PropIDs...)
+		if err != nil {
+			return nil, fmt.Errorf("query database: %w", err)
+		}
+		results, _ := result["results"].([]interface{})
+		for _, r := range results {
+			page, ok := r.(map[string]interface{})
+			if !ok || (plan.Residual != nil && !evalSQL(plan.Residual, page, plan.Types)) {
+				continue
+			}
+			rows = append(rows, page)
+			if limit > 0 && len(rows) == limit {
+				return rows, nil
+			}
+		}
+		hasMore, _ := result["has_more"].(bool)
+		nextCursor, _ := result["next_cursor"].(string)
+		if !hasMore || nextCursor == "" {
+			return rows, nil
+		}
+		body["start_cursor"] = nextCursor
+	}
+}
+
+// sqlRowValue returns a row's value for a column as text, and whether it
+// is set. Checkboxes read as "true"/"false", dates as their start and
+// multi-selects as a comma list.
+func sqlRowValue(page map[string]interface{}, col, propType string) (string, bool) {
+	if propType == "created_time" || propType == "last_edited_time" {
+		if props, _ := page["properties"].(map[string]interface{}); props[col] == nil {
+			v, _ := page[col].(string)
+			return v, v != ""
+		}
+	}
+	props, _ := page["properties"].(map[string]interface{})
+	prop, ok := props[col].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	switch propType {
+	case "checkbox":
+		b, _ := prop["checkbox"].(bool)
+		return strconv.FormatBool(b), true
+	case "date":
+		d, _ := prop["date"].(map[string]interface{})
+		v, _ := d["start"].(string)
+		return v, v != ""
+	case "number":
+		n, ok := prop["number"].(float64)
+		return strconv.FormatFloat(n, 'f', -1, 64), ok
+	case "created_time", "last_edited_time":
+		v, _ := prop[propType].(string)
+		return v, v != ""
+	}
+	v := extractPropertyValue(prop)
+	return v, v != ""
+}
+
+// evalSQL checks a normalized condition against a row. Values compare as
+// numbers when both sides are numeric, otherwise as text; LIKE ignores
+// case as in SQLite.
+func evalSQL(e sqlExpr, page map[string]interface{}, types map[string]string) bool {
+	switch e := e.(type) {
+	case *sqlLogic:
+		for _, t := range e.Terms {
+			if evalSQL(t, page, types) != (e.Op == "and") {
+				return e.Op != "and"
+			}
+		}
+		return e.Op == "and"
+	case *sqlNot:
+		return !evalSQL(e.X, page, types)
+	case *sqlCompare:
+		propType := types[e.Column]
+		value, set := sqlRowValue(page, e.Column, propType)
+		switch e.Op {
+		case "is null":
+			return !set
+		case "is not null":
+			return set
+		}
+		if !set {
+			return false
+		}
+		values := []string{value}
+		if propType == "multi_select" {
+			values = strings.Split(value, ", ")
+		}
+		switch e.Op {
+		case "in", "not in":
+			found := false
+			for _, want := range e.Values {
+				for _, v := range values {
+					found = found || sqlCompareValues(v, want, propType) == 0
+				}
+			}
+			return found == (e.Op == "in")
+		case "like", "not like":
+			re := sqlLikeRegexp(e.Values[0])
+			matched := false
+			for _, v := range values {
+				matched = matched || re.MatchString(v)
+			}
+			return matched == (e.Op == "like")
+		}
+		want := e.Values[0]
+		if propType == "multi_select" && (e.Op == "=" || e.Op == "!=") {
+			found := false
+			for _, v := range values {
+				found = found || v == want
+			}
+			return found == (e.Op == "=")
+		}
+		cmp := sqlCompareValues(value, want, propType)
+		switch e.Op {
+		case "=":
+			return cmp == 0
+		case "!=":
+			return cmp != 0
+		case "<":
+			return cmp < 0
+		case "<=":
+			return cmp <= 0
+		case ">":
+			return cmp > 0
+		case ">=":
+			return cmp >= 0
+		}
+	}
+	return false
+}
+
+func sqlCompareValues(a, b, propType string) int {
+	if propType != "title" && propType != "rich_text" {
+		fa, errA := strconv.ParseFloat(a, 64)
+		fb, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if propType == "unique_id" {
+		// TASK-9 sorts before TASK-10, and TASK-123 matches 123.
+		_, na, okA := parseUniqueID(a)
+		_, nb, okB := parseUniqueID(b)
+		if okA && okB {
+			switch {
+			case na < nb:
+				return -1
+			case na > nb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if propType == "date" || propType == "created_time" || propType == "last_edited_time" {
+		// Compare a datetime with a bare date on the date alone.
+		if len(b) == 10 && len(a) > 10 {
+			a = a[:10]
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// sqlLikeRegexp translates a LIKE pattern into a case-insensitive regexp.
+func sqlLikeRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var sqlTestSchema = map[string]interface{}{
+	"Name":     map[string]interface{}{"id": "title", "type": "title"},
+	"Status":   map[string]interface{}{"id": "st", "type": "status"},
+	"Tags":     map[string]interface{}{"id": "tg", "type": "multi_select"},
+	"Estimate": map[string]interface{}{"id": "es", "type": "number"},
+	"Due date": map[string]interface{}{"id": "du", "type": "date"},
+	"Done":     map[string]interface{}{"id": "dn", "type": "checkbox"},
+}
+
+func TestParseSQL(t *testing.T) {
+	q, err := parseSQL(`select Name, "Due date" FROM 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d WHERE Status = 'Doing' AND NOT (Estimate > 3 OR Name like 'x%') ORDER BY "Due date" desc, Name LIMIT 5;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(q.Columns, "|") != "Name|Due date" || q.From != "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d" || q.Limit != 5 {
+		t.Errorf("query = %+v", q)
+	}
+	if len(q.OrderBy) != 2 || !q.OrderBy[0].Desc || q.OrderBy[1].Desc {
+		t.Errorf("order by = %+v", q.OrderBy)
+	}
+	want := `"Status" = 'Doing' AND "Estimate" <= '3' AND "Name" NOT LIKE 'x%'`
+	if got := normalizeSQL(q.Where, false).String(); got != want {
+		t.Errorf("normalized WHERE:\n got %s\nwant %s", got, want)
+	}
+
+	for _, bad := range []string{
+		"SELECT FROM t",
+		"SELECT * FROM t WHERE",
+		"SELECT * FROM t WHERE Name = ",
+		"SELECT * FROM t LIMIT 0",
+		"SELECT * FROM t WHERE Name = 'x' extra",
+		"SELECT * FROM t WHERE Name = 'unterminated",
+	} {
+		if _, err := parseSQL(bad); err == nil {
+			t.Errorf("parseSQL(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPlanSQLSplitsServerAndClient(t *testing.T) {
+	q, _ := parseSQL(`SELECT name FROM t WHERE status = 'Doing' AND tags IN ('bug', 'p1') AND Name > 'M' AND "due date" >= '2026-01-01' AND done = false ORDER BY created_time DESC`)
+	plan, err := planSQL(q, sqlTestSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, _ := json.Marshal(plan.Body["filter"])
+	want := `{"and":[{"property":"Status","status":{"equals":"Doing"}},` +
+		`{"or":[{"multi_select":{"contains":"bug"},"property":"Tags"},{"multi_select":{"contains":"p1"},"property":"Tags"}]},` +
+		`{"date":{"on_or_after":"2026-01-01"},"property":"Due date"},` +
+		`{"checkbox":{"equals":false},"property":"Done"}]}`
+	if string(filter) != want {
+		t.Errorf("filter:\n got %s\nwant %s", filter, want)
+	}
+	if plan.Residual == nil || plan.Residual.String() != `"Name" > 'M'` {
+		t.Errorf("residual = %v", plan.Residual)
+	}
+	sorts, _ := json.Marshal(plan.Body["sorts"])
+	if string(sorts) != `[{"direction":"descending","timestamp":"created_time"}]` {
+		t.Errorf("sorts = %s", sorts)
+	}
+	if strings.Join(plan.Columns, ",") != "Name" || strings.Join(plan.PropIDs, ",") != "title,title" {
+		t.Errorf("columns %v, property ids %v", plan.Columns, plan.PropIDs)
+	}
+
+	q, _ = parseSQL(`SELECT * FROM t WHERE Status = 'Done' OR Name LIKE '%a_b%'`)
+	plan, _ = planSQL(q, sqlTestSchema)
+	if plan.Body["filter"] != nil || plan.Residual == nil {
+		t.Errorf("an OR with a client-side term should run client-side: %v / %v", plan.Body, plan.Residual)
+	}
+	if _, err := planSQL(&sqlQuery{Columns: []string{"Nope"}}, sqlTestSchema); err == nil {
+		t.Error("unknown column should fail")
+	}
+}
+
+func TestEvalSQL(t *testing.T) {
+	var page map[string]interface{}
+	json.Unmarshal([]byte(`{"id": "p1", "created_time": "2026-02-01T09:00:00.000Z", "properties": {
+	  "Name": {"type": "title", "title": [{"plain_text": "Fix login"}]},
+	  "Tags": {"type": "multi_select", "multi_select": [{"name": "bug"}, {"name": "p1"}]},
+	  "Estimate": {"type": "number", "number": 10},
+	  "Due date": {"type": "date", "date": {"start": "2026-03-01T10:00:00.000Z"}},
+	  "Status": {"type": "status", "status": null}}}`), &page)
+	types := map[string]string{"Name": "title", "Tags": "multi_select", "Estimate": "number", "Due date": "date", "Status": "status", "created_time": "created_time"}
+	for expr, want := range map[string]bool{
+		`Name LIKE 'fix%'`:                  true,
+		`Name > 'M'`:                        false,
+		`Estimate > 9`:                      true,
+		`Estimate < 9`:                      false,
+		`Tags = 'p1'`:                       true,
+		`Tags NOT IN ('bug')`:               false,
+		`"Due date" = '2026-03-01'`:         true,
+		`Status IS NULL`:                    true,
+		`Status = 'Done' OR Estimate >= 10`: true,
+		`NOT (Name LIKE '%login')`:          false,
+		`created_time < '2026-03-01'`:       true,
+	} {
+		q, err := parseSQL("SELECT * FROM t WHERE " + expr)
+		if err != nil {
+			t.Fatalf("%s: %v", expr, err)
+		}
+		if got := evalSQL(normalizeSQL(q.Where, false), page, types); got != want {
+			t.Errorf("%s = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestSQLCommandAppliesClientSideCondition(t *testing.T) {
+	var query map[string]interface{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"object": "database", "properties": sqlTestSchema})
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&query)
+		rows := []interface{}{}
+		for _, name := range []string{"Alpha", "Zulu", "Mike"} {
+			rows = append(rows, map[string]interface{}{"id": name, "properties": map[string]interface{}{
+				"Name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": name}}},
+			}})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": rows})
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("sql", "--format", "json", "SELECT Name FROM 66666666666666666666666666666666 WHERE Name >= 'M' AND Status != 'Done' ORDER BY Name"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var result struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output %q: %v", out, err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0]["Name"] != "Zulu" || result.Rows[1]["Name"] != "Mike" {
+		t.Errorf("rows = %v", result.Rows)
+	}
+	if query["filter"] == nil || query["sorts"] == nil {
+		t.Errorf("query body = %v", query)
+	}
+}
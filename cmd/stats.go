@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// maxRetries is how many times a rate-limited or transient request is
+// resent before its error is reported.
+const maxRetries = 3
+
+// statsFormat is the --stats flag: "" (off), "text" or "json".
+var statsFormat string
+
+// requestStats counts the API traffic of one command.
+type requestStats struct {
+	mu            sync.Mutex
+	started       time.Time
+	requests      int
+	failed        int
+	retries       int
+	rateLimited   int
+	rateLimitWait time.Duration
+	throttleWait  time.Duration
+	latency       time.Duration
+}
+
+// statsSummary is the JSON form of the --stats summary.
+type statsSummary struct {
+	Requests        int   `json:"requests"`
+	Failed          int   `json:"failed"`
+	Retries         int   `json:"retries"`
+	RateLimited     int   `json:"rate_limited"`
+	RateLimitWaitMS int64 `json:"rate_limit_wait_ms"`
+	ThrottleWaitMS  int64 `json:"throttle_wait_ms"`
+	LatencyMS       int64 `json:"latency_ms"`
+	ElapsedMS       int64 `json:"elapsed_ms"`
+}
+
+var stats = &requestStats{started: time.Now()}
+
+// reset starts counting for a new command.
+func (s *requestStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = time.Now()
+	s.requests, s.failed, s.retries, s.rateLimited = 0, 0, 0, 0
+	s.rateLimitWait, s.throttleWait, s.latency = 0, 0, 0
+}
+
+// countRequests is client middleware recording every request sent to the
+// API and how long it took.
+func countRequests(next notion.Handler) notion.Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next(req)
+		stats.mu.Lock()
+		stats.requests++
+		stats.latency += time.Since(start)
+		if err != nil || resp.StatusCode >= 400 {
+			stats.failed++
+		}
+		stats.mu.Unlock()
+		return resp, err
+	}
+}
+
+// noteRetry is the notion.Retry callback: it records the retry and, for
+// rate limits, the time spent waiting.
+func noteRetry(resp *http.Response, wait time.Duration) {
+	stats.mu.Lock()
+	stats.retries++
+	if resp.StatusCode == http.StatusTooManyRequests {
+		stats.rateLimited++
+		stats.rateLimitWait += wait
+	}
+	stats.mu.Unlock()
+	if debugMode {
+		fmt.Printf("↻ %d, retrying in %s\n", resp.StatusCode, wait)
+	}
+}
+
+// noteThrottle records time a command spent pacing its own requests.
+func noteThrottle(wait time.Duration) {
+	stats.mu.Lock()
+	stats.throttleWait += wait
+	stats.mu.Unlock()
+}
+
+func (s *requestStats) summary() statsSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return statsSummary{
+		Requests:        s.requests,
+		Failed:          s.failed,
+		Retries:         s.retries,
+		RateLimited:     s.rateLimited,
+		RateLimitWaitMS: s.rateLimitWait.Milliseconds(),
+		ThrottleWaitMS:  s.throttleWait.Milliseconds(),
+		LatencyMS:       s.latency.Milliseconds(),
+		ElapsedMS:       time.Since(s.started).Milliseconds(),
+	}
+}
+
+// reportStats prints the --stats summary of the command that just ran to
+// stderr, leaving stdout to the command's own output.
+func reportStats(w io.Writer) {
+	switch statsFormat {
+	case "":
+		return
+	case "json":
+		data, _ := json.Marshal(stats.summary())
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	fmt.Fprintln(w, formatStats(stats.summary()))
+}
+
+func formatStats(s statsSummary) string {
+	parts := []string{fmt.Sprintf("%d request(s)", s.Requests)}
+	if s.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", s.Failed))
+	}
+	if s.Retries > 0 {
+		parts = append(parts, fmt.Sprintf("%d retried", s.Retries))
+	}
+	if s.RateLimited > 0 {
+		parts = append(parts, fmt.Sprintf("rate-limited %d× (%s waiting)", s.RateLimited, msDuration(s.RateLimitWaitMS)))
+	}
+	if s.ThrottleWaitMS > 0 {
+		parts = append(parts, fmt.Sprintf("%s throttled", msDuration(s.ThrottleWaitMS)))
+	}
+	avg := int64(0)
+	if s.Requests > 0 {
+		avg = s.LatencyMS / int64(s.Requests)
+	}
+	parts = append(parts, fmt.Sprintf("%s in API (avg %s)", msDuration(s.LatencyMS), msDuration(avg)))
+	return fmt.Sprintf("stats: %s; %s total", strings.Join(parts, ", "), msDuration(s.ElapsedMS))
+}
+
+func msDuration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// validateStatsFlag rejects unknown --stats formats before the command runs.
+func validateStatsFlag() error {
+	switch statsFormat {
+	case "", "text", "json":
+		return nil
+	}
+	return usageError(fmt.Errorf("invalid --stats format %q: use text or json", statsFormat))
+}
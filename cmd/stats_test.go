@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatsCountsRequestsAndRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"code": "rate_limited", "message": "Slow down"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"object": "list", "results": []interface{}{}})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func() { statsFormat = "" }()
+
+	if _, _, err := executeCommand("search", "roadmap", "--stats=json"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	reportStats(&buf)
+	var s statsSummary
+	if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+		t.Fatalf("stats output %q: %v", buf.String(), err)
+	}
+	if s.Requests != 2 || s.Failed != 1 || s.Retries != 1 || s.RateLimited != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	got := formatStats(statsSummary{Requests: 4, Retries: 1, RateLimited: 1, RateLimitWaitMS: 1000, LatencyMS: 800, ElapsedMS: 2500})
+	for _, want := range []string{"4 request(s)", "1 retried", "rate-limited 1× (1s waiting)", "800ms in API (avg 200ms)", "2.5s total"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatStats() = %q, missing %q", got, want)
+		}
+	}
+}
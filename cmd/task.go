@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// doneWords are select option names that mark a task as finished.
+var doneWords = map[string]bool{
+	"done": true, "complete": true, "completed": true, "closed": true,
+	"cancelled": true, "canceled": true, "shipped": true, "resolved": true,
+}
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Helpers for task databases",
+}
+
+var taskRolloverCmd = &cobra.Command{
+	Use:   "rollover [db-id|url]",
+	Short: "Move overdue, unfinished tasks to today",
+	Long: `Find the tasks of a database that are past their due date but not done,
+and move their due date to today (or --to), keeping any time of day and the
+length of date ranges. With --comment the dates are left alone and each
+task gets the comment instead.
+
+The database is the argument or the tasks_db setting. The due date is the
+date property named by --due, else "Due" or "Due date", else the only date
+property. A task is done when its status is in the Complete group, its
+"Done" checkbox is checked, or its select reads Done, Closed, Cancelled
+and the like (--done picks the property).
+
+Examples:
+  notion config set tasks_db <db-id>
+  notion task rollover --dry-run
+  notion task rollover <db-id> --due Deadline --to 2026-03-02
+  notion task rollover --comment "Overdue — still on?"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dueFlag, _ := cmd.Flags().GetString("due")
+		doneFlag, _ := cmd.Flags().GetString("done")
+		toFlag, _ := cmd.Flags().GetString("to")
+		comment, _ := cmd.Flags().GetString("comment")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		target := ""
+		if len(args) == 1 {
+			target = args[0]
+		} else {
+			cfg, _ := config.Load()
+			target = cfg.Setting("tasks_db")
+		}
+		if target == "" {
+			return usageError(fmt.Errorf("no task database: pass one or 'notion config set tasks_db <db-id>'"))
+		}
+		today := time.Now().Format("2006-01-02")
+		if toFlag != "" {
+			if _, err := time.Parse("2006-01-02", toFlag); err != nil {
+				return usageError(fmt.Errorf("--to must be a date like 2026-03-01, got %q", toFlag))
+			}
+			today = toFlag
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c, ctx, stop := interruptible(newClient(token))
+		defer stop()
+		dbID := util.ResolveID(target)
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database schema: %w", err)
+		}
+		schema, _ := db["properties"].(map[string]interface{})
+		due, err := dateProperty(schema, dueFlag, "--due", "Due", "Due date")
+		if err != nil {
+			return err
+		}
+		isDone, err := taskDoneCheck(schema, doneFlag)
+		if err != nil {
+			return err
+		}
+
+		rows, err := c.QueryDatabaseAll(dbID, map[string]interface{}{
+			"filter": map[string]interface{}{"property": due, "date": map[string]interface{}{"before": today}},
+		})
+		if err != nil {
+			return fmt.Errorf("query database: %w", err)
+		}
+		var tasks []overdueTask
+		for _, r := range rows {
+			page, _ := r.(map[string]interface{})
+			if isDone(page) {
+				continue
+			}
+			props, _ := page["properties"].(map[string]interface{})
+			prop, _ := props[due].(map[string]interface{})
+			date, _ := prop["date"].(map[string]interface{})
+			id, _ := page["id"].(string)
+			t := overdueTask{ID: id, Title: render.ExtractTitle(page), date: date}
+			t.Due, _ = date["start"].(string)
+			if comment == "" {
+				t.NewDue = rolledDate(date, today)["start"].(string)
+			}
+			tasks = append(tasks, t)
+		}
+
+		if dryRun || len(tasks) == 0 {
+			if outputFormat == "json" {
+				if tasks == nil {
+					tasks = []overdueTask{}
+				}
+				return render.JSON(map[string]interface{}{"property": due, "to": today, "count": len(tasks), "tasks": tasks})
+			}
+			if len(tasks) == 0 {
+				fmt.Println("No overdue tasks.")
+				return nil
+			}
+			printOverdueTasks(tasks, comment != "")
+			fmt.Println()
+			verb := "moved to " + today
+			if comment != "" {
+				verb = "commented on"
+			}
+			fmt.Printf("%d overdue task(s) would be %s\n", len(tasks), verb)
+			return nil
+		}
+
+		done := 0
+		var errors []string
+		progress := newProgress("task rollover", "%d/%d tasks updated", len(tasks), os.Stdout)
+		defer progress.stop()
+		const resume = "run the same command again to continue: moved tasks are no longer overdue"
+		for _, t := range tasks {
+			if ctx.Err() != nil {
+				return progress.interrupted(resume)
+			}
+			if comment != "" {
+				_, err = c.AddComment(t.ID, comment, nil)
+			} else {
+				_, err = c.Patch("/v1/pages/"+t.ID, map[string]interface{}{
+					"properties": map[string]interface{}{due: map[string]interface{}{"date": rolledDate(t.date, today)}},
+				})
+			}
+			if err != nil {
+				if canceled(err) {
+					return progress.interrupted(resume)
+				}
+				errors = append(errors, fmt.Sprintf("%s: %v", t.ID, err))
+				progress.fail(t.ID, err)
+				continue
+			}
+			done++
+			progress.add(1)
+		}
+		progress.finish()
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"matched": len(tasks), "updated": done, "tasks": tasks, "errors": errors})
+		}
+		fmt.Println()
+		printOverdueTasks(tasks, comment != "")
+		if comment != "" {
+			fmt.Printf("\n✓ Commented on %d/%d overdue task(s)\n", done, len(tasks))
+		} else {
+			fmt.Printf("\n✓ Moved %d/%d overdue task(s) to %s\n", done, len(tasks), today)
+		}
+		for _, e := range errors {
+			fmt.Printf("  ✗ %s\n", e)
+		}
+		return nil
+	},
+}
+
+func init() {
+	taskRolloverCmd.Flags().String("due", "", "Date property holding the due date")
+	taskRolloverCmd.Flags().String("done", "", "Status, checkbox or select property telling whether a task is done")
+	taskRolloverCmd.Flags().String("to", "", "Move due dates to this date instead of today (2026-03-01)")
+	taskRolloverCmd.Flags().String("comment", "", "Comment on overdue tasks instead of moving them")
+	taskRolloverCmd.Flags().Bool("dry-run", false, "List the overdue tasks without changing them")
+	taskCmd.AddCommand(taskRolloverCmd)
+}
+
+// overdueTask is a task 'task rollover' moves or comments on.
+type overdueTask struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Due    string `json:"due"`
+	NewDue string `json:"new_due,omitempty"`
+
+	date map[string]interface{}
+}
+
+func printOverdueTasks(tasks []overdueTask, commenting bool) {
+	var rows [][]string
+	for _, t := range tasks {
+		row := []string{t.Title, t.Due}
+		if !commenting {
+			row = append(row, t.NewDue)
+		}
+		rows = append(rows, append(row, t.ID))
+	}
+	if commenting {
+		render.Table([]string{"TASK", "DUE", "ID"}, rows)
+		return
+	}
+	render.Table([]string{"TASK", "DUE", "NEW DUE", "ID"}, rows)
+}
+
+// dateProperty picks a date property: name if given (flag names the
+// option it came from), else the first of preferred, else the only date
+// property.
+func dateProperty(schema map[string]interface{}, name, flag string, preferred ...string) (string, error) {
+	if name != "" {
+		names, _, err := resolveColumns(schema, []string{name})
+		if err != nil {
+			return "", err
+		}
+		if prop, _ := schema[names[0]].(map[string]interface{}); prop["type"] != "date" {
+			return "", fmt.Errorf("%s %s is a %v property, not a date", flag, names[0], prop["type"])
+		}
+		return names[0], nil
+	}
+	var dates []string
+	for _, n := range sortedKeys(schema) {
+		if prop, _ := schema[n].(map[string]interface{}); prop["type"] == "date" {
+			dates = append(dates, n)
+		}
+	}
+	for _, want := range preferred {
+		for _, n := range dates {
+			if strings.EqualFold(n, want) {
+				return n, nil
+			}
+		}
+	}
+	if len(dates) == 1 {
+		return dates[0], nil
+	}
+	if len(dates) == 0 {
+		return "", fmt.Errorf("there is no date property")
+	}
+	return "", usageError(fmt.Errorf("several date properties (%s); pick one with %s", strings.Join(dates, ", "), flag))
+}
+
+// taskDoneCheck returns a test for finished tasks, based on the property
+// doneProperty picks.
+func taskDoneCheck(schema map[string]interface{}, name string) (func(page map[string]interface{}) bool, error) {
+	name, err := doneProperty(schema, name)
+	if err != nil {
+		return nil, err
+	}
+
+	prop, _ := schema[name].(map[string]interface{})
+	propType, _ := prop["type"].(string)
+	value := func(page map[string]interface{}) map[string]interface{} {
+		props, _ := page["properties"].(map[string]interface{})
+		v, _ := props[name].(map[string]interface{})
+		return v
+	}
+	switch propType {
+	case "checkbox":
+		return func(page map[string]interface{}) bool {
+			checked, _ := value(page)["checkbox"].(bool)
+			return checked
+		}, nil
+	case "select":
+		return func(page map[string]interface{}) bool {
+			sel, _ := value(page)["select"].(map[string]interface{})
+			option, _ := sel["name"].(string)
+			return doneWords[strings.ToLower(option)]
+		}, nil
+	case "status":
+		complete := completeStatusOptions(prop)
+		return func(page map[string]interface{}) bool {
+			status, _ := value(page)["status"].(map[string]interface{})
+			id, _ := status["id"].(string)
+			option, _ := status["name"].(string)
+			return complete[id] || (len(complete) == 0 && doneWords[strings.ToLower(option)])
+		}, nil
+	}
+	return nil, usageError(fmt.Errorf("--done %s is a %s property; use a status, checkbox or select", name, propType))
+}
+
+// doneProperty is the property telling whether a task is done: the one
+// named, else the first status property, else a checkbox called Done, else
+// a select called Status.
+func doneProperty(schema map[string]interface{}, name string) (string, error) {
+	if name != "" {
+		names, _, err := resolveColumns(schema, []string{name})
+		if err != nil {
+			return "", err
+		}
+		return names[0], nil
+	}
+	for _, n := range sortedKeys(schema) {
+		prop, _ := schema[n].(map[string]interface{})
+		if prop["type"] == "status" ||
+			(prop["type"] == "checkbox" && strings.EqualFold(n, "done")) ||
+			(prop["type"] == "select" && strings.EqualFold(n, "status")) {
+			name = n
+			if prop["type"] == "status" {
+				break
+			}
+		}
+	}
+	if name == "" {
+		return "", usageError(fmt.Errorf("can't tell which tasks are done: pick a status, checkbox or select property with --done"))
+	}
+	return name, nil
+}
+
+// completeStatusOptions returns the IDs of the options in a status
+// property's Complete group.
+func completeStatusOptions(prop map[string]interface{}) map[string]bool {
+	status, _ := prop["status"].(map[string]interface{})
+	groups, _ := status["groups"].([]interface{})
+	ids := map[string]bool{}
+	for _, g := range groups {
+		group, _ := g.(map[string]interface{})
+		if name, _ := group["name"].(string); !strings.EqualFold(name, "complete") {
+			continue
+		}
+		options, _ := group["option_ids"].([]interface{})
+		for _, o := range options {
+			if id, ok := o.(string); ok {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// rolledDate moves a date property value so it starts on day, keeping the
+// time of day, the time zone and the length of a range.
+func rolledDate(date map[string]interface{}, day string) map[string]interface{} {
+	start, _ := date["start"].(string)
+	target, _ := time.Parse("2006-01-02", day)
+	from, _ := time.Parse("2006-01-02", firstN(start, 10))
+	days := int(target.Sub(from).Hours() / 24)
+
+	out := map[string]interface{}{"start": shiftDate(start, days)}
+	if end, _ := date["end"].(string); end != "" {
+		out["end"] = shiftDate(end, days)
+	}
+	if tz, _ := date["time_zone"].(string); tz != "" {
+		out["time_zone"] = tz
+	}
+	return out
+}
+
+// shiftDate adds days to an API date ("2026-03-01" or a date-time),
+// leaving the rest of it alone.
+func shiftDate(value string, days int) string {
+	if len(value) < 10 {
+		return value
+	}
+	d, err := time.Parse("2006-01-02", value[:10])
+	if err != nil {
+		return value
+	}
+	return d.AddDate(0, 0, days).Format("2006-01-02") + value[10:]
+}
+
+func firstN(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRolledDate(t *testing.T) {
+	tests := []struct {
+		date map[string]interface{}
+		want map[string]interface{}
+	}{
+		{map[string]interface{}{"start": "2026-10-10"}, map[string]interface{}{"start": "2026-10-16"}},
+		{
+			map[string]interface{}{"start": "2026-10-10T09:30:00.000+02:00", "end": "2026-10-12T10:00:00.000+02:00", "time_zone": nil},
+			map[string]interface{}{"start": "2026-10-16T09:30:00.000+02:00", "end": "2026-10-18T10:00:00.000+02:00"},
+		},
+		{map[string]interface{}{"start": "2026-09-30", "time_zone": "Europe/Berlin"}, map[string]interface{}{"start": "2026-10-16", "time_zone": "Europe/Berlin"}},
+	}
+	for _, tt := range tests {
+		if got := rolledDate(tt.date, "2026-10-16"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("rolledDate(%v) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestTaskDoneCheck(t *testing.T) {
+	schema := map[string]interface{}{
+		"Stage": map[string]interface{}{"type": "status", "status": map[string]interface{}{"groups": []interface{}{
+			map[string]interface{}{"name": "To-do", "option_ids": []interface{}{"o1"}},
+			map[string]interface{}{"name": "Complete", "option_ids": []interface{}{"o2", "o3"}},
+		}}},
+		"Done": map[string]interface{}{"type": "checkbox"},
+	}
+	row := func(prop string, value interface{}) map[string]interface{} {
+		return map[string]interface{}{"properties": map[string]interface{}{prop: value}}
+	}
+
+	isDone, err := taskDoneCheck(schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isDone(row("Stage", map[string]interface{}{"status": map[string]interface{}{"id": "o3", "name": "Won't do"}})) {
+		t.Error("status in the Complete group should be done")
+	}
+	if isDone(row("Stage", map[string]interface{}{"status": map[string]interface{}{"id": "o1", "name": "Not started"}})) {
+		t.Error("to-do status should not be done")
+	}
+
+	isDone, err = taskDoneCheck(schema, "done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isDone(row("Done", map[string]interface{}{"checkbox": true})) || isDone(row("Done", map[string]interface{}{"checkbox": false})) {
+		t.Error("checkbox check is wrong")
+	}
+
+	if _, err := taskDoneCheck(map[string]interface{}{"Name": map[string]interface{}{"type": "title"}}, ""); err == nil {
+		t.Error("expected an error without a status, checkbox or select")
+	}
+}
+
+func TestTaskRolloverCommand(t *testing.T) {
+	const dbID = "11111111-1111-1111-1111-111111111111"
+	task := func(id, title, due string, done bool) map[string]interface{} {
+		return map[string]interface{}{"id": id, "properties": map[string]interface{}{
+			"Name": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": title}}},
+			"Due":  map[string]interface{}{"type": "date", "date": map[string]interface{}{"start": due}},
+			"Done": map[string]interface{}{"type": "checkbox", "checkbox": done},
+		}}
+	}
+	var queries, writes []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			queries = append(queries, string(body))
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+				task("t1", "Write report", "2026-03-01", false),
+				task("t2", "Send invoice", "2026-02-27", true),
+			}})
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": dbID, "properties": map[string]interface{}{
+				"Name":    map[string]interface{}{"type": "title"},
+				"Due":     map[string]interface{}{"type": "date"},
+				"Created": map[string]interface{}{"type": "date"},
+				"Done":    map[string]interface{}{"type": "checkbox"},
+			}})
+		default:
+			writes = append(writes, r.Method+" "+r.URL.Path+" "+string(body))
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("task", "rollover", dbID, "--to", "2026-03-04", "--dry-run"); err != nil {
+			t.Fatalf("task rollover --dry-run: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Write report") || strings.Contains(out, "Send invoice") || !strings.Contains(out, "1 overdue task(s) would be moved to 2026-03-04") {
+		t.Errorf("dry run:\n%s", out)
+	}
+	if len(writes) != 0 {
+		t.Fatalf("dry run wrote: %v", writes)
+	}
+	if want := `{"filter":{"date":{"before":"2026-03-04"},"property":"Due"},"page_size":100}`; len(queries) == 0 || queries[0] != want {
+		t.Errorf("query = %v, want %s", queries, want)
+	}
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("task", "rollover", dbID, "--to", "2026-03-04", "--dry-run=false"); err != nil {
+			t.Fatalf("task rollover: %v", err)
+		}
+	})
+	want := `PATCH /v1/pages/t1 {"properties":{"Due":{"date":{"start":"2026-03-04"}}}}`
+	if len(writes) != 1 || writes[0] != want {
+		t.Errorf("writes = %v, want %s", writes, want)
+	}
+
+	if _, _, err := executeCommand("task", "rollover"); err == nil || !strings.Contains(err.Error(), "tasks_db") {
+		t.Errorf("no database: err = %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+)
+
+var (
+	// timeZone is the --tz flag; empty falls back to the timezone setting.
+	timeZone string
+	// dateLocation is the zone in force for the running command: times
+	// written without an offset are taken to be in it.
+	dateLocation = time.Local
+)
+
+// resetDateLocation resolves the zone for a new command: --tz, then
+// 'notion config set timezone', then the system's local zone.
+func resetDateLocation() error {
+	dateLocation = time.Local
+	name, source := timeZone, "--tz"
+	if name == "" {
+		if cfg, err := config.Load(); err == nil {
+			name, source = cfg.Setting("timezone"), "timezone setting"
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return usageError(fmt.Errorf("%s: unknown time zone %q (use a name like Europe/Berlin)", source, name))
+	}
+	dateLocation = loc
+	return nil
+}
+
+func validateTimeZone(v string) error {
+	if _, err := time.LoadLocation(v); err != nil || v == "" {
+		return fmt.Errorf("expected a time zone name like Europe/Berlin, got %q", v)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPageSetDateInTimeZone(t *testing.T) {
+	const pageID = "11111111-1111-1111-1111-111111111111"
+	var patched string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == "PATCH" {
+			patched = string(body)
+		}
+		w.Write([]byte(`{"object":"page","id":"` + pageID + `","properties":{"Due":{"id":"d","type":"date","date":null}}}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if _, _, err := executeCommand("page", "set", pageID, "Due=2026-03-01", "--tz", "Mars/Olympus"); err == nil || !strings.Contains(err.Error(), "unknown time zone") {
+		t.Errorf("bad --tz: err = %v", err)
+	}
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "set", pageID, "Due=2026-03-01T14:00..16:00", "--tz", "Europe/Berlin"); err != nil {
+			t.Fatalf("page set: %v", err)
+		}
+	})
+	want := `{"properties":{"Due":{"date":{"end":"2026-03-01T16:00:00+01:00","start":"2026-03-01T14:00:00+01:00"}}}}`
+	if patched != want {
+		t.Errorf("PATCH body = %s, want %s", patched, want)
+	}
+	// Flags persist between executeCommand calls.
+	executeCommand("page", "set", pageID, "Due=2026-03-01", "--tz", "")
+}
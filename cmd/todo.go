@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// todoLineRe matches an unindented GitHub-flavored markdown checkbox line.
+var todoLineRe = regexp.MustCompile(`^[-*+] \[([ xX])\] (.*)$`)
+
+// localTodo is a checkbox line of the markdown file.
+type localTodo struct {
+	Line    int
+	Text    string
+	Checked bool
+}
+
+// remoteTodo is a to_do block of the page.
+type remoteTodo struct {
+	ID      string
+	Text    string
+	Checked bool
+}
+
+// syncedTodo is the state of an item after the previous sync.
+type syncedTodo struct {
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// todoAction is one change made by 'todo sync'.
+type todoAction struct {
+	Action string `json:"action"`
+	Where  string `json:"where"`
+	Text   string `json:"text"`
+}
+
+// todoPlan is everything 'todo sync' will change.
+type todoPlan struct {
+	// Remote changes.
+	Create  []localTodo
+	Check   map[string]bool // block ID → checked
+	Archive []string
+	// Local changes, by line number; lines with an empty replacement are
+	// removed. Append holds new lines for the end of the checklist.
+	Replace map[int]string
+	Append  []string
+	Actions []todoAction
+}
+
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "Sync markdown checklists with Notion to-do blocks",
+}
+
+var todoSyncCmd = &cobra.Command{
+	Use:   "sync <page-id|url> --file <path>",
+	Short: "Two-way sync between a markdown checklist and a page's to-dos",
+	Long: `Keep a markdown checklist (such as a repo's TODO.md) and the to-do blocks
+of a Notion page in step.
+
+Items are the file's unindented "- [ ] text" / "- [x] text" lines and the
+page's top-level to-do blocks, matched by text. On each run:
+  - new lines in the file become to-do blocks at the end of the page
+  - new to-dos on the page are added after the file's last checklist line
+  - ticking or unticking on either side is copied to the other
+  - items removed from the file are archived on the page, and to-dos
+    deleted on the page are removed from the file
+  - a to-do renamed on the page is renamed in the file
+
+The state after each sync is kept in the cache directory, which is how
+removals are told apart from additions. Editing an item's text in the
+file counts as removing the old item and adding a new one. When both
+sides changed the same checkbox, the file wins. Other lines of the file
+and other blocks of the page are never touched.
+
+Examples:
+  notion todo sync abc123 --file TODO.md
+  notion todo sync abc123 --file TODO.md --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if file == "" {
+			return usageError(fmt.Errorf("--file is required"))
+		}
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("read %s: %w", file, err)
+		}
+		lines := strings.Split(string(data), "\n")
+		if len(data) == 0 {
+			lines = nil
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		pageID := util.ResolveID(args[0])
+		c := newClient(token)
+
+		blocks, err := c.GetBlockChildrenAll(pageID)
+		if err != nil {
+			return fmt.Errorf("get page content: %w", err)
+		}
+		base := loadTodoState(pageID)
+		plan := planTodoSync(parseLocalTodos(lines), remoteTodos(blocks), base)
+
+		if !dryRun {
+			if err := applyTodoPlan(c, pageID, plan); err != nil {
+				return err
+			}
+			newLines := applyLocalTodoChanges(lines, plan)
+			if len(plan.Replace) > 0 || len(plan.Append) > 0 {
+				if err := os.WriteFile(file, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
+					return fmt.Errorf("write %s: %w", file, err)
+				}
+			}
+			// Re-read the page so the saved state holds the new block IDs.
+			blocks, err := c.GetBlockChildrenAll(pageID)
+			if err != nil {
+				return fmt.Errorf("get page content: %w", err)
+			}
+			if err := saveTodoState(pageID, remoteTodos(blocks)); err != nil {
+				return err
+			}
+		}
+
+		if outputFormat == "json" {
+			actions := plan.Actions
+			if actions == nil {
+				actions = []todoAction{}
+			}
+			return render.JSON(map[string]interface{}{"dry_run": dryRun, "changes": actions})
+		}
+		if len(plan.Actions) == 0 {
+			fmt.Println("✓ Already in sync")
+			return nil
+		}
+		for _, a := range plan.Actions {
+			fmt.Printf("  %-8s %-6s %s\n", a.Action, a.Where, a.Text)
+		}
+		if dryRun {
+			fmt.Printf("%d change(s) would be made (dry run)\n", len(plan.Actions))
+		} else {
+			fmt.Printf("✓ %d change(s) synced\n", len(plan.Actions))
+		}
+		return nil
+	},
+}
+
+func init() {
+	todoSyncCmd.Flags().String("file", "", "Markdown checklist file (created if missing)")
+	todoSyncCmd.Flags().Bool("dry-run", false, "Show the changes without making them")
+	todoCmd.AddCommand(todoSyncCmd)
+}
+
+func parseLocalTodos(lines []string) []localTodo {
+	var todos []localTodo
+	for i, line := range lines {
+		if m := todoLineRe.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			todos = append(todos, localTodo{Line: i, Text: strings.TrimSpace(m[2]), Checked: m[1] != " "})
+		}
+	}
+	return todos
+}
+
+func remoteTodos(blocks []interface{}) []remoteTodo {
+	var todos []remoteTodo
+	for _, b := range blocks {
+		block, _ := b.(map[string]interface{})
+		if t, _ := block["type"].(string); t != "to_do" {
+			continue
+		}
+		data, _ := block["to_do"].(map[string]interface{})
+		id, _ := block["id"].(string)
+		checked, _ := data["checked"].(bool)
+		todos = append(todos, remoteTodo{ID: id, Text: strings.TrimSpace(richTextToMarkdown(data["rich_text"])), Checked: checked})
+	}
+	return todos
+}
+
+// planTodoSync works out the changes that bring local and remote in step,
+// given the state after the previous sync (keyed by block ID).
+func planTodoSync(local []localTodo, remote []remoteTodo, base map[string]syncedTodo) todoPlan {
+	plan := todoPlan{Check: map[string]bool{}, Replace: map[int]string{}}
+	localUsed := make([]bool, len(local))
+	remoteUsed := make([]bool, len(remote))
+
+	match := func(text string) int {
+		for i, l := range local {
+			if !localUsed[i] && l.Text == text {
+				return i
+			}
+		}
+		return -1
+	}
+	syncChecked := func(l localTodo, r remoteTodo) {
+		if l.Checked == r.Checked {
+			return
+		}
+		b, known := base[r.ID]
+		if known && l.Checked == b.Checked {
+			// Only the page changed.
+			plan.Replace[l.Line] = todoLine(l.Text, r.Checked)
+			plan.Actions = append(plan.Actions, todoAction{checkVerb(r.Checked), "file", l.Text})
+			return
+		}
+		plan.Check[r.ID] = l.Checked
+		plan.Actions = append(plan.Actions, todoAction{checkVerb(l.Checked), "notion", l.Text})
+	}
+
+	for ri, r := range remote {
+		if li := match(r.Text); li >= 0 {
+			localUsed[li], remoteUsed[ri] = true, true
+			syncChecked(local[li], r)
+		}
+	}
+	// Renamed on the page: the file still has the text of the last sync.
+	for ri, r := range remote {
+		b, known := base[r.ID]
+		if remoteUsed[ri] || !known {
+			continue
+		}
+		if li := match(b.Text); li >= 0 {
+			localUsed[li], remoteUsed[ri] = true, true
+			l := local[li]
+			plan.Replace[l.Line] = todoLine(r.Text, l.Checked)
+			plan.Actions = append(plan.Actions, todoAction{"rename", "file", b.Text + " → " + r.Text})
+			l.Text = r.Text
+			syncChecked(l, r)
+		}
+	}
+	for ri, r := range remote {
+		if remoteUsed[ri] {
+			continue
+		}
+		if _, known := base[r.ID]; known {
+			plan.Archive = append(plan.Archive, r.ID)
+			plan.Actions = append(plan.Actions, todoAction{"archive", "notion", r.Text})
+		} else {
+			plan.Append = append(plan.Append, todoLine(r.Text, r.Checked))
+			plan.Actions = append(plan.Actions, todoAction{"add", "file", r.Text})
+		}
+	}
+
+	// Local items without a block: deleted on the page if they were synced
+	// before, otherwise new.
+	remoteIDs := map[string]bool{}
+	for _, r := range remote {
+		remoteIDs[r.ID] = true
+	}
+	baseTexts := map[string]bool{}
+	for id, b := range base {
+		if !remoteIDs[id] {
+			baseTexts[b.Text] = true
+		}
+	}
+	for li, l := range local {
+		if localUsed[li] {
+			continue
+		}
+		if baseTexts[l.Text] {
+			plan.Replace[l.Line] = ""
+			plan.Actions = append(plan.Actions, todoAction{"remove", "file", l.Text})
+			continue
+		}
+		plan.Create = append(plan.Create, l)
+		plan.Actions = append(plan.Actions, todoAction{"add", "notion", l.Text})
+	}
+	return plan
+}
+
+func checkVerb(checked bool) string {
+	if checked {
+		return "check"
+	}
+	return "uncheck"
+}
+
+func todoLine(text string, checked bool) string {
+	if checked {
+		return "- [x] " + text
+	}
+	return "- [ ] " + text
+}
+
+func applyTodoPlan(c *notion.Client, pageID string, plan todoPlan) error {
+	for id, checked := range plan.Check {
+		if _, err := c.Patch("/v1/blocks/"+id, map[string]interface{}{"to_do": map[string]interface{}{"checked": checked}}); err != nil {
+			return fmt.Errorf("update to-do: %w", err)
+		}
+	}
+	for _, id := range plan.Archive {
+		if _, err := c.Delete("/v1/blocks/" + id); err != nil {
+			return fmt.Errorf("archive to-do: %w", err)
+		}
+	}
+	if len(plan.Create) > 0 {
+		var children []map[string]interface{}
+		for _, l := range plan.Create {
+			block := notion.TextBlock("to_do", l.Text)
+			block["to_do"].(map[string]interface{})["checked"] = l.Checked
+			children = append(children, block)
+		}
+		if _, err := appendChildrenBatched(c, pageID, "", children); err != nil {
+			return fmt.Errorf("add to-dos: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyLocalTodoChanges returns the file's lines with the plan's local
+// changes made. New items go after the last checklist line.
+func applyLocalTodoChanges(lines []string, plan todoPlan) []string {
+	last := -1
+	for i, line := range lines {
+		if todoLineRe.MatchString(strings.TrimRight(line, "\r")) {
+			last = i
+		}
+	}
+	var out []string
+	if last < 0 && len(plan.Append) > 0 {
+		// No checklist yet: start one at the end, before a final newline.
+		for len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		out = append(out, lines...)
+		out = append(out, plan.Append...)
+		return append(out, "")
+	}
+	for i, line := range lines {
+		if repl, ok := plan.Replace[i]; ok {
+			if repl != "" {
+				out = append(out, repl)
+			}
+		} else {
+			out = append(out, line)
+		}
+		if i == last {
+			out = append(out, plan.Append...)
+		}
+	}
+	return out
+}
+
+func todoStatePath(pageID string) string {
+	return filepath.Join(config.CacheDir(), "todo-sync", strings.ReplaceAll(pageID, "-", "")+".json")
+}
+
+func loadTodoState(pageID string) map[string]syncedTodo {
+	state := map[string]syncedTodo{}
+	if data, err := os.ReadFile(todoStatePath(pageID)); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func saveTodoState(pageID string, todos []remoteTodo) error {
+	state := map[string]syncedTodo{}
+	for _, t := range todos {
+		state[t.ID] = syncedTodo{Text: t.Text, Checked: t.Checked}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := todoStatePath(pageID)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("save sync state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("save sync state: %w", err)
+	}
+	return nil
+}
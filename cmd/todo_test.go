@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanTodoSync(t *testing.T) {
+	lines := strings.Split(`# TODO
+- [x] ship it
+- [ ] write docs
+- [ ] old name
+- [ ] gone from notion
+- [ ] brand new
+  - [ ] nested lines are ignored
+`, "\n")
+	remote := []remoteTodo{
+		{ID: "b1", Text: "ship it", Checked: false},   // ticked in the file
+		{ID: "b2", Text: "write docs", Checked: true}, // ticked on the page
+		{ID: "b3", Text: "new name", Checked: false},  // renamed on the page
+		{ID: "b4", Text: "removed from file", Checked: false},
+		{ID: "b5", Text: "added on page", Checked: true},
+	}
+	base := map[string]syncedTodo{
+		"b1": {Text: "ship it"},
+		"b2": {Text: "write docs"},
+		"b3": {Text: "old name"},
+		"b4": {Text: "removed from file"},
+		"b9": {Text: "gone from notion"},
+	}
+
+	plan := planTodoSync(parseLocalTodos(lines), remote, base)
+
+	if len(plan.Check) != 1 || plan.Check["b1"] != true {
+		t.Errorf("Check = %v, want b1 checked", plan.Check)
+	}
+	if len(plan.Archive) != 1 || plan.Archive[0] != "b4" {
+		t.Errorf("Archive = %v, want [b4]", plan.Archive)
+	}
+	if len(plan.Create) != 1 || plan.Create[0].Text != "brand new" {
+		t.Errorf("Create = %+v, want [brand new]", plan.Create)
+	}
+
+	got := strings.Join(applyLocalTodoChanges(lines, plan), "\n")
+	want := `# TODO
+- [x] ship it
+- [x] write docs
+- [ ] new name
+- [ ] brand new
+- [x] added on page
+  - [ ] nested lines are ignored
+`
+	if got != want {
+		t.Errorf("file after sync:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPlanTodoSyncInSync(t *testing.T) {
+	lines := []string{"- [ ] a", "- [x] b"}
+	remote := []remoteTodo{{ID: "1", Text: "a"}, {ID: "2", Text: "b", Checked: true}}
+	if plan := planTodoSync(parseLocalTodos(lines), remote, nil); len(plan.Actions) != 0 {
+		t.Errorf("Actions = %+v, want none", plan.Actions)
+	}
+}
+
+func TestApplyLocalTodoChangesNewFile(t *testing.T) {
+	got := applyLocalTodoChanges(nil, todoPlan{Append: []string{"- [ ] first"}})
+	if strings.Join(got, "\n") != "- [ ] first\n" {
+		t.Errorf("new file = %q", strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,417 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/config"
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+// trackDateNames and trackDurationNames are the property names 'track'
+// looks for when --date or --duration isn't given.
+var (
+	trackDateNames     = []string{"Time", "Tracked", "When", "Date"}
+	trackDurationNames = []string{"Minutes", "Hours", "Duration", "Time spent"}
+)
+
+// trackState is the timers 'notion track' has running, by row ID.
+type trackState struct {
+	Timers map[string]runningTimer `json:"timers"`
+}
+
+type runningTimer struct {
+	Title    string    `json:"title"`
+	Property string    `json:"property"`
+	Start    time.Time `json:"start"`
+}
+
+var trackCmd = &cobra.Command{
+	Use:   "track",
+	Short: "Track time on database rows",
+	Long: `Use a database as a time log: 'track start' stamps the start time into a
+date property of a row, 'track stop' stamps the end (making it a date
+range) and can add the minutes to a number property and log the session
+on the page, and 'track report' sums the logged time per row or per tag.
+
+The date property is --date, else one called Time, Tracked, When or Date,
+else the row's only date property.`,
+}
+
+var trackStartCmd = &cobra.Command{
+	Use:   "start <row-id|url>",
+	Short: "Start a timer on a row",
+	Long: `Set the row's date property to start now, clearing any end.
+
+Examples:
+  notion track start abc123
+  notion track start abc123 --date "Work time"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dateFlag, _ := cmd.Flags().GetString("date")
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		rowID := util.ResolveID(args[0])
+
+		state := loadTrackState()
+		if t, ok := state.Timers[rowID]; ok {
+			return fmt.Errorf("a timer on %s is already running since %s; 'notion track stop' it first", t.Title, t.Start.Local().Format("15:04"))
+		}
+		page, err := c.GetPage(rowID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		props, _ := page["properties"].(map[string]interface{})
+		prop, err := dateProperty(props, dateFlag, "--date", trackDateNames...)
+		if err != nil {
+			return fmt.Errorf("%w on this row", err)
+		}
+
+		now := time.Now().Truncate(time.Second)
+		body := map[string]interface{}{"properties": map[string]interface{}{
+			prop: map[string]interface{}{"date": map[string]interface{}{"start": now.Format(time.RFC3339)}},
+		}}
+		if _, err := c.Patch("/v1/pages/"+rowID, body); err != nil {
+			return fmt.Errorf("start timer: %w", err)
+		}
+		timer := runningTimer{Title: firstNonEmpty(render.ExtractTitle(page), "Untitled"), Property: prop, Start: now}
+		state.Timers[rowID] = timer
+		if err := saveTrackState(state); err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"id": rowID, "title": timer.Title, "property": prop, "start": now.Format(time.RFC3339)})
+		}
+		fmt.Printf("⏱ Started %s at %s\n", timer.Title, now.Format("15:04"))
+		return nil
+	},
+}
+
+var trackStopCmd = &cobra.Command{
+	Use:   "stop [row-id|url]",
+	Short: "Stop a running timer",
+	Long: `Set the end of the row's date range to now. Without an argument, stops
+the only running timer.
+
+--duration adds the session to a number property (in hours when its name
+says so, else minutes); without it a property called Minutes, Hours,
+Duration or Time spent is used when there is one. --log also appends the
+session to the page as a line like "⏱ 09:00–10:30 (1h30m)".
+
+Examples:
+  notion track stop
+  notion track stop abc123 --duration Hours --log`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		durationFlag, _ := cmd.Flags().GetString("duration")
+		logSession, _ := cmd.Flags().GetBool("log")
+
+		state := loadTrackState()
+		rowID := ""
+		if len(args) == 1 {
+			rowID = util.ResolveID(args[0])
+		} else {
+			switch len(state.Timers) {
+			case 0:
+				return fmt.Errorf("no timer is running")
+			case 1:
+				for id := range state.Timers {
+					rowID = id
+				}
+			default:
+				return fmt.Errorf("%d timers are running; name the row to stop", len(state.Timers))
+			}
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		page, err := c.GetPage(rowID)
+		if err != nil {
+			return fmt.Errorf("get page: %w", err)
+		}
+		props, _ := page["properties"].(map[string]interface{})
+
+		// The row itself says when the timer started, so stopping works
+		// after the state file is lost or from another machine.
+		timer, running := state.Timers[rowID]
+		if !running {
+			prop, err := dateProperty(props, "", "--date", trackDateNames...)
+			if err != nil {
+				return fmt.Errorf("no timer is running on this row")
+			}
+			timer = runningTimer{Title: render.ExtractTitle(page), Property: prop}
+		}
+		value, _ := props[timer.Property].(map[string]interface{})
+		date, _ := value["date"].(map[string]interface{})
+		startText, _ := date["start"].(string)
+		start, err := time.Parse(time.RFC3339, startText)
+		if end, _ := date["end"].(string); err != nil || end != "" {
+			return fmt.Errorf("no timer is running on this row: %s is not an open start time", timer.Property)
+		}
+
+		now := time.Now().Truncate(time.Second)
+		if now.Before(start) {
+			now = start
+		}
+		elapsed := now.Sub(start)
+		update := map[string]interface{}{
+			timer.Property: map[string]interface{}{"date": map[string]interface{}{"start": startText, "end": now.Format(time.RFC3339)}},
+		}
+		durationProp := ""
+		if name := trackNumberProperty(props, durationFlag); name != "" {
+			durationProp = name
+			prop, _ := props[name].(map[string]interface{})
+			current, _ := prop["number"].(float64)
+			update[name] = map[string]interface{}{"number": current + trackAmount(name, elapsed)}
+		} else if durationFlag != "" {
+			return usageError(fmt.Errorf("--duration %s is not a number property of this row", durationFlag))
+		}
+		if _, err := c.Patch("/v1/pages/"+rowID, map[string]interface{}{"properties": update}); err != nil {
+			return fmt.Errorf("stop timer: %w", err)
+		}
+		if logSession {
+			line := fmt.Sprintf("⏱ %s–%s (%s)", start.Local().Format("2006-01-02 15:04"), now.Local().Format("15:04"), formatElapsed(elapsed))
+			if _, err := appendChildrenBatched(c, rowID, "", []map[string]interface{}{makeTextBlock("bulleted_list_item", line)}); err != nil {
+				return fmt.Errorf("log session: %w", err)
+			}
+		}
+		delete(state.Timers, rowID)
+		if err := saveTrackState(state); err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{
+				"id": rowID, "title": timer.Title, "start": startText, "end": now.Format(time.RFC3339),
+				"minutes": elapsed.Minutes(), "duration_property": durationProp,
+			})
+		}
+		fmt.Printf("✓ Stopped %s after %s\n", firstNonEmpty(timer.Title, "Untitled"), formatElapsed(elapsed))
+		return nil
+	},
+}
+
+var trackReportCmd = &cobra.Command{
+	Use:   "report <db-id|url>",
+	Short: "Sum tracked time per row or tag",
+	Long: `Add up the date ranges of a database's rows that start within --since,
+per row, or per value of --by (a select, multi-select, status or people
+property; a row with several tags counts for each). Rows whose range has
+no end — running timers — are left out.
+
+Examples:
+  notion track report abc123 --since 7d
+  notion track report abc123 --since 2026-03-01 --by Project --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		dateFlag, _ := cmd.Flags().GetString("date")
+		by, _ := cmd.Flags().GetString("by")
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			return err
+		}
+
+		token, err := getToken()
+		if err != nil {
+			return err
+		}
+		c := newClient(token)
+		dbID := util.ResolveID(args[0])
+		db, err := c.GetDatabase(dbID)
+		if err != nil {
+			return fmt.Errorf("get database schema: %w", err)
+		}
+		schema, _ := db["properties"].(map[string]interface{})
+		prop, err := dateProperty(schema, dateFlag, "--date", trackDateNames...)
+		if err != nil {
+			return fmt.Errorf("%w in the database", err)
+		}
+		if by != "" {
+			names, _, err := resolveColumns(schema, []string{by})
+			if err != nil {
+				return err
+			}
+			by = names[0]
+		}
+
+		rows, err := c.QueryDatabaseAll(dbID, map[string]interface{}{
+			"filter": map[string]interface{}{"property": prop, "date": map[string]interface{}{"on_or_after": since.Format(time.RFC3339)}},
+		})
+		if err != nil {
+			return fmt.Errorf("query database: %w", err)
+		}
+		report := trackReport(rows, prop, by)
+
+		if outputFormat == "json" {
+			return render.JSON(map[string]interface{}{"since": since.Format(time.RFC3339), "by": by, "total_minutes": report.total.Minutes(), "entries": report.entries})
+		}
+		if len(report.entries) == 0 {
+			fmt.Printf("No time tracked since %s.\n", since.Local().Format("2006-01-02"))
+			return nil
+		}
+		label := "ROW"
+		if by != "" {
+			label = strings.ToUpper(by)
+		}
+		var table [][]string
+		for _, e := range report.entries {
+			table = append(table, []string{e.Name, formatElapsed(time.Duration(e.Minutes * float64(time.Minute))), fmt.Sprintf("%d", e.Sessions)})
+		}
+		render.Table([]string{label, "TIME", "SESSIONS"}, table)
+		fmt.Printf("\nTotal: %s since %s\n", formatElapsed(report.total), since.Local().Format("2006-01-02"))
+		return nil
+	},
+}
+
+func init() {
+	trackStartCmd.Flags().String("date", "", "Date property to write the start time to")
+	trackStopCmd.Flags().String("duration", "", "Number property to add the session's minutes (or hours) to")
+	trackStopCmd.Flags().Bool("log", false, "Append the session to the page")
+	trackReportCmd.Flags().String("since", "7d", "Only sessions starting after this (7d, 2026-03-01)")
+	trackReportCmd.Flags().String("date", "", "Date property holding the sessions")
+	trackReportCmd.Flags().String("by", "", "Group by this property instead of by row")
+	trackCmd.AddCommand(trackStartCmd)
+	trackCmd.AddCommand(trackStopCmd)
+	trackCmd.AddCommand(trackReportCmd)
+}
+
+// trackEntry is one line of 'track report'.
+type trackEntry struct {
+	Name     string  `json:"name"`
+	Minutes  float64 `json:"minutes"`
+	Sessions int     `json:"sessions"`
+}
+
+type trackSummary struct {
+	entries []*trackEntry
+	total   time.Duration
+}
+
+// trackReport sums the closed date ranges of rows in prop, per row or per
+// value of the property by, most time first.
+func trackReport(rows []interface{}, prop, by string) trackSummary {
+	var s trackSummary
+	byName := map[string]*trackEntry{}
+	for _, r := range rows {
+		row, _ := r.(map[string]interface{})
+		props, _ := row["properties"].(map[string]interface{})
+		value, _ := props[prop].(map[string]interface{})
+		date, _ := value["date"].(map[string]interface{})
+		startText, _ := date["start"].(string)
+		endText, _ := date["end"].(string)
+		start, err1 := time.Parse(time.RFC3339, startText)
+		end, err2 := time.Parse(time.RFC3339, endText)
+		if err1 != nil || err2 != nil || end.Before(start) {
+			continue
+		}
+		elapsed := end.Sub(start)
+		s.total += elapsed
+
+		names := []string{firstNonEmpty(render.ExtractTitle(row), "Untitled")}
+		if by != "" {
+			group, _ := props[by].(map[string]interface{})
+			names = nil
+			for _, n := range strings.Split(notion.PropertyText(group), ",") {
+				if n = strings.TrimSpace(n); n != "" {
+					names = append(names, n)
+				}
+			}
+			if len(names) == 0 {
+				names = []string{"(none)"}
+			}
+		}
+		for _, n := range names {
+			e := byName[n]
+			if e == nil {
+				e = &trackEntry{Name: n}
+				byName[n] = e
+				s.entries = append(s.entries, e)
+			}
+			e.Minutes += elapsed.Minutes()
+			e.Sessions++
+		}
+	}
+	sort.SliceStable(s.entries, func(i, j int) bool { return s.entries[i].Minutes > s.entries[j].Minutes })
+	return s
+}
+
+// trackNumberProperty returns the number property to add durations to:
+// name if given and a number, else one with a well-known name.
+func trackNumberProperty(props map[string]interface{}, name string) string {
+	candidates := trackDurationNames
+	if name != "" {
+		candidates = []string{name}
+	}
+	for _, want := range candidates {
+		for _, n := range sortedKeys(props) {
+			if prop, _ := props[n].(map[string]interface{}); strings.EqualFold(n, want) && prop["type"] == "number" {
+				return n
+			}
+		}
+	}
+	return ""
+}
+
+// trackAmount is elapsed in the unit a number property's name suggests:
+// hours when it mentions hours, else minutes, rounded to two decimals.
+func trackAmount(property string, elapsed time.Duration) float64 {
+	v := elapsed.Minutes()
+	if strings.Contains(strings.ToLower(property), "hour") {
+		v = elapsed.Hours()
+	}
+	return float64(int64(v*100+0.5)) / 100
+}
+
+// formatElapsed shows a duration to the minute: "45m", "2h05m".
+func formatElapsed(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
+
+func trackStatePath() string {
+	return filepath.Join(config.CacheDir(), "track.json")
+}
+
+func loadTrackState() trackState {
+	var state trackState
+	if data, err := os.ReadFile(trackStatePath()); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	if state.Timers == nil {
+		state.Timers = map[string]runningTimer{}
+	}
+	return state
+}
+
+func saveTrackState(state trackState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.CacheDir(), 0700); err != nil {
+		return fmt.Errorf("save timers: %w", err)
+	}
+	if err := os.WriteFile(trackStatePath(), data, 0600); err != nil {
+		return fmt.Errorf("save timers: %w", err)
+	}
+	return nil
+}
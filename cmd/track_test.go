@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatElapsed(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Minute:                "45m",
+		90*time.Minute + 20*time.Second: "1h30m",
+		2*time.Hour + 5*time.Minute:     "2h05m",
+		29 * time.Second:                "0m",
+	}
+	for d, want := range tests {
+		if got := formatElapsed(d); got != want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestTrackReport(t *testing.T) {
+	row := func(title, start, end string, tags ...string) interface{} {
+		var options []interface{}
+		for _, tag := range tags {
+			options = append(options, map[string]interface{}{"name": tag})
+		}
+		return map[string]interface{}{"properties": map[string]interface{}{
+			"Name":    map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": title}}},
+			"When":    map[string]interface{}{"type": "date", "date": map[string]interface{}{"start": start, "end": end}},
+			"Project": map[string]interface{}{"type": "multi_select", "multi_select": options},
+		}}
+	}
+	rows := []interface{}{
+		row("Review", "2026-03-02T09:00:00Z", "2026-03-02T09:30:00Z", "Alpha"),
+		row("Build", "2026-03-02T10:00:00Z", "2026-03-02T12:00:00Z", "Alpha", "Beta"),
+		row("Running", "2026-03-02T13:00:00Z", ""),
+	}
+
+	byRow := trackReport(rows, "When", "")
+	if byRow.total != 150*time.Minute || len(byRow.entries) != 2 || byRow.entries[0].Name != "Build" {
+		t.Errorf("by row: total %v, entries %+v", byRow.total, byRow.entries)
+	}
+	byTag := trackReport(rows, "When", "Project")
+	if len(byTag.entries) != 2 || byTag.entries[0].Name != "Alpha" || byTag.entries[0].Minutes != 150 || byTag.entries[0].Sessions != 2 ||
+		byTag.entries[1].Name != "Beta" || byTag.entries[1].Minutes != 120 {
+		t.Errorf("by tag: %+v %+v", byTag.entries[0], byTag.entries[1])
+	}
+}
+
+func TestTrackStartStop(t *testing.T) {
+	const rowID = "11111111-1111-1111-1111-111111111111"
+	date := map[string]interface{}{}
+	var writes []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == "GET" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": rowID, "properties": map[string]interface{}{
+				"Name":    map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Write docs"}}},
+				"When":    map[string]interface{}{"type": "date", "date": date},
+				"Due":     map[string]interface{}{"type": "date", "date": nil},
+				"Minutes": map[string]interface{}{"type": "number", "number": 10},
+			}})
+			return
+		}
+		writes = append(writes, r.Method+" "+r.URL.Path)
+		var update struct {
+			Properties map[string]map[string]interface{} `json:"properties"`
+		}
+		json.Unmarshal(body, &update)
+		if d, ok := update.Properties["When"]["date"].(map[string]interface{}); ok {
+			date = d
+		}
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("track", "start", rowID); err != nil {
+			t.Fatalf("track start: %v", err)
+		}
+	})
+	if date["start"] == nil || date["end"] != nil {
+		t.Fatalf("start wrote %v", date)
+	}
+	if _, _, err := executeCommand("track", "start", rowID); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("second start: err = %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("track", "stop", "--log"); err != nil {
+			t.Fatalf("track stop: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Stopped Write docs after 0m") || date["end"] == nil {
+		t.Errorf("stop: %s, date %v", out, date)
+	}
+	want := []string{"PATCH /v1/pages/" + rowID, "PATCH /v1/pages/" + rowID, "PATCH /v1/blocks/" + rowID + "/children"}
+	if strings.Join(writes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("writes = %v, want %v", writes, want)
+	}
+	if len(loadTrackState().Timers) != 0 {
+		t.Error("the timer should be cleared")
+	}
+	if _, _, err := executeCommand("track", "stop", "--log=false"); err == nil || !strings.Contains(err.Error(), "no timer") {
+		t.Errorf("stop with no timer: err = %v", err)
+	}
+}
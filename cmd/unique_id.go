@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/4ier/notion-cli/internal/util"
+	"github.com/4ier/notion-cli/pkg/notion"
+)
+
+// uniqueIDRe matches a unique ID as Notion shows it: an optional prefix
+// and a dash, then the number (TASK-123, or just 123).
+var uniqueIDRe = regexp.MustCompile(`^(?:([A-Za-z][A-Za-z0-9_]*)-)?(\d+)$`)
+
+// parseUniqueID splits a unique ID into its prefix and number.
+func parseUniqueID(s string) (prefix string, n int64, ok bool) {
+	m := uniqueIDRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return "", 0, false
+	}
+	n, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return m[1], n, true
+}
+
+// resolveRowID turns a row argument into a page ID. With a database, a
+// unique ID such as TASK-123 is looked up with a query on the database's
+// unique_id property; anything else is taken as a page ID or URL.
+func resolveRowID(c *notion.Client, arg, dbArg string) (string, error) {
+	prefix, n, ok := parseUniqueID(arg)
+	if !ok || util.IsID(arg) {
+		return util.ResolveID(arg), nil
+	}
+	if dbArg == "" {
+		if prefix != "" {
+			return "", usageError(fmt.Errorf("%s looks like a unique ID; pass --db with the database it belongs to", arg))
+		}
+		return util.ResolveID(arg), nil
+	}
+
+	dbID := util.ResolveID(dbArg)
+	db, err := c.GetDatabase(dbID)
+	if err != nil {
+		return "", fmt.Errorf("get database: %w", err)
+	}
+	dbProps, _ := db["properties"].(map[string]interface{})
+	propName := ""
+	for name, v := range dbProps {
+		prop, _ := v.(map[string]interface{})
+		if prop["type"] != "unique_id" {
+			continue
+		}
+		def, _ := prop["unique_id"].(map[string]interface{})
+		if p, _ := def["prefix"].(string); prefix == "" || strings.EqualFold(p, prefix) {
+			propName = name
+			break
+		}
+	}
+	if propName == "" {
+		return "", fmt.Errorf("database %s has no unique ID property matching %s", dbID, arg)
+	}
+
+	result, err := c.QueryDatabase(dbID, map[string]interface{}{
+		"filter": map[string]interface{}{
+			"property":  propName,
+			"unique_id": map[string]interface{}{"equals": n},
+		},
+		"page_size": 1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("look up %s: %w", arg, err)
+	}
+	results, _ := result["results"].([]interface{})
+	if len(results) == 0 {
+		return "", fmt.Errorf("no row %s in database %s", arg, dbID)
+	}
+	row, _ := results[0].(map[string]interface{})
+	id, _ := row["id"].(string)
+	return id, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUniqueIDFilter(t *testing.T) {
+	dbProps := map[string]interface{}{"ID": map[string]interface{}{"type": "unique_id"}}
+	for expr, want := range map[string]map[string]interface{}{
+		"ID=TASK-123": {"equals": int64(123)},
+		"ID>=40":      {"greater_than_or_equal_to": int64(40)},
+	} {
+		got, err := parseFilter(expr, dbProps)
+		if err != nil {
+			t.Fatalf("%s: %v", expr, err)
+		}
+		if !reflect.DeepEqual(got["unique_id"], want) {
+			t.Errorf("%s: unique_id = %v, want %v", expr, got["unique_id"], want)
+		}
+	}
+	if sqlCompareValues("TASK-9", "TASK-10", "unique_id") >= 0 {
+		t.Error("TASK-9 should sort before TASK-10")
+	}
+}
+
+func TestUniqueIDRowSelector(t *testing.T) {
+	const dbID = "11111111-1111-1111-1111-111111111111"
+	const rowID = "22222222-2222-2222-2222-222222222222"
+	var query string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			query = string(body)
+			w.Write([]byte(`{"results":[{"object":"page","id":"` + rowID + `"}],"has_more":false}`))
+		case strings.HasPrefix(r.URL.Path, "/v1/databases/"):
+			w.Write([]byte(`{"object":"database","id":"` + dbID + `","properties":{
+				"Name":{"type":"title","title":{}},
+				"ID":{"type":"unique_id","unique_id":{"prefix":"TASK"}}}}`))
+		case r.URL.Path == "/v1/pages/"+rowID:
+			w.Write([]byte(`{"object":"page","id":"` + rowID + `","properties":{
+				"Name":{"id":"title","type":"title","title":[{"plain_text":"Ship it"}]},
+				"ID":{"id":"x","type":"unique_id","unique_id":{"prefix":"TASK","number":123}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"object":"error","status":404,"code":"object_not_found","message":"not found"}`))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	if _, _, err := executeCommand("page", "props", "TASK-123"); err == nil || !strings.Contains(err.Error(), "--db") {
+		t.Errorf("without --db: err = %v", err)
+	}
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "props", "TASK-123", "--db", dbID); err != nil {
+			t.Fatalf("page props: %v", err)
+		}
+	})
+	if !strings.Contains(query, `"unique_id":{"equals":123}`) {
+		t.Errorf("query = %s", query)
+	}
+	if !strings.Contains(out, "TASK-123") {
+		t.Errorf("unique ID not rendered:\n%s", out)
+	}
+	// Flags persist between executeCommand calls.
+	executeCommand("page", "props", rowID, "--db", "")
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/4ier/notion-cli/internal/client"
 	"github.com/4ier/notion-cli/internal/render"
 	"github.com/spf13/cobra"
 )
@@ -22,8 +21,7 @@ var userMeCmd = &cobra.Command{
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		me, err := c.GetMe()
 		if err != nil {
@@ -57,15 +55,19 @@ var userListCmd = &cobra.Command{
 
 		all, _ := cmd.Flags().GetBool("all")
 		cursor, _ := cmd.Flags().GetString("cursor")
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		var allResults []interface{}
 		currentCursor := cursor
+		var truncated error
 
 		for {
 			result, err := c.GetUsers(100, currentCursor)
 			if err != nil {
+				if overBudget(err, allResults) {
+					truncated = err
+					break
+				}
 				return err
 			}
 
@@ -87,6 +89,15 @@ var userListCmd = &cobra.Command{
 			currentCursor = nextCursor
 		}
 
+		if truncated != nil && outputFormat == "json" {
+			return renderTruncated(allResults, currentCursor, truncated)
+		}
+
+		if porcelain != "" {
+			porcelainUsers(allResults)
+			return truncated
+		}
+
 		headers := []string{"NAME", "TYPE", "ID"}
 		var rows [][]string
 
@@ -107,7 +118,7 @@ var userListCmd = &cobra.Command{
 		}
 
 		render.Table(headers, rows)
-		return nil
+		return truncated
 	},
 }
 
@@ -121,8 +132,7 @@ var userGetCmd = &cobra.Command{
 			return err
 		}
 
-		c := client.New(token)
-		c.SetDebug(debugMode)
+		c := newClient(token)
 
 		user, err := c.GetUser(args[0])
 		if err != nil {
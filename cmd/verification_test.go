@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func wikiPage(id, state, end string) map[string]interface{} {
+	v := map[string]interface{}{"state": state}
+	if end != "" {
+		v["date"] = map[string]interface{}{"start": "2026-01-01", "end": end}
+	}
+	return map[string]interface{}{"object": "page", "id": id, "properties": map[string]interface{}{
+		"Page":         map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Page " + id}}},
+		"Verification": map[string]interface{}{"type": "verification", "verification": v},
+	}}
+}
+
+func TestVerificationState(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		state, end, want string
+	}{
+		{"verified", "", "verified"},
+		{"verified", "2026-12-31", "verified"},
+		{"verified", "2026-10-01", "expired"},
+		{"unverified", "", "unverified"},
+	} {
+		_, prop := pageVerification(wikiPage("x", tc.state, tc.end))
+		if got := verificationState(prop, now); got != tc.want {
+			t.Errorf("%s until %q = %s, want %s", tc.state, tc.end, got, tc.want)
+		}
+	}
+	if name, _ := pageVerification(map[string]interface{}{"properties": map[string]interface{}{}}); name != "" {
+		t.Errorf("page outside a wiki has verification %q", name)
+	}
+}
+
+func TestPageVerify(t *testing.T) {
+	var patched map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			json.NewDecoder(r.Body).Decode(&patched)
+		}
+		json.NewEncoder(w).Encode(wikiPage("11111111-1111-1111-1111-111111111111", "unverified", ""))
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "verify", "11111111111111111111111111111111", "--until", "2026-12-31"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	got, _ := json.Marshal(patched)
+	if !strings.Contains(string(got), `"Verification":{"verification":{"date":{`) || !strings.Contains(string(got), `"end":"2026-12-31"`) || !strings.Contains(string(got), `"state":"verified"`) {
+		t.Errorf("patch = %s", got)
+	}
+	if !strings.Contains(out, "verified until 2026-12-31") {
+		t.Errorf("output = %q", out)
+	}
+	if _, _, err := executeCommand("page", "verify", "11111111111111111111111111111111", "--until", "soon"); err == nil {
+		t.Error("invalid --until accepted")
+	}
+}
+
+func TestSearchVerification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if filter, _ := body["filter"].(map[string]interface{}); filter["value"] != "page" {
+			t.Errorf("search filter = %v", body["filter"])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{
+			wikiPage("a", "verified", "2099-01-01"),
+			wikiPage("b", "verified", "2020-01-01"),
+			wikiPage("c", "unverified", ""),
+			map[string]interface{}{"object": "page", "id": "d", "properties": map[string]interface{}{}},
+		}})
+	}))
+	defer server.Close()
+	t.Setenv("NOTION_BASE_URL", server.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	defer func() { outputFormat = "" }()
+
+	out := captureStdout(t, func() {
+		if _, _, err := executeCommand("search", "--verification", "expired", "--format", "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var result struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	json.Unmarshal([]byte(out), &result)
+	if len(result.Results) != 1 || result.Results[0]["id"] != "b" {
+		t.Errorf("results = %v", result.Results)
+	}
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/render"
+	"github.com/4ier/notion-cli/pkg/notion"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check backups against Notion",
+}
+
+var verifyBackupCmd = &cobra.Command{
+	Use:   "backup <dir|backup.zip>",
+	Short: "Check a page export is complete and matches Notion",
+	Long: `Check a backup made with 'notion page export', as its directory or a zip
+of it, in two steps:
+
+  1. Offline: the .notion-export.json manifest is read, every page it
+     lists must have its file, with the SHA-256 recorded at export time,
+     and every sub-page a page lists must be in the backup too.
+  2. Online: --sample pages (5 by default, 0 to skip) are picked at
+     random, fetched again and rendered the way the export did; the
+     result must hash the same as the file. Pages edited in Notion since
+     the backup are reported, not counted as failures.
+
+The command fails when any check does, so it can guard a backup job.
+
+Examples:
+  notion verify backup ./backup
+  notion verify backup backup-2026-03-01.zip --sample 20
+  notion verify backup ./backup --sample 0 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sample, _ := cmd.Flags().GetInt("sample")
+		if sample < 0 {
+			return usageError(fmt.Errorf("--sample must be 0 or more"))
+		}
+		fsys, err := openBackup(args[0])
+		if err != nil {
+			return err
+		}
+		manifest, err := readBackupManifest(fsys)
+		if err != nil {
+			return err
+		}
+		report := checkBackupFiles(fsys, manifest)
+
+		if sample > 0 && len(manifest.Pages) > 0 {
+			token, err := getToken()
+			if err != nil {
+				return err
+			}
+			c := newClient(token)
+			picked := rand.Perm(len(manifest.Pages))
+			if sample < len(picked) {
+				picked = picked[:sample]
+			}
+			sort.Ints(picked)
+			for _, i := range picked {
+				check, err := refetchBackupPage(c, fsys, manifest, manifest.Pages[i])
+				if err != nil {
+					return err
+				}
+				report.Sampled = append(report.Sampled, check)
+				if check.Status == "mismatch" {
+					report.Problems = append(report.Problems, fmt.Sprintf("%s differs from the page in Notion, which wasn't edited since the backup", check.Path))
+				}
+			}
+		}
+
+		if outputFormat == "json" {
+			if report.Problems == nil {
+				report.Problems = []string{}
+			}
+			report.OK = len(report.Problems) == 0
+			if err := render.JSON(report); err != nil {
+				return err
+			}
+		} else {
+			printBackupReport(report)
+		}
+		if len(report.Problems) > 0 {
+			return fmt.Errorf("backup verification failed: %d problem(s)", len(report.Problems))
+		}
+		return nil
+	},
+}
+
+func init() {
+	verifyBackupCmd.Flags().Int("sample", 5, "Pages to fetch again and compare (0 = offline checks only)")
+	verifyCmd.AddCommand(verifyBackupCmd)
+}
+
+// backupReport is the result of 'verify backup'.
+type backupReport struct {
+	OK       bool          `json:"ok"`
+	Root     string        `json:"root"`
+	LastRun  string        `json:"last_run"`
+	Pages    int           `json:"pages"`
+	Verified int           `json:"verified"` // files present with the recorded checksum
+	Problems []string      `json:"problems"`
+	Sampled  []sampleCheck `json:"sampled,omitempty"`
+}
+
+// sampleCheck is a page of the backup compared with Notion.
+type sampleCheck struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	// Status is match, mismatch, edited (since the backup) or gone (no
+	// longer in Notion, or no longer shared).
+	Status string `json:"status"`
+}
+
+// openBackup opens a backup directory or zip archive.
+func openBackup(source string) (fs.FS, error) {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", source, err)
+	}
+	if fi.IsDir() {
+		return os.DirFS(source), nil
+	}
+	zr, err := zip.OpenReader(source)
+	if err != nil {
+		return nil, invalidInput(fmt.Errorf("%s is neither a directory nor a zip archive: %w", source, err))
+	}
+	return zr, nil
+}
+
+// readBackupManifest reads the export manifest at the root of the backup,
+// or in its only top-level directory, as zipping a directory leaves it.
+func readBackupManifest(fsys fs.FS) (*backupManifest, error) {
+	dir := "."
+	if _, err := fs.Stat(fsys, exportManifestName); err != nil {
+		matches, _ := fs.Glob(fsys, "*/"+exportManifestName)
+		if len(matches) != 1 {
+			return nil, invalidInput(fmt.Errorf("no %s found: is this a 'notion page export' directory?", exportManifestName))
+		}
+		dir = path.Dir(matches[0])
+	}
+	data, err := fs.ReadFile(fsys, path.Join(dir, exportManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var m exportManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, invalidInput(fmt.Errorf("invalid manifest: %w", err))
+	}
+	return &backupManifest{exportManifest: m, dir: dir}, nil
+}
+
+// backupManifest is the manifest of a backup and the directory of the
+// backup it was found in.
+type backupManifest struct {
+	exportManifest
+	dir string
+}
+
+func (b *backupManifest) file(p string) string {
+	return path.Join(b.dir, p)
+}
+
+// checkBackupFiles runs the offline checks: the manifest is consistent and
+// every file is there with its recorded checksum.
+func checkBackupFiles(fsys fs.FS, m *backupManifest) *backupReport {
+	report := &backupReport{Root: m.Root, LastRun: m.LastRun.Format(time.RFC3339), Pages: len(m.Pages)}
+	problem := func(format string, a ...interface{}) {
+		report.Problems = append(report.Problems, fmt.Sprintf(format, a...))
+	}
+	if m.Root == "" || len(m.Pages) == 0 {
+		problem("the manifest lists no pages")
+		return report
+	}
+	ids := map[string]bool{}
+	paths := map[string]bool{}
+	for _, p := range m.Pages {
+		ids[plainID(p.ID)] = true
+		if paths[p.Path] {
+			problem("%s is listed twice", p.Path)
+		}
+		paths[p.Path] = true
+	}
+	if !ids[plainID(m.Root)] {
+		problem("the root page %s is missing from the manifest", m.Root)
+	}
+	for _, p := range m.Pages {
+		for _, child := range p.Children {
+			if !ids[plainID(child.ID)] {
+				problem("%s: sub-page %q (%s) is missing", p.Path, firstNonEmpty(child.Title, "Untitled"), child.ID)
+			}
+		}
+		data, err := fs.ReadFile(fsys, m.file(p.Path))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			problem("%s is missing", p.Path)
+		case err != nil:
+			problem("%s: %v", p.Path, err)
+		case p.SHA256 == "":
+			problem("%s has no checksum in the manifest; export again to record one", p.Path)
+		case contentSum(data) != p.SHA256:
+			problem("%s doesn't match its checksum", p.Path)
+		default:
+			report.Verified++
+		}
+	}
+	return report
+}
+
+// refetchBackupPage fetches page p again and renders it as the export did,
+// with the rest of the backup as link targets, to compare with its file.
+func refetchBackupPage(c *notion.Client, fsys fs.FS, m *backupManifest, p manifestPage) (sampleCheck, error) {
+	check := sampleCheck{ID: p.ID, Path: p.Path}
+	page, err := c.GetPage(p.ID)
+	if errors.Is(err, notion.ErrNotFound) {
+		check.Status = "gone"
+		return check, nil
+	}
+	if err != nil {
+		return check, fmt.Errorf("get page %s: %w", p.Path, err)
+	}
+	if edited, _ := page["last_edited_time"].(string); edited != p.Edited {
+		check.Status = "edited"
+		return check, nil
+	}
+	blocks, err := fetchExportBlocks(c, p.ID)
+	if err != nil {
+		return check, fmt.Errorf("get content of %s: %w", p.Path, err)
+	}
+
+	e := newPageExporter(strings.HasSuffix(p.Path, ".html"))
+	for _, other := range m.Pages {
+		if other.ID != p.ID {
+			e.addPage(&exportPage{ID: other.ID, Title: other.Title, Path: other.Path, Unchanged: true}, other.Anchors)
+		}
+	}
+	fresh := &exportPage{ID: p.ID, Title: p.Title, Path: p.Path, blocks: blocks}
+	e.addPage(fresh, nil)
+	data, _ := fs.ReadFile(fsys, m.file(p.Path))
+	if contentSum(e.render(fresh)) == contentSum(data) {
+		check.Status = "match"
+	} else {
+		check.Status = "mismatch"
+	}
+	return check, nil
+}
+
+func printBackupReport(r *backupReport) {
+	fmt.Printf("Backup of %s, exported %s\n", r.Root, r.LastRun)
+	fmt.Printf("  %d of %d file(s) present with matching checksums\n", r.Verified, r.Pages)
+	if len(r.Sampled) > 0 {
+		counts := map[string]int{}
+		for _, s := range r.Sampled {
+			counts[s.Status]++
+		}
+		fmt.Printf("  %d page(s) fetched again: %d match", len(r.Sampled), counts["match"])
+		for _, status := range []string{"mismatch", "edited", "gone"} {
+			if counts[status] > 0 {
+				fmt.Printf(", %d %s", counts[status], status)
+			}
+		}
+		fmt.Println()
+	}
+	for _, p := range r.Problems {
+		fmt.Printf("  ✗ %s\n", p)
+	}
+	if len(r.Problems) == 0 {
+		fmt.Println("✓ Backup verified")
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	const (
+		rootID  = "11111111-1111-1111-1111-111111111111"
+		childID = "22222222-2222-2222-2222-222222222222"
+		edited  = "2026-03-01T10:00:00.000Z"
+	)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "/children") {
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+			results := []interface{}{
+				map[string]interface{}{"id": "h1", "type": "heading_1", "heading_1": map[string]interface{}{"rich_text": []interface{}{map[string]interface{}{"plain_text": "Intro"}}}},
+				map[string]interface{}{"id": childID, "type": "child_page", "last_edited_time": edited, "child_page": map[string]interface{}{"title": "Child"}},
+			}
+			if id == childID {
+				results = []interface{}{map[string]interface{}{"id": "c1", "type": "paragraph", "paragraph": map[string]interface{}{"rich_text": []interface{}{
+					map[string]interface{}{"plain_text": "back", "href": "/" + plainID(rootID) + "#h1"},
+				}}}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/v1/pages/")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": id, "last_edited_time": edited, "properties": map[string]interface{}{
+			"title": map[string]interface{}{"type": "title", "title": []interface{}{map[string]interface{}{"plain_text": "Root"}}},
+		}})
+	}))
+	defer api.Close()
+	t.Setenv("NOTION_BASE_URL", api.URL)
+	t.Setenv("NOTION_TOKEN", "secret_test")
+	dir := t.TempDir()
+	defer func() { outputFormat = "" }()
+
+	captureStdout(t, func() {
+		if _, _, err := executeCommand("page", "export", rootID, "--dir", dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	verify := func(args ...string) (backupReport, error) {
+		var report backupReport
+		var err error
+		out := captureStdout(t, func() {
+			_, _, err = executeCommand(append([]string{"verify", "backup", "--format", "json"}, args...)...)
+		})
+		if jerr := json.Unmarshal([]byte(out), &report); jerr != nil {
+			t.Fatalf("output %q: %v", out, jerr)
+		}
+		return report, err
+	}
+
+	report, err := verify(dir, "--sample", "10")
+	if err != nil || !report.OK || report.Verified != 2 || len(report.Sampled) != 2 {
+		t.Fatalf("report = %+v, %v", report, err)
+	}
+	for _, s := range report.Sampled {
+		if s.Status != "match" {
+			t.Errorf("sampled %s: %s", s.Path, s.Status)
+		}
+	}
+
+	// A zip of the directory, as archived by a backup job.
+	archive := filepath.Join(t.TempDir(), "backup.zip")
+	f, _ := os.Create(archive)
+	zw := zip.NewWriter(f)
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if info.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		w, _ := zw.Create("backup/" + filepath.ToSlash(rel))
+		data, _ := os.ReadFile(p)
+		w.Write(data)
+		return nil
+	})
+	zw.Close()
+	f.Close()
+	if report, err := verify(archive, "--sample", "0"); err != nil || !report.OK || report.Verified != 2 {
+		t.Errorf("zip report = %+v, %v", report, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "Root", "Child.md"), []byte("tampered"), 0o644)
+	report, err = verify(dir, "--sample", "0")
+	if err == nil || report.OK || len(report.Problems) != 1 || !strings.Contains(report.Problems[0], "Root/Child.md") {
+		t.Errorf("tampered report = %+v, %v", report, err)
+	}
+}
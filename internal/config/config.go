@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +14,8 @@ type Profile struct {
 	WorkspaceName string `json:"workspace_name,omitempty"`
 	WorkspaceID   string `json:"workspace_id,omitempty"`
 	BotID         string `json:"bot_id,omitempty"`
+	// ReadOnly profiles may not change the workspace
+	ReadOnly bool `json:"read_only,omitempty"`
 }
 
 // Config holds the CLI configuration with support for multiple profiles.
@@ -21,6 +24,13 @@ type Config struct {
 	CurrentProfile string `json:"current_profile,omitempty"`
 	// Profiles maps profile names to their configuration
 	Profiles map[string]*Profile `json:"profiles,omitempty"`
+	// Aliases maps short names to page/database IDs or URLs
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Settings holds user preferences set with 'notion config set'
+	Settings map[string]string `json:"settings,omitempty"`
+	// Queries maps database IDs (without dashes) to the queries saved for
+	// them by name with 'db query --save-as'
+	Queries map[string]map[string]*SavedQuery `json:"queries,omitempty"`
 
 	// Legacy fields for backward compatibility
 	Token         string `json:"token,omitempty"`
@@ -29,19 +39,59 @@ type Config struct {
 	BotID         string `json:"bot_id,omitempty"`
 }
 
+// SavedQuery is a combination of 'db query' filters, sorts and columns
+// saved under a name.
+type SavedQuery struct {
+	Filters    []string `json:"filters,omitempty"`
+	FilterJSON string   `json:"filter_json,omitempty"`
+	Sorts      []string `json:"sorts,omitempty"`
+	Columns    []string `json:"columns,omitempty"`
+	View       string   `json:"view,omitempty"`
+}
+
+// ErrDisabled is returned by Load and Save while the config file is
+// disabled.
+var ErrDisabled = errors.New("the config file is disabled (--no-config)")
+
+// disabled keeps Load and Save away from config.json.
+var disabled bool
+
+// SetDisabled makes Load return an empty config and Save fail, so nothing
+// reads or writes config.json: for CI jobs set up by the environment alone.
+func SetDisabled(off bool) {
+	disabled = off
+}
+
+// IsDisabled reports whether the config file is disabled.
+func IsDisabled() bool {
+	return disabled
+}
+
+// ActiveProfile returns the name of the profile in use: NOTION_PROFILE,
+// else the current profile, else "default".
+func (c *Config) ActiveProfile() string {
+	if name := os.Getenv("NOTION_PROFILE"); name != "" {
+		return name
+	}
+	if c.CurrentProfile == "" {
+		return "default"
+	}
+	return c.CurrentProfile
+}
+
 // GetCurrentProfile returns the current profile configuration.
 // It handles migration from legacy single-token format.
 func (c *Config) GetCurrentProfile() *Profile {
 	// If we have profiles, use the current one
 	if len(c.Profiles) > 0 {
-		profileName := c.CurrentProfile
-		if profileName == "" {
-			profileName = "default"
-		}
-		if p, ok := c.Profiles[profileName]; ok {
+		if p, ok := c.Profiles[c.ActiveProfile()]; ok {
 			return p
 		}
 	}
+	// A profile picked by NOTION_PROFILE must exist
+	if name := os.Getenv("NOTION_PROFILE"); name != "" && name != "default" {
+		return nil
+	}
 
 	// Fall back to legacy format
 	if c.Token != "" {
@@ -56,6 +106,11 @@ func (c *Config) GetCurrentProfile() *Profile {
 	return nil
 }
 
+// Setting returns the value of a user setting, or "" when unset.
+func (c *Config) Setting(key string) string {
+	return c.Settings[key]
+}
+
 // SetProfile sets or updates a profile in the config.
 func (c *Config) SetProfile(name string, profile *Profile) {
 	if c.Profiles == nil {
@@ -114,7 +169,31 @@ func configPath() string {
 	return filepath.Join(configDir(), "config.json")
 }
 
+// Dir returns the directory holding config.json and other CLI state.
+func Dir() string {
+	return configDir()
+}
+
+// Path returns the location of config.json.
+func Path() string {
+	return configPath()
+}
+
+// CacheDir returns the directory for disposable cached data.
+func CacheDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "notion-cli")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "notion-cli")
+	}
+	return filepath.Join(configDir(), "cache")
+}
+
 func Load() (*Config, error) {
+	if disabled {
+		return &Config{}, ErrDisabled
+	}
 	data, err := os.ReadFile(configPath())
 	if err != nil {
 		return &Config{}, err
@@ -127,6 +206,9 @@ func Load() (*Config, error) {
 }
 
 func Save(cfg *Config) error {
+	if disabled {
+		return ErrDisabled
+	}
 	dir := configDir()
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return err
@@ -206,3 +206,34 @@ func TestGetCurrentProfileLegacyFallback(t *testing.T) {
 		t.Errorf("Token = %q, want %q", profile.Token, "legacy-token")
 	}
 }
+
+func TestNotionProfileEnv(t *testing.T) {
+	cfg := &Config{CurrentProfile: "personal"}
+	cfg.SetProfile("personal", &Profile{Token: "personal-token"})
+	cfg.SetProfile("ci", &Profile{Token: "ci-token"})
+
+	t.Setenv("NOTION_PROFILE", "ci")
+	if p := cfg.GetCurrentProfile(); p == nil || p.Token != "ci-token" {
+		t.Errorf("NOTION_PROFILE=ci: profile = %v", p)
+	}
+	t.Setenv("NOTION_PROFILE", "missing")
+	if p := cfg.GetCurrentProfile(); p != nil {
+		t.Errorf("NOTION_PROFILE=missing: profile = %v, want nil", p)
+	}
+}
+
+func TestSetDisabled(t *testing.T) {
+	setupTestHome(t)
+	if err := Save(&Config{Token: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	SetDisabled(true)
+	defer SetDisabled(false)
+
+	if cfg, err := Load(); err != ErrDisabled || cfg.Token != "" {
+		t.Errorf("Load() = %v, %v; want an empty config and ErrDisabled", cfg, err)
+	}
+	if err := Save(&Config{Token: "other"}); err != ErrDisabled {
+		t.Errorf("Save() error = %v, want ErrDisabled", err)
+	}
+}
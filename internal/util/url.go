@@ -41,6 +41,12 @@ func ResolveID(input string) string {
 	return input
 }
 
+// IsID reports whether input is a Notion object ID or a Notion URL that
+// contains one, i.e. whether ResolveID would return a UUID.
+func IsID(input string) bool {
+	return uuidRe.MatchString(ResolveID(input))
+}
+
 // formatUUID inserts dashes into a 32-char hex string to make a standard UUID.
 func formatUUID(id string) string {
 	id = strings.ReplaceAll(id, "-", "")
@@ -64,3 +64,22 @@ func TestResolveID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"c9e9f681ec8e4eb7be25bbbe479b05b0", true},
+		{"c9e9f681-ec8e-4eb7-be25-bbbe479b05b0", true},
+		{"https://www.notion.so/My-Page-c9e9f681ec8e4eb7be25bbbe479b05b0", true},
+		{"meeting notes", false},
+		{"abc123", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsID(tt.input); got != tt.want {
+			t.Errorf("IsID(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -1,14 +1,18 @@
-package client
+package notion
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type roundTripFunc func(*http.Request) (*http.Response, error)
@@ -160,6 +164,26 @@ func TestUploadFileContentEscapesQuotedFilename(t *testing.T) {
 	}
 }
 
+func TestUploadFileContentAPIError(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		body   string
+		want   error
+	}{
+		{http.StatusUnauthorized, `{"object":"error","status":401,"code":"unauthorized","message":"API token is invalid."}`, ErrUnauthorized},
+		{http.StatusTooManyRequests, `{"object":"error","status":429,"code":"rate_limited","message":"Slow down."}`, ErrRateLimited},
+	} {
+		c := New("test-token", WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: tc.status, Body: io.NopCloser(strings.NewReader(tc.body)), Header: make(http.Header)}, nil
+		})}))
+		_, err := c.UploadFileContent("upload-123", "a.txt", "text/plain", []byte("a"))
+		var apiErr *APIError
+		if !errors.Is(err, tc.want) || !errors.As(err, &apiErr) || apiErr.Status != tc.status {
+			t.Errorf("%d: err = %v, want %v", tc.status, err, tc.want)
+		}
+	}
+}
+
 func TestAddCommentIncludesMentionRichText(t *testing.T) {
 	var gotPath string
 	var gotBody map[string]interface{}
@@ -245,3 +269,182 @@ func TestAddCommentIncludesMentionRichText(t *testing.T) {
 		t.Fatalf("rich_text[4].text.content = %v, want %q", text["content"], "Please review this")
 	}
 }
+
+func TestUnauthorizedIsDistinguishable(t *testing.T) {
+	respond := func(status int, body string) *Client {
+		return &Client{
+			token: "test-token",
+			httpClient: &http.Client{
+				Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: status,
+						Status:     fmt.Sprintf("%d", status),
+						Body:       io.NopCloser(strings.NewReader(body)),
+						Header:     make(http.Header),
+					}, nil
+				}),
+			},
+		}
+	}
+
+	_, err := respond(401, `{"code":"unauthorized","message":"API token is invalid."}`).Get("/v1/users/me")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("401 error = %v, want ErrUnauthorized", err)
+	}
+	if !strings.HasPrefix(err.Error(), "unauthorized: API token is invalid.") {
+		t.Errorf("401 message = %q", err.Error())
+	}
+
+	_, err = respond(403, `{"code":"restricted_resource","message":"Insufficient permissions"}`).Get("/v1/pages/x")
+	if err == nil || errors.Is(err, ErrUnauthorized) {
+		t.Errorf("403 error = %v, must not wrap ErrUnauthorized", err)
+	}
+}
+
+func TestOnObjectReportsPagesAndDatabases(t *testing.T) {
+	bodies := map[string]string{
+		"/v1/pages/p":     `{"object":"page","id":"p"}`,
+		"/v1/databases/d": `{"object":"database","id":"d"}`,
+		"/v1/search":      `{"object":"list","results":[{"object":"page","id":"x"}]}`,
+	}
+	c := &Client{
+		token: "test-token",
+		httpClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 200,
+					Status:     "200 OK",
+					Body:       io.NopCloser(strings.NewReader(bodies[req.URL.Path])),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		},
+	}
+	var seen []string
+	c.OnObject(func(obj map[string]interface{}) {
+		id, _ := obj["id"].(string)
+		seen = append(seen, id)
+	})
+
+	c.Get("/v1/pages/p")
+	c.Get("/v1/databases/d")
+	c.Post("/v1/search", nil)
+	if strings.Join(seen, ",") != "p,d" {
+		t.Errorf("reported objects = %v, want [p d]", seen)
+	}
+}
+
+func TestCacheSchemas(t *testing.T) {
+	gets := 0
+	c := &Client{
+		token: "test-token",
+		httpClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == "GET" {
+					gets++
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Status:     "200 OK",
+					Body:       io.NopCloser(strings.NewReader(`{"object":"database","id":"d"}`)),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		},
+	}
+
+	c.GetDatabase("d")
+	c.GetDatabase("d")
+	if gets != 2 {
+		t.Fatalf("without CacheSchemas: %d GETs, want 2", gets)
+	}
+
+	c.CacheSchemas()
+	c.GetDatabase("d")
+	c.GetDatabase("d")
+	if gets != 3 {
+		t.Errorf("with CacheSchemas: %d GETs, want 3", gets)
+	}
+	c.Patch("/v1/databases/d", map[string]interface{}{})
+	c.GetDatabase("d")
+	if gets != 4 {
+		t.Errorf("after PATCH: %d GETs, want 4 (cache cleared)", gets)
+	}
+}
+
+func TestPrefetchSchemasSharesRequests(t *testing.T) {
+	var mu sync.Mutex
+	gets := map[string]int{}
+	release := make(chan struct{})
+	c := New("test-token", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			gets[req.URL.Path]++
+			mu.Unlock()
+			body := `{"object":"database","properties":{}}`
+			if req.URL.Path == "/v1/databases/tasks" {
+				<-release
+				body = `{"object":"database","properties":{
+				  "Project": {"type": "relation", "relation": {"database_id": "projects"}},
+				  "Blocked by": {"type": "relation", "relation": {"database_id": "tasks"}}}}`
+			}
+			return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}))
+	c.CacheSchemas()
+
+	// Lookups racing the prefetch wait for its request instead of sending
+	// their own.
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetDatabase("tasks"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	c.PrefetchSchemas("tasks")
+	wg.Wait()
+	c.GetDatabase("projects")
+
+	if gets["/v1/databases/tasks"] != 1 || gets["/v1/databases/projects"] != 1 {
+		t.Errorf("GETs = %v, want one per database", gets)
+	}
+}
+
+func TestWithContextCancels(t *testing.T) {
+	c := New("test-token", WithHTTPClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, req.Context().Err()
+		}),
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.WithContext(ctx).Get("/v1/users/me"); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if c.context() != context.Background() {
+		t.Error("WithContext must not change the original client")
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	pages := map[string]map[string]interface{}{
+		"":  {"results": []interface{}{1, 2}, "has_more": true, "next_cursor": "b"},
+		"b": {"results": []interface{}{3}, "has_more": false},
+	}
+	var cursors []string
+	all, err := Paginate(func(cursor string) (map[string]interface{}, error) {
+		cursors = append(cursors, cursor)
+		return pages[cursor], nil
+	})
+	if err != nil || len(all) != 3 || strings.Join(cursors, ",") != ",b" {
+		t.Errorf("Paginate = %v, %v (cursors %q)", all, err, cursors)
+	}
+}
@@ -1,10 +1,6 @@
-package cmd
+package notion
 
-import (
-	"fmt"
-	"os"
-	"strings"
-)
+import "strings"
 
 // notionCodeLanguages lists every language value accepted by the Notion API
 // for a code block. Values not in this set must be normalized to an alias or
@@ -122,27 +118,26 @@ var codeLangAliases = map[string]string{
 	"tty":      "shell",
 }
 
-// normalizeCodeLanguage converts a raw markdown fence language label into
-// a value that the Notion API's code.language enum accepts.
+// CodeLanguage converts a raw markdown fence language label into a value
+// that the Notion API's code.language enum accepts.
 //
 // Empty input resolves to "plain text". Values already in the enum are
 // returned lowercased and unchanged. Known aliases are mapped. Unknown
-// values fall back to "plain text" and emit a one-line stderr warning so
-// long docs don't hard-fail on a single unrecognized fence tag.
-func normalizeCodeLanguage(raw string) string {
+// values fall back to "plain text" with ok false, so callers can warn
+// instead of having the API reject the whole request.
+func CodeLanguage(raw string) (lang string, ok bool) {
 	s := strings.TrimSpace(strings.ToLower(raw))
 	if s == "" {
-		return "plain text"
+		return "plain text", true
 	}
 	// Normalize dashes/dots some editors emit (`.py`, `c-plus-plus`)
 	s = strings.TrimPrefix(s, ".")
 
 	if _, ok := notionCodeLanguages[s]; ok {
-		return s
+		return s, true
 	}
 	if mapped, ok := codeLangAliases[s]; ok {
-		return mapped
+		return mapped, true
 	}
-	fmt.Fprintf(os.Stderr, "note: unknown code language %q, falling back to plain text\n", raw)
-	return "plain text"
+	return "plain text", false
 }
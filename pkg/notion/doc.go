@@ -0,0 +1,24 @@
+// Package notion is a Go client for the Notion API, and the SDK the
+// notion CLI itself is built on.
+//
+// Objects are exchanged as decoded JSON (map[string]interface{}), exactly
+// as the API documents them, so every field is available without waiting
+// for typed wrappers:
+//
+//	c := notion.New(os.Getenv("NOTION_TOKEN"))
+//	page, err := c.GetPage("c9e9f681ec8e4eb7be25bbbe479b05b0")
+//	if errors.Is(err, notion.ErrNotFound) {
+//		// the page doesn't exist or isn't shared with the integration
+//	}
+//
+// API failures are *APIError values; errors.As gives the HTTP status, the
+// Notion error code and whether the request is worth retrying.
+//
+// Requests use the client's context; derive a cancellable client with
+// WithContext. Paginated endpoints have *All variants built on Paginate.
+//
+// Helpers convert between the CLI's input formats and API values:
+// MarkdownToBlocks turns markdown into blocks, PropertyValue builds a
+// property value from a string such as "Done" or "2026-03-01", and
+// PropertyText renders a property value as text.
+package notion
@@ -0,0 +1,169 @@
+package notion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by *APIError through errors.Is, so callers can
+// branch on the kind of failure without inspecting codes:
+//
+//	if errors.Is(err, notion.ErrNotFound) { ... }
+var (
+	// ErrUnauthorized: the token itself was rejected (revoked, expired or
+	// mistyped), as opposed to a permission error on one object.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound: the object doesn't exist or isn't shared with the
+	// integration.
+	ErrNotFound = errors.New("not found")
+	// ErrRestricted: the integration lacks access or a capability.
+	ErrRestricted = errors.New("restricted")
+	// ErrRateLimited: too many requests; see APIError.RetryAfter.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation: the request was malformed or referenced unknown
+	// properties.
+	ErrValidation = errors.New("validation failed")
+	// ErrConflict: the object was changed concurrently.
+	ErrConflict = errors.New("conflict")
+)
+
+// APIError is the error returned for HTTP 4xx/5xx responses from the API.
+// Use errors.As to get at the details.
+type APIError struct {
+	// Status is the HTTP status code.
+	Status int
+	// Code is Notion's error code, such as "object_not_found"; empty when
+	// the response had no JSON error body.
+	Code    string
+	Message string
+	// Hint is an actionable suggestion for the error, or "".
+	Hint      string
+	RequestID string
+	// RetryAfter is the wait the server asked for (429 responses), or 0.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("API error: %d %s", e.Status, http.StatusText(e.Status))
+	}
+	if e.Hint != "" {
+		return fmt.Sprintf("%s: %s\n  → %s", e.Code, e.Message, e.Hint)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports whether e is of the kind described by one of the sentinel
+// errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized || e.Code == "unauthorized"
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound || e.Code == "object_not_found"
+	case ErrRestricted:
+		return e.Status == http.StatusForbidden || e.Code == "restricted_resource"
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests || e.Code == "rate_limited"
+	case ErrValidation:
+		return e.Code == "validation_error" || e.Code == "invalid_request" || e.Code == "invalid_json"
+	case ErrConflict:
+		return e.Status == http.StatusConflict || e.Code == "conflict_error"
+	}
+	return false
+}
+
+// Retryable reports whether the same request may succeed later: rate
+// limits, conflicts and server-side failures.
+func (e *APIError) Retryable() bool {
+	return e.Status == http.StatusConflict || retryableStatus(e.Status)
+}
+
+// retryableStatus reports whether a request answered with status can be
+// resent as is.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether err is an *APIError that may succeed when
+// retried.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
+
+// newAPIError builds the error for an HTTP error response.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{Status: resp.StatusCode}
+	var payload struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Message != "" {
+		e.Code, e.Message, e.RequestID = payload.Code, payload.Message, payload.RequestID
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		e.Code = "unauthorized"
+		if e.Message == "" {
+			e.Message = "API token is invalid."
+		}
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+	if e.Code != "" {
+		e.Hint = errorHint(e.Code, e.Message)
+	}
+	return e
+}
+
+// errorHint provides actionable suggestions for common API errors.
+func errorHint(code, message string) string {
+	switch code {
+	case "object_not_found":
+		return "Check the ID is correct and the page/database is shared with your integration"
+	case "unauthorized":
+		return "The token was rejected (revoked, expired or mistyped). Run 'notion auth login' to authenticate again"
+	case "restricted_resource":
+		return "Your integration doesn't have access. Share the page/database with your integration in Notion"
+	case "rate_limited":
+		return "Too many requests. Wait a moment and try again"
+	case "validation_error":
+		if strings.Contains(message, "Internal integrations aren't owned") ||
+			strings.Contains(message, "insert_content") {
+			return internalIntegrationRootPageHint
+		}
+		if strings.Contains(message, "is not a property") {
+			return "Check property names with 'notion db view <id>' or 'notion page props <id>'"
+		}
+		if strings.Contains(message, "body failed validation") {
+			return "Check your input format. Use --debug for request details"
+		}
+	case "conflict_error":
+		return "The resource was modified by another process. Retry the operation"
+	case "internal_server_error", "service_unavailable":
+		return "Notion's servers are having issues. Try again in a few minutes"
+	}
+	return ""
+}
+
+// internalIntegrationRootPageHint is the one-paragraph explanation the CLI
+// prints when an internal integration tries to create a workspace-root page.
+// It's a multi-line string because that's the shape most users need: the
+// API error is accurate but not directly actionable.
+const internalIntegrationRootPageHint = "Internal integrations can't create pages at the workspace root.\n" +
+	"     Workaround: create (or pick) a parent page in the Notion UI, share\n" +
+	"     it with this integration, then pass its ID as the parent:\n" +
+	"         notion page create <shared-page-id> --title \"...\"\n" +
+	"     To list pages shared with your integration: notion page list"
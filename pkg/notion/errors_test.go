@@ -0,0 +1,96 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		status    int
+		body      string
+		want      error
+		retryable bool
+	}{
+		{404, `{"code":"object_not_found","message":"Could not find page"}`, ErrNotFound, false},
+		{403, `{"code":"restricted_resource","message":"Insufficient permissions"}`, ErrRestricted, false},
+		{429, `{"code":"rate_limited","message":"Slow down"}`, ErrRateLimited, true},
+		{400, `{"code":"validation_error","message":"Title is not a property"}`, ErrValidation, false},
+		{409, `{"code":"conflict_error","message":"Conflict"}`, ErrConflict, true},
+		{401, ``, ErrUnauthorized, false},
+	}
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrRestricted, ErrRateLimited, ErrValidation, ErrConflict}
+
+	for _, tt := range tests {
+		_, err := fakeResponse(tt.status, tt.body, nil).Get("/v1/pages/x")
+		wrapped := fmt.Errorf("get page: %w", err)
+		for _, s := range sentinels {
+			if got := errors.Is(wrapped, s); got != (s == tt.want) {
+				t.Errorf("%d: errors.Is(err, %v) = %v", tt.status, s, got)
+			}
+		}
+		var apiErr *APIError
+		if !errors.As(wrapped, &apiErr) {
+			t.Fatalf("%d: error %T is not an *APIError", tt.status, err)
+		}
+		if apiErr.Status != tt.status {
+			t.Errorf("Status = %d, want %d", apiErr.Status, tt.status)
+		}
+		if IsRetryable(wrapped) != tt.retryable {
+			t.Errorf("%d: IsRetryable = %v, want %v", tt.status, !tt.retryable, tt.retryable)
+		}
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	_, err := fakeResponse(404, `{"code":"object_not_found","message":"Could not find page","request_id":"r1"}`, nil).Get("/v1/pages/x")
+	if !strings.HasPrefix(err.Error(), "object_not_found: Could not find page\n  → ") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	var apiErr *APIError
+	errors.As(err, &apiErr)
+	if apiErr.RequestID != "r1" || apiErr.Hint == "" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+
+	_, err = fakeResponse(502, `<html>Bad gateway</html>`, nil).Get("/v1/pages/x")
+	if err.Error() != "API error: 502 Bad Gateway" {
+		t.Errorf("non-JSON Error() = %q", err.Error())
+	}
+	if !IsRetryable(err) {
+		t.Error("502 should be retryable")
+	}
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"7"}}
+	_, err := fakeResponse(429, `{"code":"rate_limited","message":"Slow down"}`, header).Get("/v1/users")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 7*time.Second {
+		t.Errorf("RetryAfter = %v", apiErr)
+	}
+}
+
+func fakeResponse(status int, body string, header http.Header) *Client {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &Client{
+		token: "test-token",
+		httpClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: status,
+					Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     header,
+				}, nil
+			}),
+		},
+	}
+}
@@ -0,0 +1,521 @@
+package notion
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MarkdownOption configures MarkdownToBlocks.
+type MarkdownOption func(*markdownConfig)
+
+type markdownConfig struct {
+	unknownLanguage func(label string)
+	localImage      func(src, alt string) map[string]interface{}
+}
+
+// OnUnknownLanguage registers fn to be called with the label of each code
+// fence whose language Notion doesn't support; such blocks are created as
+// "plain text".
+func OnUnknownLanguage(fn func(label string)) MarkdownOption {
+	return func(cfg *markdownConfig) {
+		cfg.unknownLanguage = fn
+	}
+}
+
+// OnLocalImage registers fn to build the block for an image on a line of
+// its own (![alt](path)) whose source is a local path rather than a URL,
+// typically by uploading the file. When fn returns nil, or without this
+// option, the line is kept as a paragraph.
+func OnLocalImage(fn func(src, alt string) map[string]interface{}) MarkdownOption {
+	return func(cfg *markdownConfig) {
+		cfg.localImage = fn
+	}
+}
+
+// imageLineRe matches a line holding only an image, with an optional
+// title: ![alt](src "title").
+var imageLineRe = regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)$`)
+
+// urlSchemeRe matches the scheme of an absolute URL.
+var urlSchemeRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// MarkdownToBlocks converts markdown text to Notion block objects ready to
+// be sent as children. Headings, lists, to-dos, quotes, code fences,
+// dividers, GFM tables, footnotes, definition lists and inline formatting
+// are supported.
+func MarkdownToBlocks(content string, opts ...MarkdownOption) []map[string]interface{} {
+	var cfg markdownConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var blocks []map[string]interface{}
+	lines, notes := extractFootnotes(strings.Split(content, "\n"))
+
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
+
+		// Code fence
+		if strings.HasPrefix(line, "```") {
+			label := strings.TrimPrefix(line, "```")
+			lang, ok := CodeLanguage(label)
+			if !ok && cfg.unknownLanguage != nil {
+				cfg.unknownLanguage(label)
+			}
+			var codeLines []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(lines[i], "```") {
+				codeLines = append(codeLines, lines[i])
+				i++
+			}
+			i++ // skip closing ```
+			blocks = append(blocks, map[string]interface{}{
+				"object": "block",
+				"type":   "code",
+				"code": map[string]interface{}{
+					"rich_text": []map[string]interface{}{
+						{"text": map[string]interface{}{"content": strings.Join(codeLines, "\n")}},
+					},
+					"language": lang,
+				},
+			})
+			continue
+		}
+
+		// Skip empty lines
+		if strings.TrimSpace(line) == "" {
+			i++
+			continue
+		}
+
+		// Headings
+		if strings.HasPrefix(line, "### ") {
+			blocks = append(blocks, TextBlock("heading_3", strings.TrimPrefix(line, "### ")))
+			i++
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			blocks = append(blocks, TextBlock("heading_2", strings.TrimPrefix(line, "## ")))
+			i++
+			continue
+		}
+		if strings.HasPrefix(line, "# ") {
+			blocks = append(blocks, TextBlock("heading_1", strings.TrimPrefix(line, "# ")))
+			i++
+			continue
+		}
+
+		// Todo (must check before bullet — "- [ ]" starts with "- ")
+		if strings.HasPrefix(line, "- [ ] ") {
+			block := TextBlock("to_do", line[6:])
+			block["to_do"].(map[string]interface{})["checked"] = false
+			blocks = append(blocks, block)
+			i++
+			continue
+		}
+		if strings.HasPrefix(line, "- [x] ") || strings.HasPrefix(line, "- [X] ") {
+			block := TextBlock("to_do", line[6:])
+			block["to_do"].(map[string]interface{})["checked"] = true
+			blocks = append(blocks, block)
+			i++
+			continue
+		}
+
+		// Bullet list
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
+			blocks = append(blocks, TextBlock("bulleted_list_item", line[2:]))
+			i++
+			continue
+		}
+
+		// Numbered list
+		if len(line) > 2 && line[0] >= '0' && line[0] <= '9' && strings.Contains(line[:5], ". ") {
+			idx := strings.Index(line, ". ")
+			blocks = append(blocks, TextBlock("numbered_list_item", line[idx+2:]))
+			i++
+			continue
+		}
+
+		// Quote
+		if strings.HasPrefix(line, "> ") {
+			blocks = append(blocks, TextBlock("quote", strings.TrimPrefix(line, "> ")))
+			i++
+			continue
+		}
+
+		// Divider
+		if line == "---" || line == "***" || line == "___" {
+			blocks = append(blocks, map[string]interface{}{
+				"object":  "block",
+				"type":    "divider",
+				"divider": map[string]interface{}{},
+			})
+			i++
+			continue
+		}
+
+		// GFM Table: starts with '|'
+		if strings.HasPrefix(strings.TrimSpace(line), "|") {
+			// Collect all consecutive pipe-starting lines
+			var tableLines []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				tableLines = append(tableLines, lines[i])
+				i++
+			}
+			// Need at least header + separator + 1 data row to be a valid GFM table
+			if len(tableLines) >= 2 && isTableSeparator(tableLines[1]) {
+				tableBlock := buildTableBlock(tableLines)
+				if tableBlock != nil {
+					blocks = append(blocks, tableBlock)
+					continue
+				}
+			}
+			// Not a valid table — treat each line as a paragraph
+			for _, tl := range tableLines {
+				blocks = append(blocks, TextBlock("paragraph", tl))
+			}
+			continue
+		}
+
+		// Local image
+		if m := imageLineRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil && cfg.localImage != nil && !urlSchemeRe.MatchString(m[2]) {
+			if block := cfg.localImage(m[2], m[1]); block != nil {
+				blocks = append(blocks, block)
+				i++
+				continue
+			}
+		}
+
+		// Definition list: a term followed by ": definition" lines
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], ": ") {
+			var defs []string
+			j := i + 1
+			for j < len(lines) && strings.HasPrefix(lines[j], ": ") {
+				defs = append(defs, strings.TrimSpace(lines[j][2:]))
+				j++
+			}
+			blocks = append(blocks, definitionBlock(line, defs))
+			i = j
+			continue
+		}
+
+		// Default: paragraph
+		blocks = append(blocks, TextBlock("paragraph", line))
+		i++
+	}
+
+	return append(blocks, notes.blocks()...)
+}
+
+// definitionBlock renders a definition list entry as a paragraph: the
+// term in bold, then each definition on a line of its own.
+func definitionBlock(term string, defs []string) map[string]interface{} {
+	rt := []map[string]interface{}{{
+		"text":        map[string]interface{}{"content": strings.TrimSpace(term)},
+		"annotations": map[string]interface{}{"bold": true},
+	}}
+	for _, d := range defs {
+		rt = append(rt, plainRichText("\n"))
+		rt = append(rt, RichTextFromMarkdown(d)...)
+	}
+	return map[string]interface{}{
+		"object":    "block",
+		"type":      "paragraph",
+		"paragraph": map[string]interface{}{"rich_text": rt},
+	}
+}
+
+var (
+	footnoteDefRe = regexp.MustCompile(`^\[\^([^\]]+)\]:\s?(.*)$`)
+	footnoteRefRe = regexp.MustCompile(`\[\^([^\]]+)\]`)
+)
+
+// footnotes holds the footnotes of a document, numbered in the order they
+// are first referenced.
+type footnotes struct {
+	text    map[string]string
+	order   []string
+	excerpt map[string]string
+}
+
+// extractFootnotes removes footnote definitions ([^id]: text, with
+// indented continuation lines) from lines and replaces references to them
+// with their number, [1], [2]... Code fences are left alone.
+func extractFootnotes(lines []string) ([]string, *footnotes) {
+	notes := &footnotes{text: map[string]string{}, excerpt: map[string]string{}}
+	var defOrder []string
+	var kept []string
+	inFence := false
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+		}
+		m := footnoteDefRe.FindStringSubmatch(line)
+		if inFence || m == nil {
+			kept = append(kept, line)
+			continue
+		}
+		text := strings.TrimSpace(m[2])
+		for i+1 < len(lines) && (strings.HasPrefix(lines[i+1], "    ") || strings.HasPrefix(lines[i+1], "\t")) {
+			i++
+			text += " " + strings.TrimSpace(lines[i])
+		}
+		if _, dup := notes.text[m[1]]; !dup {
+			defOrder = append(defOrder, m[1])
+		}
+		notes.text[m[1]] = text
+	}
+	if len(notes.text) == 0 {
+		return kept, notes
+	}
+
+	number := map[string]int{}
+	inFence = false
+	for i, line := range kept {
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+		}
+		if inFence {
+			continue
+		}
+		kept[i] = footnoteRefRe.ReplaceAllStringFunc(line, func(ref string) string {
+			id := footnoteRefRe.FindStringSubmatch(ref)[1]
+			if _, ok := notes.text[id]; !ok {
+				return ref
+			}
+			if number[id] == 0 {
+				notes.order = append(notes.order, id)
+				number[id] = len(notes.order)
+				before := strings.Fields(footnoteRefRe.ReplaceAllString(line[:strings.Index(line, ref)], ""))
+				if len(before) > 5 {
+					before = before[len(before)-5:]
+				}
+				notes.excerpt[id] = strings.Join(before, " ")
+			}
+			return fmt.Sprintf("[%d]", number[id])
+		})
+	}
+	// Footnotes nobody refers to are kept too, after the others.
+	for _, id := range defOrder {
+		if number[id] == 0 {
+			notes.order = append(notes.order, id)
+			number[id] = len(notes.order)
+		}
+	}
+	return kept, notes
+}
+
+// blocks renders the footnotes for the end of the page: a divider, then
+// one paragraph per footnote. Notion can't link to a block before it
+// exists, so the backlink quotes the words leading up to the reference.
+func (f *footnotes) blocks() []map[string]interface{} {
+	if len(f.order) == 0 {
+		return nil
+	}
+	blocks := []map[string]interface{}{{"object": "block", "type": "divider", "divider": map[string]interface{}{}}}
+	for i, id := range f.order {
+		rt := RichTextFromMarkdown(fmt.Sprintf("[%d] %s", i+1, f.text[id]))
+		if excerpt := f.excerpt[id]; excerpt != "" {
+			rt = append(rt, map[string]interface{}{
+				"text":        map[string]interface{}{"content": " ↩ “…" + excerpt + "”"},
+				"annotations": map[string]interface{}{"italic": true, "color": "gray"},
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"object":    "block",
+			"type":      "paragraph",
+			"paragraph": map[string]interface{}{"rich_text": rt},
+		})
+	}
+	return blocks
+}
+
+// isTableSeparator returns true when a line looks like a GFM table separator (|---|---|).
+func isTableSeparator(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "|") {
+		return false
+	}
+	// Strip leading/trailing '|', split cells, check each cell is only -/:/space
+	inner := strings.Trim(trimmed, "|")
+	cells := strings.Split(inner, "|")
+	for _, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		// Must consist of dashes and optional colons (alignment markers)
+		for _, ch := range cell {
+			if ch != '-' && ch != ':' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitTableRow splits a pipe-delimited table row into trimmed cell strings.
+func splitTableRow(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	// Strip leading/trailing '|'
+	trimmed = strings.Trim(trimmed, "|")
+	parts := strings.Split(trimmed, "|")
+	cells := make([]string, len(parts))
+	for i, p := range parts {
+		cells[i] = strings.TrimSpace(p)
+	}
+	return cells
+}
+
+// buildTableBlock converts collected GFM table lines into a Notion table block.
+// tableLines[0] = header row, tableLines[1] = separator, tableLines[2:] = data rows.
+func buildTableBlock(tableLines []string) map[string]interface{} {
+	headerCells := splitTableRow(tableLines[0])
+	tableWidth := len(headerCells)
+	if tableWidth == 0 {
+		return nil
+	}
+
+	var rows []map[string]interface{}
+
+	// Header row (index 0), skip separator (index 1), then data rows
+	for idx, line := range tableLines {
+		if idx == 1 {
+			continue // separator — skip
+		}
+		cells := splitTableRow(line)
+		// Pad or trim to tableWidth
+		for len(cells) < tableWidth {
+			cells = append(cells, "")
+		}
+		cells = cells[:tableWidth]
+
+		notionCells := make([]interface{}, tableWidth)
+		for j, cellText := range cells {
+			notionCells[j] = RichTextFromMarkdown(cellText)
+		}
+		rows = append(rows, map[string]interface{}{
+			"object": "block",
+			"type":   "table_row",
+			"table_row": map[string]interface{}{
+				"cells": notionCells,
+			},
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Notion API requires table_row children INSIDE table{}, not at block top-level.
+	return map[string]interface{}{
+		"object": "block",
+		"type":   "table",
+		"table": map[string]interface{}{
+			"table_width":       tableWidth,
+			"has_column_header": true,
+			"has_row_header":    false,
+			"children":          rows,
+		},
+	}
+}
+
+// TextBlock returns a block of blockType ("paragraph", "heading_1", ...)
+// whose text is parsed for inline markdown.
+func TextBlock(blockType, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"object": "block",
+		"type":   blockType,
+		blockType: map[string]interface{}{
+			"rich_text": RichTextFromMarkdown(strings.TrimSpace(text)),
+		},
+	}
+}
+
+// RichTextFromMarkdown converts inline markdown (bold, italic, code, link, strikethrough)
+// into a Notion rich_text array.
+func RichTextFromMarkdown(text string) []map[string]interface{} {
+	// token pattern: **bold**, *italic*, _italic_, `code`, ~~strike~~, [text](url)
+	tokenRe := regexp.MustCompile(`\*\*(.+?)\*\*|\*(.+?)\*|_(.+?)_|` + "`" + `(.+?)` + "`" + `|~~(.+?)~~|\[([^\]]+)\]\(([^)]+)\)`)
+
+	var result []map[string]interface{}
+	remaining := text
+	for len(remaining) > 0 {
+		loc := tokenRe.FindStringIndex(remaining)
+		if loc == nil {
+			// No more tokens — append remaining as plain text
+			if remaining != "" {
+				result = append(result, plainRichText(remaining))
+			}
+			break
+		}
+		// Plain text before the match
+		if loc[0] > 0 {
+			result = append(result, plainRichText(remaining[:loc[0]]))
+		}
+		match := tokenRe.FindStringSubmatch(remaining[loc[0]:loc[1]])
+		rt := buildAnnotatedRichText(match)
+		result = append(result, rt)
+		remaining = remaining[loc[1]:]
+	}
+	if len(result) == 0 {
+		return []map[string]interface{}{plainRichText(text)}
+	}
+	return result
+}
+
+func plainRichText(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"text": map[string]interface{}{"content": text},
+	}
+}
+
+func buildAnnotatedRichText(match []string) map[string]interface{} {
+	// match[0] = full match
+	// match[1] = **bold**  content
+	// match[2] = *italic*  content
+	// match[3] = _italic_  content
+	// match[4] = `code`    content
+	// match[5] = ~~strike~~ content
+	// match[6] = [text](url) text part
+	// match[7] = [text](url) url part
+	switch {
+	case match[1] != "": // **bold**
+		return map[string]interface{}{
+			"text":        map[string]interface{}{"content": match[1]},
+			"annotations": map[string]interface{}{"bold": true},
+		}
+	case match[2] != "": // *italic*
+		return map[string]interface{}{
+			"text":        map[string]interface{}{"content": match[2]},
+			"annotations": map[string]interface{}{"italic": true},
+		}
+	case match[3] != "": // _italic_
+		return map[string]interface{}{
+			"text":        map[string]interface{}{"content": match[3]},
+			"annotations": map[string]interface{}{"italic": true},
+		}
+	case match[4] != "": // `code`
+		return map[string]interface{}{
+			"text":        map[string]interface{}{"content": match[4]},
+			"annotations": map[string]interface{}{"code": true},
+		}
+	case match[5] != "": // ~~strike~~
+		return map[string]interface{}{
+			"text":        map[string]interface{}{"content": match[5]},
+			"annotations": map[string]interface{}{"strikethrough": true},
+		}
+	case match[6] != "": // [text](url)
+		return map[string]interface{}{
+			"text": map[string]interface{}{
+				"content": match[6],
+				"link":    map[string]interface{}{"url": match[7]},
+			},
+		}
+	default:
+		return plainRichText(match[0])
+	}
+}
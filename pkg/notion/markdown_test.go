@@ -0,0 +1,98 @@
+package notion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsTableSeparator(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"| --- | --- |", true},
+		{"|---|---|", true},
+		{"| :--- | ---: | :---: |", true},
+		{"| Name | Age |", false},
+		{"---", false},
+		{"| - |", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := isTableSeparator(tt.input)
+			if got != tt.want {
+				t.Errorf("isTableSeparator(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToBlocksLocalImage(t *testing.T) {
+	var seen []string
+	hook := OnLocalImage(func(src, alt string) map[string]interface{} {
+		seen = append(seen, src+"|"+alt)
+		if src == "missing.png" {
+			return nil
+		}
+		return map[string]interface{}{"type": "image"}
+	})
+	md := "![Chart](./chart.png \"Q3\")\n![Remote](https://x.dev/a.png)\n![](missing.png)\nSee ![inline](b.png) here"
+	blocks := MarkdownToBlocks(md, hook)
+
+	var types []string
+	for _, b := range blocks {
+		types = append(types, b["type"].(string))
+	}
+	if got := strings.Join(types, ","); got != "image,paragraph,paragraph,paragraph" {
+		t.Errorf("block types = %s", got)
+	}
+	if got := strings.Join(seen, ","); got != "./chart.png|Chart,missing.png|" {
+		t.Errorf("hook calls = %s", got)
+	}
+}
+
+func TestMarkdownToBlocksFootnotes(t *testing.T) {
+	md := "Caches expire[^ttl] and rebuild[^b].\n\n[^b]: In the background.\n[^ttl]: After an hour,\n    unless pinned.\n[^unused]: Never referenced.\n\n```\nx[^ttl]\n```"
+	blocks := MarkdownToBlocks(md)
+
+	var texts []string
+	for _, b := range blocks {
+		blockType := b["type"].(string)
+		data := b[blockType].(map[string]interface{})
+		rt, _ := data["rich_text"].([]map[string]interface{})
+		var parts []string
+		for _, r := range rt {
+			parts = append(parts, r["text"].(map[string]interface{})["content"].(string))
+		}
+		texts = append(texts, blockType+":"+strings.Join(parts, ""))
+	}
+	want := []string{
+		"paragraph:Caches expire[1] and rebuild[2].",
+		"code:x[^ttl]",
+		"divider:",
+		"paragraph:[1] After an hour, unless pinned. ↩ “…Caches expire”",
+		"paragraph:[2] In the background. ↩ “…Caches expire and rebuild”",
+		"paragraph:[3] Never referenced.",
+	}
+	if strings.Join(texts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(texts, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMarkdownToBlocksDefinitionList(t *testing.T) {
+	blocks := MarkdownToBlocks("TTL\n: Time to live\n: In *seconds*\n\n: orphan")
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks", len(blocks))
+	}
+	rt := blocks[0]["paragraph"].(map[string]interface{})["rich_text"].([]map[string]interface{})
+	if rt[0]["text"].(map[string]interface{})["content"] != "TTL" || rt[0]["annotations"].(map[string]interface{})["bold"] != true {
+		t.Errorf("term = %v", rt[0])
+	}
+	var text string
+	for _, r := range rt {
+		text += r["text"].(map[string]interface{})["content"].(string)
+	}
+	if text != "TTL\nTime to live\nIn seconds" {
+		t.Errorf("text = %q", text)
+	}
+}
@@ -0,0 +1,195 @@
+package notion
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Handler sends one HTTP request to the API.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler to act before and after each request: rate
+// limiting, retries, logging, metrics and the like. A middleware that sends
+// a request more than once must rewind the body with req.GetBody, which is
+// set for every request the client makes.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middleware to the client. The first one given is the
+// outermost: it sees each request first and each response last.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.Use(mw...)
+	}
+}
+
+// Use adds middleware to the client; see WithMiddleware. Clients derived
+// with WithContext before the call don't get it.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], mw...)
+}
+
+// send runs req through the middleware chain and the HTTP client. Request
+// logging (WithDebug) is innermost, so every attempt is logged.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	h := Handler(c.httpClient.Do)
+	if c.debug {
+		h = logRequests(h)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h(req)
+}
+
+// logRequests prints each request, the status and size of its response and
+// where the time went to stdout.
+func logRequests(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+			fmt.Printf("→ %s %s (multipart, %d bytes)\n", req.Method, req.URL, req.ContentLength)
+		} else {
+			fmt.Printf("→ %s %s\n", req.Method, req.URL)
+		}
+		req, timing := traceRequest(req)
+		resp, err := next(req)
+		if err != nil {
+			fmt.Printf("  %s\n", timing)
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		timing.done = time.Now()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		fmt.Printf("← %d %s (%d bytes)\n", resp.StatusCode, resp.Status, len(body))
+		fmt.Printf("  %s\n", timing)
+		return resp, nil
+	}
+}
+
+// requestTiming records the phases of one request, so a slow command can
+// be told apart as slow DNS, a slow network or a slow API.
+type requestTiming struct {
+	start               time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	firstByte, done     time.Time
+	reused              bool
+}
+
+// traceRequest returns req with an httptrace hook filling in the timing.
+func traceRequest(req *http.Request) (*http.Request, *requestTiming) {
+	t := &requestTiming{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.dnsDone = time.Now() },
+		ConnectStart:         func(string, string) { t.connStart = time.Now() },
+		ConnectDone:          func(string, string, error) { t.connDone = time.Now() },
+		TLSHandshakeStart:    func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.tlsDone = time.Now() },
+		GotConn:              func(info httptrace.GotConnInfo) { t.reused = info.Reused },
+		GotFirstResponseByte: func() { t.firstByte = time.Now() },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+// String reads like "dns 4ms · connect 21ms · tls 48ms · ttfb 190ms ·
+// total 214ms". Phases that didn't happen, such as connecting over a
+// reused connection, are left out; ttfb runs from the connection being
+// ready, so it covers sending the request and the API's own latency.
+func (t *requestTiming) String() string {
+	var parts []string
+	phase := func(name string, from, to time.Time) {
+		if !from.IsZero() && !to.IsZero() {
+			parts = append(parts, fmt.Sprintf("%s %s", name, roundDuration(to.Sub(from))))
+		}
+	}
+	phase("dns", t.dnsStart, t.dnsDone)
+	phase("connect", t.connStart, t.connDone)
+	phase("tls", t.tlsStart, t.tlsDone)
+	if t.reused {
+		parts = append(parts, "reused connection")
+	}
+	ready := t.start
+	for _, p := range []time.Time{t.dnsDone, t.connDone, t.tlsDone} {
+		if p.After(ready) {
+			ready = p
+		}
+	}
+	phase("ttfb", ready, t.firstByte)
+	end := t.done
+	if end.IsZero() {
+		end = time.Now()
+	}
+	phase("total", t.start, end)
+	return strings.Join(parts, " · ")
+}
+
+// roundDuration keeps timings readable: whole milliseconds, or tenths
+// below 10ms.
+func roundDuration(d time.Duration) time.Duration {
+	if d < 10*time.Millisecond {
+		return d.Round(100 * time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+// Retry returns middleware that resends requests failing with a rate limit
+// (429) or a transient server error (500, 502, 503, 504), up to retries
+// times. POST and PATCH requests may have taken effect despite a server
+// error, so they are only resent after a 429, or a 503 with Retry-After
+// (the API turned them away unprocessed). It waits as long as the
+// Retry-After header asks, or backs off exponentially from half a second.
+// onWait, if not nil, is called before each wait with the response that
+// caused it.
+func Retry(retries int, onWait func(resp *http.Response, wait time.Duration)) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			for attempt := 0; ; attempt++ {
+				resp, err := next(req)
+				if err != nil || attempt >= retries || !retryable(req, resp) || (req.Body != nil && req.GetBody == nil) {
+					return resp, err
+				}
+				wait := (500 * time.Millisecond) << attempt
+				if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+					wait = time.Duration(secs) * time.Second
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if onWait != nil {
+					onWait(resp, wait)
+				}
+				select {
+				case <-time.After(wait):
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+				if req.GetBody != nil {
+					if req.Body, err = req.GetBody(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+}
+
+// retryable reports whether Retry may resend req after resp.
+func retryable(req *http.Request, resp *http.Response) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch:
+		return resp.StatusCode == http.StatusTooManyRequests ||
+			(resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "")
+	}
+	return retryableStatus(resp.StatusCode)
+}
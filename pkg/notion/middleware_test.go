@@ -0,0 +1,164 @@
+package notion
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+	c := New("test-token", WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "send")
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+	})}), WithMiddleware(tag("outer")))
+	c.Use(tag("inner"))
+
+	if _, err := c.Get("/v1/users/me"); err != nil {
+		t.Fatal(err)
+	}
+	want := "outer before,inner before,send,inner after,outer after"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestMiddlewareCanResend(t *testing.T) {
+	var bodies []string
+	c := New("test-token", WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		data, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(data))
+		status := 200
+		if len(bodies) == 1 {
+			status = 503
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+	})}))
+	c.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err == nil && resp.StatusCode == 503 {
+				resp.Body.Close()
+				req.Body, _ = req.GetBody()
+				return next(req)
+			}
+			return resp, err
+		}
+	})
+
+	if _, err := c.Post("/v1/pages", map[string]string{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"a":"b"}` {
+		t.Errorf("bodies sent = %q", bodies)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	statuses := []int{429, 503, 200}
+	var sent int
+	c := New("test-token", WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[sent]
+		sent++
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`)), Header: http.Header{"Retry-After": []string{"0"}}}, nil
+	})}))
+	var waits []int
+	c.Use(Retry(3, func(resp *http.Response, wait time.Duration) {
+		waits = append(waits, resp.StatusCode)
+	}))
+
+	if _, err := c.Post("/v1/pages", map[string]string{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 3 || len(waits) != 2 || waits[0] != 429 || waits[1] != 503 {
+		t.Errorf("sent %d request(s), waited after %v", sent, waits)
+	}
+
+	sent, statuses = 0, []int{429, 429}
+	_, err := New("test-token", WithHTTPClient(c.httpClient), WithMiddleware(Retry(1, nil))).Get("/v1/users")
+	if !errors.Is(err, ErrRateLimited) || sent != 2 {
+		t.Errorf("after retries ran out: err = %v, sent = %d", err, sent)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var statuses []int
+	var retryAfter string
+	var sent int
+	c := New("test-token", WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[sent]
+		sent++
+		header := http.Header{}
+		if retryAfter != "" {
+			header.Set("Retry-After", retryAfter)
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`)), Header: header}, nil
+	})}), WithMiddleware(Retry(3, nil)))
+
+	for _, tc := range []struct {
+		name       string
+		send       func() error
+		statuses   []int
+		retryAfter string
+		want       int
+	}{
+		{"POST after 502", func() error { _, err := c.Post("/v1/pages", map[string]string{"a": "b"}); return err }, []int{502, 200}, "", 1},
+		{"PATCH after 500", func() error { _, err := c.Patch("/v1/pages/x", map[string]string{"a": "b"}); return err }, []int{500, 200}, "", 1},
+		{"POST after 503", func() error { _, err := c.Post("/v1/pages", map[string]string{"a": "b"}); return err }, []int{503, 200}, "", 1},
+		{"POST after 503 with Retry-After", func() error { _, err := c.Post("/v1/pages", map[string]string{"a": "b"}); return err }, []int{503, 200}, "0", 2},
+		{"GET after 502", func() error { _, err := c.Get("/v1/users"); return err }, []int{502, 200}, "0", 2},
+	} {
+		statuses, retryAfter, sent = tc.statuses, tc.retryAfter, 0
+		tc.send()
+		if sent != tc.want {
+			t.Errorf("%s: sent %d request(s), want %d", tc.name, sent, tc.want)
+		}
+	}
+}
+
+func TestRequestTiming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	for i, want := range []string{"connect ", "reused connection"} {
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		req, timing := traceRequest(req)
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		timing.done = time.Now()
+		got := timing.String()
+		if !strings.Contains(got, want) || !strings.Contains(got, "ttfb ") || !strings.Contains(got, "total ") {
+			t.Errorf("request %d: timing = %q, want it to mention %q, ttfb and total", i+1, got, want)
+		}
+	}
+
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	timing := requestTiming{
+		start: start, dnsStart: start, dnsDone: start.Add(4 * time.Millisecond),
+		connStart: start.Add(4 * time.Millisecond), connDone: start.Add(25 * time.Millisecond),
+		firstByte: start.Add(215 * time.Millisecond), done: start.Add(220 * time.Millisecond),
+	}
+	if got, want := timing.String(), "dns 4ms · connect 21ms · ttfb 190ms · total 220ms"; got != want {
+		t.Errorf("timing = %q, want %q", got, want)
+	}
+}
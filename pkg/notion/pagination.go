@@ -0,0 +1,81 @@
+package notion
+
+// maxPageSize is the largest page_size the API accepts.
+const maxPageSize = 100
+
+// Paginate calls fetch with successive cursors ("" first) until a response
+// has has_more false, and returns all "results" in order. fetch returns
+// one page of a paginated list endpoint.
+func Paginate(fetch func(cursor string) (map[string]interface{}, error)) ([]interface{}, error) {
+	var all []interface{}
+	cursor := ""
+	for {
+		page, err := fetch(cursor)
+		if err != nil {
+			return all, err
+		}
+		results, _ := page["results"].([]interface{})
+		all = append(all, results...)
+
+		hasMore, _ := page["has_more"].(bool)
+		next, _ := page["next_cursor"].(string)
+		if !hasMore || next == "" {
+			return all, nil
+		}
+		cursor = next
+	}
+}
+
+// SearchAll returns every search result for query; filter is "page",
+// "database" or "" for both.
+func (c *Client) SearchAll(query, filter string) ([]interface{}, error) {
+	return Paginate(func(cursor string) (map[string]interface{}, error) {
+		return c.Search(query, filter, maxPageSize, cursor)
+	})
+}
+
+// GetBlockChildrenAll returns every child block of blockID (one level).
+func (c *Client) GetBlockChildrenAll(blockID string) ([]interface{}, error) {
+	return Paginate(func(cursor string) (map[string]interface{}, error) {
+		return c.GetBlockChildren(blockID, maxPageSize, cursor)
+	})
+}
+
+// QueryDatabaseAll returns every row of a database query. body holds the
+// filter and sorts; page_size and start_cursor are managed here. properties
+// limits the properties returned, as for QueryDatabase.
+func (c *Client) QueryDatabaseAll(dbID string, body map[string]interface{}, properties ...string) ([]interface{}, error) {
+	return Paginate(func(cursor string) (map[string]interface{}, error) {
+		req := map[string]interface{}{}
+		for k, v := range body {
+			req[k] = v
+		}
+		req["page_size"] = maxPageSize
+		if cursor != "" {
+			req["start_cursor"] = cursor
+		}
+		return c.QueryDatabase(dbID, req, properties...)
+	})
+}
+
+// GetPagePropertyAll returns every item of a paginated page property,
+// such as all the pages of a relation with more than 25 entries.
+func (c *Client) GetPagePropertyAll(pageID, propertyID string) ([]interface{}, error) {
+	return Paginate(func(cursor string) (map[string]interface{}, error) {
+		return c.GetPageProperty(pageID, propertyID, cursor)
+	})
+}
+
+// ListUsersAll returns every user in the workspace.
+func (c *Client) ListUsersAll() ([]interface{}, error) {
+	return Paginate(func(cursor string) (map[string]interface{}, error) {
+		return c.GetUsers(maxPageSize, cursor)
+	})
+}
+
+// ListViewsAll returns every view of a database.
+func (c *Client) ListViewsAll(dbID string) ([]interface{}, error) {
+	return Paginate(func(cursor string) (map[string]interface{}, error) {
+		return c.ListViews(dbID, cursor)
+	})
+}
@@ -0,0 +1,326 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4ier/notion-cli/internal/util"
+)
+
+// PropertyValue converts a string to a Notion property value of type
+// propType: "Done" for a select, "a, b" for a multi_select,
+// "2026-03-01/2026-03-05" for a date range, "true"/"yes"/"1" for a
+// checkbox, "verified/2026-12-31" for a verification that expires,
+// "48.8584,2.2945 Eiffel Tower" for a place, "id, id" (IDs or URLs) for
+// a relation or people. Unknown types are sent as rich_text.
+func PropertyValue(propType, value string) interface{} {
+	switch propType {
+	case "title":
+		return map[string]interface{}{
+			"title": []map[string]interface{}{
+				{"text": map[string]interface{}{"content": value}},
+			},
+		}
+	case "rich_text":
+		return map[string]interface{}{
+			"rich_text": []map[string]interface{}{
+				{"text": map[string]interface{}{"content": value}},
+			},
+		}
+	case "number":
+		// Try to parse as number
+		var n json.Number = json.Number(value)
+		if f, err := n.Float64(); err == nil {
+			return map[string]interface{}{"number": f}
+		}
+		return map[string]interface{}{"number": value}
+	case "select":
+		return map[string]interface{}{
+			"select": map[string]interface{}{"name": value},
+		}
+	case "multi_select":
+		names := strings.Split(value, ",")
+		options := []map[string]interface{}{}
+		for _, n := range names {
+			options = append(options, map[string]interface{}{"name": strings.TrimSpace(n)})
+		}
+		return map[string]interface{}{"multi_select": options}
+	case "status":
+		return map[string]interface{}{
+			"status": map[string]interface{}{"name": value},
+		}
+	case "date":
+		return DateValue(value, nil)
+	case "checkbox":
+		return map[string]interface{}{
+			"checkbox": value == "true" || value == "1" || value == "yes",
+		}
+	case "url":
+		return map[string]interface{}{"url": value}
+	case "email":
+		return map[string]interface{}{"email": value}
+	case "phone_number":
+		return map[string]interface{}{"phone_number": value}
+	case "verification":
+		parts := strings.SplitN(value, "/", 2)
+		v := map[string]interface{}{"state": strings.ToLower(strings.TrimSpace(parts[0]))}
+		if len(parts) == 2 {
+			v["date"] = map[string]interface{}{
+				"start": time.Now().Format("2006-01-02"),
+				"end":   strings.TrimSpace(parts[1]),
+			}
+		}
+		return map[string]interface{}{"verification": v}
+	case "relation", "people":
+		refs := []map[string]interface{}{}
+		for _, ref := range strings.Split(value, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				refs = append(refs, map[string]interface{}{"id": util.ResolveID(ref)})
+			}
+		}
+		return map[string]interface{}{propType: refs}
+	case "place":
+		coords, name, _ := strings.Cut(strings.TrimSpace(value), " ")
+		latText, lonText, _ := strings.Cut(coords, ",")
+		lat, errLat := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+		lon, errLon := strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+		if errLat != nil || errLon != nil {
+			// Not coordinates: let the API judge a bare name
+			return map[string]interface{}{"place": map[string]interface{}{"name": value}}
+		}
+		p := map[string]interface{}{"lat": lat, "lon": lon}
+		if name = strings.TrimSpace(name); name != "" {
+			p["name"] = name
+		}
+		return map[string]interface{}{"place": p}
+	default:
+		// Fallback: try as rich_text
+		return map[string]interface{}{
+			"rich_text": []map[string]interface{}{
+				{"text": map[string]interface{}{"content": value}},
+			},
+		}
+	}
+}
+
+// DateValue converts "2026-03-01", "2026-03-01T14:00" or a range written
+// "start..end" (or "start/end") to a date property value. An end that is
+// only a time ("2026-03-01T14:00..16:00") falls on the start's day. Times
+// without an offset are taken to be in loc and sent with its offset; a nil
+// loc passes them on as written, which Notion reads as UTC.
+func DateValue(value string, loc *time.Location) map[string]interface{} {
+	start, end, isRange := strings.Cut(value, "..")
+	if !isRange {
+		start, end, isRange = strings.Cut(value, "/")
+	}
+	start = strings.TrimSpace(start)
+	d := map[string]interface{}{"start": DateTimeIn(start, loc)}
+	if end = strings.TrimSpace(end); isRange && end != "" {
+		if day, _, ok := strings.Cut(start, "T"); ok && clockRe.MatchString(end) {
+			end = day + "T" + end
+		}
+		d["end"] = DateTimeIn(end, loc)
+	}
+	return map[string]interface{}{"date": d}
+}
+
+// clockRe matches a bare time of day.
+var clockRe = regexp.MustCompile(`^\d{1,2}:\d{2}(:\d{2})?$`)
+
+// localLayouts are the datetime forms accepted without an offset.
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// DateTimeIn gives a datetime written without an offset the offset of
+// loc, as RFC 3339. Dates, datetimes that carry an offset and anything
+// unparseable come back unchanged, as does everything when loc is nil.
+func DateTimeIn(s string, loc *time.Location) string {
+	if loc == nil {
+		return s
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.Format(time.RFC3339)
+		}
+	}
+	return s
+}
+
+// PropertyText renders a property value (as returned on a page) as
+// human-readable text.
+func PropertyText(prop map[string]interface{}) string {
+	propType, _ := prop["type"].(string)
+
+	switch propType {
+	case "title":
+		if arr, ok := prop["title"].([]interface{}); ok {
+			return PlainText(arr)
+		}
+	case "rich_text":
+		if arr, ok := prop["rich_text"].([]interface{}); ok {
+			return PlainText(arr)
+		}
+	case "number":
+		if n, ok := prop["number"]; ok && n != nil {
+			return fmt.Sprintf("%v", n)
+		}
+	case "select":
+		if sel, ok := prop["select"].(map[string]interface{}); ok {
+			name, _ := sel["name"].(string)
+			return name
+		}
+	case "multi_select":
+		if arr, ok := prop["multi_select"].([]interface{}); ok {
+			var names []string
+			for _, item := range arr {
+				if m, ok := item.(map[string]interface{}); ok {
+					n, _ := m["name"].(string)
+					names = append(names, n)
+				}
+			}
+			return strings.Join(names, ", ")
+		}
+	case "status":
+		if s, ok := prop["status"].(map[string]interface{}); ok {
+			name, _ := s["name"].(string)
+			return name
+		}
+	case "date":
+		if d, ok := prop["date"].(map[string]interface{}); ok {
+			start, _ := d["start"].(string)
+			end, _ := d["end"].(string)
+			if end != "" {
+				return start + " → " + end
+			}
+			return start
+		}
+	case "checkbox":
+		if b, ok := prop["checkbox"].(bool); ok {
+			if b {
+				return "✓"
+			}
+			return "✗"
+		}
+	case "url":
+		if u, ok := prop["url"].(string); ok {
+			return u
+		}
+	case "email":
+		if e, ok := prop["email"].(string); ok {
+			return e
+		}
+	case "phone_number":
+		if p, ok := prop["phone_number"].(string); ok {
+			return p
+		}
+	case "people":
+		if arr, ok := prop["people"].([]interface{}); ok {
+			var names []string
+			for _, item := range arr {
+				if m, ok := item.(map[string]interface{}); ok {
+					n, _ := m["name"].(string)
+					names = append(names, n)
+				}
+			}
+			return strings.Join(names, ", ")
+		}
+	case "relation":
+		if arr, ok := prop["relation"].([]interface{}); ok {
+			var ids []string
+			for _, item := range arr {
+				if m, ok := item.(map[string]interface{}); ok {
+					id, _ := m["id"].(string)
+					ids = append(ids, id)
+				}
+			}
+			return strings.Join(ids, ", ")
+		}
+	case "formula":
+		if f, ok := prop["formula"].(map[string]interface{}); ok {
+			fType, _ := f["type"].(string)
+			if v, ok := f[fType]; ok {
+				return fmt.Sprintf("%v", v)
+			}
+		}
+	case "rollup":
+		if r, ok := prop["rollup"].(map[string]interface{}); ok {
+			rType, _ := r["type"].(string)
+			if v, ok := r[rType]; ok {
+				return fmt.Sprintf("%v", v)
+			}
+		}
+	case "unique_id":
+		if u, ok := prop["unique_id"].(map[string]interface{}); ok {
+			n, ok := u["number"].(float64)
+			if !ok {
+				return ""
+			}
+			if prefix, _ := u["prefix"].(string); prefix != "" {
+				return fmt.Sprintf("%s-%d", prefix, int64(n))
+			}
+			return fmt.Sprintf("%d", int64(n))
+		}
+	case "verification":
+		if v, ok := prop["verification"].(map[string]interface{}); ok {
+			state, _ := v["state"].(string)
+			if by, _ := v["verified_by"].(map[string]interface{}); state == "verified" && by["name"] != nil {
+				state = fmt.Sprintf("verified by %v", by["name"])
+			}
+			d, _ := v["date"].(map[string]interface{})
+			if end, _ := d["end"].(string); strings.HasPrefix(state, "verified") && end != "" {
+				return state + " until " + end
+			}
+			return state
+		}
+	case "button":
+		// Buttons hold no value; show that there is one
+		return "[button]"
+	case "place":
+		if p, ok := prop["place"].(map[string]interface{}); ok {
+			var parts []string
+			for _, key := range []string{"name", "address"} {
+				if s, _ := p[key].(string); s != "" {
+					parts = append(parts, s)
+				}
+			}
+			if len(parts) > 0 {
+				return strings.Join(parts, ", ")
+			}
+			lat, okLat := p["lat"].(float64)
+			lon, okLon := p["lon"].(float64)
+			if okLat && okLon {
+				return fmt.Sprintf("%g, %g", lat, lon)
+			}
+		}
+	case "created_time":
+		if t, ok := prop["created_time"].(string); ok {
+			return t
+		}
+	case "last_edited_time":
+		if t, ok := prop["last_edited_time"].(string); ok {
+			return t
+		}
+	case "created_by", "last_edited_by":
+		if u, ok := prop[propType].(map[string]interface{}); ok {
+			name, _ := u["name"].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// PlainText concatenates the plain_text of a rich_text array.
+func PlainText(arr []interface{}) string {
+	var parts []string
+	for _, t := range arr {
+		if m, ok := t.(map[string]interface{}); ok {
+			if pt, ok := m["plain_text"].(string); ok {
+				parts = append(parts, pt)
+			}
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,34 @@
+package notion
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDateValue(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	tests := []struct {
+		value string
+		loc   *time.Location
+		want  map[string]interface{}
+	}{
+		{"2026-03-01", berlin, map[string]interface{}{"start": "2026-03-01"}},
+		{"2026-03-01T14:00", berlin, map[string]interface{}{"start": "2026-03-01T14:00:00+01:00"}},
+		{"2026-07-01T14:00", berlin, map[string]interface{}{"start": "2026-07-01T14:00:00+02:00"}},
+		{"2026-03-01T14:00Z", berlin, map[string]interface{}{"start": "2026-03-01T14:00Z"}},
+		{"2026-03-01T14:00", nil, map[string]interface{}{"start": "2026-03-01T14:00"}},
+		{"2026-03-01..2026-03-05", nil, map[string]interface{}{"start": "2026-03-01", "end": "2026-03-05"}},
+		{"2026-03-01/2026-03-05", nil, map[string]interface{}{"start": "2026-03-01", "end": "2026-03-05"}},
+		{"2026-03-01T14:00..16:30", berlin, map[string]interface{}{"start": "2026-03-01T14:00:00+01:00", "end": "2026-03-01T16:30:00+01:00"}},
+	}
+	for _, tt := range tests {
+		got := DateValue(tt.value, tt.loc)["date"]
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DateValue(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
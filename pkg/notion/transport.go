@@ -0,0 +1,61 @@
+package notion
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// DefaultIdleConns is how many idle connections to the API a client keeps
+// open by default: enough for the CLI's parallel workers to reuse theirs
+// instead of reconnecting (Go's default is 2 per host).
+const DefaultIdleConns = 16
+
+// TransportOptions tunes how a client talks to the API. The zero value is
+// the default: gzip-compressed responses, HTTP/2 when the server offers
+// it, and up to DefaultIdleConns kept-alive connections.
+type TransportOptions struct {
+	// DisableCompression stops asking for gzip responses, trading
+	// bandwidth for the CPU time spent decompressing.
+	DisableCompression bool
+	// DisableHTTP2 sticks to HTTP/1.1, for proxies that mishandle HTTP/2.
+	DisableHTTP2 bool
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+	// IdleConns caps the idle connections kept open (DefaultIdleConns
+	// when 0).
+	IdleConns int
+	// IdleTimeout closes connections idle this long (90s when 0).
+	IdleTimeout time.Duration
+}
+
+// WithTransport tunes the client's connections; see TransportOptions. It
+// replaces any transport set with WithHTTPClient.
+func WithTransport(opts TransportOptions) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Transport = newTransport(opts)
+		c.httpClient = &hc
+	}
+}
+
+// newTransport builds on http.DefaultTransport, which already decompresses
+// gzip transparently and negotiates HTTP/2 over TLS.
+func newTransport(opts TransportOptions) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DisableCompression = opts.DisableCompression
+	t.DisableKeepAlives = opts.DisableKeepAlives
+	t.MaxIdleConns = DefaultIdleConns
+	if opts.IdleConns > 0 {
+		t.MaxIdleConns = opts.IdleConns
+	}
+	t.MaxIdleConnsPerHost = t.MaxIdleConns
+	if opts.IdleTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleTimeout
+	}
+	if opts.DisableHTTP2 {
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return t
+}
@@ -0,0 +1,147 @@
+package notion
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTransportCompression(t *testing.T) {
+	var encodings []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Accept-Encoding"))
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write([]byte(`{"object":"user","name":"plain"}`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"object":"user","name":"zipped"}`))
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	data, err := New("test-token", WithBaseURL(srv.URL)).Get("/v1/users/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "zipped") {
+		t.Errorf("compressed response = %s", data)
+	}
+
+	data, err = New("test-token", WithBaseURL(srv.URL), WithTransport(TransportOptions{DisableCompression: true})).Get("/v1/users/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "plain") || encodings[1] != "" {
+		t.Errorf("with compression off: response %s, Accept-Encoding %q", data, encodings[1])
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	tr := newTransport(TransportOptions{})
+	if tr.MaxIdleConnsPerHost != DefaultIdleConns || !tr.ForceAttemptHTTP2 || tr.DisableKeepAlives {
+		t.Errorf("defaults: idle %d, http2 %v, no keep-alive %v", tr.MaxIdleConnsPerHost, tr.ForceAttemptHTTP2, tr.DisableKeepAlives)
+	}
+	tr = newTransport(TransportOptions{DisableHTTP2: true, DisableKeepAlives: true, IdleConns: 4})
+	if tr.MaxIdleConnsPerHost != 4 || tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil || !tr.DisableKeepAlives {
+		t.Errorf("tuned: idle %d, http2 %v, no keep-alive %v", tr.MaxIdleConnsPerHost, tr.ForceAttemptHTTP2, tr.DisableKeepAlives)
+	}
+}
+
+// BenchmarkExportTransport runs an export-like load, 8 workers fetching 64
+// pages of blocks, against a server with simulated network costs: 40ms to
+// open a connection (TCP and TLS handshakes), 5ms of API latency per
+// request and 20 MB/s of bandwidth. It compares the tuned transport with
+// each setting turned off and with Go's default transport. Measured on one
+// CPU: tuned 76ms per export, no-compression 146ms, no-http2 76ms,
+// no-keepalive 567ms and Go's default 197ms.
+//
+//	go test ./pkg/notion -run '^$' -bench ExportTransport
+func BenchmarkExportTransport(b *testing.B) {
+	const (
+		dialDelay = 40 * time.Millisecond
+		latency   = 5 * time.Millisecond
+		bandwidth = 20 << 20 // bytes per second
+		workers   = 8
+		pages     = 64
+	)
+	block := `{"object":"block","type":"paragraph","paragraph":{"rich_text":[{"type":"text","text":{"content":"Quarterly planning notes and follow-ups"},"plain_text":"Quarterly planning notes and follow-ups"}]}},`
+	payload := []byte(`{"object":"list","results":[` + strings.Repeat(block, 800) + `{}],"has_more":false}`)
+	var zipped bytes.Buffer
+	gz := gzip.NewWriter(&zipped)
+	gz.Write(payload)
+	gz.Close()
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(latency)
+		body := payload
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			body = zipped.Bytes()
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+		time.Sleep(time.Duration(len(body)) * time.Second / bandwidth)
+		w.Write(body)
+	}))
+	srv.EnableHTTP2 = true
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // handshakes cut short by CloseIdleConnections
+	srv.StartTLS()
+	defer srv.Close()
+	rootCAs := srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	slowDial := func(tr *http.Transport) *http.Transport {
+		dial := (&net.Dialer{}).DialContext
+		tr.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			time.Sleep(dialDelay)
+			return dial(ctx, network, addr)
+		}
+		tr.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+		return tr
+	}
+	for _, bc := range []struct {
+		name string
+		tr   *http.Transport
+	}{
+		{"tuned", newTransport(TransportOptions{})},
+		{"no-compression", newTransport(TransportOptions{DisableCompression: true})},
+		{"no-http2", newTransport(TransportOptions{DisableHTTP2: true})},
+		{"no-keepalive", newTransport(TransportOptions{DisableKeepAlives: true})},
+		{"go-default", http.DefaultTransport.(*http.Transport).Clone()},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			tr := slowDial(bc.tr)
+			defer tr.CloseIdleConnections()
+			c := New("test-token", WithBaseURL(srv.URL), WithHTTPClient(&http.Client{Transport: tr}))
+			for i := 0; i < b.N; i++ {
+				jobs := make(chan int)
+				var wg sync.WaitGroup
+				for w := 0; w < workers; w++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for range jobs {
+							if _, err := c.Get("/v1/blocks/x/children"); err != nil {
+								b.Error(err)
+							}
+						}
+					}()
+				}
+				for p := 0; p < pages; p++ {
+					jobs <- p
+				}
+				close(jobs)
+				wg.Wait()
+			}
+		})
+	}
+}